@@ -87,7 +87,9 @@ var diagnosticBannerLeaves = map[string]bool{
 	"thrum team daemons":    true,
 	"thrum agent list":      true,
 	"thrum version":         true,
+	"thrum health":          true,
 	"thrum daemon logs":     true,
+	"thrum daemon metrics":  true,
 	"thrum daemon restart":  true,
 	"thrum daemon run":      true,
 	"thrum daemon start":    true,
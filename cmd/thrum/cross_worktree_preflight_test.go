@@ -297,7 +297,9 @@ func TestCrossWorktreePreflight_RealCobraLeaves_FireBanner(t *testing.T) {
 	// intended audit signal.
 	bypassClassBLeaves := []string{
 		"thrum daemon status",
+		"thrum health",
 		"thrum daemon logs",
+		"thrum daemon metrics",
 		"thrum daemon start",
 		"thrum daemon stop",
 		"thrum daemon restart",
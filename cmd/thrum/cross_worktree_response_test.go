@@ -424,7 +424,9 @@ func TestRealCobraLeaves_HaveExpectedClasses(t *testing.T) {
 		"thrum team daemons":   CrossWorktreeResponseDiagnosticBanner,
 		"thrum agent list":     CrossWorktreeResponseDiagnosticBanner,
 		"thrum version":        CrossWorktreeResponseDiagnosticBanner,
+		"thrum health":         CrossWorktreeResponseDiagnosticBanner,
 		"thrum daemon logs":    CrossWorktreeResponseDiagnosticBanner,
+		"thrum daemon metrics": CrossWorktreeResponseDiagnosticBanner,
 		"thrum daemon restart": CrossWorktreeResponseDiagnosticBanner,
 		"thrum daemon run":     CrossWorktreeResponseDiagnosticBanner,
 		"thrum daemon start":   CrossWorktreeResponseDiagnosticBanner,
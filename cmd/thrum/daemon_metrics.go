@@ -0,0 +1,89 @@
+// Daemon metrics: registers the Prometheus gauges and counters served at
+// GET /metrics when daemon.metrics_enabled is set. See internal/daemon/metrics
+// for the exposition format; this file is the production wiring.
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/leonletto/thrum/internal/daemon/metrics"
+	"github.com/leonletto/thrum/internal/daemon/rpc"
+	"github.com/leonletto/thrum/internal/daemon/state"
+	thrumSync "github.com/leonletto/thrum/internal/sync"
+)
+
+// metricsQueryTimeout bounds each COUNT(*) run at scrape time so a slow DB
+// cannot wedge the scraper.
+const metricsQueryTimeout = 2 * time.Second
+
+// newDaemonMetrics builds the metrics registry. syncLoop may be nil (no sync
+// worktree), in which case the sync_* series are omitted. wsClientCount
+// reports the number of connected WebSocket clients, and rpcCalls is
+// incremented per dispatched RPC by the Unix socket and WebSocket servers.
+func newDaemonMetrics(st *state.State, messages *rpc.MessageHandler, syncLoop *thrumSync.SyncLoop, wsClientCount func() int, rpcCalls *metrics.CounterVec) *metrics.Registry {
+	reg := metrics.NewRegistry()
+
+	reg.RegisterLabeled("thrum_rpc_calls_total", "RPC calls dispatched since start, over the Unix socket, WebSocket and HTTP gateway.", metrics.Counter,
+		"method", rpcCalls.Snapshot)
+
+	reg.Register("thrum_messages_sent_total", "Messages sent through this daemon since start.", metrics.Counter,
+		func() (float64, bool) { return float64(messages.SentCount()), true })
+
+	reg.Register("thrum_ws_clients", "Connected WebSocket clients.", metrics.Gauge,
+		func() (float64, bool) { return float64(wsClientCount()), true })
+
+	reg.Register("thrum_active_sessions", "Sessions that have not ended.", metrics.Gauge,
+		countQuery(st, `SELECT COUNT(*) FROM sessions WHERE ended_at IS NULL`))
+
+	reg.Register("thrum_agents", "Registered agents.", metrics.Gauge,
+		countQuery(st, `SELECT COUNT(*) FROM agents`))
+
+	reg.Register("thrum_scheduled_messages_pending", "Scheduled messages (send --schedule) waiting for their delivery time.", metrics.Gauge,
+		countQuery(st, `SELECT COUNT(*) FROM scheduled_messages`))
+
+	if syncLoop != nil {
+		reg.Register("thrum_sync_cycles_total", "Sync cycles attempted since start.", metrics.Counter,
+			func() (float64, bool) {
+				cycles, _, _ := syncLoop.Counters()
+				return float64(cycles), true
+			})
+		reg.Register("thrum_sync_successes_total", "Sync cycles that completed without an error since start.", metrics.Counter,
+			func() (float64, bool) {
+				_, successes, _ := syncLoop.Counters()
+				return float64(successes), true
+			})
+		reg.Register("thrum_sync_errors_total", "Sync cycles that recorded an error since start.", metrics.Counter,
+			func() (float64, bool) {
+				_, _, errs := syncLoop.Counters()
+				return float64(errs), true
+			})
+		reg.Register("thrum_sync_lag_seconds", "Seconds since the last successful sync cycle.", metrics.Gauge,
+			func() (float64, bool) {
+				last := syncLoop.GetStatus().LastSyncAt
+				if last.IsZero() {
+					return 0, false
+				}
+				return time.Since(last).Seconds(), true
+			})
+	}
+
+	return reg
+}
+
+// countQuery returns a ReadFunc that runs a single-column COUNT query under
+// the state read lock. A query error omits the sample rather than reporting
+// a misleading zero.
+func countQuery(st *state.State, query string) metrics.ReadFunc {
+	return func() (float64, bool) {
+		ctx, cancel := context.WithTimeout(context.Background(), metricsQueryTimeout)
+		defer cancel()
+		st.RLock()
+		defer st.RUnlock()
+		var n int64
+		if err := st.DB().QueryRowContext(ctx, query).Scan(&n); err != nil {
+			return 0, false
+		}
+		return float64(n), true
+	}
+}
@@ -0,0 +1,132 @@
+// Daemon reload: re-reads .thrum/config.json on `thrum daemon reload`
+// (daemon.reload RPC) or SIGHUP and applies the settings that can change
+// without tearing down sockets. See runDaemon for the wiring.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"sync"
+
+	"github.com/leonletto/thrum/internal/config"
+	"github.com/leonletto/thrum/internal/daemon/rpc"
+	thrumSync "github.com/leonletto/thrum/internal/sync"
+)
+
+// daemonReloader applies config.json changes to a running daemon. Live:
+// local_only, sync_push_retries, sync_push_retry_delay_ms, idle_threshold,
+// send_rate_limit_per_minute, send_rate_limit_burst. Reported as needing a restart: ws_port.
+type daemonReloader struct {
+	mu       sync.Mutex // serializes reloads (RPC and SIGHUP can race)
+	thrumDir string
+	syncLoop *thrumSync.SyncLoop // nil when the daemon runs without a sync worktree
+	// explicitLocal is set when --local or THRUM_LOCAL forced local-only
+	// mode at boot; config.json cannot turn that off.
+	explicitLocal bool
+	// bootWSPort is the ws_port config value the daemon started with.
+	bootWSPort string
+	// exposureGate re-runs the a-sync exposure gate before remote sync is
+	// switched back on, so a reload never starts pushing to a public remote
+	// the boot-time gate would have held off.
+	exposureGate func(ctx context.Context, cfg *config.ThrumConfig) exposureGateOutcome
+	// idleThreshold is shared with the presence-reporting handlers.
+	idleThreshold *rpc.IdleThreshold
+	// sendLimiter is shared with the message.send handler.
+	sendLimiter *rpc.SendRateLimiter
+}
+
+// Reload re-reads config.json and applies it. A config that fails to load
+// changes nothing.
+func (r *daemonReloader) Reload(ctx context.Context) (*rpc.DaemonReloadResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cfg, err := config.LoadThrumConfig(r.thrumDir)
+	if err != nil {
+		return nil, fmt.Errorf("read config.json: %w", err)
+	}
+
+	resp := &rpc.DaemonReloadResponse{
+		Changed:         []rpc.ConfigChange{},
+		RestartRequired: []rpc.ConfigChange{},
+	}
+
+	if r.syncLoop != nil {
+		cur := r.syncLoop.Config()
+		next := cur
+		next.PushRetries = cfg.Daemon.SyncPushRetriesEffective()
+		next.PushRetryDelay = cfg.Daemon.SyncPushRetryDelayEffective()
+
+		switch {
+		case cfg.Daemon.LocalOnly || r.explicitLocal:
+			next.LocalOnly = true
+			next.LocalOnlyReason = ""
+		case cur.LocalOnly:
+			next.LocalOnly = false
+			next.LocalOnlyReason = ""
+			if r.exposureGate != nil {
+				if gate := r.exposureGate(ctx, cfg); gate.LocalOnly {
+					next.LocalOnly = true
+					next.LocalOnlyReason = gate.Reason
+				}
+			}
+		}
+
+		resp.Changed = appendChange(resp.Changed, "local_only",
+			strconv.FormatBool(cur.LocalOnly), strconv.FormatBool(next.LocalOnly))
+		resp.Changed = appendChange(resp.Changed, "sync_push_retries",
+			strconv.Itoa(cur.PushRetries), strconv.Itoa(next.PushRetries))
+		resp.Changed = appendChange(resp.Changed, "sync_push_retry_delay_ms",
+			strconv.FormatInt(cur.PushRetryDelay.Milliseconds(), 10), strconv.FormatInt(next.PushRetryDelay.Milliseconds(), 10))
+
+		if next != cur {
+			r.syncLoop.Reconfigure(next)
+			if cur.LocalOnly && !next.LocalOnly {
+				// Catch up on anything written while remote sync was off.
+				r.syncLoop.TriggerSync()
+			}
+		}
+	}
+
+	if r.idleThreshold != nil {
+		cur, next := r.idleThreshold.Get(), cfg.Daemon.IdleThresholdEffective()
+		resp.Changed = appendChange(resp.Changed, "idle_threshold", cur.String(), next.String())
+		r.idleThreshold.Set(next)
+	}
+
+	if r.sendLimiter != nil {
+		curRate, curBurst := r.sendLimiter.Limits()
+		nextRate, nextBurst := cfg.Daemon.SendRateLimitEffective()
+		resp.Changed = appendChange(resp.Changed, "send_rate_limit_per_minute", strconv.Itoa(curRate), strconv.Itoa(nextRate))
+		resp.Changed = appendChange(resp.Changed, "send_rate_limit_burst", strconv.Itoa(curBurst), strconv.Itoa(nextBurst))
+		r.sendLimiter.Set(nextRate, nextBurst)
+	}
+
+	resp.RestartRequired = appendChange(resp.RestartRequired, "ws_port", wsPortSetting(r.bootWSPort), wsPortSetting(cfg.Daemon.WSPort))
+
+	for _, c := range resp.Changed {
+		slog.Info("daemon.reload", "setting", c.Setting, "old", c.Old, "new", c.New)
+	}
+	for _, c := range resp.RestartRequired {
+		slog.Warn("daemon.reload: restart required", "setting", c.Setting, "old", c.Old, "new", c.New)
+	}
+	return resp, nil
+}
+
+// appendChange appends a ConfigChange when old and next differ.
+func appendChange(changes []rpc.ConfigChange, setting, old, next string) []rpc.ConfigChange {
+	if old == next {
+		return changes
+	}
+	return append(changes, rpc.ConfigChange{Setting: setting, Old: old, New: next})
+}
+
+// wsPortSetting normalizes an unset ws_port to its "auto" meaning.
+func wsPortSetting(v string) string {
+	if v == "" {
+		return "auto"
+	}
+	return v
+}
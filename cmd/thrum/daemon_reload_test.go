@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/leonletto/thrum/internal/config"
+	"github.com/leonletto/thrum/internal/daemon/rpc"
+	thrumSync "github.com/leonletto/thrum/internal/sync"
+)
+
+// newTestReloader builds a daemonReloader over a temp .thrum dir and a sync
+// loop that is never started (Reload only touches its config).
+func newTestReloader(t *testing.T, localOnly bool) (*daemonReloader, *thrumSync.SyncLoop, string) {
+	t.Helper()
+	repo := t.TempDir()
+	thrumDir := filepath.Join(repo, ".thrum")
+	if err := os.MkdirAll(thrumDir, 0o750); err != nil {
+		t.Fatal(err)
+	}
+	syncDir := filepath.Join(repo, ".git", "thrum-sync", "a-sync")
+	syncer := thrumSync.NewSyncer(repo, syncDir, localOnly)
+	loop := thrumSync.NewSyncLoop(syncer, nil, repo, syncDir, thrumDir, localOnly)
+	return &daemonReloader{thrumDir: thrumDir, syncLoop: loop, bootWSPort: "auto"}, loop, thrumDir
+}
+
+func saveTestConfig(t *testing.T, thrumDir string, d config.DaemonConfig) {
+	t.Helper()
+	if err := config.SaveThrumConfig(thrumDir, &config.ThrumConfig{Daemon: d}); err != nil {
+		t.Fatalf("SaveThrumConfig: %v", err)
+	}
+}
+
+func TestDaemonReload_NoChanges(t *testing.T) {
+	r, _, thrumDir := newTestReloader(t, false)
+	saveTestConfig(t, thrumDir, config.DaemonConfig{})
+
+	resp, err := r.Reload(context.Background())
+	if err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if len(resp.Changed) != 0 || len(resp.RestartRequired) != 0 {
+		t.Errorf("expected no changes, got %+v", resp)
+	}
+}
+
+func TestDaemonReload_AppliesLiveSettings(t *testing.T) {
+	r, loop, thrumDir := newTestReloader(t, false)
+	saveTestConfig(t, thrumDir, config.DaemonConfig{
+		LocalOnly:              true,
+		SyncPushRetries:        5,
+		SyncPushRetryDelayMS:   100,
+		WSPort:                 "9999",
+		IdleThreshold:          "10m",
+		SendRateLimitPerMinute: 30,
+	})
+	r.idleThreshold = &rpc.IdleThreshold{}
+	r.idleThreshold.Set(config.DefaultIdleThreshold)
+	r.sendLimiter = &rpc.SendRateLimiter{}
+
+	resp, err := r.Reload(context.Background())
+	if err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+
+	got := map[string]string{}
+	for _, c := range resp.Changed {
+		got[c.Setting] = c.Old + "→" + c.New
+	}
+	want := map[string]string{
+		"local_only":                 "false→true",
+		"sync_push_retries":          "3→5",
+		"sync_push_retry_delay_ms":   "500→100",
+		"idle_threshold":             "5m0s→10m0s",
+		"send_rate_limit_per_minute": "0→30",
+		"send_rate_limit_burst":      "0→30",
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("change %s = %q, want %q", k, got[k], v)
+		}
+	}
+	if len(resp.RestartRequired) != 1 || resp.RestartRequired[0].Setting != "ws_port" ||
+		resp.RestartRequired[0].New != "9999" {
+		t.Errorf("RestartRequired = %+v, want ws_port auto→9999", resp.RestartRequired)
+	}
+
+	if cfg := loop.Config(); !cfg.LocalOnly || cfg.PushRetries != 5 {
+		t.Errorf("loop config not updated: %+v", cfg)
+	}
+	if got := r.idleThreshold.Get(); got != 10*time.Minute {
+		t.Errorf("idle threshold = %v, want 10m", got)
+	}
+	if rate, burst := r.sendLimiter.Limits(); rate != 30 || burst != 30 {
+		t.Errorf("send limiter = %d/min burst %d, want 30/30", rate, burst)
+	}
+}
+
+func TestDaemonReload_RemoteReenableRunsExposureGate(t *testing.T) {
+	r, loop, thrumDir := newTestReloader(t, true)
+	saveTestConfig(t, thrumDir, config.DaemonConfig{})
+
+	calls := 0
+	r.exposureGate = func(context.Context, *config.ThrumConfig) exposureGateOutcome {
+		calls++
+		return exposureGateOutcome{LocalOnly: true, Reason: "public remote"}
+	}
+
+	resp, err := r.Reload(context.Background())
+	if err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("exposure gate calls = %d, want 1", calls)
+	}
+	if len(resp.Changed) != 0 {
+		t.Errorf("gate held local-only; expected no local_only change, got %+v", resp.Changed)
+	}
+	if cfg := loop.Config(); !cfg.LocalOnly || cfg.LocalOnlyReason != "public remote" {
+		t.Errorf("loop config = %+v, want local-only with gate reason", cfg)
+	}
+}
+
+func TestDaemonReload_ExplicitLocalWins(t *testing.T) {
+	r, loop, thrumDir := newTestReloader(t, true)
+	r.explicitLocal = true
+	saveTestConfig(t, thrumDir, config.DaemonConfig{})
+
+	if _, err := r.Reload(context.Background()); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if !loop.Config().LocalOnly {
+		t.Error("--local at boot must keep the daemon local-only across reloads")
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"database/sql"
 	"encoding/json"
@@ -15,11 +16,14 @@ import (
 	"net/http"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
 	goruntime "runtime"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	"golang.org/x/term"
@@ -35,8 +39,10 @@ import (
 	"github.com/leonletto/thrum/internal/daemon/backstop"
 	"github.com/leonletto/thrum/internal/daemon/bootstrap"
 	"github.com/leonletto/thrum/internal/daemon/cleanup"
+	"github.com/leonletto/thrum/internal/daemon/gc"
 	"github.com/leonletto/thrum/internal/daemon/identity/peercred"
 	"github.com/leonletto/thrum/internal/daemon/inbox"
+	"github.com/leonletto/thrum/internal/daemon/metrics"
 	"github.com/leonletto/thrum/internal/daemon/monitor"
 	"github.com/leonletto/thrum/internal/daemon/nudge"
 	"github.com/leonletto/thrum/internal/daemon/permission"
@@ -85,6 +91,7 @@ var (
 	flagJSON    bool
 	flagQuiet   bool
 	flagVerbose bool
+	flagNoColor bool
 
 	// currentCobraCmd is set by rootCmd.PersistentPreRunE before every
 	// leaf RunE so getClient() can consult the leaf's
@@ -116,7 +123,9 @@ sessions, worktrees, and machines using Git as the sync layer.
 
 Environment variables:
   THRUM_NO_HINTS=1   Suppress all CLI hints (both stderr trailers and JSON
-                     'hints' field). Useful in CI or scripted pipelines.`,
+                     'hints' field). Useful in CI or scripted pipelines.
+  NO_COLOR=1         Same as --no-color: no terminal escapes (hyperlinks,
+                     screen clears) in output.`,
 		SilenceUsage:  true,
 		SilenceErrors: true,
 	}
@@ -138,12 +147,19 @@ Environment variables:
 	rootCmd.PersistentFlags().BoolVar(&flagJSON, "json", false, "JSON output for scripting")
 	rootCmd.PersistentFlags().BoolVar(&flagQuiet, "quiet", false, "Suppress non-essential output")
 	rootCmd.PersistentFlags().BoolVar(&flagVerbose, "verbose", false, "Debug output")
+	rootCmd.PersistentFlags().BoolVar(&flagNoColor, "no-color", false, "Disable terminal escapes such as hyperlinks (or set NO_COLOR)")
 
 	// Set version for --version flag
 	rootCmd.Version = Version
+	// --version renders before PersistentPreRunE, so the link helper applies
+	// --no-color itself.
+	cobra.AddTemplateFunc("link", func(url string) string {
+		cli.SetNoColor(flagNoColor)
+		return cli.Hyperlink(url, url)
+	})
 	rootCmd.SetVersionTemplate("thrum v{{.Version}} (build: " + Build + ", " + goruntime.Version() + ")\n" +
-		"\x1b]8;;https://github.com/leonletto/thrum\x07https://github.com/leonletto/thrum\x1b]8;;\x07\n" +
-		"\x1b]8;;https://thrum.team\x07https://thrum.team\x1b]8;;\x07\n")
+		"{{link \"https://github.com/leonletto/thrum\"}}\n" +
+		"{{link \"https://thrum.team\"}}\n")
 
 	// Resolve flagRepo to the nearest parent containing .thrum/ (git-style traversal).
 	// Skip for "init" which creates .thrum/ and doesn't need it to exist.
@@ -154,6 +170,7 @@ Environment variables:
 		// var is safe; PersistentPreRunE on rootCmd fires before
 		// every leaf RunE.
 		currentCobraCmd = cmd
+		cli.SetNoColor(flagNoColor)
 
 		// Install slog bridge FIRST so any code running during repo
 		// resolution (worktree lookups, identity refresh) already has the
@@ -219,6 +236,7 @@ Environment variables:
 
 	rootCmd.AddCommand(whoamiCmd())
 	rootCmd.AddCommand(versionCmd())
+	rootCmd.AddCommand(healthCmd())
 	rootCmd.AddCommand(waitCmd())
 	// thrum-7b84.3.5: `thrum cron install-inbox-poll` deprecated — the
 	// daemon-side backstop ticker (internal/daemon/backstop) now handles
@@ -234,6 +252,7 @@ Environment variables:
 
 	// Coordination commands
 	rootCmd.AddCommand(whoHasCmd())
+	rootCmd.AddCommand(whoCanCmd())
 	rootCmd.AddCommand(pingCmd())
 
 	// Configuration
@@ -245,10 +264,10 @@ Environment variables:
 	rootCmd.AddCommand(agentCmd())
 	rootCmd.AddCommand(sessionCmd())
 	rootCmd.AddCommand(messageCmd())
+	rootCmd.AddCommand(threadCmd())
+	rootCmd.AddCommand(groupCmd())
 	// subscribeCmd, unsubscribeCmd, subscriptionsCmd removed — use thrum wait for CLI notifications.
 	rootCmd.AddCommand(contextCmd())
-	// groupCmd() removed — groups are no longer user-facing.
-	// Group RPC handlers remain registered for Telegram bridge (tg:* groups).
 	rootCmd.AddCommand(runtimeGroupCmd())
 	rootCmd.AddCommand(syncCmd())
 	rootCmd.AddCommand(backupCmd())
@@ -259,6 +278,8 @@ Environment variables:
 	rootCmd.AddCommand(mcpCmd())
 	rootCmd.AddCommand(rolesCmd())
 	rootCmd.AddCommand(purgeCmd())
+	rootCmd.AddCommand(exportCmd())
+	rootCmd.AddCommand(importCmd())
 	rootCmd.AddCommand(telegramCmd())
 	rootCmd.AddCommand(tmuxCmd())
 	rootCmd.AddCommand(restartCmd())
@@ -311,20 +332,29 @@ message synchronization, and updates .gitignore.
 Use --stealth to avoid any footprint in tracked files: exclusions are
 written to .git/info/exclude instead of .gitignore.
 
+Exclusions live in a block between "# BEGIN thrum" and "# END thrum"
+markers that re-running init updates in place. Thrum entries found outside
+the block are moved into it. Use --import-gitignore (with --force on an
+initialized repo) to fold entries left by an older init into the block
+even when they are already complete.
+
 Detects installed AI runtimes and prompts you to select one (interactive).
 When --runtime is specified, uses that runtime directly without prompting.
 
 Examples:
   thrum init                          # Init + interactive runtime selection
   thrum init --stealth                # Init with zero tracked-file footprint
+  thrum init --force --import-gitignore # Adopt loose .gitignore entries
   thrum init --runtime claude         # Init + generate Claude configs
   thrum init --runtime codex --force  # Init + overwrite Codex configs
+  thrum init --runtime amp --dry-run  # Preview Amp configs
   thrum init --runtime all --dry-run  # Preview all runtime configs
   thrum init --skills                 # Install thrum skill for detected agent
   thrum init --skills --runtime cursor # Install skill for Cursor specifically`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			force, _ := cmd.Flags().GetBool("force")
 			stealth, _ := cmd.Flags().GetBool("stealth")
+			importGitignore, _ := cmd.Flags().GetBool("import-gitignore")
 			runtimeFlag, _ := cmd.Flags().GetString("runtime")
 			dryRun, _ := cmd.Flags().GetBool("dry-run")
 			skillsOnly, _ := cmd.Flags().GetBool("skills")
@@ -419,24 +449,26 @@ Examples:
 					}
 
 					return cli.RunWizard(&cli.WizardConfig{
-						RepoPath:      wizardRepo,
-						Prompter:      cli.NewScannerPrompter(os.Stdin, os.Stderr),
-						NameFlag:      name,
-						RoleFlag:      role,
-						ModuleFlag:    module,
-						WorktreesRoot: worktreesRoot,
-						RolesChoice:   rolesChoice,
-						NoDaemon:      noDaemon,
-						Force:         force,
-						Stealth:       stealth,
-						Runtime:       runtimeFlag,
+						RepoPath:        wizardRepo,
+						Prompter:        cli.NewScannerPrompter(os.Stdin, os.Stderr),
+						NameFlag:        name,
+						RoleFlag:        role,
+						ModuleFlag:      module,
+						WorktreesRoot:   worktreesRoot,
+						RolesChoice:     rolesChoice,
+						NoDaemon:        noDaemon,
+						Force:           force,
+						Stealth:         stealth,
+						Runtime:         runtimeFlag,
+						ImportGitignore: importGitignore,
 					})
 				}
 
 				opts := cli.InitOptions{
-					RepoPath: flagRepo,
-					Force:    force,
-					Stealth:  stealth,
+					RepoPath:        flagRepo,
+					Force:           force,
+					Stealth:         stealth,
+					ImportGitignore: importGitignore,
 				}
 
 				if err := cli.Init(opts); err != nil {
@@ -676,7 +708,7 @@ Examples:
 
 			// Start daemon if not already running
 			if _, err := getClient(); err != nil {
-				if startErr := cli.DaemonStart(flagRepo, false, false); startErr != nil && !strings.Contains(startErr.Error(), "already running") {
+				if startErr := cli.DaemonStart(flagRepo, cli.DaemonStartOptions{}); startErr != nil && !strings.Contains(startErr.Error(), "already running") {
 					fmt.Fprintf(os.Stderr, "Warning: could not auto-start daemon: %v\n", startErr)
 					fmt.Println("Start manually: thrum daemon start")
 				} else if !flagQuiet {
@@ -718,8 +750,9 @@ Examples:
 
 	cmd.Flags().Bool("force", false, "Force reinitialization / overwrite existing files")
 	cmd.Flags().Bool("stealth", false, "Use .git/info/exclude instead of .gitignore (zero footprint in tracked files)")
+	cmd.Flags().Bool("import-gitignore", false, "Move Thrum entries left loose by an older init into the managed ignore block")
 	cmd.Flags().Bool("dry-run", false, "Preview changes without writing files")
-	cmd.Flags().String("runtime", "", "Generate runtime-specific configs (claude|codex|cursor|gemini|opencode|cli-only|all)")
+	cmd.Flags().String("runtime", "", "Generate runtime-specific configs (claude|codex|cursor|gemini|opencode|auggie|amp|cli-only|all)")
 	cmd.Flags().Bool("skills", false, "Install thrum skill only (no MCP config, no startup script)")
 
 	// Wizard-related flags. The wizard fires on a TTY for fresh repos (or
@@ -905,9 +938,75 @@ func configGroupCmd() *cobra.Command {
 	}
 
 	cmd.AddCommand(configShowCmd())
+	cmd.AddCommand(configGetCmd())
+	cmd.AddCommand(configSetCmd())
 	return cmd
 }
 
+func configGetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "get KEY",
+		Short: "Print a config.json value",
+		Long: `Print the effective value of a .thrum/config.json key.
+
+Keys are dotted paths into config.json (e.g. daemon.log_level). Defaults
+are shown for keys the file leaves unset. List values print
+comma-separated. An unknown key lists the valid ones.
+
+Examples:
+  thrum config get daemon.log_level
+  thrum config get runtime.primary --json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			result, err := cli.ConfigGet(flagRepo, args[0])
+			if err != nil {
+				return err
+			}
+			if flagJSON {
+				return cli.EmitJSON(result)
+			}
+			fmt.Println(result.Value)
+			return nil
+		},
+	}
+}
+
+func configSetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "set KEY VALUE",
+		Short: "Set a config.json value",
+		Long: `Set a .thrum/config.json key, leaving the rest of the file untouched.
+
+Keys are dotted paths into config.json (e.g. daemon.log_level). The value
+must match the key's type: true/false for booleans, an integer for numbers,
+and a comma-separated list for lists (empty clears). An unknown key lists
+the valid ones. identity.* is written by the daemon and cannot be set.
+
+Most settings are read when the daemon starts; a reminder to restart it is
+printed for those.
+
+Examples:
+  thrum config set daemon.log_level debug
+  thrum config set daemon.events_retention_days 7
+  thrum config set runtime.primary claude
+  thrum config set permission_supervisors coordinator,@coordinator_main`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			result, err := cli.ConfigSet(flagRepo, args[0], args[1])
+			if err != nil {
+				return err
+			}
+			if flagJSON {
+				return cli.EmitJSON(result)
+			}
+			if !flagQuiet {
+				fmt.Print(cli.FormatConfigSet(result))
+			}
+			return nil
+		},
+	}
+}
+
 func configShowCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "show",
@@ -1014,6 +1113,124 @@ Examples:
 	return cmd
 }
 
+func exportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export all messages to a JSONL or markdown archive",
+		Long: `Export every message in the repo, with its scopes, refs and thread, to a
+self-contained archive, e.g. to hand the history to a teammate.
+
+--format jsonl (default) writes one record per line in the message archive
+format, which "thrum message import" reads back. --format markdown renders a
+readable document with one section per thread. Without --output the archive
+is written to stdout.
+
+Deleted messages are left out unless --include-deleted is set; they are then
+exported as tombstones (no body) and skipped by "thrum message import".
+
+--since accepts relative durations (7d, 24h), date-only (2026-03-15), and
+RFC 3339.
+
+Examples:
+  thrum export --output history.jsonl
+  thrum export --format markdown --output history.md
+  thrum export --since 7d --include-deleted > recent.jsonl`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, _ := cmd.Flags().GetString("format")
+			output, _ := cmd.Flags().GetString("output")
+			sinceFlag, _ := cmd.Flags().GetString("since")
+			includeDeleted, _ := cmd.Flags().GetBool("include-deleted")
+
+			if format != "jsonl" && format != "markdown" {
+				return fmt.Errorf("invalid --format %q (must be jsonl or markdown)", format)
+			}
+
+			opts := cli.ExportOptions{IncludeDeleted: includeDeleted}
+			if sinceFlag != "" {
+				since, err := timeparse.ParseBefore(sinceFlag)
+				if err != nil {
+					return fmt.Errorf("invalid --since value: %w", err)
+				}
+				opts.Since = since.Format(time.RFC3339)
+			}
+
+			client, err := getClient()
+			if err != nil {
+				return fmt.Errorf("failed to connect to daemon: %w", err)
+			}
+			defer func() { _ = client.Close() }()
+
+			result, err := cli.Export(client, opts)
+			if err != nil {
+				return err
+			}
+
+			if output == "" || output == "-" {
+				return cli.WriteExport(os.Stdout, result, format)
+			}
+
+			var buf strings.Builder
+			if err := cli.WriteExport(&buf, result, format); err != nil {
+				return err
+			}
+			if err := os.WriteFile(output, []byte(buf.String()), 0o600); err != nil {
+				return fmt.Errorf("write export file: %w", err)
+			}
+
+			summary := cli.ExportSummary{
+				Path:     output,
+				Format:   format,
+				Messages: len(result.Records),
+				Threads:  len(result.Threads),
+			}
+			for _, rec := range result.Records {
+				if rec.Deleted {
+					summary.Tombstones++
+				}
+			}
+			if flagJSON {
+				return cli.EmitJSON(summary)
+			}
+			if !flagQuiet {
+				fmt.Print(cli.FormatExportSummary(summary))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().String("format", "jsonl", "Archive format: jsonl or markdown")
+	cmd.Flags().StringP("output", "o", "", "Write the archive to this file (default: stdout)")
+	cmd.Flags().String("since", "", "Only messages created at or after: duration (7d, 24h), date (2026-03-15), or RFC 3339")
+	cmd.Flags().Bool("include-deleted", false, "Include deleted messages as tombstones")
+	return cmd
+}
+
+func importCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import PATH",
+		Short: "Load an exported JSONL archive into this repo",
+		Long: `Load a JSONL archive written by "thrum export" (or message.archive) into
+this repo, e.g. to move a repo's message history to a new location.
+
+Each record is replayed as a message.create event, preserving its message ID,
+timestamp, author, scopes, refs and thread. Messages whose ID already exists
+are skipped, so running the import twice is harmless; --force overwrites them
+instead. Deleted-message tombstones are skipped.
+
+Replies and forwards whose original message is in neither the archive nor
+this repo are imported unchanged and listed as dangling refs at the end.
+
+Same as "thrum message import".
+
+Examples:
+  thrum import history.jsonl
+  thrum import history.jsonl --force`,
+		Args: cobra.ExactArgs(1),
+		RunE: messageImportRunE,
+	}
+	cmd.Flags().Bool("force", false, "Overwrite messages whose ID already exists")
+	return cmd
+}
+
 func setupCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "setup",
@@ -1081,12 +1298,19 @@ is idempotent: running it twice produces byte-identical file content.`,
 func addBodyInputFlags(cmd *cobra.Command) {
 	cmd.Flags().Bool("stdin", false, "Read the message body from stdin (or pass the body argument as '-'); use a quoted heredoc <<'EOF' for shell-safe bodies")
 	cmd.Flags().String("body-file", "", "Read the message body from a file (shell-safe; avoids shell quoting/substitution)")
-	cmd.MarkFlagsMutuallyExclusive("stdin", "body-file")
+	cmd.Flags().String("file", "", "Alias for --body-file")
+	cmd.MarkFlagsMutuallyExclusive("stdin", "body-file", "file")
+}
+
+// stdinIsTerminal reports whether r is the process's stdin attached to a
+// terminal. Overridable in tests.
+var stdinIsTerminal = func(r io.Reader) bool {
+	return r == os.Stdin && isInteractive()
 }
 
 // resolveMessageBody resolves a message body from exactly one of three
 // mutually-exclusive sources: a positional argument, --stdin (also triggered by
-// passing the positional as "-"), or --body-file <path> (thrum-d3fp).
+// passing the positional as "-"), or --body-file / --file <path> (thrum-d3fp).
 //
 // hasPositional reports whether the body positional was supplied; positional
 // holds its value. Exactly one source must be present, else an actionable error
@@ -1096,12 +1320,19 @@ func addBodyInputFlags(cmd *cobra.Command) {
 func resolveMessageBody(cmd *cobra.Command, positional string, hasPositional bool) (string, error) {
 	useStdin, _ := cmd.Flags().GetBool("stdin")
 	bodyFile, _ := cmd.Flags().GetString("body-file")
+	fileFlag := "--body-file"
+	if f, _ := cmd.Flags().GetString("file"); f != "" {
+		bodyFile, fileFlag = f, "--file"
+	}
 
 	// A positional of "-" is an alias for --stdin.
 	if hasPositional && positional == "-" {
 		useStdin = true
 		hasPositional = false
 	}
+	if hasPositional && bodyFile != "" {
+		return "", fmt.Errorf("message body is ambiguous: got both a positional MESSAGE and %s; drop one", fileFlag)
+	}
 
 	sources := 0
 	if hasPositional {
@@ -1122,6 +1353,11 @@ func resolveMessageBody(cmd *cobra.Command, positional string, hasPositional boo
 
 	switch {
 	case useStdin:
+		// Reading an interactive terminal would block silently until EOF;
+		// callers almost always meant to pipe a body in.
+		if stdinIsTerminal(cmd.InOrStdin()) {
+			return "", fmt.Errorf("stdin is a terminal: pipe the body in (e.g. thrum send --to @agent - <<'EOF' ... EOF) or use --file PATH")
+		}
 		data, err := io.ReadAll(cmd.InOrStdin())
 		if err != nil {
 			return "", fmt.Errorf("read message body from stdin: %w", err)
@@ -1177,17 +1413,75 @@ or a file using a QUOTED heredoc:
   literal text with backticks and $(do not run me)
   EOF
 
-  thrum send --to @agent --body-file ./body.md
-  some-generator | thrum send --to @agent -        # '-' is a stdin alias`,
+  thrum send --to @agent --file ./body.md          # --body-file also works
+  some-generator | thrum send --to @agent -        # '-' is a stdin alias
+
+--snapshot-group expands a group to its current members at send time,
+following nested groups and roles, and addresses each member directly.
+Agents who join the group later do not see the message; a group mention
+(--mention @group) is resolved when read instead:
+  thrum send 'freeze starts now' --snapshot-group @release
+
+--mention-file mentions every agent whose session currently has the file in
+its changes (the same agents 'thrum who-has' lists), so the people touching
+the code see the message:
+  thrum send 'heads up: changing the token format' --mention-file internal/auth/token.go
+
+If nobody is editing the file, thrum warns and sends without those mentions;
+with no other recipient flag that is an unaddressed (team-wide) message.
+--require-recipients aborts instead.
+
+--attach copies a file onto the sync branch under attachments/<message_id>/
+and records its SHA-256 on the message; peers get it with the next sync.
+Repeat for several files. Files over 10 MB are sent with a warning, since
+every peer downloads them. Recipients fetch with 'thrum message attachment
+fetch':
+  thrum send 'build failed, log attached' --to @ops --attach ./build.log
+
+--reply-to sends the message as a reply to MSG_ID, like 'thrum reply' but
+with send's flags. The parent must exist and fails the same way reply does.
+--inherit-audience addresses the parent's audience (its mentions, author,
+and groups) and stands in for a recipient flag:
+  thrum send 'fixed in abc123' --reply-to msg_01H... --inherit-audience
+
+--schedule holds the message until a later time, given as RFC 3339 or as
++DURATION from now. It is sent right away but stays out of inboxes, and
+notifies no one, until then; it survives a daemon restart. Withdraw it
+before delivery with 'thrum message cancel MSG_ID':
+  thrum send 'standup in 10' --broadcast --schedule 2026-03-02T08:50:00-05:00
+  thrum send 'check the deploy' --to @ops --schedule +2h
+
+--dry-run resolves recipients, scopes, and refs on the daemon and prints
+them without sending. It fails on unknown recipients exactly as the real
+send would:
+  thrum send 'deploy at noon' --broadcast --dry-run`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			scopes, _ := cmd.Flags().GetStringSlice("scope")
 			refs, _ := cmd.Flags().GetStringSlice("ref")
 			mentions, _ := cmd.Flags().GetStringSlice("mention")
+			tags, _ := cmd.Flags().GetStringSlice("tag")
+			priority, _ := cmd.Flags().GetString("priority")
+			ttl, _ := cmd.Flags().GetString("ttl")
+			schedule, _ := cmd.Flags().GetString("schedule")
 			structured, _ := cmd.Flags().GetString("structured")
 			format, _ := cmd.Flags().GetString("format")
 			to, _ := cmd.Flags().GetString("to")
 			broadcast, _ := cmd.Flags().GetBool("broadcast")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			snapshotGroups, _ := cmd.Flags().GetStringSlice("snapshot-group")
+			mentionFiles, _ := cmd.Flags().GetStringSlice("mention-file")
+			requireRecipients, _ := cmd.Flags().GetBool("require-recipients")
+			attachments, _ := cmd.Flags().GetStringSlice("attach")
+			replyTo, _ := cmd.Flags().GetString("reply-to")
+			inheritAudience, _ := cmd.Flags().GetBool("inherit-audience")
+			idempotencyKey, _ := cmd.Flags().GetString("idempotency-key")
+			if idempotencyKey == "" {
+				// One key per invocation: the client redials and retries
+				// a call whose connection dropped, and the key keeps that
+				// retry from sending the message twice.
+				idempotencyKey = identity.GenerateIdempotencyKey()
+			}
 
 			// thrum-t698: require an explicit recipient flag. The
 			// previous default (silent broadcast when --to absent)
@@ -1196,8 +1490,11 @@ or a file using a QUOTED heredoc:
 			// Convention (CLAUDE.md "send to specific names, never
 			// role names") already says always --to; this aligns the
 			// CLI default with the convention.
-			if to == "" && !broadcast {
-				return fmt.Errorf("thrum send: missing recipient. Did you intend to:\n  - send to a specific agent? Use --to @agent_name\n  - broadcast to the entire team? Use --broadcast")
+			if inheritAudience && replyTo == "" {
+				return fmt.Errorf("--inherit-audience requires --reply-to")
+			}
+			if to == "" && !broadcast && len(snapshotGroups) == 0 && len(mentionFiles) == 0 && !inheritAudience {
+				return fmt.Errorf("thrum send: missing recipient. Did you intend to:\n  - send to a specific agent? Use --to @agent_name\n  - send to a group's current members? Use --snapshot-group @group\n  - mention whoever is editing a file? Use --mention-file PATH\n  - broadcast to the entire team? Use --broadcast")
 			}
 			// --broadcast desugars to the existing @everyone audience
 			// the daemon already accepts. --to @everyone continues
@@ -1224,14 +1521,22 @@ or a file using a QUOTED heredoc:
 			}
 
 			opts := cli.SendOptions{
-				Content:       content,
-				Scopes:        scopes,
-				Refs:          refs,
-				Mentions:      mentions,
-				Structured:    structured,
-				Format:        format,
-				To:            to,
-				CallerAgentID: "", // set below
+				Content:        content,
+				Scopes:         scopes,
+				Refs:           refs,
+				Mentions:       mentions,
+				SnapshotGroups: snapshotGroups,
+				Tags:           tags,
+				Priority:       priority,
+				TTL:            ttl,
+				Schedule:       schedule,
+				Attachments:    attachments,
+				ReplyTo:        replyTo,
+				Structured:     structured,
+				Format:         format,
+				To:             to,
+				IdempotencyKey: idempotencyKey,
+				CallerAgentID:  "", // set below
 			}
 
 			agentID, err := resolveLocalAgentID()
@@ -1246,6 +1551,53 @@ or a file using a QUOTED heredoc:
 			}
 			defer func() { _ = client.Close() }()
 
+			// Resolve --mention-file to the agents editing each file and
+			// add them as mentions, skipping the sender and anyone already
+			// mentioned.
+			mentioned := make(map[string]bool, len(opts.Mentions))
+			for _, m := range opts.Mentions {
+				mentioned[strings.TrimPrefix(m, "@")] = true
+			}
+			for _, file := range mentionFiles {
+				result, err := cli.AgentListContext(client, "", "", file)
+				if err != nil {
+					return fmt.Errorf("resolve --mention-file %s: %w", file, err)
+				}
+				editors := cli.FileEditors(result, agentID)
+				if len(editors) == 0 {
+					if requireRecipients {
+						return fmt.Errorf("thrum send: no agents are editing %s (--require-recipients)", file)
+					}
+					fmt.Fprintf(os.Stderr, "  warning: no agents are editing %s; sending without mentioning anyone for it\n", file)
+					continue
+				}
+				for _, id := range editors {
+					if !mentioned[id] {
+						mentioned[id] = true
+						opts.Mentions = append(opts.Mentions, "@"+id)
+					}
+				}
+			}
+
+			if replyTo != "" {
+				opts, err = cli.PrepareReplyTo(client, opts, inheritAudience)
+				if err != nil {
+					return err
+				}
+			}
+
+			if dryRun {
+				preview, err := cli.Resolve(client, opts)
+				if err != nil {
+					return err
+				}
+				if flagJSON {
+					return cli.EmitJSON(preview)
+				}
+				fmt.Print(cli.FormatResolve(preview))
+				return nil
+			}
+
 			// Hint pipeline: pre-action collection only. Send has no
 			// post-action hints in the pilot; recipient-stale is info
 			// severity so HandlePreAction never blocks — but collecting
@@ -1273,11 +1625,21 @@ or a file using a QUOTED heredoc:
 				}
 			} else if !flagQuiet {
 				// Human-readable output
-				fmt.Printf("✓ Message sent: %s\n", result.MessageID)
+				if result.DeliverAt != "" {
+					fmt.Printf("✓ Message scheduled: %s\n", result.MessageID)
+				} else {
+					fmt.Printf("✓ Message sent: %s\n", result.MessageID)
+				}
 				if result.ThreadID != "" {
 					fmt.Printf("  Thread: %s\n", result.ThreadID)
 				}
 				fmt.Printf("  Created: %s\n", result.CreatedAt)
+				if result.DeliverAt != "" {
+					fmt.Printf("  Delivers: %s\n", result.DeliverAt)
+				}
+				if result.ExpiresAt != "" {
+					fmt.Printf("  Expires: %s\n", result.ExpiresAt)
+				}
 				if len(result.Audiences) > 0 {
 					parts := make([]string, len(result.Audiences))
 					for i, audience := range result.Audiences {
@@ -1305,11 +1667,25 @@ or a file using a QUOTED heredoc:
 	cmd.Flags().StringSlice("scope", nil, "Add scope (repeatable, format: type:value)")
 	cmd.Flags().StringSlice("ref", nil, "Add reference (repeatable, format: type:value)")
 	cmd.Flags().StringSlice("mention", nil, "Mention a role (repeatable, format: @role)")
+	cmd.Flags().StringSlice("tag", nil, "Tag the message (repeatable; lowercase letters, digits, dashes)")
+	cmd.Flags().String("priority", "", "Message priority: low, normal, or high (filter with inbox --priority)")
+	cmd.Flags().String("ttl", "", "Delete the message this long after sending, e.g. 30m or 2h (replies keep the thread alive)")
+	cmd.Flags().String("schedule", "", "Deliver the message later: an RFC 3339 time or +DURATION, e.g. +2h (cancel with 'thrum message cancel')")
 	cmd.Flags().String("structured", "", "Structured payload (JSON)")
-	cmd.Flags().String("format", "markdown", "Message format (markdown, plain, json)")
+	cmd.Flags().StringSlice("attach", nil, "Attach a file, synced on the a-sync branch (repeatable)")
+	cmd.Flags().String("format", "markdown", "Message format (markdown, plain, json); json bodies must parse as JSON")
 	cmd.Flags().String("to", "", "Recipient (@agent_name or @everyone)")
 	cmd.Flags().Bool("broadcast", false, "Fan out to the entire team (mutually exclusive with --to)")
+	cmd.Flags().StringSlice("snapshot-group", nil, "Send to a group's current members, expanded now (repeatable, format: @group)")
+	cmd.Flags().StringSlice("mention-file", nil, "Mention the agents currently editing this file (repeatable)")
+	cmd.Flags().Bool("require-recipients", false, "With --mention-file, abort instead of warning when nobody is editing a file")
+	cmd.Flags().Bool("dry-run", false, "Show resolved recipients, scopes, and refs without sending")
+	cmd.Flags().String("reply-to", "", "Send as a reply to this message ID (same checks as 'thrum reply')")
+	cmd.Flags().Bool("inherit-audience", false, "With --reply-to, address the parent's audience (counts as a recipient flag)")
+	cmd.Flags().String("idempotency-key", "", "Key for safe retries: resending with the same key within 24h returns the first send's result (default: new key per run)")
 	cmd.MarkFlagsMutuallyExclusive("to", "broadcast")
+	cmd.MarkFlagsMutuallyExclusive("snapshot-group", "broadcast")
+	cmd.MarkFlagsMutuallyExclusive("inherit-audience", "broadcast")
 	addBodyInputFlags(cmd)
 
 	return cmd
@@ -1374,8 +1750,173 @@ to inspect a message with full recipient state.`,
 	return cmd
 }
 
-// groupCmd and subcommands removed — groups are no longer user-facing.
-// Group RPC handlers (group.go) remain for Telegram bridge (tg:* groups).
+// groupCmd exposes only `group members`, `group rename` and `group copy`;
+// create/add/remove/delete were removed when groups stopped being
+// user-facing. The remaining group RPC handlers (group.go) serve the
+// Telegram bridge (tg:* groups).
+func groupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "group",
+		Short: "Manage groups",
+	}
+
+	renameCmd := &cobra.Command{
+		Use:   "rename OLD NEW",
+		Short: "Rename a group, keeping its message history",
+		Long: `Rename a group in place. Members are kept, and messages addressed to the
+old name are moved to the new name so inbox filtering still finds them.
+
+The new name must not already be taken, and the built-in @everyone group
+cannot be renamed. A leading @ on either name is ignored.
+
+Example:
+  thrum group rename @eng @engineering`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := getClient()
+			if err != nil {
+				return fmt.Errorf("failed to connect to daemon: %w", err)
+			}
+			defer func() { _ = client.Close() }()
+
+			callerID, _ := resolveLocalAgentID()
+			result, err := cli.GroupRename(client,
+				strings.TrimPrefix(args[0], "@"), strings.TrimPrefix(args[1], "@"), callerID)
+			if err != nil {
+				return err
+			}
+
+			if flagJSON {
+				return cli.EmitJSON(result)
+			}
+			if !flagQuiet {
+				fmt.Print(cli.FormatGroupRename(result))
+			}
+			return nil
+		},
+	}
+	cmd.AddCommand(renameCmd)
+
+	copyCmd := &cobra.Command{
+		Use:   "copy SRC DST",
+		Short: "Create a group with another group's members",
+		Long: `Create DST with the same description and members as SRC, for starting a
+parallel team without re-adding everyone. DST must not already exist.
+
+Nested groups in SRC are copied as references to those groups, so DST keeps
+following their membership. --expand copies their agent and role members
+instead, recursively, so DST no longer depends on them. A leading @ on
+either name is ignored.
+
+Examples:
+  thrum group copy @reviewers @reviewers-v2
+  thrum group copy @release @release-snapshot --expand`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			expand, _ := cmd.Flags().GetBool("expand")
+
+			client, err := getClient()
+			if err != nil {
+				return fmt.Errorf("failed to connect to daemon: %w", err)
+			}
+			defer func() { _ = client.Close() }()
+
+			callerID, _ := resolveLocalAgentID()
+			result, err := cli.GroupCopy(client,
+				strings.TrimPrefix(args[0], "@"), strings.TrimPrefix(args[1], "@"), expand, callerID)
+			if err != nil {
+				return err
+			}
+
+			if flagJSON {
+				return cli.EmitJSON(result)
+			}
+			if !flagQuiet {
+				fmt.Print(cli.FormatGroupCopy(result))
+			}
+			return nil
+		},
+	}
+	copyCmd.Flags().Bool("expand", false, "Copy nested groups' agent and role members instead of the groups themselves")
+	cmd.AddCommand(copyCmd)
+
+	membersCmd := &cobra.Command{
+		Use:   "members NAME",
+		Short: "List a group's members",
+		Long: `List a group's direct members: agents, roles, and nested groups.
+
+--expand also resolves the group to agents, following roles and nested
+groups recursively. Each agent is listed once. A membership cycle (a group
+that contains itself through other groups) is reported as a warning rather
+than followed forever; every group on it is still expanded once.
+
+Examples:
+  thrum group members @release
+  thrum group members @release --expand`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			expand, _ := cmd.Flags().GetBool("expand")
+			name := strings.TrimPrefix(args[0], "@")
+
+			client, err := getClient()
+			if err != nil {
+				return fmt.Errorf("failed to connect to daemon: %w", err)
+			}
+			defer func() { _ = client.Close() }()
+
+			result, err := cli.GroupMembers(client, cli.GroupMembersOptions{Name: name, Expand: expand})
+			if err != nil {
+				return err
+			}
+
+			if flagJSON {
+				return cli.EmitJSON(result)
+			}
+			fmt.Print(cli.FormatGroupMembers(name, result, expand))
+			return nil
+		},
+	}
+	membersCmd.Flags().Bool("expand", false, "Resolve roles and nested groups to agents")
+	cmd.AddCommand(membersCmd)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "info NAME",
+		Short: "Show a group's member tree and who is online",
+		Long: `Show a group's description and its members as a tree, with each agent's
+presence: active, away (session open but silent past the idle threshold),
+or offline. Role members are expanded to the agents holding the role, and
+nested groups to their own members, recursively. A nested group that leads
+back to one of its ancestors is marked as a cycle and not expanded again.
+
+The header counts the distinct agents in the tree and how many are active.
+--json returns the same tree as nested "children" arrays.
+
+Examples:
+  thrum group info @reviewers
+  thrum group info @release --json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := getClient()
+			if err != nil {
+				return fmt.Errorf("failed to connect to daemon: %w", err)
+			}
+			defer func() { _ = client.Close() }()
+
+			result, err := cli.GroupInfo(client, strings.TrimPrefix(args[0], "@"))
+			if err != nil {
+				return err
+			}
+
+			if flagJSON {
+				return cli.EmitJSON(result)
+			}
+			fmt.Print(cli.FormatGroupInfo(result))
+			return nil
+		},
+	})
+
+	return cmd
+}
 
 func inboxCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -1390,15 +1931,94 @@ Messages are shown newest-first, so --limit N returns the N most recent. Use
 --chronological (alias --oldest) to read oldest-first with replies clustered
 under their parent.
 
+--threaded reads conversations in context: it implies --chronological and
+nests each reply beneath its parent with a ↳ connector, one level deeper per
+reply. A reply whose parent is not on the current page is marked with an
+"(in thread …)" breadcrumb instead.
+
+--grep REGEX filters the fetched page to messages whose body matches a Go
+regular expression (case-insensitive; a plain word matches as a substring)
+and highlights each match as **match**. It applies to the current page only
+and composes with --unread, --scope, --from, or a larger --limit. An invalid
+pattern is an error before the daemon is contacted.
+
+--since limits the inbox to messages created after a point in time: an
+RFC3339 timestamp or a relative duration like -1h or -30m. It combines with
+--unread and the other filters.
+
+--priority high shows only messages sent with send --priority high; these
+are marked [high] in the listing. --priority-sort keeps every message but
+moves unread high-priority ones to the top of the page order.
+
+--bump-sort orders messages by when they were last bumped with 'thrum message
+bump', falling back to when they were sent, so a resurfaced message moves back
+to the top. Not with --chronological or --threaded.
+
+--pinned lists only messages pinned with 'thrum message pin', including your
+own and ones sent before you registered. Pinned messages are marked 📌, and
+every listing ends with a "📌 N pinned" hint when any are pinned.
+
+--assigned-to-me lists only open tasks assigned to you with 'thrum message
+assign', whoever they were addressed to. Complete one with 'thrum message
+complete MSG_ID'.
+
+--deleted also lists messages removed with 'thrum message delete', marked
+"[deleted] reason: …" above the original text, for auditing what was removed
+and why. Deleted messages are otherwise left out and never count as unread.
+
+--template prints one line per message from a Go text/template over the
+message fields, e.g. '{{.AgentID}}: {{.Body.Content}}'. Unknown fields render
+empty. Not with --json or --watch.
+
+--cursor pages with a cursor instead of a page number: pass the
+next_cursor of the previous page (shown in the footer, or in --json output)
+to get the page after it. Messages that arrive in between don't shift rows
+onto the next page or repeat them. Start a walk with --cursor "". Not with
+--page, --chronological, --threaded, or --priority-sort.
+
+--ids-only prints just the message IDs, one per line, for piping into xargs
+or a reply. It prints nothing else and does not mark the messages read. Not
+with --json, --template, or --watch.
+
+--watch keeps running and streams new messages to stdout as JSON Lines, one
+message object per line, oldest first. Filters apply as usual; --since sets
+where the stream starts (default: now). If the daemon restarts, the stream
+reconnects and replays anything newer than the last emitted message. Watched
+messages are not marked read. Stop with Ctrl-C.
+
 The daemon must be running and you must have an active session.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			var since time.Time
+			if sinceStr, _ := cmd.Flags().GetString("since"); sinceStr != "" {
+				var err error
+				since, err = parseSinceTime(sinceStr, time.Now())
+				if err != nil {
+					return err
+				}
+			}
+
 			scope, _ := cmd.Flags().GetString("scope")
 			mentions, _ := cmd.Flags().GetBool("mentions")
 			unread, _ := cmd.Flags().GetBool("unread")
 			showAll, _ := cmd.Flags().GetBool("all")
 			pageSize, _ := cmd.Flags().GetInt("page-size")
 			page, _ := cmd.Flags().GetInt("page")
+			cursor, _ := cmd.Flags().GetString("cursor")
+			useCursor := cmd.Flags().Changed("cursor")
+			if useCursor && cmd.Flags().Changed("page") {
+				return fmt.Errorf("--cursor cannot be combined with --page")
+			}
 			fromAgent, _ := cmd.Flags().GetString("from")
+			authorRole, _ := cmd.Flags().GetString("author-role")
+			tag, _ := cmd.Flags().GetString("tag")
+			priority, _ := cmd.Flags().GetString("priority")
+			prioritySort, _ := cmd.Flags().GetBool("priority-sort")
+			bumpSort, _ := cmd.Flags().GetBool("bump-sort")
+			pinned, _ := cmd.Flags().GetBool("pinned")
+			assignedToMe, _ := cmd.Flags().GetBool("assigned-to-me")
+			includeExpired, _ := cmd.Flags().GetBool("include-expired")
+			includeDeleted, _ := cmd.Flags().GetBool("deleted")
+			grep, _ := cmd.Flags().GetString("grep")
 			// thrum-3vl0: default is newest-first; --chronological (alias
 			// --oldest) opts into the oldest-first, reply-clustered view for
 			// reading a thread in order.
@@ -1406,6 +2026,31 @@ The daemon must be running and you must have an active session.`,
 			if oldest, _ := cmd.Flags().GetBool("oldest"); oldest {
 				chronological = true
 			}
+			threaded, _ := cmd.Flags().GetBool("threaded")
+			if threaded {
+				chronological = true
+			}
+			if bumpSort && chronological {
+				return fmt.Errorf("--bump-sort cannot be combined with --chronological or --threaded")
+			}
+			// Compile --grep and --template before any RPC so a bad pattern
+			// fails fast.
+			grepRe, err := cli.CompileGrep(grep)
+			if err != nil {
+				return err
+			}
+			templateText, _ := cmd.Flags().GetString("template")
+			itemTmpl, err := cli.CompileItemTemplate(templateText)
+			if err != nil {
+				return err
+			}
+			if itemTmpl != nil && flagJSON {
+				return fmt.Errorf("--template cannot be combined with --json")
+			}
+			idsOnly, _ := cmd.Flags().GetBool("ids-only")
+			if idsOnly && (flagJSON || itemTmpl != nil) {
+				return fmt.Errorf("--ids-only cannot be combined with --json or --template")
+			}
 
 			// --limit is an alias for --page-size
 			if cmd.Flags().Changed("limit") {
@@ -1432,9 +2077,20 @@ The daemon must be running and you must have an active session.`,
 				Unread:            unread,
 				PageSize:          pageSize,
 				Page:              page,
+				Cursor:            cursor,
 				CallerAgentID:     agentID,
 				CallerMentionRole: agentRole,
 				AuthorID:          fromAgent,
+				AuthorRole:        strings.TrimPrefix(authorRole, "@"),
+				Tag:               tag,
+				Priority:          priority,
+				PrioritySort:      prioritySort,
+				BumpSort:          bumpSort,
+				Pinned:            pinned,
+				IncludeExpired:    includeExpired,
+				IncludeDeleted:    includeDeleted,
+				IncludeSelf:       pinned || assignedToMe,
+				CreatedAfter:      since,
 				Chronological:     chronological,
 			}
 
@@ -1444,6 +2100,16 @@ The daemon must be running and you must have an active session.`,
 				opts.ForAgent = agentID
 				opts.ForAgentRole = agentRole
 			}
+			// A task needn't be addressed to its assignee, so --assigned-to-me
+			// replaces the addressed-to-me filter rather than narrowing it.
+			if assignedToMe {
+				if agentID == "" {
+					return fmt.Errorf("--assigned-to-me needs a registered agent identity")
+				}
+				opts.AssignedTo = agentID
+				opts.ForAgent = ""
+				opts.ForAgentRole = ""
+			}
 
 			client, err := getClient()
 			if err != nil {
@@ -1471,21 +2137,79 @@ The daemon must be running and you must have an active session.`,
 				}
 			}
 
-			result, err := cli.Inbox(client, opts)
-			if err != nil {
+			if watch, _ := cmd.Flags().GetBool("watch"); watch {
+				if grep != "" {
+					return fmt.Errorf("--grep cannot be combined with --watch")
+				}
+				if pinned {
+					return fmt.Errorf("--pinned cannot be combined with --watch")
+				}
+				if assignedToMe {
+					return fmt.Errorf("--assigned-to-me cannot be combined with --watch")
+				}
+				if includeDeleted {
+					return fmt.Errorf("--deleted cannot be combined with --watch")
+				}
+				if itemTmpl != nil {
+					return fmt.Errorf("--template cannot be combined with --watch")
+				}
+				if threaded {
+					return fmt.Errorf("--threaded cannot be combined with --watch")
+				}
+				if useCursor {
+					return fmt.Errorf("--cursor cannot be combined with --watch")
+				}
+				if idsOnly {
+					return fmt.Errorf("--ids-only cannot be combined with --watch")
+				}
+				socketPath := os.Getenv("THRUM_SOCKET")
+				if socketPath == "" {
+					socketPath = cli.DefaultSocketPath(flagRepo)
+				}
+				ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+				defer stop()
+				return cli.InboxWatch(ctx, os.Stdout, cli.InboxWatchOptions{
+					Inbox:      opts,
+					SocketPath: socketPath,
+					RepoPath:   flagRepo,
+					Since:      since,
+					Quiet:      flagQuiet,
+				})
+			}
+
+			result, err := cli.Inbox(client, opts)
+			if err != nil {
 				return err
 			}
+			// --grep narrows the fetched page client-side; only the
+			// surviving messages are rendered and auto-marked read below.
+			grepScanned := cli.FilterInboxByBody(result, grepRe)
+
+			if idsOnly {
+				// IDs only: no footer, no hints, and no auto mark-read.
+				fmt.Print(cli.FormatMessageIDs(result))
+				return nil
+			}
 
 			if flagJSON {
 				if err := cli.EmitJSON(result); err != nil {
 					return err
 				}
+			} else if itemTmpl != nil {
+				if err := cli.RenderItems(os.Stdout, itemTmpl, result.Messages); err != nil {
+					return err
+				}
 			} else {
 				// Human-readable formatted output with filter context
 				fmtOpts := cli.InboxFormatOptions{
 					ActiveScope: scope,
 					ForAgent:    opts.ForAgent,
 					Unread:      unread,
+					Grep:        grep,
+					GrepRegexp:  grepRe,
+					GrepScanned: grepScanned,
+					Threaded:    threaded,
+					Cursor:      useCursor,
 					Quiet:       flagQuiet,
 					JSON:        flagJSON,
 				}
@@ -1524,16 +2248,65 @@ The daemon must be running and you must have an active session.`,
 	cmd.Flags().Int("page-size", 10, "Results per page")
 	cmd.Flags().Int("limit", 0, "Alias for --page-size")
 	cmd.Flags().Int("page", 1, "Page number")
+	cmd.Flags().String("cursor", "", "Page after this next_cursor instead of by page number (\"\" starts at the top)")
 	cmd.Flags().String("from", "", "Filter inbox to messages from a specific agent (use @agent_name or agent_name)")
+	cmd.Flags().String("author-role", "", "Filter inbox to messages authored by any agent with this role")
+	cmd.Flags().String("tag", "", "Filter inbox to messages carrying this tag (set via send --tag)")
+	cmd.Flags().String("priority", "", "Filter inbox to messages with this priority (low, normal, high)")
+	cmd.Flags().Bool("priority-sort", false, "List unread high-priority messages first")
+	cmd.Flags().Bool("bump-sort", false, "Order by last 'thrum message bump', else send time (newest first)")
+	cmd.Flags().Bool("pinned", false, "Only messages pinned with 'thrum message pin'")
+	cmd.Flags().Bool("assigned-to-me", false, "Only open tasks assigned to you with 'thrum message assign'")
+	cmd.Flags().Bool("include-expired", false, "Include send --ttl messages past their expiry that cleanup hasn't deleted yet")
+	cmd.Flags().Bool("deleted", false, "Include deleted messages, marked [deleted] with the reason")
+	cmd.Flags().String("since", "", "Only messages created after this time (RFC3339, or relative like -1h, -30m)")
+	cmd.Flags().String("grep", "", "Only show messages on the fetched page whose body matches REGEX (case-insensitive)")
+	cmd.Flags().String("template", "", "Render each message with a Go text/template, e.g. '{{.AgentID}}: {{.Body.Content}}'")
+	cmd.Flags().Bool("ids-only", false, "Print only message IDs, one per line, without marking them read")
 	// thrum-3vl0: inbox defaults to newest-first. --chronological (alias
 	// --oldest) switches to the oldest-first, reply-clustered view for reading
 	// a thread in order.
 	cmd.Flags().Bool("chronological", false, "Oldest-first, reply-clustered order (default is newest-first)")
 	cmd.Flags().Bool("oldest", false, "Alias for --chronological (oldest-first)")
+	cmd.Flags().Bool("threaded", false, "Nest replies beneath their parent message (implies --chronological)")
+	cmd.Flags().Bool("watch", false, "Stream new messages as JSON Lines until interrupted (reconnects across daemon restarts)")
 
 	return cmd
 }
 
+func healthCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "health",
+		Short: "Show daemon health",
+		Long: `Show the daemon's health check: status, version, uptime, repo ID, sync
+state, and Tailscale peers.
+
+Intended for monitoring. Exits 1 with a one-line error when the daemon is not
+running or doesn't answer.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// No identity refresh: health must work from any directory a
+			// monitor runs in, and must not fail on agent-level guards.
+			client, err := getClientNoRefresh()
+			if err != nil {
+				return fmt.Errorf("daemon is not running — start it with: thrum daemon start")
+			}
+			defer func() { _ = client.Close() }()
+
+			result, err := cli.Health(client)
+			if err != nil {
+				return err
+			}
+
+			if flagJSON {
+				return cli.EmitJSON(result)
+			}
+			fmt.Print(cli.FormatHealth(result))
+			return nil
+		},
+	}
+}
+
 func versionCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "version",
@@ -1549,11 +2322,11 @@ func versionCmd() *cobra.Command {
 					"website_url": "https://thrum.team",
 				})
 			}
-			// Human-readable output with OSC 8 hyperlinks
-			// Format: ESC ] 8 ; ; URL ESC \ TEXT ESC ] 8 ; ; ESC \
+			// Human-readable output with OSC 8 hyperlinks (plain URLs under
+			// --no-color / NO_COLOR)
 			fmt.Printf("thrum v%s (build: %s, %s)\n", Version, Build, goruntime.Version())
-			fmt.Printf("\x1b]8;;https://github.com/leonletto/thrum\x07https://github.com/leonletto/thrum\x1b]8;;\x07\n")
-			fmt.Printf("\x1b]8;;https://thrum.team\x07https://thrum.team\x1b]8;;\x07\n")
+			fmt.Println(cli.Hyperlink("https://github.com/leonletto/thrum", "https://github.com/leonletto/thrum"))
+			fmt.Println(cli.Hyperlink("https://thrum.team", "https://thrum.team"))
 			return nil
 		},
 	}
@@ -1623,6 +2396,30 @@ func agentSummaryField(s *cli.AgentSummary, field string) (string, bool) {
 // and `thrum agent whoami`. It loads identity, optionally enriches from the
 // daemon, then prints the result.
 func runWhoami(cmd *cobra.Command, args []string) error {
+	if resolve, _ := cmd.Flags().GetBool("resolve"); resolve {
+		if all, _ := cmd.Flags().GetBool("all"); all {
+			return fmt.Errorf("--resolve cannot be combined with --all")
+		}
+		if field, _ := cmd.Flags().GetString("field"); field != "" {
+			return fmt.Errorf("--resolve cannot be combined with --field")
+		}
+		if check, _ := cmd.Flags().GetBool("check"); check {
+			return fmt.Errorf("--resolve cannot be combined with --check")
+		}
+		return runAgentID(cmd, args)
+	}
+	if check, _ := cmd.Flags().GetBool("check"); check {
+		if all, _ := cmd.Flags().GetBool("all"); all {
+			return fmt.Errorf("--check cannot be combined with --all")
+		}
+		if field, _ := cmd.Flags().GetString("field"); field != "" {
+			return fmt.Errorf("--check cannot be combined with --field")
+		}
+	}
+	if all, _ := cmd.Flags().GetBool("all"); all {
+		return runWhoamiAll(cmd)
+	}
+
 	identityFile, identityPath, err := config.LoadIdentityWithPath(flagRepo)
 	if err != nil {
 		thrumDir := filepath.Join(flagRepo, ".thrum")
@@ -1636,6 +2433,10 @@ func runWhoami(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if check, _ := cmd.Flags().GetBool("check"); check {
+		return runWhoamiCheck(identityFile, identityPath)
+	}
+
 	// Try daemon enrichment (non-fatal)
 	var daemonInfo *cli.WhoamiResult
 	if client, clientErr := getClient(); clientErr == nil {
@@ -1658,6 +2459,78 @@ func runWhoami(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// runWhoamiCheck cross-references the identity file with the daemon's view
+// of the agent and exits 1 on any mismatch. It connects without the identity
+// refresh getClient does, so the file is checked as found rather than after
+// the daemon rewrites it. When the daemon is down only the file is checked,
+// and the output says so.
+func runWhoamiCheck(identityFile *config.IdentityFile, identityPath string) error {
+	var daemonInfo *cli.WhoamiResult
+	var daemonErr error
+	if client, clientErr := getClientNoRefresh(); clientErr == nil {
+		defer func() { _ = client.Close() }()
+		daemonInfo, daemonErr = cli.AgentWhoami(client, identityFile.Agent.Name)
+	}
+
+	check := cli.CheckIdentity(identityFile, identityPath, daemonInfo, daemonErr)
+	if flagJSON {
+		if err := cli.EmitJSON(check); err != nil {
+			return err
+		}
+	} else {
+		fmt.Print(cli.FormatIdentityCheck(check))
+	}
+	if !check.OK {
+		os.Exit(1)
+	}
+	return nil
+}
+
+// runAgentID prints the agent ID resolveLocalAgentID computes — the ID that
+// send, inbox and the other commands act as — and nothing else, so scripts
+// can capture it with AGENT=$(thrum agent id). It needs no daemon.
+func runAgentID(cmd *cobra.Command, args []string) error {
+	result, err := cli.AgentID(flagRepo, flagRole, flagModule)
+	if err != nil {
+		return err
+	}
+	if flagJSON {
+		return cli.EmitJSON(result)
+	}
+	fmt.Print(cli.FormatAgentID(result))
+	return nil
+}
+
+// runWhoamiAll lists every identity file in the repo: the main .thrum/
+// (following a worktree's redirect) and each git worktree's identities/.
+func runWhoamiAll(cmd *cobra.Command) error {
+	if field, _ := cmd.Flags().GetString("field"); field != "" {
+		return fmt.Errorf("--field cannot be combined with --all")
+	}
+	thrumDir, err := paths.ResolveThrumDir(flagRepo)
+	if err != nil {
+		return err
+	}
+	if _, statErr := os.Stat(thrumDir); os.IsNotExist(statErr) {
+		return fmt.Errorf("thrum not initialized in this repository\n  Run 'thrum init' first")
+	}
+
+	dirs := rpc.AllIdentityDirs(cmd.Context(), thrumDir)
+	if local := paths.IdentitiesDir(flagRepo); !slices.Contains(dirs, local) {
+		dirs = append(dirs, local)
+	}
+	entries, warnings := cli.ListIdentities(dirs)
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+	}
+
+	if flagJSON {
+		return cli.EmitJSON(entries)
+	}
+	fmt.Print(cli.FormatIdentityList(entries))
+	return nil
+}
+
 func whoamiCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "whoami",
@@ -1667,14 +2540,30 @@ func whoamiCmd() *cobra.Command {
 Shows the current agent identity. Reads directly from
 .thrum/identities/*.json files.
 
+--all lists every identity registered in the repo instead: the main
+.thrum/identities/ (followed through a worktree's redirect) and the
+identities/ of each git worktree, one per line. Malformed identity files
+are skipped with a warning.
+
+--check validates the identity instead: the file must name an agent with
+a role and module, and the daemon must know that agent with the same role
+and module. Mismatches are listed and exit 1. When the daemon is not
+running only the file is checked, and the output says so.
+
 Examples:
   thrum whoami
   thrum whoami --json
+  thrum whoami --all --json
+  thrum whoami --resolve
+  thrum whoami --check
   THRUM_NAME=alice thrum whoami`,
 		RunE: runWhoami,
 	}
 
 	cmd.Flags().String("field", "", "Print a single field's value (e.g. agent_id, tmux_alive) and exit")
+	cmd.Flags().Bool("all", false, "List every identity in the repo (name, role, module, worktree)")
+	cmd.Flags().Bool("resolve", false, "Print only the resolved agent ID (same as 'thrum agent id')")
+	cmd.Flags().Bool("check", false, "Check the identity file against the daemon's record; exit 1 on mismatch")
 
 	return cmd
 }
@@ -1712,24 +2601,9 @@ Exit codes:
 			// Parse --after relative time
 			var afterTime time.Time
 			if afterStr != "" {
-				// Parse as relative duration from now
-				// "-30s" = 30s ago, "30s" or "+30s" = 30s from now
-				durationStr := afterStr
-				negate := false
-				if strings.HasPrefix(durationStr, "-") {
-					negate = true
-					durationStr = durationStr[1:]
-				} else if strings.HasPrefix(durationStr, "+") {
-					durationStr = durationStr[1:]
-				}
-				d, parseErr := time.ParseDuration(durationStr)
-				if parseErr != nil {
-					return fmt.Errorf("invalid --after duration %q: %w (examples: -30s, -5m, +60s)", afterStr, parseErr)
-				}
-				if negate {
-					afterTime = time.Now().Add(-d)
-				} else {
-					afterTime = time.Now().Add(d)
+				afterTime, err = parseRelativeTime("after", afterStr, time.Now())
+				if err != nil {
+					return err
 				}
 			} else {
 				// Default: look back 1s to avoid race between sender and wait startup.
@@ -1818,9 +2692,43 @@ Exit codes:
 	return cmd
 }
 
+// parseRelativeTime parses a relative duration flag value against now:
+// "-30s" is 30s ago, "30s" or "+30s" is 30s from now. flag names the flag in
+// the error so wait --after and inbox --since fail the same way.
+func parseRelativeTime(flag, value string, now time.Time) (time.Time, error) {
+	durationStr := value
+	negate := false
+	if strings.HasPrefix(durationStr, "-") {
+		negate = true
+		durationStr = durationStr[1:]
+	} else if strings.HasPrefix(durationStr, "+") {
+		durationStr = durationStr[1:]
+	}
+	d, err := time.ParseDuration(durationStr)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --%s duration %q: %w (examples: -30s, -5m, +60s)", flag, value, err)
+	}
+	if negate {
+		return now.Add(-d), nil
+	}
+	return now.Add(d), nil
+}
+
+// parseSinceTime parses inbox --since: an RFC3339 timestamp, or a relative
+// duration as accepted by wait --after.
+func parseSinceTime(value string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, nil
+	}
+	return parseRelativeTime("since", value, now)
+}
+
 func daemonCmd() *cobra.Command {
 	var flagLocal bool
 	var flagForce bool
+	var flagLogLevel string
+	var flagSocket string
+	var flagWSPort int
 
 	cmd := &cobra.Command{
 		Use:   "daemon",
@@ -1831,12 +2739,59 @@ func daemonCmd() *cobra.Command {
 		"Local-only mode: skip git push/fetch in sync loop")
 	cmd.PersistentFlags().BoolVar(&flagForce, "force", false,
 		"Proceed even when the repo directory is not git-anchored (G2 override)")
+	cmd.PersistentFlags().StringVar(&flagLogLevel, "log-level", "",
+		"Daemon log level: debug, info, warn, error (overrides THRUM_LOG_LEVEL and config.json)")
 
-	cmd.AddCommand(&cobra.Command{
+	startCmd := &cobra.Command{
 		Use:   "start",
 		Short: "Start the daemon in the background",
+		Long: `Start the daemon in the background and wait until it is listening.
+
+--foreground runs the daemon in this process instead, for systemd, Docker,
+and other supervisors. There is no fork, and logs go to stdout as well as
+.thrum/var/daemon.log. The PID file is written as usual, so 'thrum daemon
+status' and 'thrum daemon stop' work. SIGTERM or SIGINT shuts it down
+cleanly and the command exits 0.
+
+Examples:
+--socket listens on another Unix socket path, e.g. when .thrum sits on a
+filesystem that cannot hold sockets. --ws-port fixes the WebSocket port,
+taking precedence over THRUM_WS_PORT and config.json. The daemon records the
+socket in its PID file, so status, stop, restart, and every other command
+find it without further flags.
+
+Examples:
+  thrum daemon start
+  thrum daemon start --foreground --log-level debug
+  thrum daemon start --socket /tmp/thrum-myrepo.sock --ws-port 9100`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := cli.DaemonStart(flagRepo, flagLocal, flagForce); err != nil {
+			if flagWSPort < 0 || flagWSPort > 65535 {
+				return fmt.Errorf("--ws-port must be between 1 and 65535")
+			}
+			if foreground, _ := cmd.Flags().GetBool("foreground"); foreground {
+				if err := cli.DaemonCheckNotRunning(flagRepo); err != nil {
+					return err
+				}
+				return runDaemon(flagRepo, flagLocal, flagForce, flagLogLevel, flagSocket, flagWSPort, true)
+			}
+
+			// The forked daemon inherits our environment, so --log-level
+			// reaches it through THRUM_LOG_LEVEL (same hand-off DaemonRestart
+			// uses for THRUM_WS_PORT). Validate here so a typo fails in the
+			// foreground instead of in the daemon log.
+			if flagLogLevel != "" {
+				level, err := daemon.ResolveLogLevel(flagLogLevel, "")
+				if err != nil {
+					return err
+				}
+				_ = os.Setenv(daemon.LogLevelEnv, level) // #nosec G104 -- inherited by the daemon child
+			}
+			if err := cli.DaemonStart(flagRepo, cli.DaemonStartOptions{
+				LocalOnly:  flagLocal,
+				Force:      flagForce,
+				SocketPath: flagSocket,
+				WSPort:     flagWSPort,
+			}); err != nil {
 				return err
 			}
 
@@ -1850,7 +2805,11 @@ func daemonCmd() *cobra.Command {
 
 			return nil
 		},
-	})
+	}
+	startCmd.Flags().Bool("foreground", false, "Run in this process and log to stdout (for systemd, Docker, and other supervisors)")
+	startCmd.Flags().StringVar(&flagSocket, "socket", "", "Unix socket path (default .thrum/var/thrum.sock)")
+	startCmd.Flags().IntVar(&flagWSPort, "ws-port", 0, "WebSocket port (overrides THRUM_WS_PORT and config.json)")
+	cmd.AddCommand(startCmd)
 
 	cmd.AddCommand(&cobra.Command{
 		Use:   "stop",
@@ -1870,10 +2829,60 @@ func daemonCmd() *cobra.Command {
 		},
 	})
 
-	cmd.AddCommand(&cobra.Command{
+	statusCmd := &cobra.Command{
 		Use:   "status",
 		Short: "Show daemon status",
+		Long: `Show whether the daemon is running, its PID, uptime, version, and sync state.
+
+Exits 1 when the daemon is not running (in JSON mode the state is in the body
+and the exit code is 0).
+
+--wait polls until the daemon answers its health check, for scripts that start
+the daemon and use it straight away. It exits 0 once the daemon is healthy and
+1 if --timeout passes first, saying whether the daemon was never started or
+is running but not yet listening:
+  thrum daemon start && thrum daemon status --wait --timeout 10s
+
+--all lists every running daemon started with 'thrum daemon start', across
+repos, with its repo path, socket, and WebSocket port. Daemons are found
+through the registry in ~/.thrum/daemons/; entries for daemons that have
+exited are pruned. It always exits 0.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			wait, _ := cmd.Flags().GetBool("wait")
+			timeout, _ := cmd.Flags().GetDuration("timeout")
+			all, _ := cmd.Flags().GetBool("all")
+
+			if all {
+				result, err := cli.DaemonStatusAll()
+				if err != nil {
+					return err
+				}
+				if flagJSON {
+					return cli.EmitJSON(result)
+				}
+				fmt.Print(cli.FormatDaemonStatusAll(result))
+				return nil
+			}
+
+			if wait {
+				result, waitErr := cli.DaemonWaitHealthy(flagRepo, timeout)
+				if result == nil {
+					return waitErr
+				}
+				if flagJSON {
+					if err := cli.EmitJSON(result); err != nil {
+						return err
+					}
+				} else if !flagQuiet || waitErr != nil {
+					fmt.Print(cli.FormatDaemonStatus(result))
+				}
+				if waitErr != nil {
+					fmt.Fprintf(os.Stderr, "thrum daemon status: %v\n", waitErr)
+					os.Exit(1)
+				}
+				return nil
+			}
+
 			result, err := cli.DaemonStatus(flagRepo)
 			if err != nil {
 				return err
@@ -1896,7 +2905,12 @@ func daemonCmd() *cobra.Command {
 
 			return nil
 		},
-	})
+	}
+	statusCmd.Flags().Bool("wait", false, "Block until the daemon answers its health check")
+	statusCmd.Flags().Duration("timeout", 10*time.Second, "How long --wait polls before exiting 1")
+	statusCmd.Flags().Bool("all", false, "List running daemons across all repos")
+	statusCmd.MarkFlagsMutuallyExclusive("all", "wait")
+	cmd.AddCommand(statusCmd)
 
 	cmd.AddCommand(&cobra.Command{
 		Use:   "restart",
@@ -1918,7 +2932,106 @@ func daemonCmd() *cobra.Command {
 		},
 	})
 
-	cmd.AddCommand(daemonRunCmd(&flagLocal, &flagForce))
+	cmd.AddCommand(&cobra.Command{
+		Use:   "reload",
+		Short: "Re-read config.json without restarting the daemon",
+		Long: `Re-read .thrum/config.json and apply it to the running daemon without
+dropping client connections. Sending the daemon SIGHUP does the same.
+
+Applied live: daemon.local_only, daemon.sync_push_retries,
+daemon.sync_push_retry_delay_ms, daemon.idle_threshold,
+daemon.send_rate_limit_per_minute, daemon.send_rate_limit_burst. Turning
+local_only off re-runs the public-remote exposure check first.
+
+Reported as needing a restart: daemon.ws_port.
+
+Examples:
+  thrum daemon reload
+  thrum daemon reload --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := getClient()
+			if err != nil {
+				return fmt.Errorf("failed to connect to daemon: %w", err)
+			}
+			defer func() { _ = client.Close() }()
+
+			result, err := cli.DaemonReload(client)
+			if err != nil {
+				return err
+			}
+			if flagJSON {
+				return cli.EmitJSON(result)
+			}
+			if !flagQuiet {
+				fmt.Print(cli.FormatDaemonReload(result))
+			}
+			return nil
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "metrics",
+		Short: "Print daemon metrics in Prometheus text format",
+		Long: `Print the daemon's Prometheus metrics (messages sent, RPC calls by
+method, active sessions, registered agents, connected WebSocket clients, sync
+cycles/successes/errors, sync lag).
+
+The same text is served at http://localhost:<ws_port>/metrics for scrapers,
+to loopback clients only. The endpoint is off by default; enable it with
+"daemon": {"metrics_enabled": true} in .thrum/config.json and restart the
+daemon.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			text, err := cli.DaemonMetrics(flagRepo)
+			if err != nil {
+				return err
+			}
+			fmt.Print(text)
+			return nil
+		},
+	})
+
+	gcCmd := &cobra.Command{
+		Use:   "gc",
+		Short: "Compact the event log and vacuum the database",
+		Long: `Reclaim space in a long-lived repo. Run it with the daemon stopped.
+
+gc snapshots the database and event journal to .thrum/var/*.pre-gc.bak,
+replacing the previous snapshot. It then drops events a later delete made
+redundant: the pins, read receipts and bumps of deleted messages. The
+create, edits and delete stay, so deleted messages keep their tombstone and
+'thrum message history' keeps every version. It dedups the messages-v2/ and
+receipts/ sync files and runs VACUUM.
+
+Paired peers pull events from this daemon, so gc refuses while any peer
+has not pulled every event. Start the daemon and let them sync, or pass
+--force to drop the events anyway. A peer that misses them still gets
+message state from the sync worktree, but not the dropped history.
+
+To gc automatically, set "daemon": {"gc_interval": "24h"} in
+.thrum/config.json. The daemon then skips a pass whenever a peer is behind.
+
+Examples:
+  thrum daemon stop && thrum daemon gc && thrum daemon start
+  thrum daemon gc --force --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			force, _ := cmd.Flags().GetBool("force")
+			result, err := cli.DaemonGC(flagRepo, force)
+			if err != nil {
+				return err
+			}
+			if flagJSON {
+				return cli.EmitJSON(result)
+			}
+			if !flagQuiet {
+				fmt.Print(cli.FormatDaemonGC(result))
+			}
+			return nil
+		},
+	}
+	gcCmd.Flags().Bool("force", false, "Drop superseded events even if a peer has not pulled them yet")
+	cmd.AddCommand(gcCmd)
+
+	cmd.AddCommand(daemonRunCmd(&flagLocal, &flagForce, &flagLogLevel))
 	cmd.AddCommand(daemonLogsCmd())
 	// Old tsync/peers commands removed — replaced by top-level "thrum peer" commands
 
@@ -1963,15 +3076,23 @@ they are written. Use --since to filter by timestamp (e.g. "1h", "7d",
 	return cmd
 }
 
-func daemonRunCmd(flagLocal *bool, flagForce *bool) *cobra.Command {
-	return &cobra.Command{
+func daemonRunCmd(flagLocal *bool, flagForce *bool, flagLogLevel *string) *cobra.Command {
+	var (
+		flagSocket string
+		flagWSPort int
+	)
+	cmd := &cobra.Command{
 		Use:    "run",
 		Short:  "Run the daemon in the foreground (internal use)",
 		Hidden: true, // Hidden from help - used internally by daemon start
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runDaemon(flagRepo, *flagLocal, *flagForce)
+			return runDaemon(flagRepo, *flagLocal, *flagForce, *flagLogLevel, flagSocket, flagWSPort, false)
 		},
 	}
+	// Set by daemon start --socket / --ws-port.
+	cmd.Flags().StringVar(&flagSocket, "socket", "", "Unix socket path")
+	cmd.Flags().IntVar(&flagWSPort, "ws-port", 0, "WebSocket port")
+	return cmd
 }
 
 func peerCmd() *cobra.Command {
@@ -2278,10 +3399,15 @@ stored secrets in peers.json to re-handshake without minting a new token.`,
 		},
 	})
 
-	// thrum peer status — detailed health per peer
+	// thrum peer rename <old> <new> — set a peer's display name
 	cmd.AddCommand(&cobra.Command{
-		Use:   "status",
-		Short: "Show detailed sync status for all peers",
+		Use:   "rename <name> <new-name>",
+		Short: "Give a paired peer a friendlier name",
+		Long: `Changes the name a peer is shown and addressed by in peer list, status,
+remove, and configure. The peer's daemon ID, address, and token are unchanged,
+so sync keeps working. Names must be unique among peers; the current name may
+also be given as the peer's daemon ID.`,
+		Args: cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			client, err := getClient()
 			if err != nil {
@@ -2289,25 +3415,27 @@ stored secrets in peers.json to re-handshake without minting a new token.`,
 			}
 			defer func() { _ = client.Close() }()
 
-			peers, err := cli.PeerStatus(client)
+			result, err := cli.PeerRename(client, args[0], args[1])
 			if err != nil {
 				return err
 			}
 
 			if flagJSON {
-				return cli.EmitJSON(peers)
+				return cli.EmitJSON(result)
 			}
-			fmt.Print(cli.FormatPeerStatus(peers))
+			fmt.Printf("Renamed peer %q to %q (daemon %s).\n", args[0], result.NewName, result.DaemonID)
 			return nil
 		},
 	})
 
-	// thrum peer configure <peer-name> <action> <agent-name> — manage proxy agents
+	// thrum peer sync-now <name> — force a pull from one peer
 	cmd.AddCommand(&cobra.Command{
-		Use:   "configure <peer-name> <action> <agent-name>",
-		Short: "Configure proxy agents for a peer",
-		Long:  "Add or remove proxy agents for a peer. Actions: add-agent, remove-agent",
-		Args:  cobra.ExactArgs(3),
+		Use:   "sync-now <name>",
+		Short: "Pull new events from one peer right now",
+		Long: `Pulls events from a single paired peer immediately instead of waiting for
+a sync.notify or the periodic sync, and reports how many were pulled. Useful
+when debugging one peer; 'thrum sync force' runs git sync instead.`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			client, err := getClient()
 			if err != nil {
@@ -2315,32 +3443,96 @@ stored secrets in peers.json to re-handshake without minting a new token.`,
 			}
 			defer func() { _ = client.Close() }()
 
-			peerName, action, agentName := args[0], args[1], args[2]
-			var result any
-			if err := client.Call("peer.configure", map[string]any{
-				"peer_name":  peerName,
-				"action":     action,
-				"agent_name": agentName,
-			}, &result); err != nil {
+			result, err := cli.PeerSyncNow(client, args[0])
+			if err != nil {
 				return err
 			}
-			fmt.Printf("✓ %s: %s %s\n", peerName, action, agentName)
+
+			if flagJSON {
+				return cli.EmitJSON(result)
+			}
+			fmt.Print(cli.FormatPeerSyncNow(args[0], result))
 			return nil
 		},
 	})
 
-	return cmd
-}
-
-func monitorCmd() *cobra.Command {
-	cmd := &cobra.Command{
-		Use:   "monitor",
-		Short: "Manage long-running monitor jobs",
-		Long: `Monitor runs a command, filters its stdout/stderr through a regex, and
-delivers matching lines as thrum messages to the specified target.
+	// thrum peer status [name] — detailed health per peer
+	var statusProbe bool
+	statusCmd := &cobra.Command{
+		Use:   "status [name]",
+		Short: "Show detailed sync status for all peers, or one",
+		Long: `Shows each paired peer's address, pairing time, and last sync.
+
+--probe also calls every peer now (sync.peer_info over its sync transport)
+and reports whether it answered, with the round-trip time. Peers are probed
+concurrently and each gives up after a few seconds, so one dead peer does
+not hold up the others. Use it to debug pairing and connectivity.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := getClient()
+			if err != nil {
+				return fmt.Errorf("failed to connect to daemon: %w", err)
+			}
+			defer func() { _ = client.Close() }()
 
-Examples:
-  thrum monitor add --name errors --match "ERROR" --to @team -- tail -F /tmp/app.log
+			var name string
+			if len(args) == 1 {
+				name = args[0]
+			}
+			peers, err := cli.PeerStatus(client, name, statusProbe)
+			if err != nil {
+				return err
+			}
+
+			if flagJSON {
+				return cli.EmitJSON(peers)
+			}
+			fmt.Print(cli.FormatPeerStatus(peers))
+			return nil
+		},
+	}
+	statusCmd.Flags().BoolVar(&statusProbe, "probe", false, "Call each peer now and report reachability and round-trip time")
+	cmd.AddCommand(statusCmd)
+
+	// thrum peer configure <peer-name> <action> <agent-name> — manage proxy agents
+	cmd.AddCommand(&cobra.Command{
+		Use:   "configure <peer-name> <action> <agent-name>",
+		Short: "Configure proxy agents for a peer",
+		Long:  "Add or remove proxy agents for a peer. Actions: add-agent, remove-agent",
+		Args:  cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := getClient()
+			if err != nil {
+				return fmt.Errorf("failed to connect to daemon: %w", err)
+			}
+			defer func() { _ = client.Close() }()
+
+			peerName, action, agentName := args[0], args[1], args[2]
+			var result any
+			if err := client.Call("peer.configure", map[string]any{
+				"peer_name":  peerName,
+				"action":     action,
+				"agent_name": agentName,
+			}, &result); err != nil {
+				return err
+			}
+			fmt.Printf("✓ %s: %s %s\n", peerName, action, agentName)
+			return nil
+		},
+	})
+
+	return cmd
+}
+
+func monitorCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "monitor",
+		Short: "Manage long-running monitor jobs",
+		Long: `Monitor runs a command, filters its stdout/stderr through a regex, and
+delivers matching lines as thrum messages to the specified target.
+
+Examples:
+  thrum monitor add --name errors --match "ERROR" --to @team -- tail -F /tmp/app.log
   thrum monitor list
   thrum monitor show <name|id>
   thrum monitor stop <name|id>
@@ -2568,12 +3760,32 @@ The agent identity is determined from:
 1. --name flag (highest priority)
 2. THRUM_NAME env var (default when --name is not provided)
 3. Environment variables (THRUM_ROLE, THRUM_MODULE for role/module)
-4. Identity file in .thrum/identities/ directory`,
+4. Identity file in .thrum/identities/ directory
+
+--from-git infers what --role and --module leave out. The role comes from
+THRUM_ROLE, then agents.default_role in .thrum/config.json. The module is the
+directory you run from: the first directory below the worktree root, or the
+one below a container such as services/ or packages/ (agents.module_dirs).
+From the worktree root or directly in a container the module is ambiguous:
+on a terminal you are asked to pick one, otherwise register fails and lists
+the candidates.
+
+Examples:
+  cd services/billing && thrum agent register --from-git --role implementer
+  thrum config set agents.default_role implementer
+  cd packages/ui && thrum agent register --from-git`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			force, _ := cmd.Flags().GetBool("force")
 			reRegister, _ := cmd.Flags().GetBool("re-register")
 			display, _ := cmd.Flags().GetString("display")
 			name, _ := cmd.Flags().GetString("name")
+			capabilities, _ := cmd.Flags().GetStringSlice("capability")
+
+			if fromGit, _ := cmd.Flags().GetBool("from-git"); fromGit {
+				if err := resolveGitRegistration(); err != nil {
+					return err
+				}
+			}
 
 			// Use flagRole and flagModule from global flags
 			if flagRole == "" || flagModule == "" {
@@ -2599,6 +3811,8 @@ The agent identity is determined from:
 				Display:    display,
 				Force:      force,
 				ReRegister: reRegister,
+
+				Capabilities: capabilities,
 			}
 
 			client, err := getClient()
@@ -2678,19 +3892,55 @@ The agent identity is determined from:
 	registerCmd.Flags().String("name", "", "Human-readable agent name (optional, defaults to role_hash)")
 	registerCmd.Flags().Bool("force", false, "Force registration (override existing)")
 	registerCmd.Flags().Bool("re-register", false, "Re-register same agent")
+	registerCmd.Flags().Bool("from-git", false, "Infer the module from the current directory and the role from THRUM_ROLE or agents.default_role")
 	registerCmd.Flags().String("display", "", "Display name for the agent")
+	registerCmd.Flags().StringSlice("capability", nil, "Capability this agent advertises, e.g. go or security (repeatable; replaces the current set)")
 	cmd.AddCommand(registerCmd)
 
 	listCmd := &cobra.Command{
 		Use:   "list",
 		Short: "List registered agents",
-		Long: `List all registered agents, optionally filtered by role or module.
+		Long: `List all registered agents, optionally filtered by role, module, or
+capability.
+
+Use --context to show work context (branch, commits, intent) for each agent.
 
-Use --context to show work context (branch, commits, intent) for each agent.`,
+Use --template to print one line per agent from a Go text/template over the
+agent fields, e.g. '{{.AgentID}} {{.Role}} {{.LastSeenAt}}'. Unknown fields
+render empty.
+
+Use --sort to order the list: name (A to Z), role, last-seen (least recently
+seen first, to spot stragglers) or unread (most unread first). --reverse
+flips the order. Agents without a work context are always listed last.
+
+Examples:
+  thrum agent list --sort last-seen
+  thrum agent list --sort unread --reverse`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			filterRole, _ := cmd.Flags().GetString("role")
 			filterModule, _ := cmd.Flags().GetString("module")
+			filterCapability, _ := cmd.Flags().GetString("capability")
 			showContext, _ := cmd.Flags().GetBool("context")
+			templateText, _ := cmd.Flags().GetString("template")
+			sortKey, _ := cmd.Flags().GetString("sort")
+			reverse, _ := cmd.Flags().GetBool("reverse")
+
+			itemTmpl, err := cli.CompileItemTemplate(templateText)
+			if err != nil {
+				return err
+			}
+			if itemTmpl != nil && (flagJSON || showContext) {
+				return fmt.Errorf("--template cannot be combined with --json or --context")
+			}
+			if err := cli.ValidateAgentSortKey(sortKey); err != nil {
+				return err
+			}
+			if reverse && sortKey == "" {
+				return fmt.Errorf("--reverse requires --sort")
+			}
+			if sortKey != "" && showContext {
+				return fmt.Errorf("--sort cannot be combined with --context")
+			}
 
 			if showContext {
 				// Show work context table instead of agent list
@@ -2713,8 +3963,9 @@ Use --context to show work context (branch, commits, intent) for each agent.`,
 			}
 
 			opts := cli.AgentListOptions{
-				Role:   filterRole,
-				Module: filterModule,
+				Role:       filterRole,
+				Module:     filterModule,
+				Capability: filterCapability,
 			}
 
 			client, err := getClient()
@@ -2727,6 +3978,9 @@ Use --context to show work context (branch, commits, intent) for each agent.`,
 			if err != nil {
 				return err
 			}
+			if itemTmpl != nil && sortKey == "" {
+				return cli.RenderItems(os.Stdout, itemTmpl, result.Agents)
+			}
 
 			// Also fetch work contexts for enhanced display
 			contexts, err := cli.AgentListContext(client, "", "", "")
@@ -2735,6 +3989,19 @@ Use --context to show work context (branch, commits, intent) for each agent.`,
 				contexts = nil
 			}
 
+			if sortKey != "" {
+				var unread map[string]int
+				if sortKey == "unread" {
+					if unread, err = cli.AgentUnreadCounts(client); err != nil {
+						return err
+					}
+				}
+				cli.SortAgentList(result, contexts, sortKey, reverse, unread)
+			}
+			if itemTmpl != nil {
+				return cli.RenderItems(os.Stdout, itemTmpl, result.Agents)
+			}
+
 			if flagJSON {
 				var body any = result
 				if contexts != nil {
@@ -2752,9 +4019,78 @@ Use --context to show work context (branch, commits, intent) for each agent.`,
 	}
 	listCmd.Flags().String("role", "", "Filter by role")
 	listCmd.Flags().String("module", "", "Filter by module")
+	listCmd.Flags().String("capability", "", "Filter by advertised capability")
 	listCmd.Flags().Bool("context", false, "Show work context (branch, commits, intent)")
+	listCmd.Flags().String("template", "", "Render each agent with a Go text/template, e.g. '{{.AgentID}} {{.Role}}'")
+	listCmd.Flags().String("sort", "", "Sort by name, role, last-seen or unread")
+	listCmd.Flags().Bool("reverse", false, "Reverse the --sort order")
 	cmd.AddCommand(listCmd)
 
+	agentContextCmd := &cobra.Command{
+		Use:   "context [NAME]",
+		Short: "Show an agent's work context",
+		Long: `Show the work context (branch, worktree, intent, task, unmerged commits
+and changed files) of an agent, one block per active session. Defaults to the
+current agent.
+
+Use --watch to keep the view open while pairing: the screen is redrawn each
+time the agent's heartbeat, intent or task changes, and every 10 seconds.
+Press Ctrl-C to exit.`,
+		Example: `  thrum agent context
+  thrum agent context @furiosa --watch`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			watch, _ := cmd.Flags().GetBool("watch")
+
+			var agentID string
+			if len(args) == 1 {
+				agentID = strings.TrimPrefix(args[0], "@")
+			} else {
+				id, err := resolveLocalAgentID()
+				if err != nil {
+					return fmt.Errorf("failed to resolve agent identity: %w\n  Pass an agent name: thrum agent context NAME", err)
+				}
+				agentID = id
+			}
+
+			if watch {
+				if flagJSON {
+					return fmt.Errorf("--json cannot be combined with --watch")
+				}
+				socketPath := os.Getenv("THRUM_SOCKET")
+				if socketPath == "" {
+					socketPath = cli.DefaultSocketPath(flagRepo)
+				}
+				ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+				defer stop()
+				return cli.AgentContextWatch(ctx, os.Stdout, cli.AgentContextWatchOptions{
+					AgentID:    agentID,
+					SocketPath: socketPath,
+					RepoPath:   flagRepo,
+					Quiet:      flagQuiet,
+				})
+			}
+
+			client, err := getClient()
+			if err != nil {
+				return fmt.Errorf("failed to connect to daemon: %w", err)
+			}
+			defer func() { _ = client.Close() }()
+
+			result, err := cli.AgentListContext(client, agentID, "", "")
+			if err != nil {
+				return err
+			}
+			if flagJSON {
+				return cli.EmitJSON(result)
+			}
+			fmt.Print(cli.FormatAgentContext(agentID, result.Contexts))
+			return nil
+		},
+	}
+	agentContextCmd.Flags().Bool("watch", false, "Redraw on every context change until interrupted")
+	cmd.AddCommand(agentContextCmd)
+
 	agentWhoamiCmd := &cobra.Command{
 		Use:   "whoami",
 		Short: "Show current agent identity",
@@ -2767,8 +4103,27 @@ Identity is resolved from:
 		RunE: runWhoami,
 	}
 	agentWhoamiCmd.Flags().String("field", "", "Print a single field's value (e.g. agent_id, tmux_alive) and exit")
+	agentWhoamiCmd.Flags().Bool("all", false, "List every identity in the repo (name, role, module, worktree)")
+	agentWhoamiCmd.Flags().Bool("resolve", false, "Print only the resolved agent ID (same as 'thrum agent id')")
+	agentWhoamiCmd.Flags().Bool("check", false, "Check the identity file against the daemon's record; exit 1 on mismatch")
 	cmd.AddCommand(agentWhoamiCmd)
 
+	cmd.AddCommand(&cobra.Command{
+		Use:   "id",
+		Short: "Print the resolved agent ID",
+		Long: `Print the agent ID this shell resolves to — the ID 'thrum send' and the
+other commands act as — and nothing else. Honors --role, --module and
+THRUM_NAME the same way send does, and needs no running daemon. Exits
+non-zero with a one-line error when no identity can be resolved.
+
+Examples:
+  AGENT=$(thrum agent id)
+  THRUM_NAME=reviewer thrum agent id
+  thrum agent whoami --resolve`,
+		Args: cobra.NoArgs,
+		RunE: runAgentID,
+	})
+
 	deleteCmd := &cobra.Command{
 		Use:   "delete <name>",
 		Short: "Delete an agent",
@@ -2820,140 +4175,539 @@ Examples:
 	deleteCmd.Flags().Bool("force", false, "Skip confirmation prompt")
 	cmd.AddCommand(deleteCmd)
 
-	cleanupCmd := &cobra.Command{
-		Use:   "cleanup",
-		Short: "Clean up orphaned agents",
-		Long: `Detect and remove orphaned agents whose worktrees or branches no longer exist.
+	renameCmd := &cobra.Command{
+		Use:   "rename OLD NEW",
+		Short: "Rename an agent",
+		Long: `Rename agent OLD to NEW. OLD may also be one of the agent's aliases; a
+leading @ on either argument is ignored.
 
-This command scans all registered agents and identifies orphans based on:
-- Missing worktree (deleted from filesystem)
-- Missing branch (deleted from git)
-- Stale agents (not seen in a long time)
+The agent keeps its history under the new name: sessions (including an
+active one), sent and received messages, read state, reactions, aliases, and
+group memberships all move over, and its identity and context files in
+.thrum/ are renamed. NEW must be a valid agent name not already used by
+another agent, alias, role, or group.
 
-For each orphan found, you'll be prompted to confirm deletion.
+A running agent that exports THRUM_NAME=OLD should be restarted (or have
+THRUM_NAME updated) so it picks up the new identity file.
 
 Examples:
-  thrum agent cleanup                  # Interactive cleanup
-  thrum agent cleanup --dry-run        # List orphans without deleting
-  thrum agent cleanup --force          # Delete all orphans without prompting`,
+  thrum agent rename coordinator_1B9K coordinator
+  thrum agent rename @coord lead`,
+		Args: cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			dryRun, _ := cmd.Flags().GetBool("dry-run")
-			force, _ := cmd.Flags().GetBool("force")
-			threshold, _ := cmd.Flags().GetInt("threshold")
-
-			if dryRun && force {
-				return fmt.Errorf("--dry-run and --force are mutually exclusive")
-			}
-
 			client, err := getClient()
 			if err != nil {
 				return fmt.Errorf("failed to connect to daemon: %w", err)
 			}
 			defer func() { _ = client.Close() }()
 
-			result, err := cli.AgentCleanup(client, cli.AgentCleanupOptions{
-				DryRun:    dryRun,
-				Force:     force,
-				Threshold: threshold,
-			})
+			result, err := cli.AgentRename(client,
+				strings.TrimPrefix(args[0], "@"), strings.TrimPrefix(args[1], "@"))
 			if err != nil {
 				return err
 			}
 
-			// Handle JSON output
 			if flagJSON {
 				return cli.EmitJSON(result)
 			}
+			if !flagQuiet {
+				fmt.Print(cli.FormatAgentRename(result))
+			}
+			return nil
+		},
+	}
+	cmd.AddCommand(renameCmd)
 
-			// Display results
-			fmt.Print(cli.FormatAgentCleanup(result))
+	transferCmd := &cobra.Command{
+		Use:   "transfer FROM TO",
+		Short: "Hand off an agent's work context to another agent",
+		Long: `Hand FROM's work over to TO in one step:
 
-			// If interactive mode (not force, not dry-run) and orphans found, prompt for deletion
-			if !force && !dryRun && len(result.Orphans) > 0 {
-				fmt.Println("\nDelete these orphaned agents? [y/N]")
+- the intent, task and scopes of FROM's active session are copied onto TO's
+  active session (one is started if TO has none);
+- FROM's saved context (thrum context save) replaces TO's;
+- a handoff message is sent to TO, mentioning FROM, with what was copied.
+
+FROM must have an active session. Its session stays open unless
+--end-source is given, which ends it with reason "handoff". You are asked to
+confirm unless --force is given. A leading @ on either argument is ignored.
+
+Examples:
+  thrum agent transfer alice bob
+  thrum agent transfer @alice @bob --end-source --force`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			from := strings.TrimPrefix(args[0], "@")
+			to := strings.TrimPrefix(args[1], "@")
+			endSource, _ := cmd.Flags().GetBool("end-source")
+			force, _ := cmd.Flags().GetBool("force")
+
+			if !force {
+				prompt := fmt.Sprintf("Transfer intent, task, scopes and saved context from '%s' to '%s'", from, to)
+				if endSource {
+					prompt += fmt.Sprintf(" and end %s's session", from)
+				}
+				fmt.Print(prompt + "? [y/N] ")
 				var response string
 				_, _ = fmt.Scanln(&response)
-				if response == "y" || response == "Y" {
-					// Delete each orphan
-					deleted := 0
-					for _, orphan := range result.Orphans {
-						_, err := cli.AgentDelete(client, cli.AgentDeleteOptions{Name: orphan.AgentID})
-						if err != nil {
-							fmt.Printf("✗ Failed to delete %s: %v\n", orphan.AgentID, err)
-						} else {
-							fmt.Printf("✓ Deleted %s\n", orphan.AgentID)
-							deleted++
-						}
-					}
-					fmt.Printf("\n✓ Deleted %d orphaned agent(s)\n", deleted)
-				} else {
-					fmt.Println("Cleanup canceled.")
+				if response != "y" && response != "Y" {
+					fmt.Println("Transfer canceled.")
+					return nil
 				}
 			}
 
-			return nil
-		},
-	}
-	cleanupCmd.Flags().Bool("dry-run", false, "List orphans without deleting")
-	cleanupCmd.Flags().Bool("force", false, "Delete all orphans without prompting")
-	cleanupCmd.Flags().Int("threshold", 30, "Days since last seen to consider agent stale")
-	cmd.AddCommand(cleanupCmd)
+			client, err := getClient()
+			if err != nil {
+				return fmt.Errorf("failed to connect to daemon: %w", err)
+			}
+			defer func() { _ = client.Close() }()
 
-	// Agent-centric aliases for session commands
-	// These share RunE functions with session commands (no code duplication)
+			result, err := cli.SessionTransfer(client, cli.SessionTransferRequest{
+				FromAgentID: from,
+				ToAgentID:   to,
+				EndSource:   endSource,
+			})
+			if err != nil {
+				return err
+			}
 
-	agentStartCmd := &cobra.Command{
-		Use:   "start",
-		Short: "Start a new session (alias for 'thrum session start')",
-		Long: `Start a new work session for the current agent.
+			absRepo, _ := filepath.Abs(flagRepo)
+			includePreamble := false
+			var saved rpc.ContextShowResponse
+			if err := client.Call("context.show", rpc.ContextShowRequest{
+				AgentName:       from,
+				IncludePreamble: &includePreamble,
+				RepoPath:        absRepo,
+			}, &saved); err != nil {
+				return fmt.Errorf("session transferred, but reading %s's context failed: %w", from, err)
+			}
+			if saved.HasContext {
+				var resp rpc.ContextSaveResponse
+				if err := client.Call("context.save", rpc.ContextSaveRequest{
+					AgentName: to,
+					Content:   saved.Content,
+					RepoPath:  absRepo,
+				}, &resp); err != nil {
+					return fmt.Errorf("session transferred, but copying the context to %s failed: %w", to, err)
+				}
+				result.ContextBytes = len(saved.Content)
+			}
 
-This is an alias for 'thrum session start'.
-The agent must be registered first (use 'thrum quickstart').`,
-		RunE: sessionStartRunE,
+			// The handoff message comes from whoever ran the transfer.
+			callerID, _ := resolveLocalAgentID()
+			sent, err := cli.Send(client, cli.SendOptions{
+				Content:       cli.HandoffMessage(result),
+				To:            "@" + to,
+				Mentions:      []string{"@" + from},
+				Tags:          []string{"handoff"},
+				CallerAgentID: callerID,
+			})
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "  warning: work transferred, but the handoff message was not sent: %v\n", err)
+			} else {
+				result.HandoffMessageID = sent.MessageID
+			}
+
+			if flagJSON {
+				return cli.EmitJSON(result)
+			}
+			if !flagQuiet {
+				fmt.Print(cli.FormatSessionTransfer(result))
+			}
+			return nil
+		},
 	}
-	cmd.AddCommand(agentStartCmd)
+	transferCmd.Flags().Bool("end-source", false, "End FROM's session with reason \"handoff\" after the transfer")
+	transferCmd.Flags().Bool("force", false, "Skip confirmation prompt")
+	cmd.AddCommand(transferCmd)
 
-	agentEndCmd := &cobra.Command{
-		Use:   "end",
-		Short: "End current session (alias for 'thrum session end')",
-		Long: `End the current active session.
+	aliasCmd := &cobra.Command{
+		Use:   "alias",
+		Short: "Manage agent nicknames",
+		Long: `Give an agent a short nickname. Aliases are accepted anywhere an agent is
+resolved (send --to, --mention, ping, group members) and are checked before
+agent names and roles. They show up in 'thrum agent list'.
 
-This is an alias for 'thrum session end'.`,
-		RunE: sessionEndRunE,
+An alias must not match an existing agent name, role, or group name, and
+each alias belongs to one agent. Deleting an agent removes its aliases.`,
 	}
-	agentEndCmd.Flags().String("reason", "normal", "End reason (normal|crash)")
-	agentEndCmd.Flags().String("session-id", "", "Session ID to end (defaults to current session)")
-	cmd.AddCommand(agentEndCmd)
 
-	agentSetIntentCmd := &cobra.Command{
-		Use:   "set-intent TEXT",
-		Short: "Set work intent (alias for 'thrum session set-intent')",
-		Long: `Set the work intent for the current session.
-
-This is an alias for 'thrum session set-intent'.
-Pass an empty string to clear the intent.
+	aliasSetCmd := &cobra.Command{
+		Use:   "set NAME ALIAS",
+		Short: "Assign an alias to an agent",
+		Long: `Assign ALIAS to the agent NAME. A leading @ on either argument is ignored.
 
 Examples:
-  thrum agent set-intent "Fixing memory leak in connection pool"
-  thrum agent set-intent ""   # clear intent`,
-		Args: cobra.ExactArgs(1),
-		RunE: sessionSetIntentRunE,
-	}
-	cmd.AddCommand(agentSetIntentCmd)
-
-	agentHeartbeatCmd := &cobra.Command{
-		Use:   "heartbeat",
-		Short: "Send heartbeat (alias for 'thrum session heartbeat')",
-		Long: `Send a heartbeat for the current session.
+  thrum agent alias set coordinator_1B9K coord
+  thrum send --to @coord "status?"
+  thrum ping @coord`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := getClient()
+			if err != nil {
+				return fmt.Errorf("failed to connect to daemon: %w", err)
+			}
+			defer func() { _ = client.Close() }()
+
+			result, err := cli.AgentAliasSet(client,
+				strings.TrimPrefix(args[0], "@"), strings.TrimPrefix(args[1], "@"))
+			if err != nil {
+				return err
+			}
+
+			if flagJSON {
+				return cli.EmitJSON(result)
+			}
+			if !flagQuiet {
+				fmt.Print(cli.FormatAgentAlias(result))
+			}
+			return nil
+		},
+	}
+	aliasCmd.AddCommand(aliasSetCmd)
+
+	aliasRemoveCmd := &cobra.Command{
+		Use:   "remove ALIAS",
+		Short: "Remove an alias",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := getClient()
+			if err != nil {
+				return fmt.Errorf("failed to connect to daemon: %w", err)
+			}
+			defer func() { _ = client.Close() }()
+
+			result, err := cli.AgentAliasRemove(client, strings.TrimPrefix(args[0], "@"))
+			if err != nil {
+				return err
+			}
+
+			if flagJSON {
+				return cli.EmitJSON(result)
+			}
+			if !flagQuiet {
+				fmt.Print(cli.FormatAgentAlias(result))
+			}
+			return nil
+		},
+	}
+	aliasCmd.AddCommand(aliasRemoveCmd)
+	cmd.AddCommand(aliasCmd)
+
+	setCapabilitiesCmd := &cobra.Command{
+		Use:   "set-capabilities NAME [CAPABILITY...]",
+		Short: "Replace an agent's capability tags",
+		Long: `Replace the capability tags agent NAME advertises, without re-registering
+it. The new set replaces the old one; pass --clear with no capabilities to
+remove them all. Tags are lowercased and may contain letters, digits, '.',
+'_', '+', and '-'. 'thrum who-can' lists agents by capability.
+
+Examples:
+  thrum agent set-capabilities impl_api go sql
+  thrum agent set-capabilities @reviewer security frontend
+  thrum agent set-capabilities impl_api --clear`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clearAll, _ := cmd.Flags().GetBool("clear")
+			caps := args[1:]
+			if clearAll && len(caps) > 0 {
+				return fmt.Errorf("--clear cannot be combined with capabilities")
+			}
+			if !clearAll && len(caps) == 0 {
+				return fmt.Errorf("no capabilities given: pass one or more, or --clear to remove them all")
+			}
+
+			client, err := getClient()
+			if err != nil {
+				return fmt.Errorf("failed to connect to daemon: %w", err)
+			}
+			defer func() { _ = client.Close() }()
+
+			result, err := cli.AgentCapabilitiesSet(client, strings.TrimPrefix(args[0], "@"), caps)
+			if err != nil {
+				return err
+			}
+
+			if flagJSON {
+				return cli.EmitJSON(result)
+			}
+			if !flagQuiet {
+				fmt.Print(cli.FormatAgentCapabilities(result))
+			}
+			return nil
+		},
+	}
+	setCapabilitiesCmd.Flags().Bool("clear", false, "Remove all capabilities")
+	cmd.AddCommand(setCapabilitiesCmd)
+
+	muteCmd := &cobra.Command{
+		Use:     "mute",
+		Aliases: []string{"pause"},
+		Short:   "Stop notification pushes for a while",
+		Long: `Mute this agent (or --agent NAME): messages still land in the inbox, but
+subscriptions don't push them and 'thrum wait' isn't woken by them. Without
+--duration the mute lasts until 'thrum agent unmute'; with it, the mute
+expires on its own. Muting again replaces the previous mute.
+
+--allow-mentions lets messages that @mention the agent by name through.
+Role, group, and @everyone mentions stay muted.
+
+Examples:
+  thrum agent mute --duration 30m
+  thrum agent mute --duration 2h --allow-mentions
+  thrum agent mute --agent impl_api`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target, _ := cmd.Flags().GetString("agent")
+			duration, _ := cmd.Flags().GetDuration("duration")
+			allowMentions, _ := cmd.Flags().GetBool("allow-mentions")
+			if duration < 0 {
+				return fmt.Errorf("--duration must be positive")
+			}
+			if target == "" {
+				id, err := resolveLocalAgentID()
+				if err != nil {
+					return fmt.Errorf("failed to resolve agent identity: %w", err)
+				}
+				target = id
+			}
+
+			client, err := getClient()
+			if err != nil {
+				return fmt.Errorf("failed to connect to daemon: %w", err)
+			}
+			defer func() { _ = client.Close() }()
+
+			result, err := cli.AgentMute(client, strings.TrimPrefix(target, "@"), duration, allowMentions)
+			if err != nil {
+				return err
+			}
+
+			if flagJSON {
+				return cli.EmitJSON(result)
+			}
+			if !flagQuiet {
+				fmt.Print(cli.FormatAgentMute(result))
+			}
+			return nil
+		},
+	}
+	muteCmd.Flags().String("agent", "", "Agent to mute (default: this agent)")
+	muteCmd.Flags().Duration("duration", 0, "Unmute automatically after this long, e.g. 30m or 2h")
+	muteCmd.Flags().Bool("allow-mentions", false, "Still push messages that @mention the agent by name")
+	cmd.AddCommand(muteCmd)
+
+	unmuteCmd := &cobra.Command{
+		Use:     "unmute",
+		Aliases: []string{"resume"},
+		Short:   "Resume notification pushes",
+		Long: `Lift the mute set by 'thrum agent mute' on this agent (or --agent NAME).
+Messages that arrived while muted stay in the inbox.
+
+Examples:
+  thrum agent unmute
+  thrum agent unmute --agent impl_api`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target, _ := cmd.Flags().GetString("agent")
+			if target == "" {
+				id, err := resolveLocalAgentID()
+				if err != nil {
+					return fmt.Errorf("failed to resolve agent identity: %w", err)
+				}
+				target = id
+			}
+
+			client, err := getClient()
+			if err != nil {
+				return fmt.Errorf("failed to connect to daemon: %w", err)
+			}
+			defer func() { _ = client.Close() }()
+
+			result, err := cli.AgentUnmute(client, strings.TrimPrefix(target, "@"))
+			if err != nil {
+				return err
+			}
+
+			if flagJSON {
+				return cli.EmitJSON(result)
+			}
+			if !flagQuiet {
+				fmt.Print(cli.FormatAgentMute(result))
+			}
+			return nil
+		},
+	}
+	unmuteCmd.Flags().String("agent", "", "Agent to unmute (default: this agent)")
+	cmd.AddCommand(unmuteCmd)
+
+	cleanupCmd := &cobra.Command{
+		Use:   "cleanup",
+		Short: "Clean up orphaned agents",
+		Long: `Detect and remove orphaned agents whose worktrees or branches no longer exist.
+
+This command scans all registered agents and identifies orphans based on:
+- Missing worktree (deleted from filesystem)
+- Missing branch (deleted from git)
+- Stale agents (not seen in a long time)
+
+For each orphan found, you'll be prompted to confirm deletion.
+
+With --stale-sessions, open sessions whose last heartbeat is older than the
+daemon's idle threshold (daemon.idle_threshold) are listed too and can be
+ended with reason "stale". Agents that are still heartbeating are left alone.
+
+Examples:
+  thrum agent cleanup                  # Interactive cleanup
+  thrum agent cleanup --dry-run        # List orphans without deleting
+  thrum agent cleanup --force          # Delete all orphans without prompting
+  thrum agent cleanup --stale-sessions --dry-run  # Also list silent sessions`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			force, _ := cmd.Flags().GetBool("force")
+			threshold, _ := cmd.Flags().GetInt("threshold")
+			staleSessions, _ := cmd.Flags().GetBool("stale-sessions")
+
+			if dryRun && force {
+				return fmt.Errorf("--dry-run and --force are mutually exclusive")
+			}
+
+			client, err := getClient()
+			if err != nil {
+				return fmt.Errorf("failed to connect to daemon: %w", err)
+			}
+			defer func() { _ = client.Close() }()
+
+			result, err := cli.AgentCleanup(client, cli.AgentCleanupOptions{
+				DryRun:        dryRun,
+				Force:         force,
+				Threshold:     threshold,
+				StaleSessions: staleSessions,
+			})
+			if err != nil {
+				return err
+			}
+
+			// Handle JSON output
+			if flagJSON {
+				return cli.EmitJSON(result)
+			}
+
+			// Display results
+			fmt.Print(cli.FormatAgentCleanup(result))
+
+			// If interactive mode (not force, not dry-run) and orphans found, prompt for deletion
+			if !force && !dryRun && len(result.Orphans) > 0 {
+				fmt.Println("\nDelete these orphaned agents? [y/N]")
+				var response string
+				_, _ = fmt.Scanln(&response)
+				if response == "y" || response == "Y" {
+					// Delete each orphan
+					deleted := 0
+					for _, orphan := range result.Orphans {
+						_, err := cli.AgentDelete(client, cli.AgentDeleteOptions{Name: orphan.AgentID})
+						if err != nil {
+							fmt.Printf("✗ Failed to delete %s: %v\n", orphan.AgentID, err)
+						} else {
+							fmt.Printf("✓ Deleted %s\n", orphan.AgentID)
+							deleted++
+						}
+					}
+					fmt.Printf("\n✓ Deleted %d orphaned agent(s)\n", deleted)
+				} else {
+					fmt.Println("Cleanup canceled.")
+				}
+			}
+
+			if !force && !dryRun && len(result.StaleSessions) > 0 {
+				fmt.Println("\nEnd these stale sessions? [y/N]")
+				var response string
+				_, _ = fmt.Scanln(&response)
+				if response == "y" || response == "Y" {
+					ended := 0
+					for _, sess := range result.StaleSessions {
+						_, err := cli.SessionEnd(client, cli.SessionEndOptions{SessionID: sess.SessionID, Reason: "stale"})
+						if err != nil {
+							fmt.Printf("✗ Failed to end %s: %v\n", sess.SessionID, err)
+						} else {
+							fmt.Printf("✓ Ended %s (@%s)\n", sess.SessionID, sess.AgentID)
+							ended++
+						}
+					}
+					fmt.Printf("\n✓ Ended %d stale session(s)\n", ended)
+				} else {
+					fmt.Println("Stale sessions left open.")
+				}
+			}
+
+			return nil
+		},
+	}
+	cleanupCmd.Flags().Bool("dry-run", false, "List orphans without deleting")
+	cleanupCmd.Flags().Bool("force", false, "Delete all orphans without prompting")
+	cleanupCmd.Flags().Int("threshold", 30, "Days since last seen to consider agent stale")
+	cleanupCmd.Flags().Bool("stale-sessions", false, "Also end sessions with no heartbeat within daemon.idle_threshold")
+	cmd.AddCommand(cleanupCmd)
+
+	// Agent-centric aliases for session commands
+	// These share RunE functions with session commands (no code duplication)
+
+	agentStartCmd := &cobra.Command{
+		Use:   "start",
+		Short: "Start a new session (alias for 'thrum session start')",
+		Long: `Start a new work session for the current agent.
+
+This is an alias for 'thrum session start'.
+The agent must be registered first (use 'thrum quickstart').`,
+		RunE: sessionStartRunE,
+	}
+	addDeclaredFilesFlag(agentStartCmd)
+	cmd.AddCommand(agentStartCmd)
+
+	agentEndCmd := &cobra.Command{
+		Use:   "end",
+		Short: "End current session (alias for 'thrum session end')",
+		Long: `End the current active session.
+
+This is an alias for 'thrum session end'.`,
+		RunE: sessionEndRunE,
+	}
+	agentEndCmd.Flags().String("reason", "normal", "End reason (normal|crash)")
+	agentEndCmd.Flags().String("session-id", "", "Session ID to end (defaults to current session)")
+	cmd.AddCommand(agentEndCmd)
+
+	agentSetIntentCmd := &cobra.Command{
+		Use:   "set-intent TEXT",
+		Short: "Set work intent (alias for 'thrum session set-intent')",
+		Long: `Set the work intent for the current session.
+
+This is an alias for 'thrum session set-intent'.
+Pass an empty string to clear the intent.
+
+Examples:
+  thrum agent set-intent "Fixing memory leak in connection pool"
+  thrum agent set-intent ""   # clear intent`,
+		Args: cobra.ExactArgs(1),
+		RunE: sessionSetIntentRunE,
+	}
+	cmd.AddCommand(agentSetIntentCmd)
+
+	agentHeartbeatCmd := &cobra.Command{
+		Use:   "heartbeat",
+		Short: "Send heartbeat (alias for 'thrum session heartbeat')",
+		Long: `Send a heartbeat for the current session.
 
 This is an alias for 'thrum session heartbeat'.
-Triggers git context extraction and updates the agent's last-seen time.`,
+Triggers git context extraction and updates the agent's last-seen time.
+--intent and --task update the work context in the same call.`,
 		RunE: sessionHeartbeatRunE,
 	}
 	agentHeartbeatCmd.Flags().StringSlice("add-scope", nil, "Add scope (repeatable, format: type:value)")
 	agentHeartbeatCmd.Flags().StringSlice("remove-scope", nil, "Remove scope (repeatable, format: type:value)")
 	agentHeartbeatCmd.Flags().StringSlice("add-ref", nil, "Add ref (repeatable, format: type:value)")
 	agentHeartbeatCmd.Flags().StringSlice("remove-ref", nil, "Remove ref (repeatable, format: type:value)")
+	agentHeartbeatCmd.Flags().String("intent", "", "Also set work intent (empty string clears)")
+	agentHeartbeatCmd.Flags().String("task", "", "Also set current task (empty string clears)")
 	cmd.AddCommand(agentHeartbeatCmd)
 
 	agentSetTaskCmd := &cobra.Command{
@@ -3536,6 +5290,12 @@ func setRemoteAgentStatus(agentName, status string) error {
 	return nil
 }
 
+// addDeclaredFilesFlag adds the --files flag shared by 'session start' and
+// 'agent start'.
+func addDeclaredFilesFlag(cmd *cobra.Command) {
+	cmd.Flags().StringSlice("files", nil, "File this session will touch, shown by who-has and team (repeatable or comma-separated)")
+}
+
 // sessionStartRunE is the shared RunE for 'session start' and 'agent start'.
 func sessionStartRunE(cmd *cobra.Command, args []string) error {
 	client, err := getClient()
@@ -3554,10 +5314,6 @@ func sessionStartRunE(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get agent identity: %w\n\nHint: Register first with 'thrum quickstart --name <name> --role <role> --module <module>'", err)
 	}
 
-	// Parse scope flags (optional for now, will be used in Epic 4)
-	// scopes, _ := cmd.Flags().GetStringSlice("scope")
-	// TODO: Parse scopes when Epic 4 is implemented
-
 	opts := cli.SessionStartOptions{
 		AgentID: whoami.AgentID,
 	}
@@ -3568,10 +5324,14 @@ func sessionStartRunE(cmd *cobra.Command, args []string) error {
 	// would land on the process's actual working directory, which in
 	// fixture tests is the parent thrum source tree and pollutes
 	// session_refs with cross-agent collisions at the same git root.
-	if worktreeRoot := cli.GitTopLevel(flagRepo); worktreeRoot != "" {
+	worktreeRoot := cli.GitTopLevel(flagRepo)
+	if worktreeRoot != "" {
 		opts.Refs = append(opts.Refs, types.Ref{Type: "worktree", Value: worktreeRoot})
 	}
 
+	files, _ := cmd.Flags().GetStringSlice("files")
+	opts.Scopes = cli.DeclaredFileScopes(worktreeRoot, files)
+
 	result, err := cli.SessionStart(client, opts)
 	if err != nil {
 		return err
@@ -3584,9 +5344,54 @@ func sessionStartRunE(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-// sessionEndRunE is the shared RunE for 'session end' and 'agent end'.
-func sessionEndRunE(cmd *cobra.Command, args []string) error {
-	reason, _ := cmd.Flags().GetString("reason")
+// sessionResumeRunE reattaches to the agent's orphaned session, falling back
+// to starting a new one when there is nothing to resume.
+func sessionResumeRunE(cmd *cobra.Command, args []string) error {
+	client, err := getClient()
+	if err != nil {
+		return fmt.Errorf("failed to connect to daemon: %w", err)
+	}
+
+	agentID, err := resolveLocalAgentID()
+	if err != nil {
+		_ = client.Close()
+		return fmt.Errorf("failed to resolve agent identity: %w\n  Register with: thrum quickstart --name <name> --role <role> --module <module>", err)
+	}
+	whoami, err := cli.AgentWhoami(client, agentID)
+	if err != nil {
+		_ = client.Close()
+		return fmt.Errorf("failed to get agent identity: %w\n\nHint: Register first with 'thrum quickstart --name <name> --role <role> --module <module>'", err)
+	}
+
+	opts := cli.SessionResumeOptions{AgentID: whoami.AgentID}
+	if len(args) > 0 {
+		opts.SessionID = args[0]
+	}
+	if worktreeRoot := cli.GitTopLevel(flagRepo); worktreeRoot != "" {
+		opts.Refs = append(opts.Refs, types.Ref{Type: "worktree", Value: worktreeRoot})
+	}
+
+	result, err := cli.SessionResume(client, opts)
+	_ = client.Close()
+	if err != nil {
+		return err
+	}
+
+	if !result.Resumed {
+		fmt.Fprintln(os.Stderr, "No orphaned session to resume — starting a new one.")
+		return sessionStartRunE(cmd, nil)
+	}
+
+	if flagJSON {
+		return cli.EmitJSON(result)
+	}
+	fmt.Print(cli.FormatSessionResume(result))
+	return nil
+}
+
+// sessionEndRunE is the shared RunE for 'session end' and 'agent end'.
+func sessionEndRunE(cmd *cobra.Command, args []string) error {
+	reason, _ := cmd.Flags().GetString("reason")
 	sessionID, _ := cmd.Flags().GetString("session-id")
 
 	client, err := getClient()
@@ -3720,12 +5525,46 @@ func sessionCmd() *cobra.Command {
 		Long: `Start a new work session for the current agent.
 
 The agent must be registered first (use 'thrum quickstart').
-Starting a new session will automatically recover any orphaned sessions.`,
+Starting a new session will automatically recover any orphaned sessions.
+
+Use --files to declare the files you plan to touch. 'thrum who-has' and
+'thrum team' show declared files alongside the files git shows you editing,
+so other agents can avoid a collision before any edit lands. Declared files
+are cleared when the session ends.
+
+Examples:
+  thrum session start
+  thrum session start --files internal/auth/login.go --files internal/auth/token.go
+  thrum session start --files internal/auth/login.go,internal/auth/token.go`,
 		RunE: sessionStartRunE,
 	}
-	// startCmd.Flags().StringSlice("scope", nil, "Session scope (repeatable, format: type:value)")
+	addDeclaredFilesFlag(startCmd)
 	cmd.AddCommand(startCmd)
 
+	resumeCmd := &cobra.Command{
+		Use:   "resume [SESSION_ID]",
+		Short: "Reattach to an orphaned session",
+		Long: `Reattach to a session that was never ended, e.g. after the shell or
+terminal running the agent died.
+
+Unlike 'thrum session start', which ends orphaned sessions as crash-recovered,
+resume keeps the session going: its intent, task, declared files and refs
+carry over, and the current worktree is added as a ref.
+
+With several open sessions, pass the SESSION_ID to resume (see
+'thrum session list --active'); the others are ended as superseded. With no
+open session, a new one is started instead.
+
+Examples:
+  thrum session resume
+  thrum session resume ses_01HXF2A9...`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: sessionResumeRunE,
+	}
+	// resume falls back to sessionStartRunE, which reads --files.
+	addDeclaredFilesFlag(resumeCmd)
+	cmd.AddCommand(resumeCmd)
+
 	endCmd := &cobra.Command{
 		Use:   "end",
 		Short: "End current session",
@@ -3745,14 +5584,24 @@ Specify the session ID to end, or use 'thrum agent whoami' to find your current
 
 Use --active to show only active sessions.
 Use --agent to filter by agent ID.
+Use --since to show only sessions active within the last DURATION: those
+still running and those that ended inside the window.
+
+Each session shows its duration: ended minus started, or "active Nm" for a
+live one. Crashed sessions have no clean end time and show "unknown".
 
 Examples:
   thrum session list
   thrum session list --active
+  thrum session list --since 2h
   thrum session list --json`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			activeOnly, _ := cmd.Flags().GetBool("active")
 			agentID, _ := cmd.Flags().GetString("agent")
+			sinceWindow, _ := cmd.Flags().GetDuration("since")
+			if sinceWindow < 0 {
+				return fmt.Errorf("--since must be a positive duration")
+			}
 
 			client, err := getClient()
 			if err != nil {
@@ -3764,6 +5613,9 @@ Examples:
 				AgentID:    agentID,
 				ActiveOnly: activeOnly,
 			}
+			if sinceWindow > 0 {
+				opts.Since = time.Now().Add(-sinceWindow).UTC().Format(time.RFC3339Nano)
+			}
 
 			result, err := cli.SessionList(client, opts)
 			if err != nil {
@@ -3779,6 +5631,7 @@ Examples:
 	}
 	listCmd.Flags().Bool("active", false, "Show only active sessions")
 	listCmd.Flags().String("agent", "", "Filter by agent ID")
+	listCmd.Flags().Duration("since", 0, "Show only sessions active within this long (e.g. 30m, 2h)")
 	cmd.AddCommand(listCmd)
 
 	// heartbeat subcommand
@@ -3788,18 +5641,22 @@ Examples:
 		Long: `Send a heartbeat for the current session.
 
 This triggers git context extraction and updates the agent's last-seen time.
-Optionally add or remove scopes and refs.
+Optionally add or remove scopes and refs, and set the intent or current task
+in the same call (an empty string clears either).
 
 Examples:
   thrum session heartbeat
   thrum session heartbeat --add-scope module:auth
-  thrum session heartbeat --remove-ref pr:42`,
+  thrum session heartbeat --remove-ref pr:42
+  thrum session heartbeat --intent "Refactoring auth" --task beads:thrum-42`,
 		RunE: sessionHeartbeatRunE,
 	}
 	heartbeatCmd.Flags().StringSlice("add-scope", nil, "Add scope (repeatable, format: type:value)")
 	heartbeatCmd.Flags().StringSlice("remove-scope", nil, "Remove scope (repeatable, format: type:value)")
 	heartbeatCmd.Flags().StringSlice("add-ref", nil, "Add ref (repeatable, format: type:value)")
 	heartbeatCmd.Flags().StringSlice("remove-ref", nil, "Remove ref (repeatable, format: type:value)")
+	heartbeatCmd.Flags().String("intent", "", "Also set work intent (empty string clears)")
+	heartbeatCmd.Flags().String("task", "", "Also set current task (empty string clears)")
 	cmd.AddCommand(heartbeatCmd)
 
 	// set-intent subcommand
@@ -3850,9 +5707,19 @@ func replyCmd() *cobra.Command {
 The reply will include a reply_to reference to the parent message and will be sent
 to the same recipients as the parent message.
 
+To change the audience, --to and --mention replace the inherited recipients
+entirely, while --add-mention keeps them and adds more.
+
+Replying to a message that is not in a thread starts one: the parent and the
+reply both join it, so later replies cluster with them. A parent already in a
+thread is joined instead. --no-thread keeps a flat parent and its reply flat.
+
 Examples:
   thrum reply msg_01HXE... "Good idea, let's do that"
+  thrum reply msg_01HXE... "Noted" --no-thread
   thrum reply msg_01HXE... "Acknowledged" --format plain
+  thrum reply msg_01HXE... "Looping in review" --add-mention @reviewer
+  thrum reply msg_01HXE... "Taking this offline" --to @coordinator
 
 Shell-safe bodies (thrum-d3fp): backticks, $(...), $VAR, and quotes in a
 double-quoted TEXT are interpreted by your shell BEFORE thrum runs. To reply
@@ -3866,75 +5733,338 @@ with such text safely, read the body from stdin or a file via a QUOTED heredoc:
   some-generator | thrum reply msg_01HXE... -        # '-' is a stdin alias`,
 		Args: cobra.RangeArgs(1, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			format, _ := cmd.Flags().GetString("format")
+			return replyRunE(cmd, args, false)
+		},
+	}
 
-			// Resolve the body from positional TEXT, --stdin/'-', or
-			// --body-file (thrum-d3fp). MSG_ID is args[0]; TEXT (when present)
-			// is args[1]. Resolved before getClient so a missing-body error
-			// fails fast without a daemon round-trip.
-			replyText := ""
-			if len(args) > 1 {
-				replyText = args[1]
+	cmd.Flags().String("format", "markdown", "Message format (markdown, plain, json); json bodies must parse as JSON")
+	cmd.Flags().Bool("no-thread", false, "Don't start a thread when the parent is not in one")
+	addBodyInputFlags(cmd)
+	addReplyAudienceFlags(cmd)
+
+	return cmd
+}
+
+// addReplyAudienceFlags registers the flags that adjust the audience a
+// reply inherits from its parent.
+func addReplyAudienceFlags(cmd *cobra.Command) {
+	cmd.Flags().String("to", "", "Send to this recipient instead of the parent's audience (@agent_name or @everyone)")
+	cmd.Flags().StringSlice("mention", nil, "Mention a role instead of the parent's audience (repeatable, format: @role)")
+	cmd.Flags().StringSlice("add-mention", nil, "Mention a role in addition to the parent's audience (repeatable, format: @role)")
+}
+
+// replyRunE runs `thrum reply` and, with quote set, `thrum message quote`.
+func replyRunE(cmd *cobra.Command, args []string, quote bool) error {
+	format, _ := cmd.Flags().GetString("format")
+	to, _ := cmd.Flags().GetString("to")
+	mentions, _ := cmd.Flags().GetStringSlice("mention")
+	addMentions, _ := cmd.Flags().GetStringSlice("add-mention")
+	noThread, _ := cmd.Flags().GetBool("no-thread")
+
+	// Resolve the body from positional TEXT, --stdin/'-', or
+	// --body-file (thrum-d3fp). MSG_ID is args[0]; TEXT (when present)
+	// is args[1]. Resolved before getClient so a missing-body error
+	// fails fast without a daemon round-trip.
+	replyText := ""
+	if len(args) > 1 {
+		replyText = args[1]
+	}
+	content, err := resolveMessageBody(cmd, replyText, len(args) > 1)
+	if err != nil {
+		return err
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return fmt.Errorf("failed to connect to daemon: %w", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	agentID, err := resolveLocalAgentID()
+	if err != nil {
+		return fmt.Errorf("failed to resolve agent identity: %w\n  Register with: thrum quickstart --name <name> --role <role> --module <module>", err)
+	}
+
+	opts := cli.ReplyOptions{
+		MessageID:     args[0],
+		Content:       content,
+		Format:        format,
+		CallerAgentID: agentID,
+		Quote:         quote,
+		NoThread:      noThread,
+		To:            to,
+		Mentions:      mentions,
+		AddMentions:   addMentions,
+	}
+
+	result, err := cli.Reply(client, opts)
+	if err != nil {
+		return err
+	}
+
+	// Auto mark-as-read: mark the replied-to message as read.
+	// Single explicit ID; no race surface; no watermark needed.
+	_, _ = cli.MessageMarkRead(client, []string{opts.MessageID}, agentID, "")
+
+	if flagJSON {
+		return cli.EmitJSON(result)
+	}
+	if !flagQuiet {
+		fmt.Printf("✓ Reply sent: %s\n", result.MessageID)
+	}
+	return nil
+}
+
+// messagePinRunE is shared by message pin and message unpin.
+func messagePinRunE(messageID string, pin bool) error {
+	client, err := getClient()
+	if err != nil {
+		return fmt.Errorf("failed to connect to daemon: %w", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	callerID, _ := resolveLocalAgentID()
+	result, err := cli.MessagePin(client, messageID, pin, callerID)
+	if err != nil {
+		return err
+	}
+
+	if flagJSON {
+		return cli.EmitJSON(result)
+	}
+	if !flagQuiet {
+		fmt.Print(cli.FormatMessagePin(result))
+	}
+	return nil
+}
+
+// messageImportRunE runs `thrum message import` and `thrum import`.
+func messageImportRunE(cmd *cobra.Command, args []string) error {
+	force, _ := cmd.Flags().GetBool("force")
+
+	records, err := cli.ReadMessageArchive(args[0])
+	if err != nil {
+		return err
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return fmt.Errorf("failed to connect to daemon: %w", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	result, err := cli.MessageImport(client, records, force)
+	if err != nil {
+		return err
+	}
+
+	if flagJSON {
+		return cli.EmitJSON(result)
+	}
+	if !flagQuiet {
+		fmt.Print(cli.FormatMessageImport(result))
+	}
+	return nil
+}
+
+func messageCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "message",
+		Short: "Manage individual messages",
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List messages without marking them read",
+		Long: `List messages across the repo with filtering and pagination.
+
+Unlike inbox, message list does not auto-filter to your own audience, keeps
+messages you authored, and never marks anything as read.
+
+--grep REGEX filters the fetched page to messages whose body matches a Go
+regular expression (case-insensitive) and highlights the matches; it does not
+search beyond the current page.
+
+--unseen-by @agent shows the backlog another agent has not read yet (its own
+messages are excluded). It is restricted to coordinator roles.
+
+--deleted includes messages removed with 'thrum message delete', marked
+"[deleted] reason: …" above the original text.
+
+--order sequence orders by the sequence number the sending daemon gave each
+message instead of by send time, so clock skew between synced daemons cannot
+reorder them. Messages from two daemons that share a sequence number are
+ordered by daemon ID. Older messages whose sequence could not be recovered
+on upgrade have none and are listed after all others.
+
+--ids-only prints just the message IDs, one per line, for piping into xargs.
+Not with --json.
+
+Examples:
+  thrum message list
+  thrum message list --order sequence
+  thrum message list --from @planner --page-size 50
+  thrum message list --author-role tester
+  thrum message list --unseen-by @implementer_api
+  thrum message list --deleted --from @planner
+  thrum message list --from @planner --ids-only | xargs -n1 thrum message get`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			scope, _ := cmd.Flags().GetString("scope")
+			unread, _ := cmd.Flags().GetBool("unread")
+			pageSize, _ := cmd.Flags().GetInt("page-size")
+			page, _ := cmd.Flags().GetInt("page")
+			fromAgent, _ := cmd.Flags().GetString("from")
+			authorRole, _ := cmd.Flags().GetString("author-role")
+			unseenBy, _ := cmd.Flags().GetString("unseen-by")
+			tag, _ := cmd.Flags().GetString("tag")
+			priority, _ := cmd.Flags().GetString("priority")
+			grep, _ := cmd.Flags().GetString("grep")
+			includeDeleted, _ := cmd.Flags().GetBool("deleted")
+			order, _ := cmd.Flags().GetString("order")
+			idsOnly, _ := cmd.Flags().GetBool("ids-only")
+			fromAgent = strings.TrimPrefix(fromAgent, "@")
+			unseenBy = strings.TrimPrefix(unseenBy, "@")
+
+			if unread && unseenBy != "" {
+				return fmt.Errorf("--unread and --unseen-by are mutually exclusive")
+			}
+			if order != "time" && order != "sequence" {
+				return fmt.Errorf("invalid --order %q (must be time or sequence)", order)
 			}
-			content, err := resolveMessageBody(cmd, replyText, len(args) > 1)
+			if idsOnly && flagJSON {
+				return fmt.Errorf("--ids-only cannot be combined with --json")
+			}
+			grepRe, err := cli.CompileGrep(grep)
 			if err != nil {
 				return err
 			}
 
+			agentID, err := resolveLocalAgentID()
+			if err != nil {
+				return fmt.Errorf("failed to resolve agent identity: %w\n  Register with: thrum quickstart --name <name> --role <role> --module <module>", err)
+			}
+
 			client, err := getClient()
 			if err != nil {
 				return fmt.Errorf("failed to connect to daemon: %w", err)
 			}
 			defer func() { _ = client.Close() }()
 
-			agentID, err := resolveLocalAgentID()
+			result, err := cli.Inbox(client, cli.InboxOptions{
+				Scope:          scope,
+				Unread:         unread,
+				PageSize:       pageSize,
+				Page:           page,
+				CallerAgentID:  agentID,
+				AuthorID:       fromAgent,
+				AuthorRole:     strings.TrimPrefix(authorRole, "@"),
+				Tag:            tag,
+				Priority:       priority,
+				UnseenBy:       unseenBy,
+				IncludeDeleted: includeDeleted,
+				IncludeSelf:    true,
+				SequenceSort:   order == "sequence",
+			})
 			if err != nil {
-				return fmt.Errorf("failed to resolve agent identity: %w\n  Register with: thrum quickstart --name <name> --role <role> --module <module>", err)
+				return err
 			}
+			grepScanned := cli.FilterInboxByBody(result, grepRe)
 
-			opts := cli.ReplyOptions{
-				MessageID:     args[0],
-				Content:       content,
-				Format:        format,
-				CallerAgentID: agentID,
+			if idsOnly {
+				fmt.Print(cli.FormatMessageIDs(result))
+				return nil
+			}
+			if flagJSON {
+				return cli.EmitJSON(result)
+			}
+			fmt.Print(cli.FormatInboxWithOptions(result, cli.InboxFormatOptions{
+				ActiveScope: scope,
+				Grep:        grep,
+				GrepRegexp:  grepRe,
+				GrepScanned: grepScanned,
+				Quiet:       flagQuiet,
+			}))
+			return nil
+		},
+	}
+	listCmd.Flags().String("scope", "", "Filter by scope (format: type:value)")
+	listCmd.Flags().Bool("unread", false, "Only messages you have not read")
+	listCmd.Flags().String("unseen-by", "", "Only messages this agent has not read (coordinator roles only; @agent or agent)")
+	listCmd.Flags().String("from", "", "Filter to messages from a specific agent (use @agent_name or agent_name)")
+	listCmd.Flags().String("author-role", "", "Filter to messages authored by any agent with this role")
+	listCmd.Flags().String("tag", "", "Filter to messages carrying this tag")
+	listCmd.Flags().String("priority", "", "Filter to messages with this priority (low, normal, high)")
+	listCmd.Flags().String("grep", "", "Only show messages on the fetched page whose body matches REGEX (case-insensitive)")
+	listCmd.Flags().Bool("deleted", false, "Include deleted messages, marked [deleted] with the reason")
+	listCmd.Flags().Bool("ids-only", false, "Print only message IDs, one per line")
+	listCmd.Flags().String("order", "time", "Sort by send time or by origin sequence (time, sequence); newest first")
+	listCmd.Flags().Int("page-size", 10, "Results per page")
+	listCmd.Flags().Int("page", 1, "Page number")
+	cmd.AddCommand(listCmd)
+
+	searchCmd := &cobra.Command{
+		Use:   "search QUERY",
+		Short: "Full-text search over message bodies",
+		Long: `Search message bodies across the repo. Every word in QUERY must match.
+
+Results are ranked by relevance using the daemon's full-text index. Each
+hit shows the matched text with surrounding context. Deleted, expired, and
+not-yet-delivered scheduled messages are never returned, and searching does
+not mark anything as read.
+
+Examples:
+  thrum message search "memory leak"
+  thrum message search "memory leak" --author @planner
+  thrum message search migration --scope module:auth --limit 5`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			author, _ := cmd.Flags().GetString("author")
+			scope, _ := cmd.Flags().GetString("scope")
+			limit, _ := cmd.Flags().GetInt("limit")
+			query := strings.Join(args, " ")
+
+			client, err := getClient()
+			if err != nil {
+				return fmt.Errorf("failed to connect to daemon: %w", err)
 			}
+			defer func() { _ = client.Close() }()
 
-			result, err := cli.Reply(client, opts)
+			result, err := cli.MessageSearch(client, cli.MessageSearchOptions{
+				Query:    query,
+				AuthorID: strings.TrimPrefix(author, "@"),
+				Scope:    scope,
+				Limit:    limit,
+			})
 			if err != nil {
 				return err
 			}
 
-			// Auto mark-as-read: mark the replied-to message as read.
-			// Single explicit ID; no race surface; no watermark needed.
-			_, _ = cli.MessageMarkRead(client, []string{opts.MessageID}, agentID, "")
-
 			if flagJSON {
 				return cli.EmitJSON(result)
 			}
 			if !flagQuiet {
-				fmt.Printf("✓ Reply sent: %s\n", result.MessageID)
+				fmt.Print(cli.FormatMessageSearch(query, result))
 			}
 			return nil
 		},
 	}
-
-	cmd.Flags().String("format", "markdown", "Message format (markdown, plain, json)")
-	addBodyInputFlags(cmd)
-
-	return cmd
-}
-
-func messageCmd() *cobra.Command {
-	cmd := &cobra.Command{
-		Use:   "message",
-		Short: "Manage individual messages",
-	}
+	searchCmd.Flags().String("author", "", "Only messages from this agent (use @agent_name or agent_name)")
+	searchCmd.Flags().String("scope", "", "Filter by scope (format: type:value)")
+	searchCmd.Flags().Int("limit", 20, "Maximum number of results (max 100)")
+	cmd.AddCommand(searchCmd)
 
 	getCmd := &cobra.Command{
 		Use:   "get MSG_ID",
 		Short: "Get a single message with full details",
-		Args:  cobra.ExactArgs(1),
+		Long: `Get a single message with full details.
+
+Use --thread to also show the messages immediately before and after it in
+its thread, oldest first. A reply without a thread shows its reply_to parent.
+A message outside any thread is printed on its own.`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			showThread, _ := cmd.Flags().GetBool("thread")
+
 			client, err := getClient()
 			if err != nil {
 				return fmt.Errorf("failed to connect to daemon: %w", err)
@@ -3946,6 +6076,15 @@ func messageCmd() *cobra.Command {
 				return err
 			}
 
+			if showThread {
+				// Context is a convenience; the message itself still prints
+				// if the thread can't be loaded.
+				result.Thread, err = cli.MessageThreadNeighbors(client, &result.Message)
+				if err != nil && !flagQuiet {
+					fmt.Fprintf(os.Stderr, "Warning: Could not load thread context: %v\n", err)
+				}
+			}
+
 			// Auto mark-as-read (best effort — don't fail if identity resolution fails)
 			agentID, err := resolveLocalAgentID()
 			if err != nil {
@@ -3960,10 +6099,11 @@ func messageCmd() *cobra.Command {
 			if flagJSON {
 				return cli.EmitJSON(result)
 			}
-			fmt.Print(cli.FormatMessageGet(result))
+			fmt.Print(cli.FormatMessageGetWithThread(result))
 			return nil
 		},
 	}
+	getCmd.Flags().Bool("thread", false, "Also show the previous and next messages in the thread")
 	cmd.AddCommand(getCmd)
 
 	editCmd := &cobra.Command{
@@ -4028,21 +6168,580 @@ content with such text safely, read the body from stdin or a file:
 	addBodyInputFlags(editCmd)
 	cmd.AddCommand(editCmd)
 
-	deleteCmd := &cobra.Command{
-		Use:   "delete MSG_ID",
-		Short: "Delete a message",
-		Long: `Delete a message by ID.
+	historyCmd := &cobra.Command{
+		Use:   "history MSG_ID",
+		Short: "Show a message's edit history",
+		Long: `Show every version of a message, oldest first: the original body followed
+by one entry per edit with its timestamp and version number. Deleted messages
+still show their history. Viewing history does not mark the message as read.
 
-Requires --force flag to confirm deletion.
+Examples:
+  thrum message history msg_01HXE8Z7
+  thrum message history msg_01HXE8Z7 --json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := getClient()
+			if err != nil {
+				return fmt.Errorf("failed to connect to daemon: %w", err)
+			}
+			defer func() { _ = client.Close() }()
+
+			result, err := cli.MessageHistory(client, args[0])
+			if err != nil {
+				return err
+			}
+
+			if flagJSON {
+				return cli.EmitJSON(result)
+			}
+			if !flagQuiet {
+				fmt.Print(cli.FormatMessageHistory(result))
+			}
+			return nil
+		},
+	}
+	cmd.AddCommand(historyCmd)
+
+	readersCmd := &cobra.Command{
+		Use:   "readers MSG_ID",
+		Short: "Show which recipients have read a message",
+		Long: `Show which of a message's intended recipients have read it and which
+have not. Recipients are resolved from the message's mentions (by agent or
+role) and group scopes. For @everyone, every registered agent other than the
+author counts, using the roster as it is now rather than when the message was
+sent. Checking readers does not mark the message as read.
 
 Examples:
-  thrum message delete msg_01HXE... --force`,
+  thrum message readers msg_01HXE8Z7
+  thrum message readers msg_01HXE8Z7 --json`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := getClient()
+			if err != nil {
+				return fmt.Errorf("failed to connect to daemon: %w", err)
+			}
+			defer func() { _ = client.Close() }()
+
+			result, err := cli.MessageReaders(client, args[0])
+			if err != nil {
+				return err
+			}
+
+			if flagJSON {
+				return cli.EmitJSON(result)
+			}
+			if !flagQuiet {
+				fmt.Print(cli.FormatMessageReaders(result))
+			}
+			return nil
+		},
+	}
+	cmd.AddCommand(readersCmd)
+
+	forwardCmd := &cobra.Command{
+		Use:   "forward MSG_ID",
+		Short: "Re-send a message to a different audience",
+		Long: `Forward a message to a new audience. The new message quotes the original
+content under an attribution header (author, message ID, timestamp) and
+carries a forwarded_from ref pointing at the source message. Recipients are
+chosen with --to, --mention, and --scope exactly as in 'thrum send'.
+Deleted messages cannot be forwarded.
+
+Examples:
+  thrum message forward msg_01HXE8Z7 --to @implementer_api
+  thrum message forward msg_01HXE8Z7 --mention @reviewer
+  thrum message forward msg_01HXE8Z7 --scope module:auth --mention @auth`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			to, _ := cmd.Flags().GetString("to")
+			mentions, _ := cmd.Flags().GetStringSlice("mention")
+			scopes, _ := cmd.Flags().GetStringSlice("scope")
+			if to == "" && len(mentions) == 0 && len(scopes) == 0 {
+				return fmt.Errorf("missing recipient: use --to @agent_name, --mention @role, or --scope type:value")
+			}
+
+			agentID, err := resolveLocalAgentID()
+			if err != nil {
+				return fmt.Errorf("failed to resolve agent identity: %w", err)
+			}
+
+			client, err := getClient()
+			if err != nil {
+				return fmt.Errorf("failed to connect to daemon: %w", err)
+			}
+			defer func() { _ = client.Close() }()
+
+			result, err := cli.MessageForward(client, cli.MessageForwardOptions{
+				MessageID:     args[0],
+				To:            to,
+				Mentions:      mentions,
+				Scopes:        scopes,
+				CallerAgentID: agentID,
+			})
+			if err != nil {
+				return err
+			}
+
+			if flagJSON {
+				return cli.EmitJSON(result)
+			}
+			if !flagQuiet {
+				fmt.Print(cli.FormatMessageForward(args[0], result))
+				for _, w := range result.Warnings {
+					fmt.Fprintf(os.Stderr, "  warning: %s\n", w)
+				}
+			}
+			return nil
+		},
+	}
+	forwardCmd.Flags().String("to", "", "Recipient (@agent_name or @everyone)")
+	forwardCmd.Flags().StringSlice("mention", nil, "Mention a role (repeatable, format: @role)")
+	forwardCmd.Flags().StringSlice("scope", nil, "Add scope (repeatable, format: type:value)")
+	cmd.AddCommand(forwardCmd)
+
+	quoteCmd := &cobra.Command{
+		Use:   "quote MSG_ID [TEXT]",
+		Short: "Reply with the parent message quoted",
+		Long: `Reply to a message with its content quoted above your text. Works like
+'thrum reply' (same audience, reply_to ref, and body input flags), but the
+parent's first few lines are prefixed to the reply. Long parents are cut off
+with an ellipsis.
+
+Markdown parents are quoted as a "> " blockquote. Plain-format parents are
+indented under an "@author wrote:" line instead, since their readers would
+see the "> " literally.
+
+Examples:
+  thrum message quote msg_01HXE8Z7 "Agreed, let's ship after CI"
+  thrum message quote msg_01HXE8Z7 --body-file ./answer.md`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return replyRunE(cmd, args, true)
+		},
+	}
+	quoteCmd.Flags().String("format", "markdown", "Message format (markdown, plain, json)")
+	quoteCmd.Flags().Bool("no-thread", false, "Don't start a thread when the parent is not in one")
+	addBodyInputFlags(quoteCmd)
+	addReplyAudienceFlags(quoteCmd)
+	cmd.AddCommand(quoteCmd)
+
+	deleteCmd := &cobra.Command{
+		Use:   "delete MSG_ID",
+		Short: "Delete a message",
+		Long: `Delete a message by ID.
+
+Requires --force flag to confirm deletion.
+
+Examples:
+  thrum message delete msg_01HXE... --force`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			force, _ := cmd.Flags().GetBool("force")
+			if !force {
+				return fmt.Errorf("use --force to confirm deletion")
+			}
+
+			client, err := getClient()
+			if err != nil {
+				return fmt.Errorf("failed to connect to daemon: %w", err)
+			}
+			defer func() { _ = client.Close() }()
+
+			// Pass caller's claimed identity so the daemon's
+			// shared-worktree disambiguation (thrum-0pos) can accept
+			// the claim when peercred picks a co-located sibling.
+			callerID, _ := resolveLocalAgentID()
+			result, err := cli.MessageDelete(client, args[0], callerID)
+			if err != nil {
+				return err
+			}
+
+			if flagJSON {
+				return cli.EmitJSON(result)
+			}
+			if !flagQuiet {
+				fmt.Print(cli.FormatMessageDelete(result))
+			}
+			return nil
+		},
+	}
+	deleteCmd.Flags().Bool("force", false, "Confirm deletion")
+	cmd.AddCommand(deleteCmd)
+
+	cancelCmd := &cobra.Command{
+		Use:   "cancel MSG_ID",
+		Short: "Cancel a scheduled message before it is delivered",
+		Long: `Withdraw a message sent with 'thrum send --schedule' before its delivery
+time. The message is deleted (reason "canceled") and its recipients are
+never notified. Only the author can cancel, and only while the message is
+still pending; 'thrum sent' lists pending messages as scheduled.
+
+Examples:
+  thrum message cancel msg_01HXE...`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := getClient()
+			if err != nil {
+				return fmt.Errorf("failed to connect to daemon: %w", err)
+			}
+			defer func() { _ = client.Close() }()
+
+			callerID, _ := resolveLocalAgentID()
+			result, err := cli.MessageCancel(client, args[0], callerID)
+			if err != nil {
+				return err
+			}
+
+			if flagJSON {
+				return cli.EmitJSON(result)
+			}
+			if !flagQuiet {
+				fmt.Print(cli.FormatMessageCancel(result))
+			}
+			return nil
+		},
+	}
+	cmd.AddCommand(cancelCmd)
+
+	reactCmd := &cobra.Command{
+		Use:   "react MSG_ID EMOJI",
+		Short: "Toggle an emoji reaction on a message",
+		Long: `Acknowledge a message with an emoji instead of a reply.
+
+EMOJI is a :shortcode: or a literal emoji. Reacting again with the same
+emoji removes your reaction. Reactions show up in 'thrum message get'
+and do not mark the message read. They sync to other machines like edits
+and deletes do.
+
+Examples:
+  thrum message react msg_01HXE... :thumbsup:
+  thrum message react msg_01HXE... 👀`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := getClient()
+			if err != nil {
+				return fmt.Errorf("failed to connect to daemon: %w", err)
+			}
+			defer func() { _ = client.Close() }()
+
+			callerID, _ := resolveLocalAgentID()
+			result, err := cli.MessageReact(client, args[0], args[1], callerID)
+			if err != nil {
+				return err
+			}
+
+			if flagJSON {
+				return cli.EmitJSON(result)
+			}
+			if !flagQuiet {
+				fmt.Print(cli.FormatMessageReact(result))
+			}
+			return nil
+		},
+	}
+	cmd.AddCommand(reactCmd)
+
+	pinCmd := &cobra.Command{
+		Use:   "pin MSG_ID",
+		Short: "Pin a message for everyone in the repo",
+		Long: `Pin an important message so it stays easy to find.
+
+Pins are shared by every agent in the repo, not kept per agent. List them
+with 'thrum inbox --pinned'. Deleted messages can't be pinned, and deleting
+a pinned message unpins it.
+
+Examples:
+  thrum message pin msg_01HXE...`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return messagePinRunE(args[0], true)
+		},
+	}
+	cmd.AddCommand(pinCmd)
+
+	unpinCmd := &cobra.Command{
+		Use:   "unpin MSG_ID",
+		Short: "Unpin a message",
+		Long: `Remove a message's pin. Unpinning a message that isn't pinned changes
+nothing and says so.
+
+Examples:
+  thrum message unpin msg_01HXE...`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return messagePinRunE(args[0], false)
+		},
+	}
+	cmd.AddCommand(unpinCmd)
+
+	moveCmd := &cobra.Command{
+		Use:   "move MSG_ID --thread THREAD_ID",
+		Short: "Move one of your messages into another thread",
+		Long: `Reassign a message you sent to an existing thread, for example a
+follow-up that was sent on its own but belongs in an earlier conversation.
+
+Only the author can move a message, and the thread must already exist.
+Replies keep their reply_to reference, so 'thrum thread show' still nests
+them under their original parent when it is in the same thread.
+
+Examples:
+  thrum message move msg_01HXE... --thread thr_01HXD...`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			threadID, _ := cmd.Flags().GetString("thread")
+
+			client, err := getClient()
+			if err != nil {
+				return fmt.Errorf("failed to connect to daemon: %w", err)
+			}
+			defer func() { _ = client.Close() }()
+
+			callerID, _ := resolveLocalAgentID()
+			result, err := cli.MessageMove(client, args[0], threadID, callerID)
+			if err != nil {
+				return err
+			}
+
+			if flagJSON {
+				return cli.EmitJSON(result)
+			}
+			if !flagQuiet {
+				fmt.Print(cli.FormatMessageMove(result))
+			}
+			return nil
+		},
+	}
+	moveCmd.Flags().String("thread", "", "Destination thread ID (required)")
+	_ = moveCmd.MarkFlagRequired("thread")
+	cmd.AddCommand(moveCmd)
+
+	bumpCmd := &cobra.Command{
+		Use:   "bump MSG_ID",
+		Short: "Resurface one of your messages by notifying its recipients again",
+		Long: `Send a message's notification again to everyone it reached the first time,
+for example a review request that has gone unanswered. The message itself is
+unchanged; its bumped_at time is recorded so 'thrum inbox --bump-sort' can
+list recently bumped messages first.
+
+Only the author can bump a message, a scheduled message can't be bumped
+before it is delivered, and a message can be bumped at most once every 5
+minutes.
+
+Examples:
+  thrum message bump msg_01HXE...`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := getClient()
+			if err != nil {
+				return fmt.Errorf("failed to connect to daemon: %w", err)
+			}
+			defer func() { _ = client.Close() }()
+
+			callerID, _ := resolveLocalAgentID()
+			result, err := cli.MessageBump(client, args[0], callerID)
+			if err != nil {
+				return err
+			}
+
+			if flagJSON {
+				return cli.EmitJSON(result)
+			}
+			if !flagQuiet {
+				fmt.Print(cli.FormatMessageBump(result))
+			}
+			return nil
+		},
+	}
+	cmd.AddCommand(bumpCmd)
+
+	tailCmd := &cobra.Command{
+		Use:   "tail [-n N] [--follow]",
+		Short: "Show the latest messages, optionally following new ones",
+		Long: `Print the last N messages in your inbox, oldest first, one block per
+message. With --follow, keep running and print new messages as they arrive,
+like tail -f. Stop with Ctrl-C.
+
+Like 'thrum inbox', tail shows messages addressed to you plus broadcasts,
+and --follow keeps applying the same filter; use --all for every message.
+Messages are not marked read. With --json each message is printed as one
+JSON line.
+
+Examples:
+  thrum message tail
+  thrum message tail -n 50 --follow
+  thrum message tail --follow --all --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			n, _ := cmd.Flags().GetInt("lines")
+			follow, _ := cmd.Flags().GetBool("follow")
+			showAll, _ := cmd.Flags().GetBool("all")
+			if n < 0 || n > 100 {
+				return fmt.Errorf("-n must be between 0 and 100")
+			}
+
+			agentID, err := resolveLocalAgentID()
+			if err != nil {
+				return fmt.Errorf("failed to resolve agent identity: %w\n  Register with: thrum quickstart --name <name> --role <role> --module <module>", err)
+			}
+			agentRole, err := resolveLocalMentionRole()
+			if err != nil {
+				return fmt.Errorf("failed to resolve agent role: %w\n  Register with: thrum quickstart --name <name> --role <role> --module <module>", err)
+			}
+			opts := cli.InboxOptions{
+				CallerAgentID:     agentID,
+				CallerMentionRole: agentRole,
+			}
+			if !showAll && agentID != "" {
+				opts.ForAgent = agentID
+				opts.ForAgentRole = agentRole
+			}
+
+			render := cli.FormatTailLine
+			if flagJSON {
+				render = nil
+			}
+
+			start := time.Now()
+			var msgs []cli.Message
+			if n > 0 {
+				client, err := getClient()
+				if err != nil {
+					return fmt.Errorf("failed to connect to daemon: %w", err)
+				}
+				msgs, err = cli.MessageTail(client, opts, n)
+				_ = client.Close()
+				if err != nil {
+					return err
+				}
+			}
+			for _, m := range msgs {
+				if render == nil {
+					if err := json.NewEncoder(os.Stdout).Encode(m); err != nil {
+						return err
+					}
+				} else {
+					fmt.Print(render(m))
+				}
+			}
+			if !follow {
+				return nil
+			}
+
+			socketPath := os.Getenv("THRUM_SOCKET")
+			if socketPath == "" {
+				socketPath = cli.DefaultSocketPath(flagRepo)
+			}
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			return cli.InboxWatch(ctx, os.Stdout, cli.InboxWatchOptions{
+				Inbox:      opts,
+				SocketPath: socketPath,
+				RepoPath:   flagRepo,
+				Since:      cli.TailSince(msgs, start),
+				Quiet:      flagQuiet,
+				Render:     render,
+			})
+		},
+	}
+	tailCmd.Flags().IntP("lines", "n", 20, "Number of recent messages to show first (max 100)")
+	tailCmd.Flags().BoolP("follow", "f", false, "Keep running and print new messages as they arrive")
+	tailCmd.Flags().BoolP("all", "a", false, "Show all messages, not just ones addressed to you")
+	cmd.AddCommand(tailCmd)
+
+	assignCmd := &cobra.Command{
+		Use:   "assign MSG_ID @AGENT",
+		Short: "Turn a message into a task for an agent",
+		Long: `Assign a message to an agent as a task. The assignee sees it with
+'thrum inbox --assigned-to-me' and marks it done with 'thrum message complete'.
+
+Assigning a message that already has an open task reassigns it. The earlier
+assignment is kept as history.
+
+Examples:
+  thrum message assign msg_01HXE... @implementer`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := getClient()
+			if err != nil {
+				return fmt.Errorf("failed to connect to daemon: %w", err)
+			}
+			defer func() { _ = client.Close() }()
+
+			callerID, _ := resolveLocalAgentID()
+			result, err := cli.MessageAssign(client, args[0], args[1], callerID)
+			if err != nil {
+				return err
+			}
+
+			if flagJSON {
+				return cli.EmitJSON(result)
+			}
+			if !flagQuiet {
+				fmt.Print(cli.FormatMessageAssign(result))
+			}
+			return nil
+		},
+	}
+	cmd.AddCommand(assignCmd)
+
+	completeCmd := &cobra.Command{
+		Use:   "complete MSG_ID",
+		Short: "Mark a task assigned to you as done",
+		Long: `Complete a message assigned to you with 'thrum message assign'. Only the
+current assignee can complete it. The agent who assigned it gets a message
+from @system, threaded under the task.
+
+Examples:
+  thrum message complete msg_01HXE...`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := getClient()
+			if err != nil {
+				return fmt.Errorf("failed to connect to daemon: %w", err)
+			}
+			defer func() { _ = client.Close() }()
+
+			callerID, _ := resolveLocalAgentID()
+			result, err := cli.MessageComplete(client, args[0], callerID)
+			if err != nil {
+				return err
+			}
+
+			if flagJSON {
+				return cli.EmitJSON(result)
+			}
+			if !flagQuiet {
+				fmt.Print(cli.FormatMessageComplete(result))
+			}
+			return nil
+		},
+	}
+	cmd.AddCommand(completeCmd)
+
+	attachmentCmd := &cobra.Command{
+		Use:   "attachment",
+		Short: "Work with files attached to messages",
+	}
+	attachmentFetchCmd := &cobra.Command{
+		Use:   "fetch MSG_ID NAME",
+		Short: "Copy a message attachment to a local file",
+		Long: `Copy a file attached with 'thrum send --attach' out of the sync worktree.
+'thrum message get' lists a message's attachments by name.
+
+The copy is checked against the SHA-256 recorded when the message was sent
+and removed if it does not match. It is written to ./NAME unless --output is
+given, and an existing file is only replaced with --force.
+
+An attachment from another machine is available once sync has pulled it;
+'thrum sync force' pulls right away.
+
+Examples:
+  thrum message attachment fetch msg_01HXE... build.log
+  thrum message attachment fetch msg_01HXE... build.log -o /tmp/ci.log`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			output, _ := cmd.Flags().GetString("output")
 			force, _ := cmd.Flags().GetBool("force")
-			if !force {
-				return fmt.Errorf("use --force to confirm deletion")
-			}
 
 			client, err := getClient()
 			if err != nil {
@@ -4050,11 +6749,7 @@ Examples:
 			}
 			defer func() { _ = client.Close() }()
 
-			// Pass caller's claimed identity so the daemon's
-			// shared-worktree disambiguation (thrum-0pos) can accept
-			// the claim when peercred picks a co-located sibling.
-			callerID, _ := resolveLocalAgentID()
-			result, err := cli.MessageDelete(client, args[0], callerID)
+			result, err := cli.MessageAttachmentFetch(client, args[0], args[1], output, force)
 			if err != nil {
 				return err
 			}
@@ -4063,13 +6758,15 @@ Examples:
 				return cli.EmitJSON(result)
 			}
 			if !flagQuiet {
-				fmt.Print(cli.FormatMessageDelete(result))
+				fmt.Print(cli.FormatAttachmentFetch(result))
 			}
 			return nil
 		},
 	}
-	deleteCmd.Flags().Bool("force", false, "Confirm deletion")
-	cmd.AddCommand(deleteCmd)
+	attachmentFetchCmd.Flags().StringP("output", "o", "", "Write the attachment to this path (default: ./NAME)")
+	attachmentFetchCmd.Flags().Bool("force", false, "Overwrite the output file if it exists")
+	attachmentCmd.AddCommand(attachmentFetchCmd)
+	cmd.AddCommand(attachmentCmd)
 
 	readCmd := &cobra.Command{
 		Use:   "read [MSG_ID...]",
@@ -4112,8 +6809,12 @@ Examples:
 				// time; the watermark only guards what gets *marked*.
 				markedBefore := time.Now().UTC().Format(time.RFC3339Nano)
 
-				// Fetch all unread message IDs (capped at 100 per page)
-				inboxResult, err := cli.Inbox(client, cli.InboxOptions{
+				// Fetch every unread page: page 1, then the rest pipelined in
+				// one round trip, and mark them all with a single markRead.
+				// Both calls are idempotent, so a connection the daemon drops
+				// mid-way is redialed and resumed.
+				client.SetPersistent(true)
+				unread, err := cli.InboxAllPages(client, cli.InboxOptions{
 					Unread:            true,
 					PageSize:          100,
 					CallerAgentID:     agentID,
@@ -4122,14 +6823,14 @@ Examples:
 				if err != nil {
 					return fmt.Errorf("failed to list unread messages: %w", err)
 				}
-				if len(inboxResult.Messages) == 0 {
+				if len(unread) == 0 {
 					if !flagQuiet {
 						fmt.Println("No unread messages.")
 					}
 					return nil
 				}
-				messageIDs = make([]string, len(inboxResult.Messages))
-				for i, m := range inboxResult.Messages {
+				messageIDs = make([]string, len(unread))
+				for i, m := range unread {
 					messageIDs[i] = m.MessageID
 				}
 
@@ -4195,6 +6896,74 @@ Examples:
 	readCmd.Flags().Bool("all", false, "Mark all unread messages as read")
 	cmd.AddCommand(readCmd)
 
+	importCmd := &cobra.Command{
+		Use:   "import FILE",
+		Short: "Import messages from an exported JSONL archive",
+		Long: `Import messages from a JSONL file in the message archive format
+(.thrum/archive/<name>.jsonl), e.g. to seed a fresh repo with history.
+
+Each record is replayed as a message.create event, preserving its message ID,
+timestamp, author, scopes and refs. Messages whose ID already exists are
+skipped; --force overwrites them instead.
+
+Examples:
+  thrum message import .thrum/archive/backend.jsonl
+  thrum message import history.jsonl --force`,
+		Args: cobra.ExactArgs(1),
+		RunE: messageImportRunE,
+	}
+	importCmd.Flags().Bool("force", false, "Overwrite messages whose ID already exists")
+	cmd.AddCommand(importCmd)
+
+	return cmd
+}
+
+func threadCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "thread",
+		Short: "View conversation threads",
+	}
+
+	showCmd := &cobra.Command{
+		Use:   "show THREAD_ID",
+		Short: "Show a whole thread as a reply tree",
+		Long: `Show every message in a thread, nested under the message it replies to.
+
+Replies are indented beneath their parent, and branches are ordered by their
+most recent activity so the liveliest branch prints last. Deleted messages
+keep their place as "(deleted)"; replies whose parent no longer exists are
+grouped under a "(deleted)" placeholder. Viewing a thread does not mark
+anything as read.
+
+With --json the tree is returned as nested "replies" arrays.
+
+Examples:
+  thrum thread show thr_01HXE8Z7
+  thrum thread show thr_01HXE8Z7 --json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := getClient()
+			if err != nil {
+				return fmt.Errorf("failed to connect to daemon: %w", err)
+			}
+			defer func() { _ = client.Close() }()
+
+			result, err := cli.ThreadGet(client, args[0])
+			if err != nil {
+				return err
+			}
+
+			if flagJSON {
+				return cli.EmitJSON(result)
+			}
+			if !flagQuiet {
+				fmt.Print(cli.FormatThread(result))
+			}
+			return nil
+		},
+	}
+	cmd.AddCommand(showCmd)
+
 	return cmd
 }
 
@@ -4209,9 +6978,12 @@ func contextCmd() *cobra.Command {
 
 	cmd.AddCommand(contextSaveCmd())
 	cmd.AddCommand(contextShowCmd())
+	cmd.AddCommand(contextDiffCmd())
 	cmd.AddCommand(contextClearCmd())
 	cmd.AddCommand(contextSyncCmd())
 	cmd.AddCommand(contextPreambleCmd())
+	cmd.AddCommand(contextExportCmd())
+	cmd.AddCommand(contextImportCmd())
 
 	return cmd
 }
@@ -4298,9 +7070,21 @@ Examples:
   thrum context show --project      # Show project state only
   thrum context show --session      # Show session context only
   thrum context show --agent coordinator
+  thrum context show --agent all    # Every agent's session context
   thrum context show --raw
-  thrum context show --no-preamble`,
+  thrum context show --no-preamble
+
+--agent all prints the session context of every registered agent under a
+header, listing agents with nothing saved as empty. With --json it returns
+a map of agent name to context.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if flagAgent == "all" {
+				if flagProject || flagRaw {
+					return fmt.Errorf("--agent all shows session context only; drop --project and --raw")
+				}
+				return contextShowAll(!flagNoPreamble)
+			}
+
 			agentID, err := resolveLocalAgentID()
 			if err != nil && flagAgent == "" {
 				return fmt.Errorf("failed to resolve agent identity: %w", err)
@@ -4417,7 +7201,7 @@ Examples:
 		},
 	}
 
-	cmd.Flags().StringVar(&flagAgent, "agent", "", "Override agent name")
+	cmd.Flags().StringVar(&flagAgent, "agent", "", "Override agent name ('all' for every agent)")
 	cmd.Flags().BoolVar(&flagRaw, "raw", false, "Raw output with file boundary markers, no header")
 	cmd.Flags().BoolVar(&flagNoPreamble, "no-preamble", false, "Exclude preamble from output")
 	cmd.Flags().BoolVar(&flagProject, "project", false, "Show project state only")
@@ -4426,6 +7210,119 @@ Examples:
 	return cmd
 }
 
+// contextShowAll implements `thrum context show --agent all`: it lists the
+// registered agents and fetches each one's context with context.show.
+func contextShowAll(includePreamble bool) error {
+	absRepo, _ := filepath.Abs(flagRepo)
+
+	client, err := getClient()
+	if err != nil {
+		return fmt.Errorf("connect to daemon: %w", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	agents, err := cli.AgentList(client, cli.AgentListOptions{})
+	if err != nil {
+		return err
+	}
+
+	contexts := make(map[string]cli.AgentContext)
+	for _, name := range cli.ContextAgents(agents) {
+		var resp rpc.ContextShowResponse
+		if err := client.Call("context.show", rpc.ContextShowRequest{
+			AgentName:       name,
+			IncludePreamble: &includePreamble,
+			RepoPath:        absRepo,
+		}, &resp); err != nil {
+			return fmt.Errorf("context for %s: %w", name, err)
+		}
+		contexts[name] = cli.AgentContext{
+			Content:    string(resp.Content),
+			Preamble:   string(resp.Preamble),
+			HasContext: resp.HasContext,
+			Size:       resp.Size,
+			UpdatedAt:  resp.UpdatedAt,
+		}
+	}
+
+	if flagJSON {
+		return cli.EmitJSON(contexts)
+	}
+	fmt.Print(cli.FormatAllContexts(contexts))
+	return nil
+}
+
+func contextDiffCmd() *cobra.Command {
+	var flagFile string
+	var flagAgent string
+
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Compare saved context with a file",
+		Long: `Show a unified diff from the saved context of the current agent (or
+--agent NAME) to a candidate file, e.g. before overwriting it with
+'thrum context save'. The preamble is not included. With no saved context,
+the whole file is shown as additions.
+
+Examples:
+  thrum context diff --file dev-docs/Continuation_Prompt.md
+  thrum context diff --file context.md --agent coordinator
+  thrum context diff --file context.md --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			agentID, err := resolveLocalAgentID()
+			if err != nil && flagAgent == "" {
+				return fmt.Errorf("failed to resolve agent identity: %w", err)
+			}
+			if flagAgent != "" {
+				agentID = flagAgent
+			}
+
+			candidate, err := os.ReadFile(flagFile) // #nosec G304 -- flagFile is user-specified via CLI flag; this is a CLI tool, user controls the path
+			if err != nil {
+				return fmt.Errorf("read candidate file: %w", err)
+			}
+
+			absRepo, _ := filepath.Abs(flagRepo)
+
+			client, err := getClient()
+			if err != nil {
+				return fmt.Errorf("connect to daemon: %w", err)
+			}
+			defer func() { _ = client.Close() }()
+
+			includePreamble := false
+			var resp rpc.ContextShowResponse
+			if err := client.Call("context.show", rpc.ContextShowRequest{
+				AgentName:       agentID,
+				IncludePreamble: &includePreamble,
+				RepoPath:        absRepo,
+			}, &resp); err != nil {
+				return err
+			}
+
+			result := &cli.ContextDiffResult{
+				AgentName:  resp.AgentName,
+				File:       flagFile,
+				HasContext: resp.HasContext,
+				Hunks:      cli.DiffContext(string(resp.Content), string(candidate)),
+			}
+			result.Identical = len(result.Hunks) == 0
+
+			if flagJSON {
+				return cli.EmitJSON(result)
+			}
+			fmt.Print(cli.FormatContextDiff(result))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&flagFile, "file", "", "Candidate context file to compare (required)")
+	cmd.Flags().StringVar(&flagAgent, "agent", "", "Override agent name")
+	_ = cmd.MarkFlagRequired("file")
+
+	return cmd
+}
+
 func contextClearCmd() *cobra.Command {
 	var flagAgent string
 
@@ -4521,37 +7418,215 @@ Examples:
 				var resp rpc.PreambleSaveResponse
 				if err := client.Call("context.preamble.save", rpc.PreambleSaveRequest{
 					AgentName: agentID,
-					Content:   data,
+					Content:   data,
+					RepoPath:  absRepo,
+				}, &resp); err != nil {
+					return err
+				}
+				fmt.Println(resp.Message)
+				return nil
+			}
+
+			// Show current preamble
+			var resp rpc.PreambleShowResponse
+			if err := client.Call("context.preamble.show", rpc.PreambleShowRequest{
+				AgentName: agentID,
+				RepoPath:  absRepo,
+			}, &resp); err != nil {
+				return err
+			}
+
+			if !resp.HasPreamble {
+				fmt.Printf("No preamble for %s (use --init to create default)\n", resp.AgentName)
+				return nil
+			}
+
+			fmt.Print(string(resp.Content))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&flagAgent, "agent", "", "Override agent name")
+	cmd.Flags().BoolVar(&flagInit, "init", false, "Create or reset to default preamble")
+	cmd.Flags().StringVar(&flagFile, "file", "", "Set preamble from file")
+
+	return cmd
+}
+
+func contextExportCmd() *cobra.Command {
+	var flagAgent string
+	var flagOutput string
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export agent context and preamble as one bundle file",
+		Long: `Write the saved context and preamble of the current agent (or --agent NAME)
+to a single bundle file, to hand to an agent in another repo with
+'thrum context import'. Each part is framed by markers like
+'thrum context show --raw', and the bundle round-trips byte for byte.
+Without --output the bundle is written to stdout.
+
+Examples:
+  thrum context export --output coordinator.ctx.md
+  thrum context export --agent coordinator > coordinator.ctx.md`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			agentID, err := resolveLocalAgentID()
+			if err != nil && flagAgent == "" {
+				return fmt.Errorf("failed to resolve agent identity: %w", err)
+			}
+			if flagAgent != "" {
+				agentID = flagAgent
+			}
+
+			absRepo, _ := filepath.Abs(flagRepo)
+
+			client, err := getClient()
+			if err != nil {
+				return fmt.Errorf("connect to daemon: %w", err)
+			}
+			defer func() { _ = client.Close() }()
+
+			includePreamble := true
+			var resp rpc.ContextShowResponse
+			if err := client.Call("context.show", rpc.ContextShowRequest{
+				AgentName:       agentID,
+				IncludePreamble: &includePreamble,
+				RepoPath:        absRepo,
+			}, &resp); err != nil {
+				return err
+			}
+			if !resp.HasContext && !resp.HasPreamble {
+				return fmt.Errorf("no context or preamble saved for %s", resp.AgentName)
+			}
+
+			bundle := &cli.ContextBundle{Agent: resp.AgentName}
+			if resp.HasPreamble {
+				bundle.Preamble = resp.Preamble
+			}
+			if resp.HasContext {
+				bundle.Context = resp.Content
+			}
+			data := cli.EncodeContextBundle(bundle)
+
+			if flagOutput == "" {
+				_, err := os.Stdout.Write(data)
+				return err
+			}
+			if err := os.WriteFile(flagOutput, data, 0o600); err != nil {
+				return fmt.Errorf("write bundle: %w", err)
+			}
+			if !flagQuiet {
+				fmt.Printf("Exported context for %s to %s (context %d bytes, preamble %d bytes)\n",
+					resp.AgentName, flagOutput, len(bundle.Context), len(bundle.Preamble))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&flagAgent, "agent", "", "Override agent name")
+	cmd.Flags().StringVar(&flagOutput, "output", "", "Write the bundle to this file (default: stdout)")
+
+	return cmd
+}
+
+func contextImportCmd() *cobra.Command {
+	var flagAgent string
+	var flagFile string
+	var flagForce bool
+
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Import a context bundle written by 'thrum context export'",
+		Long: `Load a bundle from 'thrum context export' into the current agent (or
+--agent NAME). The bundle's context replaces any saved context, as with
+'thrum context save'. Its preamble is only written when the agent has no
+preamble yet, or already has the same one; pass --force to replace a
+different existing preamble. Nothing is written if the preamble check fails.
+
+The agent named in the bundle is informational: the bundle is imported into
+the target agent whatever its name.
+
+Examples:
+  thrum context import --file coordinator.ctx.md
+  thrum context import --agent coordinator --file coordinator.ctx.md --force`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			agentID, err := resolveLocalAgentID()
+			if err != nil && flagAgent == "" {
+				return fmt.Errorf("failed to resolve agent identity: %w", err)
+			}
+			if flagAgent != "" {
+				agentID = flagAgent
+			}
+
+			data, err := os.ReadFile(flagFile) // #nosec G304 -- flagFile is user-specified via CLI flag; this is a CLI tool, user controls the path
+			if err != nil {
+				return fmt.Errorf("read bundle file: %w", err)
+			}
+			bundle, err := cli.ParseContextBundle(data)
+			if err != nil {
+				return err
+			}
+
+			absRepo, _ := filepath.Abs(flagRepo)
+
+			client, err := getClient()
+			if err != nil {
+				return fmt.Errorf("connect to daemon: %w", err)
+			}
+			defer func() { _ = client.Close() }()
+
+			// Check the preamble before writing anything: context.save
+			// creates a default preamble when the agent has none.
+			writePreamble := bundle.Preamble != nil
+			if writePreamble {
+				var existing rpc.PreambleShowResponse
+				if err := client.Call("context.preamble.show", rpc.PreambleShowRequest{
+					AgentName: agentID,
+					RepoPath:  absRepo,
+				}, &existing); err != nil {
+					return err
+				}
+				if existing.HasPreamble {
+					same := bytes.Equal(existing.Content, bundle.Preamble)
+					if !same && !flagForce {
+						return fmt.Errorf("%s already has a different preamble; use --force to replace it", agentID)
+					}
+					writePreamble = !same
+				}
+			}
+
+			if bundle.Context != nil {
+				var resp rpc.ContextSaveResponse
+				if err := client.Call("context.save", rpc.ContextSaveRequest{
+					AgentName: agentID,
+					Content:   bundle.Context,
+					RepoPath:  absRepo,
+				}, &resp); err != nil {
+					return err
+				}
+				fmt.Println(resp.Message)
+			}
+			if writePreamble {
+				var resp rpc.PreambleSaveResponse
+				if err := client.Call("context.preamble.save", rpc.PreambleSaveRequest{
+					AgentName: agentID,
+					Content:   bundle.Preamble,
 					RepoPath:  absRepo,
 				}, &resp); err != nil {
 					return err
 				}
 				fmt.Println(resp.Message)
-				return nil
-			}
-
-			// Show current preamble
-			var resp rpc.PreambleShowResponse
-			if err := client.Call("context.preamble.show", rpc.PreambleShowRequest{
-				AgentName: agentID,
-				RepoPath:  absRepo,
-			}, &resp); err != nil {
-				return err
-			}
-
-			if !resp.HasPreamble {
-				fmt.Printf("No preamble for %s (use --init to create default)\n", resp.AgentName)
-				return nil
+			} else if bundle.Preamble != nil {
+				fmt.Printf("Preamble for %s unchanged\n", agentID)
 			}
-
-			fmt.Print(string(resp.Content))
 			return nil
 		},
 	}
 
 	cmd.Flags().StringVar(&flagAgent, "agent", "", "Override agent name")
-	cmd.Flags().BoolVar(&flagInit, "init", false, "Create or reset to default preamble")
-	cmd.Flags().StringVar(&flagFile, "file", "", "Set preamble from file")
+	cmd.Flags().StringVar(&flagFile, "file", "", "Bundle file to import (required)")
+	cmd.Flags().BoolVar(&flagForce, "force", false, "Replace an existing, different preamble")
+	_ = cmd.MarkFlagRequired("file")
 
 	return cmd
 }
@@ -4664,7 +7739,7 @@ func loadPrimeOwnershipMode(repoPath string) guard.Mode {
 }
 
 func primeCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "prime",
 		Short: "Gather session context for agent initialization",
 		Long: `Collect all context needed for agent session initialization or recovery.
@@ -4675,10 +7750,23 @@ SessionStart and PreCompact to re-prime agent context.
 
 Gracefully degrades if daemon is not running.
 
+--budget N keeps the output to about N tokens (4 characters each) by
+trimming lower-priority sections first: the uncommitted file list, the
+team list, read inbox messages, the messaging protocol, project state,
+agent instructions, and saved session context. Identity, unread
+messages, and a restart snapshot are always kept. A notice at the top
+lists what was trimmed. JSON output is never trimmed.
+
 Examples:
-  thrum prime          # Human-readable summary
-  thrum prime --json   # Structured JSON output`,
+  thrum prime              # Human-readable summary
+  thrum prime --budget 2000
+  thrum prime --json       # Structured JSON output`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			budget, _ := cmd.Flags().GetInt("budget")
+			if budget < 0 {
+				return fmt.Errorf("--budget must be positive")
+			}
+
 			client, err := getClient()
 			if err != nil {
 				// Graceful degradation: output helpful message instead of error
@@ -4741,6 +7829,7 @@ Examples:
 			}
 
 			result := cli.ContextPrime(client, agentID)
+			result.Budget = budget
 
 			// Wire SingleAgentMode from config
 			if result.RepoPath != "" {
@@ -4803,6 +7892,10 @@ Examples:
 			return nil
 		},
 	}
+
+	cmd.Flags().Int("budget", 0, "Trim lower-priority sections to fit about N tokens (0 = no limit)")
+
+	return cmd
 }
 
 func runtimeGroupCmd() *cobra.Command {
@@ -4871,9 +7964,59 @@ defaults. Use these commands to list, inspect, and configure runtimes.`,
 		},
 	}
 
+	// thrum runtime generate <name>
+	generateCmd := &cobra.Command{
+		Use:   "generate <name>",
+		Short: "Write a runtime's config files into this repo",
+		Long: `Write the config files for runtime <name> into an already-initialized
+repo — the same files 'thrum init --runtime <name>' generates — without
+re-running init. Use it to add Codex configs to a repo set up for Claude.
+
+Files that already exist are listed as skipped unless --force is given.
+Thrum-managed scripts are always refreshed.
+
+Examples:
+  thrum runtime generate codex --dry-run
+  thrum runtime generate codex
+  thrum runtime generate cursor --force`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			force, _ := cmd.Flags().GetBool("force")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+			if !runtime.IsValidRuntime(args[0]) {
+				return fmt.Errorf("unknown runtime %q; supported: %s", args[0], strings.Join(runtime.SupportedRuntimes(), ", "))
+			}
+			if _, err := os.Stat(filepath.Join(flagRepo, ".thrum")); os.IsNotExist(err) {
+				return fmt.Errorf("thrum not initialized in this repository\n  Run 'thrum init --runtime %s' instead", args[0])
+			}
+
+			result, err := cli.RuntimeInit(cli.RuntimeInitOptions{
+				RepoPath: flagRepo,
+				Runtime:  args[0],
+				DryRun:   dryRun,
+				Force:    force,
+			})
+			if err != nil {
+				return err
+			}
+
+			if flagJSON {
+				return cli.EmitJSON(result)
+			}
+			if !flagQuiet {
+				fmt.Print(cli.FormatRuntimeInit(result))
+			}
+			return nil
+		},
+	}
+	generateCmd.Flags().Bool("force", false, "Overwrite existing config files")
+	generateCmd.Flags().Bool("dry-run", false, "List the files that would be written without writing them")
+
 	cmd.AddCommand(listCmd)
 	cmd.AddCommand(showCmd)
 	cmd.AddCommand(setDefaultCmd)
+	cmd.AddCommand(generateCmd)
 
 	return cmd
 }
@@ -5021,19 +8164,57 @@ func syncCmd() *cobra.Command {
 	})
 
 	cmd.AddCommand(&cobra.Command{
+		Use:   "log",
+		Short: "Show recent sync attempts",
+		Long: `List the most recent sync attempts, newest first, with the number of
+remote events fetched, the lines pushed, and any error.
+
+The log is kept in daemon memory only. It covers both forced and
+write-triggered syncs, but it is cleared when the daemon restarts.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := getClient()
+			if err != nil {
+				return fmt.Errorf("failed to connect to daemon: %w", err)
+			}
+			defer func() { _ = client.Close() }()
+
+			result, err := cli.SyncLog(client)
+			if err != nil {
+				return err
+			}
+
+			if flagJSON {
+				return cli.EmitJSON(result)
+			}
+			fmt.Print(cli.FormatSyncLog(result))
+			return nil
+		},
+	})
+
+	forceCmd := &cobra.Command{
 		Use:   "force",
 		Short: "Force immediate sync",
 		Long: `Trigger an immediate sync operation (non-blocking).
 
-This will fetch new messages from the remote and push local messages.`,
+This will fetch new messages from the remote and push local messages.
+
+Use --push-only to push local messages without pulling, or --pull-only to
+pull remote messages without pushing local state (e.g. during a recovery).
+
+While sync is paused (thrum sync pause) this fails; pass
+--force-even-if-paused to run one sync cycle without lifting the pause.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			pushOnly, _ := cmd.Flags().GetBool("push-only")
+			pullOnly, _ := cmd.Flags().GetBool("pull-only")
+			evenIfPaused, _ := cmd.Flags().GetBool("force-even-if-paused")
+
 			client, err := getClient()
 			if err != nil {
 				return fmt.Errorf("failed to connect to daemon: %w", err)
 			}
 			defer func() { _ = client.Close() }()
 
-			result, err := cli.SyncForce(client)
+			result, err := cli.SyncForce(client, pushOnly, pullOnly, evenIfPaused)
 			if err != nil {
 				return err
 			}
@@ -5044,6 +8225,74 @@ This will fetch new messages from the remote and push local messages.`,
 			fmt.Print(cli.FormatSyncForce(result))
 			return nil
 		},
+	}
+	forceCmd.Flags().Bool("push-only", false, "Push local messages without fetching the remote")
+	forceCmd.Flags().Bool("pull-only", false, "Pull remote messages without pushing local state")
+	forceCmd.Flags().Bool("force-even-if-paused", false, "Run one sync cycle even though sync is paused")
+	forceCmd.MarkFlagsMutuallyExclusive("push-only", "pull-only")
+	cmd.AddCommand(forceCmd)
+
+	pauseCmd := &cobra.Command{
+		Use:   "pause",
+		Short: "Pause git sync",
+		Long: `Stop the daemon from running git fetch/push for sync. The sync loop
+stays alive and local messaging keeps working; changes are synced when
+sync resumes.
+
+Without --duration the pause lasts until 'thrum sync resume' or a daemon
+restart. With --duration sync resumes on its own after that long.
+
+Examples:
+  thrum sync pause
+  thrum sync pause --duration 10m`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			duration, _ := cmd.Flags().GetString("duration")
+
+			client, err := getClient()
+			if err != nil {
+				return fmt.Errorf("failed to connect to daemon: %w", err)
+			}
+			defer func() { _ = client.Close() }()
+
+			result, err := cli.SyncPause(client, duration)
+			if err != nil {
+				return err
+			}
+
+			if flagJSON {
+				return cli.EmitJSON(result)
+			}
+			fmt.Print(cli.FormatSyncPause(result))
+			return nil
+		},
+	}
+	pauseCmd.Flags().String("duration", "", "Resume automatically after this long (e.g. 10m, 1h)")
+	cmd.AddCommand(pauseCmd)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "resume",
+		Short: "Resume paused git sync",
+		Long:  `Lift a pause set by 'thrum sync pause' and run a catch-up sync right away.`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := getClient()
+			if err != nil {
+				return fmt.Errorf("failed to connect to daemon: %w", err)
+			}
+			defer func() { _ = client.Close() }()
+
+			result, err := cli.SyncResume(client)
+			if err != nil {
+				return err
+			}
+
+			if flagJSON {
+				return cli.EmitJSON(result)
+			}
+			fmt.Print(cli.FormatSyncPause(result))
+			return nil
+		},
 	})
 
 	return cmd
@@ -5438,7 +8687,12 @@ func fetchTeam(cmd *cobra.Command) (*cli.TeamListResponse, error) {
 // human reading the table footer) to misread the team-wide numbers as scoped to
 // the filter — equally misleading whether the filtered set is empty or not. The
 // unfiltered `thrum team` is the only view that surfaces the shared block.
-func emitFilteredTeam(members []cli.TeamMember, kind, value string) error {
+//
+// With --flat the members are emitted as cli.FlattenTeam records instead.
+func emitFilteredTeam(members []cli.TeamMember, kind, value string, flat bool) error {
+	if flat {
+		return cli.EmitJSON(cli.FlattenTeam(members))
+	}
 	filtered := &cli.TeamListResponse{Members: members}
 	if flagJSON {
 		return cli.EmitJSON(filtered)
@@ -5463,11 +8717,16 @@ and per-file change details for all agents with active sessions.
 Filter by the daemon that owns each agent or by host, or use the
 'local' / 'daemons' subviews. Every form honors --json.
 
+--flat prints a flat JSON array instead, one record per agent with
+agent, role, module, online, unread, branch and intent. Fields are never
+omitted: offline agents (with --all) carry false/0/null. Implies --json.
+
 Examples:
   thrum team
   thrum team --all
   thrum team --system
   thrum team --json
+  thrum team --json --flat --all | jq '.[] | select(.unread > 0)'
   thrum team --daemon <daemon-id>   # agents owned by one daemon
   thrum team --host <hostname>      # agents on one host
   thrum team local                  # agents on THIS repo's daemon
@@ -5485,6 +8744,7 @@ Examples:
 			hostname, _ := cmd.Flags().GetString("host")
 			daemonSet := cmd.Flags().Changed("daemon")
 			hostSet := cmd.Flags().Changed("host")
+			flat, _ := cmd.Flags().GetBool("flat")
 			if daemonSet && hostSet {
 				return fmt.Errorf("thrum team: --daemon and --host are mutually exclusive")
 			}
@@ -5496,11 +8756,14 @@ Examples:
 
 			switch {
 			case daemonSet:
-				return emitFilteredTeam(cli.FilterByDaemon(result.Members, daemonID), "daemon", daemonID)
+				return emitFilteredTeam(cli.FilterByDaemon(result.Members, daemonID), "daemon", daemonID, flat)
 			case hostSet:
-				return emitFilteredTeam(cli.FilterByHost(result.Members, hostname), "host", hostname)
+				return emitFilteredTeam(cli.FilterByHost(result.Members, hostname), "host", hostname, flat)
 			}
 
+			if flat {
+				return cli.EmitJSON(cli.FlattenTeam(result.Members))
+			}
 			if flagJSON {
 				return cli.EmitJSON(result)
 			}
@@ -5513,6 +8776,7 @@ Examples:
 	cmd.Flags().Bool("system", false, "Include reserved pseudo-agents (@supervisor_*, etc.)")
 	cmd.Flags().String("daemon", "", "Only show agents whose origin_daemon matches this daemon id")
 	cmd.Flags().String("host", "", "Only show agents on this hostname")
+	cmd.Flags().Bool("flat", false, "Emit a flat JSON array of agent records (implies --json)")
 
 	cmd.AddCommand(teamLocalCmd())
 	cmd.AddCommand(teamDaemonsCmd())
@@ -5546,11 +8810,13 @@ daemon id the same way 'thrum daemon status' does. Honors --json.`,
 			if err != nil {
 				return err
 			}
-			return emitFilteredTeam(cli.FilterByDaemon(result.Members, selfDaemon), "daemon", selfDaemon)
+			flat, _ := cmd.Flags().GetBool("flat")
+			return emitFilteredTeam(cli.FilterByDaemon(result.Members, selfDaemon), "daemon", selfDaemon, flat)
 		},
 	}
 	cmd.Flags().Bool("all", false, "Include offline agents")
 	cmd.Flags().Bool("system", false, "Include reserved pseudo-agents (@supervisor_*, etc.)")
+	cmd.Flags().Bool("flat", false, "Emit a flat JSON array of agent records (implies --json)")
 	return cmd
 }
 
@@ -5592,7 +8858,9 @@ func whoHasCmd() *cobra.Command {
 		Long: `Check which agents are currently editing a file.
 
 Shows agents with the file in their uncommitted changes or changed files,
-along with branch and change count information.
+along with branch and change count information. Files an agent declared with
+'thrum session start --files' are included and marked [declared], even
+before the agent has changed them.
 
 Examples:
   thrum who-has auth.go
@@ -5624,6 +8892,52 @@ Examples:
 	}
 }
 
+func whoCanCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "who-can CAPABILITY",
+		Short: "List agents that advertise a capability",
+		Long: `List the agents advertising CAPABILITY, with their online status. Agents
+declare capabilities with 'thrum agent register --capability' or
+'thrum agent set-capabilities'. Where who-has answers "who is touching this
+file", who-can answers "who can take this kind of work".
+
+Examples:
+  thrum who-can go
+  thrum who-can security --json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			capability := strings.ToLower(strings.TrimSpace(args[0]))
+
+			client, err := getClient()
+			if err != nil {
+				return fmt.Errorf("failed to connect to daemon: %w", err)
+			}
+			defer func() { _ = client.Close() }()
+
+			agents, err := cli.AgentList(client, cli.AgentListOptions{Capability: capability})
+			if err != nil {
+				return err
+			}
+
+			// Contexts supply online status; without them everyone shows offline.
+			contexts, err := cli.AgentListContext(client, "", "", "")
+			if err != nil {
+				contexts = nil
+			}
+
+			if flagJSON {
+				return cli.EmitJSON(map[string]any{
+					"capability": capability,
+					"agents":     agents.Agents,
+					"contexts":   contexts,
+				})
+			}
+			fmt.Print(cli.FormatWhoCan(capability, agents, contexts))
+			return nil
+		},
+	}
+}
+
 func pingCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "ping AGENT",
@@ -5633,7 +8947,8 @@ func pingCmd() *cobra.Command {
 Shows whether the agent is active or offline, along with their current
 intent, task, and branch information if active.
 
-The agent can be specified with or without the @ prefix.
+The agent can be specified with or without the @ prefix. Aliases set with
+'thrum agent alias set' work too.
 
 Examples:
   thrum ping @reviewer
@@ -5705,6 +9020,14 @@ func sessionHeartbeatRunE(cmd *cobra.Command, args []string) error {
 	opts := cli.HeartbeatOptions{
 		SessionID: whoami.SessionID,
 	}
+	if cmd.Flags().Changed("intent") {
+		intent, _ := cmd.Flags().GetString("intent")
+		opts.Intent = &intent
+	}
+	if cmd.Flags().Changed("task") {
+		task, _ := cmd.Flags().GetString("task")
+		opts.CurrentTask = &task
+	}
 
 	// Parse scopes (type:value format)
 	for _, s := range addScopes {
@@ -6020,21 +9343,7 @@ func getClientNoRefresh() (*cli.Client, error) {
 // that footgun while keeping env as a legitimate fallback for callers outside
 // any worktree.
 func resolveLocalAgentID() (string, error) {
-	cfg, err := config.LoadWithPath(flagRepo, flagRole, flagModule)
-	if err == nil && cfg.Agent.Name != "" {
-		// For named agents, GenerateAgentID returns the name directly.
-		// For unnamed agents, it generates a deterministic hash-based ID.
-		return identity.GenerateAgentID(cfg.RepoID, cfg.Agent.Role, cfg.Agent.Module, cfg.Agent.Name), nil
-	}
-
-	if agentID := strings.TrimSpace(os.Getenv("THRUM_AGENT_ID")); agentID != "" {
-		return agentID, nil
-	}
-
-	if err != nil {
-		return "", err
-	}
-	return "", fmt.Errorf("no agent name in config and THRUM_AGENT_ID env var not set")
+	return cli.ResolveAgentID(flagRepo, flagRole, flagModule)
 }
 
 // resolveLocalMentionRole resolves the agent's role from the local worktree's identity file.
@@ -6159,7 +9468,11 @@ func resolveBootExposureGate(ctx context.Context, cfg *config.ThrumConfig, deps
 }
 
 // runDaemon runs the daemon server in the foreground.
-func runDaemon(repoPath string, flagLocal bool, flagForce bool) error {
+// runDaemon runs the daemon in the current process until Lifecycle sees
+// SIGTERM/SIGINT. foreground also copies the log to stdout for
+// 'daemon start --foreground'; the forked 'daemon run' child logs to the
+// file alone.
+func runDaemon(repoPath string, flagLocal bool, flagForce bool, flagLogLevel string, flagSocket string, flagWSPort int, foreground bool) error {
 	// Profile instrumentation gate (thrum-bpq5 substrate). Reads
 	// THRUM_PROFILE env at start; default off (no perf cost). Set to "1"
 	// before launching the daemon to surface per-phase slog timing.
@@ -6208,8 +9521,12 @@ func runDaemon(repoPath string, flagLocal bool, flagForce bool) error {
 	// Install rotating log writer as early as possible so every subsequent
 	// log.Printf in daemon startup is captured. lumberjack rotates the file
 	// when it exceeds 10MB and keeps 4 compressed backups for 28 days.
-	logWriter := daemon.NewLogWriter(varDir)
-	defer func() { _ = logWriter.Close() }()
+	fileLog := daemon.NewLogWriter(varDir)
+	defer func() { _ = fileLog.Close() }()
+	var logWriter io.Writer = fileLog
+	if foreground {
+		logWriter = io.MultiWriter(os.Stdout, fileLog)
+	}
 	daemon.InstallLogWriter(logWriter)
 	log.Printf("daemon: starting version=%s repo=%s", Version+"+"+Build, absPath)
 
@@ -6234,7 +9551,7 @@ func runDaemon(repoPath string, flagLocal bool, flagForce bool) error {
 	// correct here, because reference files are binary-version content, not
 	// per-checkout data like identities.
 	if err := agentcontext.WriteStrategies(thrumDir); err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: failed to refresh embedded reference files: %v\n", err)
+		slog.Warn("[daemon] refresh embedded reference files failed", "err", err)
 	}
 
 	// Generate repo ID (use directory name for now)
@@ -6244,7 +9561,7 @@ func runDaemon(repoPath string, flagLocal bool, flagForce bool) error {
 	peersFile := filepath.Join(varDir, "peers.json")
 	peerRegistry, err := daemon.NewPeerRegistry(peersFile)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: failed to create peer registry: %v\n", err)
+		slog.Warn("[daemon] create peer registry failed", "err", err)
 	}
 
 	// Create state manager. Passing empty daemonID so state.NewState calls
@@ -6258,25 +9575,18 @@ func runDaemon(repoPath string, flagLocal bool, flagForce bool) error {
 	}
 	defer func() { _ = st.Close() }()
 
-	// Run initial cleanup of stale work contexts
-	if deleted, err := cleanup.CleanupStaleContexts(context.Background(), st.DB(), time.Now().UTC()); err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: cleanup failed: %v\n", err)
-	} else if deleted > 0 {
-		fmt.Fprintf(os.Stderr, "Cleaned up %d stale work context(s)\n", deleted)
-	}
-
 	// EnsureEveryoneGroup removed — @everyone is now a direct broadcast
 	// (scope_type='broadcast'), not a group. Fixes cross-repo sync leak.
 
 	// Validate sync worktree exists
 	if _, err := os.Stat(syncDir); os.IsNotExist(err) {
-		fmt.Fprintf(os.Stderr, "Warning: sync worktree not found at %s (sync disabled)\n", syncDir)
+		slog.Warn("[sync] sync worktree not found, sync disabled", "path", syncDir)
 	}
 
 	// Load config.json (used for local-only, WS port)
 	thrumCfg, cfgErr := config.LoadThrumConfig(thrumDir)
 	if cfgErr != nil {
-		fmt.Fprintf(os.Stderr, "Warning: failed to read config.json: %v\n", cfgErr)
+		slog.Warn("[config] read config.json failed", "err", cfgErr)
 		thrumCfg = &config.ThrumConfig{
 			Daemon: config.DaemonConfig{
 				WSPort:   config.DefaultWSPort,
@@ -6287,17 +9597,21 @@ func runDaemon(repoPath string, flagLocal bool, flagForce bool) error {
 
 	// Configure slog with the resolved log level so any subsequent calls
 	// to slog.Info/Debug/Warn/Error respect the user's configured threshold.
+	// Resolution: --log-level flag > THRUM_LOG_LEVEL env > config.json.
 	// Log.Printf calls continue to write unconditionally through the
 	// lumberjack writer for backward compatibility.
-	daemon.ConfigureSlog(logWriter, thrumCfg.Daemon.LogLevel)
-	log.Printf("daemon: log level=%s", thrumCfg.Daemon.LogLevel)
+	logLevel, err := daemon.ResolveLogLevel(flagLogLevel, thrumCfg.Daemon.LogLevel)
+	if err != nil {
+		return err
+	}
+	daemon.ConfigureSlog(logWriter, logLevel)
+	log.Printf("daemon: log level=%s", logLevel)
 
 	// Validate permission_supervisors invariant: the array is authoritative
 	// routing for permission-prompt nudges (thrum-zmsk). If an operator
 	// sets the array but forgets a coordinator-role recipient, prompts
 	// can land in dead mailboxes — warn loudly so they see it on boot.
 	if warn := config.ValidatePermissionSupervisors(thrumCfg.PermissionSupervisors); warn != "" {
-		fmt.Fprintf(os.Stderr, "Warning: %s\n", warn)
 		slog.Warn("[config] permission_supervisors validation", "issue", warn)
 	}
 
@@ -6317,7 +9631,7 @@ func runDaemon(repoPath string, flagLocal bool, flagForce bool) error {
 	if localOnlyFromExplicit {
 		thrumCfg.Daemon.LocalOnly = true
 		if err := config.SaveThrumConfig(thrumDir, thrumCfg); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to save config.json: %v\n", err)
+			slog.Warn("[config] save config.json failed", "err", err)
 		}
 	}
 	if localOnly {
@@ -6364,10 +9678,26 @@ func runDaemon(repoPath string, flagLocal bool, flagForce bool) error {
 		log.Printf("daemon: permission found %d pending nudge(s) still in flight", len(rows))
 	}
 
+	// runExposureGate resolves the a-sync exposure gate against cfg. Used at
+	// boot and again by daemon reload before remote sync is switched back on.
+	runExposureGate := func(c context.Context, cfg *config.ThrumConfig) exposureGateOutcome {
+		originURL, _ := safecmd.Git(c, absPath, "remote", "get-url", "origin")
+		return resolveBootExposureGate(c, cfg, exposureGateDeps{
+			originURL: string(originURL),
+			prober: func(c context.Context, probeURL string) thrumSync.Visibility {
+				out, perr := safecmd.GitProbeAnonymous(c, probeURL)
+				return thrumSync.ClassifyVisibility(out, perr)
+			},
+			saveConfig: func(c *config.ThrumConfig) error { return config.SaveThrumConfig(thrumDir, c) },
+			warn:       func(canonRemote string) { emitExposureWarning(c, permPkg, st, cfg, canonRemote) },
+		})
+	}
+
 	// Create sync loop for event-triggered git sync
 	ctx := context.Background()
 	var syncLoop *thrumSync.SyncLoop
 	var pendingPool *syncPending.Pool // thrum-s6os: nil when syncDir is absent
+	var compactor *syncCompact.Compactor
 	if _, err := os.Stat(syncDir); err == nil {
 		// thrum-44mt: resolve the a-sync exposure gate once at boot. syncDir
 		// existing ⇒ a-sync is a configured mechanism (peer/email-only users
@@ -6379,16 +9709,7 @@ func runDaemon(repoPath string, flagLocal bool, flagForce bool) error {
 		// outcome.
 		var exposureReason string
 		if !localOnly { // an explicit --local / THRUM_LOCAL already disabled remote sync
-			originURL, _ := safecmd.Git(ctx, absPath, "remote", "get-url", "origin")
-			outcome := resolveBootExposureGate(ctx, thrumCfg, exposureGateDeps{
-				originURL: string(originURL),
-				prober: func(c context.Context, probeURL string) thrumSync.Visibility {
-					out, perr := safecmd.GitProbeAnonymous(c, probeURL)
-					return thrumSync.ClassifyVisibility(out, perr)
-				},
-				saveConfig: func(c *config.ThrumConfig) error { return config.SaveThrumConfig(thrumDir, c) },
-				warn:       func(canonRemote string) { emitExposureWarning(ctx, permPkg, st, thrumCfg, canonRemote) },
-			})
+			outcome := runExposureGate(ctx, thrumCfg)
 			if outcome.LocalOnly {
 				localOnly = true
 			}
@@ -6396,6 +9717,7 @@ func runDaemon(repoPath string, flagLocal bool, flagForce bool) error {
 		}
 
 		syncer := thrumSync.NewSyncer(absPath, syncDir, localOnly)
+		syncer.SetPushRetry(thrumCfg.Daemon.SyncPushRetriesEffective(), thrumCfg.Daemon.SyncPushRetryDelayEffective())
 		syncLoop = thrumSync.NewSyncLoop(syncer, st.Projector(), absPath, syncDir, thrumDir, localOnly)
 		// Route synced events through State.IngestSyncedEvent so the
 		// event-write hook fires on cross-repo ingest, not just local
@@ -6481,7 +9803,7 @@ func runDaemon(repoPath string, flagLocal bool, flagForce bool) error {
 			log.Printf("sync: bootstrap-ingested %d legacy events from sync worktree", rows)
 		}
 
-		compactor := syncCompact.New(thrumDir, syncDir,
+		compactor = syncCompact.New(thrumDir, syncDir,
 			thrumCfg.Daemon.EventsRetentionDays,
 			thrumCfg.Daemon.CompactionSizeThresholdMB)
 		if err := compactor.CompactAll(ctx, st.DB()); err != nil {
@@ -6512,14 +9834,21 @@ func runDaemon(repoPath string, flagLocal bool, flagForce bool) error {
 		st.Projector().SetPendingResolver(projResolver)
 
 		if err := syncLoop.Start(ctx); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to start sync loop: %v\n", err)
+			slog.Warn("[sync] start sync loop failed", "err", err)
 		} else {
 			defer func() { _ = syncLoop.Stop() }()
 		}
 	}
 
 	// Create Unix socket server
+	// --socket moves it out of .thrum/var; the PID file records the path
+	// so status, stop, and clients still find it.
 	socketPath := filepath.Join(varDir, "thrum.sock")
+	if flagSocket != "" {
+		if socketPath, err = filepath.Abs(flagSocket); err != nil {
+			return fmt.Errorf("failed to resolve socket path: %w", err)
+		}
+	}
 	server := daemon.NewServer(socketPath)
 
 	// Wire the peer-credential identity resolver into the server. The
@@ -6532,6 +9861,14 @@ func runDaemon(repoPath string, flagLocal bool, flagForce bool) error {
 	identityResolver := peercred.NewResolver(identityLister)
 	server.SetIdentityResolver(identityResolver)
 
+	// RPC calls by method for the metrics endpoint, counted by both the Unix
+	// socket and WebSocket servers. Only wired when metrics are enabled.
+	var rpcCalls *metrics.CounterVec
+	if thrumCfg.Daemon.MetricsEnabled {
+		rpcCalls = &metrics.CounterVec{}
+		server.SetCallObserver(rpcCalls.Inc)
+	}
+
 	// Create subscription dispatcher
 	dispatcher := subscriptions.NewDispatcher(st.DB())
 
@@ -6567,13 +9904,26 @@ func runDaemon(repoPath string, flagLocal bool, flagForce bool) error {
 		return rpc.DeriveSyncState(s), s.LocalOnly, s.LocalOnlyReason
 	})
 
+	// Presence: agents silent past daemon.idle_threshold show as "away" in
+	// team.list, agent.lookup and agent.listContext. Shared so daemon
+	// reload can change it in place.
+	idleThreshold := &rpc.IdleThreshold{}
+	idleThreshold.Set(thrumCfg.Daemon.IdleThresholdEffective())
+
 	// Agent management
 	agentHandler := rpc.NewAgentHandler(st)
+	agentHandler.SetIdleThreshold(idleThreshold)
 	server.RegisterHandler("agent.register", agentHandler.HandleRegister)
 	server.RegisterHandler("agent.list", agentHandler.HandleList)
 	server.RegisterHandler("agent.whoami", agentHandler.HandleWhoami)
 	server.RegisterHandler("agent.listContext", agentHandler.HandleListContext)
 	server.RegisterHandler("agent.delete", agentHandler.HandleDelete)
+	server.RegisterHandler("agent.alias.set", agentHandler.HandleAliasSet)
+	server.RegisterHandler("agent.alias.remove", agentHandler.HandleAliasRemove)
+	server.RegisterHandler("agent.capabilities.set", agentHandler.HandleCapabilitiesSet)
+	server.RegisterHandler("agent.mute", agentHandler.HandleMute)
+	server.RegisterHandler("agent.unmute", agentHandler.HandleUnmute)
+	server.RegisterHandler("agent.rename", agentHandler.HandleRename)
 	server.RegisterHandler("agent.cleanup", agentHandler.HandleCleanup)
 	server.RegisterHandler("agent.set-status", agentHandler.HandleSetAgentStatus)
 
@@ -6582,10 +9932,12 @@ func runDaemon(repoPath string, flagLocal bool, flagForce bool) error {
 	// instead of team.list so the hot path does not amortize the full
 	// team-list build per send.
 	agentLookupHandler := rpc.NewAgentLookupHandler(st)
+	agentLookupHandler.SetIdleThreshold(idleThreshold)
 	server.RegisterHandler("agent.lookup", agentLookupHandler.HandleLookup)
 
 	// Team management
 	teamHandler := rpc.NewTeamHandler(st, thrumDir, supervisorIdentity)
+	teamHandler.SetIdleThreshold(idleThreshold)
 	server.RegisterHandler("team.list", teamHandler.HandleList)
 
 	// Context management
@@ -6598,17 +9950,23 @@ func runDaemon(repoPath string, flagLocal bool, flagForce bool) error {
 
 	// Session management
 	sessionHandler := rpc.NewSessionHandler(st)
+	sessionHandler.SetDispatcher(dispatcher) // notification.context.updated for agent context --watch
 	server.RegisterHandler("session.start", sessionHandler.HandleStart)
 	server.RegisterHandler("session.end", sessionHandler.HandleEnd)
+	server.RegisterHandler("session.resume", sessionHandler.HandleResume)
 	server.RegisterHandler("session.list", sessionHandler.HandleList)
 	server.RegisterHandler("session.heartbeat", sessionHandler.HandleHeartbeat)
 	server.RegisterHandler("session.setIntent", sessionHandler.HandleSetIntent)
 	server.RegisterHandler("session.setTask", sessionHandler.HandleSetTask)
+	server.RegisterHandler("session.transfer", sessionHandler.HandleTransfer)
 
 	// Group management
 	groupHandler := rpc.NewGroupHandler(st)
+	groupHandler.SetIdleThreshold(idleThreshold)
 	server.RegisterHandler("group.create", groupHandler.HandleCreate)
 	server.RegisterHandler("group.delete", groupHandler.HandleDelete)
+	server.RegisterHandler("group.rename", groupHandler.HandleRename)
+	server.RegisterHandler("group.copy", groupHandler.HandleCopy)
 	server.RegisterHandler("group.member.add", groupHandler.HandleMemberAdd)
 	server.RegisterHandler("group.member.remove", groupHandler.HandleMemberRemove)
 	server.RegisterHandler("group.list", groupHandler.HandleList)
@@ -6617,16 +9975,41 @@ func runDaemon(repoPath string, flagLocal bool, flagForce bool) error {
 
 	// Message management
 	messageHandler := rpc.NewMessageHandlerWithDispatcher(st, dispatcher, thrumDir, supervisorID, legacySupervisorID, thrumCfg.Daemon.MaxMessageBodyBytesEffective())
+	// Per-agent send throttle (daemon.send_rate_limit_*), off by default.
+	// The same handler serves socket and WebSocket, so both share the
+	// buckets; daemon reload updates the limits in place.
+	sendLimiter := &rpc.SendRateLimiter{}
+	sendLimiter.Set(thrumCfg.Daemon.SendRateLimitEffective())
+	messageHandler.SetSendRateLimiter(sendLimiter)
 	server.RegisterHandler("message.send", messageHandler.HandleSend)
+	server.RegisterHandler("message.resolve", messageHandler.HandleResolve)
 	server.RegisterHandler("message.get", messageHandler.HandleGet)
 	server.RegisterHandler("message.list", messageHandler.HandleList)
+	server.RegisterHandler("message.search", messageHandler.HandleSearch)
+	server.RegisterHandler("thread.get", messageHandler.HandleThreadGet)
 	server.RegisterHandler("message.outbox", messageHandler.HandleOutbox)
 	server.RegisterHandler("message.delete", messageHandler.HandleDelete)
+	server.RegisterHandler("message.cancel", messageHandler.HandleCancel)
 	server.RegisterHandler("message.edit", messageHandler.HandleEdit)
+	server.RegisterHandler("message.history", messageHandler.HandleHistory)
+	server.RegisterHandler("message.readers", messageHandler.HandleReaders)
+	server.RegisterHandler("message.forward", messageHandler.HandleForward)
+	server.RegisterHandler("message.react", messageHandler.HandleReact)
+	server.RegisterHandler("message.pin", messageHandler.HandlePin)
+	server.RegisterHandler("message.unpin", messageHandler.HandleUnpin)
+	server.RegisterHandler("message.move", messageHandler.HandleMove)
+	server.RegisterHandler("message.bump", messageHandler.HandleBump)
+	server.RegisterHandler("message.assign", messageHandler.HandleAssign)
+	server.RegisterHandler("message.complete", messageHandler.HandleComplete)
 	server.RegisterHandler("message.markRead", messageHandler.HandleMarkRead)
 	server.RegisterHandler("message.deleteByScope", messageHandler.HandleDeleteByScope)
 	server.RegisterHandler("message.deleteByAgent", messageHandler.HandleDeleteByAgent)
 	server.RegisterHandler("message.archive", messageHandler.HandleArchive)
+	server.RegisterHandler("message.import", messageHandler.HandleImport)
+	server.RegisterHandler("message.export", messageHandler.HandleExport)
+	// message.attachment returns a path on the daemon host, useful only to a
+	// local client, so like send --attach it stays off the WebSocket.
+	server.RegisterHandler("message.attachment", messageHandler.HandleAttachment)
 
 	// Monitor jobs — SECURITY: these handlers spawn child processes with the
 	// daemon's privileges, so they are registered on the unix-socket `server`
@@ -6653,11 +10036,18 @@ func runDaemon(repoPath string, flagLocal bool, flagForce bool) error {
 	// Sync management
 	var syncForceHandler *rpc.SyncForceHandler
 	var syncStatusHandler *rpc.SyncStatusHandler
+	var syncLogHandler *rpc.SyncLogHandler
+	var syncPauseHandler *rpc.SyncPauseHandler
 	if syncLoop != nil {
 		syncForceHandler = rpc.NewSyncForceHandler(syncLoop)
 		syncStatusHandler = rpc.NewSyncStatusHandler(syncLoop)
+		syncLogHandler = rpc.NewSyncLogHandler(syncLoop)
+		syncPauseHandler = rpc.NewSyncPauseHandler(syncLoop)
 		server.RegisterHandler("sync.force", syncForceHandler.Handle)
 		server.RegisterHandler("sync.status", syncStatusHandler.Handle)
+		server.RegisterHandler("sync.log", syncLogHandler.Handle)
+		server.RegisterHandler("sync.pause", syncPauseHandler.HandlePause)
+		server.RegisterHandler("sync.resume", syncPauseHandler.HandleResume)
 	}
 
 	// thrum-s6os v0.10.6: pending-pool diagnostics surface.
@@ -6755,31 +10145,12 @@ func runDaemon(repoPath string, flagLocal bool, flagForce bool) error {
 	// This fires on LOCAL writes only. The cross-repo path (events
 	// arriving via sync ingest) is bridged through IngestSyncedEvent
 	// in Task 6.3, which fires the same hook.
-	st.SetOnEventWrite(func(daemonID string, sequence int64, event []byte) {
-		if syncManager != nil {
-			go syncManager.BroadcastNotify(daemonID, sequence, 1)
-		}
-		// Cheap type-only unmarshal to filter non-message events
-		// BEFORE the larger MessageCreateEvent decode. The double
-		// unmarshal is intentional: the head check short-circuits
-		// hot paths (agent.register, session.start, etc.) without
-		// building a full MessageCreateEvent that would be
-		// immediately discarded. Do NOT "optimize" these into a
-		// single decode without verifying the non-message traffic
-		// volume on a busy daemon.
-		var head struct {
-			Type string `json:"type"`
-		}
-		if err := json.Unmarshal(event, &head); err != nil {
-			return
-		}
-		if head.Type != "message.create" {
-			return
-		}
-		var evt types.MessageCreateEvent
-		if err := json.Unmarshal(event, &evt); err != nil {
-			return
-		}
+	// releaseMessageCreate runs everything a new message triggers on this
+	// daemon: reply interception, the WebSocket broadcast, tmux nudges and
+	// inbox spool files. The event write hook below calls it for each
+	// message.create, except that a scheduled message (send --schedule)
+	// waits in messageScheduler until its deliver_at.
+	releaseMessageCreate := func(evt types.MessageCreateEvent) {
 		// Dispatch off the writer goroutine with a fresh context
 		// (the caller's ctx may be canceled by the time this runs)
 		// and a panic recover so a reply-dispatcher bug can't crash
@@ -6928,6 +10299,48 @@ func runDaemon(repoPath string, flagLocal bool, flagForce bool) error {
 				}
 			}
 		}(evt)
+	}
+	messageScheduler := daemon.NewMessageScheduler(st, func(ctx context.Context, evt types.MessageCreateEvent) {
+		messageHandler.ReleaseScheduled(ctx, evt)
+		releaseMessageCreate(evt)
+	})
+
+	st.SetOnEventWrite(func(daemonID string, sequence int64, event []byte) {
+		if syncManager != nil {
+			go syncManager.BroadcastNotify(daemonID, sequence, 1)
+		}
+		// Cheap type-only unmarshal to filter non-message events
+		// BEFORE the larger MessageCreateEvent decode. The double
+		// unmarshal is intentional: the head check short-circuits
+		// hot paths (agent.register, session.start, etc.) without
+		// building a full MessageCreateEvent that would be
+		// immediately discarded. Do NOT "optimize" these into a
+		// single decode without verifying the non-message traffic
+		// volume on a busy daemon.
+		var head struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal(event, &head); err != nil {
+			return
+		}
+		if head.Type != "message.create" {
+			return
+		}
+		var evt types.MessageCreateEvent
+		if err := json.Unmarshal(event, &evt); err != nil {
+			return
+		}
+		// Queue scheduled messages instead of notifying now. If the queue
+		// write fails, notify right away rather than never.
+		if evt.DeliverAt != "" {
+			err := messageScheduler.Enqueue(context.Background(), evt.MessageID, evt.DeliverAt, event)
+			if err == nil {
+				return
+			}
+			slog.Warn("[schedule] queue scheduled message failed; delivering now",
+				"msg_id", evt.MessageID, "deliver_at", evt.DeliverAt, "err", err)
+		}
+		releaseMessageCreate(evt)
 	})
 
 	// pairHandler is created once, registered on multiple WS registries:
@@ -6993,6 +10406,7 @@ func runDaemon(repoPath string, flagLocal bool, flagForce bool) error {
 		// both wsRegistry (for --type local loopback reach-back) and
 		// syncRegistry (for tsnet cross-host).
 		syncPullHandler = rpc.NewSyncPullHandler(st)
+		syncPullHandler.SetPullRecorder(peerRegistry)
 		syncPeerInfoHandler = rpc.NewPeerInfoHandler(st.DaemonID(), hostname)
 		syncNotifyHandler = rpc.NewSyncNotifyHandler(syncManager.SyncFromPeerByID)
 
@@ -7171,7 +10585,7 @@ func runDaemon(repoPath string, flagLocal bool, flagForce bool) error {
 				// it (fallback to Name) and sanitizes.
 				peer.ProxyPrefix = daemon.DeriveProxyPrefix(peer)
 				if updateErr := peerRegistry.AddPeer(peer); updateErr != nil {
-					fmt.Fprintf(os.Stderr, "[peer.join] warning: failed to update peer transport/role: %v\n", updateErr)
+					slog.Warn("[peer.join] update peer transport/role failed", "err", updateErr)
 				}
 				// thrum-1f4y: spawn the bridge for this new peer immediately;
 				// previously a daemon restart was required for ConnectAll to
@@ -7217,7 +10631,7 @@ func runDaemon(repoPath string, flagLocal bool, flagForce bool) error {
 				// it (fallback to Name) and sanitizes.
 				peer.ProxyPrefix = daemon.DeriveProxyPrefix(peer)
 				if updateErr := peerRegistry.AddPeer(peer); updateErr != nil {
-					fmt.Fprintf(os.Stderr, "[peer.join] warning: failed to update peer transport/role: %v\n", updateErr)
+					slog.Warn("[peer.join] update peer transport/role failed", "err", updateErr)
 				}
 				// thrum-1f4y: spawn the bridge for this new peer immediately;
 				// previously a daemon restart was required for ConnectAll to
@@ -7272,7 +10686,7 @@ func runDaemon(repoPath string, flagLocal bool, flagForce bool) error {
 				// it (fallback to Name) and sanitizes.
 				peer.ProxyPrefix = daemon.DeriveProxyPrefix(peer)
 				if updateErr := peerRegistry.AddPeer(peer); updateErr != nil {
-					fmt.Fprintf(os.Stderr, "[peer.join] warning: failed to update peer transport/role: %v\n", updateErr)
+					slog.Warn("[peer.join] update peer transport/role failed", "err", updateErr)
 				}
 				// thrum-1f4y: spawn the bridge for this new peer immediately;
 				// previously a daemon restart was required for ConnectAll to
@@ -7379,7 +10793,7 @@ func runDaemon(repoPath string, flagLocal bool, flagForce bool) error {
 				}
 				if oldKey != result.DaemonID && oldKey != "" {
 					if rmErr := peerRegistry.RemovePeer(oldKey); rmErr != nil {
-						fmt.Fprintf(os.Stderr, "[peer.join] warning: failed to remove stale peer entry %s: %v\n", oldKey, rmErr)
+						slog.Warn("[peer.join] remove stale peer entry failed", "peer", oldKey, "err", rmErr)
 					}
 				}
 				if addErr := peerRegistry.AddPeer(&refreshed); addErr != nil {
@@ -7428,6 +10842,14 @@ func runDaemon(repoPath string, flagLocal bool, flagForce bool) error {
 		server.RegisterHandler("peer.remove",
 			rpc.NewPeerRemoveHandler(removeFn, findByNameFn).Handle)
 
+		// peer.rename — change a peer's display name; daemon ID is unchanged
+		server.RegisterHandler("peer.rename",
+			rpc.NewPeerRenameHandler(syncManager.PeerRegistry().RenamePeer, findByNameFn).Handle)
+
+		// peer.sync — pull from one peer now (thrum peer sync-now)
+		server.RegisterHandler("peer.sync",
+			rpc.NewPeerSyncHandler(syncManager.PullFromPeerByID, findByNameFn).Handle)
+
 		// peer.status — detailed per-peer status
 		statusFn := func() []rpc.PeerDetailedStatus {
 			infos := syncManager.DetailedPeerStatus()
@@ -7446,7 +10868,7 @@ func runDaemon(repoPath string, flagLocal bool, flagForce bool) error {
 			return statuses
 		}
 		server.RegisterHandler("peer.status",
-			rpc.NewPeerStatusHandler(statusFn).Handle)
+			rpc.NewPeerStatusHandler(statusFn, syncManager.ProbePeer).Handle)
 
 		// peer.configure — add/remove proxy agents for a peer
 		peerConfigureHandler := rpc.NewPeerConfigureHandler(
@@ -7537,8 +10959,24 @@ func runDaemon(repoPath string, flagLocal bool, flagForce bool) error {
 	purgeHandler := rpc.NewPurgeHandler(st)
 	server.RegisterHandler("purge.execute", purgeHandler.Handle)
 
-	// Resolve WS port: env var > config.json > default ("auto" = find free port)
+	// Config reload (thrum daemon reload / SIGHUP). Unix socket only.
+	reloader := &daemonReloader{
+		thrumDir:      thrumDir,
+		syncLoop:      syncLoop,
+		explicitLocal: localOnlyFromExplicit,
+		bootWSPort:    thrumCfg.Daemon.WSPort,
+		exposureGate:  runExposureGate,
+		idleThreshold: idleThreshold,
+		sendLimiter:   sendLimiter,
+	}
+	reloadHandler := rpc.NewReloadHandler(reloader.Reload)
+	server.RegisterHandler("daemon.reload", reloadHandler.Handle)
+
+	// Resolve WS port: --ws-port > env var > config.json > default ("auto" = find free port)
 	wsPort = os.Getenv("THRUM_WS_PORT")
+	if flagWSPort > 0 {
+		wsPort = strconv.Itoa(flagWSPort)
+	}
 	if wsPort == "" {
 		wsPort = thrumCfg.Daemon.WSPort
 	}
@@ -7580,26 +11018,50 @@ func runDaemon(repoPath string, flagLocal bool, flagForce bool) error {
 	wsRegistry.Register("agent.whoami", websocket.Handler(agentHandler.HandleWhoami))
 	wsRegistry.Register("agent.listContext", websocket.Handler(agentHandler.HandleListContext))
 	wsRegistry.Register("agent.delete", websocket.Handler(agentHandler.HandleDelete))
+	wsRegistry.Register("agent.alias.set", websocket.Handler(agentHandler.HandleAliasSet))
+	wsRegistry.Register("agent.alias.remove", websocket.Handler(agentHandler.HandleAliasRemove))
+	wsRegistry.Register("agent.capabilities.set", websocket.Handler(agentHandler.HandleCapabilitiesSet))
+	wsRegistry.Register("agent.mute", websocket.Handler(agentHandler.HandleMute))
+	wsRegistry.Register("agent.unmute", websocket.Handler(agentHandler.HandleUnmute))
+	wsRegistry.Register("agent.rename", websocket.Handler(agentHandler.HandleRename))
 	wsRegistry.Register("agent.cleanup", websocket.Handler(agentHandler.HandleCleanup))
 	wsRegistry.Register("session.start", websocket.Handler(sessionHandler.HandleStart))
 	wsRegistry.Register("session.end", websocket.Handler(sessionHandler.HandleEnd))
+	wsRegistry.Register("session.resume", websocket.Handler(sessionHandler.HandleResume))
 	wsRegistry.Register("session.list", websocket.Handler(sessionHandler.HandleList))
 	wsRegistry.Register("session.heartbeat", websocket.Handler(sessionHandler.HandleHeartbeat))
 	wsRegistry.Register("session.setIntent", websocket.Handler(sessionHandler.HandleSetIntent))
 	wsRegistry.Register("session.setTask", websocket.Handler(sessionHandler.HandleSetTask))
+	wsRegistry.Register("session.transfer", websocket.Handler(sessionHandler.HandleTransfer))
 	wsRegistry.Register("group.create", websocket.Handler(groupHandler.HandleCreate))
 	wsRegistry.Register("group.delete", websocket.Handler(groupHandler.HandleDelete))
+	wsRegistry.Register("group.rename", websocket.Handler(groupHandler.HandleRename))
+	wsRegistry.Register("group.copy", websocket.Handler(groupHandler.HandleCopy))
 	wsRegistry.Register("group.member.add", websocket.Handler(groupHandler.HandleMemberAdd))
 	wsRegistry.Register("group.member.remove", websocket.Handler(groupHandler.HandleMemberRemove))
 	wsRegistry.Register("group.list", websocket.Handler(groupHandler.HandleList))
 	wsRegistry.Register("group.info", websocket.Handler(groupHandler.HandleInfo))
 	wsRegistry.Register("group.members", websocket.Handler(groupHandler.HandleMembers))
 	wsRegistry.Register("message.send", websocket.Handler(messageHandler.HandleSend))
+	wsRegistry.Register("message.resolve", websocket.Handler(messageHandler.HandleResolve))
 	wsRegistry.Register("message.get", websocket.Handler(messageHandler.HandleGet))
 	wsRegistry.Register("message.list", websocket.Handler(messageHandler.HandleList))
+	wsRegistry.Register("message.search", websocket.Handler(messageHandler.HandleSearch))
+	wsRegistry.Register("thread.get", websocket.Handler(messageHandler.HandleThreadGet))
 	wsRegistry.Register("message.outbox", websocket.Handler(messageHandler.HandleOutbox))
 	wsRegistry.Register("message.delete", websocket.Handler(messageHandler.HandleDelete))
+	wsRegistry.Register("message.cancel", websocket.Handler(messageHandler.HandleCancel))
 	wsRegistry.Register("message.edit", websocket.Handler(messageHandler.HandleEdit))
+	wsRegistry.Register("message.history", websocket.Handler(messageHandler.HandleHistory))
+	wsRegistry.Register("message.readers", websocket.Handler(messageHandler.HandleReaders))
+	wsRegistry.Register("message.forward", websocket.Handler(messageHandler.HandleForward))
+	wsRegistry.Register("message.react", websocket.Handler(messageHandler.HandleReact))
+	wsRegistry.Register("message.pin", websocket.Handler(messageHandler.HandlePin))
+	wsRegistry.Register("message.unpin", websocket.Handler(messageHandler.HandleUnpin))
+	wsRegistry.Register("message.move", websocket.Handler(messageHandler.HandleMove))
+	wsRegistry.Register("message.bump", websocket.Handler(messageHandler.HandleBump))
+	wsRegistry.Register("message.assign", websocket.Handler(messageHandler.HandleAssign))
+	wsRegistry.Register("message.complete", websocket.Handler(messageHandler.HandleComplete))
 	wsRegistry.Register("message.markRead", websocket.Handler(messageHandler.HandleMarkRead))
 	// SECURITY (sec.8): message.deleteByAgent and message.deleteByScope are
 	// NOT registered on the WS transport. They are admin/system operations
@@ -7608,6 +11070,10 @@ func runDaemon(repoPath string, flagLocal bool, flagForce bool) error {
 	// any localhost browser page could invoke bulk hard-deletes.
 	// See internal/daemon/rpc/monitor_trust_boundary_test.go for the
 	// structural guard pattern that enforces this on the monitor.* handlers.
+	// message.import is likewise unix-socket only: it writes messages under
+	// arbitrary author IDs and --force hard-deletes existing rows.
+	// message.export too: it returns every message, direct ones included,
+	// without recipient filtering.
 	wsRegistry.Register("message.archive", websocket.Handler(messageHandler.HandleArchive))
 	// Subscribe/unsubscribe WS handlers removed — CLI subscribe commands deleted.
 	wsRegistry.Register("user.register", websocket.Handler(userHandler.HandleRegister))
@@ -7615,6 +11081,9 @@ func runDaemon(repoPath string, flagLocal bool, flagForce bool) error {
 	if syncLoop != nil {
 		wsRegistry.Register("sync.force", websocket.Handler(syncForceHandler.Handle))
 		wsRegistry.Register("sync.status", websocket.Handler(syncStatusHandler.Handle))
+		wsRegistry.Register("sync.log", websocket.Handler(syncLogHandler.Handle))
+		wsRegistry.Register("sync.pause", websocket.Handler(syncPauseHandler.HandlePause))
+		wsRegistry.Register("sync.resume", websocket.Handler(syncPauseHandler.HandleResume))
 	}
 
 	// xir.27 sub-1: pair.request on the localhost WS so --type local peers
@@ -7693,7 +11162,30 @@ func runDaemon(repoPath string, flagLocal bool, flagForce bool) error {
 		}))
 	}
 
+	// Optional Prometheus endpoint (daemon.metrics_enabled). The client
+	// registry only exists once wsServer is built, so the ws-clients gauge
+	// reads through a pointer captured here and set just below.
+	var wsClients *websocket.ClientRegistry
+	if thrumCfg.Daemon.MetricsEnabled {
+		wsOpts = append(wsOpts, websocket.WithMetricsHandler(
+			newDaemonMetrics(st, messageHandler, syncLoop, func() int {
+				if wsClients == nil {
+					return 0
+				}
+				return wsClients.Count()
+			}, rpcCalls).Handler()),
+			websocket.WithCallObserver(rpcCalls.Inc))
+	}
+
+	// Optional HTTP+JSON gateway (daemon.http_gateway_enabled): POST
+	// /rpc/{method} against the same registry, minus sync/peer/pair methods
+	// unless listed in daemon.http_gateway_methods.
+	if thrumCfg.Daemon.HTTPGatewayEnabled {
+		wsOpts = append(wsOpts, websocket.WithHTTPGateway(websocket.GatewayAllow(thrumCfg.Daemon.HTTPGatewayMethods)))
+	}
+
 	wsServer := websocket.NewServer(wsAddr, wsRegistry, uiFS, wsOpts...)
+	wsClients = wsServer.GetClients()
 
 	// xir.27 sub-2: lazy per-IP secondary WS listener for --type network.
 	// Reuses wsServer.HTTPHandler() so all RPC handlers + the pairing /
@@ -7910,7 +11402,7 @@ func runDaemon(repoPath string, flagLocal bool, flagForce bool) error {
 
 	if tsBootPort > 0 {
 		if err := startTsnet(tsBootPort); err != nil {
-			fmt.Fprintf(os.Stderr, "Tailscale sync disabled: %v\n", err)
+			slog.Warn("[tailscale] sync disabled", "err", err)
 		}
 	}
 
@@ -7927,6 +11419,10 @@ func runDaemon(repoPath string, flagLocal bool, flagForce bool) error {
 	wsPortFile := filepath.Join(varDir, "ws.port")
 	lockFile := filepath.Join(varDir, "thrum.lock")
 	lifecycle := daemon.NewLifecycle(server, pidFile, wsServer, wsPortFile)
+	lifecycle.SetReload(func(c context.Context) error {
+		_, err := reloader.Reload(c)
+		return err
+	})
 
 	// Set repo info for PID file metadata
 	lifecycle.SetRepoInfo(absPath, socketPath)
@@ -7958,7 +11454,7 @@ func runDaemon(repoPath string, flagLocal bool, flagForce bool) error {
 	if thrumCfg.Backup.Schedule != "" {
 		backupInterval, parseErr := time.ParseDuration(thrumCfg.Backup.Schedule)
 		if parseErr != nil {
-			fmt.Fprintf(os.Stderr, "Warning: invalid backup schedule %q: %v\n", thrumCfg.Backup.Schedule, parseErr)
+			slog.Warn("[backup] invalid backup schedule", "schedule", thrumCfg.Backup.Schedule, "err", parseErr)
 		} else if backupInterval > 0 {
 			backupDir := thrumCfg.Backup.Dir
 			if backupDir == "" {
@@ -8015,6 +11511,32 @@ func runDaemon(repoPath string, flagLocal bool, flagForce bool) error {
 	deadAgentSweeper := daemon.NewDeadAgentSweeper(st, thrumDir)
 	go deadAgentSweeper.Start(ctx)
 
+	// Stale work contexts and expired (send --ttl) messages: one pass now,
+	// then every daemon.cleanup_interval.
+	go cleanup.Start(ctx, st, thrumCfg.Daemon.CleanupIntervalEffective())
+
+	// Scheduled (send --schedule) messages: releases any that came due
+	// while the daemon was down, then each one as its deliver_at passes.
+	go messageScheduler.Start(ctx)
+
+	// Optional periodic gc (daemon.gc_interval, off by default). Shares the
+	// sync compactor so the two never rewrite the same file at once. Without
+	// a peer registry we can't tell whether peers are caught up, so no gc.
+	if gcInterval := thrumCfg.Daemon.GCIntervalEffective(); gcInterval > 0 && peerRegistry != nil {
+		if compactor == nil {
+			compactor = syncCompact.New(thrumDir, syncDir,
+				thrumCfg.Daemon.EventsRetentionDays,
+				thrumCfg.Daemon.CompactionSizeThresholdMB)
+		}
+		go gc.Start(ctx, st, thrumDir, compactor, gcInterval, func() []gc.Peer {
+			var peers []gc.Peer
+			for _, p := range peerRegistry.ListPeers() {
+				peers = append(peers, gc.Peer{Name: p.Name, DaemonID: p.DaemonID, PulledSequence: p.PulledSequence})
+			}
+			return peers
+		})
+	}
+
 	// thrum-7b84.3 E3: backstop ticker. Every 15 minutes, scan
 	// message_deliveries for unread rows older than the AgeCutoff for
 	// alive agents, and re-fire the existing tmux nudge. Catches the
@@ -8189,7 +11711,7 @@ func runDaemon(repoPath string, flagLocal bool, flagForce bool) error {
 
 	// Recover queue state after restart — mark interrupted commands, reload queued.
 	if err := tmuxHandler.RecoverQueueState(ctx); err != nil {
-		fmt.Fprintf(os.Stderr, "[queue] recovery failed: %v\n", err)
+		slog.Warn("[queue] recovery failed", "err", err)
 	}
 
 	// Auto-connect to dialer-role peers after the WS server is ready.
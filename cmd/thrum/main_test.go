@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/leonletto/thrum/internal/cli"
 	"github.com/leonletto/thrum/internal/worktree"
@@ -227,3 +228,35 @@ func TestWorktreeTeardown_DeleteBranchFlag(t *testing.T) {
 		t.Errorf("branch still present after --delete-branch: %s", out)
 	}
 }
+
+func TestParseSinceTime(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"-1h", now.Add(-time.Hour)},
+		{"-30m", now.Add(-30 * time.Minute)},
+		{"+60s", now.Add(time.Minute)},
+		{"2026-04-30T08:00:00Z", time.Date(2026, 4, 30, 8, 0, 0, 0, time.UTC)},
+		{"2026-04-30T08:00:00-07:00", time.Date(2026, 4, 30, 15, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := parseSinceTime(tt.in, now)
+		if err != nil {
+			t.Errorf("parseSinceTime(%q): %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("parseSinceTime(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+
+	// Same error shape as wait --after, naming the --since flag.
+	_, err := parseSinceTime("yesterday", now)
+	if err == nil || !strings.HasPrefix(err.Error(), `invalid --since duration "yesterday": `) ||
+		!strings.HasSuffix(err.Error(), "(examples: -30s, -5m, +60s)") {
+		t.Errorf("parseSinceTime(yesterday) error = %v", err)
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -352,3 +353,58 @@ func TestEditCmd_StdinFlagRegistered(t *testing.T) {
 		t.Errorf("message edit missing --body-file flag (addBodyInputFlags not wired)")
 	}
 }
+
+// TestResolveMessageBody_FileAlias pins --file as an alias for --body-file.
+func TestResolveMessageBody_FileAlias(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "body.md")
+	if err := os.WriteFile(path, []byte("from `file`\n"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	cmd := newBodyInputTestCmd("")
+	if err := cmd.ParseFlags([]string{"--file", path}); err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	got, err := resolveMessageBody(cmd, "", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "from `file`" {
+		t.Errorf("--file body wrong; got %q", got)
+	}
+}
+
+// TestResolveMessageBody_FileAndPositional errors naming the flag the user
+// actually passed when --file is combined with a positional MESSAGE.
+func TestResolveMessageBody_FileAndPositional(t *testing.T) {
+	cmd := newBodyInputTestCmd("")
+	if err := cmd.ParseFlags([]string{"--file", "/tmp/body.md"}); err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	_, err := resolveMessageBody(cmd, "hi", true)
+	if err == nil {
+		t.Fatalf("expected error for --file + positional")
+	}
+	if !strings.Contains(err.Error(), "--file") || !strings.Contains(err.Error(), "positional") {
+		t.Errorf("error should name --file and the positional; got %q", err.Error())
+	}
+}
+
+// TestResolveMessageBody_StdinTerminalHint returns a hint instead of blocking
+// when '-' is passed but stdin is an interactive terminal.
+func TestResolveMessageBody_StdinTerminalHint(t *testing.T) {
+	orig := stdinIsTerminal
+	stdinIsTerminal = func(io.Reader) bool { return true }
+	defer func() { stdinIsTerminal = orig }()
+
+	cmd := newBodyInputTestCmd("never read")
+	if err := cmd.ParseFlags(nil); err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	_, err := resolveMessageBody(cmd, "-", true)
+	if err == nil {
+		t.Fatalf("expected terminal hint error")
+	}
+	if !strings.Contains(err.Error(), "stdin is a terminal") || !strings.Contains(err.Error(), "--file") {
+		t.Errorf("error should explain the terminal and suggest --file; got %q", err.Error())
+	}
+}
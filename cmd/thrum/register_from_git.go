@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/leonletto/thrum/internal/cli"
+	"github.com/leonletto/thrum/internal/config"
+	"github.com/leonletto/thrum/internal/identity"
+)
+
+// gitRegistration is the input to inferGitRegistration, split out so tests
+// can supply a fixed directory, config and terminal.
+type gitRegistration struct {
+	Role, Module string // explicit --role / --module, which win over inference
+	EnvRole      string // THRUM_ROLE
+	Dir          string // where register runs
+	TopLevel     string // git rev-parse --show-toplevel of Dir
+	Agents       config.AgentsConfig
+	Interactive  bool // prompt on ambiguity instead of failing
+	In           io.Reader
+	Out          io.Writer
+}
+
+// inferGitRegistration resolves role and module for `thrum agent register
+// --from-git`. Role: --role, then THRUM_ROLE, then agents.default_role.
+// Module: --module, then identity.InferModule on the directory. When either
+// is still open, it prompts if interactive and otherwise returns an error
+// saying how to settle it.
+func inferGitRegistration(r gitRegistration) (role, module string, err error) {
+	role = r.Role
+	if role == "" {
+		role = r.EnvRole
+	}
+	if role == "" {
+		role = r.Agents.DefaultRole
+	}
+	var reader *bufio.Reader
+	if r.Interactive {
+		reader = bufio.NewReader(r.In)
+	}
+	if role == "" {
+		if reader == nil {
+			return "", "", fmt.Errorf("--from-git could not infer a role: pass --role, set THRUM_ROLE, or run 'thrum config set agents.default_role ROLE'")
+		}
+		_, _ = fmt.Fprint(r.Out, "Role: ")
+		input, _ := reader.ReadString('\n')
+		if role = strings.TrimSpace(input); role == "" {
+			return "", "", fmt.Errorf("role is required")
+		}
+	}
+
+	module = r.Module
+	if module != "" {
+		return role, module, nil
+	}
+	if r.TopLevel == "" {
+		return "", "", fmt.Errorf("--from-git requires a git worktree (%s is not in one)", r.Dir)
+	}
+	inf, err := identity.InferModule(r.TopLevel, r.Dir, r.Agents.ModuleDirs)
+	if err != nil {
+		return "", "", err
+	}
+	if inf.Module != "" {
+		return role, inf.Module, nil
+	}
+	if len(inf.Candidates) == 0 {
+		return "", "", fmt.Errorf("--from-git could not infer a module from %q: no subdirectories to choose from; pass --module", inf.Path)
+	}
+	if reader == nil {
+		return "", "", fmt.Errorf("--from-git could not infer a module from %q (candidates: %s): run from inside the module's directory or pass --module",
+			inf.Path, strings.Join(inf.Candidates, ", "))
+	}
+	_, _ = fmt.Fprintf(r.Out, "Module is ambiguous from %q. Candidates:\n", inf.Path)
+	for i, c := range inf.Candidates {
+		_, _ = fmt.Fprintf(r.Out, "  %d. %s\n", i+1, c)
+	}
+	_, _ = fmt.Fprintf(r.Out, "Which module? [1-%d]: ", len(inf.Candidates))
+	input, _ := reader.ReadString('\n')
+	input = strings.TrimSpace(input)
+	n, convErr := strconv.Atoi(input)
+	if convErr != nil || n < 1 || n > len(inf.Candidates) {
+		return "", "", fmt.Errorf("invalid selection %q; enter a number 1-%d", input, len(inf.Candidates))
+	}
+	return role, inf.Candidates[n-1], nil
+}
+
+// resolveGitRegistration fills flagRole and flagModule for `thrum agent
+// register --from-git` from the working directory and .thrum/config.json.
+// It uses the process's working directory rather than flagRepo, which has
+// already been walked up to the thrum root.
+func resolveGitRegistration() error {
+	dir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("resolve working directory: %w", err)
+	}
+	// git reports the physical top level; compare like with like.
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = resolved
+	}
+	topLevel := cli.GitTopLevel(dir)
+	if resolved, err := filepath.EvalSymlinks(topLevel); topLevel != "" && err == nil {
+		topLevel = resolved
+	}
+
+	var agents config.AgentsConfig
+	if cfg, err := config.LoadThrumConfig(filepath.Join(flagRepo, ".thrum")); err == nil {
+		agents = cfg.Agents
+	}
+
+	role, module, err := inferGitRegistration(gitRegistration{
+		Role:        flagRole,
+		Module:      flagModule,
+		EnvRole:     os.Getenv("THRUM_ROLE"),
+		Dir:         dir,
+		TopLevel:    topLevel,
+		Agents:      agents,
+		Interactive: isInteractive() && !flagJSON,
+		In:          os.Stdin,
+		Out:         os.Stderr,
+	})
+	if err != nil {
+		return err
+	}
+	flagRole, flagModule = role, module
+	return nil
+}
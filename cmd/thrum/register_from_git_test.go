@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/leonletto/thrum/internal/config"
+)
+
+func TestInferGitRegistration(t *testing.T) {
+	root := t.TempDir()
+	for _, d := range []string{"services/billing", "services/auth"} {
+		if err := os.MkdirAll(filepath.Join(root, d), 0o750); err != nil {
+			t.Fatal(err)
+		}
+	}
+	base := gitRegistration{TopLevel: root, Dir: filepath.Join(root, "services/billing")}
+
+	t.Run("role precedence", func(t *testing.T) {
+		r := base
+		r.Agents = config.AgentsConfig{DefaultRole: "tester"}
+		if role, module, err := inferGitRegistration(r); err != nil || role != "tester" || module != "billing" {
+			t.Errorf("config default = %q, %q, %v; want tester, billing", role, module, err)
+		}
+		r.EnvRole = "reviewer"
+		if role, _, _ := inferGitRegistration(r); role != "reviewer" {
+			t.Errorf("role = %q, want THRUM_ROLE over config", role)
+		}
+		r.Role = "implementer"
+		if role, _, _ := inferGitRegistration(r); role != "implementer" {
+			t.Errorf("role = %q, want --role over THRUM_ROLE", role)
+		}
+	})
+
+	t.Run("explicit module wins", func(t *testing.T) {
+		r := base
+		r.Role, r.Module, r.Dir = "implementer", "core", root
+		if _, module, err := inferGitRegistration(r); err != nil || module != "core" {
+			t.Errorf("module = %q, %v; want core", module, err)
+		}
+	})
+
+	t.Run("ambiguous non-interactive", func(t *testing.T) {
+		r := base
+		r.Role, r.Dir = "implementer", filepath.Join(root, "services")
+		_, _, err := inferGitRegistration(r)
+		if err == nil || !strings.Contains(err.Error(), "auth, billing") || !strings.Contains(err.Error(), "--module") {
+			t.Errorf("err = %v, want candidates and --module guidance", err)
+		}
+		r.Role = ""
+		if _, _, err := inferGitRegistration(r); err == nil || !strings.Contains(err.Error(), "agents.default_role") {
+			t.Errorf("missing role: err = %v, want guidance", err)
+		}
+	})
+
+	t.Run("ambiguous interactive prompts", func(t *testing.T) {
+		r := base
+		var out bytes.Buffer
+		r.Dir, r.Interactive, r.Out = filepath.Join(root, "services"), true, &out
+		r.In = strings.NewReader("planner\n2\n")
+		role, module, err := inferGitRegistration(r)
+		if err != nil || role != "planner" || module != "billing" {
+			t.Errorf("got %q, %q, %v; want planner, billing", role, module, err)
+		}
+		if !strings.Contains(out.String(), "  1. auth\n  2. billing\n") {
+			t.Errorf("prompt = %q, want numbered candidates", out.String())
+		}
+
+		r.In = strings.NewReader("planner\n9\n")
+		if _, _, err := inferGitRegistration(r); err == nil {
+			t.Error("expected an error for an out-of-range selection")
+		}
+	})
+
+	t.Run("not a git worktree", func(t *testing.T) {
+		r := base
+		r.Role, r.TopLevel = "implementer", ""
+		if _, _, err := inferGitRegistration(r); err == nil {
+			t.Error("expected an error outside a git worktree")
+		}
+	})
+}
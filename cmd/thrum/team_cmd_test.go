@@ -36,7 +36,7 @@ func TestTeamCmd_JSONForms(t *testing.T) {
 
 	t.Run("filter-nonempty", func(t *testing.T) {
 		out, _ := captureStdStreams(t, func() {
-			if err := emitFilteredTeam(cli.FilterByDaemon(members, "d_1"), "daemon", "d_1"); err != nil {
+			if err := emitFilteredTeam(cli.FilterByDaemon(members, "d_1"), "daemon", "d_1", false); err != nil {
 				t.Fatalf("emitFilteredTeam: %v", err)
 			}
 		})
@@ -55,7 +55,7 @@ func TestTeamCmd_JSONForms(t *testing.T) {
 
 	t.Run("filter-empty", func(t *testing.T) {
 		out, _ := captureStdStreams(t, func() {
-			if err := emitFilteredTeam(cli.FilterByDaemon(members, "d_nope"), "daemon", "d_nope"); err != nil {
+			if err := emitFilteredTeam(cli.FilterByDaemon(members, "d_nope"), "daemon", "d_nope", false); err != nil {
 				t.Fatalf("emitFilteredTeam: %v", err)
 			}
 		})
@@ -71,6 +71,21 @@ func TestTeamCmd_JSONForms(t *testing.T) {
 		}
 	})
 
+	t.Run("filter-flat", func(t *testing.T) {
+		out, _ := captureStdStreams(t, func() {
+			if err := emitFilteredTeam(cli.FilterByDaemon(members, "d_1"), "daemon", "d_1", true); err != nil {
+				t.Fatalf("emitFilteredTeam: %v", err)
+			}
+		})
+		var rows []cli.FlatTeamMember
+		if err := json.Unmarshal([]byte(out), &rows); err != nil {
+			t.Fatalf("--flat must emit a JSON array: %v\n%s", err, out)
+		}
+		if len(rows) != 2 {
+			t.Errorf("want 2 flat records, got %d", len(rows))
+		}
+	})
+
 	t.Run("daemons", func(t *testing.T) {
 		out, _ := captureStdStreams(t, func() {
 			if err := cli.EmitJSON(cli.AggregateByDaemon(members)); err != nil {
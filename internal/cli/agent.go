@@ -2,6 +2,7 @@ package cli
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -18,6 +19,8 @@ type RegisterRequest struct {
 	Force      bool   `json:"force,omitempty"`
 	ReRegister bool   `json:"re_register,omitempty"`
 	AgentPID   int    `json:"agent_pid,omitempty"`
+
+	Capabilities []string `json:"capabilities,omitempty"`
 }
 
 // RegisterResponse represents the response from agent.register RPC.
@@ -51,20 +54,23 @@ type AgentLookupResponse struct {
 
 // AgentInfo represents information about a registered agent.
 type AgentInfo struct {
-	AgentID      string `json:"agent_id"`
-	Kind         string `json:"kind"`
-	Role         string `json:"role"`
-	Module       string `json:"module"`
-	Display      string `json:"display"`
-	RegisteredAt string `json:"registered_at"`
-	LastSeenAt   string `json:"last_seen_at,omitempty"`
-	AgentPID     int    `json:"agent_pid,omitempty"`
+	AgentID      string   `json:"agent_id"`
+	Kind         string   `json:"kind"`
+	Role         string   `json:"role"`
+	Module       string   `json:"module"`
+	Display      string   `json:"display"`
+	RegisteredAt string   `json:"registered_at"`
+	LastSeenAt   string   `json:"last_seen_at,omitempty"`
+	AgentPID     int      `json:"agent_pid,omitempty"`
+	Aliases      []string `json:"aliases,omitempty"`
+	Capabilities []string `json:"capabilities,omitempty"`
 }
 
 // ListAgentsRequest represents the request for agent.list RPC.
 type ListAgentsRequest struct {
-	Role   string `json:"role,omitempty"`
-	Module string `json:"module,omitempty"`
+	Role       string `json:"role,omitempty"`
+	Module     string `json:"module,omitempty"`
+	Capability string `json:"capability,omitempty"`
 }
 
 // ListAgentsResponse represents the response from agent.list RPC.
@@ -81,12 +87,15 @@ type AgentRegisterOptions struct {
 	Force      bool
 	ReRegister bool
 	AgentPID   int
+
+	Capabilities []string // Replace the agent's capability tags (empty keeps them)
 }
 
 // AgentListOptions contains options for listing agents.
 type AgentListOptions struct {
-	Role   string
-	Module string
+	Role       string
+	Module     string
+	Capability string
 }
 
 // AgentDeleteOptions contains options for deleting an agent.
@@ -108,16 +117,27 @@ type DeleteAgentResponse struct {
 
 // AgentCleanupOptions contains options for cleaning up orphaned agents.
 type AgentCleanupOptions struct {
-	DryRun    bool
-	Force     bool
-	Threshold int // Days since last seen
+	DryRun        bool
+	Force         bool
+	Threshold     int  // Days since last seen
+	StaleSessions bool // Also find (and with Force, end) sessions past the idle threshold
 }
 
 // CleanupAgentRequest represents the request for agent.cleanup RPC.
 type CleanupAgentRequest struct {
-	DryRun    bool `json:"dry_run"`
-	Force     bool `json:"force"`
-	Threshold int  `json:"threshold"`
+	DryRun        bool `json:"dry_run"`
+	Force         bool `json:"force"`
+	Threshold     int  `json:"threshold"`
+	StaleSessions bool `json:"stale_sessions,omitempty"`
+}
+
+// StaleSession is an open session whose last heartbeat is older than the
+// daemon's idle threshold.
+type StaleSession struct {
+	SessionID   string `json:"session_id"`
+	AgentID     string `json:"agent_id"`
+	LastSeenAt  string `json:"last_seen_at"`
+	IdleSeconds int64  `json:"idle_seconds"`
 }
 
 // OrphanedAgent represents an orphaned agent.
@@ -136,10 +156,12 @@ type OrphanedAgent struct {
 
 // CleanupAgentResponse represents the response from agent.cleanup RPC.
 type CleanupAgentResponse struct {
-	Orphans []OrphanedAgent `json:"orphans"`
-	Deleted []string        `json:"deleted"` // List of deleted agent IDs
-	DryRun  bool            `json:"dry_run"`
-	Message string          `json:"message,omitempty"`
+	Orphans       []OrphanedAgent `json:"orphans"`
+	Deleted       []string        `json:"deleted"` // List of deleted agent IDs
+	StaleSessions []StaleSession  `json:"stale_sessions,omitempty"`
+	EndedSessions []string        `json:"ended_sessions,omitempty"` // Stale sessions ended (force only)
+	DryRun        bool            `json:"dry_run"`
+	Message       string          `json:"message,omitempty"`
 }
 
 // AgentRegister registers an agent with the daemon.
@@ -178,6 +200,157 @@ func AgentDelete(client *Client, opts AgentDeleteOptions) (*DeleteAgentResponse,
 	return &result, nil
 }
 
+// AgentAliasResult is the response from agent.alias.set and
+// agent.alias.remove.
+type AgentAliasResult struct {
+	AgentID string `json:"agent_id"`
+	Alias   string `json:"alias"`
+	Removed bool   `json:"removed,omitempty"`
+}
+
+// AgentAliasSet assigns alias to the agent named name.
+func AgentAliasSet(client *Client, name, alias string) (*AgentAliasResult, error) {
+	req := map[string]string{"name": name, "alias": alias}
+	var result AgentAliasResult
+	if err := client.Call("agent.alias.set", req, &result); err != nil {
+		return nil, fmt.Errorf("agent.alias.set RPC failed: %w", err)
+	}
+	return &result, nil
+}
+
+// AgentAliasRemove removes an alias from whichever agent holds it.
+func AgentAliasRemove(client *Client, alias string) (*AgentAliasResult, error) {
+	req := map[string]string{"alias": alias}
+	var result AgentAliasResult
+	if err := client.Call("agent.alias.remove", req, &result); err != nil {
+		return nil, fmt.Errorf("agent.alias.remove RPC failed: %w", err)
+	}
+	return &result, nil
+}
+
+// FormatAgentAlias formats an alias set/remove result for display.
+func FormatAgentAlias(result *AgentAliasResult) string {
+	if result.Removed {
+		return fmt.Sprintf("✓ Alias @%s removed from @%s\n", result.Alias, result.AgentID)
+	}
+	return fmt.Sprintf("✓ Alias @%s → @%s\n", result.Alias, result.AgentID)
+}
+
+// AgentCapabilitiesResult is the response from agent.capabilities.set.
+type AgentCapabilitiesResult struct {
+	AgentID      string   `json:"agent_id"`
+	Capabilities []string `json:"capabilities"`
+	Changed      bool     `json:"changed"`
+}
+
+// AgentCapabilitiesSet replaces the capability tags of the agent named name
+// (or holding that alias). An empty caps clears them.
+func AgentCapabilitiesSet(client *Client, name string, caps []string) (*AgentCapabilitiesResult, error) {
+	if caps == nil {
+		caps = []string{}
+	}
+	req := map[string]any{"name": name, "capabilities": caps}
+	var result AgentCapabilitiesResult
+	if err := client.Call("agent.capabilities.set", req, &result); err != nil {
+		return nil, fmt.Errorf("agent.capabilities.set RPC failed: %w", err)
+	}
+	return &result, nil
+}
+
+// FormatAgentCapabilities formats an agent.capabilities.set result for display.
+func FormatAgentCapabilities(result *AgentCapabilitiesResult) string {
+	if len(result.Capabilities) == 0 {
+		return fmt.Sprintf("✓ Capabilities cleared for @%s\n", result.AgentID)
+	}
+	suffix := ""
+	if !result.Changed {
+		suffix = " (unchanged)"
+	}
+	return fmt.Sprintf("✓ @%s capabilities: %s%s\n", result.AgentID, strings.Join(result.Capabilities, ", "), suffix)
+}
+
+// AgentMuteResult is the response from agent.mute and agent.unmute.
+type AgentMuteResult struct {
+	AgentID       string `json:"agent_id"`
+	Muted         bool   `json:"muted"`
+	Until         string `json:"until,omitempty"`
+	AllowMentions bool   `json:"allow_mentions,omitempty"`
+	Changed       bool   `json:"changed"`
+}
+
+// AgentMute mutes notification pushes for the agent named name (or holding
+// that alias). A zero duration mutes until AgentUnmute.
+func AgentMute(client *Client, name string, duration time.Duration, allowMentions bool) (*AgentMuteResult, error) {
+	req := map[string]any{"name": name, "allow_mentions": allowMentions}
+	if duration > 0 {
+		req["duration"] = duration.String()
+	}
+	var result AgentMuteResult
+	if err := client.Call("agent.mute", req, &result); err != nil {
+		return nil, fmt.Errorf("agent.mute RPC failed: %w", err)
+	}
+	return &result, nil
+}
+
+// AgentUnmute lifts the mute on the agent named name (or holding that alias).
+func AgentUnmute(client *Client, name string) (*AgentMuteResult, error) {
+	req := map[string]string{"name": name}
+	var result AgentMuteResult
+	if err := client.Call("agent.unmute", req, &result); err != nil {
+		return nil, fmt.Errorf("agent.unmute RPC failed: %w", err)
+	}
+	return &result, nil
+}
+
+// FormatAgentMute formats an agent.mute or agent.unmute result for display.
+func FormatAgentMute(result *AgentMuteResult) string {
+	if !result.Muted {
+		if !result.Changed {
+			return fmt.Sprintf("@%s was not muted\n", result.AgentID)
+		}
+		return fmt.Sprintf("✓ @%s unmuted\n", result.AgentID)
+	}
+	until := "until unmuted"
+	if t, err := time.Parse(time.RFC3339, result.Until); err == nil {
+		until = "until " + t.Local().Format("2006-01-02 15:04")
+	}
+	suffix := ""
+	if result.AllowMentions {
+		suffix = " (direct mentions still notify)"
+	}
+	return fmt.Sprintf("✓ @%s muted %s%s\n", result.AgentID, until, suffix)
+}
+
+// AgentRenameResult is the response from agent.rename.
+type AgentRenameResult struct {
+	AgentID    string `json:"agent_id"`
+	OldAgentID string `json:"old_agent_id"`
+}
+
+// AgentRename renames the agent named name (or holding that alias) to newName.
+func AgentRename(client *Client, name, newName string) (*AgentRenameResult, error) {
+	req := map[string]string{"name": name, "new_name": newName}
+	var result AgentRenameResult
+	if err := client.Call("agent.rename", req, &result); err != nil {
+		return nil, fmt.Errorf("agent.rename RPC failed: %w", err)
+	}
+	return &result, nil
+}
+
+// FormatAgentRename formats an agent rename result for display.
+func FormatAgentRename(result *AgentRenameResult) string {
+	return fmt.Sprintf("✓ Agent @%s renamed to @%s\n", result.OldAgentID, result.AgentID)
+}
+
+// formatAliases renders an agent's aliases as " (aka @a, @b)", or "" when
+// it has none.
+func formatAliases(aliases []string) string {
+	if len(aliases) == 0 {
+		return ""
+	}
+	return " (aka @" + strings.Join(aliases, ", @") + ")"
+}
+
 // AgentCleanup performs cleanup of orphaned agents.
 func AgentCleanup(client *Client, opts AgentCleanupOptions) (*CleanupAgentResponse, error) {
 	req := CleanupAgentRequest(opts)
@@ -259,13 +432,17 @@ func FormatAgentList(result *ListAgentsResponse) string {
 
 	for _, agent := range result.Agents {
 		// Format agent ID with role highlighted
-		fmt.Fprintf(&output, "┌─ @%s (%s)\n", agent.Role, agent.AgentID)
+		fmt.Fprintf(&output, "┌─ @%s (%s)%s\n", agent.Role, agent.AgentID, formatAliases(agent.Aliases))
 
 		// Module
 		if agent.Module != "" {
 			fmt.Fprintf(&output, "│  Module:     %s\n", agent.Module)
 		}
 
+		if len(agent.Capabilities) > 0 {
+			fmt.Fprintf(&output, "│  Skills:     %s\n", strings.Join(agent.Capabilities, ", "))
+		}
+
 		// Display name
 		if agent.Display != "" {
 			fmt.Fprintf(&output, "│  Display:    %s\n", agent.Display)
@@ -324,7 +501,6 @@ func FormatAgentCleanup(result *CleanupAgentResponse) string {
 
 	if len(result.Orphans) == 0 {
 		output.WriteString("No orphaned agents found.\n")
-		return output.String()
 	}
 
 	for _, orphan := range result.Orphans {
@@ -353,7 +529,20 @@ func FormatAgentCleanup(result *CleanupAgentResponse) string {
 		fmt.Fprintf(&output, "✓ Deleted %d orphaned agent(s): %s\n", len(result.Deleted), strings.Join(result.Deleted, ", "))
 	}
 
-	if result.Message != "" {
+	if len(result.StaleSessions) > 0 {
+		output.WriteString("\nStale sessions (no heartbeat within the idle threshold):\n")
+		for _, sess := range result.StaleSessions {
+			fmt.Fprintf(&output, "  %s  @%s  last seen %s ago\n", sess.SessionID, sess.AgentID,
+				formatDuration(time.Duration(sess.IdleSeconds)*time.Second))
+		}
+	}
+	if len(result.EndedSessions) > 0 {
+		fmt.Fprintf(&output, "✓ Ended %d stale session(s): %s\n", len(result.EndedSessions), strings.Join(result.EndedSessions, ", "))
+	}
+
+	// With no orphans and nothing else to report, "No orphaned agents found."
+	// already says it all.
+	if result.Message != "" && (len(result.Orphans) > 0 || len(result.StaleSessions) > 0) {
 		fmt.Fprintf(&output, "\n%s\n", result.Message)
 	}
 
@@ -392,12 +581,15 @@ func FormatAgentListWithContext(agents *ListAgentsResponse, contexts *ListContex
 		}
 
 		// Format agent ID with role and status
-		fmt.Fprintf(&output, "┌─ %s @%s (%s)\n", status, agent.Role, statusText)
+		fmt.Fprintf(&output, "┌─ %s @%s (%s)%s\n", status, agent.Role, statusText, formatAliases(agent.Aliases))
 
 		// Module
 		if agent.Module != "" {
 			fmt.Fprintf(&output, "│  Module:  %s\n", agent.Module)
 		}
+		if len(agent.Capabilities) > 0 {
+			fmt.Fprintf(&output, "│  Skills:  %s\n", strings.Join(agent.Capabilities, ", "))
+		}
 
 		// Session info for active agents
 		if ctx != nil && ctx.SessionID != "" {
@@ -485,6 +677,9 @@ type AgentWorkContext struct {
 	TaskUpdatedAt    string              `json:"task_updated_at,omitempty"`
 	Intent           string              `json:"intent,omitempty"`
 	IntentUpdatedAt  string              `json:"intent_updated_at,omitempty"`
+	DeclaredFiles    []string            `json:"declared_files,omitempty"`
+	LastSeenAt       string              `json:"last_seen_at,omitempty"`
+	Status           string              `json:"status,omitempty"` // "active" or "away"
 }
 
 // CommitSummary represents a single commit.
@@ -687,7 +882,25 @@ func FormatContextDetail(ctx *AgentWorkContext) string {
 	return output.String()
 }
 
+// FormatAgentContext formats every work context of one agent (one per
+// session) with FormatContextDetail, separated by blank lines.
+func FormatAgentContext(agentID string, contexts []AgentWorkContext) string {
+	if len(contexts) == 0 {
+		return fmt.Sprintf("No active work context for %s.\n", agentID)
+	}
+	var output strings.Builder
+	for i := range contexts {
+		if i > 0 {
+			output.WriteString("\n")
+		}
+		output.WriteString(FormatContextDetail(&contexts[i]))
+	}
+	return output.String()
+}
+
 // FormatWhoHas formats the who-has response showing agents touching a file.
+// Files an agent declared with session start --files are marked as such;
+// a declared file with no git changes yet is reported as declared only.
 func FormatWhoHas(file string, result *ListContextResponse) string {
 	if len(result.Contexts) == 0 {
 		return fmt.Sprintf("No agents are currently editing %s\n", file)
@@ -715,11 +928,21 @@ func FormatWhoHas(file string, result *ListContextResponse) string {
 			}
 		}
 
+		declared := slices.Contains(ctx.DeclaredFiles, file)
+		if declared && !fileFound && !slices.Contains(ctx.UncommittedFiles, file) && !slices.Contains(ctx.ChangedFiles, file) {
+			fmt.Fprintf(&output, "@%s has declared %s (no changes yet), branch: %s\n",
+				role, file, branch)
+			continue
+		}
+
 		// Fallback to legacy format if FileChanges not available
 		if !fileFound {
 			uncommitted := len(ctx.UncommittedFiles)
 			fileDetails = fmt.Sprintf(" (%d uncommitted changes)", uncommitted)
 		}
+		if declared {
+			fileDetails += " [declared]"
+		}
 
 		fmt.Fprintf(&output, "@%s is editing %s%s, branch: %s\n",
 			role, file, fileDetails, branch)
@@ -728,17 +951,93 @@ func FormatWhoHas(file string, result *ListContextResponse) string {
 	return output.String()
 }
 
+// FileEditors returns the agent IDs in result (an agent.listContext reply
+// filtered by file), sorted and de-duplicated, leaving out exclude. An agent
+// with several sessions touching the file is listed once.
+func FileEditors(result *ListContextResponse, exclude string) []string {
+	var ids []string
+	for _, ctx := range result.Contexts {
+		if ctx.AgentID == "" || ctx.AgentID == exclude {
+			continue
+		}
+		ids = append(ids, ctx.AgentID)
+	}
+	slices.Sort(ids)
+	return slices.Compact(ids)
+}
+
+// FormatWhoCan formats the agents advertising capability, active agents
+// first, each with its presence (active, away, or offline) taken from
+// contexts the same way FormatPing does.
+func FormatWhoCan(capability string, agents *ListAgentsResponse, contexts *ListContextResponse) string {
+	if len(agents.Agents) == 0 {
+		return fmt.Sprintf("No agents advertise %s\n", capability)
+	}
+
+	sessions := make(map[string]*AgentWorkContext)
+	if contexts != nil {
+		for i := range contexts.Contexts {
+			if ctx := &contexts.Contexts[i]; ctx.SessionID != "" {
+				sessions[ctx.AgentID] = ctx
+			}
+		}
+	}
+
+	type row struct {
+		agent  AgentInfo
+		status string
+	}
+	rows := make([]row, 0, len(agents.Agents))
+	for _, agent := range agents.Agents {
+		status := "offline"
+		if ctx := sessions[agent.AgentID]; ctx != nil {
+			status = "active"
+			if ctx.Status == "away" {
+				status = "away"
+			}
+		}
+		rows = append(rows, row{agent: agent, status: status})
+	}
+	rank := map[string]int{"active": 0, "away": 1, "offline": 2}
+	slices.SortStableFunc(rows, func(a, b row) int { return rank[a.status] - rank[b.status] })
+
+	var output strings.Builder
+	fmt.Fprintf(&output, "Agents that can do %s (%d):\n", capability, len(rows))
+	for _, r := range rows {
+		marker := "●"
+		detail := r.status
+		if r.status == "offline" {
+			marker = "○"
+			if t, err := time.Parse(time.RFC3339, r.agent.LastSeenAt); err == nil {
+				detail += ", last seen " + formatTimeAgo(t)
+			}
+		}
+		fmt.Fprintf(&output, "  %s @%s (%s)  role: %s, module: %s\n",
+			marker, r.agent.AgentID, detail, r.agent.Role, r.agent.Module)
+	}
+	return output.String()
+}
+
 // FormatPing formats the ping response showing agent presence.
 func FormatPing(name string, agents *ListAgentsResponse, contexts *ListContextResponse) string {
-	// Find the agent by name: check AgentID, Display, then fall back to Role
+	// Find the agent by name: check aliases first (they never collide with
+	// an agent ID or role), then AgentID, Display, and finally Role.
 	var agent *AgentInfo
 	for i := range agents.Agents {
-		a := &agents.Agents[i]
-		if a.AgentID == name || a.Display == name {
-			agent = a
+		if slices.Contains(agents.Agents[i].Aliases, name) {
+			agent = &agents.Agents[i]
 			break
 		}
 	}
+	if agent == nil {
+		for i := range agents.Agents {
+			a := &agents.Agents[i]
+			if a.AgentID == name || a.Display == name {
+				agent = a
+				break
+			}
+		}
+	}
 	if agent == nil {
 		for i := range agents.Agents {
 			if agents.Agents[i].Role == name {
@@ -766,14 +1065,22 @@ func FormatPing(name string, agents *ListAgentsResponse, contexts *ListContextRe
 	var output strings.Builder
 
 	if ctx != nil && ctx.SessionID != "" {
-		// Active agent
+		// Active (or away: session open but no recent heartbeat)
+		status := "active"
+		if ctx.Status == "away" {
+			status = "away"
+		}
 		sessionDuration := ""
-		if ctx.GitUpdatedAt != "" {
-			if t, err := time.Parse(time.RFC3339, ctx.GitUpdatedAt); err == nil {
+		heartbeat := ctx.LastSeenAt
+		if heartbeat == "" {
+			heartbeat = ctx.GitUpdatedAt
+		}
+		if heartbeat != "" {
+			if t, err := time.Parse(time.RFC3339, heartbeat); err == nil {
 				sessionDuration = fmt.Sprintf(", last heartbeat %s", formatTimeAgo(t))
 			}
 		}
-		fmt.Fprintf(&output, "@%s: active%s\n", name, sessionDuration)
+		fmt.Fprintf(&output, "@%s: %s%s\n", name, status, sessionDuration)
 
 		if ctx.Intent != "" {
 			fmt.Fprintf(&output, "  Intent: %s\n", ctx.Intent)
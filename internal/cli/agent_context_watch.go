@@ -0,0 +1,169 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/leonletto/thrum/internal/bridge"
+)
+
+// AgentContextWatchOptions configures AgentContextWatch.
+type AgentContextWatchOptions struct {
+	AgentID    string // Agent whose work context is shown
+	SocketPath string // Daemon Unix socket (agent.listContext)
+	RepoPath   string // Locates .thrum/var/ws.port for the notification stream
+	Quiet      bool   // Suppress stderr connection status
+}
+
+// contextWatchResyncInterval redraws even without a notification. Contexts
+// of agents on other machines arrive by sync and never produce a local
+// notification.context.updated, and the "set 2m ago" times go stale.
+const contextWatchResyncInterval = 10 * time.Second
+
+// AgentContextWatch redraws an agent's work context on out every time the
+// daemon pushes a notification.context.updated for it (heartbeat, intent or
+// task change), and every contextWatchResyncInterval. Each frame clears the
+// screen first. When the daemon goes away it reconnects with backoff.
+// Returns nil when ctx is canceled, or an error if out can't be written.
+func AgentContextWatch(ctx context.Context, out io.Writer, opts AgentContextWatchOptions) error {
+	w := &contextWatcher{
+		out:     out,
+		agentID: opts.AgentID,
+		list: func() ([]AgentWorkContext, error) {
+			client, err := NewClient(opts.SocketPath)
+			if err != nil {
+				return nil, err
+			}
+			defer func() { _ = client.Close() }()
+			result, err := AgentListContext(client, opts.AgentID, "", "")
+			if err != nil {
+				return nil, err
+			}
+			return result.Contexts, nil
+		},
+		dial: func(ctx context.Context) (<-chan bridge.Notification, func(), error) {
+			return dialDaemonNotifications(ctx, opts.RepoPath)
+		},
+		status: func(msg string) {
+			if !opts.Quiet {
+				fmt.Fprintln(os.Stderr, msg)
+			}
+		},
+		now:      time.Now,
+		retryMin: watchRetryMin,
+		retryMax: watchRetryMax,
+	}
+	return w.run(ctx)
+}
+
+// contextWatcher holds AgentContextWatch state. list, dial and now are
+// swapped out in tests.
+type contextWatcher struct {
+	out      io.Writer
+	agentID  string
+	writeErr error
+
+	list   func() ([]AgentWorkContext, error)
+	dial   func(ctx context.Context) (<-chan bridge.Notification, func(), error)
+	status func(msg string)
+	now    func() time.Time
+
+	retryMin, retryMax time.Duration
+}
+
+func (w *contextWatcher) run(ctx context.Context) error {
+	retry := w.retryMin
+	lost := false
+	for {
+		// Subscribe before the first draw so an update in between
+		// triggers a redraw instead of being missed.
+		notifs, closeFn, err := w.dial(ctx)
+		if err == nil {
+			if err = w.redraw(); err != nil {
+				closeFn()
+			}
+		}
+		if w.writeErr != nil {
+			return w.writeErr
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if !lost {
+				w.status("Daemon not available, waiting for it to start...")
+				lost = true
+			}
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(retry):
+			}
+			retry = min(retry*2, w.retryMax)
+			continue
+		}
+		lost = false
+		retry = w.retryMin
+
+		w.stream(ctx, notifs)
+		closeFn()
+		if w.writeErr != nil {
+			return w.writeErr
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		w.status("Lost connection to daemon, reconnecting...")
+		lost = true
+	}
+}
+
+// stream redraws for every notification.context.updated about the watched
+// agent (and every contextWatchResyncInterval) until ctx is canceled, the
+// channel closes, or a redraw fails.
+func (w *contextWatcher) stream(ctx context.Context, notifs <-chan bridge.Notification) {
+	resync := time.NewTicker(contextWatchResyncInterval)
+	defer resync.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case n, ok := <-notifs:
+			if !ok {
+				return
+			}
+			if n.Method != "notification.context.updated" {
+				continue
+			}
+			var params struct {
+				AgentID string `json:"agent_id"`
+			}
+			if err := json.Unmarshal(n.Params, &params); err == nil && params.AgentID != w.agentID {
+				continue
+			}
+		case <-resync.C:
+		}
+		if err := w.redraw(); err != nil {
+			return
+		}
+	}
+}
+
+// redraw clears the screen and renders the agent's current work context.
+func (w *contextWatcher) redraw() error {
+	contexts, err := w.list()
+	if err != nil {
+		return err
+	}
+	frame := fmt.Sprintf("%sWatching %s, updated %s (Ctrl-C to exit)\n\n%s",
+		ClearScreen(), w.agentID, w.now().Format("15:04:05"), FormatAgentContext(w.agentID, contexts))
+	if _, err := io.WriteString(w.out, frame); err != nil {
+		w.writeErr = fmt.Errorf("write context: %w", err)
+		return w.writeErr
+	}
+	return nil
+}
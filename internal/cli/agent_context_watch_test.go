@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/leonletto/thrum/internal/bridge"
+)
+
+// TestAgentContextWatch_RedrawsOnMatchingUpdates checks that the watcher
+// draws once on connect, redraws only for notification.context.updated about
+// the watched agent, clears the screen before each frame, and returns nil
+// when canceled.
+func TestAgentContextWatch_RedrawsOnMatchingUpdates(t *testing.T) {
+	t.Setenv("NO_COLOR", "") // frames are split on the clear-screen sequence
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	intent := "reading"
+	lists := 0
+	list := func() ([]AgentWorkContext, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		lists++
+		return []AgentWorkContext{{AgentID: "furiosa", SessionID: "ses_1", Branch: "feature/x", Intent: intent}}, nil
+	}
+
+	notifs := make(chan bridge.Notification)
+	var out bytes.Buffer
+	w := &contextWatcher{
+		out:     &out,
+		agentID: "furiosa",
+		list:    list,
+		dial: func(context.Context) (<-chan bridge.Notification, func(), error) {
+			return notifs, func() {}, nil
+		},
+		status:   func(string) {},
+		now:      func() time.Time { return time.Date(2026, 5, 1, 9, 30, 0, 0, time.UTC) },
+		retryMin: time.Millisecond,
+		retryMax: time.Millisecond,
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- w.run(ctx) }()
+
+	notifs <- bridge.Notification{Method: "notification.message", Params: []byte(`{}`)}
+	notifs <- bridge.Notification{Method: "notification.context.updated", Params: []byte(`{"agent_id":"nux"}`)}
+	mu.Lock()
+	intent = "pairing on auth"
+	mu.Unlock()
+	notifs <- bridge.Notification{Method: "notification.context.updated", Params: []byte(`{"agent_id":"furiosa"}`)}
+	// The unbuffered send above returns once stream has received it; the
+	// next one can't be taken until that redraw finished.
+	notifs <- bridge.Notification{Method: "notification.message", Params: []byte(`{}`)}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("run = %v, want nil on cancel", err)
+	}
+
+	if lists != 2 {
+		t.Errorf("listed %d times, want 2 (connect + furiosa update)", lists)
+	}
+	frames := strings.Split(out.String(), clearScreen)
+	if len(frames) != 3 || frames[0] != "" {
+		t.Fatalf("got %d screen clears, want 2 frames:\n%q", len(frames)-1, out.String())
+	}
+	last := frames[2]
+	for _, want := range []string{"Watching furiosa, updated 09:30:00", "Branch: feature/x", "Intent: pairing on auth"} {
+		if !strings.Contains(last, want) {
+			t.Errorf("last frame missing %q:\n%s", want, last)
+		}
+	}
+}
+
+func TestFormatAgentContext(t *testing.T) {
+	if got := FormatAgentContext("nux", nil); got != "No active work context for nux.\n" {
+		t.Errorf("empty = %q", got)
+	}
+	got := FormatAgentContext("nux", []AgentWorkContext{
+		{AgentID: "nux", SessionID: "ses_1", Branch: "a"},
+		{AgentID: "nux", SessionID: "ses_2", Branch: "b"},
+	})
+	if !strings.Contains(got, "Branch: a\n\nAgent:") || !strings.Contains(got, "Branch: b") {
+		t.Errorf("two sessions should be separated by a blank line:\n%s", got)
+	}
+}
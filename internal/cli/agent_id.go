@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/leonletto/thrum/internal/config"
+	"github.com/leonletto/thrum/internal/identity"
+)
+
+// AgentIDResult is the output of `thrum agent id` (and `whoami --resolve`).
+type AgentIDResult struct {
+	AgentID string `json:"agent_id"`
+}
+
+// ResolveAgentID returns the agent ID commands run in repoPath act as. A
+// named identity from config wins (the identity file THRUM_NAME selects, or
+// the only one); otherwise THRUM_AGENT_ID is used, even when loading config
+// failed. role and module are the --role and --module flag overrides.
+func ResolveAgentID(repoPath, role, module string) (string, error) {
+	cfg, err := config.LoadWithPath(repoPath, role, module)
+	if err == nil && cfg.Agent.Name != "" {
+		// For named agents, GenerateAgentID returns the name directly.
+		// For unnamed agents, it generates a deterministic hash-based ID.
+		return identity.GenerateAgentID(cfg.RepoID, cfg.Agent.Role, cfg.Agent.Module, cfg.Agent.Name), nil
+	}
+
+	if agentID := strings.TrimSpace(os.Getenv("THRUM_AGENT_ID")); agentID != "" {
+		return agentID, nil
+	}
+
+	if err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("no agent name in config and THRUM_AGENT_ID env var not set")
+}
+
+// AgentID resolves the agent ID for `thrum agent id`. On failure the error
+// is cut to its first line, so a script capturing the output gets a single
+// line on stderr.
+func AgentID(repoPath, role, module string) (*AgentIDResult, error) {
+	agentID, err := ResolveAgentID(repoPath, role, module)
+	if err != nil {
+		msg, _, _ := strings.Cut(err.Error(), "\n")
+		return nil, fmt.Errorf("cannot resolve agent ID: %s", msg)
+	}
+	return &AgentIDResult{AgentID: agentID}, nil
+}
+
+// FormatAgentID renders the bare agent ID on one line, for
+// AGENT=$(thrum agent id).
+func FormatAgentID(r *AgentIDResult) string {
+	return r.AgentID + "\n"
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newIdentityRepo returns a repo holding one identity file per name, none of
+// them bound to the current worktree, with the identity env vars cleared.
+func newIdentityRepo(t *testing.T, names ...string) string {
+	t.Helper()
+	for _, env := range []string{"THRUM_NAME", "THRUM_ROLE", "THRUM_MODULE", "THRUM_AGENT_ID", "THRUM_HOME"} {
+		t.Setenv(env, "")
+	}
+	repo := t.TempDir()
+	dir := filepath.Join(repo, ".thrum", "identities")
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range names {
+		body := `{"agent":{"name":"` + name + `","role":"implementer","module":"api"},"worktree":"/elsewhere"}`
+		if err := os.WriteFile(filepath.Join(dir, name+".json"), []byte(body), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return repo
+}
+
+func TestResolveAgentID(t *testing.T) {
+
+	t.Run("identity file wins over THRUM_AGENT_ID", func(t *testing.T) {
+		repo := newIdentityRepo(t, "alice")
+		t.Setenv("THRUM_AGENT_ID", "from_env")
+		if got, err := ResolveAgentID(repo, "", ""); err != nil || got != "alice" {
+			t.Errorf("ResolveAgentID = %q, %v; want alice", got, err)
+		}
+	})
+
+	t.Run("THRUM_NAME selects among identities", func(t *testing.T) {
+		repo := newIdentityRepo(t, "alice", "bob")
+		t.Setenv("THRUM_NAME", "bob")
+		if got, err := ResolveAgentID(repo, "", ""); err != nil || got != "bob" {
+			t.Errorf("ResolveAgentID = %q, %v; want bob", got, err)
+		}
+	})
+
+	t.Run("THRUM_AGENT_ID when config fails", func(t *testing.T) {
+		repo := newIdentityRepo(t, "alice", "bob")
+		t.Setenv("THRUM_AGENT_ID", " from_env ")
+		if got, err := ResolveAgentID(repo, "", ""); err != nil || got != "from_env" {
+			t.Errorf("ResolveAgentID = %q, %v; want from_env", got, err)
+		}
+	})
+
+	t.Run("THRUM_AGENT_ID when config has no name", func(t *testing.T) {
+		repo := newIdentityRepo(t)
+		t.Setenv("THRUM_AGENT_ID", "from_env")
+		if got, err := ResolveAgentID(repo, "implementer", "api"); err != nil || got != "from_env" {
+			t.Errorf("ResolveAgentID = %q, %v; want from_env", got, err)
+		}
+	})
+
+	t.Run("nothing resolves", func(t *testing.T) {
+		_, err := ResolveAgentID(newIdentityRepo(t), "implementer", "api")
+		if err == nil || !strings.Contains(err.Error(), "THRUM_AGENT_ID env var not set") {
+			t.Errorf("err = %v, want the no-name error", err)
+		}
+	})
+}
+
+func TestAgentID(t *testing.T) {
+	repo := newIdentityRepo(t)
+	t.Setenv("THRUM_AGENT_ID", "impl_api")
+	result, err := AgentID(repo, "implementer", "api")
+	if err != nil {
+		t.Fatalf("AgentID: %v", err)
+	}
+	if out := FormatAgentID(result); out != "impl_api\n" {
+		t.Errorf("FormatAgentID = %q, want the bare ID on one line", out)
+	}
+	if data, _ := json.Marshal(result); string(data) != `{"agent_id":"impl_api"}` {
+		t.Errorf("JSON = %s", data)
+	}
+
+	// Two identity files and no THRUM_NAME: config's error spans several
+	// lines (hint, available names) and is cut to the first.
+	_, err = AgentID(newIdentityRepo(t, "alice", "bob"), "", "")
+	if err == nil || !strings.HasPrefix(err.Error(), "cannot resolve agent ID: cannot auto-select identity") {
+		t.Fatalf("err = %v, want the auto-select error", err)
+	}
+	if strings.Contains(err.Error(), "\n") {
+		t.Errorf("err spans several lines: %q", err.Error())
+	}
+}
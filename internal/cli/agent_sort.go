@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AgentSortKeys are the values accepted by `thrum agent list --sort`.
+var AgentSortKeys = []string{"name", "role", "last-seen", "unread"}
+
+// ValidateAgentSortKey returns an error naming the accepted keys when key is
+// not one of AgentSortKeys. An empty key (no sorting) is valid.
+func ValidateAgentSortKey(key string) error {
+	if key == "" {
+		return nil
+	}
+	for _, k := range AgentSortKeys {
+		if key == k {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid --sort %q (must be one of: %s)", key, strings.Join(AgentSortKeys, ", "))
+}
+
+// SortAgentList orders agents in place for `thrum agent list --sort`:
+//   - name: agent ID, A to Z
+//   - role: role, then agent ID
+//   - last-seen: least recently seen first, so stragglers lead
+//   - unread: most unread first, from unread (agent ID -> count)
+//
+// reverse flips the key's order. Agents without a work context in contexts
+// always come after those with one, whatever the key or direction, and are
+// ordered by the same key among themselves. Ties fall back to agent ID.
+func SortAgentList(agents *ListAgentsResponse, contexts *ListContextResponse, key string, reverse bool, unread map[string]int) {
+	if key == "" {
+		return
+	}
+	ctxByAgent := make(map[string]*AgentWorkContext)
+	if contexts != nil {
+		for i := range contexts.Contexts {
+			ctxByAgent[contexts.Contexts[i].AgentID] = &contexts.Contexts[i]
+		}
+	}
+	lastSeen := func(a AgentInfo) time.Time {
+		t := parseAgentTime(a.LastSeenAt)
+		if ctx := ctxByAgent[a.AgentID]; ctx != nil {
+			if ct := parseAgentTime(ctx.LastSeenAt); ct.After(t) {
+				t = ct
+			}
+		}
+		return t
+	}
+
+	// compare returns <0 when a sorts before b under key, ignoring reverse.
+	compare := func(a, b AgentInfo) int {
+		switch key {
+		case "role":
+			return strings.Compare(a.Role, b.Role)
+		case "last-seen":
+			return lastSeen(a).Compare(lastSeen(b))
+		case "unread":
+			return unread[b.AgentID] - unread[a.AgentID]
+		}
+		return 0
+	}
+
+	list := agents.Agents
+	sort.SliceStable(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		aCtx, bCtx := ctxByAgent[a.AgentID] != nil, ctxByAgent[b.AgentID] != nil
+		if aCtx != bCtx {
+			return aCtx
+		}
+		c := compare(a, b)
+		if c == 0 {
+			c = strings.Compare(a.AgentID, b.AgentID)
+		}
+		if reverse {
+			return c > 0
+		}
+		return c < 0
+	})
+}
+
+// parseAgentTime parses an RFC 3339 timestamp, returning the zero time (which
+// sorts first) when it is empty or malformed.
+func parseAgentTime(s string) time.Time {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// AgentUnreadCounts returns each agent's unread count (the team view's
+// header-line count), offline agents included, for sorting by unread.
+func AgentUnreadCounts(client *Client) (map[string]int, error) {
+	var result TeamListResponse
+	if err := client.Call("team.list", TeamListRequest{IncludeOffline: true}, &result); err != nil {
+		return nil, fmt.Errorf("team.list RPC failed: %w", err)
+	}
+	counts := make(map[string]int, len(result.Members))
+	for _, m := range result.Members {
+		counts[m.AgentID] = m.UnreadCount
+	}
+	return counts, nil
+}
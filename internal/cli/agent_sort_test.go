@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func sortSample() (*ListAgentsResponse, *ListContextResponse, map[string]int) {
+	agents := &ListAgentsResponse{Agents: []AgentInfo{
+		{AgentID: "carol", Role: "tester", LastSeenAt: "2026-03-01T12:00:00Z"},
+		{AgentID: "alice", Role: "reviewer", LastSeenAt: "2026-03-01T09:00:00Z"},
+		{AgentID: "dave", Role: "implementer", LastSeenAt: "2026-03-01T08:00:00Z"}, // no context
+		{AgentID: "bob", Role: "implementer", LastSeenAt: "2026-03-01T10:00:00Z"},
+	}}
+	contexts := &ListContextResponse{Contexts: []AgentWorkContext{
+		{AgentID: "alice", SessionID: "ses_a", LastSeenAt: "2026-03-01T11:30:00Z"},
+		{AgentID: "bob", SessionID: "ses_b"},
+		{AgentID: "carol", SessionID: "ses_c"},
+	}}
+	unread := map[string]int{"alice": 2, "bob": 7, "dave": 9}
+	return agents, contexts, unread
+}
+
+func agentOrder(agents *ListAgentsResponse) string {
+	ids := make([]string, 0, len(agents.Agents))
+	for _, a := range agents.Agents {
+		ids = append(ids, a.AgentID)
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestSortAgentList(t *testing.T) {
+	cases := []struct {
+		key     string
+		reverse bool
+		want    string
+	}{
+		{"name", false, "alice,bob,carol,dave"},
+		{"name", true, "carol,bob,alice,dave"},
+		{"role", false, "bob,alice,carol,dave"},
+		// alice's session was seen at 11:30, later than her agent row.
+		{"last-seen", false, "bob,alice,carol,dave"},
+		{"last-seen", true, "carol,alice,bob,dave"},
+		// dave has the most unread but no work context, so stays last.
+		{"unread", false, "bob,alice,carol,dave"},
+		{"unread", true, "carol,alice,bob,dave"},
+	}
+	for _, tc := range cases {
+		agents, contexts, unread := sortSample()
+		SortAgentList(agents, contexts, tc.key, tc.reverse, unread)
+		if got := agentOrder(agents); got != tc.want {
+			t.Errorf("--sort %s (reverse=%v) = %s, want %s", tc.key, tc.reverse, got, tc.want)
+		}
+	}
+}
+
+func TestSortAgentList_NoKeyKeepsOrder(t *testing.T) {
+	agents, contexts, unread := sortSample()
+	SortAgentList(agents, contexts, "", true, unread)
+	if got := agentOrder(agents); got != "carol,alice,dave,bob" {
+		t.Errorf("order = %s, want daemon order unchanged", got)
+	}
+}
+
+func TestValidateAgentSortKey(t *testing.T) {
+	for _, key := range append([]string{""}, AgentSortKeys...) {
+		if err := ValidateAgentSortKey(key); err != nil {
+			t.Errorf("ValidateAgentSortKey(%q): %v", key, err)
+		}
+	}
+	if err := ValidateAgentSortKey("age"); err == nil || !strings.Contains(err.Error(), "last-seen") {
+		t.Errorf("ValidateAgentSortKey(age) = %v, want error listing the keys", err)
+	}
+}
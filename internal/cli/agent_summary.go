@@ -144,6 +144,7 @@ func FormatAgentSummary(s *AgentSummary) string {
 //
 // Status glyphs:
 //   - ● active agent (has a live session)
+//   - ◐ away agent (session open, but no heartbeat within daemon.idle_threshold)
 //   - ○ offline agent (no live session)
 //   - ⊙ reserved pseudo-agent (surfaced only by `thrum team --system`;
 //     used for daemon-internal identities like @supervisor_<project>
@@ -153,6 +154,8 @@ func FormatAgentSummaryCompact(s *AgentSummary) string {
 	switch s.Status {
 	case "active":
 		icon = "●"
+	case "away":
+		icon = "◐"
 	case "reserved":
 		icon = "⊙"
 	}
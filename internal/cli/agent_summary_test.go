@@ -140,6 +140,7 @@ func TestFormatAgentSummaryCompact_StatusGlyphs(t *testing.T) {
 		purpose string
 	}{
 		{"active", "●", "live agent with a session"},
+		{"away", "◐", "session open but no recent heartbeat"},
 		{"offline", "○", "agent without a session"},
 		{"reserved", "⊙", "daemon-internal pseudo-agent (team --system)"},
 		// Unknown status defaults to offline glyph.
@@ -3,7 +3,9 @@ package cli
 import (
 	"encoding/json"
 	"net"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/leonletto/thrum/internal/config"
 )
@@ -363,6 +365,21 @@ func TestFormatAgentList(t *testing.T) {
 			},
 			contains: []string{"implementer", "auth"},
 		},
+		{
+			name: "with_aliases",
+			response: ListAgentsResponse{
+				Agents: []AgentInfo{
+					{
+						AgentID:      "coordinator_main",
+						Role:         "coordinator",
+						Module:       "main",
+						Aliases:      []string{"boss", "coord"},
+						RegisteredAt: "2026-02-03T10:00:00Z",
+					},
+				},
+			},
+			contains: []string{"(aka @boss, @coord)"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -404,6 +421,35 @@ func TestFormatWhoHas(t *testing.T) {
 			},
 			contains: []string{"@planner", "auth.go", "3 uncommitted", "feature/auth"},
 		},
+		{
+			name: "declared and editing",
+			file: "auth.go",
+			response: ListContextResponse{
+				Contexts: []AgentWorkContext{
+					{
+						AgentID:          "agent:planner:auth",
+						Branch:           "feature/auth",
+						UncommittedFiles: []string{"auth.go"},
+						DeclaredFiles:    []string{"auth.go"},
+					},
+				},
+			},
+			contains: []string{"@planner is editing auth.go", "[declared]"},
+		},
+		{
+			name: "declared only",
+			file: "auth.go",
+			response: ListContextResponse{
+				Contexts: []AgentWorkContext{
+					{
+						AgentID:       "agent:planner:auth",
+						Branch:        "feature/auth",
+						DeclaredFiles: []string{"auth.go"},
+					},
+				},
+			},
+			contains: []string{"@planner has declared auth.go (no changes yet)", "feature/auth"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -453,6 +499,22 @@ func TestFormatPing(t *testing.T) {
 			},
 			contains: []string{"@agent_b", "active", "Reviewing PR #42", "feature/auth"},
 		},
+		{
+			name: "alias matched before name",
+			role: "rev",
+			agents: ListAgentsResponse{
+				Agents: []AgentInfo{
+					{AgentID: "rev", Role: "reviewer"},
+					{AgentID: "agent:reviewer:auth", Role: "reviewer", Display: "agent_b", Aliases: []string{"rev"}},
+				},
+			},
+			contexts: &ListContextResponse{
+				Contexts: []AgentWorkContext{
+					{AgentID: "agent:reviewer:auth", SessionID: "ses_abc", Intent: "Reviewing PR #42"},
+				},
+			},
+			contains: []string{"Reviewing PR #42"},
+		},
 		{
 			name: "fallback to role match",
 			role: "reviewer",
@@ -472,6 +534,26 @@ func TestFormatPing(t *testing.T) {
 			},
 			contains: []string{"@reviewer", "active"},
 		},
+		{
+			name: "away agent",
+			role: "builder",
+			agents: ListAgentsResponse{
+				Agents: []AgentInfo{
+					{AgentID: "agent:builder:core", Role: "builder", Display: "builder"},
+				},
+			},
+			contexts: &ListContextResponse{
+				Contexts: []AgentWorkContext{
+					{
+						AgentID:    "agent:builder:core",
+						SessionID:  "ses_abc",
+						Status:     "away",
+						LastSeenAt: "2026-02-03T10:00:00Z",
+					},
+				},
+			},
+			contains: []string{"@builder: away, last heartbeat"},
+		},
 		{
 			name: "offline agent",
 			role: "builder",
@@ -525,3 +607,94 @@ func TestAgentSummary_IncludesHookDeliveryFields(t *testing.T) {
 		}
 	}
 }
+
+func TestFileEditors(t *testing.T) {
+	result := &ListContextResponse{Contexts: []AgentWorkContext{
+		{SessionID: "ses_1", AgentID: "reviewer"},
+		{SessionID: "ses_2", AgentID: "impl_auth"},
+		{SessionID: "ses_3", AgentID: "reviewer"},
+		{SessionID: "ses_4", AgentID: "me"},
+	}}
+
+	got := FileEditors(result, "me")
+	want := []string{"impl_auth", "reviewer"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("FileEditors = %v, want %v", got, want)
+	}
+
+	if got := FileEditors(&ListContextResponse{}, "me"); len(got) != 0 {
+		t.Errorf("FileEditors(empty) = %v, want none", got)
+	}
+}
+
+func TestFormatWhoCan(t *testing.T) {
+	if got := FormatWhoCan("go", &ListAgentsResponse{}, nil); got != "No agents advertise go\n" {
+		t.Errorf("empty = %q", got)
+	}
+
+	agents := &ListAgentsResponse{Agents: []AgentInfo{
+		{AgentID: "idle_dev", Role: "implementer", Module: "api", LastSeenAt: time.Now().Add(-3 * time.Hour).UTC().Format(time.RFC3339)},
+		{AgentID: "busy_dev", Role: "implementer", Module: "web"},
+	}}
+	contexts := &ListContextResponse{Contexts: []AgentWorkContext{
+		{AgentID: "busy_dev", SessionID: "ses_1"},
+	}}
+	output := FormatWhoCan("go", agents, contexts)
+
+	for _, want := range []string{
+		"Agents that can do go (2):",
+		"● @busy_dev (active)  role: implementer, module: web",
+		"○ @idle_dev (offline, last seen 3h ago)  role: implementer, module: api",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+	if strings.Index(output, "busy_dev") > strings.Index(output, "idle_dev") {
+		t.Errorf("active agents should be listed first:\n%s", output)
+	}
+}
+
+func TestFormatAgentMute(t *testing.T) {
+	tests := []struct {
+		name   string
+		result AgentMuteResult
+		want   string
+	}{
+		{"indefinite", AgentMuteResult{AgentID: "impl", Muted: true, Changed: true}, "✓ @impl muted until unmuted\n"},
+		{"mentions", AgentMuteResult{AgentID: "impl", Muted: true, AllowMentions: true, Changed: true}, "✓ @impl muted until unmuted (direct mentions still notify)\n"},
+		{"unmuted", AgentMuteResult{AgentID: "impl", Changed: true}, "✓ @impl unmuted\n"},
+		{"not muted", AgentMuteResult{AgentID: "impl"}, "@impl was not muted\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatAgentMute(&tt.result); got != tt.want {
+				t.Errorf("FormatAgentMute() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	until := time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC)
+	got := FormatAgentMute(&AgentMuteResult{AgentID: "impl", Muted: true, Until: until.Format(time.RFC3339), Changed: true})
+	if want := "until " + until.Local().Format("2006-01-02 15:04"); !strings.Contains(got, want) {
+		t.Errorf("FormatAgentMute() = %q, want it to contain %q", got, want)
+	}
+}
+
+func TestFormatAgentCleanup_StaleSessions(t *testing.T) {
+	got := FormatAgentCleanup(&CleanupAgentResponse{
+		DryRun:        true,
+		StaleSessions: []StaleSession{{SessionID: "ses_1", AgentID: "tester", IdleSeconds: 3 * 3600}},
+		Message:       "Found 0 orphaned agent(s) and 1 stale session(s) (dry-run mode)",
+	})
+	for _, want := range []string{"No orphaned agents found.", "ses_1  @tester  last seen 3h ago", "1 stale session(s)"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("FormatAgentCleanup() = %q, want it to contain %q", got, want)
+		}
+	}
+
+	got = FormatAgentCleanup(&CleanupAgentResponse{EndedSessions: []string{"ses_1"}})
+	if !strings.Contains(got, "✓ Ended 1 stale session(s): ses_1") {
+		t.Errorf("FormatAgentCleanup() = %q, want ended-session line", got)
+	}
+}
@@ -1,21 +1,28 @@
 package cli
 
 import (
+	"bufio"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net"
 	"path/filepath"
 	"sync/atomic"
+	"syscall"
 	"time"
 
 	"github.com/leonletto/thrum/internal/paths"
 )
 
 // Client is a JSON-RPC client that connects to the Thrum daemon via Unix socket.
+// One connection is reused for every call; see SetPersistent for surviving a
+// dropped connection and CallBatch for pipelining many calls.
 type Client struct {
 	conn       net.Conn
 	socketPath string
 	nextID     atomic.Uint64
+	persistent bool
 }
 
 // NewClient creates a new RPC client connected to the daemon at the given socket path.
@@ -42,6 +49,16 @@ func (c *Client) SocketPath() string {
 	return c.socketPath
 }
 
+// SetPersistent turns persistent-connection mode on or off. In persistent
+// mode a Call or CallBatch that fails because the daemon dropped the
+// connection (idle timeout, daemon restart) redials the socket and resends
+// the requests that had not been answered. A request whose response was lost
+// may therefore run twice, so only enable this for idempotent calls — the
+// scripting case it exists for (listing, marking read) is.
+func (c *Client) SetPersistent(on bool) {
+	c.persistent = on
+}
+
 // defaultCallTimeout is the maximum time a CLI→daemon RPC call can take.
 // Prevents CLI commands from hanging forever when the daemon is unresponsive.
 const defaultCallTimeout = 10 * time.Second
@@ -57,6 +74,16 @@ func (c *Client) Call(method string, params any, result any) error {
 	}
 	defer func() { _ = c.conn.SetDeadline(time.Time{}) }()
 
+	err := c.callRaw(method, params, result)
+	if err == nil || !c.persistent || !isConnDropped(err) {
+		return err
+	}
+	if rerr := c.redial(); rerr != nil {
+		return fmt.Errorf("%w (reconnect failed: %v)", err, rerr)
+	}
+	if err := c.conn.SetDeadline(time.Now().Add(defaultCallTimeout)); err != nil {
+		return fmt.Errorf("set deadline: %w", err)
+	}
 	return c.callRaw(method, params, result)
 }
 
@@ -79,16 +106,7 @@ func (c *Client) callRaw(method string, params any, result any) error {
 
 	// Read and decode response
 	decoder := json.NewDecoder(c.conn)
-	var response struct {
-		JSONRPC string          `json:"jsonrpc"`
-		ID      uint64          `json:"id"`
-		Result  json.RawMessage `json:"result,omitempty"`
-		Error   *struct {
-			Code    int    `json:"code"`
-			Message string `json:"message"`
-			Data    any    `json:"data,omitempty"`
-		} `json:"error,omitempty"`
-	}
+	var response rpcResponse
 
 	if err := decoder.Decode(&response); err != nil {
 		return fmt.Errorf("failed to read response (daemon may be unresponsive — try: thrum daemon restart): %w", err)
@@ -109,6 +127,140 @@ func (c *Client) callRaw(method string, params any, result any) error {
 	return nil
 }
 
+// rpcResponse is a JSON-RPC response envelope as read from the daemon.
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      uint64          `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Data    any    `json:"data,omitempty"`
+	} `json:"error,omitempty"`
+}
+
+// BatchCall is one request in a CallBatch. Result, when non-nil, receives
+// the decoded result; Err is set by CallBatch to the call's own RPC or
+// decode error.
+type BatchCall struct {
+	Method string
+	Params any
+	Result any
+	Err    error
+}
+
+// CallBatch sends every call before reading any response, so n calls cost
+// one round trip instead of n. The daemon serves a connection's requests in
+// order, so responses are matched positionally and checked by ID.
+//
+// A failing call does not stop the batch: its error lands in calls[i].Err.
+// The returned error is reserved for transport failures. In persistent mode
+// (SetPersistent) a connection dropped mid-batch is redialed and the
+// unanswered calls are resent; the batch gives up only when a fresh
+// connection fails before answering anything.
+func (c *Client) CallBatch(calls []BatchCall) error {
+	done := 0
+	for attempt := 0; done < len(calls); attempt++ {
+		n, err := c.pipeline(calls[done:])
+		done += n
+		if err == nil {
+			return nil
+		}
+		if !c.persistent || !isConnDropped(err) || (attempt > 0 && n == 0) {
+			return err
+		}
+		if rerr := c.redial(); rerr != nil {
+			return fmt.Errorf("%w (reconnect failed: %v)", err, rerr)
+		}
+	}
+	return nil
+}
+
+// pipeline writes all calls and reads their responses, returning how many
+// were answered. Requests are written from a goroutine so a large batch
+// cannot deadlock against the daemon's replies filling the socket buffer.
+func (c *Client) pipeline(calls []BatchCall) (int, error) {
+	conn := c.conn
+	if err := conn.SetDeadline(time.Now().Add(defaultCallTimeout)); err != nil {
+		return 0, fmt.Errorf("set deadline: %w", err)
+	}
+	defer func() { _ = conn.SetDeadline(time.Time{}) }()
+
+	ids := make([]uint64, len(calls))
+	for i := range calls {
+		ids[i] = c.nextID.Add(1)
+	}
+	writeErr := make(chan error, 1)
+	go func() {
+		w := bufio.NewWriter(conn)
+		encoder := json.NewEncoder(w)
+		for i, call := range calls {
+			request := map[string]any{
+				"jsonrpc": "2.0",
+				"id":      ids[i],
+				"method":  call.Method,
+				"params":  call.Params,
+			}
+			if err := encoder.Encode(request); err != nil {
+				writeErr <- fmt.Errorf("failed to send request: %w", err)
+				return
+			}
+		}
+		if err := w.Flush(); err != nil {
+			writeErr <- fmt.Errorf("failed to send request: %w", err)
+			return
+		}
+		writeErr <- nil
+	}()
+
+	decoder := json.NewDecoder(conn)
+	for i := range calls {
+		var response rpcResponse
+		if err := decoder.Decode(&response); err != nil {
+			// The stream is unusable past this point; closing it also
+			// unblocks the writer if the daemon stopped reading.
+			_ = conn.Close()
+			<-writeErr
+			return i, fmt.Errorf("failed to read response (daemon may be unresponsive — try: thrum daemon restart): %w", err)
+		}
+		if response.ID != ids[i] {
+			_ = conn.Close()
+			<-writeErr
+			return i, fmt.Errorf("response out of order: got id %d, want %d", response.ID, ids[i])
+		}
+		switch {
+		case response.Error != nil:
+			calls[i].Err = fmt.Errorf("RPC error %d: %s", response.Error.Code, response.Error.Message)
+		case calls[i].Result != nil && len(response.Result) > 0:
+			if err := json.Unmarshal(response.Result, calls[i].Result); err != nil {
+				calls[i].Err = fmt.Errorf("failed to decode result: %w", err)
+			}
+		}
+	}
+	return len(calls), <-writeErr
+}
+
+// redial replaces the connection with a fresh one to the same socket.
+func (c *Client) redial() error {
+	if c.conn != nil {
+		_ = c.conn.Close()
+	}
+	conn, err := net.DialTimeout("unix", c.socketPath, 5*time.Second)
+	if err != nil {
+		return fmt.Errorf("failed to connect to daemon at %s: %w", c.socketPath, err)
+	}
+	c.conn = conn
+	return nil
+}
+
+// isConnDropped reports whether err means the daemon closed or reset the
+// connection, as opposed to a timeout or an RPC-level error.
+func isConnDropped(err error) bool {
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, net.ErrClosed) || errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, syscall.ECONNRESET)
+}
+
 // CallWithTimeout is like Call but sets a deadline on the connection.
 // Useful for long-polling RPCs like peer.wait_pairing.
 func (c *Client) CallWithTimeout(method string, params any, result any, timeout time.Duration) error {
@@ -132,6 +284,9 @@ func (c *Client) Close() error {
 // It follows .thrum/redirect files so feature worktrees connect to the
 // daemon running in the main worktree.
 func DefaultSocketPath(repoPath string) string {
+	if socketPath := customSocketPath(repoPath); socketPath != "" {
+		return socketPath
+	}
 	repoPath = paths.EffectiveRepoPath(repoPath)
 	thrumDir, err := paths.ResolveThrumDir(repoPath)
 	if err != nil {
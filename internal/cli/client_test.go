@@ -5,6 +5,7 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -224,6 +225,155 @@ func TestClient_Call_Error(t *testing.T) {
 	}
 }
 
+// echoHandler answers up to limit requests on conn (0 = unlimited) with
+// {"n": params.n}, or an RPC error for method "fail", then closes it.
+func echoHandler(limit int) func(conn net.Conn) {
+	return func(conn net.Conn) {
+		defer func() { _ = conn.Close() }()
+		decoder := json.NewDecoder(conn)
+		encoder := json.NewEncoder(conn)
+		for served := 0; limit == 0 || served < limit; served++ {
+			var request struct {
+				ID     uint64         `json:"id"`
+				Method string         `json:"method"`
+				Params map[string]any `json:"params"`
+			}
+			if err := decoder.Decode(&request); err != nil {
+				return
+			}
+			response := map[string]any{"jsonrpc": "2.0", "id": request.ID}
+			if request.Method == "fail" {
+				response["error"] = map[string]any{"code": -32000, "message": "boom"}
+			} else {
+				response["result"] = map[string]any{"n": request.Params["n"]}
+			}
+			if err := encoder.Encode(response); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func batchOf(n int) []BatchCall {
+	calls := make([]BatchCall, n)
+	for i := range calls {
+		calls[i] = BatchCall{Method: "echo", Params: map[string]any{"n": i}, Result: &map[string]any{}}
+	}
+	return calls
+}
+
+func checkBatch(t *testing.T, calls []BatchCall) {
+	t.Helper()
+	for i, call := range calls {
+		if call.Err != nil {
+			t.Errorf("call %d: %v", i, call.Err)
+			continue
+		}
+		if got := (*call.Result.(*map[string]any))["n"]; got != float64(i) {
+			t.Errorf("call %d: n = %v, want %d", i, got, i)
+		}
+	}
+}
+
+func TestClient_CallBatch(t *testing.T) {
+	daemon, socketPath := newMockDaemon(t)
+	defer daemon.stop()
+	daemon.start(t, echoHandler(0))
+	<-daemon.Ready()
+
+	client, err := NewClient(socketPath)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	// A failing call mid-batch does not disturb the calls around it.
+	calls := append([]BatchCall{{Method: "fail"}}, batchOf(500)...)
+	if err := client.CallBatch(calls); err != nil {
+		t.Fatalf("CallBatch: %v", err)
+	}
+	if calls[0].Err == nil {
+		t.Error("expected per-call error for method fail")
+	}
+	checkBatch(t, calls[1:])
+
+	// The connection is still in sync for ordinary calls afterwards.
+	var result map[string]any
+	if err := client.Call("echo", map[string]any{"n": 7}, &result); err != nil || result["n"] != float64(7) {
+		t.Errorf("Call after batch: result=%v err=%v", result, err)
+	}
+}
+
+func TestClient_CallBatch_ReconnectsMidBatch(t *testing.T) {
+	daemon, socketPath := newMockDaemon(t)
+	defer daemon.stop()
+	// The first connection drops after answering two requests.
+	var conns atomic.Int32
+	daemon.start(t, func(conn net.Conn) {
+		if conns.Add(1) == 1 {
+			echoHandler(2)(conn)
+			return
+		}
+		echoHandler(0)(conn)
+	})
+	<-daemon.Ready()
+
+	client, err := NewClient(socketPath)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	if err := client.CallBatch(batchOf(5)); err == nil {
+		t.Fatal("expected a dropped connection to fail the batch outside persistent mode")
+	}
+
+	conns.Store(0)
+	if err := client.redial(); err != nil {
+		t.Fatal(err)
+	}
+	client.SetPersistent(true)
+	calls := batchOf(5)
+	if err := client.CallBatch(calls); err != nil {
+		t.Fatalf("CallBatch: %v", err)
+	}
+	checkBatch(t, calls)
+	if got := conns.Load(); got != 2 {
+		t.Errorf("connections = %d, want 2 (one reconnect)", got)
+	}
+}
+
+func TestClient_Call_PersistentReconnect(t *testing.T) {
+	daemon, socketPath := newMockDaemon(t)
+	defer daemon.stop()
+	// The first connection is closed by the "daemon" before any request,
+	// like an idle timeout.
+	var conns atomic.Int32
+	daemon.start(t, func(conn net.Conn) {
+		if conns.Add(1) == 1 {
+			_ = conn.Close()
+			return
+		}
+		echoHandler(0)(conn)
+	})
+	<-daemon.Ready()
+
+	client, err := NewClient(socketPath)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer func() { _ = client.Close() }()
+	client.SetPersistent(true)
+
+	var result map[string]any
+	if err := client.Call("echo", map[string]any{"n": 1}, &result); err != nil {
+		t.Fatalf("Call: %v", err)
+	}
+	if result["n"] != float64(1) {
+		t.Errorf("n = %v, want 1", result["n"])
+	}
+}
+
 func TestDefaultSocketPath(t *testing.T) {
 	// thrum-qofl (rc.6): EffectiveRepoPath now walks up from the supplied
 	// repoPath looking for a thrum worktree root. For pass-through tests
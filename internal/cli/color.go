@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"os"
+	"regexp"
+)
+
+// noColor is set from the global --no-color flag by SetNoColor.
+var noColor bool
+
+// SetNoColor records the global --no-color flag. cmd/thrum calls it before
+// any output is produced.
+func SetNoColor(v bool) {
+	noColor = v
+}
+
+// ColorEnabled reports whether output may carry terminal escape sequences:
+// false when --no-color was given or NO_COLOR is set to a non-empty value
+// (https://no-color.org). Every escape thrum writes goes through a helper in
+// this file, so this is the one place the decision is made.
+func ColorEnabled() bool {
+	return !noColor && os.Getenv("NO_COLOR") == ""
+}
+
+// Hyperlink returns text wrapped in an OSC 8 terminal hyperlink to url, or
+// the bare text when color is disabled.
+func Hyperlink(url, text string) string {
+	if !ColorEnabled() {
+		return text
+	}
+	return "\x1b]8;;" + url + "\x07" + text + "\x1b]8;;\x07"
+}
+
+// clearScreen moves the cursor home and clears the terminal.
+const clearScreen = "\033[H\033[2J"
+
+// ClearScreen returns the sequence that clears the terminal before a redraw,
+// or a blank line separating frames when color is disabled.
+func ClearScreen() string {
+	if !ColorEnabled() {
+		return "\n"
+	}
+	return clearScreen
+}
+
+// ansiEscape matches CSI sequences (colors, cursor movement) and OSC
+// sequences (hyperlinks, titles) terminated by BEL or ST.
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)`)
+
+// StripANSI removes terminal escape sequences from s. Formatters apply it
+// through Plain to text that did not come from thrum (message bodies, agent
+// intents) when color is disabled.
+func StripANSI(s string) string {
+	return ansiEscape.ReplaceAllString(s, "")
+}
+
+// Plain returns s with terminal escapes removed when color is disabled, and
+// s unchanged otherwise.
+func Plain(s string) string {
+	if ColorEnabled() {
+		return s
+	}
+	return StripANSI(s)
+}
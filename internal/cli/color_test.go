@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/leonletto/thrum/internal/types"
+)
+
+func TestHyperlink_HonorsNoColor(t *testing.T) {
+	const url = "https://thrum.team"
+	t.Setenv("NO_COLOR", "")
+	t.Cleanup(func() { SetNoColor(false) })
+
+	if got := Hyperlink(url, url); got == url {
+		t.Errorf("Hyperlink with color enabled = %q, want OSC 8 escape", got)
+	}
+
+	SetNoColor(true)
+	if got := Hyperlink(url, url); got != url {
+		t.Errorf("Hyperlink under --no-color = %q, want bare URL", got)
+	}
+	if got := ClearScreen(); got != "\n" {
+		t.Errorf("ClearScreen under --no-color = %q, want blank line", got)
+	}
+
+	SetNoColor(false)
+	t.Setenv("NO_COLOR", "1")
+	if got := Hyperlink(url, url); got != url {
+		t.Errorf("Hyperlink with NO_COLOR=1 = %q, want bare URL", got)
+	}
+}
+
+func TestStripANSI(t *testing.T) {
+	cases := map[string]string{
+		"\x1b[31mred\x1b[0m text":                             "red text",
+		"\x1b]8;;https://thrum.team\x07thrum\x1b]8;;\x07":     "thrum",
+		"\x1b]8;;https://thrum.team\x1b\\thrum\x1b]8;;\x1b\\": "thrum",
+		"\x1b[H\x1b[2Jframe":                                  "frame",
+		"plain ✓ text":                                        "plain ✓ text",
+	}
+	for in, want := range cases {
+		if got := StripANSI(in); got != want {
+			t.Errorf("StripANSI(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestFormatMessageGet_StripsEscapesUnderNoColor(t *testing.T) {
+	resp := &MessageGetResponse{Message: MessageDetail{
+		MessageID: "msg_1",
+		Author:    AuthorInfo{AgentID: "alice"},
+		Body:      types.MessageBody{Content: "\x1b[1mbold\x1b[0m body"},
+	}}
+
+	t.Setenv("NO_COLOR", "")
+	if out := FormatMessageGet(resp); !strings.Contains(out, "\x1b[1mbold") {
+		t.Errorf("body escapes removed with color enabled: %q", out)
+	}
+	t.Setenv("NO_COLOR", "1")
+	if out := FormatMessageGet(resp); !strings.Contains(out, "\nbold body\n") {
+		t.Errorf("body escapes kept under NO_COLOR: %q", out)
+	}
+}
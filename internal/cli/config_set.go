@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/leonletto/thrum/internal/config"
+	"github.com/leonletto/thrum/internal/runtime"
+)
+
+// ConfigKeyResult is the result of `thrum config get` and `thrum config set`.
+type ConfigKeyResult struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+
+	// RestartRequired is set by config set when the key is read by the
+	// daemon at startup, so the change only takes effect after a restart.
+	RestartRequired bool `json:"restart_required,omitempty"`
+}
+
+// cliOnlyConfigSections are read fresh by each CLI invocation; every other
+// section is loaded by the daemon when it starts.
+var cliOnlyConfigSections = map[string]bool{
+	"runtime":       true,
+	"worktrees":     true,
+	"orchestration": true,
+	"agents":        true,
+}
+
+// ConfigGet returns the effective value of a dotted config key, including
+// defaults applied when config.json leaves it unset.
+func ConfigGet(repoPath, key string) (*ConfigKeyResult, error) {
+	cfg, err := config.LoadThrumConfig(filepath.Join(repoPath, ".thrum"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	value, err := config.GetThrumConfigValue(cfg, key)
+	if err != nil {
+		return nil, err
+	}
+	return &ConfigKeyResult{Key: key, Value: value}, nil
+}
+
+// ConfigSet writes a dotted config key to .thrum/config.json after checking
+// the value's type and, for keys with a fixed set of values, the value itself.
+func ConfigSet(repoPath, key, value string) (*ConfigKeyResult, error) {
+	thrumDir := filepath.Join(repoPath, ".thrum")
+	if _, err := os.Stat(thrumDir); os.IsNotExist(err) {
+		return nil, fmt.Errorf("not in a thrum workspace (run thrum init first)")
+	}
+
+	switch key {
+	case "runtime.primary":
+		if !runtime.IsValidRuntime(value) {
+			return nil, fmt.Errorf("invalid runtime %q (supported: %s)", value, strings.Join(runtime.SupportedRuntimes(), ", "))
+		}
+	case "daemon.log_level":
+		switch value {
+		case "debug", "info", "warn", "error":
+		default:
+			return nil, fmt.Errorf("invalid log level %q (must be debug, info, warn, or error)", value)
+		}
+	}
+
+	if err := config.SetThrumConfigValue(thrumDir, key, value); err != nil {
+		return nil, err
+	}
+	section, _, _ := strings.Cut(key, ".")
+	return &ConfigKeyResult{
+		Key:             key,
+		Value:           value,
+		RestartRequired: !cliOnlyConfigSections[section],
+	}, nil
+}
+
+// FormatConfigSet formats the config set result for human-readable display.
+func FormatConfigSet(result *ConfigKeyResult) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "✓ %s = %s\n", result.Key, result.Value)
+	if result.RestartRequired {
+		b.WriteString("  Restart the daemon to apply: thrum daemon restart\n")
+	}
+	return b.String()
+}
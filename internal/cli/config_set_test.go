@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigSetGet(t *testing.T) {
+	repo := t.TempDir()
+	if _, err := ConfigSet(repo, "daemon.log_level", "debug"); err == nil {
+		t.Fatal("set outside a thrum workspace should fail")
+	}
+	if err := os.MkdirAll(filepath.Join(repo, ".thrum"), 0750); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ConfigSet(repo, "daemon.events_retention_days", "15")
+	if err != nil {
+		t.Fatalf("ConfigSet: %v", err)
+	}
+	if !result.RestartRequired {
+		t.Error("daemon keys should require a restart")
+	}
+	if out := FormatConfigSet(result); !strings.Contains(out, "thrum daemon restart") {
+		t.Errorf("FormatConfigSet = %q, want restart reminder", out)
+	}
+
+	got, err := ConfigGet(repo, "daemon.events_retention_days")
+	if err != nil || got.Value != "15" {
+		t.Errorf("ConfigGet = %+v, %v; want 15", got, err)
+	}
+
+	if _, err := ConfigSet(repo, "daemon.log_level", "loud"); err == nil {
+		t.Error("invalid log level should be rejected")
+	}
+	if _, err := ConfigSet(repo, "runtime.primary", "no-such-runtime"); err == nil {
+		t.Error("unknown runtime should be rejected")
+	}
+	result, err = ConfigSet(repo, "worktrees.base_path", "/tmp/wt")
+	if err != nil {
+		t.Fatalf("ConfigSet: %v", err)
+	}
+	if result.RestartRequired {
+		t.Error("worktrees keys are read by the CLI and should not require a restart")
+	}
+}
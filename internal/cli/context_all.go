@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// AgentContext is one agent's saved context as shown by
+// `thrum context show --agent all`. An agent with nothing saved has
+// HasContext false and an empty Content.
+type AgentContext struct {
+	Content    string `json:"content"`
+	Preamble   string `json:"preamble,omitempty"`
+	HasContext bool   `json:"has_context"`
+	Size       int64  `json:"size,omitempty"`
+	UpdatedAt  string `json:"updated_at,omitempty"`
+}
+
+// ContextAgents returns the IDs of the agents in an agent.list result
+// whose context `context show --agent all` iterates, sorted. Users have
+// no saved context and are skipped.
+func ContextAgents(result *ListAgentsResponse) []string {
+	var ids []string
+	for _, agent := range result.Agents {
+		if agent.Kind == "user" {
+			continue
+		}
+		ids = append(ids, agent.AgentID)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// FormatAllContexts formats every agent's context under a header, in
+// agent order. Agents without saved context are listed as empty.
+func FormatAllContexts(contexts map[string]AgentContext) string {
+	names := make([]string, 0, len(contexts))
+	for name := range contexts {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	if len(names) == 0 {
+		return "No agents registered.\n"
+	}
+
+	var out strings.Builder
+	for i, name := range names {
+		if i > 0 {
+			out.WriteString("\n")
+		}
+		ctx := contexts[name]
+		if ctx.HasContext {
+			fmt.Fprintf(&out, "=== %s (%d bytes, updated %s) ===\n\n", name, ctx.Size, ctx.UpdatedAt)
+		} else {
+			fmt.Fprintf(&out, "=== %s ===\n\n", name)
+		}
+		if ctx.Preamble != "" {
+			out.WriteString(ctx.Preamble)
+			if !strings.HasSuffix(ctx.Preamble, "\n") {
+				out.WriteString("\n")
+			}
+			out.WriteString("\n")
+		}
+		if !ctx.HasContext {
+			out.WriteString("(no context saved)\n")
+			continue
+		}
+		out.WriteString(ctx.Content)
+		if !strings.HasSuffix(ctx.Content, "\n") {
+			out.WriteString("\n")
+		}
+	}
+	return out.String()
+}
@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestContextAgents(t *testing.T) {
+	result := &ListAgentsResponse{Agents: []AgentInfo{
+		{AgentID: "reviewer", Kind: "agent"},
+		{AgentID: "user:leon", Kind: "user"},
+		{AgentID: "coordinator", Kind: "agent"},
+	}}
+	if got, want := ContextAgents(result), []string{"coordinator", "reviewer"}; !slices.Equal(got, want) {
+		t.Errorf("ContextAgents() = %v, want %v", got, want)
+	}
+}
+
+func TestFormatAllContexts(t *testing.T) {
+	got := FormatAllContexts(map[string]AgentContext{
+		"reviewer": {},
+		"coordinator": {
+			Content:    "# Plan\nship it",
+			Preamble:   "Be brief.\n",
+			HasContext: true,
+			Size:       14,
+			UpdatedAt:  "2026-03-01T12:00:00Z",
+		},
+	})
+	want := "=== coordinator (14 bytes, updated 2026-03-01T12:00:00Z) ===\n\n" +
+		"Be brief.\n\n# Plan\nship it\n" +
+		"\n=== reviewer ===\n\n(no context saved)\n"
+	if got != want {
+		t.Errorf("FormatAllContexts() =\n%q\nwant\n%q", got, want)
+	}
+
+	if got := FormatAllContexts(nil); got != "No agents registered.\n" {
+		t.Errorf("FormatAllContexts(nil) = %q", got)
+	}
+}
@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// contextBundleHeader opens every bundle written by `thrum context export`.
+// The version lets a future format be told apart; the agent is informational.
+const contextBundleHeader = "<!-- thrum context bundle v1 agent: %s -->\n"
+
+var (
+	contextBundleHeaderRe  = regexp.MustCompile(`^<!-- thrum context bundle v1 agent: (.*) -->$`)
+	contextBundleSectionRe = regexp.MustCompile(`^<!-- (preamble|context) ([0-9]+) bytes -->$`)
+)
+
+// ContextBundle is one agent's context and preamble as a single file, for
+// `thrum context export` and `thrum context import`. A nil section is
+// absent from the bundle; an empty, non-nil one is present but empty.
+type ContextBundle struct {
+	Agent    string
+	Preamble []byte
+	Context  []byte
+}
+
+// EncodeContextBundle renders b in the bundle format: a header line, then a
+// preamble and a context section, each only when present. A section is
+// framed by markers in the style of `thrum context show --raw`, and its
+// start marker records the exact byte length, so any content (including
+// text that looks like a marker, or a missing final newline) round-trips
+// through ParseContextBundle unchanged.
+func EncodeContextBundle(b *ContextBundle) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, contextBundleHeader, b.Agent)
+	for _, s := range []struct {
+		name    string
+		content []byte
+	}{{"preamble", b.Preamble}, {"context", b.Context}} {
+		if s.content == nil {
+			continue
+		}
+		fmt.Fprintf(&buf, "<!-- %s %d bytes -->\n", s.name, len(s.content))
+		buf.Write(s.content)
+		if !bytes.HasSuffix(s.content, []byte("\n")) {
+			buf.WriteByte('\n')
+		}
+		fmt.Fprintf(&buf, "<!-- end %s -->\n", s.name)
+	}
+	return buf.Bytes()
+}
+
+// ParseContextBundle parses a bundle written by EncodeContextBundle. A
+// bundle whose section lengths no longer match its markers (for example
+// after hand editing) is rejected rather than imported partially.
+func ParseContextBundle(data []byte) (*ContextBundle, error) {
+	line, rest, ok := bytes.Cut(data, []byte("\n"))
+	m := contextBundleHeaderRe.FindSubmatch(line)
+	if !ok || m == nil {
+		return nil, fmt.Errorf("not a thrum context bundle (missing header line)")
+	}
+	b := &ContextBundle{Agent: string(m[1])}
+
+	for len(rest) > 0 {
+		line, rest, ok = bytes.Cut(rest, []byte("\n"))
+		m = contextBundleSectionRe.FindSubmatch(line)
+		if !ok || m == nil {
+			return nil, fmt.Errorf("invalid context bundle: unexpected line %q", line)
+		}
+		name := string(m[1])
+		if (name == "preamble" && b.Preamble != nil) || (name == "context" && b.Context != nil) {
+			return nil, fmt.Errorf("invalid context bundle: duplicate %s section", name)
+		}
+		size, err := strconv.Atoi(string(m[2]))
+		if err != nil || size > len(rest) {
+			return nil, fmt.Errorf("invalid context bundle: %s section is truncated", name)
+		}
+		content := append([]byte{}, rest[:size]...)
+		rest = rest[size:]
+		if !bytes.HasSuffix(content, []byte("\n")) {
+			if !bytes.HasPrefix(rest, []byte("\n")) {
+				return nil, fmt.Errorf("invalid context bundle: %s section does not match its length (was the file edited?)", name)
+			}
+			rest = rest[1:]
+		}
+		end := fmt.Sprintf("<!-- end %s -->\n", name)
+		if !bytes.HasPrefix(rest, []byte(end)) {
+			return nil, fmt.Errorf("invalid context bundle: %s section does not match its length (was the file edited?)", name)
+		}
+		rest = rest[len(end):]
+
+		if name == "preamble" {
+			b.Preamble = content
+		} else {
+			b.Context = content
+		}
+	}
+	return b, nil
+}
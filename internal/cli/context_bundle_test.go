@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestContextBundle_RoundTrip(t *testing.T) {
+	cases := []struct {
+		name string
+		b    ContextBundle
+	}{
+		{"both", ContextBundle{Agent: "coordinator", Preamble: []byte("# Preamble\n"), Context: []byte("## Progress\n- done\n")}},
+		{"no final newline", ContextBundle{Agent: "a", Preamble: []byte("header"), Context: []byte("tail")}},
+		{"marker-like content", ContextBundle{Agent: "a", Context: []byte("<!-- end context -->\n<!-- preamble 3 bytes -->\n")}},
+		{"context only", ContextBundle{Agent: "a", Context: []byte("ctx\n")}},
+		{"preamble only", ContextBundle{Agent: "a", Preamble: []byte("pre\n\n")}},
+		{"empty section", ContextBundle{Agent: "a", Context: []byte{}}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseContextBundle(EncodeContextBundle(&tc.b))
+			if err != nil {
+				t.Fatalf("ParseContextBundle: %v", err)
+			}
+			if got.Agent != tc.b.Agent {
+				t.Errorf("agent = %q, want %q", got.Agent, tc.b.Agent)
+			}
+			if !bytes.Equal(got.Preamble, tc.b.Preamble) || (got.Preamble == nil) != (tc.b.Preamble == nil) {
+				t.Errorf("preamble = %q, want %q", got.Preamble, tc.b.Preamble)
+			}
+			if !bytes.Equal(got.Context, tc.b.Context) || (got.Context == nil) != (tc.b.Context == nil) {
+				t.Errorf("context = %q, want %q", got.Context, tc.b.Context)
+			}
+		})
+	}
+}
+
+func TestContextBundle_Format(t *testing.T) {
+	out := string(EncodeContextBundle(&ContextBundle{Agent: "coordinator", Preamble: []byte("pre\n"), Context: []byte("ctx")}))
+	want := "<!-- thrum context bundle v1 agent: coordinator -->\n" +
+		"<!-- preamble 4 bytes -->\npre\n<!-- end preamble -->\n" +
+		"<!-- context 3 bytes -->\nctx\n<!-- end context -->\n"
+	if out != want {
+		t.Errorf("bundle =\n%s\nwant\n%s", out, want)
+	}
+}
+
+func TestParseContextBundle_Invalid(t *testing.T) {
+	valid := string(EncodeContextBundle(&ContextBundle{Agent: "a", Context: []byte("hello\n")}))
+	for name, data := range map[string]string{
+		"no header": "# just markdown\n",
+		"edited":    strings.Replace(valid, "hello", "hello there", 1),
+		"truncated": valid[:len(valid)-10],
+		"duplicate": valid + valid[strings.Index(valid, "<!-- context"):],
+		"stray":     valid + "trailing text\n",
+	} {
+		if _, err := ParseContextBundle([]byte(data)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
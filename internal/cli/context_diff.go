@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// contextDiffLines is how many unchanged lines surround each change in a
+// `thrum context diff` hunk, as in diff -u.
+const contextDiffLines = 3
+
+// ContextDiffHunk is one hunk of a unified diff. Starts are 1-based; an
+// empty side starts at the line before the change, as in diff -u. Each
+// line is prefixed with ' ', '-' or '+' and has no trailing newline.
+type ContextDiffHunk struct {
+	OldStart int      `json:"old_start"`
+	OldLines int      `json:"old_lines"`
+	NewStart int      `json:"new_start"`
+	NewLines int      `json:"new_lines"`
+	Lines    []string `json:"lines"`
+}
+
+// ContextDiffResult compares an agent's saved context with a candidate file.
+type ContextDiffResult struct {
+	AgentName  string            `json:"agent_name"`
+	File       string            `json:"file"`
+	HasContext bool              `json:"has_context"` // false: nothing saved, every file line is an addition
+	Identical  bool              `json:"identical"`
+	Hunks      []ContextDiffHunk `json:"hunks"`
+}
+
+// DiffContext computes the unified-diff hunks turning saved into candidate.
+// It returns no hunks when the two are identical.
+func DiffContext(saved, candidate string) []ContextDiffHunk {
+	a, b := diffLines(saved), diffLines(candidate)
+	m := difflib.NewMatcher(a, b)
+
+	hunks := []ContextDiffHunk{}
+	for _, group := range m.GetGroupedOpCodes(contextDiffLines) {
+		if len(group) == 1 && group[0].Tag == 'e' {
+			continue // identical inputs still yield one all-equal group
+		}
+		first, last := group[0], group[len(group)-1]
+		h := ContextDiffHunk{
+			OldStart: hunkStart(first.I1, last.I2),
+			OldLines: last.I2 - first.I1,
+			NewStart: hunkStart(first.J1, last.J2),
+			NewLines: last.J2 - first.J1,
+		}
+		for _, op := range group {
+			if op.Tag == 'e' {
+				for _, line := range a[op.I1:op.I2] {
+					h.Lines = append(h.Lines, " "+line)
+				}
+				continue
+			}
+			if op.Tag == 'r' || op.Tag == 'd' {
+				for _, line := range a[op.I1:op.I2] {
+					h.Lines = append(h.Lines, "-"+line)
+				}
+			}
+			if op.Tag == 'r' || op.Tag == 'i' {
+				for _, line := range b[op.J1:op.J2] {
+					h.Lines = append(h.Lines, "+"+line)
+				}
+			}
+		}
+		hunks = append(hunks, h)
+	}
+	return hunks
+}
+
+// diffLines splits s into lines without their newlines. A trailing newline
+// does not start an extra empty line.
+func diffLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// hunkStart converts a 0-based range start to a unified-diff line number.
+func hunkStart(start, stop int) int {
+	if start == stop {
+		return start // empty range: the line before, 1-based
+	}
+	return start + 1
+}
+
+// FormatContextDiff renders the result as a unified diff, saved context on
+// the "---" side and the file on the "+++" side.
+func FormatContextDiff(result *ContextDiffResult) string {
+	if result.Identical {
+		return fmt.Sprintf("Saved context for %s matches %s.\n", result.AgentName, result.File)
+	}
+
+	var b strings.Builder
+	if !result.HasContext {
+		fmt.Fprintf(&b, "No context saved for %s; showing %s as new.\n", result.AgentName, result.File)
+	}
+	fmt.Fprintf(&b, "--- context/%s (saved)\n", result.AgentName)
+	fmt.Fprintf(&b, "+++ %s\n", result.File)
+	for _, h := range result.Hunks {
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n", hunkRange(h.OldStart, h.OldLines), hunkRange(h.NewStart, h.NewLines))
+		for _, line := range h.Lines {
+			b.WriteString(line)
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}
+
+// hunkRange formats a hunk side as diff -u does: the length is omitted when
+// it is 1.
+func hunkRange(start, lines int) string {
+	if lines == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, lines)
+}
@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiffContext(t *testing.T) {
+	saved := "# Context\n\nstep 1\nstep 2\nstep 3\n"
+	candidate := "# Context\n\nstep 1\nstep 2b\nstep 3\nstep 4\n"
+
+	hunks := DiffContext(saved, candidate)
+	if len(hunks) != 1 {
+		t.Fatalf("hunks = %+v, want 1", hunks)
+	}
+	h := hunks[0]
+	if h.OldStart != 1 || h.OldLines != 5 || h.NewStart != 1 || h.NewLines != 6 {
+		t.Errorf("hunk range = -%d,%d +%d,%d, want -1,5 +1,6", h.OldStart, h.OldLines, h.NewStart, h.NewLines)
+	}
+	want := []string{" # Context", " ", " step 1", "-step 2", "+step 2b", " step 3", "+step 4"}
+	if strings.Join(h.Lines, "|") != strings.Join(want, "|") {
+		t.Errorf("lines = %q, want %q", h.Lines, want)
+	}
+
+	if got := DiffContext(saved, saved); len(got) != 0 {
+		t.Errorf("identical inputs: hunks = %+v, want none", got)
+	}
+	if got := DiffContext("", ""); len(got) != 0 {
+		t.Errorf("empty inputs: hunks = %+v, want none", got)
+	}
+}
+
+func TestFormatContextDiff_NoSavedContext(t *testing.T) {
+	result := &ContextDiffResult{
+		AgentName: "impl_api",
+		File:      "ctx.md",
+		Hunks:     DiffContext("", "line one\nline two\n"),
+	}
+	out := FormatContextDiff(result)
+	for _, want := range []string{
+		"No context saved for impl_api",
+		"--- context/impl_api (saved)\n+++ ctx.md\n",
+		"@@ -0,0 +1,2 @@\n+line one\n+line two\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	same := FormatContextDiff(&ContextDiffResult{AgentName: "impl_api", File: "ctx.md", HasContext: true, Identical: true})
+	if !strings.Contains(same, "matches ctx.md") {
+		t.Errorf("identical output = %q", same)
+	}
+}
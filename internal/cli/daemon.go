@@ -2,9 +2,13 @@ package cli
 
 import (
 	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
@@ -22,30 +26,32 @@ type DaemonStatusResult struct {
 	Version       string        `json:"version,omitempty"`
 	SyncState     string        `json:"sync_state,omitempty"`
 	WebSocketPort int           `json:"ws_port,omitempty"`
+	SocketPath    string        `json:"socket_path,omitempty"`
 	Identity      *IdentityInfo `json:"identity,omitempty"`
+	// Healthy is true once the daemon answers the health RPC on its socket.
+	// A running daemon that is not yet healthy is still starting up.
+	Healthy bool `json:"healthy"`
 }
 
-// DaemonStart starts the daemon in the background.
-// When localOnly is true, the --local flag is passed to the daemon subprocess.
-// When force is true, the --force flag is passed so the daemon's G2 guard
-// accepts non-git-anchored directories.
-func DaemonStart(repoPath string, localOnly bool, force bool) error {
-	// Convert to absolute path so the daemon knows where to run
+// DaemonCheckNotRunning returns an error when a daemon is already running
+// for repoPath. Used by 'daemon start --foreground', which runs the daemon
+// in-process and so can't rely on DaemonStart's check before the fork.
+func DaemonCheckNotRunning(repoPath string) error {
 	absPath, err := filepath.Abs(repoPath)
 	if err != nil {
 		return fmt.Errorf("failed to resolve repo path: %w", err)
 	}
-	repoPath = absPath
-
-	thrumDir, err := paths.ResolveThrumDir(repoPath)
+	thrumDir, err := paths.ResolveThrumDir(absPath)
 	if err != nil {
-		thrumDir = filepath.Join(repoPath, ".thrum")
+		thrumDir = filepath.Join(absPath, ".thrum")
 	}
-	pidPath := filepath.Join(thrumDir, "var", "thrum.pid")
-	socketPath := filepath.Join(thrumDir, "var", "thrum.sock")
+	return checkDaemonNotRunning(thrumDir, absPath)
+}
 
-	// Check if daemon is already running
-	running, pidInfo, err := daemon.CheckPIDFileJSON(pidPath)
+// checkDaemonNotRunning reads the PID file under thrumDir. A live daemon for
+// another repo sharing it is only warned about.
+func checkDaemonNotRunning(thrumDir, repoPath string) error {
+	running, pidInfo, err := daemon.CheckPIDFileJSON(filepath.Join(thrumDir, "var", "thrum.pid"))
 	if err != nil {
 		return fmt.Errorf("failed to check daemon status: %w", err)
 	}
@@ -59,6 +65,42 @@ func DaemonStart(repoPath string, localOnly bool, force bool) error {
 		fmt.Fprintf(os.Stderr, "WARNING: Daemon PID %d is running for different repo %s, proceeding\n",
 			pidInfo.PID, pidInfo.RepoPath)
 	}
+	return nil
+}
+
+// DaemonStartOptions contains options for starting the daemon.
+type DaemonStartOptions struct {
+	LocalOnly  bool   // Pass --local: skip git push/fetch in the sync loop
+	Force      bool   // Pass --force: the daemon's G2 guard accepts non-git-anchored directories
+	SocketPath string // Listen here instead of .thrum/var/thrum.sock (daemon start --socket)
+	WSPort     int    // WebSocket port (daemon start --ws-port); 0 defers to THRUM_WS_PORT and config.json
+}
+
+// DaemonStart starts the daemon in the background. The daemon records its
+// socket in the PID file, so DaemonStatus and clients using
+// DefaultSocketPath find a custom opts.SocketPath without being told.
+func DaemonStart(repoPath string, opts DaemonStartOptions) error {
+	// Convert to absolute path so the daemon knows where to run
+	absPath, err := filepath.Abs(repoPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve repo path: %w", err)
+	}
+	repoPath = absPath
+
+	thrumDir, err := paths.ResolveThrumDir(repoPath)
+	if err != nil {
+		thrumDir = filepath.Join(repoPath, ".thrum")
+	}
+	socketPath := filepath.Join(thrumDir, "var", "thrum.sock")
+	if opts.SocketPath != "" {
+		if socketPath, err = filepath.Abs(opts.SocketPath); err != nil {
+			return fmt.Errorf("failed to resolve socket path: %w", err)
+		}
+	}
+
+	if err := checkDaemonNotRunning(thrumDir, repoPath); err != nil {
+		return err
+	}
 
 	// Get the path to the current executable
 	executable, err := os.Executable()
@@ -68,12 +110,18 @@ func DaemonStart(repoPath string, localOnly bool, force bool) error {
 
 	// Build command to start daemon
 	args := []string{"daemon", "run", "--repo", repoPath}
-	if localOnly {
+	if opts.LocalOnly {
 		args = append(args, "--local")
 	}
-	if force {
+	if opts.Force {
 		args = append(args, "--force")
 	}
+	if opts.SocketPath != "" {
+		args = append(args, "--socket", socketPath)
+	}
+	if opts.WSPort > 0 {
+		args = append(args, "--ws-port", strconv.Itoa(opts.WSPort))
+	}
 	cmd := exec.Command(executable, args...) // #nosec G204 -- executable from os.Executable(); repoPath is validated internal config, not raw user input
 
 	// Open the daemon log file so the forked process inherits valid fds for
@@ -118,7 +166,25 @@ func DaemonStart(repoPath string, localOnly bool, force bool) error {
 	// migration progress, or a frozen heartbeat) still times out within a
 	// bounded window.
 	wsPortPath := filepath.Join(thrumDir, "var", "ws.port")
-	return waitForDaemonReady(daemonStartWaitDefaults(socketPath, wsPortPath, varDir))
+	if err := waitForDaemonReady(daemonStartWaitDefaults(socketPath, wsPortPath, varDir)); err != nil {
+		return err
+	}
+
+	// Record the daemon in the per-user registry for `daemon status --all`.
+	// Best-effort: the daemon is up either way.
+	if info, err := daemon.ReadPIDFileJSON(filepath.Join(varDir, "thrum.pid")); err == nil {
+		if info.SocketPath != "" {
+			socketPath = info.SocketPath
+		}
+		_ = daemon.RegisterDaemon(daemon.RegistryEntry{
+			RepoPath:   repoPath,
+			ThrumDir:   thrumDir,
+			PID:        info.PID,
+			SocketPath: socketPath,
+			StartedAt:  info.StartedAt,
+		})
+	}
+	return nil
 }
 
 // DaemonStop stops the daemon gracefully.
@@ -182,13 +248,17 @@ func DaemonStatus(repoPath string) (*DaemonStatusResult, error) {
 		thrumDir = filepath.Join(repoPath, ".thrum")
 	}
 	pidPath := filepath.Join(thrumDir, "var", "thrum.pid")
-	socketPath := filepath.Join(thrumDir, "var", "thrum.sock")
 
 	// Check if daemon is running
 	running, pidInfo, err := daemon.CheckPIDFileJSON(pidPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check daemon status: %w", err)
 	}
+	// A daemon started with --socket records where it listens.
+	socketPath := pidInfo.SocketPath
+	if socketPath == "" {
+		socketPath = filepath.Join(thrumDir, "var", "thrum.sock")
+	}
 
 	status := "stopped"
 	if running {
@@ -206,6 +276,7 @@ func DaemonStatus(repoPath string) (*DaemonStatusResult, error) {
 	if running {
 		// Read WebSocket port
 		result.WebSocketPort = ReadWebSocketPort(repoPath)
+		result.SocketPath = socketPath
 
 		// Check if socket exists
 		if _, err := os.Stat(socketPath); err == nil {
@@ -216,6 +287,7 @@ func DaemonStatus(repoPath string) (*DaemonStatusResult, error) {
 
 				var health HealthResult
 				if err := client.Call("health", map[string]any{}, &health); err == nil {
+					result.Healthy = health.Status == "ok"
 					// Format uptime
 					uptime := time.Duration(health.UptimeMs) * time.Millisecond
 					result.Uptime = formatDuration(uptime)
@@ -230,12 +302,109 @@ func DaemonStatus(repoPath string) (*DaemonStatusResult, error) {
 	return result, nil
 }
 
+// DaemonStatusAllResult lists the running daemons found in the per-user
+// registry, across repos.
+type DaemonStatusAllResult struct {
+	Daemons []DaemonStatusResult `json:"daemons"`
+	// Pruned lists the repo paths of registry entries removed because
+	// their daemon is no longer running.
+	Pruned []string `json:"pruned,omitempty"`
+}
+
+// DaemonStatusAll reports every running daemon recorded in the registry
+// that DaemonStart writes under $HOME/.thrum/daemons/, pruning entries
+// whose process has exited.
+func DaemonStatusAll() (*DaemonStatusAllResult, error) {
+	live, pruned, err := daemon.ListRegisteredDaemons()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DaemonStatusAllResult{Daemons: []DaemonStatusResult{}}
+	for _, entry := range pruned {
+		repo := entry.RepoPath
+		if repo == "" {
+			repo = "(unreadable entry)"
+		}
+		result.Pruned = append(result.Pruned, repo)
+	}
+	for _, entry := range live {
+		status, err := DaemonStatus(entry.RepoPath)
+		if err != nil || !status.Running {
+			// Exited between the registry check and now.
+			continue
+		}
+		if status.RepoPath == "" {
+			status.RepoPath = entry.RepoPath
+		}
+		result.Daemons = append(result.Daemons, *status)
+	}
+	return result, nil
+}
+
+// FormatDaemonStatusAll formats the cross-repo daemon list for display.
+func FormatDaemonStatusAll(result *DaemonStatusAllResult) string {
+	var b strings.Builder
+	if len(result.Daemons) == 0 {
+		b.WriteString("No running daemons found (daemons register when started with 'thrum daemon start')\n")
+	} else {
+		fmt.Fprintf(&b, "%-8s %-8s %-8s %s\n", "PID", "WS PORT", "UPTIME", "REPO")
+		for _, d := range result.Daemons {
+			port := "-"
+			if d.WebSocketPort > 0 {
+				port = fmt.Sprintf("%d", d.WebSocketPort)
+			}
+			uptime := d.Uptime
+			if uptime == "" {
+				uptime = "-"
+			}
+			fmt.Fprintf(&b, "%-8d %-8s %-8s %s\n", d.PID, port, uptime, d.RepoPath)
+			if d.SocketPath != "" {
+				fmt.Fprintf(&b, "%-26s socket: %s\n", "", d.SocketPath)
+			}
+		}
+	}
+	for _, repo := range result.Pruned {
+		fmt.Fprintf(&b, "Pruned stale entry: %s\n", repo)
+	}
+	return b.String()
+}
+
+// daemonWaitInterval is how often DaemonWaitHealthy polls the daemon.
+var daemonWaitInterval = 100 * time.Millisecond
+
+// DaemonWaitHealthy polls DaemonStatus until the daemon answers the health
+// RPC or timeout elapses. On timeout it returns the last status along with an
+// error that tells a daemon that was never started apart from one that is
+// running but not yet listening.
+func DaemonWaitHealthy(repoPath string, timeout time.Duration) (*DaemonStatusResult, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		result, err := DaemonStatus(repoPath)
+		if err != nil {
+			return nil, err
+		}
+		if result.Healthy {
+			return result, nil
+		}
+		if !time.Now().Before(deadline) {
+			if !result.Running {
+				return result, fmt.Errorf("daemon not started after %s — start it with: thrum daemon start", timeout)
+			}
+			return result, fmt.Errorf("daemon started (PID %d) but not yet listening after %s", result.PID, timeout)
+		}
+		time.Sleep(daemonWaitInterval)
+	}
+}
+
 // DaemonRestart restarts the daemon (stop + start).
 // When localOnly is true, the restarted daemon runs in local-only mode.
 // When force is true, the daemon's G2 guard accepts non-git-anchored dirs.
+// A socket set with daemon start --socket is kept.
 func DaemonRestart(repoPath string, localOnly bool, force bool) error {
 	// Read the previous WebSocket port before stopping (DaemonStop deletes ws.port)
 	prevPort := ReadWebSocketPort(repoPath)
+	opts := DaemonStartOptions{LocalOnly: localOnly, Force: force, SocketPath: customSocketPath(repoPath)}
 
 	// Try to stop daemon (ignore error if not running)
 	_ = DaemonStop(repoPath)
@@ -250,7 +419,22 @@ func DaemonRestart(repoPath string, localOnly bool, force bool) error {
 	}
 
 	// Start daemon
-	return DaemonStart(repoPath, localOnly, force)
+	return DaemonStart(repoPath, opts)
+}
+
+// customSocketPath returns the socket a running daemon for repoPath
+// recorded in its PID file when it differs from .thrum/var/thrum.sock, or
+// "" when the daemon is down or listens in the default place.
+func customSocketPath(repoPath string) string {
+	thrumDir, err := paths.ResolveThrumDir(paths.EffectiveRepoPath(repoPath))
+	if err != nil {
+		return ""
+	}
+	running, info, err := daemon.CheckPIDFileJSON(filepath.Join(thrumDir, "var", "thrum.pid"))
+	if err != nil || !running || info.SocketPath == filepath.Join(thrumDir, "var", "thrum.sock") {
+		return ""
+	}
+	return info.SocketPath
 }
 
 // FormatDaemonStatus formats the daemon status for display.
@@ -260,6 +444,9 @@ func FormatDaemonStatus(result *DaemonStatusResult) string {
 	}
 
 	status := fmt.Sprintf("Daemon:   running (PID %d)\n", result.PID)
+	if !result.Healthy {
+		status += "Health:   not yet listening\n"
+	}
 	if result.Uptime != "" {
 		status += fmt.Sprintf("Uptime:   %s\n", result.Uptime)
 	}
@@ -298,3 +485,72 @@ func FormatDaemonStatus(result *DaemonStatusResult) string {
 
 	return status
 }
+
+// DaemonMetrics fetches the Prometheus text exposition from the daemon's
+// /metrics endpoint on the loopback WebSocket port. The endpoint only exists
+// when daemon.metrics_enabled is set in .thrum/config.json.
+func DaemonMetrics(repoPath string) (string, error) {
+	port := ReadWebSocketPort(repoPath)
+	if port == 0 {
+		return "", fmt.Errorf("daemon is not running (no ws.port file)")
+	}
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Get(fmt.Sprintf("http://127.0.0.1:%d/metrics", port))
+	if err != nil {
+		return "", fmt.Errorf("fetch metrics: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("read metrics: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/plain") {
+		return "", fmt.Errorf("metrics endpoint unavailable (HTTP %d) — set \"daemon\": {\"metrics_enabled\": true} in .thrum/config.json and restart the daemon", resp.StatusCode)
+	}
+	return string(body), nil
+}
+
+// ConfigChange is one setting that differs between the running daemon and
+// .thrum/config.json.
+type ConfigChange struct {
+	Setting string `json:"setting"`
+	Old     string `json:"old"`
+	New     string `json:"new"`
+}
+
+// DaemonReloadResult represents the response from the daemon.reload RPC.
+type DaemonReloadResult struct {
+	Changed         []ConfigChange `json:"changed"`
+	RestartRequired []ConfigChange `json:"restart_required"`
+}
+
+// DaemonReload asks the running daemon to re-read .thrum/config.json and
+// apply the settings that can change without a restart.
+func DaemonReload(client *Client) (*DaemonReloadResult, error) {
+	var result DaemonReloadResult
+	if err := client.Call("daemon.reload", map[string]any{}, &result); err != nil {
+		return nil, fmt.Errorf("daemon.reload RPC failed: %w", err)
+	}
+	return &result, nil
+}
+
+// FormatDaemonReload formats the daemon.reload result for display.
+func FormatDaemonReload(result *DaemonReloadResult) string {
+	var out strings.Builder
+	out.WriteString("✓ Config reloaded")
+	if len(result.Changed) == 0 {
+		out.WriteString(" (no changes applied)\n")
+	} else {
+		out.WriteString("\n")
+		for _, c := range result.Changed {
+			fmt.Fprintf(&out, "  %s: %s → %s\n", c.Setting, c.Old, c.New)
+		}
+	}
+	for _, c := range result.RestartRequired {
+		fmt.Fprintf(&out, "⚠ %s changed (%s → %s); restart the daemon to apply (thrum daemon restart)\n",
+			c.Setting, c.Old, c.New)
+	}
+	return out.String()
+}
@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/leonletto/thrum/internal/config"
+	"github.com/leonletto/thrum/internal/daemon"
+	"github.com/leonletto/thrum/internal/daemon/gc"
+	"github.com/leonletto/thrum/internal/daemon/safedb"
+	"github.com/leonletto/thrum/internal/paths"
+	"github.com/leonletto/thrum/internal/schema"
+	"github.com/leonletto/thrum/internal/sync/compact"
+)
+
+// DaemonGC runs gc.Run against the repo's database with the daemon stopped.
+// A running daemon is refused: it holds the journal and database open, and
+// has daemon.gc_interval for the same work. When a peer is behind and force
+// is false, the partial result (LaggingPeers) is returned with an error
+// wrapping gc.ErrPeersBehind.
+func DaemonGC(repoPath string, force bool) (*gc.Result, error) {
+	absPath, err := filepath.Abs(repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve repo path: %w", err)
+	}
+	thrumDir, err := paths.ResolveThrumDir(absPath)
+	if err != nil {
+		thrumDir = filepath.Join(absPath, ".thrum")
+	}
+	if err := checkDaemonNotRunning(thrumDir, absPath); err != nil {
+		return nil, fmt.Errorf("%w — stop it first with: thrum daemon stop", err)
+	}
+	syncDir, err := paths.SyncWorktreePath(absPath)
+	if err != nil {
+		return nil, fmt.Errorf("resolve sync worktree: %w", err)
+	}
+
+	cfg, err := config.LoadThrumConfig(thrumDir)
+	if err != nil {
+		return nil, fmt.Errorf("load config: %w", err)
+	}
+
+	registry, err := daemon.NewPeerRegistry(filepath.Join(thrumDir, "var", "peers.json"))
+	if err != nil {
+		return nil, fmt.Errorf("load peers: %w", err)
+	}
+	var peers []gc.Peer
+	for _, p := range registry.ListPeers() {
+		peers = append(peers, gc.Peer{Name: p.Name, DaemonID: p.DaemonID, PulledSequence: p.PulledSequence})
+	}
+
+	db, err := schema.OpenDB(filepath.Join(thrumDir, "var", "messages.db"))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = db.Close() }()
+	version, err := schema.GetSchemaVersion(db)
+	if err != nil {
+		return nil, fmt.Errorf("read schema version: %w", err)
+	}
+	if version != schema.CurrentVersion {
+		return nil, fmt.Errorf("database is at schema v%d but this thrum expects v%d — start the daemon once to migrate it, stop it, then re-run gc",
+			version, schema.CurrentVersion)
+	}
+
+	result, err := gc.Run(context.Background(), safedb.New(db), gc.Options{
+		ThrumDir: thrumDir,
+		Compactor: compact.New(thrumDir, syncDir,
+			cfg.Daemon.EventsRetentionDays, cfg.Daemon.CompactionSizeThresholdMB),
+		Peers: peers,
+		Force: force,
+	})
+	if errors.Is(err, gc.ErrPeersBehind) {
+		return result, fmt.Errorf("%w: %s", err, formatLaggingPeers(result))
+	}
+	return result, err
+}
+
+// formatLaggingPeers lists the peers holding up gc, for the refusal message.
+func formatLaggingPeers(result *gc.Result) string {
+	var out strings.Builder
+	fmt.Fprintf(&out, "latest sequence is %d\n", result.LatestSequence)
+	for _, p := range result.LaggingPeers {
+		fmt.Fprintf(&out, "  %s (%s) has pulled through %d\n", p.Name, p.DaemonID, p.PulledSequence)
+	}
+	out.WriteString("Start the daemon and let peers sync, or re-run with --force to drop events they have not pulled.")
+	return out.String()
+}
+
+// FormatDaemonGC formats a completed gc.Result for display.
+func FormatDaemonGC(result *gc.Result) string {
+	var out strings.Builder
+	out.WriteString("✓ GC complete\n")
+	fmt.Fprintf(&out, "  Superseded events dropped: %d from the events table, %d from the journal\n",
+		result.EventsDropped, result.JournalDropped)
+	fmt.Fprintf(&out, "  Database: %s → %s\n", formatSize(result.DBBytesBefore), formatSize(result.DBBytesAfter))
+	if result.Forced {
+		fmt.Fprintf(&out, "  Forced past %d peer(s) that had not pulled every event\n", len(result.LaggingPeers))
+	}
+	for i, path := range result.Snapshot {
+		label := "  Snapshot: "
+		if i > 0 {
+			label = "            "
+		}
+		fmt.Fprintf(&out, "%s%s\n", label, path)
+	}
+	return out.String()
+}
+
+// formatSize renders a byte count as B, KB or MB.
+func formatSize(n int64) string {
+	switch {
+	case n >= 1024*1024:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1024*1024))
+	case n >= 1024:
+		return fmt.Sprintf("%.1f KB", float64(n)/1024)
+	}
+	return fmt.Sprintf("%d B", n)
+}
@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/leonletto/thrum/internal/daemon/gc"
+)
+
+func TestFormatDaemonGC(t *testing.T) {
+	out := FormatDaemonGC(&gc.Result{
+		EventsDropped:  12,
+		JournalDropped: 10,
+		DBBytesBefore:  3 * 1024 * 1024,
+		DBBytesAfter:   512 * 1024,
+		Forced:         true,
+		LaggingPeers:   []gc.Peer{{Name: "server", DaemonID: "d_server"}},
+		Snapshot:       []string{"/r/.thrum/var/messages.db.pre-gc.bak", "/r/.thrum/var/events.jsonl.pre-gc.bak"},
+	})
+
+	for _, want := range []string{
+		"12 from the events table, 10 from the journal",
+		"3.0 MB → 512.0 KB",
+		"Forced past 1 peer(s)",
+		"Snapshot: /r/.thrum/var/messages.db.pre-gc.bak",
+		"            /r/.thrum/var/events.jsonl.pre-gc.bak",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestFormatLaggingPeers(t *testing.T) {
+	out := formatLaggingPeers(&gc.Result{
+		LatestSequence: 40,
+		LaggingPeers:   []gc.Peer{{Name: "laptop", DaemonID: "d_laptop", PulledSequence: 31}},
+	})
+	if !strings.Contains(out, "latest sequence is 40") || !strings.Contains(out, "laptop (d_laptop) has pulled through 31") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+	if !strings.Contains(out, "--force") {
+		t.Errorf("output should mention --force:\n%s", out)
+	}
+}
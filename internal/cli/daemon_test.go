@@ -3,7 +3,9 @@ package cli
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/leonletto/thrum/internal/daemon"
 )
@@ -60,6 +62,101 @@ func TestDaemonStatus_Running(t *testing.T) {
 	}
 }
 
+func TestDaemonStatus_CustomSocket(t *testing.T) {
+	tmpDir := t.TempDir()
+	pidPath := filepath.Join(tmpDir, ".thrum", "var", "thrum.pid")
+	socketPath := filepath.Join(t.TempDir(), "custom.sock")
+
+	// A daemon started with --socket records it in the PID file.
+	if err := daemon.WritePIDFileJSON(pidPath, daemon.PIDInfo{PID: os.Getpid(), RepoPath: tmpDir, SocketPath: socketPath}); err != nil {
+		t.Fatalf("write PID file: %v", err)
+	}
+
+	result, err := DaemonStatus(tmpDir)
+	if err != nil {
+		t.Fatalf("DaemonStatus failed: %v", err)
+	}
+	if result.SocketPath != socketPath {
+		t.Errorf("SocketPath = %q, want %q", result.SocketPath, socketPath)
+	}
+	if got := DefaultSocketPath(tmpDir); got != socketPath {
+		t.Errorf("DefaultSocketPath() = %q, want %q", got, socketPath)
+	}
+
+	// Once that daemon is gone, clients fall back to the default socket.
+	if err := daemon.WritePIDFileJSON(pidPath, daemon.PIDInfo{PID: 999999999, RepoPath: tmpDir, SocketPath: socketPath}); err != nil {
+		t.Fatalf("write PID file: %v", err)
+	}
+	if got, want := DefaultSocketPath(tmpDir), filepath.Join(tmpDir, ".thrum", "var", "thrum.sock"); got != want {
+		t.Errorf("DefaultSocketPath() after exit = %q, want %q", got, want)
+	}
+}
+
+func TestDaemonCheckNotRunning(t *testing.T) {
+	tmpDir := t.TempDir()
+	pidPath := filepath.Join(tmpDir, ".thrum", "var", "thrum.pid")
+
+	if err := DaemonCheckNotRunning(tmpDir); err != nil {
+		t.Fatalf("no PID file: %v", err)
+	}
+
+	// A live PID for another repo only warns.
+	if err := daemon.WritePIDFileJSON(pidPath, daemon.PIDInfo{PID: os.Getpid(), RepoPath: "/elsewhere"}); err != nil {
+		t.Fatalf("write PID file: %v", err)
+	}
+	if err := DaemonCheckNotRunning(tmpDir); err != nil {
+		t.Errorf("daemon for another repo: %v", err)
+	}
+
+	if err := daemon.WritePIDFileJSON(pidPath, daemon.PIDInfo{PID: os.Getpid(), RepoPath: tmpDir}); err != nil {
+		t.Fatalf("write PID file: %v", err)
+	}
+	err := DaemonCheckNotRunning(tmpDir)
+	if err == nil || !strings.Contains(err.Error(), "already running") {
+		t.Errorf("daemon for this repo: err = %v", err)
+	}
+}
+
+func TestDaemonWaitHealthy(t *testing.T) {
+	daemonWaitInterval = 10 * time.Millisecond
+	t.Cleanup(func() { daemonWaitInterval = 100 * time.Millisecond })
+
+	t.Run("not started", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		if err := os.MkdirAll(filepath.Join(tmpDir, ".thrum", "var"), 0700); err != nil {
+			t.Fatalf("Failed to create var directory: %v", err)
+		}
+
+		result, err := DaemonWaitHealthy(tmpDir, 50*time.Millisecond)
+		if err == nil || !strings.Contains(err.Error(), "not started") {
+			t.Fatalf("expected not-started error, got %v", err)
+		}
+		if result.Running || result.Healthy {
+			t.Errorf("expected stopped daemon, got %+v", result)
+		}
+	})
+
+	t.Run("started but not listening", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		varDir := filepath.Join(tmpDir, ".thrum", "var")
+		if err := os.MkdirAll(varDir, 0700); err != nil {
+			t.Fatalf("Failed to create var directory: %v", err)
+		}
+		// PID file for this (running) process, but no socket.
+		if err := daemon.WritePIDFile(filepath.Join(varDir, "thrum.pid")); err != nil {
+			t.Fatalf("Failed to write PID file: %v", err)
+		}
+
+		result, err := DaemonWaitHealthy(tmpDir, 50*time.Millisecond)
+		if err == nil || !strings.Contains(err.Error(), "not yet listening") {
+			t.Fatalf("expected not-listening error, got %v", err)
+		}
+		if !result.Running || result.Healthy {
+			t.Errorf("expected running but unhealthy daemon, got %+v", result)
+		}
+	})
+}
+
 func TestDaemonStop_NotRunning(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -152,3 +249,48 @@ func findSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestFormatDaemonReload(t *testing.T) {
+	out := FormatDaemonReload(&DaemonReloadResult{})
+	if out != "✓ Config reloaded (no changes applied)\n" {
+		t.Errorf("no-change output = %q", out)
+	}
+
+	out = FormatDaemonReload(&DaemonReloadResult{
+		Changed:         []ConfigChange{{Setting: "local_only", Old: "false", New: "true"}},
+		RestartRequired: []ConfigChange{{Setting: "ws_port", Old: "auto", New: "9999"}},
+	})
+	for _, want := range []string{
+		"✓ Config reloaded\n",
+		"  local_only: false → true\n",
+		"⚠ ws_port changed (auto → 9999); restart the daemon to apply",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestFormatDaemonStatusAll(t *testing.T) {
+	output := FormatDaemonStatusAll(&DaemonStatusAllResult{
+		Daemons: []DaemonStatusResult{
+			{Running: true, PID: 4242, RepoPath: "/work/api", WebSocketPort: 9999, Uptime: "2h", SocketPath: "/work/api/.thrum/var/thrum.sock"},
+			{Running: true, PID: 77, RepoPath: "/work/web"},
+		},
+		Pruned: []string{"/work/old"},
+	})
+	for _, want := range []string{
+		"4242     9999     2h       /work/api\n",
+		"socket: /work/api/.thrum/var/thrum.sock\n",
+		"77       -        -        /work/web\n",
+		"Pruned stale entry: /work/old\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+
+	if got := FormatDaemonStatusAll(&DaemonStatusAllResult{}); !strings.HasPrefix(got, "No running daemons found") {
+		t.Errorf("empty output = %q", got)
+	}
+}
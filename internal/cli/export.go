@@ -0,0 +1,199 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/leonletto/thrum/internal/types"
+)
+
+// ExportOptions contains options for the message.export RPC call.
+type ExportOptions struct {
+	Since          string // RFC 3339 lower bound (already resolved by caller)
+	IncludeDeleted bool
+}
+
+// ExportRecord is one exported message. The fields match the message.archive
+// JSONL format, so a JSONL export can be fed back to `thrum message import`.
+type ExportRecord struct {
+	MessageID string        `json:"message_id"`
+	ThreadID  string        `json:"thread_id,omitempty"`
+	AgentID   string        `json:"agent_id"`
+	CreatedAt string        `json:"created_at"`
+	Body      ExportBody    `json:"body"`
+	Scopes    []types.Scope `json:"scopes"`
+	Refs      []types.Ref   `json:"refs"`
+	Deleted   bool          `json:"deleted,omitempty"`
+	DeletedAt string        `json:"deleted_at,omitempty"`
+}
+
+// ExportBody holds the body fields of an exported message.
+type ExportBody struct {
+	Format  string `json:"format"`
+	Content string `json:"content"`
+}
+
+// ExportThread describes a thread referenced by exported records.
+type ExportThread struct {
+	ThreadID  string `json:"thread_id"`
+	Title     string `json:"title,omitempty"`
+	CreatedAt string `json:"created_at"`
+	CreatedBy string `json:"created_by"`
+}
+
+// ExportResult represents the response from the message.export RPC.
+type ExportResult struct {
+	Records []ExportRecord `json:"records"`
+	Threads []ExportThread `json:"threads"`
+}
+
+// ExportSummary is what `thrum export --output PATH --json` prints.
+type ExportSummary struct {
+	Path       string `json:"path"`
+	Format     string `json:"format"`
+	Messages   int    `json:"messages"`
+	Threads    int    `json:"threads"`
+	Tombstones int    `json:"tombstones,omitempty"`
+}
+
+// Export calls the message.export RPC and returns every matching message.
+func Export(client *Client, opts ExportOptions) (*ExportResult, error) {
+	req := map[string]any{}
+	if opts.Since != "" {
+		req["since"] = opts.Since
+	}
+	if opts.IncludeDeleted {
+		req["include_deleted"] = true
+	}
+	var result ExportResult
+	if err := client.Call("message.export", req, &result); err != nil {
+		return nil, fmt.Errorf("message.export RPC failed: %w", err)
+	}
+	return &result, nil
+}
+
+// WriteExport writes the export in the given format ("jsonl" or "markdown").
+func WriteExport(w io.Writer, result *ExportResult, format string) error {
+	switch format {
+	case "jsonl":
+		return writeExportJSONL(w, result)
+	case "markdown":
+		_, err := io.WriteString(w, FormatExportMarkdown(result))
+		return err
+	default:
+		return fmt.Errorf("invalid format %q (must be \"jsonl\" or \"markdown\")", format)
+	}
+}
+
+// writeExportJSONL writes one record per line, oldest first.
+func writeExportJSONL(w io.Writer, result *ExportResult) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	for _, rec := range result.Records {
+		if err := enc.Encode(rec); err != nil {
+			return fmt.Errorf("write export record %s: %w", rec.MessageID, err)
+		}
+	}
+	return nil
+}
+
+// FormatExportMarkdown renders the export as a markdown document: one
+// section per thread (in order of first message), then the messages that
+// belong to no thread.
+//
+//	# Thrum message export
+//
+//	3 message(s) in 1 thread(s).
+//
+//	## Thread: Deploy plan
+//
+//	`thr_01` · started by impl_api · 2026-02-03T10:00:00Z
+//
+//	### impl_api · 2026-02-03T10:00:00Z
+//
+//	Deploying at noon.
+func FormatExportMarkdown(result *ExportResult) string {
+	var out strings.Builder
+	out.WriteString("# Thrum message export\n\n")
+
+	byThread := make(map[string][]ExportRecord)
+	var unthreaded []ExportRecord
+	tombstones := 0
+	for _, rec := range result.Records {
+		if rec.Deleted {
+			tombstones++
+		}
+		if rec.ThreadID == "" {
+			unthreaded = append(unthreaded, rec)
+			continue
+		}
+		byThread[rec.ThreadID] = append(byThread[rec.ThreadID], rec)
+	}
+
+	fmt.Fprintf(&out, "%d message(s) in %d thread(s)", len(result.Records), len(result.Threads))
+	if tombstones > 0 {
+		fmt.Fprintf(&out, " (%d deleted)", tombstones)
+	}
+	out.WriteString(".\n")
+
+	for _, th := range result.Threads {
+		title := th.Title
+		if title == "" {
+			title = th.ThreadID
+		}
+		fmt.Fprintf(&out, "\n## Thread: %s\n\n", title)
+		fmt.Fprintf(&out, "`%s` · started by %s · %s\n", th.ThreadID, th.CreatedBy, th.CreatedAt)
+		for _, rec := range byThread[th.ThreadID] {
+			writeExportMarkdownMessage(&out, rec)
+		}
+	}
+
+	if len(unthreaded) > 0 {
+		out.WriteString("\n## Messages outside threads\n")
+		for _, rec := range unthreaded {
+			writeExportMarkdownMessage(&out, rec)
+		}
+	}
+	return out.String()
+}
+
+func writeExportMarkdownMessage(out *strings.Builder, rec ExportRecord) {
+	fmt.Fprintf(out, "\n### %s · %s\n\n", rec.AgentID, rec.CreatedAt)
+
+	var meta []string
+	meta = append(meta, "`"+rec.MessageID+"`")
+	if len(rec.Scopes) > 0 {
+		scopes := make([]string, 0, len(rec.Scopes))
+		for _, s := range rec.Scopes {
+			scopes = append(scopes, s.Type+":"+s.Value)
+		}
+		meta = append(meta, "scopes: "+strings.Join(scopes, ", "))
+	}
+	if len(rec.Refs) > 0 {
+		refs := make([]string, 0, len(rec.Refs))
+		for _, r := range rec.Refs {
+			refs = append(refs, r.Type+":"+r.Value)
+		}
+		meta = append(meta, "refs: "+strings.Join(refs, ", "))
+	}
+	fmt.Fprintf(out, "%s\n\n", strings.Join(meta, " · "))
+
+	if rec.Deleted {
+		fmt.Fprintf(out, "_(deleted %s)_\n", rec.DeletedAt)
+		return
+	}
+	out.WriteString(strings.TrimRight(rec.Body.Content, "\n"))
+	out.WriteString("\n")
+}
+
+// FormatExportSummary formats the confirmation printed after writing a file.
+func FormatExportSummary(s ExportSummary) string {
+	msg := fmt.Sprintf("✓ Exported %d message(s) in %d thread(s) to %s (%s)\n",
+		s.Messages, s.Threads, s.Path, s.Format)
+	if s.Tombstones > 0 {
+		msg += fmt.Sprintf("  Includes %d deleted message(s) as tombstones\n", s.Tombstones)
+	}
+	return msg
+}
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/leonletto/thrum/internal/types"
+)
+
+func sampleExport() *ExportResult {
+	return &ExportResult{
+		Records: []ExportRecord{
+			{MessageID: "msg_1", ThreadID: "thr_1", AgentID: "impl_api", CreatedAt: "2026-02-03T10:00:00Z",
+				Body: ExportBody{Format: "markdown", Content: "Deploying at noon."}, Scopes: []types.Scope{{Type: "group", Value: "backend"}}},
+			{MessageID: "msg_2", AgentID: "reviewer", CreatedAt: "2026-02-03T10:05:00Z",
+				Body: ExportBody{Format: "markdown", Content: "Standalone note"}},
+			{MessageID: "msg_3", ThreadID: "thr_1", AgentID: "reviewer", CreatedAt: "2026-02-03T10:10:00Z",
+				Deleted: true, DeletedAt: "2026-02-03T11:00:00Z"},
+		},
+		Threads: []ExportThread{{ThreadID: "thr_1", Title: "Deploy plan", CreatedAt: "2026-02-03T10:00:00Z", CreatedBy: "impl_api"}},
+	}
+}
+
+func TestFormatExportMarkdown(t *testing.T) {
+	out := FormatExportMarkdown(sampleExport())
+
+	for _, want := range []string{
+		"# Thrum message export\n",
+		"3 message(s) in 1 thread(s) (1 deleted).\n",
+		"## Thread: Deploy plan\n",
+		"`thr_1` · started by impl_api · 2026-02-03T10:00:00Z\n",
+		"### impl_api · 2026-02-03T10:00:00Z\n",
+		"`msg_1` · scopes: group:backend\n\nDeploying at noon.\n",
+		"_(deleted 2026-02-03T11:00:00Z)_\n",
+		"## Messages outside threads\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("markdown missing %q:\n%s", want, out)
+		}
+	}
+	// Thread replies stay in their thread section, ahead of unthreaded mail.
+	if strings.Index(out, "msg_3") > strings.Index(out, "## Messages outside threads") {
+		t.Errorf("threaded tombstone rendered outside its thread:\n%s", out)
+	}
+}
+
+func TestWriteExport_JSONL(t *testing.T) {
+	var buf strings.Builder
+	if err := WriteExport(&buf, sampleExport(), "jsonl"); err != nil {
+		t.Fatalf("WriteExport: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3:\n%s", len(lines), buf.String())
+	}
+	var rec map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &rec); err != nil {
+		t.Fatalf("line 1 not JSON: %v", err)
+	}
+	if rec["message_id"] != "msg_1" || rec["thread_id"] != "thr_1" {
+		t.Errorf("line 1 = %v", rec)
+	}
+	if _, ok := rec["deleted"]; ok {
+		t.Errorf("live record should omit deleted: %v", rec)
+	}
+
+	if err := WriteExport(&buf, sampleExport(), "csv"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
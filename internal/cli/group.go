@@ -1,9 +1,16 @@
 package cli
 
-// Group CLI functions — only GroupList and GroupMembers remain.
-// GroupCreate, GroupDelete, GroupAdd, GroupRemove, and formatting helpers
-// removed with the group CLI commands. Telegram bridge and MCP waiter
-// still use GroupList and GroupMembers via RPC.
+// Group CLI functions — GroupList, GroupMembers, GroupInfo, GroupRename, and
+// GroupCopy remain. GroupCreate, GroupDelete, GroupAdd, GroupRemove, and
+// formatting helpers removed with the group CLI commands. Telegram bridge and
+// MCP waiter still use GroupList and GroupMembers via RPC; GroupMembers also
+// backs `thrum group members`, GroupInfo backs `thrum group info`, GroupRename
+// backs `thrum group rename`, and GroupCopy backs `thrum group copy`.
+
+import (
+	"fmt"
+	"strings"
+)
 
 // GroupListOptions contains options for listing groups.
 type GroupListOptions struct{}
@@ -40,6 +47,7 @@ type GroupMemberItem struct {
 type GroupMembersResult struct {
 	Members  []GroupMemberItem `json:"members"`
 	Expanded []string          `json:"expanded,omitempty"`
+	Cycles   [][]string        `json:"cycles,omitempty"` // nested-group cycles found while expanding
 }
 
 // GroupList lists all groups via the daemon.
@@ -64,3 +72,185 @@ func GroupMembers(client *Client, opts GroupMembersOptions) (*GroupMembersResult
 	}
 	return &result, nil
 }
+
+// FormatGroupMembers formats a group's direct members and, when expanded,
+// the agents they resolve to. Membership cycles are reported as warnings;
+// each group on a cycle is still expanded once.
+func FormatGroupMembers(name string, result *GroupMembersResult, expanded bool) string {
+	var out strings.Builder
+	if len(result.Members) == 0 {
+		fmt.Fprintf(&out, "@%s has no members\n", name)
+	} else {
+		fmt.Fprintf(&out, "@%s members:\n", name)
+		for _, m := range result.Members {
+			fmt.Fprintf(&out, "  %-6s %s\n", m.MemberType, m.MemberValue)
+		}
+	}
+
+	if !expanded {
+		return out.String()
+	}
+	noun := "agents"
+	if len(result.Expanded) == 1 {
+		noun = "agent"
+	}
+	fmt.Fprintf(&out, "\nExpanded (%d %s):\n", len(result.Expanded), noun)
+	for _, agentID := range result.Expanded {
+		fmt.Fprintf(&out, "  @%s\n", agentID)
+	}
+	for _, cycle := range result.Cycles {
+		fmt.Fprintf(&out, "  warning: membership cycle @%s (each group expanded once)\n", strings.Join(cycle, " → @"))
+	}
+	return out.String()
+}
+
+// GroupInfoResult is the result of group.info.
+type GroupInfoResult struct {
+	GroupID     string            `json:"group_id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	CreatedAt   string            `json:"created_at"`
+	CreatedBy   string            `json:"created_by"`
+	Members     []GroupMemberItem `json:"members"`
+	Tree        []GroupTreeNode   `json:"tree"`
+	AgentCount  int               `json:"agent_count"`
+	Online      int               `json:"online"`
+}
+
+// GroupTreeNode is one member in a group's membership tree. Agents carry
+// their presence; roles and nested groups carry their resolved members.
+type GroupTreeNode struct {
+	MemberType  string          `json:"member_type"`
+	MemberValue string          `json:"member_value"`
+	Status      string          `json:"status,omitempty"` // agents only: "active", "away", or "offline"
+	Children    []GroupTreeNode `json:"children,omitempty"`
+	Cycle       bool            `json:"cycle,omitempty"` // group already above this node; not expanded
+}
+
+// GroupInfo fetches a group's details and member tree via the daemon.
+func GroupInfo(client *Client, name string) (*GroupInfoResult, error) {
+	var result GroupInfoResult
+	if err := client.Call("group.info", map[string]any{"name": name}, &result); err != nil {
+		return nil, fmt.Errorf("group.info RPC failed: %w", err)
+	}
+	return &result, nil
+}
+
+// FormatGroupInfo formats a group's details and its member tree, with each
+// agent's presence, for display.
+func FormatGroupInfo(result *GroupInfoResult) string {
+	var out strings.Builder
+	fmt.Fprintf(&out, "@%s\n", result.Name)
+	if result.Description != "" {
+		fmt.Fprintf(&out, "  %s\n", result.Description)
+	}
+	if result.CreatedBy != "" {
+		fmt.Fprintf(&out, "  Created by %s\n", result.CreatedBy)
+	}
+	if len(result.Tree) == 0 {
+		out.WriteString("\nNo members\n")
+		return out.String()
+	}
+
+	noun := "agents"
+	if result.AgentCount == 1 {
+		noun = "agent"
+	}
+	fmt.Fprintf(&out, "\nMembers (%d of %d %s online):\n", result.Online, result.AgentCount, noun)
+	writeGroupTree(&out, result.Tree, 1)
+	return out.String()
+}
+
+// writeGroupTree writes nodes one per line, indented two spaces per level.
+func writeGroupTree(out *strings.Builder, nodes []GroupTreeNode, depth int) {
+	indent := strings.Repeat("  ", depth)
+	for _, n := range nodes {
+		switch n.MemberType {
+		case "agent":
+			marker := "●"
+			if n.Status == "offline" {
+				marker = "○"
+			}
+			fmt.Fprintf(out, "%s%s @%s (%s)\n", indent, marker, n.MemberValue, n.Status)
+		case "role":
+			fmt.Fprintf(out, "%srole %s\n", indent, n.MemberValue)
+		case "group":
+			if n.Cycle {
+				fmt.Fprintf(out, "%s@%s (cycle, not expanded)\n", indent, n.MemberValue)
+			} else {
+				fmt.Fprintf(out, "%s@%s\n", indent, n.MemberValue)
+			}
+		default:
+			fmt.Fprintf(out, "%s%s %s\n", indent, n.MemberType, n.MemberValue)
+		}
+		writeGroupTree(out, n.Children, depth+1)
+	}
+}
+
+// GroupRenameResult is the result of renaming a group.
+type GroupRenameResult struct {
+	GroupID   string `json:"group_id"`
+	OldName   string `json:"old_name"`
+	Name      string `json:"name"`
+	RenamedAt string `json:"renamed_at"`
+}
+
+// GroupRename renames a group via the daemon. Messages scoped to the old
+// name move with it.
+func GroupRename(client *Client, oldName, newName, callerAgentID string) (*GroupRenameResult, error) {
+	params := map[string]string{
+		"name":     oldName,
+		"new_name": newName,
+	}
+	if callerAgentID != "" {
+		params["caller_agent_id"] = callerAgentID
+	}
+
+	var result GroupRenameResult
+	if err := client.Call("group.rename", params, &result); err != nil {
+		return nil, fmt.Errorf("group.rename RPC failed: %w", err)
+	}
+	return &result, nil
+}
+
+// FormatGroupRename formats the rename result for display.
+func FormatGroupRename(result *GroupRenameResult) string {
+	return fmt.Sprintf("✓ Group renamed: @%s → @%s\n", result.OldName, result.Name)
+}
+
+// GroupCopyResult is the result of copying a group.
+type GroupCopyResult struct {
+	GroupID   string            `json:"group_id"`
+	Name      string            `json:"name"`
+	Source    string            `json:"source"`
+	Members   []GroupMemberItem `json:"members"`
+	CreatedAt string            `json:"created_at"`
+}
+
+// GroupCopy creates dest with source's members via the daemon. With expand,
+// nested groups are replaced by their own agent and role members.
+func GroupCopy(client *Client, source, dest string, expand bool, callerAgentID string) (*GroupCopyResult, error) {
+	params := map[string]any{
+		"source": source,
+		"dest":   dest,
+		"expand": expand,
+	}
+	if callerAgentID != "" {
+		params["caller_agent_id"] = callerAgentID
+	}
+
+	var result GroupCopyResult
+	if err := client.Call("group.copy", params, &result); err != nil {
+		return nil, fmt.Errorf("group.copy RPC failed: %w", err)
+	}
+	return &result, nil
+}
+
+// FormatGroupCopy formats the copy result for display.
+func FormatGroupCopy(result *GroupCopyResult) string {
+	noun := "members"
+	if len(result.Members) == 1 {
+		noun = "member"
+	}
+	return fmt.Sprintf("✓ Group copied: @%s → @%s (%d %s)\n", result.Source, result.Name, len(result.Members), noun)
+}
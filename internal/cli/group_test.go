@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatGroupMembers(t *testing.T) {
+	result := &GroupMembersResult{
+		Members: []GroupMemberItem{
+			{MemberType: "agent", MemberValue: "alice"},
+			{MemberType: "group", MemberValue: "pong"},
+		},
+		Expanded: []string{"alice", "bob"},
+		Cycles:   [][]string{{"ping", "pong", "ping"}},
+	}
+
+	out := FormatGroupMembers("ping", result, false)
+	if !strings.Contains(out, "@ping members:") || !strings.Contains(out, "group  pong") {
+		t.Errorf("unexpected members output:\n%s", out)
+	}
+	if strings.Contains(out, "Expanded") {
+		t.Errorf("expansion shown without --expand:\n%s", out)
+	}
+
+	out = FormatGroupMembers("ping", result, true)
+	for _, want := range []string{
+		"Expanded (2 agents):",
+		"  @alice\n",
+		"  @bob\n",
+		"warning: membership cycle @ping → @pong → @ping",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	empty := FormatGroupMembers("solo", &GroupMembersResult{}, false)
+	if empty != "@solo has no members\n" {
+		t.Errorf("unexpected empty output: %q", empty)
+	}
+}
+
+func TestFormatGroupCopy(t *testing.T) {
+	got := FormatGroupCopy(&GroupCopyResult{Source: "reviewers", Name: "reviewers-v2", Members: []GroupMemberItem{{MemberType: "agent", MemberValue: "alice"}}})
+	if want := "✓ Group copied: @reviewers → @reviewers-v2 (1 member)\n"; got != want {
+		t.Errorf("FormatGroupCopy = %q, want %q", got, want)
+	}
+}
+
+func TestFormatGroupInfo(t *testing.T) {
+	result := &GroupInfoResult{
+		Name:        "release",
+		Description: "Release crew",
+		AgentCount:  2,
+		Online:      1,
+		Tree: []GroupTreeNode{
+			{MemberType: "agent", MemberValue: "alice", Status: "offline"},
+			{MemberType: "group", MemberValue: "core", Children: []GroupTreeNode{
+				{MemberType: "group", MemberValue: "release", Cycle: true},
+				{MemberType: "role", MemberValue: "reviewer", Children: []GroupTreeNode{
+					{MemberType: "agent", MemberValue: "bob", Status: "active"},
+				}},
+			}},
+		},
+	}
+
+	out := FormatGroupInfo(result)
+	for _, want := range []string{
+		"@release\n  Release crew\n",
+		"Members (1 of 2 agents online):",
+		"\n  ○ @alice (offline)\n",
+		"\n  @core\n    @release (cycle, not expanded)\n    role reviewer\n      ● @bob (active)\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	if empty := FormatGroupInfo(&GroupInfoResult{Name: "solo"}); !strings.Contains(empty, "No members") {
+		t.Errorf("unexpected empty output: %q", empty)
+	}
+}
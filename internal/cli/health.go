@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Health calls the daemon's health RPC.
+func Health(client *Client) (*HealthResult, error) {
+	var result HealthResult
+	if err := client.Call("health", map[string]any{}, &result); err != nil {
+		return nil, fmt.Errorf("health check failed: %w", err)
+	}
+	return &result, nil
+}
+
+// FormatHealth formats a health result for display, one field per line so
+// monitoring scripts can grep it.
+func FormatHealth(h *HealthResult) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Status:    %s\n", h.Status)
+	fmt.Fprintf(&b, "Version:   %s\n", h.Version)
+	fmt.Fprintf(&b, "Uptime:    %s\n", formatUptime(time.Duration(h.UptimeMs)*time.Millisecond))
+	fmt.Fprintf(&b, "Repo ID:   %s\n", h.RepoID)
+
+	sync := h.SyncState
+	if h.LocalOnly && h.LocalOnlyReason != "" {
+		sync += " (" + h.LocalOnlyReason + ")"
+	}
+	fmt.Fprintf(&b, "Sync:      %s\n", sync)
+
+	if ts := h.Tailscale; ts != nil && ts.Enabled {
+		fmt.Fprintf(&b, "Tailscale: %s, %d peer(s), %s\n", ts.Hostname, ts.ConnectedPeers, ts.SyncStatus)
+		for _, p := range ts.Peers {
+			fmt.Fprintf(&b, "  - %s (last sync: %s)\n", p.Name, p.LastSync)
+		}
+	} else {
+		b.WriteString("Tailscale: disabled\n")
+	}
+
+	return b.String()
+}
@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatHealth(t *testing.T) {
+	h := &HealthResult{
+		Status:          "ok",
+		UptimeMs:        (3*60 + 42) * 60 * 1000,
+		Version:         "1.2.3",
+		RepoID:          "r_ABC",
+		SyncState:       "local-only",
+		LocalOnly:       true,
+		LocalOnlyReason: "exposure gate",
+		Tailscale: &TailscaleSyncInfo{
+			Enabled:        true,
+			Hostname:       "laptop",
+			ConnectedPeers: 1,
+			SyncStatus:     "idle",
+			Peers:          []TailscalePeer{{DaemonID: "d_1", Name: "desktop", LastSync: "5s ago"}},
+		},
+	}
+	out := FormatHealth(h)
+	for _, want := range []string{
+		"Status:    ok",
+		"Version:   1.2.3",
+		"Uptime:    3h42m",
+		"Repo ID:   r_ABC",
+		"Sync:      local-only (exposure gate)",
+		"Tailscale: laptop, 1 peer(s), idle",
+		"  - desktop (last sync: 5s ago)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	h.Tailscale = nil
+	if out := FormatHealth(h); !strings.Contains(out, "Tailscale: disabled") {
+		t.Errorf("expected disabled Tailscale line:\n%s", out)
+	}
+}
@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/leonletto/thrum/internal/config"
+)
+
+// IdentityCheck is the result of `thrum whoami --check`.
+type IdentityCheck struct {
+	AgentID       string   `json:"agent_id"`
+	IdentityFile  string   `json:"identity_file"`
+	DaemonChecked bool     `json:"daemon_checked"`
+	OK            bool     `json:"ok"`
+	Issues        []string `json:"issues"`
+}
+
+// CheckIdentity cross-references the identity file at idPath with what the
+// daemon reports for it. Without daemonInfo (daemon not running) only the
+// file itself is checked: name, role and module are set, and the file is
+// named after the agent. With it, the daemon must resolve the same agent
+// and hold the same role and module; an empty daemon role means the agent
+// is not registered with the daemon. Display names are compared only when
+// both sides have one. A non-nil daemonErr is the daemon failing to resolve
+// the agent at all, and is reported as an issue.
+func CheckIdentity(idFile *config.IdentityFile, idPath string, daemonInfo *WhoamiResult, daemonErr error) *IdentityCheck {
+	c := &IdentityCheck{
+		AgentID:       idFile.Agent.Name,
+		IdentityFile:  idPath,
+		DaemonChecked: daemonInfo != nil || daemonErr != nil,
+		Issues:        []string{},
+	}
+	a := idFile.Agent
+
+	if a.Name == "" {
+		c.Issues = append(c.Issues, "identity file has no agent name")
+	} else if base := strings.TrimSuffix(filepath.Base(idPath), ".json"); idPath != "" && base != a.Name {
+		c.Issues = append(c.Issues, fmt.Sprintf("identity file is named %s.json but holds agent %s", base, a.Name))
+	}
+	if a.Role == "" {
+		c.Issues = append(c.Issues, "identity file has no role")
+	}
+	if a.Module == "" {
+		c.Issues = append(c.Issues, "identity file has no module")
+	}
+
+	if daemonErr != nil {
+		c.Issues = append(c.Issues, fmt.Sprintf("daemon could not resolve agent %s: %v", a.Name, daemonErr))
+	}
+	if daemonInfo != nil {
+		switch {
+		case daemonInfo.AgentID != a.Name:
+			c.Issues = append(c.Issues, fmt.Sprintf("daemon resolves this shell to %s, identity file says %s", daemonInfo.AgentID, a.Name))
+		case daemonInfo.Role == "":
+			c.Issues = append(c.Issues, fmt.Sprintf("agent %s is not registered with the daemon", a.Name))
+		default:
+			if daemonInfo.Role != a.Role {
+				c.Issues = append(c.Issues, fmt.Sprintf("role mismatch: identity file says %s, daemon has %s", a.Role, daemonInfo.Role))
+			}
+			if daemonInfo.Module != a.Module {
+				c.Issues = append(c.Issues, fmt.Sprintf("module mismatch: identity file says %s, daemon has %s", a.Module, daemonInfo.Module))
+			}
+			if a.Display != "" && daemonInfo.Display != "" && daemonInfo.Display != a.Display {
+				c.Issues = append(c.Issues, fmt.Sprintf("display mismatch: identity file says %q, daemon has %q", a.Display, daemonInfo.Display))
+			}
+		}
+	}
+
+	c.OK = len(c.Issues) == 0
+	return c
+}
+
+// FormatIdentityCheck formats an identity check for display.
+func FormatIdentityCheck(c *IdentityCheck) string {
+	var out strings.Builder
+	if c.OK {
+		fmt.Fprintf(&out, "✓ Identity %s is consistent\n", c.AgentID)
+	} else {
+		fmt.Fprintf(&out, "✗ Identity %s has %d problem(s):\n", c.AgentID, len(c.Issues))
+		for _, issue := range c.Issues {
+			fmt.Fprintf(&out, "  - %s\n", issue)
+		}
+	}
+	fmt.Fprintf(&out, "  File:   %s\n", c.IdentityFile)
+	if !c.DaemonChecked {
+		out.WriteString("  Daemon: not running — checked the local identity file only\n")
+	}
+	return out.String()
+}
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/leonletto/thrum/internal/config"
+)
+
+func TestCheckIdentity(t *testing.T) {
+	idFile := &config.IdentityFile{
+		Agent: config.AgentConfig{Name: "impl_api", Role: "reviewer", Module: "api"},
+	}
+	const path = "/repo/.thrum/identities/impl_api.json"
+
+	local := CheckIdentity(idFile, path, nil, nil)
+	if !local.OK || local.DaemonChecked {
+		t.Errorf("local-only check = %+v, want ok without a daemon check", local)
+	}
+	if out := FormatIdentityCheck(local); !strings.Contains(out, "checked the local identity file only") {
+		t.Errorf("local-only output does not say the daemon was skipped:\n%s", out)
+	}
+
+	daemonInfo := &WhoamiResult{AgentID: "impl_api", Role: "implementer", Module: "api"}
+	mismatch := CheckIdentity(idFile, path, daemonInfo, nil)
+	if mismatch.OK || len(mismatch.Issues) != 1 || !strings.Contains(mismatch.Issues[0], "role mismatch: identity file says reviewer, daemon has implementer") {
+		t.Errorf("role mismatch issues = %q", mismatch.Issues)
+	}
+
+	unregistered := CheckIdentity(idFile, path, &WhoamiResult{AgentID: "impl_api"}, nil)
+	if unregistered.OK || !strings.Contains(unregistered.Issues[0], "not registered") {
+		t.Errorf("unregistered issues = %q", unregistered.Issues)
+	}
+
+	other := CheckIdentity(idFile, path, &WhoamiResult{AgentID: "coordinator", Role: "coordinator", Module: "main"}, nil)
+	if other.OK || !strings.Contains(other.Issues[0], "resolves this shell to coordinator") {
+		t.Errorf("different-agent issues = %q", other.Issues)
+	}
+
+	failed := CheckIdentity(idFile, path, nil, errors.New("agent.whoami RPC failed: unknown agent"))
+	if failed.OK || !failed.DaemonChecked || !strings.Contains(failed.Issues[0], "daemon could not resolve agent impl_api") {
+		t.Errorf("daemon-error check = %+v", failed)
+	}
+
+	broken := CheckIdentity(&config.IdentityFile{Agent: config.AgentConfig{Name: "bob"}}, path, nil, nil)
+	if got := strings.Join(broken.Issues, "; "); !strings.Contains(got, "named impl_api.json but holds agent bob") ||
+		!strings.Contains(got, "no role") || !strings.Contains(got, "no module") {
+		t.Errorf("local issues = %s", got)
+	}
+}
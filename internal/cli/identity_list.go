@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/leonletto/thrum/internal/config"
+)
+
+// IdentityListEntry is one identity file reported by `thrum whoami --all`.
+type IdentityListEntry struct {
+	Name         string `json:"name"`
+	Role         string `json:"role"`
+	Module       string `json:"module"`
+	Worktree     string `json:"worktree,omitempty"`
+	IdentityFile string `json:"identity_file"`
+}
+
+// ListIdentities reads every *.json identity file in dirs. Missing
+// directories are skipped silently (a worktree need not have registered
+// anyone); unreadable or malformed files are skipped and described in the
+// returned warnings so one bad file does not hide the rest. Entries are
+// sorted by name, then path, and an agent registered in several worktrees
+// appears once per file.
+func ListIdentities(dirs []string) ([]IdentityListEntry, []string) {
+	entries := []IdentityListEntry{}
+	var warnings []string
+	for _, dir := range dirs {
+		des, err := os.ReadDir(dir)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				warnings = append(warnings, fmt.Sprintf("skipping %s: %v", dir, err))
+			}
+			continue
+		}
+		for _, de := range des {
+			if de.IsDir() || filepath.Ext(de.Name()) != ".json" {
+				continue
+			}
+			path := filepath.Join(dir, de.Name())
+			data, err := os.ReadFile(path) // #nosec G304 -- identity file under a known .thrum/identities/ dir
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("skipping %s: %v", path, err))
+				continue
+			}
+			var idFile config.IdentityFile
+			if err := json.Unmarshal(data, &idFile); err != nil {
+				warnings = append(warnings, fmt.Sprintf("skipping malformed identity file %s: %v", path, err))
+				continue
+			}
+			name := idFile.Agent.Name
+			if name == "" {
+				name = strings.TrimSuffix(de.Name(), ".json")
+			}
+			entries = append(entries, IdentityListEntry{
+				Name:         name,
+				Role:         idFile.Agent.Role,
+				Module:       idFile.Agent.Module,
+				Worktree:     idFile.Worktree,
+				IdentityFile: path,
+			})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Name != entries[j].Name {
+			return entries[i].Name < entries[j].Name
+		}
+		return entries[i].IdentityFile < entries[j].IdentityFile
+	})
+	return entries, warnings
+}
+
+// FormatIdentityList formats identities one per line: name, role, module,
+// worktree.
+func FormatIdentityList(entries []IdentityListEntry) string {
+	if len(entries) == 0 {
+		return "No agent identities registered.\n"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-24s %-16s %-16s %s\n", "NAME", "ROLE", "MODULE", "WORKTREE")
+	for _, e := range entries {
+		worktree := e.Worktree
+		if worktree == "" {
+			worktree = "-"
+		}
+		fmt.Fprintf(&b, "%-24s %-16s %-16s %s\n", e.Name, e.Role, e.Module, worktree)
+	}
+	return b.String()
+}
@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestListIdentities(t *testing.T) {
+	root := t.TempDir()
+	mainDir := filepath.Join(root, "main", ".thrum", "identities")
+	featDir := filepath.Join(root, "feat", ".thrum", "identities")
+	for _, d := range []string{mainDir, featDir} {
+		if err := os.MkdirAll(d, 0o750); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write := func(dir, name, body string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(mainDir, "coordinator.json", `{"agent":{"name":"coordinator","role":"coordinator","module":"main"},"worktree":"/repo/main"}`)
+	write(mainDir, "notes.txt", `not an identity`)
+	write(featDir, "impl_api.json", `{"agent":{"name":"impl_api","role":"implementer","module":"api"},"worktree":"/repo/feat"}`)
+	write(featDir, "broken.json", `{"agent":`)
+	write(featDir, "legacy.json", `{"agent":{"role":"reviewer","module":"core"}}`)
+
+	entries, warnings := ListIdentities([]string{mainDir, featDir, filepath.Join(root, "gone", ".thrum", "identities")})
+
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name)
+	}
+	if got := strings.Join(names, ","); got != "coordinator,impl_api,legacy" {
+		t.Errorf("names = %s, want coordinator,impl_api,legacy", got)
+	}
+	if entries[1].Role != "implementer" || entries[1].Module != "api" || entries[1].Worktree != "/repo/feat" {
+		t.Errorf("impl_api entry = %+v", entries[1])
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "broken.json") {
+		t.Errorf("warnings = %v, want one for broken.json", warnings)
+	}
+
+	out := FormatIdentityList(entries)
+	if !strings.Contains(out, "NAME") || !strings.Contains(out, "impl_api") || !strings.Contains(out, "/repo/feat") {
+		t.Errorf("FormatIdentityList output missing fields:\n%s", out)
+	}
+	if got := FormatIdentityList(nil); got != "No agent identities registered.\n" {
+		t.Errorf("empty output = %q", got)
+	}
+}
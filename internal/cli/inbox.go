@@ -2,6 +2,7 @@ package cli
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 	"time"
 
@@ -15,12 +16,26 @@ type InboxOptions struct {
 	Unread            bool
 	PageSize          int
 	Page              int
-	CallerAgentID     string // Caller's resolved agent ID (for worktree identity)
-	CallerMentionRole string // Caller's role (for mentions filter)
-	ForAgent          string // Auto-filter: agent name (messages mentioning this name + broadcasts)
-	ForAgentRole      string // Auto-filter: agent role (messages mentioning this role + broadcasts)
-	AuthorID          string // Filter messages by author (--from); daemon-side filter (author_id)
-	Chronological     bool   // Oldest-first, reply-clustered view (--chronological/--oldest); default is newest-first (thrum-3vl0)
+	Cursor            string    // A previous page's next_cursor (--cursor); daemon-side after_cursor, replaces Page
+	CallerAgentID     string    // Caller's resolved agent ID (for worktree identity)
+	CallerMentionRole string    // Caller's role (for mentions filter)
+	ForAgent          string    // Auto-filter: agent name (messages mentioning this name + broadcasts)
+	ForAgentRole      string    // Auto-filter: agent role (messages mentioning this role + broadcasts)
+	AuthorID          string    // Filter messages by author (--from); daemon-side filter (author_id)
+	AuthorRole        string    // Filter messages by the author's role (--author-role); daemon-side filter (author_role)
+	Tag               string    // Filter messages by tag (--tag); daemon-side filter (tag)
+	Priority          string    // Filter messages by priority (--priority); daemon-side filter (priority)
+	PrioritySort      bool      // Unread high-priority messages first (--priority-sort)
+	BumpSort          bool      // Order by last bump, else send time (--bump-sort); daemon-side sort_by=bumped_at
+	SequenceSort      bool      // Order by origin sequence, then origin daemon (--order sequence); daemon-side sort_by=sequence
+	Pinned            bool      // Only pinned messages (--pinned); daemon-side filter (pinned)
+	AssignedTo        string    // Only open tasks assigned to this agent (--assigned-to-me); daemon-side filter (assigned_to)
+	IncludeExpired    bool      // Keep TTL messages past their expiry (--include-expired); daemon-side filter (include_expired)
+	IncludeDeleted    bool      // Include soft-deleted messages as tombstones (--deleted); daemon-side filter (include_deleted)
+	CreatedAfter      time.Time // Only messages created after this instant (--since); daemon-side filter (created_after)
+	Chronological     bool      // Oldest-first, reply-clustered view (--chronological/--oldest); default is newest-first (thrum-3vl0)
+	UnseenBy          string    // Another agent's unread backlog (--unseen-by); coordinator roles only, daemon-enforced
+	IncludeSelf       bool      // Keep the caller's own messages (message list); inbox always excludes them
 }
 
 // Message represents a message from the inbox.
@@ -34,10 +49,20 @@ type Message struct {
 		Content    string `json:"content"`
 		Structured string `json:"structured,omitempty"`
 	} `json:"body"`
-	CreatedAt string `json:"created_at"`
-	UpdatedAt string `json:"updated_at,omitempty"`
-	Deleted   bool   `json:"deleted"`
-	IsRead    bool   `json:"is_read"`
+	CreatedAt    string `json:"created_at"`
+	UpdatedAt    string `json:"updated_at,omitempty"`
+	Deleted      bool   `json:"deleted"`
+	DeletedAt    string `json:"deleted_at,omitempty"`    // tombstones only (--deleted)
+	DeleteReason string `json:"delete_reason,omitempty"` // tombstones only
+	IsRead       bool   `json:"is_read"`
+	Priority     string `json:"priority,omitempty"` // "low" or "high"; empty means normal
+	Pinned       bool   `json:"pinned,omitempty"`
+	ExpiresAt    string `json:"expires_at,omitempty"` // send --ttl messages only
+	DeliverAt    string `json:"deliver_at,omitempty"` // send --schedule messages only
+	BumpedAt     string `json:"bumped_at,omitempty"`  // last 'thrum message bump', if any
+	Snippet      string `json:"snippet,omitempty"`    // message search only
+	OriginDaemon string `json:"origin_daemon,omitempty"`
+	Sequence     int64  `json:"sequence,omitempty"` // origin daemon's event sequence; 0 if unknown
 }
 
 // InboxResult contains the result of listing messages.
@@ -46,13 +71,77 @@ type InboxResult struct {
 	Total          int       `json:"total"`
 	Unread         int       `json:"unread"`
 	HiddenByFilter int       `json:"hidden_by_filter,omitempty"`
+	PinnedCount    int       `json:"pinned_count,omitempty"`
 	Page           int       `json:"page"`
 	PageSize       int       `json:"page_size"`
 	TotalPages     int       `json:"total_pages"`
+	NextCursor     string    `json:"next_cursor,omitempty"`
 }
 
 // Inbox retrieves messages from the inbox.
 func Inbox(client *Client, opts InboxOptions) (*InboxResult, error) {
+	params, err := inboxParams(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Call RPC
+	var result InboxResult
+	if err := client.Call("message.list", params, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// InboxAllPages returns the messages on every page of the inbox for opts
+// (opts.Page is ignored). Page 1 is fetched first to learn the page count;
+// the remaining pages are pipelined in a single CallBatch round trip. A
+// message that shifts between pages while they are read is returned once.
+func InboxAllPages(client *Client, opts InboxOptions) ([]Message, error) {
+	opts.Page = 1
+	first, err := Inbox(client, opts)
+	if err != nil {
+		return nil, err
+	}
+	messages := first.Messages
+	if first.TotalPages <= 1 {
+		return messages, nil
+	}
+
+	calls := make([]BatchCall, 0, first.TotalPages-1)
+	for page := 2; page <= first.TotalPages; page++ {
+		opts.Page = page
+		params, err := inboxParams(opts)
+		if err != nil {
+			return nil, err
+		}
+		calls = append(calls, BatchCall{Method: "message.list", Params: params, Result: &InboxResult{}})
+	}
+	if err := client.CallBatch(calls); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(messages))
+	for _, m := range messages {
+		seen[m.MessageID] = true
+	}
+	for _, call := range calls {
+		if call.Err != nil {
+			return nil, call.Err
+		}
+		for _, m := range call.Result.(*InboxResult).Messages {
+			if !seen[m.MessageID] {
+				seen[m.MessageID] = true
+				messages = append(messages, m)
+			}
+		}
+	}
+	return messages, nil
+}
+
+// inboxParams builds message.list params from opts.
+func inboxParams(opts InboxOptions) (map[string]any, error) {
 	params := map[string]any{}
 
 	// Parse scope if provided
@@ -76,7 +165,9 @@ func Inbox(client *Client, opts InboxOptions) (*InboxResult, error) {
 	}
 
 	// Exclude messages sent by the current agent (no echo)
-	params["exclude_self"] = true
+	if !opts.IncludeSelf {
+		params["exclude_self"] = true
+	}
 
 	if opts.CallerAgentID != "" {
 		params["caller_agent_id"] = opts.CallerAgentID
@@ -98,6 +189,45 @@ func Inbox(client *Client, opts InboxOptions) (*InboxResult, error) {
 		params["author_id"] = opts.AuthorID
 	}
 
+	if opts.AuthorRole != "" {
+		params["author_role"] = opts.AuthorRole
+	}
+	if opts.Tag != "" {
+		params["tag"] = opts.Tag
+	}
+	if opts.Priority != "" {
+		params["priority"] = opts.Priority
+	}
+	if opts.PrioritySort {
+		params["priority_sort"] = true
+	}
+	if opts.BumpSort {
+		params["sort_by"] = "bumped_at"
+	}
+	if opts.SequenceSort {
+		params["sort_by"] = "sequence"
+	}
+	if opts.Pinned {
+		params["pinned"] = true
+	}
+	if opts.AssignedTo != "" {
+		params["assigned_to"] = opts.AssignedTo
+	}
+	if opts.IncludeExpired {
+		params["include_expired"] = true
+	}
+	if opts.IncludeDeleted {
+		params["include_deleted"] = true
+	}
+
+	if !opts.CreatedAfter.IsZero() {
+		params["created_after"] = opts.CreatedAfter.UTC().Format(time.RFC3339Nano)
+	}
+
+	if opts.UnseenBy != "" {
+		params["unseen_by"] = opts.UnseenBy
+	}
+
 	// thrum-3vl0: opt into the oldest-first, reply-clustered view. Default
 	// (false) leaves sort_order unset so the daemon returns newest-first.
 	if opts.Chronological {
@@ -112,13 +242,66 @@ func Inbox(client *Client, opts InboxOptions) (*InboxResult, error) {
 		params["page"] = opts.Page
 	}
 
-	// Call RPC
-	var result InboxResult
-	if err := client.Call("message.list", params, &result); err != nil {
-		return nil, err
+	if opts.Cursor != "" {
+		params["after_cursor"] = opts.Cursor
 	}
 
-	return &result, nil
+	return params, nil
+}
+
+// CompileGrep compiles a --grep pattern as a case-insensitive Go regular
+// expression. An empty pattern yields a nil regexp, which filters nothing.
+func CompileGrep(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	re, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --grep pattern %q: %w", pattern, err)
+	}
+	return re, nil
+}
+
+// FilterInboxByBody drops messages whose body does not match re and returns
+// how many messages were scanned. A nil re keeps everything. It filters the
+// page already fetched from the daemon — Total, Unread, and the pagination
+// fields still describe the daemon-side result set.
+func FilterInboxByBody(result *InboxResult, re *regexp.Regexp) int {
+	scanned := len(result.Messages)
+	if re == nil {
+		return scanned
+	}
+	kept := result.Messages[:0]
+	for _, msg := range result.Messages {
+		if re.MatchString(msg.Body.Content) {
+			kept = append(kept, msg)
+		}
+	}
+	result.Messages = kept
+	return scanned
+}
+
+// highlightMatches wraps each non-empty match of re in content with ** so
+// --grep hits stand out in the boxed listing.
+func highlightMatches(re *regexp.Regexp, content string) string {
+	return re.ReplaceAllStringFunc(content, func(m string) string {
+		if m == "" {
+			return m
+		}
+		return "**" + m + "**"
+	})
+}
+
+// FormatMessageIDs formats just the message IDs of result, one per line,
+// for inbox and message list --ids-only (e.g. piping into xargs). An empty
+// result formats as an empty string.
+func FormatMessageIDs(result *InboxResult) string {
+	var output strings.Builder
+	for _, msg := range result.Messages {
+		output.WriteString(msg.MessageID)
+		output.WriteString("\n")
+	}
+	return output.String()
 }
 
 // FormatInbox formats the inbox result for display.
@@ -128,9 +311,14 @@ func FormatInbox(result *InboxResult) string {
 
 // InboxFormatOptions contains options for formatting inbox output.
 type InboxFormatOptions struct {
-	ActiveScope string // The active filter scope (for empty state feedback)
-	ForAgent    string // The agent name being filtered for (for empty state / footer)
-	Unread      bool   // --unread filter: empty result produces no output (silent polling)
+	ActiveScope string         // The active filter scope (for empty state feedback)
+	ForAgent    string         // The agent name being filtered for (for empty state / footer)
+	Unread      bool           // --unread filter: empty result produces no output (silent polling)
+	Grep        string         // --grep pattern applied client-side via FilterInboxByBody
+	GrepRegexp  *regexp.Regexp // compiled --grep pattern; matches are highlighted
+	GrepScanned int            // messages on the page before the --grep filter ran
+	Threaded    bool           // --threaded: nest replies under their parent (see threadInboxMessages)
+	Cursor      bool           // --cursor: the page's position is relative, so the footer names the next cursor instead
 	Quiet       bool
 	JSON        bool
 }
@@ -145,7 +333,10 @@ func FormatInboxWithOptions(result *InboxResult, opts InboxFormatOptions) string
 		if opts.Unread && !opts.JSON {
 			return ""
 		}
-		if opts.ActiveScope != "" {
+		if opts.Grep != "" {
+			fmt.Fprintf(&output, "No messages matching --grep %q on this page (%d scanned of %d total)\n",
+				opts.Grep, opts.GrepScanned, result.Total)
+		} else if opts.ActiveScope != "" {
 			fmt.Fprintf(&output, "No messages matching filter --scope %s\n", opts.ActiveScope)
 			fmt.Fprintf(&output, "  Showing 0 of %d total messages (filter: scope=%s)\n", result.Total, opts.ActiveScope)
 			if !opts.Quiet && !opts.JSON {
@@ -162,6 +353,7 @@ func FormatInboxWithOptions(result *InboxResult, opts InboxFormatOptions) string
 				output.WriteString(LegacyHint("inbox.empty", opts.Quiet, opts.JSON))
 			}
 		}
+		output.WriteString(formatPinnedHint(result.PinnedCount))
 		return output.String()
 	}
 
@@ -179,8 +371,19 @@ func FormatInboxWithOptions(result *InboxResult, opts InboxFormatOptions) string
 	// Top border
 	output.WriteString("┌" + strings.Repeat("─", boxWidth) + "┐\n")
 
-	for i, msg := range result.Messages {
-		isReply := msg.ReplyTo != ""
+	entries := flatInboxEntries(result.Messages)
+	if opts.Threaded {
+		entries = threadInboxMessages(result.Messages)
+	}
+
+	for i, entry := range entries {
+		msg := entry.msg
+		isReply := entry.depth > 0
+		// Each level past the first shifts the reply two more columns.
+		nest := ""
+		if isReply {
+			nest = strings.Repeat("  ", entry.depth-1)
+		}
 
 		// Message header line
 		agentName := extractAgentName(msg.AgentID)
@@ -192,12 +395,23 @@ func FormatInboxWithOptions(result *InboxResult, opts InboxFormatOptions) string
 			readIndicator = "○" // read
 		}
 
+		// Parent not on this page: say where the reply belongs.
+		if entry.breadcrumb != "" {
+			output.WriteString(padLine("│   "+nest+entry.breadcrumb, boxWidth) + "│\n")
+		}
+
 		// Indent replies with ↳ indicator
 		if isReply {
-			header := fmt.Sprintf("│   %s %s  ↳ %s  %s", readIndicator, msg.MessageID, agentName, relTime)
+			header := fmt.Sprintf("│   %s%s %s  ↳ %s  %s", nest, readIndicator, msg.MessageID, agentName, relTime)
 			if msg.UpdatedAt != "" {
 				header += " (edited)"
 			}
+			if msg.Priority == "high" {
+				header += " [high]"
+			}
+			if msg.Pinned {
+				header += " 📌"
+			}
 			header = padLine(header, boxWidth)
 			output.WriteString(header + "│\n")
 		} else {
@@ -205,6 +419,12 @@ func FormatInboxWithOptions(result *InboxResult, opts InboxFormatOptions) string
 			if msg.UpdatedAt != "" {
 				header += " (edited)"
 			}
+			if msg.Priority == "high" {
+				header += " [high]"
+			}
+			if msg.Pinned {
+				header += " 📌"
+			}
 			header = padLine(header, boxWidth)
 			output.WriteString(header + "│\n")
 		}
@@ -212,24 +432,35 @@ func FormatInboxWithOptions(result *InboxResult, opts InboxFormatOptions) string
 		// Message content (word wrap to fit in box)
 		prefix := ""
 		if isReply {
-			prefix = "  ↳ "
+			prefix = nest + "  ↳ "
+		}
+		body := Plain(msg.Body.Content)
+		if opts.GrepRegexp != nil {
+			body = highlightMatches(opts.GrepRegexp, body)
+		}
+		content := wordWrap(body, contentWidth-len(prefix))
+		if msg.Deleted {
+			content = wordWrap(tombstoneLine(msg), contentWidth-len(prefix)) + "\n" + content
 		}
-		content := wordWrap(msg.Body.Content, contentWidth-len(prefix))
 		for j, line := range strings.Split(content, "\n") {
 			if j == 0 && isReply {
 				output.WriteString("│ " + padLine(prefix+line, contentWidth) + "│\n")
 			} else if isReply {
-				output.WriteString("│ " + padLine("    "+line, contentWidth) + "│\n")
+				output.WriteString("│ " + padLine(nest+"    "+line, contentWidth) + "│\n")
 			} else {
 				output.WriteString("│ " + padLine(line, contentWidth) + "│\n")
 			}
 		}
 
-		// Separator or bottom border
-		if i < len(result.Messages)-1 {
-			output.WriteString("├" + strings.Repeat("─", boxWidth) + "┤\n")
-		} else {
+		// Separator or bottom border. Threaded mode keeps a conversation in
+		// one box section and only separates top-level messages.
+		switch {
+		case i == len(entries)-1:
 			output.WriteString("└" + strings.Repeat("─", boxWidth) + "┘\n")
+		case opts.Threaded && entries[i+1].depth > 0 && entries[i+1].breadcrumb == "":
+			// next message continues this conversation
+		default:
+			output.WriteString("├" + strings.Repeat("─", boxWidth) + "┤\n")
 		}
 	}
 
@@ -238,6 +469,13 @@ func FormatInboxWithOptions(result *InboxResult, opts InboxFormatOptions) string
 	end := start + len(result.Messages) - 1
 
 	footer := fmt.Sprintf("Showing %d-%d of %d messages", start, end, result.Total)
+	if opts.Cursor {
+		footer = fmt.Sprintf("Showing %d of %d messages after cursor", len(result.Messages), result.Total)
+	}
+	if opts.Grep != "" {
+		footer = fmt.Sprintf("Showing %d of %d messages on page %d matching %q (%d total)",
+			len(result.Messages), opts.GrepScanned, result.Page, opts.Grep, result.Total)
+	}
 	if result.Unread > 0 {
 		footer += fmt.Sprintf(" (%d unread)", result.Unread)
 	}
@@ -246,6 +484,9 @@ func FormatInboxWithOptions(result *InboxResult, opts InboxFormatOptions) string
 	}
 
 	output.WriteString(footer + "\n")
+	if opts.Cursor && result.NextCursor != "" {
+		fmt.Fprintf(&output, "  Next page: --cursor %s\n", result.NextCursor)
+	}
 
 	// Surface the "hidden by filter" count when non-zero. Regular agents
 	// shouldn't normally see this footer, but when the inbox claims "N
@@ -256,10 +497,110 @@ func FormatInboxWithOptions(result *InboxResult, opts InboxFormatOptions) string
 		fmt.Fprintf(&output, "  %d additional unread messages exist outside your filter — see thrum messages (coming soon) for the full landscape view.\n",
 			result.HiddenByFilter)
 	}
+	output.WriteString(formatPinnedHint(result.PinnedCount))
 
 	return output.String()
 }
 
+// tombstoneLine is the marker shown above a deleted message's body when the
+// listing includes tombstones.
+func tombstoneLine(msg Message) string {
+	if msg.DeleteReason == "" {
+		return "[deleted]"
+	}
+	return "[deleted] reason: " + msg.DeleteReason
+}
+
+// inboxEntry is one message as placed in the inbox listing.
+type inboxEntry struct {
+	msg        Message
+	depth      int    // 0 = top level; replies are 1 + their parent's depth
+	breadcrumb string // set when the parent is not on this page
+}
+
+// flatInboxEntries is the default layout: page order, every reply indented
+// one level whether or not its parent is shown.
+func flatInboxEntries(msgs []Message) []inboxEntry {
+	entries := make([]inboxEntry, len(msgs))
+	for i, msg := range msgs {
+		entries[i] = inboxEntry{msg: msg}
+		if msg.ReplyTo != "" {
+			entries[i].depth = 1
+		}
+	}
+	return entries
+}
+
+// threadInboxMessages is the --threaded layout. Each reply is placed
+// directly under its parent, nested one level deeper, with siblings in page
+// order. A reply whose parent is not on the page stays where the page put it,
+// one level deep, behind a "(in thread …)" breadcrumb.
+func threadInboxMessages(msgs []Message) []inboxEntry {
+	onPage := make(map[string]bool, len(msgs))
+	for _, msg := range msgs {
+		onPage[msg.MessageID] = true
+	}
+	children := make(map[string][]Message)
+	for _, msg := range msgs {
+		if msg.ReplyTo != "" && msg.ReplyTo != msg.MessageID && onPage[msg.ReplyTo] {
+			children[msg.ReplyTo] = append(children[msg.ReplyTo], msg)
+		}
+	}
+
+	entries := make([]inboxEntry, 0, len(msgs))
+	placed := make(map[string]bool, len(msgs))
+	var place func(msg Message, depth int)
+	place = func(msg Message, depth int) {
+		if placed[msg.MessageID] {
+			return // reply_to cycle; each message is shown once
+		}
+		placed[msg.MessageID] = true
+		entries = append(entries, inboxEntry{msg: msg, depth: depth})
+		for _, child := range children[msg.MessageID] {
+			place(child, depth+1)
+		}
+	}
+
+	for _, msg := range msgs {
+		switch {
+		case msg.ReplyTo == "":
+			place(msg, 0)
+		case !onPage[msg.ReplyTo]:
+			n := len(entries)
+			place(msg, 1)
+			if len(entries) > n {
+				entries[n].breadcrumb = replyBreadcrumb(msg)
+			}
+		}
+	}
+	// Anything left sits in a reply_to cycle with no way in from a root.
+	for _, msg := range msgs {
+		if !placed[msg.MessageID] {
+			n := len(entries)
+			place(msg, 1)
+			entries[n].breadcrumb = replyBreadcrumb(msg)
+		}
+	}
+	return entries
+}
+
+// replyBreadcrumb names where an orphaned reply belongs.
+func replyBreadcrumb(msg Message) string {
+	if msg.ThreadID != "" {
+		return fmt.Sprintf("┆ (in thread %s …)", msg.ThreadID)
+	}
+	return fmt.Sprintf("┆ (reply to %s …)", msg.ReplyTo)
+}
+
+// formatPinnedHint returns the "📌 N pinned" line shown under every inbox
+// listing, or "" when nothing is pinned.
+func formatPinnedHint(pinned int) string {
+	if pinned == 0 {
+		return ""
+	}
+	return fmt.Sprintf("📌 %d pinned (thrum inbox --pinned)\n", pinned)
+}
+
 // extractAgentName extracts a short name from agent ID for display.
 func extractAgentName(agentID string) string {
 	return identity.ExtractDisplayName(agentID)
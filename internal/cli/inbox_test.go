@@ -2,6 +2,7 @@ package cli
 
 import (
 	"encoding/json"
+	"fmt"
 	"net"
 	"strings"
 	"testing"
@@ -99,6 +100,57 @@ func TestInbox(t *testing.T) {
 	}
 }
 
+func TestInboxAllPages(t *testing.T) {
+	daemon, socketPath := newMockDaemon(t)
+	defer daemon.stop()
+
+	// Three pages; msg_2 shows up again on page 3, as if it shifted.
+	pages := map[float64][]string{1: {"msg_1", "msg_2"}, 2: {"msg_3", "msg_4"}, 3: {"msg_2", "msg_5"}}
+	daemon.start(t, func(conn net.Conn) {
+		defer func() { _ = conn.Close() }()
+		decoder := json.NewDecoder(conn)
+		encoder := json.NewEncoder(conn)
+		for {
+			var request map[string]any
+			if err := decoder.Decode(&request); err != nil {
+				return
+			}
+			params, _ := request["params"].(map[string]any)
+			if params["unread"] != true {
+				t.Errorf("page %v: unread filter not forwarded: %v", params["page"], params)
+			}
+			var msgs []map[string]any
+			for _, id := range pages[params["page"].(float64)] {
+				msgs = append(msgs, map[string]any{"message_id": id})
+			}
+			_ = encoder.Encode(map[string]any{
+				"jsonrpc": "2.0",
+				"id":      request["id"],
+				"result":  map[string]any{"messages": msgs, "page": params["page"], "total_pages": 3},
+			})
+		}
+	})
+	<-daemon.Ready()
+
+	client, err := NewClient(socketPath)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	msgs, err := InboxAllPages(client, InboxOptions{Unread: true, PageSize: 2, Page: 7})
+	if err != nil {
+		t.Fatalf("InboxAllPages: %v", err)
+	}
+	var ids []string
+	for _, m := range msgs {
+		ids = append(ids, m.MessageID)
+	}
+	if got := strings.Join(ids, ","); got != "msg_1,msg_2,msg_3,msg_4,msg_5" {
+		t.Errorf("ids = %s, want msg_1..msg_5 once each", got)
+	}
+}
+
 func TestInbox_WithFilters(t *testing.T) {
 	daemon, socketPath := newMockDaemon(t)
 	defer daemon.stop()
@@ -301,6 +353,107 @@ func TestInbox_ChronoParam(t *testing.T) {
 	}
 }
 
+// TestInbox_SinceParam verifies --since reaches the daemon as an RFC3339
+// created_after alongside unread, so the two filters AND server-side.
+func TestInbox_SinceParam(t *testing.T) {
+	since := time.Date(2026, 3, 1, 12, 0, 0, 0, time.FixedZone("PST", -8*3600))
+	params := captureInboxParams(t, InboxOptions{CallerAgentID: "alice", Unread: true, CreatedAfter: since})
+	if got := params["created_after"]; got != "2026-03-01T20:00:00Z" {
+		t.Fatalf("created_after = %v, want 2026-03-01T20:00:00Z", got)
+	}
+	if unread, _ := params["unread"].(bool); !unread {
+		t.Fatalf("expected unread=true alongside created_after, got %v", params["unread"])
+	}
+
+	params = captureInboxParams(t, InboxOptions{CallerAgentID: "alice"})
+	if _, present := params["created_after"]; present {
+		t.Fatalf("expected created_after absent without --since, got %v", params["created_after"])
+	}
+}
+
+// TestInbox_TagParam verifies --tag reaches the daemon as tag and is
+// omitted when unset.
+func TestInbox_TagParam(t *testing.T) {
+	params := captureInboxParams(t, InboxOptions{CallerAgentID: "alice", Tag: "decision"})
+	if got := params["tag"]; got != "decision" {
+		t.Fatalf("tag = %v, want decision", got)
+	}
+
+	params = captureInboxParams(t, InboxOptions{CallerAgentID: "alice"})
+	if _, present := params["tag"]; present {
+		t.Fatalf("expected tag absent without --tag, got %v", params["tag"])
+	}
+}
+
+// TestInbox_PriorityParams verifies --priority and --priority-sort reach the
+// daemon and are omitted when unset.
+func TestInbox_PriorityParams(t *testing.T) {
+	params := captureInboxParams(t, InboxOptions{CallerAgentID: "alice", Priority: "high", PrioritySort: true})
+	if params["priority"] != "high" || params["priority_sort"] != true {
+		t.Fatalf("priority = %v, priority_sort = %v; want high/true", params["priority"], params["priority_sort"])
+	}
+
+	params = captureInboxParams(t, InboxOptions{CallerAgentID: "alice"})
+	if _, present := params["priority"]; present {
+		t.Fatalf("expected priority absent without --priority, got %v", params["priority"])
+	}
+	if _, present := params["priority_sort"]; present {
+		t.Fatalf("expected priority_sort absent without --priority-sort, got %v", params["priority_sort"])
+	}
+}
+
+// TestInbox_BumpSortParam verifies --bump-sort asks the daemon to sort by
+// bumped_at and leaves sort_by unset otherwise.
+func TestInbox_BumpSortParam(t *testing.T) {
+	params := captureInboxParams(t, InboxOptions{CallerAgentID: "alice", BumpSort: true})
+	if got := params["sort_by"]; got != "bumped_at" {
+		t.Fatalf("sort_by = %v, want bumped_at", got)
+	}
+
+	params = captureInboxParams(t, InboxOptions{CallerAgentID: "alice"})
+	if _, present := params["sort_by"]; present {
+		t.Fatalf("expected sort_by absent without --bump-sort, got %v", params["sort_by"])
+	}
+}
+
+// TestInbox_SequenceSortParam verifies --order sequence asks the daemon to
+// sort by origin sequence.
+func TestInbox_SequenceSortParam(t *testing.T) {
+	params := captureInboxParams(t, InboxOptions{CallerAgentID: "alice", SequenceSort: true})
+	if got := params["sort_by"]; got != "sequence" {
+		t.Fatalf("sort_by = %v, want sequence", got)
+	}
+}
+
+func TestInbox_CursorParam(t *testing.T) {
+	params := captureInboxParams(t, InboxOptions{CallerAgentID: "alice", Cursor: "eyJ4IjoxfQ"})
+	if got := params["after_cursor"]; got != "eyJ4IjoxfQ" {
+		t.Fatalf("after_cursor = %v, want the cursor", got)
+	}
+	if _, present := captureInboxParams(t, InboxOptions{CallerAgentID: "alice"})["after_cursor"]; present {
+		t.Fatal("after_cursor sent without a cursor")
+	}
+}
+
+func TestFormatInbox_CursorFooter(t *testing.T) {
+	result := &InboxResult{
+		Messages:   []Message{{MessageID: "msg_01", AgentID: "planner", CreatedAt: time.Now().Format(time.RFC3339)}},
+		Total:      47,
+		Page:       1,
+		PageSize:   1,
+		NextCursor: "abc123",
+	}
+	out := FormatInboxWithOptions(result, InboxFormatOptions{Cursor: true})
+	for _, want := range []string{"Showing 1 of 47 messages after cursor", "Next page: --cursor abc123"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if out := FormatInbox(result); strings.Contains(out, "Next page") {
+		t.Errorf("page-number output names a cursor:\n%s", out)
+	}
+}
+
 // TestInbox_DefaultNoChrono verifies the default omits the param, so
 // the daemon applies its newest-first default (thrum-3vl0). It must also leave
 // sort_order unset so the daemon's "desc" default takes effect.
@@ -374,6 +527,19 @@ func TestInbox_NoFromOmitsAuthorID(t *testing.T) {
 	}
 }
 
+func TestFormatMessageIDs(t *testing.T) {
+	result := &InboxResult{Messages: []Message{
+		{MessageID: "msg_02", AgentID: "planner"},
+		{MessageID: "msg_01", AgentID: "reviewer"},
+	}, Total: 2}
+	if got, want := FormatMessageIDs(result), "msg_02\nmsg_01\n"; got != want {
+		t.Errorf("FormatMessageIDs() = %q, want %q", got, want)
+	}
+	if got := FormatMessageIDs(&InboxResult{}); got != "" {
+		t.Errorf("FormatMessageIDs(empty) = %q, want empty", got)
+	}
+}
+
 func TestFormatInbox(t *testing.T) {
 	result := &InboxResult{
 		Messages: []Message{
@@ -535,6 +701,94 @@ func TestFormatInbox_NoHidden_NoWarning(t *testing.T) {
 	}
 }
 
+func TestFormatInbox_Tombstone(t *testing.T) {
+	withReason := Message{MessageID: "msg_a", AgentID: "bob", CreatedAt: "2026-05-14T15:00:00Z", Deleted: true, DeleteReason: "wrong channel"}
+	withReason.Body.Content = "deploy key is hunter2"
+	bare := Message{MessageID: "msg_b", AgentID: "bob", CreatedAt: "2026-05-14T15:01:00Z", Deleted: true}
+	result := &InboxResult{Messages: []Message{withReason, bare}, Total: 2, Page: 1, PageSize: 10, TotalPages: 1}
+
+	out := FormatInbox(result)
+	for _, want := range []string{"[deleted] reason: wrong channel", "deploy key is hunter2", "[deleted]"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "[deleted] reason: \n") || strings.Count(out, "reason:") != 1 {
+		t.Errorf("reason should only be shown when one was given:\n%s", out)
+	}
+}
+
+func TestFilterInboxByBody(t *testing.T) {
+	result := &InboxResult{Total: 30, Page: 1, PageSize: 3}
+	for i, body := range []string{"Deploy FAILED on main", "all green", "retrying the deploy"} {
+		var msg Message
+		msg.MessageID = fmt.Sprintf("msg_%d", i)
+		msg.Body.Content = body
+		result.Messages = append(result.Messages, msg)
+	}
+
+	re, err := CompileGrep("deploy")
+	if err != nil {
+		t.Fatalf("CompileGrep: %v", err)
+	}
+	scanned := FilterInboxByBody(result, re)
+	if scanned != 3 {
+		t.Errorf("scanned = %d, want 3", scanned)
+	}
+	if len(result.Messages) != 2 || result.Messages[0].MessageID != "msg_0" || result.Messages[1].MessageID != "msg_2" {
+		t.Fatalf("unexpected filtered messages: %+v", result.Messages)
+	}
+	if result.Total != 30 {
+		t.Errorf("Total must describe the daemon result set, got %d", result.Total)
+	}
+
+	out := FormatInboxWithOptions(result, InboxFormatOptions{Grep: "deploy", GrepRegexp: re, GrepScanned: scanned})
+	if !strings.Contains(out, `Showing 2 of 3 messages on page 1 matching "deploy" (30 total)`) {
+		t.Errorf("unexpected grep footer:\n%s", out)
+	}
+	if !strings.Contains(out, "**Deploy** FAILED") || !strings.Contains(out, "the **deploy**") {
+		t.Errorf("expected highlighted matches:\n%s", out)
+	}
+
+	re, _ = CompileGrep("nothing-matches")
+	FilterInboxByBody(result, re)
+	out = FormatInboxWithOptions(result, InboxFormatOptions{Grep: "nothing-matches", GrepScanned: 2})
+	if !strings.Contains(out, `No messages matching --grep "nothing-matches" on this page`) {
+		t.Errorf("unexpected grep empty state:\n%s", out)
+	}
+}
+
+func TestFilterInboxByBody_Regexp(t *testing.T) {
+	result := &InboxResult{Page: 1, PageSize: 3}
+	for i, body := range []string{"PR #42 ready", "see PR 7", "no pull requests"} {
+		var msg Message
+		msg.MessageID = fmt.Sprintf("msg_%d", i)
+		msg.Body.Content = body
+		result.Messages = append(result.Messages, msg)
+	}
+
+	re, err := CompileGrep(`^pr #\d+`)
+	if err != nil {
+		t.Fatalf("CompileGrep: %v", err)
+	}
+	FilterInboxByBody(result, re)
+	if len(result.Messages) != 1 || result.Messages[0].MessageID != "msg_0" {
+		t.Fatalf("unexpected filtered messages: %+v", result.Messages)
+	}
+
+	if re, err := CompileGrep(""); re != nil || err != nil {
+		t.Errorf("CompileGrep(\"\") = %v, %v; want nil, nil", re, err)
+	}
+	if FilterInboxByBody(result, nil) != 1 || len(result.Messages) != 1 {
+		t.Error("nil regexp must keep every message")
+	}
+
+	_, err = CompileGrep("deploy(")
+	if err == nil || !strings.Contains(err.Error(), `invalid --grep pattern "deploy("`) {
+		t.Errorf("expected invalid pattern error, got %v", err)
+	}
+}
+
 func TestFormatInbox_UnreadEmpty_IsSilent(t *testing.T) {
 	// --unread with zero messages should produce no output so that
 	// hook/cron driven bash calls stay quiet when there's nothing new.
@@ -661,6 +915,29 @@ func TestFormatInbox_EditedMessage(t *testing.T) {
 	}
 }
 
+func TestFormatInbox_HighPriorityMarker(t *testing.T) {
+	result := &InboxResult{
+		Messages: []Message{
+			{MessageID: "msg_urgent", AgentID: "agent:planner:ABC123", Priority: "high", CreatedAt: time.Now().Format(time.RFC3339)},
+			{MessageID: "msg_later", AgentID: "agent:planner:ABC123", Priority: "low", CreatedAt: time.Now().Format(time.RFC3339)},
+		},
+		Total:      2,
+		Page:       1,
+		PageSize:   10,
+		TotalPages: 1,
+	}
+
+	output := FormatInbox(result)
+	for _, line := range strings.Split(output, "\n") {
+		switch {
+		case strings.Contains(line, "msg_urgent") && !strings.Contains(line, "[high]"):
+			t.Errorf("high-priority header should be marked: %q", line)
+		case strings.Contains(line, "msg_later") && strings.Contains(line, "[high]"):
+			t.Errorf("low-priority header should not be marked: %q", line)
+		}
+	}
+}
+
 func TestExtractAgentName(t *testing.T) {
 	tests := []struct {
 		agentID string
@@ -769,3 +1046,107 @@ func TestWordWrap(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatInbox_PinnedHint(t *testing.T) {
+	result := &InboxResult{
+		Messages: []Message{
+			{MessageID: "msg_decision", AgentID: "agent:planner:ABC123", Pinned: true, CreatedAt: time.Now().Format(time.RFC3339)},
+			{MessageID: "msg_chatter", AgentID: "agent:planner:ABC123", CreatedAt: time.Now().Format(time.RFC3339)},
+		},
+		Total:       2,
+		PinnedCount: 3,
+		Page:        1,
+		PageSize:    10,
+		TotalPages:  1,
+	}
+
+	output := FormatInbox(result)
+	for _, line := range strings.Split(output, "\n") {
+		switch {
+		case strings.Contains(line, "msg_decision") && !strings.Contains(line, "📌"):
+			t.Errorf("pinned header should be marked: %q", line)
+		case strings.Contains(line, "msg_chatter") && strings.Contains(line, "📌"):
+			t.Errorf("unpinned header should not be marked: %q", line)
+		}
+	}
+	if !strings.Contains(output, "📌 3 pinned") {
+		t.Errorf("expected pinned hint, got:\n%s", output)
+	}
+
+	// The hint shows on an empty page too, and disappears when nothing is pinned.
+	empty := &InboxResult{PinnedCount: 1}
+	if out := FormatInbox(empty); !strings.Contains(out, "📌 1 pinned") {
+		t.Errorf("expected pinned hint on empty inbox, got:\n%s", out)
+	}
+	result.PinnedCount = 0
+	if out := FormatInbox(result); strings.Contains(out, "pinned") {
+		t.Errorf("unexpected pinned hint with nothing pinned:\n%s", out)
+	}
+}
+
+func TestFormatInbox_Threaded(t *testing.T) {
+	now := time.Now().Format(time.RFC3339)
+	msg := func(id, replyTo, threadID string) Message {
+		m := Message{MessageID: id, ReplyTo: replyTo, ThreadID: threadID, AgentID: "agent:planner:ABC123", CreatedAt: now}
+		m.Body.Content = "body of " + id
+		return m
+	}
+	result := &InboxResult{
+		// Page order as the daemon's reply clustering returns it; the
+		// grandchild arrives before its sibling's subtree is complete.
+		Messages: []Message{
+			msg("msg_root", "", ""),
+			msg("msg_a", "msg_root", "thr_1"),
+			msg("msg_b", "msg_root", "thr_1"),
+			msg("msg_a1", "msg_a", "thr_1"),
+			msg("msg_orphan", "msg_offpage", "thr_2"),
+		},
+		Total: 5, Page: 1, PageSize: 10, TotalPages: 1,
+	}
+
+	output := FormatInboxWithOptions(result, InboxFormatOptions{Threaded: true})
+
+	// Replies follow their parent depth-first: a1 sits under a, before b.
+	order := []string{"msg_root ", "msg_a ", "msg_a1 ", "msg_b ", "msg_orphan "}
+	last := -1
+	for _, id := range order {
+		idx := strings.Index(output, id)
+		if idx < 0 || idx < last {
+			t.Fatalf("expected %q after previous entries in:\n%s", id, output)
+		}
+		last = idx
+	}
+
+	lineWith := func(s string) string {
+		for _, line := range strings.Split(output, "\n") {
+			if strings.Contains(line, s) {
+				return line
+			}
+		}
+		t.Fatalf("no line containing %q in:\n%s", s, output)
+		return ""
+	}
+	if !strings.HasPrefix(lineWith("msg_a1 "), "│     ") {
+		t.Errorf("grandchild should be nested two levels: %q", lineWith("msg_a1 "))
+	}
+	if !strings.Contains(lineWith("msg_a1 "), "↳") {
+		t.Errorf("reply header should carry the ↳ connector: %q", lineWith("msg_a1 "))
+	}
+	if !strings.Contains(output, "(in thread thr_2 …)") {
+		t.Errorf("orphaned reply should get a breadcrumb:\n%s", output)
+	}
+
+	// One conversation, one section: separators only before the orphan.
+	if got := strings.Count(output, "├"); got != 1 {
+		t.Errorf("separators = %d, want 1 (before the orphan):\n%s", got, output)
+	}
+
+	// Without --threaded the page keeps its flat layout.
+	flat := FormatInbox(result)
+	if strings.Contains(flat, "in thread") {
+		t.Errorf("flat layout should not render breadcrumbs:\n%s", flat)
+	}
+	if got := strings.Count(flat, "├"); got != 4 {
+		t.Errorf("flat separators = %d, want 4", got)
+	}
+}
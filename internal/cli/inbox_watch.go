@@ -0,0 +1,257 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/leonletto/thrum/internal/bridge"
+)
+
+// InboxWatchOptions configures InboxWatch.
+type InboxWatchOptions struct {
+	Inbox      InboxOptions         // Filters; Page, PageSize and CreatedAfter are managed by the watcher
+	SocketPath string               // Daemon Unix socket (message.list)
+	RepoPath   string               // Locates .thrum/var/ws.port for the notification stream
+	Since      time.Time            // Emit messages created after this instant (zero = now)
+	Quiet      bool                 // Suppress stderr connection status
+	Render     func(Message) string // One line per message; nil writes JSON Lines
+}
+
+const (
+	// watchPageSize is the message.list page size used for catch-up queries.
+	watchPageSize = 100
+
+	// watchOverlap re-queries a short window before the newest emitted
+	// message so siblings sharing its created_at aren't lost to
+	// created_after's strict comparison. Already-emitted IDs are skipped.
+	watchOverlap = time.Second
+
+	// watchResyncInterval is a safety-net catch-up for messages that never
+	// produce a local notification.message broadcast (peer-synced events).
+	watchResyncInterval = 30 * time.Second
+
+	watchRetryMin = 500 * time.Millisecond
+	watchRetryMax = 5 * time.Second
+)
+
+// InboxWatch streams inbox messages to out as JSON Lines, one Message per
+// line, oldest first, or through opts.Render when set. It listens for notification.message broadcasts on the
+// daemon WebSocket and answers each with a message.list catch-up from the
+// last emitted created_at, so filters apply exactly as in `thrum inbox`.
+// When the daemon goes away it reconnects with backoff and replays
+// everything newer than the last emitted message. Messages are not marked
+// read. Returns nil when ctx is canceled, or an error if out can't be
+// written.
+func InboxWatch(ctx context.Context, out io.Writer, opts InboxWatchOptions) error {
+	since := opts.Since
+	if since.IsZero() {
+		since = time.Now()
+	}
+	emit := jsonLines(out)
+	if opts.Render != nil {
+		emit = func(m Message) error {
+			_, err := io.WriteString(out, opts.Render(m))
+			return err
+		}
+	}
+	w := &inboxWatcher{
+		emit:  emit,
+		since: since,
+		seen:  make(map[string]time.Time),
+		list: func(after time.Time) ([]Message, error) {
+			return listInboxSince(opts.SocketPath, opts.Inbox, after)
+		},
+		dial: func(ctx context.Context) (<-chan bridge.Notification, func(), error) {
+			return dialDaemonNotifications(ctx, opts.RepoPath)
+		},
+		status: func(msg string) {
+			if !opts.Quiet {
+				fmt.Fprintln(os.Stderr, msg)
+			}
+		},
+		retryMin: watchRetryMin,
+		retryMax: watchRetryMax,
+	}
+	return w.run(ctx)
+}
+
+// inboxWatcher holds InboxWatch state. list and dial are swapped out in
+// tests.
+type inboxWatcher struct {
+	emit     func(Message) error
+	since    time.Time            // Lower bound until the first message is emitted
+	last     time.Time            // created_at of the newest emitted message
+	seen     map[string]time.Time // Emitted IDs within the overlap window
+	writeErr error
+
+	list   func(after time.Time) ([]Message, error) // Newest first
+	dial   func(ctx context.Context) (<-chan bridge.Notification, func(), error)
+	status func(msg string)
+
+	retryMin, retryMax time.Duration
+}
+
+func (w *inboxWatcher) run(ctx context.Context) error {
+	retry := w.retryMin
+	everConnected, lost := false, false
+	for {
+		// Subscribe before catching up so nothing created in between is
+		// missed: it either shows up in the catch-up or triggers a
+		// notification.
+		notifs, closeFn, err := w.dial(ctx)
+		if err == nil {
+			if err = w.catchUp(); err != nil {
+				closeFn()
+			}
+		}
+		if w.writeErr != nil {
+			return w.writeErr
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if !everConnected && !lost {
+				w.status("Daemon not available, waiting for it to start...")
+				lost = true
+			}
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(retry):
+			}
+			retry = min(retry*2, w.retryMax)
+			continue
+		}
+
+		if everConnected && lost {
+			w.status("Reconnected to daemon")
+		}
+		everConnected, lost = true, false
+		retry = w.retryMin
+
+		w.stream(ctx, notifs)
+		closeFn()
+		if w.writeErr != nil {
+			return w.writeErr
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		w.status("Lost connection to daemon, reconnecting...")
+		lost = true
+	}
+}
+
+// stream runs a catch-up for every notification.message (and every
+// watchResyncInterval) until ctx is canceled, the channel closes, or a
+// catch-up fails.
+func (w *inboxWatcher) stream(ctx context.Context, notifs <-chan bridge.Notification) {
+	resync := time.NewTicker(watchResyncInterval)
+	defer resync.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case n, ok := <-notifs:
+			if !ok {
+				return
+			}
+			if n.Method != "notification.message" {
+				continue
+			}
+		case <-resync.C:
+		}
+		if err := w.catchUp(); err != nil {
+			return
+		}
+	}
+}
+
+// catchUp emits every listed message newer than the last one emitted,
+// oldest first.
+func (w *inboxWatcher) catchUp() error {
+	after := w.since
+	if !w.last.IsZero() {
+		after = w.last.Add(-watchOverlap)
+	}
+	msgs, err := w.list(after)
+	if err != nil {
+		return err
+	}
+	for i := len(msgs) - 1; i >= 0; i-- {
+		m := msgs[i]
+		if _, dup := w.seen[m.MessageID]; dup {
+			continue
+		}
+		if err := w.emit(m); err != nil {
+			w.writeErr = fmt.Errorf("write message: %w", err)
+			return w.writeErr
+		}
+		created, _ := time.Parse(time.RFC3339Nano, m.CreatedAt)
+		w.seen[m.MessageID] = created
+		if created.After(w.last) {
+			w.last = created
+		}
+	}
+	cutoff := w.last.Add(-watchOverlap)
+	for id, created := range w.seen {
+		if created.Before(cutoff) {
+			delete(w.seen, id)
+		}
+	}
+	return nil
+}
+
+// jsonLines returns an emitter writing each message to out as one JSON line.
+func jsonLines(out io.Writer) func(Message) error {
+	enc := json.NewEncoder(out)
+	return func(m Message) error { return enc.Encode(m) }
+}
+
+// listInboxSince returns every message matching opts created after after,
+// newest first, walking all message.list pages.
+func listInboxSince(socketPath string, opts InboxOptions, after time.Time) ([]Message, error) {
+	client, err := NewClient(socketPath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = client.Close() }()
+
+	opts.CreatedAfter = after
+	opts.PageSize = watchPageSize
+	opts.Chronological = false
+	opts.PrioritySort = false
+
+	var msgs []Message
+	for page := 1; ; page++ {
+		opts.Page = page
+		result, err := Inbox(client, opts)
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, result.Messages...)
+		if page >= result.TotalPages {
+			return msgs, nil
+		}
+	}
+}
+
+// dialDaemonNotifications connects to the daemon's loopback WebSocket, which
+// pushes notification.message to every connected client.
+func dialDaemonNotifications(ctx context.Context, repoPath string) (<-chan bridge.Notification, func(), error) {
+	port := ReadWebSocketPort(repoPath)
+	if port == 0 {
+		return nil, nil, fmt.Errorf("daemon WebSocket port not found")
+	}
+	ws := bridge.NewWSClient(fmt.Sprintf("ws://127.0.0.1:%d/ws", port),
+		bridge.WithAddressValidator(bridge.LoopbackValidator))
+	if err := ws.Connect(ctx); err != nil {
+		return nil, nil, err
+	}
+	return ws.Notifications(), func() { _ = ws.Close() }, nil
+}
@@ -0,0 +1,171 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/leonletto/thrum/internal/bridge"
+)
+
+func watchMsg(id string, created time.Time) Message {
+	return Message{MessageID: id, AgentID: "agent:ops:AAA", CreatedAt: created.UTC().Format(time.RFC3339Nano)}
+}
+
+func decodeWatchLines(t *testing.T, out *bytes.Buffer) []string {
+	t.Helper()
+	var ids []string
+	sc := bufio.NewScanner(out)
+	for sc.Scan() {
+		var m Message
+		if err := json.Unmarshal(sc.Bytes(), &m); err != nil {
+			t.Fatalf("line %q is not a Message: %v", sc.Text(), err)
+		}
+		ids = append(ids, m.MessageID)
+	}
+	return ids
+}
+
+// TestInboxWatch_StreamsAndReplaysOnReconnect drives the watcher through a
+// notification, a dropped connection, a failed redial, and a reconnect. Each
+// message must be emitted exactly once, oldest first, and the post-reconnect
+// catch-up must start from the last emitted created_at.
+func TestInboxWatch_StreamsAndReplaysOnReconnect(t *testing.T) {
+	base := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	m1, m2, m3, m4 := watchMsg("m1", base.Add(time.Second)), watchMsg("m2", base.Add(2*time.Second)),
+		watchMsg("m3", base.Add(2*time.Second)), watchMsg("m4", base.Add(5*time.Second))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The daemon's view: newest first, filtered by after.
+	var mu sync.Mutex
+	var stored []Message
+	var afters []time.Time
+	list := func(after time.Time) ([]Message, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		afters = append(afters, after)
+		var out []Message
+		for i := len(stored) - 1; i >= 0; i-- {
+			if created, _ := time.Parse(time.RFC3339Nano, stored[i].CreatedAt); created.After(after) {
+				out = append(out, stored[i])
+			}
+		}
+		return out, nil
+	}
+
+	var notifs chan bridge.Notification
+	dials := 0
+	dial := func(context.Context) (<-chan bridge.Notification, func(), error) {
+		mu.Lock()
+		defer mu.Unlock()
+		dials++
+		switch dials {
+		case 1:
+			stored = []Message{m1}
+		case 2:
+			return nil, nil, errors.New("daemon restarting")
+		case 3:
+			// Arrived while disconnected. The stream drops right after the
+			// catch-up so the next dial ends the test.
+			stored = append(stored, m4)
+			closed := make(chan bridge.Notification)
+			close(closed)
+			return closed, func() {}, nil
+		default:
+			cancel()
+			return nil, nil, context.Canceled
+		}
+		notifs = make(chan bridge.Notification, 4)
+		return notifs, func() {}, nil
+	}
+
+	var out bytes.Buffer
+	w := &inboxWatcher{
+		emit:     jsonLines(&out),
+		since:    base,
+		seen:     make(map[string]time.Time),
+		list:     list,
+		dial:     dial,
+		status:   func(string) {},
+		retryMin: time.Millisecond,
+		retryMax: time.Millisecond,
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- w.run(ctx) }()
+
+	// First connection: initial catch-up emits m1. Then two messages with
+	// the same created_at arrive and a notification fires; after that the
+	// stream drops.
+	waitForWatch(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(afters) == 1
+	})
+	mu.Lock()
+	stored = append(stored, m2, m3)
+	first := notifs
+	mu.Unlock()
+	first <- bridge.Notification{Method: "notification.presence"}
+	first <- bridge.Notification{Method: "notification.message"}
+	close(first)
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("run() = %v, want nil after cancel", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("watcher did not stop")
+	}
+
+	if got := decodeWatchLines(t, &out); len(got) != 4 || got[0] != "m1" || got[3] != "m4" {
+		t.Fatalf("emitted %v, want [m1 m2|m3 m3|m2 m4]", got)
+	}
+	if want := base.Add(2 * time.Second).Add(-watchOverlap); !afters[len(afters)-1].Equal(want) {
+		t.Errorf("reconnect catch-up after = %v, want %v (last created_at minus overlap)", afters[len(afters)-1], want)
+	}
+}
+
+func TestInboxWatch_WriteErrorStops(t *testing.T) {
+	base := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	w := &inboxWatcher{
+		emit:  jsonLines(failingWriter{}),
+		since: base,
+		seen:  make(map[string]time.Time),
+		list: func(time.Time) ([]Message, error) {
+			return []Message{watchMsg("m1", base.Add(time.Second))}, nil
+		},
+		dial: func(context.Context) (<-chan bridge.Notification, func(), error) {
+			return make(chan bridge.Notification), func() {}, nil
+		},
+		status:   func(string) {},
+		retryMin: time.Millisecond,
+		retryMax: time.Millisecond,
+	}
+	if err := w.run(context.Background()); err == nil {
+		t.Fatal("run() = nil, want write error")
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("broken pipe") }
+
+func waitForWatch(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -24,9 +24,10 @@ var ErrNotGitRepo = errors.New("not a git repository")
 
 // InitOptions contains options for initializing a Thrum repository.
 type InitOptions struct {
-	RepoPath string
-	Force    bool
-	Stealth  bool // Use .git/info/exclude instead of .gitignore
+	RepoPath        string
+	Force           bool
+	Stealth         bool // Use .git/info/exclude instead of .gitignore
+	ImportGitignore bool // Fold loose entries from an older init into the managed block
 }
 
 // SyncReconciliation describes how Init should set up the sync branch and
@@ -179,12 +180,12 @@ func Init(opts InitOptions) error {
 
 	// 4. Add exclusions (.gitignore or .git/info/exclude in stealth mode)
 	if opts.Stealth {
-		if err := updateGitExclude(opts.RepoPath); err != nil {
+		if err := updateGitExclude(opts.RepoPath, opts.ImportGitignore); err != nil {
 			retErr = fmt.Errorf("failed to update .git/info/exclude: %w", err)
 			return retErr
 		}
 	} else {
-		if err := updateGitignore(opts.RepoPath); err != nil {
+		if err := updateGitignore(opts.RepoPath, opts.ImportGitignore); err != nil {
 			retErr = fmt.Errorf("failed to update .gitignore: %w", err)
 			return retErr
 		}
@@ -293,86 +294,35 @@ var thrumExcludeEntries = []string{
 	"scripts/thrum-check-inbox.sh",
 }
 
-// updateGitignore adds Thrum-related entries to .gitignore.
-func updateGitignore(repoPath string) error {
-	gitignorePath := filepath.Join(repoPath, ".gitignore")
+// Markers delimiting the Thrum-managed block in .gitignore and
+// .git/info/exclude. init rewrites everything between them in place, so
+// re-running it never appends a second copy.
+const (
+	thrumBlockBegin = "# BEGIN thrum (managed by thrum init; edits inside this block are overwritten)"
+	thrumBlockEnd   = "# END thrum"
+)
 
-	// Entries to add
+// legacyThrumHeaders are the section comments init wrote before the managed
+// block existed. They are folded into the block along with loose entries.
+var legacyThrumHeaders = []string{
+	"# Thrum data directory (all data lives on a-sync branch via worktree)",
+	"# Thrum stealth mode (added by thrum init --stealth)",
+}
+
+// updateGitignore writes the Thrum-managed block to .gitignore. With
+// importLoose set, Thrum entries already present outside a block (from an
+// older init) are folded into it; see mergeThrumBlock.
+func updateGitignore(repoPath string, importLoose bool) error {
+	gitignorePath := filepath.Join(repoPath, ".gitignore")
 	entries := append([]string{
 		"# Thrum data directory (all data lives on a-sync branch via worktree)",
 	}, thrumExcludeEntries...)
-
-	// Read existing .gitignore if it exists
-	var existing []byte
-	var err error
-	if _, statErr := os.Stat(gitignorePath); statErr == nil {
-		existing, err = os.ReadFile(gitignorePath) // #nosec G304 -- gitignorePath is <repoPath>/.gitignore, derived from the CLI-provided repo root
-		if err != nil {
-			return err
-		}
-	}
-
-	existingStr := string(existing)
-
-	// Check if entries already exist (line-by-line to avoid substring false positives)
-	needsUpdate := false
-	existingLines := strings.Split(existingStr, "\n")
-	for _, entry := range entries {
-		// Skip comment line when checking
-		if strings.HasPrefix(entry, "#") {
-			continue
-		}
-		found := false
-		for _, line := range existingLines {
-			if strings.TrimSpace(line) == entry {
-				found = true
-				break
-			}
-		}
-		if !found {
-			needsUpdate = true
-			break
-		}
-	}
-
-	if !needsUpdate {
-		return nil
-	}
-
-	// Append entries
-	f, err := os.OpenFile(gitignorePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600) // #nosec G304 -- gitignorePath is <repoPath>/.gitignore, derived from the CLI-provided repo root
-	if err != nil {
-		return err
-	}
-	defer func() { _ = f.Close() }()
-
-	// Add newline if file doesn't end with one
-	if len(existing) > 0 && existing[len(existing)-1] != '\n' {
-		if _, err := f.WriteString("\n"); err != nil {
-			return err
-		}
-	}
-
-	// Add a blank line before our section if file has content
-	if len(existing) > 0 {
-		if _, err := f.WriteString("\n"); err != nil {
-			return err
-		}
-	}
-
-	// Write entries
-	for _, entry := range entries {
-		if _, err := f.WriteString(entry + "\n"); err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return writeThrumBlock(gitignorePath, entries, importLoose)
 }
 
-// updateGitExclude adds Thrum-related entries to .git/info/exclude (stealth mode).
-// This avoids any footprint in tracked files like .gitignore.
-func updateGitExclude(repoPath string) error {
+// updateGitExclude writes the Thrum-managed block to .git/info/exclude
+// (stealth mode). This avoids any footprint in tracked files like .gitignore.
+func updateGitExclude(repoPath string, importLoose bool) error {
 	// Resolve the git dir (handles worktrees correctly)
 	out, err := safecmd.Git(stdcontext.Background(), repoPath, "rev-parse", "--git-dir")
 	if err != nil {
@@ -388,69 +338,121 @@ func updateGitExclude(repoPath string) error {
 		return fmt.Errorf("create .git/info/: %w", err)
 	}
 
-	excludePath := filepath.Join(infoDir, "exclude")
-
 	entries := append([]string{
 		"# Thrum stealth mode (added by thrum init --stealth)",
 	}, thrumExcludeEntries...)
+	return writeThrumBlock(filepath.Join(infoDir, "exclude"), entries, importLoose)
+}
 
-	// Read existing exclude file
-	var existing []byte
-	if _, statErr := os.Stat(excludePath); statErr == nil {
-		existing, err = os.ReadFile(excludePath) // #nosec G304 -- excludePath is .git/info/exclude, a known git internal path
-		if err != nil {
-			return err
-		}
+// writeThrumBlock merges the Thrum-managed block holding entries into the
+// ignore file at path, creating the file if needed. The file is only
+// rewritten when its content changes.
+func writeThrumBlock(path string, entries []string, importLoose bool) error {
+	existing, err := os.ReadFile(path) // #nosec G304 -- path is <repo>/.gitignore or <git-dir>/info/exclude
+	if err != nil && !os.IsNotExist(err) {
+		return err
 	}
 
-	// Check if entries already present
-	existingLines := strings.Split(string(existing), "\n")
-	needsUpdate := false
-	for _, entry := range entries {
-		if strings.HasPrefix(entry, "#") {
-			continue
-		}
-		found := false
-		for _, line := range existingLines {
-			if strings.TrimSpace(line) == entry {
-				found = true
-				break
+	merged, changed, err := mergeThrumBlock(string(existing), entries, importLoose)
+	if err != nil {
+		return fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	if !changed {
+		return nil
+	}
+	return os.WriteFile(path, []byte(merged), 0600) // #nosec G304 -- see above
+}
+
+// mergeThrumBlock returns content with its Thrum-managed block set to
+// entries, and whether that changed anything. An existing block is replaced
+// where it stands; otherwise one is appended. Whenever the block is written,
+// Thrum entries and legacy headers lying outside it are moved into it so no
+// pattern is listed twice.
+//
+// A file from an older init that already lists every entry loosely, with no
+// block, is left untouched unless importLoose is set. A begin marker without
+// an end marker is an error rather than a guess at where the block stops.
+func mergeThrumBlock(content string, entries []string, importLoose bool) (string, bool, error) {
+	var lines []string
+	if content != "" {
+		lines = strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	}
+
+	begin, end := -1, -1
+	for i, line := range lines {
+		switch strings.TrimSpace(line) {
+		case thrumBlockBegin:
+			if begin == -1 {
+				begin = i
+			}
+		case thrumBlockEnd:
+			if begin != -1 && end == -1 {
+				end = i
 			}
-		}
-		if !found {
-			needsUpdate = true
-			break
 		}
 	}
-
-	if !needsUpdate {
-		return nil
+	if begin != -1 && end == -1 {
+		return "", false, fmt.Errorf("unterminated thrum block: %q has no matching %q", thrumBlockBegin, thrumBlockEnd)
 	}
 
-	f, err := os.OpenFile(excludePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600) // #nosec G304 -- excludePath is .git/info/exclude, a known git internal path
-	if err != nil {
-		return err
+	loose := make(map[string]bool, len(entries)+len(legacyThrumHeaders))
+	for _, entry := range entries {
+		loose[entry] = true
+	}
+	for _, header := range legacyThrumHeaders {
+		loose[header] = true
 	}
-	defer func() { _ = f.Close() }()
 
-	if len(existing) > 0 && existing[len(existing)-1] != '\n' {
-		if _, err := f.WriteString("\n"); err != nil {
-			return err
+	// Lines outside the block, minus loose Thrum lines; insertAt is where
+	// the block goes back.
+	var kept []string
+	insertAt := -1
+	present := make(map[string]bool)
+	for i, line := range lines {
+		if begin != -1 && i >= begin && i <= end {
+			if i == begin {
+				insertAt = len(kept)
+			}
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+		if loose[trimmed] {
+			present[trimmed] = true
+			continue
 		}
+		kept = append(kept, line)
 	}
-	if len(existing) > 0 {
-		if _, err := f.WriteString("\n"); err != nil {
-			return err
+
+	if begin == -1 && !importLoose {
+		complete := true
+		for _, entry := range entries {
+			if !strings.HasPrefix(entry, "#") && !present[entry] {
+				complete = false
+				break
+			}
+		}
+		if complete {
+			return content, false, nil
 		}
 	}
 
-	for _, entry := range entries {
-		if _, err := f.WriteString(entry + "\n"); err != nil {
-			return err
+	block := append(append([]string{thrumBlockBegin}, entries...), thrumBlockEnd)
+	var out []string
+	if insertAt == -1 {
+		for len(kept) > 0 && strings.TrimSpace(kept[len(kept)-1]) == "" {
+			kept = kept[:len(kept)-1]
+		}
+		out = kept
+		if len(out) > 0 {
+			out = append(out, "")
 		}
+		out = append(out, block...)
+	} else {
+		out = append(append(append(out, kept[:insertAt]...), block...), kept[insertAt:]...)
 	}
 
-	return nil
+	merged := strings.Join(out, "\n") + "\n"
+	return merged, merged != content, nil
 }
 
 // reinitIdentityOnly refreshes identity and strategy files without touching
@@ -199,7 +199,7 @@ func TestInit_NotGitRepo(t *testing.T) {
 func TestUpdateGitignore_NewFile(t *testing.T) {
 	tmpDir := t.TempDir()
 
-	err := updateGitignore(tmpDir)
+	err := updateGitignore(tmpDir, false)
 	if err != nil {
 		t.Fatalf("updateGitignore failed: %v", err)
 	}
@@ -229,7 +229,7 @@ func TestUpdateGitignore_ExistingFile(t *testing.T) {
 		t.Fatalf("Failed to create .gitignore: %v", err)
 	}
 
-	err := updateGitignore(tmpDir)
+	err := updateGitignore(tmpDir, false)
 	if err != nil {
 		t.Fatalf("updateGitignore failed: %v", err)
 	}
@@ -254,7 +254,7 @@ func TestUpdateGitignore_Idempotent(t *testing.T) {
 	tmpDir := t.TempDir()
 
 	// First update
-	if err := updateGitignore(tmpDir); err != nil {
+	if err := updateGitignore(tmpDir, false); err != nil {
 		t.Fatalf("First updateGitignore failed: %v", err)
 	}
 
@@ -265,7 +265,7 @@ func TestUpdateGitignore_Idempotent(t *testing.T) {
 	}
 
 	// Second update
-	if err := updateGitignore(tmpDir); err != nil {
+	if err := updateGitignore(tmpDir, false); err != nil {
 		t.Fatalf("Second updateGitignore failed: %v", err)
 	}
 
@@ -280,6 +280,53 @@ func TestUpdateGitignore_Idempotent(t *testing.T) {
 	}
 }
 
+// TestMergeThrumBlock covers the managed-block merge shared by .gitignore
+// and .git/info/exclude.
+func TestMergeThrumBlock(t *testing.T) {
+	entries := []string{"# Thrum header", ".thrum/", ".thrum.*.json"}
+	block := thrumBlockBegin + "\n# Thrum header\n.thrum/\n.thrum.*.json\n" + thrumBlockEnd + "\n"
+	legacy := "node_modules/\n\n# Thrum data directory (all data lives on a-sync branch via worktree)\n.thrum/\n.thrum.*.json\n"
+
+	tests := []struct {
+		name        string
+		content     string
+		importLoose bool
+		want        string
+	}{
+		{"empty file", "", false, block},
+		{"appended after user content", "node_modules/", false, "node_modules/\n\n" + block},
+		{"complete legacy entries left alone", legacy, false, legacy},
+		{"legacy entries imported", legacy, true, "node_modules/\n\n" + block},
+		{"partial legacy entries folded", "# Thrum stealth mode (added by thrum init --stealth)\n.thrum/\n*.log\n", false, "*.log\n\n" + block},
+		{
+			"stale block replaced in place",
+			"a/\n" + thrumBlockBegin + "\n.thrum/\nold-entry\n" + thrumBlockEnd + "\nb/\n",
+			false,
+			"a/\n" + block + "b/\n",
+		},
+		{"duplicate outside block removed", block + ".thrum/\ndist/\n", false, block + "dist/\n"},
+		{"current block unchanged", "x/\n\n" + block, false, "x/\n\n" + block},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed, err := mergeThrumBlock(tt.content, entries, tt.importLoose)
+			if err != nil {
+				t.Fatalf("mergeThrumBlock: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("mergeThrumBlock =\n%q\nwant\n%q", got, tt.want)
+			}
+			if changed != (tt.want != tt.content) {
+				t.Errorf("changed = %v, want %v", changed, !changed)
+			}
+		})
+	}
+
+	if _, _, err := mergeThrumBlock(thrumBlockBegin+"\n.thrum/\n", entries, false); err == nil {
+		t.Error("expected error for unterminated block")
+	}
+}
+
 func TestIsGitWorktree(t *testing.T) {
 	// Create main repo
 	mainDir := t.TempDir()
@@ -1024,4 +1071,19 @@ func TestInit_StealthExcludeContainsCheckInboxScript(t *testing.T) {
 	if !strings.Contains(string(data), "scripts/thrum-check-inbox.sh") {
 		t.Errorf("expected scripts/thrum-check-inbox.sh in .git/info/exclude, got:\n%s", data)
 	}
+	if !strings.Contains(string(data), thrumBlockBegin) {
+		t.Errorf("expected managed block in .git/info/exclude, got:\n%s", data)
+	}
+
+	// Re-running stealth init must not add a second block.
+	if err := Init(InitOptions{RepoPath: tmp, Stealth: true, Force: true}); err != nil {
+		t.Fatalf("Init --force: %v", err)
+	}
+	again, err := os.ReadFile(filepath.Join(tmp, ".git", "info", "exclude")) //nolint:gosec // G304 - test fixture path
+	if err != nil {
+		t.Fatalf("read .git/info/exclude: %v", err)
+	}
+	if string(again) != string(data) {
+		t.Errorf("exclude changed on re-init:\n%s", again)
+	}
 }
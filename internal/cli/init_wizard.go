@@ -20,20 +20,21 @@ import (
 // thrum-75rw.1; until then RunWizard is reachable only via direct calls,
 // currently exercised by tests.)
 type WizardConfig struct {
-	RepoPath      string
-	Prompter      Prompter
-	NameFlag      string
-	RoleFlag      string
-	ModuleFlag    string
-	WorktreesRoot string
-	RolesChoice   string // "enhanced" | "default" | "skip" | ""
-	NoDaemon      bool
-	Force         bool
-	Stealth       bool
-	Runtime       string
+	RepoPath        string
+	Prompter        Prompter
+	NameFlag        string
+	RoleFlag        string
+	ModuleFlag      string
+	WorktreesRoot   string
+	RolesChoice     string // "enhanced" | "default" | "skip" | ""
+	NoDaemon        bool
+	Force           bool
+	Stealth         bool
+	ImportGitignore bool
+	Runtime         string
 
 	// gitignoreSnapshot / excludeSnapshot capture the files Init() will
-	// append to so rollback can restore them byte-for-byte. nil means
+	// write to so rollback can restore them byte-for-byte. nil means
 	// "file did not exist before Init"; in that case rollback removes the
 	// file rather than restoring nil bytes. Populated by snapshotGitFiles
 	// before Init runs.
@@ -174,7 +175,7 @@ func stepDaemon(cfg *WizardConfig) error {
 	}
 	switch decideDaemonAction(isDaemonRunning(cfg.RepoPath), cfg.Force) {
 	case daemonActionStart:
-		return DaemonStart(cfg.RepoPath, true, cfg.Force)
+		return DaemonStart(cfg.RepoPath, DaemonStartOptions{LocalOnly: true, Force: cfg.Force})
 	case daemonActionRestart:
 		return DaemonRestart(cfg.RepoPath, true, cfg.Force)
 	case daemonActionSkip:
@@ -319,9 +320,10 @@ func RunWizard(cfg *WizardConfig) error {
 	}()
 
 	if err := Init(InitOptions{
-		RepoPath: cfg.RepoPath,
-		Force:    cfg.Force,
-		Stealth:  cfg.Stealth,
+		RepoPath:        cfg.RepoPath,
+		Force:           cfg.Force,
+		Stealth:         cfg.Stealth,
+		ImportGitignore: cfg.ImportGitignore,
 	}); err != nil {
 		return err
 	}
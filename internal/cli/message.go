@@ -1,8 +1,18 @@
 package cli
 
 import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/leonletto/thrum/internal/types"
 )
@@ -11,23 +21,44 @@ import (
 
 // MessageGetResponse represents the response from message.get RPC.
 type MessageGetResponse struct {
-	Message MessageDetail `json:"message"`
+	Message MessageDetail         `json:"message"`
+	Thread  *MessageThreadContext `json:"thread,omitempty"` // Filled client-side by message get --thread
+}
+
+// MessageThreadContext is the messages immediately before and after a
+// message in its thread, in chronological order.
+type MessageThreadContext struct {
+	ThreadID string      `json:"thread_id,omitempty"`
+	Position int         `json:"position,omitempty"` // 1-based; 0 when only the reply_to parent is known
+	Count    int         `json:"count,omitempty"`
+	Previous *ThreadNode `json:"previous,omitempty"`
+	Next     *ThreadNode `json:"next,omitempty"`
 }
 
 // MessageDetail represents detailed information about a message.
 type MessageDetail struct {
-	MessageID  string            `json:"message_id"`
-	ThreadID   string            `json:"thread_id,omitempty"`
-	Author     AuthorInfo        `json:"author"`
-	Body       types.MessageBody `json:"body"`
-	Scopes     []types.Scope     `json:"scopes"`
-	Refs       []types.Ref       `json:"refs"`
-	Metadata   MessageMetadata   `json:"metadata"`
-	CreatedAt  string            `json:"created_at"`
-	UpdatedAt  string            `json:"updated_at,omitempty"`
-	Deleted    bool              `json:"deleted"`
-	Audiences  []Audience        `json:"audiences,omitempty"`
-	Recipients []RecipientState  `json:"recipients,omitempty"`
+	MessageID   string              `json:"message_id"`
+	ThreadID    string              `json:"thread_id,omitempty"`
+	Author      AuthorInfo          `json:"author"`
+	Body        types.MessageBody   `json:"body"`
+	Scopes      []types.Scope       `json:"scopes"`
+	Refs        []types.Ref         `json:"refs"`
+	Metadata    MessageMetadata     `json:"metadata"`
+	CreatedAt   string              `json:"created_at"`
+	UpdatedAt   string              `json:"updated_at,omitempty"`
+	Deleted     bool                `json:"deleted"`
+	Audiences   []Audience          `json:"audiences,omitempty"`
+	Recipients  []RecipientState    `json:"recipients,omitempty"`
+	Reactions   map[string][]string `json:"reactions,omitempty"` // emoji → agent IDs
+	Tags        []string            `json:"tags,omitempty"`
+	Attachments []Attachment        `json:"attachments,omitempty"`
+}
+
+// Attachment is a file sent with a message via send --attach.
+type Attachment struct {
+	Name   string `json:"name"`
+	Path   string `json:"path"` // relative to the sync worktree
+	SHA256 string `json:"sha256"`
 }
 
 // AuthorInfo represents the message author.
@@ -76,14 +107,21 @@ func FormatMessageGet(resp *MessageGetResponse) string {
 		fmt.Fprintf(&out, "  Scopes:  %s\n", strings.Join(scopeStrs, ", "))
 	}
 
-	if len(msg.Refs) > 0 {
-		refStrs := make([]string, len(msg.Refs))
-		for i, r := range msg.Refs {
-			refStrs[i] = r.Type + ":" + r.Value
+	// Attachment refs are listed under Attachments instead.
+	var refStrs []string
+	for _, r := range msg.Refs {
+		if r.Type != "attachment" {
+			refStrs = append(refStrs, r.Type+":"+r.Value)
 		}
+	}
+	if len(refStrs) > 0 {
 		fmt.Fprintf(&out, "  Refs:    %s\n", strings.Join(refStrs, ", "))
 	}
 
+	if len(msg.Tags) > 0 {
+		fmt.Fprintf(&out, "  Tags:    %s\n", strings.Join(msg.Tags, ", "))
+	}
+
 	if msg.UpdatedAt != "" {
 		fmt.Fprintf(&out, "  Edited:  %s\n", formatRelativeTime(msg.UpdatedAt))
 	}
@@ -110,17 +148,142 @@ func FormatMessageGet(resp *MessageGetResponse) string {
 		}
 	}
 
+	if len(msg.Reactions) > 0 {
+		fmt.Fprintf(&out, "  Reactions: %s\n", formatReactions(msg.Reactions))
+	}
+
+	if len(msg.Attachments) > 0 {
+		out.WriteString("  Attachments:\n")
+		for _, a := range msg.Attachments {
+			fmt.Fprintf(&out, "    - %s (sha256 %s)\n", a.Name, shortDigest(a.SHA256))
+		}
+		fmt.Fprintf(&out, "    fetch with: thrum message attachment fetch %s NAME\n", msg.MessageID)
+	}
+
 	if msg.Deleted {
 		out.WriteString("  Status:  DELETED\n")
 	}
 
 	out.WriteString("\n")
-	out.WriteString(msg.Body.Content)
+	out.WriteString(Plain(msg.Body.Content))
 	out.WriteString("\n")
 
 	return out.String()
 }
 
+// MessageThreadNeighbors finds the messages around msg in its thread using
+// thread.get. A message without a thread_id falls back to its reply_to
+// parent as the previous message. Returns nil when there is no thread
+// context to show.
+func MessageThreadNeighbors(client *Client, msg *MessageDetail) (*MessageThreadContext, error) {
+	if msg.ThreadID == "" {
+		var parentID string
+		for _, r := range msg.Refs {
+			if r.Type == "reply_to" {
+				parentID = r.Value
+			}
+		}
+		if parentID == "" {
+			return nil, nil
+		}
+		parent, err := MessageGet(client, parentID)
+		if err != nil {
+			return nil, err
+		}
+		p := parent.Message
+		return &MessageThreadContext{Previous: &ThreadNode{
+			MessageID: p.MessageID,
+			AgentID:   p.Author.AgentID,
+			CreatedAt: p.CreatedAt,
+			Body:      p.Body,
+			Deleted:   p.Deleted,
+		}}, nil
+	}
+
+	thread, err := ThreadGet(client, msg.ThreadID)
+	if err != nil {
+		return nil, err
+	}
+	return threadNeighbors(thread, msg.MessageID), nil
+}
+
+// threadNeighbors flattens a thread tree into chronological order and
+// returns the entries on either side of messageID, without their replies.
+// Placeholders for vanished parents are skipped. Returns nil if messageID is
+// not in the thread.
+func threadNeighbors(thread *ThreadGetResponse, messageID string) *MessageThreadContext {
+	var flat []*ThreadNode
+	var walk func(nodes []*ThreadNode)
+	walk = func(nodes []*ThreadNode) {
+		for _, n := range nodes {
+			if !n.Placeholder {
+				flat = append(flat, n)
+			}
+			walk(n.Replies)
+		}
+	}
+	walk(thread.Roots)
+	sort.SliceStable(flat, func(i, j int) bool {
+		ti, _ := time.Parse(time.RFC3339Nano, flat[i].CreatedAt)
+		tj, _ := time.Parse(time.RFC3339Nano, flat[j].CreatedAt)
+		return ti.Before(tj)
+	})
+
+	leaf := func(n *ThreadNode) *ThreadNode {
+		c := *n
+		c.Replies = nil
+		return &c
+	}
+	for i, n := range flat {
+		if n.MessageID != messageID {
+			continue
+		}
+		tc := &MessageThreadContext{ThreadID: thread.ThreadID, Position: i + 1, Count: len(flat)}
+		if i > 0 {
+			tc.Previous = leaf(flat[i-1])
+		}
+		if i+1 < len(flat) {
+			tc.Next = leaf(flat[i+1])
+		}
+		return tc
+	}
+	return nil
+}
+
+// FormatMessageGetWithThread renders the message as FormatMessageGet does,
+// with the previous thread message above it and the next one below.
+func FormatMessageGetWithThread(resp *MessageGetResponse) string {
+	tc := resp.Thread
+	if tc == nil {
+		return FormatMessageGet(resp)
+	}
+	var out strings.Builder
+	if tc.ThreadID != "" {
+		fmt.Fprintf(&out, "Thread %s: message %d of %d\n\n", tc.ThreadID, tc.Position, tc.Count)
+	}
+	if tc.Previous != nil {
+		formatThreadNeighbor(&out, "↑", tc.Previous)
+		out.WriteString("\n")
+	}
+	out.WriteString(FormatMessageGet(resp))
+	if tc.Next != nil {
+		out.WriteString("\n")
+		formatThreadNeighbor(&out, "↓", tc.Next)
+	}
+	return out.String()
+}
+
+// formatThreadNeighbor prints one neighboring message in the same
+// header-plus-preview shape as thread show.
+func formatThreadNeighbor(out *strings.Builder, arrow string, n *ThreadNode) {
+	if n.Deleted {
+		fmt.Fprintf(out, "%s %s  %s  %s  (deleted)\n", arrow, n.MessageID, extractAgentName(n.AgentID), formatRelativeTime(n.CreatedAt))
+		return
+	}
+	fmt.Fprintf(out, "%s %s  %s  %s\n", arrow, n.MessageID, extractAgentName(n.AgentID), formatRelativeTime(n.CreatedAt))
+	fmt.Fprintf(out, "    %s\n", threadPreview(n.Body.Content))
+}
+
 // --- Message Edit ---
 
 // MessageEditResponse represents the response from message.edit RPC.
@@ -149,6 +312,171 @@ func FormatMessageEdit(resp *MessageEditResponse) string {
 	return fmt.Sprintf("✓ Message edited: %s (version %d)\n", resp.MessageID, resp.Version)
 }
 
+// --- Message History ---
+
+// MessageHistoryResponse represents the response from message.history RPC.
+type MessageHistoryResponse struct {
+	MessageID string           `json:"message_id"`
+	AgentID   string           `json:"agent_id"`
+	Deleted   bool             `json:"deleted"`
+	Versions  []MessageVersion `json:"versions"`
+}
+
+// MessageVersion is one revision of a message body; version 0 is the original.
+type MessageVersion struct {
+	Version    int    `json:"version"`
+	Content    string `json:"content"`
+	Structured string `json:"structured,omitempty"`
+	Timestamp  string `json:"timestamp"`
+	EditedBy   string `json:"edited_by,omitempty"`
+}
+
+// MessageHistory retrieves every version of a message, oldest first.
+func MessageHistory(client *Client, messageID string) (*MessageHistoryResponse, error) {
+	req := map[string]string{"message_id": messageID}
+	var resp MessageHistoryResponse
+	if err := client.Call("message.history", req, &resp); err != nil {
+		return nil, fmt.Errorf("message.history RPC failed: %w", err)
+	}
+	return &resp, nil
+}
+
+// FormatMessageHistory formats a message's versions for display, oldest
+// first, each body shown in full and indented beneath its header.
+func FormatMessageHistory(resp *MessageHistoryResponse) string {
+	var out strings.Builder
+	fmt.Fprintf(&out, "History: %s (%s", resp.MessageID, extractAgentName(resp.AgentID))
+	if resp.Deleted {
+		out.WriteString(", deleted")
+	}
+	out.WriteString(")\n")
+
+	for _, v := range resp.Versions {
+		label := "edited"
+		if v.Version == 0 {
+			label = "original"
+		}
+		fmt.Fprintf(&out, "\n  v%d  %s  %s (%s)\n", v.Version, label, formatRelativeTime(v.Timestamp), v.Timestamp)
+		for _, line := range strings.Split(v.Content, "\n") {
+			fmt.Fprintf(&out, "    %s\n", line)
+		}
+	}
+	return out.String()
+}
+
+// --- Message Readers ---
+
+// MessageReadersResponse represents the response from message.readers RPC.
+type MessageReadersResponse struct {
+	MessageID string          `json:"message_id"`
+	AgentID   string          `json:"agent_id"`
+	Read      []MessageReader `json:"read"`
+	Unread    []MessageReader `json:"unread"`
+}
+
+// MessageReader is one intended recipient of a message.
+type MessageReader struct {
+	AgentID string `json:"agent_id"`
+	Role    string `json:"role,omitempty"`
+	ReadAt  string `json:"read_at,omitempty"`
+}
+
+// MessageReaders retrieves which intended recipients have read a message.
+func MessageReaders(client *Client, messageID string) (*MessageReadersResponse, error) {
+	req := map[string]string{"message_id": messageID}
+	var resp MessageReadersResponse
+	if err := client.Call("message.readers", req, &resp); err != nil {
+		return nil, fmt.Errorf("message.readers RPC failed: %w", err)
+	}
+	return &resp, nil
+}
+
+// FormatMessageReaders formats read receipts for display: readers oldest
+// first with when they read, then the recipients still to read it.
+func FormatMessageReaders(resp *MessageReadersResponse) string {
+	var out strings.Builder
+	fmt.Fprintf(&out, "Readers: %s (%s) — %d of %d read\n",
+		resp.MessageID, extractAgentName(resp.AgentID), len(resp.Read), len(resp.Read)+len(resp.Unread))
+	if len(resp.Read)+len(resp.Unread) == 0 {
+		out.WriteString("  No recipients.\n")
+		return out.String()
+	}
+	for _, r := range resp.Read {
+		fmt.Fprintf(&out, "  ✓ %-24s read %s\n", extractAgentName(r.AgentID), formatRelativeTime(r.ReadAt))
+	}
+	for _, r := range resp.Unread {
+		fmt.Fprintf(&out, "  · %-24s not read\n", extractAgentName(r.AgentID))
+	}
+	return out.String()
+}
+
+// --- Message Forward ---
+
+// MessageForwardOptions selects the message to forward and its new audience.
+// To, Mentions, and Scopes use the same formats as SendOptions.
+type MessageForwardOptions struct {
+	MessageID     string
+	To            string   // Direct recipient (e.g., "@reviewer" or "@everyone")
+	Mentions      []string // Format: "@role"
+	Scopes        []string // Format: "type:value"
+	CallerAgentID string
+}
+
+// MessageForward re-sends a message's content to a new audience. The daemon
+// quotes the original under an attribution header and adds a forwarded_from
+// ref; the result has the same shape as a send.
+func MessageForward(client *Client, opts MessageForwardOptions) (*SendResult, error) {
+	scopes, err := parseScopes(opts.Scopes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid scope: %w", err)
+	}
+
+	params := map[string]any{"message_id": opts.MessageID}
+	if opts.To != "" {
+		to := opts.To
+		if !strings.HasPrefix(to, "@") {
+			to = "@" + to
+		}
+		params["to"] = to
+	}
+	if len(opts.Mentions) > 0 {
+		params["mentions"] = opts.Mentions
+	}
+	if len(scopes) > 0 {
+		params["scopes"] = scopes
+	}
+	if opts.CallerAgentID != "" {
+		params["caller_agent_id"] = opts.CallerAgentID
+	}
+
+	var result SendResult
+	if err := client.Call("message.forward", params, &result); err != nil {
+		return nil, fmt.Errorf("message.forward RPC failed: %w", err)
+	}
+	return &result, nil
+}
+
+// FormatMessageForward formats the forward response for display.
+func FormatMessageForward(sourceID string, result *SendResult) string {
+	var out strings.Builder
+	fmt.Fprintf(&out, "✓ Message forwarded: %s (from %s)\n", result.MessageID, sourceID)
+	if len(result.Audiences) > 0 {
+		parts := make([]string, len(result.Audiences))
+		for i, audience := range result.Audiences {
+			parts[i] = audience.Type + ":" + audience.Value
+		}
+		fmt.Fprintf(&out, "  To: %s\n", strings.Join(parts, ", "))
+	}
+	if len(result.Recipients) > 0 {
+		names := make([]string, len(result.Recipients))
+		for i, recipient := range result.Recipients {
+			names[i] = recipient.AgentID
+		}
+		fmt.Fprintf(&out, "  Recipients: %s\n", strings.Join(names, ", "))
+	}
+	return out.String()
+}
+
 // --- Message Delete ---
 
 // MessageDeleteResponse represents the response from message.delete RPC.
@@ -179,6 +507,404 @@ func FormatMessageDelete(resp *MessageDeleteResponse) string {
 	return fmt.Sprintf("✓ Message deleted: %s\n", resp.MessageID)
 }
 
+// --- Message Cancel ---
+
+// MessageCancelResponse represents the response from message.cancel RPC.
+type MessageCancelResponse struct {
+	MessageID  string `json:"message_id"`
+	DeliverAt  string `json:"deliver_at"`
+	CanceledAt string `json:"canceled_at"`
+}
+
+// MessageCancel withdraws a scheduled message (send --schedule) before it
+// is delivered. callerAgentID is passed as for MessageDelete.
+func MessageCancel(client *Client, messageID, callerAgentID string) (*MessageCancelResponse, error) {
+	req := map[string]string{"message_id": messageID}
+	if callerAgentID != "" {
+		req["caller_agent_id"] = callerAgentID
+	}
+	var resp MessageCancelResponse
+	if err := client.Call("message.cancel", req, &resp); err != nil {
+		return nil, fmt.Errorf("message.cancel RPC failed: %w", err)
+	}
+	return &resp, nil
+}
+
+// FormatMessageCancel formats the cancel response for display.
+func FormatMessageCancel(resp *MessageCancelResponse) string {
+	return fmt.Sprintf("✓ Scheduled message canceled: %s (was due %s)\n", resp.MessageID, resp.DeliverAt)
+}
+
+// --- Message React ---
+
+// MessageReactResponse represents the response from message.react RPC.
+type MessageReactResponse struct {
+	MessageID string              `json:"message_id"`
+	Emoji     string              `json:"emoji"`
+	Removed   bool                `json:"removed"`
+	Reactions map[string][]string `json:"reactions"`
+}
+
+// MessageReact toggles the caller's emoji reaction on a message.
+func MessageReact(client *Client, messageID, emoji, callerAgentID string) (*MessageReactResponse, error) {
+	req := map[string]string{"message_id": messageID, "emoji": emoji}
+	if callerAgentID != "" {
+		req["caller_agent_id"] = callerAgentID
+	}
+	var resp MessageReactResponse
+	if err := client.Call("message.react", req, &resp); err != nil {
+		return nil, fmt.Errorf("message.react RPC failed: %w", err)
+	}
+	return &resp, nil
+}
+
+// FormatMessageReact formats the react response for display.
+func FormatMessageReact(resp *MessageReactResponse) string {
+	verb := "added"
+	if resp.Removed {
+		verb = "removed"
+	}
+	out := fmt.Sprintf("✓ Reaction %s %s on %s\n", resp.Emoji, verb, resp.MessageID)
+	if len(resp.Reactions) > 0 {
+		out += fmt.Sprintf("  Reactions: %s\n", formatReactions(resp.Reactions))
+	}
+	return out
+}
+
+// formatReactions renders "emoji name, name · emoji name" with emoji in
+// sorted order so output is stable across calls.
+func formatReactions(reactions map[string][]string) string {
+	emojis := make([]string, 0, len(reactions))
+	for emoji := range reactions {
+		emojis = append(emojis, emoji)
+	}
+	sort.Strings(emojis)
+	parts := make([]string, 0, len(emojis))
+	for _, emoji := range emojis {
+		names := make([]string, len(reactions[emoji]))
+		for i, agent := range reactions[emoji] {
+			names[i] = extractAgentName(agent)
+		}
+		parts = append(parts, emoji+" "+strings.Join(names, ", "))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// --- Message Pin ---
+
+// MessagePinResponse represents the response from message.pin and
+// message.unpin RPCs.
+type MessagePinResponse struct {
+	MessageID string `json:"message_id"`
+	Pinned    bool   `json:"pinned"`
+	Changed   bool   `json:"changed"`
+}
+
+// MessagePin pins (pin=true) or unpins a message for the whole repo.
+func MessagePin(client *Client, messageID string, pin bool, callerAgentID string) (*MessagePinResponse, error) {
+	method := "message.pin"
+	if !pin {
+		method = "message.unpin"
+	}
+	req := map[string]string{"message_id": messageID}
+	if callerAgentID != "" {
+		req["caller_agent_id"] = callerAgentID
+	}
+	var resp MessagePinResponse
+	if err := client.Call(method, req, &resp); err != nil {
+		return nil, fmt.Errorf("%s RPC failed: %w", method, err)
+	}
+	return &resp, nil
+}
+
+// FormatMessagePin formats the pin/unpin response for display.
+func FormatMessagePin(resp *MessagePinResponse) string {
+	switch {
+	case resp.Pinned && resp.Changed:
+		return fmt.Sprintf("📌 Message pinned: %s\n", resp.MessageID)
+	case resp.Pinned:
+		return fmt.Sprintf("Message %s is already pinned\n", resp.MessageID)
+	case resp.Changed:
+		return fmt.Sprintf("✓ Message unpinned: %s\n", resp.MessageID)
+	default:
+		return fmt.Sprintf("Message %s was not pinned; nothing to do\n", resp.MessageID)
+	}
+}
+
+// --- Message Move ---
+
+// MessageMoveResponse represents the response from message.move RPC.
+type MessageMoveResponse struct {
+	MessageID        string `json:"message_id"`
+	ThreadID         string `json:"thread_id"`
+	PreviousThreadID string `json:"previous_thread_id,omitempty"`
+	Changed          bool   `json:"changed"`
+}
+
+// MessageMove reassigns one of the caller's messages to an existing thread.
+func MessageMove(client *Client, messageID, threadID, callerAgentID string) (*MessageMoveResponse, error) {
+	req := map[string]string{
+		"message_id": messageID,
+		"thread_id":  threadID,
+	}
+	if callerAgentID != "" {
+		req["caller_agent_id"] = callerAgentID
+	}
+	var resp MessageMoveResponse
+	if err := client.Call("message.move", req, &resp); err != nil {
+		return nil, fmt.Errorf("message.move RPC failed: %w", err)
+	}
+	return &resp, nil
+}
+
+// FormatMessageMove formats the move response for display.
+func FormatMessageMove(resp *MessageMoveResponse) string {
+	switch {
+	case !resp.Changed:
+		return fmt.Sprintf("Message %s is already in thread %s\n", resp.MessageID, resp.ThreadID)
+	case resp.PreviousThreadID == "":
+		return fmt.Sprintf("✓ Message %s moved into thread %s\n", resp.MessageID, resp.ThreadID)
+	default:
+		return fmt.Sprintf("✓ Message %s moved: %s → %s\n", resp.MessageID, resp.PreviousThreadID, resp.ThreadID)
+	}
+}
+
+// --- Message Bump ---
+
+// MessageBumpResponse represents the response from message.bump RPC.
+type MessageBumpResponse struct {
+	MessageID string `json:"message_id"`
+	BumpedAt  string `json:"bumped_at"`
+}
+
+// MessageBump re-notifies the recipients of one of the caller's messages.
+func MessageBump(client *Client, messageID, callerAgentID string) (*MessageBumpResponse, error) {
+	req := map[string]string{
+		"message_id": messageID,
+	}
+	if callerAgentID != "" {
+		req["caller_agent_id"] = callerAgentID
+	}
+	var resp MessageBumpResponse
+	if err := client.Call("message.bump", req, &resp); err != nil {
+		return nil, fmt.Errorf("message.bump RPC failed: %w", err)
+	}
+	return &resp, nil
+}
+
+// FormatMessageBump formats the bump response for display.
+func FormatMessageBump(resp *MessageBumpResponse) string {
+	return fmt.Sprintf("✓ Message %s bumped; recipients notified again\n", resp.MessageID)
+}
+
+// --- Message Assign / Complete ---
+
+// MessageAssignResponse represents the response from message.assign RPC.
+type MessageAssignResponse struct {
+	MessageID        string `json:"message_id"`
+	AssignmentID     string `json:"assignment_id"`
+	Assignee         string `json:"assignee"`
+	PreviousAssignee string `json:"previous_assignee,omitempty"`
+	Changed          bool   `json:"changed"`
+}
+
+// MessageAssign turns a message into a task for assignee, reassigning it if
+// someone else holds it.
+func MessageAssign(client *Client, messageID, assignee, callerAgentID string) (*MessageAssignResponse, error) {
+	req := map[string]string{
+		"message_id": messageID,
+		"assignee":   strings.TrimPrefix(assignee, "@"),
+	}
+	if callerAgentID != "" {
+		req["caller_agent_id"] = callerAgentID
+	}
+	var resp MessageAssignResponse
+	if err := client.Call("message.assign", req, &resp); err != nil {
+		return nil, fmt.Errorf("message.assign RPC failed: %w", err)
+	}
+	return &resp, nil
+}
+
+// FormatMessageAssign formats the assign response for display.
+func FormatMessageAssign(resp *MessageAssignResponse) string {
+	switch {
+	case !resp.Changed:
+		return fmt.Sprintf("Message %s is already assigned to @%s\n", resp.MessageID, resp.Assignee)
+	case resp.PreviousAssignee != "":
+		return fmt.Sprintf("✓ Message %s reassigned: @%s → @%s\n", resp.MessageID, resp.PreviousAssignee, resp.Assignee)
+	default:
+		return fmt.Sprintf("✓ Message %s assigned to @%s\n", resp.MessageID, resp.Assignee)
+	}
+}
+
+// MessageCompleteResponse represents the response from message.complete RPC.
+type MessageCompleteResponse struct {
+	MessageID      string `json:"message_id"`
+	AssignmentID   string `json:"assignment_id"`
+	AssignedBy     string `json:"assigned_by"`
+	NotificationID string `json:"notification_id,omitempty"`
+}
+
+// MessageComplete marks the caller's open assignment of a message done.
+func MessageComplete(client *Client, messageID, callerAgentID string) (*MessageCompleteResponse, error) {
+	req := map[string]string{"message_id": messageID}
+	if callerAgentID != "" {
+		req["caller_agent_id"] = callerAgentID
+	}
+	var resp MessageCompleteResponse
+	if err := client.Call("message.complete", req, &resp); err != nil {
+		return nil, fmt.Errorf("message.complete RPC failed: %w", err)
+	}
+	return &resp, nil
+}
+
+// FormatMessageComplete formats the complete response for display.
+func FormatMessageComplete(resp *MessageCompleteResponse) string {
+	if resp.NotificationID == "" {
+		return fmt.Sprintf("✓ Task %s completed\n", resp.MessageID)
+	}
+	return fmt.Sprintf("✓ Task %s completed; @%s notified\n", resp.MessageID, resp.AssignedBy)
+}
+
+// --- Message Attachment ---
+
+// AttachmentFetchResult describes an attachment written by
+// MessageAttachmentFetch.
+type AttachmentFetchResult struct {
+	MessageID string `json:"message_id"`
+	Name      string `json:"name"`
+	SHA256    string `json:"sha256"`
+	Size      int64  `json:"size"`
+	Output    string `json:"output"` // where the file was written
+}
+
+// MessageAttachmentFetch copies a message's attachment from the daemon's
+// sync worktree to output and checks the copy against the SHA-256 recorded
+// when it was sent. On a mismatch the copy is removed. An existing output
+// file is only replaced when overwrite is set.
+func MessageAttachmentFetch(client *Client, messageID, name, output string, overwrite bool) (*AttachmentFetchResult, error) {
+	req := map[string]string{"message_id": messageID, "name": name}
+	var loc struct {
+		Attachment
+		LocalPath string `json:"local_path"`
+	}
+	if err := client.Call("message.attachment", req, &loc); err != nil {
+		return nil, fmt.Errorf("message.attachment RPC failed: %w", err)
+	}
+	if output == "" {
+		output = loc.Name
+	}
+
+	in, err := os.Open(loc.LocalPath) // #nosec G304 -- path under the daemon's sync worktree
+	if err != nil {
+		return nil, fmt.Errorf("open attachment: %w", err)
+	}
+	defer func() { _ = in.Close() }()
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if overwrite {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	out, err := os.OpenFile(output, flags, 0o644) // #nosec G302 G304 -- user-chosen output path for a file they asked for
+	if errors.Is(err, os.ErrExist) {
+		return nil, fmt.Errorf("%s already exists (use --force to overwrite)", output)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("create %s: %w", output, err)
+	}
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(out, hash), in)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(output)
+		return nil, fmt.Errorf("write %s: %w", output, err)
+	}
+	if got := hex.EncodeToString(hash.Sum(nil)); got != loc.SHA256 {
+		_ = os.Remove(output)
+		return nil, fmt.Errorf("checksum mismatch for %s: got sha256 %s, message recorded %s; the synced copy is damaged", loc.Name, got, loc.SHA256)
+	}
+	return &AttachmentFetchResult{
+		MessageID: messageID,
+		Name:      loc.Name,
+		SHA256:    loc.SHA256,
+		Size:      size,
+		Output:    output,
+	}, nil
+}
+
+// FormatAttachmentFetch formats the fetch result for display.
+func FormatAttachmentFetch(res *AttachmentFetchResult) string {
+	return fmt.Sprintf("✓ %s → %s (%d bytes, sha256 %s verified)\n", res.Name, res.Output, res.Size, shortDigest(res.SHA256))
+}
+
+// shortDigest abbreviates a hex digest for display.
+func shortDigest(digest string) string {
+	if len(digest) > 12 {
+		return digest[:12]
+	}
+	return digest
+}
+
+// --- Message Search ---
+
+// MessageSearchOptions contains options for message.search.
+type MessageSearchOptions struct {
+	Query    string
+	AuthorID string // --author, without the leading @
+	Scope    string // Format: "type:value"
+	Limit    int
+}
+
+// MessageSearchResponse represents the response from message.search RPC.
+type MessageSearchResponse struct {
+	Messages []Message `json:"messages"`
+}
+
+// MessageSearch runs a full-text search over message bodies.
+func MessageSearch(client *Client, opts MessageSearchOptions) (*MessageSearchResponse, error) {
+	params := map[string]any{"query": opts.Query}
+	if opts.AuthorID != "" {
+		params["author_id"] = opts.AuthorID
+	}
+	if opts.Scope != "" {
+		parts := strings.SplitN(opts.Scope, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("scope must be in 'type:value' format, got: %s", opts.Scope)
+		}
+		params["scope"] = map[string]string{"type": parts[0], "value": parts[1]}
+	}
+	if opts.Limit > 0 {
+		params["limit"] = opts.Limit
+	}
+
+	var resp MessageSearchResponse
+	if err := client.Call("message.search", params, &resp); err != nil {
+		return nil, fmt.Errorf("message.search RPC failed: %w", err)
+	}
+	return &resp, nil
+}
+
+// FormatMessageSearch formats search hits for display, one header line and
+// one snippet line per message.
+func FormatMessageSearch(query string, resp *MessageSearchResponse) string {
+	if len(resp.Messages) == 0 {
+		return fmt.Sprintf("No messages matching %q\n", query)
+	}
+
+	var out strings.Builder
+	noun := "messages"
+	if len(resp.Messages) == 1 {
+		noun = "message"
+	}
+	fmt.Fprintf(&out, "Found %d %s matching %q:\n\n", len(resp.Messages), noun, query)
+	for _, msg := range resp.Messages {
+		fmt.Fprintf(&out, "  %s  %s  %s\n", msg.MessageID, extractAgentName(msg.AgentID), formatRelativeTime(msg.CreatedAt))
+		fmt.Fprintf(&out, "    %s\n", msg.Snippet)
+	}
+	return out.String()
+}
+
 // --- Message Mark Read ---
 
 // MarkReadResponse represents the response from message.markRead RPC.
@@ -221,6 +947,92 @@ func FormatMarkRead(resp *MarkReadResponse) string {
 	return fmt.Sprintf("✓ Marked %d messages as read\n", resp.MarkedCount)
 }
 
+// --- Message Import ---
+
+// ImportResponse represents the response from message.import RPC.
+type ImportResponse struct {
+	ImportedCount    int           `json:"imported_count"`
+	SkippedCount     int           `json:"skipped_count"`
+	OverwrittenCount int           `json:"overwritten_count,omitempty"`
+	TombstoneCount   int           `json:"tombstone_count,omitempty"`
+	DanglingRefs     []DanglingRef `json:"dangling_refs,omitempty"`
+}
+
+// DanglingRef is an imported reply_to/forwarded_from ref whose target
+// message is neither in the archive nor in the repo.
+type DanglingRef struct {
+	MessageID string `json:"message_id"`
+	RefType   string `json:"ref_type"`
+	RefValue  string `json:"ref_value"`
+}
+
+// ReadMessageArchive reads a JSONL message archive (the format written by
+// message.archive) and returns one raw record per non-blank line. Records are
+// passed to the daemon as-is; it validates the fields.
+func ReadMessageArchive(path string) ([]json.RawMessage, error) {
+	f, err := os.Open(path) // #nosec G304 -- path is the user-supplied import file
+	if err != nil {
+		return nil, fmt.Errorf("open import file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var records []json.RawMessage
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if !json.Valid([]byte(line)) {
+			return nil, fmt.Errorf("%s:%d: invalid JSON", path, lineNo)
+		}
+		records = append(records, json.RawMessage(line))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read import file: %w", err)
+	}
+	return records, nil
+}
+
+// MessageImport replays exported message records into the daemon. Records
+// whose message_id already exists are skipped unless force is set.
+func MessageImport(client *Client, records []json.RawMessage, force bool) (*ImportResponse, error) {
+	req := map[string]any{"records": records}
+	if force {
+		req["force"] = true
+	}
+	var resp ImportResponse
+	if err := client.Call("message.import", req, &resp); err != nil {
+		return nil, fmt.Errorf("message.import RPC failed: %w", err)
+	}
+	return &resp, nil
+}
+
+// FormatMessageImport formats the import response for display.
+func FormatMessageImport(resp *ImportResponse) string {
+	var out strings.Builder
+	fmt.Fprintf(&out, "✓ Imported %d message(s)\n", resp.ImportedCount)
+	if resp.OverwrittenCount > 0 {
+		fmt.Fprintf(&out, "  Overwritten: %d existing message(s)\n", resp.OverwrittenCount)
+	}
+	if resp.SkippedCount > 0 {
+		fmt.Fprintf(&out, "  Skipped: %d already present (use --force to overwrite)\n", resp.SkippedCount)
+	}
+	if resp.TombstoneCount > 0 {
+		fmt.Fprintf(&out, "  Skipped: %d deleted message(s) (tombstones)\n", resp.TombstoneCount)
+	}
+	if len(resp.DanglingRefs) > 0 {
+		fmt.Fprintf(&out, "  Dangling refs: %d (target not in archive or repo; kept as-is)\n", len(resp.DanglingRefs))
+		for _, ref := range resp.DanglingRefs {
+			fmt.Fprintf(&out, "    %s %s → %s\n", ref.MessageID, ref.RefType, ref.RefValue)
+		}
+	}
+	return out.String()
+}
+
 // --- Outbox / Sent items ---
 
 // OutboxResult contains sent messages for the current agent.
@@ -240,6 +1052,7 @@ type OutboxMessage struct {
 	AgentID    string            `json:"agent_id"`
 	Body       types.MessageBody `json:"body"`
 	CreatedAt  string            `json:"created_at"`
+	DeliverAt  string            `json:"deliver_at,omitempty"` // send --schedule messages only
 	Deleted    bool              `json:"deleted"`
 	Audiences  []Audience        `json:"audiences,omitempty"`
 	Recipients []RecipientState  `json:"recipients,omitempty"`
@@ -294,21 +1107,28 @@ func FormatOutbox(resp *OutboxResult) string {
 		}
 		readCount := msg.ReadCount
 		totalRecipients := len(msg.Recipients)
+		when := formatRelativeTime(msg.CreatedAt)
+		pending := isPendingDelivery(msg.DeliverAt)
+		if pending {
+			when = "scheduled for " + msg.DeliverAt
+		}
 		fmt.Fprintf(&out, "%s  %s  to %s  %d/%d read\n",
 			msg.MessageID,
-			formatRelativeTime(msg.CreatedAt),
+			when,
 			strings.Join(audienceParts, ", "),
 			readCount,
 			totalRecipients,
 		)
 		if msg.Body.Content != "" {
-			fmt.Fprintf(&out, "  %s\n", msg.Body.Content)
+			fmt.Fprintf(&out, "  %s\n", Plain(msg.Body.Content))
 		}
 		if totalRecipients > 0 {
 			names := make([]string, len(msg.Recipients))
 			for i, recipient := range msg.Recipients {
 				status := "delivered"
 				switch {
+				case pending:
+					status = "scheduled"
 				case recipient.ReadAt != "":
 					status = "read"
 				case recipient.SeenAt != "":
@@ -322,6 +1142,13 @@ func FormatOutbox(resp *OutboxResult) string {
 	return out.String()
 }
 
+// isPendingDelivery reports whether a send --schedule message is still
+// waiting for its deliver_at.
+func isPendingDelivery(deliverAt string) bool {
+	t, err := time.Parse(time.RFC3339, deliverAt)
+	return err == nil && t.After(time.Now())
+}
+
 // rejectSystemReply returns an error if the parent message was sent by @system.
 func rejectSystemReply(parent MessageDetail) error {
 	if parent.Author.AgentID == "system" {
@@ -330,34 +1157,144 @@ func rejectSystemReply(parent MessageDetail) error {
 	return nil
 }
 
+// --- Quote ---
+
+// Quoted parents are cut to this many lines, or this many runes, whichever
+// comes first, so a long message doesn't drown out the reply.
+const (
+	quoteMaxLines = 5
+	quoteMaxRunes = 500
+)
+
+// QuoteMessage renders the start of parent's content for quoting in a reply.
+// Markdown parents become a blockquote; plain parents, whose readers would
+// see a literal "> ", are indented under an "@author wrote:" line instead.
+// Content past quoteMaxLines or quoteMaxRunes is replaced by an ellipsis.
+func QuoteMessage(parent MessageDetail) string {
+	lines := strings.Split(strings.TrimSpace(parent.Body.Content), "\n")
+	truncated := false
+	if len(lines) > quoteMaxLines {
+		lines = lines[:quoteMaxLines]
+		truncated = true
+	}
+	budget := quoteMaxRunes
+	for i, line := range lines {
+		runes := []rune(line)
+		if len(runes) > budget {
+			lines[i] = string(runes[:budget])
+			lines = lines[:i+1]
+			truncated = true
+			break
+		}
+		budget -= len(runes)
+	}
+	if truncated {
+		lines[len(lines)-1] = strings.TrimRight(lines[len(lines)-1], " ") + "…"
+	}
+
+	var out strings.Builder
+	if parent.Body.Format == "plain" {
+		fmt.Fprintf(&out, "@%s wrote:\n", strings.TrimPrefix(parent.Author.AgentID, "@"))
+		for i, line := range lines {
+			if i > 0 {
+				out.WriteString("\n")
+			}
+			out.WriteString("  " + line)
+		}
+		return out.String()
+	}
+	for i, line := range lines {
+		if i > 0 {
+			out.WriteString("\n")
+		}
+		out.WriteString(strings.TrimRight("> "+line, " "))
+	}
+	return out.String()
+}
+
 // --- Reply ---
 
+// replyMentions is the mention list for a reply. By default it copies the
+// parent's audience: its mention refs, its author (unless that is the
+// caller) and its group scopes as @group mentions. When opts.To or
+// opts.Mentions is set the parent's audience is dropped and opts.Mentions
+// used instead. opts.AddMentions is unioned onto the result either way,
+// skipping entries already present with or without the leading @.
+func replyMentions(parent MessageDetail, opts ReplyOptions) []string {
+	var mentions []string
+	if opts.To != "" || len(opts.Mentions) > 0 {
+		mentions = append(mentions, opts.Mentions...)
+	} else {
+		for _, ref := range parent.Refs {
+			if ref.Type == "mention" {
+				mentions = append(mentions, ref.Value)
+			}
+		}
+		// Route the reply back to the original sender.
+		if senderID := parent.Author.AgentID; senderID != "" && senderID != opts.CallerAgentID && !slices.Contains(mentions, senderID) {
+			mentions = append(mentions, senderID)
+		}
+		for _, scope := range parent.Scopes {
+			if scope.Type == "group" {
+				mentions = append(mentions, "@"+scope.Value)
+			}
+		}
+	}
+
+	present := make(map[string]bool, len(mentions))
+	for _, m := range mentions {
+		present[strings.TrimPrefix(m, "@")] = true
+	}
+	for _, m := range opts.AddMentions {
+		if key := strings.TrimPrefix(m, "@"); !present[key] {
+			present[key] = true
+			mentions = append(mentions, m)
+		}
+	}
+	return mentions
+}
+
 // ReplyOptions contains options for the reply command.
 type ReplyOptions struct {
 	MessageID     string
 	Content       string
 	Format        string
 	CallerAgentID string // Caller's resolved agent ID (for worktree identity)
+	Quote         bool   // Prefix Content with the parent's content (thrum message quote)
+	NoThread      bool   // Keep a reply to a message outside any thread flat (reply --no-thread)
+
+	// To and Mentions replace the parent's audience; AddMentions extends
+	// whichever audience applies (reply --to / --mention / --add-mention).
+	To          string
+	Mentions    []string
+	AddMentions []string
 }
 
 // Reply sends a reply to a message.
 // It fetches the parent message to copy its audience (mentions/scopes) and sets reply_to ref.
+// With opts.Quote the parent's content is quoted above the reply (see QuoteMessage).
+// opts.To and opts.Mentions override the copied audience (see replyMentions).
 func Reply(client *Client, opts ReplyOptions) (*SendResult, error) {
 	// Get the parent message to extract its audience
-	parentResp, err := MessageGet(client, opts.MessageID)
+	parent, err := replyParent(client, opts.MessageID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get parent message: %w", err)
+		return nil, err
 	}
-	parent := parentResp.Message
 
-	if err := rejectSystemReply(parent); err != nil {
-		return nil, err
+	content := opts.Content
+	if opts.Quote {
+		if parent.Deleted {
+			return nil, fmt.Errorf("cannot quote deleted message %s", parent.MessageID)
+		}
+		content = QuoteMessage(parent) + "\n\n" + content
 	}
 
 	// Build send options with reply_to ref
 	sendOpts := SendOptions{
-		Content:       opts.Content,
+		Content:       content,
 		ReplyTo:       opts.MessageID,
+		NoThread:      opts.NoThread,
+		To:            opts.To,
 		CallerAgentID: opts.CallerAgentID,
 	}
 
@@ -365,41 +1302,39 @@ func Reply(client *Client, opts ReplyOptions) (*SendResult, error) {
 		sendOpts.Format = opts.Format
 	}
 
-	// Copy audience from parent message:
-	// 1. Extract mentions from parent's refs (mention:agent_id refs)
-	var mentions []string
-	for _, ref := range parent.Refs {
-		if ref.Type == "mention" {
-			mentions = append(mentions, ref.Value)
-		}
+	if mentions := replyMentions(parent, opts); len(mentions) > 0 {
+		sendOpts.Mentions = mentions
 	}
 
-	// 2. Add original sender so the reply routes back to them
-	senderID := parent.Author.AgentID
-	if senderID != "" && senderID != opts.CallerAgentID {
-		alreadyMentioned := false
-		for _, m := range mentions {
-			if m == senderID {
-				alreadyMentioned = true
-				break
-			}
-		}
-		if !alreadyMentioned {
-			mentions = append(mentions, senderID)
-		}
-	}
+	return Send(client, sendOpts)
+}
 
-	// 3. Add group scopes as mentions
-	for _, scope := range parent.Scopes {
-		if scope.Type == "group" {
-			mentions = append(mentions, "@"+scope.Value)
-		}
+// replyParent fetches the message being replied to and refuses system
+// messages. Reply and send --reply-to share it so both fail the same way.
+func replyParent(client *Client, messageID string) (MessageDetail, error) {
+	parentResp, err := MessageGet(client, messageID)
+	if err != nil {
+		return MessageDetail{}, fmt.Errorf("failed to get parent message: %w", err)
 	}
-
-	// Set mentions if we found any
-	if len(mentions) > 0 {
-		sendOpts.Mentions = mentions
+	if err := rejectSystemReply(parentResp.Message); err != nil {
+		return MessageDetail{}, err
 	}
+	return parentResp.Message, nil
+}
 
-	return Send(client, sendOpts)
+// PrepareReplyTo checks opts.ReplyTo the way Reply checks its parent
+// (thrum send --reply-to). With inheritAudience the parent's audience,
+// as Reply would copy it, is added to opts.Mentions.
+func PrepareReplyTo(client *Client, opts SendOptions, inheritAudience bool) (SendOptions, error) {
+	parent, err := replyParent(client, opts.ReplyTo)
+	if err != nil {
+		return opts, err
+	}
+	if inheritAudience {
+		opts.Mentions = replyMentions(parent, ReplyOptions{
+			CallerAgentID: opts.CallerAgentID,
+			AddMentions:   opts.Mentions,
+		})
+	}
+	return opts, nil
 }
@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MessageTail returns the n most recent messages matching opts, oldest
+// first, for `thrum message tail`. Messages are not marked read.
+func MessageTail(client *Client, opts InboxOptions, n int) ([]Message, error) {
+	opts.PageSize = n
+	opts.Page = 1
+	opts.Chronological = false
+	opts.PrioritySort = false
+	opts.BumpSort = false
+	result, err := Inbox(client, opts)
+	if err != nil {
+		return nil, err
+	}
+	msgs := result.Messages
+	for i, j := 0, len(msgs)-1; i < j; i, j = i+1, j-1 {
+		msgs[i], msgs[j] = msgs[j], msgs[i]
+	}
+	return msgs, nil
+}
+
+// TailSince is where `thrum message tail --follow` resumes after the
+// initial messages: the newest one's created_at, or start when none were
+// shown (or the newest is unparseable).
+func TailSince(msgs []Message, start time.Time) time.Time {
+	if len(msgs) == 0 {
+		return start
+	}
+	t, err := time.Parse(time.RFC3339Nano, msgs[len(msgs)-1].CreatedAt)
+	if err != nil {
+		return start
+	}
+	return t
+}
+
+// FormatTailLine renders one message as a log line for `thrum message
+// tail`: local time, author, ID and body. Continuation lines of a
+// multi-line body are indented so each message stays one visual block.
+func FormatTailLine(msg Message) string {
+	stamp := msg.CreatedAt
+	if t, err := time.Parse(time.RFC3339Nano, msg.CreatedAt); err == nil {
+		stamp = t.Local().Format("15:04:05")
+	}
+	header := fmt.Sprintf("%s  %s  %s", stamp, extractAgentName(msg.AgentID), msg.MessageID)
+	if msg.ReplyTo != "" {
+		header += " ↳ " + msg.ReplyTo
+	}
+	if msg.Priority == "high" {
+		header += " [high]"
+	}
+	body := strings.TrimRight(Plain(msg.Body.Content), "\n")
+	return header + "\n    " + strings.ReplaceAll(body, "\n", "\n    ") + "\n"
+}
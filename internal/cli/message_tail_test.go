@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMessageTail(t *testing.T) {
+	daemon, socketPath := newMockDaemon(t)
+	defer daemon.stop()
+
+	var params map[string]any
+	daemon.start(t, func(conn net.Conn) {
+		defer func() { _ = conn.Close() }()
+		var request map[string]any
+		if err := json.NewDecoder(conn).Decode(&request); err != nil {
+			return
+		}
+		params, _ = request["params"].(map[string]any)
+		// The daemon lists newest first.
+		_ = json.NewEncoder(conn).Encode(map[string]any{
+			"jsonrpc": "2.0",
+			"id":      request["id"],
+			"result": map[string]any{
+				"messages": []map[string]any{
+					{"message_id": "msg_3", "agent_id": "ops", "created_at": "2026-05-01T12:00:03Z"},
+					{"message_id": "msg_2", "agent_id": "ops", "created_at": "2026-05-01T12:00:02Z"},
+				},
+				"total": 2, "page": 1, "page_size": 2, "total_pages": 1,
+			},
+		})
+	})
+	<-daemon.Ready()
+
+	client, err := NewClient(socketPath)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	msgs, err := MessageTail(client, InboxOptions{ForAgent: "alice", Chronological: true}, 2)
+	if err != nil {
+		t.Fatalf("MessageTail: %v", err)
+	}
+	if len(msgs) != 2 || msgs[0].MessageID != "msg_2" || msgs[1].MessageID != "msg_3" {
+		t.Fatalf("messages = %+v, want oldest first", msgs)
+	}
+	if params["page_size"] != float64(2) || params["for_agent"] != "alice" {
+		t.Errorf("params = %v, want page_size 2 and the for_agent filter", params)
+	}
+	if _, present := params["chronological"]; present {
+		t.Error("tail must ask for the newest messages, not the chronological view")
+	}
+
+	start := time.Date(2026, 5, 1, 13, 0, 0, 0, time.UTC)
+	if got := TailSince(msgs, start); !got.Equal(time.Date(2026, 5, 1, 12, 0, 3, 0, time.UTC)) {
+		t.Errorf("TailSince = %v, want the newest message's created_at", got)
+	}
+	if got := TailSince(nil, start); !got.Equal(start) {
+		t.Errorf("TailSince(nil) = %v, want start", got)
+	}
+}
+
+func TestFormatTailLine(t *testing.T) {
+	msg := Message{MessageID: "msg_1", AgentID: "ops", ReplyTo: "msg_0", CreatedAt: "not a time", Priority: "high"}
+	msg.Body.Content = "first line\nsecond line\n"
+	want := "not a time  @ops  msg_1 ↳ msg_0 [high]\n    first line\n    second line\n"
+	if got := FormatTailLine(msg); got != want {
+		t.Errorf("FormatTailLine =\n%q\nwant\n%q", got, want)
+	}
+
+	msg = Message{MessageID: "msg_2", AgentID: "ops", CreatedAt: time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC).Format(time.RFC3339)}
+	msg.Body.Content = "hi"
+	if got := FormatTailLine(msg); !strings.HasSuffix(got, "  @ops  msg_2\n    hi\n") {
+		t.Errorf("FormatTailLine = %q", got)
+	}
+}
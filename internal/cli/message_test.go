@@ -1,8 +1,13 @@
 package cli
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"net"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -134,6 +139,45 @@ func TestFormatMessageGet_Deleted(t *testing.T) {
 	}
 }
 
+func TestFormatMessageGet_Reactions(t *testing.T) {
+	resp := &MessageGetResponse{
+		Message: MessageDetail{
+			MessageID: "msg_reacted",
+			Author:    AuthorInfo{AgentID: "agent:test:123"},
+			Body:      types.MessageBody{Content: "ship it"},
+			CreatedAt: time.Now().Format(time.RFC3339),
+			Reactions: map[string][]string{
+				":thumbsup:": {"agent:ops:AAA", "agent:reviewer:BBB"},
+				":eyes:":     {"agent:ops:AAA"},
+			},
+		},
+	}
+
+	output := FormatMessageGet(resp)
+	want := "  Reactions: :eyes: @ops · :thumbsup: @ops, @reviewer\n"
+	if !strings.Contains(output, want) {
+		t.Errorf("Output should contain %q, got:\n%s", want, output)
+	}
+}
+
+func TestFormatMessageGet_Tags(t *testing.T) {
+	resp := &MessageGetResponse{
+		Message: MessageDetail{
+			MessageID: "msg_tagged",
+			Author:    AuthorInfo{AgentID: "agent:test:123"},
+			Body:      types.MessageBody{Content: "use sqlite"},
+			CreatedAt: time.Now().Format(time.RFC3339),
+			Tags:      []string{"db", "decision"},
+		},
+	}
+
+	output := FormatMessageGet(resp)
+	want := "  Tags:    db, decision\n"
+	if !strings.Contains(output, want) {
+		t.Errorf("Output should contain %q, got:\n%s", want, output)
+	}
+}
+
 func TestFormatMessageGet_Edited(t *testing.T) {
 	resp := &MessageGetResponse{
 		Message: MessageDetail{
@@ -151,6 +195,174 @@ func TestFormatMessageGet_Edited(t *testing.T) {
 	}
 }
 
+func TestFormatMessageGet_Attachments(t *testing.T) {
+	digest := strings.Repeat("ab", 32)
+	resp := &MessageGetResponse{
+		Message: MessageDetail{
+			MessageID: "msg_att",
+			Author:    AuthorInfo{AgentID: "agent:test:123"},
+			Body:      types.MessageBody{Content: "log attached"},
+			CreatedAt: time.Now().Format(time.RFC3339),
+			Refs: []types.Ref{
+				{Type: "reply_to", Value: "msg_parent"},
+				{Type: "attachment", Value: "attachments/msg_att/build.log:" + digest},
+			},
+			Attachments: []Attachment{{Name: "build.log", Path: "attachments/msg_att/build.log", SHA256: digest}},
+		},
+	}
+
+	output := FormatMessageGet(resp)
+	for _, want := range []string{"  Refs:    reply_to:msg_parent\n", "    - build.log (sha256 abababababab)\n", "thrum message attachment fetch msg_att NAME"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Output should contain %q, got:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "attachment:") {
+		t.Errorf("attachment refs should only be listed under Attachments, got:\n%s", output)
+	}
+}
+
+func TestThreadNeighbors(t *testing.T) {
+	node := func(id, at string, replies ...*ThreadNode) *ThreadNode {
+		return &ThreadNode{MessageID: id, AgentID: "alice", CreatedAt: at, Body: types.MessageBody{Content: "body " + id}, Replies: replies}
+	}
+	// Tree order (root, a, b, c) differs from time order (root, b, a, c);
+	// the placeholder stands in for a vanished parent and is skipped.
+	thread := &ThreadGetResponse{ThreadID: "thr_1", Roots: []*ThreadNode{
+		node("root", "2026-05-01T10:00:00Z",
+			node("a", "2026-05-01T10:02:00Z", node("c", "2026-05-01T10:03:00Z")),
+			node("b", "2026-05-01T10:01:00Z")),
+		{MessageID: "gone", Placeholder: true},
+	}}
+
+	tc := threadNeighbors(thread, "a")
+	if tc == nil || tc.Position != 3 || tc.Count != 4 || tc.Previous.MessageID != "b" || tc.Next.MessageID != "c" {
+		t.Fatalf("neighbors of a = %+v", tc)
+	}
+	if tc.Previous.Replies != nil || tc.Next.Replies != nil {
+		t.Error("neighbors should not carry their replies")
+	}
+	if tc := threadNeighbors(thread, "root"); tc.Previous != nil || tc.Next.MessageID != "b" {
+		t.Errorf("neighbors of root = %+v", tc)
+	}
+	if tc := threadNeighbors(thread, "c"); tc.Next != nil || tc.Previous.MessageID != "a" {
+		t.Errorf("neighbors of c = %+v", tc)
+	}
+	if tc := threadNeighbors(thread, "msg_elsewhere"); tc != nil {
+		t.Errorf("message outside the thread = %+v, want nil", tc)
+	}
+}
+
+func TestMessageThreadNeighbors_NoThread(t *testing.T) {
+	// Nothing to look up, so the client is never used.
+	tc, err := MessageThreadNeighbors(nil, &MessageDetail{MessageID: "msg_solo"})
+	if err != nil || tc != nil {
+		t.Errorf("MessageThreadNeighbors = %+v, %v; want nil, nil", tc, err)
+	}
+}
+
+func TestFormatMessageGetWithThread(t *testing.T) {
+	resp := &MessageGetResponse{Message: MessageDetail{
+		MessageID: "msg_b",
+		ThreadID:  "thr_1",
+		Author:    AuthorInfo{AgentID: "bob"},
+		Body:      types.MessageBody{Content: "the reply"},
+		CreatedAt: "2026-05-01T10:01:00Z",
+	}}
+	if got := FormatMessageGetWithThread(resp); got != FormatMessageGet(resp) {
+		t.Errorf("without thread context should match FormatMessageGet:\n%s", got)
+	}
+
+	resp.Thread = &MessageThreadContext{
+		ThreadID: "thr_1", Position: 2, Count: 3,
+		Previous: &ThreadNode{MessageID: "msg_a", AgentID: "alice", Body: types.MessageBody{Content: "the question"}},
+		Next:     &ThreadNode{MessageID: "msg_c", AgentID: "carol", Deleted: true},
+	}
+	got := FormatMessageGetWithThread(resp)
+	order := []string{"Thread thr_1: message 2 of 3", "↑ msg_a  @alice", "the question", "Message: msg_b", "the reply", "↓ msg_c  @carol", "(deleted)"}
+	pos := 0
+	for _, want := range order {
+		i := strings.Index(got[pos:], want)
+		if i < 0 {
+			t.Fatalf("missing %q after offset %d in:\n%s", want, pos, got)
+		}
+		pos += i + len(want)
+	}
+}
+
+func TestMessageAttachmentFetch(t *testing.T) {
+	content := []byte("step 3 failed\n")
+	sum := sha256.Sum256(content)
+	src := filepath.Join(t.TempDir(), "build.log")
+	if err := os.WriteFile(src, content, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	fetch := func(t *testing.T, digest, output string, overwrite bool) (*AttachmentFetchResult, error) {
+		t.Helper()
+		daemon, socketPath := newMockDaemon(t)
+		defer daemon.stop()
+		daemon.start(t, func(conn net.Conn) {
+			defer func() { _ = conn.Close() }()
+			var request map[string]any
+			if err := json.NewDecoder(conn).Decode(&request); err != nil {
+				return
+			}
+			if request["method"] != "message.attachment" {
+				t.Errorf("Expected method 'message.attachment', got %v", request["method"])
+			}
+			_ = json.NewEncoder(conn).Encode(map[string]any{
+				"jsonrpc": "2.0",
+				"id":      request["id"],
+				"result": map[string]any{
+					"message_id": "msg_att",
+					"name":       "build.log",
+					"path":       "attachments/msg_att/build.log",
+					"sha256":     digest,
+					"local_path": src,
+					"size":       len(content),
+				},
+			})
+		})
+		<-daemon.Ready()
+		client, err := NewClient(socketPath)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		defer func() { _ = client.Close() }()
+		return MessageAttachmentFetch(client, "msg_att", "build.log", output, overwrite)
+	}
+
+	t.Run("verified_copy", func(t *testing.T) {
+		out := filepath.Join(t.TempDir(), "ci.log")
+		res, err := fetch(t, hex.EncodeToString(sum[:]), out, false)
+		if err != nil {
+			t.Fatalf("MessageAttachmentFetch: %v", err)
+		}
+		got, _ := os.ReadFile(out) //nolint:gosec // test path
+		if string(got) != string(content) || res.Size != int64(len(content)) || res.Output != out {
+			t.Errorf("result = %+v with %q; want a full copy at %s", res, got, out)
+		}
+		if _, err := fetch(t, hex.EncodeToString(sum[:]), out, false); err == nil || !strings.Contains(err.Error(), "--force") {
+			t.Errorf("second fetch err = %v, want refusal to overwrite", err)
+		}
+		if _, err := fetch(t, hex.EncodeToString(sum[:]), out, true); err != nil {
+			t.Errorf("fetch with overwrite: %v", err)
+		}
+	})
+
+	t.Run("checksum_mismatch_removes_copy", func(t *testing.T) {
+		out := filepath.Join(t.TempDir(), "ci.log")
+		_, err := fetch(t, strings.Repeat("00", 32), out, false)
+		if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+			t.Fatalf("err = %v, want checksum mismatch", err)
+		}
+		if _, statErr := os.Stat(out); !os.IsNotExist(statErr) {
+			t.Errorf("mismatched copy left at %s", out)
+		}
+	})
+}
+
 func TestFormatMessageEdit(t *testing.T) {
 	resp := &MessageEditResponse{
 		MessageID: "msg_01HXE8Z7",
@@ -170,6 +382,80 @@ func TestFormatMessageEdit(t *testing.T) {
 	}
 }
 
+func TestFormatMessageHistory(t *testing.T) {
+	resp := &MessageHistoryResponse{
+		MessageID: "msg_01HXE8Z7",
+		AgentID:   "planner",
+		Versions: []MessageVersion{
+			{Version: 0, Content: "first draft\nsecond line", Timestamp: "2026-02-03T10:00:00Z"},
+			{Version: 1, Content: "revised", Timestamp: "2026-02-03T10:05:00Z", EditedBy: "ses_1"},
+		},
+	}
+
+	output := FormatMessageHistory(resp)
+	for _, want := range []string{
+		"History: msg_01HXE8Z7 (@planner)",
+		"v0  original",
+		"    first draft\n    second line\n",
+		"v1  edited",
+		"(2026-02-03T10:05:00Z)",
+		"    revised\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+	if strings.Index(output, "v0") > strings.Index(output, "v1") {
+		t.Error("versions should be listed oldest first")
+	}
+}
+
+func TestFormatMessageReaders(t *testing.T) {
+	resp := &MessageReadersResponse{
+		MessageID: "msg_01HXE8Z7",
+		AgentID:   "planner",
+		Read:      []MessageReader{{AgentID: "reviewer", Role: "reviewer", ReadAt: "2026-02-03T10:05:00Z"}},
+		Unread:    []MessageReader{{AgentID: "tester", Role: "tester"}},
+	}
+
+	output := FormatMessageReaders(resp)
+	for _, want := range []string{
+		"Readers: msg_01HXE8Z7 (@planner) — 1 of 2 read",
+		"✓ @reviewer",
+		"· @tester",
+		"not read",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+	if strings.Index(output, "@reviewer") > strings.Index(output, "@tester") {
+		t.Error("readers should be listed before unread recipients")
+	}
+
+	empty := FormatMessageReaders(&MessageReadersResponse{MessageID: "msg_X", AgentID: "planner"})
+	if !strings.Contains(empty, "No recipients.") {
+		t.Errorf("empty output = %q, want 'No recipients.'", empty)
+	}
+}
+
+func TestFormatMessageForward(t *testing.T) {
+	output := FormatMessageForward("msg_SRC", &SendResult{
+		MessageID:  "msg_FWD",
+		Audiences:  []Audience{{Type: "role", Value: "reviewer"}},
+		Recipients: []RecipientState{{AgentID: "reviewer_01"}},
+	})
+	for _, want := range []string{
+		"✓ Message forwarded: msg_FWD (from msg_SRC)\n",
+		"  To: role:reviewer\n",
+		"  Recipients: reviewer_01\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+}
+
 func TestFormatMessageDelete(t *testing.T) {
 	resp := &MessageDeleteResponse{
 		MessageID: "msg_01HXE8Z7",
@@ -185,6 +471,22 @@ func TestFormatMessageDelete(t *testing.T) {
 	}
 }
 
+func TestFormatOutbox_Scheduled(t *testing.T) {
+	deliverAt := time.Now().UTC().Add(2 * time.Hour).Format(time.RFC3339)
+	output := FormatOutbox(&OutboxResult{Messages: []OutboxMessage{{
+		MessageID:  "msg_later",
+		CreatedAt:  deliverAt,
+		DeliverAt:  deliverAt,
+		Recipients: []RecipientState{{AgentID: "ops", DeliveredAt: deliverAt}},
+	}}})
+	if !strings.Contains(output, "scheduled for "+deliverAt) {
+		t.Errorf("pending message not shown as scheduled:\n%s", output)
+	}
+	if !strings.Contains(output, "ops(scheduled)") {
+		t.Errorf("pending recipient not shown as scheduled:\n%s", output)
+	}
+}
+
 func TestFormatMarkRead(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -213,6 +515,38 @@ func TestFormatMarkRead(t *testing.T) {
 	}
 }
 
+func TestFormatMessageImport(t *testing.T) {
+	tests := []struct {
+		name     string
+		resp     *ImportResponse
+		contains []string
+	}{
+		{
+			name:     "imported and skipped",
+			resp:     &ImportResponse{ImportedCount: 12, SkippedCount: 3},
+			contains: []string{"Imported 12 message(s)", "Skipped: 3 already present"},
+		},
+		{
+			name: "dangling refs",
+			resp: &ImportResponse{ImportedCount: 2, DanglingRefs: []DanglingRef{
+				{MessageID: "msg_2", RefType: "reply_to", RefValue: "msg_gone"},
+			}},
+			contains: []string{"Dangling refs: 1", "msg_2 reply_to → msg_gone"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := FormatMessageImport(tt.resp)
+			for _, want := range tt.contains {
+				if !strings.Contains(output, want) {
+					t.Errorf("Output should contain %q, got %q", want, output)
+				}
+			}
+		})
+	}
+}
+
 func TestMessageOutbox(t *testing.T) {
 	mockResponse := map[string]any{
 		"messages":    []map[string]any{},
@@ -634,6 +968,54 @@ func TestReplyGroupScope(t *testing.T) {
 	}
 }
 
+func TestPrepareReplyTo(t *testing.T) {
+	parent := map[string]any{
+		"message": map[string]any{
+			"message_id": "msg_parent_01",
+			"author":     map[string]string{"agent_id": "coordinator", "session_id": "ses_01"},
+			"body":       map[string]any{"format": "markdown", "content": "review this"},
+			"scopes":     []map[string]string{{"type": "group", "value": "backend"}},
+			"refs":       []map[string]string{{"type": "mention", "value": "implementer"}},
+			"created_at": "2026-02-03T10:00:00Z",
+		},
+	}
+	get := mockMonitorHandler{
+		method: "message.get",
+		validateParams: func(t *testing.T, params map[string]any) {
+			if params["message_id"] != "msg_parent_01" {
+				t.Errorf("message_id = %v, want msg_parent_01", params["message_id"])
+			}
+		},
+		response: parent,
+	}
+
+	opts := SendOptions{ReplyTo: "msg_parent_01", Mentions: []string{"@reviewer"}, CallerAgentID: "implementer"}
+
+	got, err := PrepareReplyTo(setupMonitorDaemon(t, get), opts, false)
+	if err != nil {
+		t.Fatalf("PrepareReplyTo() error = %v", err)
+	}
+	if !slices.Equal(got.Mentions, []string{"@reviewer"}) {
+		t.Errorf("without inherit, Mentions = %v, want unchanged", got.Mentions)
+	}
+
+	got, err = PrepareReplyTo(setupMonitorDaemon(t, get), opts, true)
+	if err != nil {
+		t.Fatalf("PrepareReplyTo() error = %v", err)
+	}
+	want := []string{"implementer", "coordinator", "@backend", "@reviewer"}
+	if !slices.Equal(got.Mentions, want) {
+		t.Errorf("with inherit, Mentions = %v, want %v", got.Mentions, want)
+	}
+
+	system := mockMonitorHandler{method: "message.get", response: map[string]any{
+		"message": map[string]any{"message_id": "msg_sys", "author": map[string]string{"agent_id": "system"}},
+	}}
+	if _, err := PrepareReplyTo(setupMonitorDaemon(t, system), SendOptions{ReplyTo: "msg_sys"}, false); err == nil {
+		t.Error("PrepareReplyTo() to a system message should fail like Reply")
+	}
+}
+
 func TestRejectSystemReply(t *testing.T) {
 	sysMsg := MessageDetail{
 		MessageID: "msg_sys",
@@ -852,3 +1234,84 @@ func TestMarkSkipped(t *testing.T) {
 		t.Fatalf("expected MarkedCount=1 from wire, got %d", resp.MarkedCount)
 	}
 }
+
+func TestFormatMessageSearch(t *testing.T) {
+	resp := &MessageSearchResponse{
+		Messages: []Message{{
+			MessageID: "msg_01HXE8Z7",
+			AgentID:   "agent:planner:ABC123",
+			CreatedAt: time.Now().Add(-2 * time.Hour).Format(time.RFC3339),
+			Snippet:   "…found a memory leak in the sync loop…",
+		}},
+	}
+
+	output := FormatMessageSearch("memory leak", resp)
+	for _, expected := range []string{
+		`Found 1 message matching "memory leak"`,
+		"msg_01HXE8Z7",
+		"@planner",
+		"    …found a memory leak in the sync loop…\n",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Output should contain %q, got:\n%s", expected, output)
+		}
+	}
+
+	empty := FormatMessageSearch("nothing", &MessageSearchResponse{})
+	if empty != "No messages matching \"nothing\"\n" {
+		t.Errorf("empty output = %q", empty)
+	}
+}
+
+func TestQuoteMessage(t *testing.T) {
+	long := strings.Repeat("word ", 200)
+	tests := []struct {
+		name   string
+		format string
+		body   string
+		want   string
+	}{
+		{"markdown", "markdown", "Ship it?\n\nAfter CI.", "> Ship it?\n>\n> After CI."},
+		{"plain", "plain", "Ship it?\nAfter CI.", "@coordinator wrote:\n  Ship it?\n  After CI."},
+		{"many_lines", "markdown", "1\n2\n3\n4\n5\n6\n7", "> 1\n> 2\n> 3\n> 4\n> 5…"},
+		{"long_line", "markdown", long, "> " + strings.TrimRight(long[:quoteMaxRunes], " ") + "…"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := QuoteMessage(MessageDetail{
+				Author: AuthorInfo{AgentID: "coordinator"},
+				Body:   types.MessageBody{Format: tt.format, Content: tt.body},
+			})
+			if got != tt.want {
+				t.Errorf("QuoteMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReplyMentions(t *testing.T) {
+	parent := MessageDetail{
+		Author: AuthorInfo{AgentID: "coordinator"},
+		Scopes: []types.Scope{{Type: "group", Value: "reviewers"}},
+		Refs:   []types.Ref{{Type: "mention", Value: "implementer"}},
+	}
+	tests := []struct {
+		name string
+		opts ReplyOptions
+		want []string
+	}{
+		{"inherits", ReplyOptions{CallerAgentID: "impl_api"}, []string{"implementer", "coordinator", "@reviewers"}},
+		{"add unions", ReplyOptions{CallerAgentID: "impl_api", AddMentions: []string{"@security", "@implementer"}},
+			[]string{"implementer", "coordinator", "@reviewers", "@security"}},
+		{"to replaces", ReplyOptions{CallerAgentID: "impl_api", To: "@ops"}, nil},
+		{"mention replaces", ReplyOptions{CallerAgentID: "impl_api", Mentions: []string{"@qa"}}, []string{"@qa"}},
+		{"to plus add", ReplyOptions{CallerAgentID: "impl_api", To: "@ops", AddMentions: []string{"@qa"}}, []string{"@qa"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := replyMentions(parent, tt.opts); !slices.Equal(got, tt.want) {
+				t.Errorf("replyMentions = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -300,7 +300,7 @@ func migrateGitignore(repoPath string) (bool, error) {
 	if err != nil {
 		if os.IsNotExist(err) {
 			// No .gitignore — use the standard updateGitignore from init
-			return true, updateGitignore(repoPath)
+			return true, updateGitignore(repoPath, false)
 		}
 		return false, err
 	}
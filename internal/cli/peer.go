@@ -64,6 +64,15 @@ type PeerDetailedStatusEntry struct {
 	PairedAt string `json:"paired_at"`
 	LastSync string `json:"last_sync"`
 	LastSeq  int64  `json:"last_synced_seq"`
+	// Probe is set by peer status --probe.
+	Probe *PeerProbeResult `json:"probe,omitempty"`
+}
+
+// PeerProbeResult reports whether a peer answered sync.peer_info just now.
+type PeerProbeResult struct {
+	Reachable bool    `json:"reachable"`
+	RTTMs     float64 `json:"rtt_ms,omitempty"`
+	Error     string  `json:"error,omitempty"`
 }
 
 // --- RPC client functions ---
@@ -211,10 +220,60 @@ func PeerRemove(client *Client, name string) error {
 	return nil
 }
 
-// PeerStatus returns detailed status for all peers.
-func PeerStatus(client *Client) ([]PeerDetailedStatusEntry, error) {
+// PeerRenameResult is the result of renaming a peer.
+type PeerRenameResult struct {
+	DaemonID string `json:"daemon_id"`
+	OldName  string `json:"old_name,omitempty"`
+	NewName  string `json:"new_name"`
+}
+
+// PeerRename changes a peer's display name. name is the current name, or
+// the peer's daemon ID when no peer has that name.
+func PeerRename(client *Client, name, newName string) (*PeerRenameResult, error) {
+	req := struct {
+		Name    string `json:"name"`
+		NewName string `json:"new_name"`
+	}{Name: name, NewName: newName}
+
+	var result PeerRenameResult
+	if err := client.Call("peer.rename", req, &result); err != nil {
+		return nil, fmt.Errorf("rename peer: %w", err)
+	}
+	return &result, nil
+}
+
+// PeerSyncNowResult is the result of pulling from one peer.
+type PeerSyncNowResult struct {
+	DaemonID string `json:"daemon_id"`
+	Name     string `json:"name,omitempty"`
+	Applied  int    `json:"applied"`
+	Skipped  int    `json:"skipped"`
+}
+
+// PeerSyncNow pulls new events from the peer called name right away.
+func PeerSyncNow(client *Client, name string) (*PeerSyncNowResult, error) {
+	req := struct {
+		Name string `json:"name"`
+	}{Name: name}
+
+	var result PeerSyncNowResult
+	if err := client.Call("peer.sync", req, &result); err != nil {
+		return nil, fmt.Errorf("sync from peer: %w", err)
+	}
+	return &result, nil
+}
+
+// PeerStatus returns detailed status for all peers, or only the peer whose
+// name or daemon ID is name when name is non-empty. With probe the daemon
+// also calls each peer now and fills in Probe.
+func PeerStatus(client *Client, name string, probe bool) ([]PeerDetailedStatusEntry, error) {
+	req := struct {
+		Name  string `json:"name,omitempty"`
+		Probe bool   `json:"probe,omitempty"`
+	}{Name: name, Probe: probe}
+
 	var result []PeerDetailedStatusEntry
-	if err := client.Call("peer.status", struct{}{}, &result); err != nil {
+	if err := client.Call("peer.status", req, &result); err != nil {
 		return nil, fmt.Errorf("peer status: %w", err)
 	}
 	return result, nil
@@ -253,6 +312,14 @@ func FormatPeerList(peers []PeerListEntry) string {
 	return b.String()
 }
 
+// FormatPeerSyncNow formats the result of a single-peer pull for display.
+func FormatPeerSyncNow(name string, r *PeerSyncNowResult) string {
+	if r.Applied == 0 && r.Skipped == 0 {
+		return fmt.Sprintf("Synced from %s: no new events.\n", name)
+	}
+	return fmt.Sprintf("Synced from %s: pulled %d new event(s), skipped %d already present.\n", name, r.Applied, r.Skipped)
+}
+
 // FormatPeerStatus formats detailed peer status for display.
 func FormatPeerStatus(peers []PeerDetailedStatusEntry) string {
 	if len(peers) == 0 {
@@ -275,6 +342,13 @@ func FormatPeerStatus(peers []PeerDetailedStatusEntry) string {
 		} else {
 			fmt.Fprintf(&b, "Auth:      none\n")
 		}
+		switch {
+		case p.Probe == nil:
+		case p.Probe.Reachable:
+			fmt.Fprintf(&b, "Probe:     ✓ reachable (%.1fms)\n", p.Probe.RTTMs)
+		default:
+			fmt.Fprintf(&b, "Probe:     ✗ unreachable: %s\n", p.Probe.Error)
+		}
 	}
 
 	return b.String()
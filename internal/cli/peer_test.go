@@ -54,6 +54,25 @@ func TestFormatPeerList_Healthy_NoDriftSection(t *testing.T) {
 // sides need to move together; otherwise peer.list JSON round-trips
 // the new value but FormatPeerList renders no marker (silent
 // regression). This test pins them.
+func TestFormatPeerStatus_Probe(t *testing.T) {
+	out := FormatPeerStatus([]PeerDetailedStatusEntry{
+		{Name: "laptop", Probe: &PeerProbeResult{Reachable: true, RTTMs: 1.52}},
+		{Name: "desktop", Probe: &PeerProbeResult{Error: "no response within 3s"}},
+		{Name: "server"},
+	})
+	for _, want := range []string{
+		"Probe:     ✓ reachable (1.5ms)\n",
+		"Probe:     ✗ unreachable: no response within 3s\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("FormatPeerStatus() missing %q:\n%s", want, out)
+		}
+	}
+	if n := strings.Count(out, "Probe:"); n != 2 {
+		t.Errorf("FormatPeerStatus() has %d Probe lines, want 2 (none for an unprobed peer)", n)
+	}
+}
+
 func TestDriftReconcileFailedStatus_MatchesReconcilePackage(t *testing.T) {
 	// Literal here intentionally duplicates the reconcile constant;
 	// importing the daemon→reconcile chain from cli would create a
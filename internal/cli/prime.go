@@ -31,6 +31,10 @@ type PrimeContext struct {
 	TmuxMode            bool             `json:"tmux_mode,omitempty"`
 	RestartSnapshot     string           `json:"restart_snapshot,omitempty"`
 	SavedSessionContext string           `json:"saved_session_context,omitempty"`
+
+	// Budget, when positive, is the approximate token budget for
+	// FormatPrimeContext (prime --budget). It does not affect JSON output.
+	Budget int `json:"-"`
 }
 
 // LocalAgentName resolves the agent name for LOCAL-state prime consumes —
@@ -306,7 +310,46 @@ func gitOutput(args ...string) (string, error) {
 }
 
 // FormatPrimeContext formats the prime context for human-readable display.
+// With ctx.Budget set, lower-priority sections are trimmed to fit it (see
+// fitPrimeBudget) and a notice at the top lists what was trimmed.
 func FormatPrimeContext(ctx *PrimeContext) string {
+	sections := primeSections(ctx)
+
+	var out strings.Builder
+	if ctx.Budget > 0 {
+		if trimmed := fitPrimeBudget(sections, ctx.Budget); len(trimmed) > 0 {
+			out.WriteString(primeBudgetNotice(ctx.Budget, trimmed))
+		}
+	}
+	for _, s := range sections {
+		out.WriteString(s.text)
+	}
+	return out.String()
+}
+
+// primeSections renders each block of the prime output in display order.
+func primeSections(ctx *PrimeContext) []primeSection {
+	var sections []primeSection
+	add := func(s primeSection) {
+		if s.text != "" {
+			sections = append(sections, s)
+		}
+	}
+	add(primeIdentitySection(ctx))
+	add(primeTeamSection(ctx))
+	add(primeInboxSection(ctx))
+	add(primeGitSection(ctx))
+	add(primeDaemonSection(ctx))
+	add(primePreambleSection(ctx))
+	add(primeProjectStateSection(ctx))
+	add(primeSessionContextSection(ctx))
+	add(primeRestartSnapshotSection(ctx))
+	add(primeMessagingSection(ctx))
+	return sections
+}
+
+// primeIdentitySection renders the agent identity and session.
+func primeIdentitySection(ctx *PrimeContext) primeSection {
 	var out strings.Builder
 
 	// Identity
@@ -335,226 +378,333 @@ func FormatPrimeContext(ctx *PrimeContext) string {
 		out.WriteString("Session: none\n")
 	}
 
-	// Agents
-	if ctx.Agents != nil {
-		fmt.Fprintf(&out, "\nTeam: %d agents (%d active)\n", ctx.Agents.Total, ctx.Agents.Active)
-		for _, agent := range ctx.Agents.List {
-			fmt.Fprintf(&out, "  @%s (%s)\n", agent.Role, agent.Module)
-		}
+	return primeSection{text: out.String()}
+}
+
+// primeTeamSection renders the team summary; trimming drops the agent list.
+func primeTeamSection(ctx *PrimeContext) primeSection {
+	if ctx.Agents == nil {
+		return primeSection{}
 	}
+	header := fmt.Sprintf("\nTeam: %d agents (%d active)\n", ctx.Agents.Total, ctx.Agents.Active)
+	var out strings.Builder
+	out.WriteString(header)
+	for _, agent := range ctx.Agents.List {
+		fmt.Fprintf(&out, "  @%s (%s)\n", agent.Role, agent.Module)
+	}
+	return primeSection{
+		name:    "team list",
+		rank:    primeTrimTeam,
+		text:    out.String(),
+		trimmed: header,
+	}
+}
 
-	// Messages — only show recent messages when there are unread ones
-	if ctx.Messages != nil {
-		if ctx.Messages.Unread > 0 {
-			fmt.Fprintf(&out, "\nInbox: %d unread (%d total) — process these before starting new work\n", ctx.Messages.Unread, ctx.Messages.Total)
-			for _, msg := range ctx.Messages.Recent {
-				from := extractRole(msg.AgentID)
-				content := msg.Body.Content
-				if len(content) > 60 {
-					content = content[:57] + "..."
-				}
-				fmt.Fprintf(&out, "  @%s: %s\n", from, content)
-			}
-		} else {
-			fmt.Fprintf(&out, "\nInbox: %d messages (all read)\n", ctx.Messages.Total)
-		}
+// primeInboxSection renders the inbox summary. Recent messages are only
+// shown when there are unread ones; trimming keeps the unread ones and
+// drops those already read.
+func primeInboxSection(ctx *PrimeContext) primeSection {
+	if ctx.Messages == nil {
+		return primeSection{}
+	}
+	if ctx.Messages.Unread == 0 {
+		return primeSection{text: fmt.Sprintf("\nInbox: %d messages (all read)\n", ctx.Messages.Total)}
 	}
 
-	// Work context
-	if ctx.WorkContext != nil {
-		if ctx.WorkContext.Error != "" {
-			fmt.Fprintf(&out, "\nGit: %s\n", ctx.WorkContext.Error)
-		} else {
-			fmt.Fprintf(&out, "\nBranch: %s\n", ctx.WorkContext.Branch)
-			if ctx.WorkContext.UnmergedCommits > 0 {
-				fmt.Fprintf(&out, "  Unmerged commits: %d\n", ctx.WorkContext.UnmergedCommits)
+	render := func(unreadOnly bool) string {
+		var out strings.Builder
+		fmt.Fprintf(&out, "\nInbox: %d unread (%d total) — process these before starting new work\n", ctx.Messages.Unread, ctx.Messages.Total)
+		for _, msg := range ctx.Messages.Recent {
+			if unreadOnly && msg.IsRead {
+				continue
 			}
-			if len(ctx.WorkContext.UncommittedFiles) > 0 {
-				fmt.Fprintf(&out, "  Uncommitted files: %d\n", len(ctx.WorkContext.UncommittedFiles))
-				for _, f := range ctx.WorkContext.UncommittedFiles {
-					fmt.Fprintf(&out, "    %s\n", f)
-				}
+			from := extractRole(msg.AgentID)
+			content := msg.Body.Content
+			if len(content) > 60 {
+				content = content[:57] + "..."
 			}
+			fmt.Fprintf(&out, "  @%s: %s\n", from, content)
 		}
+		return out.String()
+	}
+	return primeSection{
+		name:    "read inbox messages",
+		rank:    primeTrimReadMessages,
+		text:    render(false),
+		trimmed: render(true),
 	}
+}
 
-	// Sync state
-	if ctx.SyncState != nil {
-		fmt.Fprintf(&out, "\nDaemon: %s", ctx.SyncState.DaemonStatus)
-		if ctx.SyncState.Version != "" {
-			fmt.Fprintf(&out, " (v%s)", ctx.SyncState.Version)
-		}
-		if ctx.SyncState.UptimeMs > 0 {
-			fmt.Fprintf(&out, ", up %s", formatDuration(time.Duration(ctx.SyncState.UptimeMs)*time.Millisecond))
-		}
-		out.WriteString("\n")
-		if ctx.SyncState.SyncState != "" {
-			fmt.Fprintf(&out, "  Sync: %s\n", ctx.SyncState.SyncState)
-		}
+// primeGitSection renders the git work context; trimming drops the list of
+// uncommitted files and keeps their count.
+func primeGitSection(ctx *PrimeContext) primeSection {
+	if ctx.WorkContext == nil {
+		return primeSection{}
+	}
+	if ctx.WorkContext.Error != "" {
+		return primeSection{text: fmt.Sprintf("\nGit: %s\n", ctx.WorkContext.Error)}
 	}
 
-	// Section 2: Preamble (role instructions). The preamble is LOCAL state;
-	// resolve the agent name from the daemon identity OR the on-disk identity
-	// fallback (thrum-t6qx) so a restarted-during-race agent doesn't lose its
-	// entire role discipline when ctx.Identity is transiently nil.
-	if preambleAgent := ctx.LocalAgentName(); ctx.RepoPath != "" && preambleAgent != "" {
-		thrumDir := filepath.Join(ctx.RepoPath, ".thrum")
-		agentName := preambleAgent
-		preamble, err := agentcontext.LoadPreamble(thrumDir, agentName)
-		if err == nil && len(preamble) > 0 {
-			out.WriteString("\n# Agent Instructions\n\n")
-			out.Write(preamble)
-			if preamble[len(preamble)-1] != '\n' {
-				out.WriteString("\n")
-			}
-		} else {
-			// Fallback: generate in-memory from role
-			out.WriteString("\n# Agent Instructions\n\n")
-			out.Write(agentcontext.DefaultPreamble())
-			out.WriteString("\n")
-		}
+	var out strings.Builder
+	fmt.Fprintf(&out, "\nBranch: %s\n", ctx.WorkContext.Branch)
+	if ctx.WorkContext.UnmergedCommits > 0 {
+		fmt.Fprintf(&out, "  Unmerged commits: %d\n", ctx.WorkContext.UnmergedCommits)
+	}
+	if len(ctx.WorkContext.UncommittedFiles) > 0 {
+		fmt.Fprintf(&out, "  Uncommitted files: %d\n", len(ctx.WorkContext.UncommittedFiles))
+	}
+	summary := out.String()
+	for _, f := range ctx.WorkContext.UncommittedFiles {
+		fmt.Fprintf(&out, "    %s\n", f)
+	}
+	return primeSection{
+		name:    "uncommitted file list",
+		rank:    primeTrimGitFiles,
+		text:    out.String(),
+		trimmed: summary,
 	}
+}
 
-	// Section 3: Project State
-	//
-	// project_state.md lives in the main repo's .thrum/context/ and is
-	// shared across all worktrees backed by it. Before thrum-92mj this
-	// joined ctx.RepoPath (the calling worktree) directly, so feature-
-	// worktree agents hit a missing file and the whole section was
-	// silently skipped — starting sessions blind to repo structure,
-	// decisions, and scope. paths.ResolveThrumDir follows .thrum/redirect
-	// when present and returns the worktree-local .thrum/ otherwise, so
-	// the same call works for both main-repo and feature-worktree agents.
-	if ctx.RepoPath != "" {
-		thrumDir, err := paths.ResolveThrumDir(ctx.RepoPath)
-		if err != nil {
-			// Resolution failed (malformed redirect, missing target).
-			// Surface via stderr so the problem is visible — previously
-			// all path failures were silent. Continue without blocking;
-			// the agent still gets every other section.
-			fmt.Fprintf(os.Stderr, "thrum prime: could not resolve .thrum directory for project_state.md: %v\n", err)
-		} else {
-			projectStatePath := filepath.Join(thrumDir, "context", "project_state.md")
-			if data, readErr := os.ReadFile(projectStatePath); readErr == nil && len(data) > 0 { // #nosec G304 -- internal context file resolved via paths.ResolveThrumDir
-				// Role-aware filter (thrum-ir2a): coordinator sees the full
-				// narrative; implementers/testers/researchers get the
-				// architectural subset to avoid flooding context with
-				// Recent Sessions and What's Queued blocks they don't act on.
-				role := ""
-				if ctx.Identity != nil {
-					role = ctx.Identity.Role
-				}
-				data = filterProjectStateSections(data, role)
-				// If the filter returned empty bytes (e.g. a file with
-				// only non-allowlisted sections for a non-coordinator
-				// role), silently skip the Project State block — an
-				// empty header with no body would be worse than nothing.
-				if len(data) > 0 {
-					out.WriteString("\n# Project State\n\n")
-					out.WriteString("The following is the current project state that is being maintained ")
-					out.WriteString("to give you a full understanding of where you are and what's next.\n\n")
-					out.Write(data)
-					if data[len(data)-1] != '\n' {
-						out.WriteString("\n")
-					}
-				}
-			}
-		}
+// primeDaemonSection renders daemon health and sync state.
+func primeDaemonSection(ctx *PrimeContext) primeSection {
+	if ctx.SyncState == nil {
+		return primeSection{}
+	}
+	var out strings.Builder
+	fmt.Fprintf(&out, "\nDaemon: %s", ctx.SyncState.DaemonStatus)
+	if ctx.SyncState.Version != "" {
+		fmt.Fprintf(&out, " (v%s)", ctx.SyncState.Version)
+	}
+	if ctx.SyncState.UptimeMs > 0 {
+		fmt.Fprintf(&out, ", up %s", formatDuration(time.Duration(ctx.SyncState.UptimeMs)*time.Millisecond))
+	}
+	out.WriteString("\n")
+	if ctx.SyncState.SyncState != "" {
+		fmt.Fprintf(&out, "  Sync: %s\n", ctx.SyncState.SyncState)
+	}
+	return primeSection{text: out.String()}
+}
+
+// primePreambleSection renders Section 2: Preamble (role instructions).
+func primePreambleSection(ctx *PrimeContext) primeSection {
+	// The preamble is LOCAL state; resolve the agent name from the daemon
+	// identity OR the on-disk identity fallback (thrum-t6qx) so a
+	// restarted-during-race agent doesn't lose its entire role discipline
+	// when ctx.Identity is transiently nil.
+	preambleAgent := ctx.LocalAgentName()
+	if ctx.RepoPath == "" || preambleAgent == "" {
+		return primeSection{}
 	}
 
-	// Section 4: Session Context (if saved)
-	if ctx.SavedSessionContext != "" {
-		out.WriteString("\n# Session Context\n\n")
-		out.WriteString(ctx.SavedSessionContext)
-		if ctx.SavedSessionContext[len(ctx.SavedSessionContext)-1] != '\n' {
+	const heading = "\n# Agent Instructions\n\n"
+	var out strings.Builder
+	thrumDir := filepath.Join(ctx.RepoPath, ".thrum")
+	agentName := preambleAgent
+	preamble, err := agentcontext.LoadPreamble(thrumDir, agentName)
+	if err == nil && len(preamble) > 0 {
+		out.WriteString(heading)
+		out.Write(preamble)
+		if preamble[len(preamble)-1] != '\n' {
 			out.WriteString("\n")
 		}
+	} else {
+		// Fallback: generate in-memory from role
+		out.WriteString(heading)
+		out.Write(agentcontext.DefaultPreamble())
+		out.WriteString("\n")
 	}
+	return primeSection{
+		name:    "agent instructions",
+		rank:    primeTrimPreamble,
+		text:    out.String(),
+		trimmed: heading + primeTrimmedPlaceholder,
+	}
+}
 
-	// Section 4.5: Restart Snapshot (if present — consumed from .thrum/restart/)
-	//
-	// Heading text "# Previous Session Context" is load-bearing: the
-	// claude-plugin SessionStart hook (inject-prime-context.sh) greps for
-	// this exact string to decide whether to hoist the loud action-required
-	// preamble at the top of additionalContext. Do not rename without
-	// updating the hook script in lockstep.
-	if ctx.RestartSnapshot != "" {
-		out.WriteString("\n# Previous Session Context\n\n")
-		out.WriteString("**🛑 ACTION REQUIRED — read this section before responding to the user or doing other work.**\n\n")
-		out.WriteString("The block below is a conversation snapshot **you** wrote in your previous session, immediately before restarting. It contains a `## Resume Plan` sub-section with concrete numbered steps that past-you decided future-you must execute. This is not background reading — it is your own message-to-self.\n\n")
-		out.WriteString("**Required steps:**\n\n")
-		out.WriteString("1. Read the `## Resume Plan` sub-section in full.\n")
-		out.WriteString("2. Execute its numbered steps in order.\n")
-		out.WriteString("3. Only after the plan is complete, return to the rest of this briefing or any pending user prompt.\n\n")
-		out.WriteString("---\n\n")
-		out.WriteString(ctx.RestartSnapshot)
+// primeProjectStateSection renders Section 3: Project State.
+//
+// project_state.md lives in the main repo's .thrum/context/ and is
+// shared across all worktrees backed by it. Before thrum-92mj this
+// joined ctx.RepoPath (the calling worktree) directly, so feature-
+// worktree agents hit a missing file and the whole section was
+// silently skipped — starting sessions blind to repo structure,
+// decisions, and scope. paths.ResolveThrumDir follows .thrum/redirect
+// when present and returns the worktree-local .thrum/ otherwise, so
+// the same call works for both main-repo and feature-worktree agents.
+func primeProjectStateSection(ctx *PrimeContext) primeSection {
+	if ctx.RepoPath == "" {
+		return primeSection{}
+	}
+	thrumDir, err := paths.ResolveThrumDir(ctx.RepoPath)
+	if err != nil {
+		// Resolution failed (malformed redirect, missing target).
+		// Surface via stderr so the problem is visible — previously
+		// all path failures were silent. Continue without blocking;
+		// the agent still gets every other section.
+		fmt.Fprintf(os.Stderr, "thrum prime: could not resolve .thrum directory for project_state.md: %v\n", err)
+		return primeSection{}
+	}
+	projectStatePath := filepath.Join(thrumDir, "context", "project_state.md")
+	data, readErr := os.ReadFile(projectStatePath) // #nosec G304 -- internal context file resolved via paths.ResolveThrumDir
+	if readErr != nil || len(data) == 0 {
+		return primeSection{}
+	}
+	// Role-aware filter (thrum-ir2a): coordinator sees the full
+	// narrative; implementers/testers/researchers get the
+	// architectural subset to avoid flooding context with
+	// Recent Sessions and What's Queued blocks they don't act on.
+	role := ""
+	if ctx.Identity != nil {
+		role = ctx.Identity.Role
+	}
+	data = filterProjectStateSections(data, role)
+	// If the filter returned empty bytes (e.g. a file with
+	// only non-allowlisted sections for a non-coordinator
+	// role), silently skip the Project State block — an
+	// empty header with no body would be worse than nothing.
+	if len(data) == 0 {
+		return primeSection{}
+	}
+
+	const heading = "\n# Project State\n\n"
+	var out strings.Builder
+	out.WriteString(heading)
+	out.WriteString("The following is the current project state that is being maintained ")
+	out.WriteString("to give you a full understanding of where you are and what's next.\n\n")
+	out.Write(data)
+	if data[len(data)-1] != '\n' {
 		out.WriteString("\n")
 	}
+	return primeSection{
+		name:    "project state",
+		rank:    primeTrimProjectState,
+		text:    out.String(),
+		trimmed: heading + primeTrimmedPlaceholder,
+	}
+}
 
-	// Sections 5-6: Multi-agent only
-	if !ctx.SingleAgentMode && ctx.Identity != nil && ctx.Runtime == "claude" {
-		repoPath := ctx.RepoPath
-		if repoPath == "" {
-			repoPath = "."
-		}
-		identDir := filepath.Join(repoPath, ".thrum", "identities")
-		if entries, err := os.ReadDir(identDir); err == nil && len(entries) > 0 {
-			// Section 5: Messaging protocol
-			out.WriteString("\n# Multi-Agent Messaging Protocol\n\n")
-			if ctx.TmuxMode {
-				// Tmux-mode: no listener rules, direct notification delivery
-				out.WriteString("## Tmux-Managed Session\n\n")
-				out.WriteString("You are running in a tmux-managed session. Message notifications\n")
-				out.WriteString("are delivered directly to your input — do NOT spawn a background listener.\n\n")
-				out.WriteString("When you see a message notification, check your inbox:\n")
-				out.WriteString("  thrum inbox --unread\n\n")
-			} else {
-				out.WriteString("## Listener Rules\n\n")
-				out.WriteString("**Tip:** If tmux is available on your system, consider using ")
-				out.WriteString("`thrum tmux start` for zero-cost message delivery instead of ")
-				out.WriteString("the listener pattern. See TMUX_SESSIONS.md resource.\n\n")
-				out.WriteString("**Keep the listener running at all times.** Spawn it on session start ")
-				out.WriteString("and re-arm whenever it completes. The PID file prevents duplicates — ")
-				out.WriteString("spawning when one is already running is a no-op.\n\n")
-			}
-			out.WriteString("1. **Check inbox before starting work and at every breakpoint.** ")
-			out.WriteString("`thrum inbox --unread`\n")
-			out.WriteString("2. **Send to agent NAMES, never role names.** ")
-			out.WriteString("Run `thrum team` first.\n")
-			out.WriteString("3. **Save context before compaction.** ")
-			out.WriteString("Use `/thrum:update-project` skill.\n\n")
-			// Command reference
-			out.WriteString("## Commands\n\n")
-			out.WriteString("**Check messages:** `thrum inbox --unread`\n")
-			out.WriteString("**Send message:** `thrum send \"message\" --to @<agent_name>`\n")
-			out.WriteString("**Reply:** `thrum reply <MSG_ID> \"response\"`\n")
-			out.WriteString("**Who's online:** `thrum team`\n")
-			out.WriteString("**Agent/daemon status:** `thrum status`\n")
-			out.WriteString("**Mark all read:** `thrum message read --all`\n\n")
-			// Priority handling
-			out.WriteString("## Priority Handling\n\n")
-			out.WriteString("| Priority | Action |\n")
-			out.WriteString("|----------|--------|\n")
-			out.WriteString("| `critical` | Stop current work immediately |\n")
-			out.WriteString("| `high` | Process at next breakpoint |\n")
-			out.WriteString("| `normal` | Process when current sub-task completes |\n")
-			out.WriteString("| `low` | Queue, process when convenient |\n\n")
-			// Anti-patterns
-			out.WriteString("## Anti-Patterns\n\n")
-			if !ctx.TmuxMode {
-				out.WriteString("- **Deaf Agent** — No listener running. You miss messages.\n")
-			}
-			out.WriteString("- **Silent Agent** — Never sends status updates.\n\n")
-
-			// Section 6: Listener spawn instructions (legacy mode only)
-			if !ctx.TmuxMode {
-				agentID := ctx.Identity.AgentID
-				out.WriteString("\n## Start Background Message Listener\n\n")
-				fmt.Fprintf(&out, "  Task(subagent_type=\"message-listener\", model=\"haiku\",\n")
-				fmt.Fprintf(&out, "    prompt=\"Listen for Thrum messages.\\nSTEP_1: %s/scripts/thrum-startup.sh --listener-heartbeat\\nSTEP_2: thrum wait --timeout 8m --after -15s --agent-name %s\")\n", repoPath, agentID)
-			}
-		}
+// primeSessionContextSection renders Section 4: Session Context (if saved).
+func primeSessionContextSection(ctx *PrimeContext) primeSection {
+	if ctx.SavedSessionContext == "" {
+		return primeSection{}
+	}
+	const heading = "\n# Session Context\n\n"
+	var out strings.Builder
+	out.WriteString(heading)
+	out.WriteString(ctx.SavedSessionContext)
+	if ctx.SavedSessionContext[len(ctx.SavedSessionContext)-1] != '\n' {
+		out.WriteString("\n")
 	}
+	return primeSection{
+		name:    "session context",
+		rank:    primeTrimSessionContext,
+		text:    out.String(),
+		trimmed: heading + primeTrimmedPlaceholder,
+	}
+}
 
-	return out.String()
+// primeRestartSnapshotSection renders Section 4.5: Restart Snapshot (if
+// present — consumed from .thrum/restart/). It is never trimmed: prime
+// deletes the snapshot once it is printed, so a trimmed one would be lost.
+//
+// Heading text "# Previous Session Context" is load-bearing: the
+// claude-plugin SessionStart hook (inject-prime-context.sh) greps for
+// this exact string to decide whether to hoist the loud action-required
+// preamble at the top of additionalContext. Do not rename without
+// updating the hook script in lockstep.
+func primeRestartSnapshotSection(ctx *PrimeContext) primeSection {
+	if ctx.RestartSnapshot == "" {
+		return primeSection{}
+	}
+	var out strings.Builder
+	out.WriteString("\n# Previous Session Context\n\n")
+	out.WriteString("**🛑 ACTION REQUIRED — read this section before responding to the user or doing other work.**\n\n")
+	out.WriteString("The block below is a conversation snapshot **you** wrote in your previous session, immediately before restarting. It contains a `## Resume Plan` sub-section with concrete numbered steps that past-you decided future-you must execute. This is not background reading — it is your own message-to-self.\n\n")
+	out.WriteString("**Required steps:**\n\n")
+	out.WriteString("1. Read the `## Resume Plan` sub-section in full.\n")
+	out.WriteString("2. Execute its numbered steps in order.\n")
+	out.WriteString("3. Only after the plan is complete, return to the rest of this briefing or any pending user prompt.\n\n")
+	out.WriteString("---\n\n")
+	out.WriteString(ctx.RestartSnapshot)
+	out.WriteString("\n")
+	return primeSection{text: out.String()}
+}
+
+// primeMessagingSection renders Sections 5-6 (multi-agent only): the
+// messaging protocol and, in legacy mode, the listener spawn instructions.
+func primeMessagingSection(ctx *PrimeContext) primeSection {
+	if ctx.SingleAgentMode || ctx.Identity == nil || ctx.Runtime != "claude" {
+		return primeSection{}
+	}
+	repoPath := ctx.RepoPath
+	if repoPath == "" {
+		repoPath = "."
+	}
+	identDir := filepath.Join(repoPath, ".thrum", "identities")
+	if entries, err := os.ReadDir(identDir); err != nil || len(entries) == 0 {
+		return primeSection{}
+	}
+
+	// Section 5: Messaging protocol
+	const heading = "\n# Multi-Agent Messaging Protocol\n\n"
+	var out strings.Builder
+	out.WriteString(heading)
+	if ctx.TmuxMode {
+		// Tmux-mode: no listener rules, direct notification delivery
+		out.WriteString("## Tmux-Managed Session\n\n")
+		out.WriteString("You are running in a tmux-managed session. Message notifications\n")
+		out.WriteString("are delivered directly to your input — do NOT spawn a background listener.\n\n")
+		out.WriteString("When you see a message notification, check your inbox:\n")
+		out.WriteString("  thrum inbox --unread\n\n")
+	} else {
+		out.WriteString("## Listener Rules\n\n")
+		out.WriteString("**Tip:** If tmux is available on your system, consider using ")
+		out.WriteString("`thrum tmux start` for zero-cost message delivery instead of ")
+		out.WriteString("the listener pattern. See TMUX_SESSIONS.md resource.\n\n")
+		out.WriteString("**Keep the listener running at all times.** Spawn it on session start ")
+		out.WriteString("and re-arm whenever it completes. The PID file prevents duplicates — ")
+		out.WriteString("spawning when one is already running is a no-op.\n\n")
+	}
+	out.WriteString("1. **Check inbox before starting work and at every breakpoint.** ")
+	out.WriteString("`thrum inbox --unread`\n")
+	out.WriteString("2. **Send to agent NAMES, never role names.** ")
+	out.WriteString("Run `thrum team` first.\n")
+	out.WriteString("3. **Save context before compaction.** ")
+	out.WriteString("Use `/thrum:update-project` skill.\n\n")
+	// Command reference
+	out.WriteString("## Commands\n\n")
+	out.WriteString("**Check messages:** `thrum inbox --unread`\n")
+	out.WriteString("**Send message:** `thrum send \"message\" --to @<agent_name>`\n")
+	out.WriteString("**Reply:** `thrum reply <MSG_ID> \"response\"`\n")
+	out.WriteString("**Who's online:** `thrum team`\n")
+	out.WriteString("**Agent/daemon status:** `thrum status`\n")
+	out.WriteString("**Mark all read:** `thrum message read --all`\n\n")
+	// Priority handling
+	out.WriteString("## Priority Handling\n\n")
+	out.WriteString("| Priority | Action |\n")
+	out.WriteString("|----------|--------|\n")
+	out.WriteString("| `critical` | Stop current work immediately |\n")
+	out.WriteString("| `high` | Process at next breakpoint |\n")
+	out.WriteString("| `normal` | Process when current sub-task completes |\n")
+	out.WriteString("| `low` | Queue, process when convenient |\n\n")
+	// Anti-patterns
+	out.WriteString("## Anti-Patterns\n\n")
+	if !ctx.TmuxMode {
+		out.WriteString("- **Deaf Agent** — No listener running. You miss messages.\n")
+	}
+	out.WriteString("- **Silent Agent** — Never sends status updates.\n\n")
+
+	// Section 6: Listener spawn instructions (legacy mode only)
+	if !ctx.TmuxMode {
+		agentID := ctx.Identity.AgentID
+		out.WriteString("\n## Start Background Message Listener\n\n")
+		fmt.Fprintf(&out, "  Task(subagent_type=\"message-listener\", model=\"haiku\",\n")
+		fmt.Fprintf(&out, "    prompt=\"Listen for Thrum messages.\\nSTEP_1: %s/scripts/thrum-startup.sh --listener-heartbeat\\nSTEP_2: thrum wait --timeout 8m --after -15s --agent-name %s\")\n", repoPath, agentID)
+	}
+	return primeSection{
+		name:    "messaging protocol",
+		rank:    primeTrimMessaging,
+		text:    out.String(),
+		trimmed: heading + primeTrimmedPlaceholder,
+	}
 }
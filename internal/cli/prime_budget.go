@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+)
+
+// primeCharsPerToken approximates tokens from output size for prime
+// --budget. Four characters per token is the usual rule of thumb for
+// English text and markdown.
+const primeCharsPerToken = 4
+
+// Trim ranks for prime --budget, in the order sections are trimmed. Rank 0
+// (the zero value) marks a section that is never trimmed: identity and
+// session, the inbox counts and unread messages, daemon health, and the
+// restart snapshot.
+const (
+	primeTrimGitFiles = iota + 1
+	primeTrimTeam
+	primeTrimReadMessages
+	primeTrimMessaging
+	primeTrimProjectState
+	primeTrimPreamble
+	primeTrimSessionContext
+)
+
+// primeTrimmedPlaceholder replaces the body of a section dropped to fit
+// the budget, so the agent still sees that the section exists.
+const primeTrimmedPlaceholder = "[Trimmed to fit --budget; run `thrum prime` without --budget to see it.]\n"
+
+// primeSection is one block of FormatPrimeContext output.
+type primeSection struct {
+	name    string // what trimming removes, for the budget notice
+	rank    int    // trim order; 0 is never trimmed
+	text    string // full rendering
+	trimmed string // rendering once trimmed
+}
+
+// fitPrimeBudget trims sections in rank order until the output, including
+// the budget notice, fits budget tokens, and returns the names of the
+// sections it trimmed. Sections whose trimmed rendering is no shorter are
+// left alone. The result can still exceed the budget when the sections
+// that are never trimmed are larger than it.
+func fitPrimeBudget(sections []primeSection, budget int) []string {
+	limit := budget * primeCharsPerToken
+	var trimmed []string
+	size := func() int {
+		n := 0
+		for _, s := range sections {
+			n += len(s.text)
+		}
+		if len(trimmed) > 0 {
+			n += len(primeBudgetNotice(budget, trimmed))
+		}
+		return n
+	}
+
+	for rank := primeTrimGitFiles; rank <= primeTrimSessionContext; rank++ {
+		if size() <= limit {
+			break
+		}
+		for i := range sections {
+			s := &sections[i]
+			if s.rank != rank || len(s.trimmed) >= len(s.text) {
+				continue
+			}
+			s.text = s.trimmed
+			trimmed = append(trimmed, s.name)
+		}
+	}
+	return trimmed
+}
+
+// primeBudgetNotice tells the agent its context is partial and what was
+// left out. It leads the output so it is read before the trimmed sections.
+func primeBudgetNotice(budget int, trimmed []string) string {
+	return fmt.Sprintf("[Context truncated to fit --budget %d (about %d characters). Trimmed: %s. Run `thrum prime` without --budget for the full context.]\n\n",
+		budget, budget*primeCharsPerToken, strings.Join(trimmed, ", "))
+}
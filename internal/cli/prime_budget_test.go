@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatPrimeContext_Budget(t *testing.T) {
+	message := func(id, content string, read bool) Message {
+		m := Message{MessageID: id, AgentID: "test:coordinator:main", IsRead: read}
+		m.Body.Content = content
+		return m
+	}
+	newCtx := func(budget int) *PrimeContext {
+		files := make([]string, 40)
+		for i := range files {
+			files[i] = "internal/some/deeply/nested/package/file.go"
+		}
+		return &PrimeContext{
+			Identity: &WhoamiResult{AgentID: "impl_auth", Role: "implementer"},
+			Agents: &AgentsInfo{Total: 2, Active: 1, List: []AgentInfo{
+				{AgentID: "impl_auth", Role: "implementer", Module: "auth"},
+				{AgentID: "reviewer", Role: "reviewer", Module: "all"},
+			}},
+			Messages: &MessagesInfo{Unread: 1, Total: 2, Recent: []Message{
+				message("msg_new", "UNREAD MENTION", false),
+				message("msg_old", "OLD READ ENTRY", true),
+			}},
+			WorkContext:         &WorkContextInfo{Branch: "main", UncommittedFiles: files},
+			SavedSessionContext: strings.Repeat("saved notes ", 100),
+			RestartSnapshot:     "## Resume Plan\n1. finish the migration",
+			Budget:              budget,
+		}
+	}
+
+	full := FormatPrimeContext(newCtx(0))
+	if got := FormatPrimeContext(newCtx(len(full))); got != full {
+		t.Errorf("output within budget changed:\n%s", got)
+	}
+
+	// Dropping the file list is enough to fit; nothing else is trimmed.
+	out := FormatPrimeContext(newCtx((len(full) - 1500) / primeCharsPerToken))
+	if !strings.HasPrefix(out, "[Context truncated to fit --budget") ||
+		!strings.Contains(out, "Trimmed: uncommitted file list. Run") {
+		t.Errorf("missing or wrong truncation notice:\n%s", out)
+	}
+	if strings.Contains(out, "nested/package") || !strings.Contains(out, "Uncommitted files: 40") {
+		t.Errorf("file list should be trimmed to its count:\n%s", out)
+	}
+	if !strings.Contains(out, "@reviewer") || !strings.Contains(out, "saved notes") {
+		t.Errorf("sections ranked after the file list should be kept:\n%s", out)
+	}
+
+	// A tiny budget trims everything trimmable but keeps identity, unread
+	// messages, and the restart snapshot.
+	out = FormatPrimeContext(newCtx(10))
+	for _, want := range []string{
+		"Trimmed: uncommitted file list, team list, read inbox messages, session context.",
+		"Agent: @implementer (impl_auth)",
+		"Team: 2 agents (1 active)",
+		"UNREAD MENTION",
+		"# Session Context\n\n" + primeTrimmedPlaceholder,
+		"## Resume Plan",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	for _, unwanted := range []string{"@reviewer", "OLD READ ENTRY", "saved notes"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("output should not contain %q:\n%s", unwanted, out)
+		}
+	}
+}
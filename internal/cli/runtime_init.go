@@ -117,6 +117,12 @@ func runtimeTemplates(runtime string) []runtimeTemplate {
 			{tmplPath: "templates/auggie/rules.md.tmpl", outPath: ".augment/rules/thrum.md", mode: 0644},
 			{tmplPath: "templates/shared/startup.sh.tmpl", outPath: "scripts/thrum-startup.sh", mode: 0755, managed: true},
 		}
+	case "amp":
+		return []runtimeTemplate{
+			{tmplPath: "templates/amp/settings.json.tmpl", outPath: ".amp/settings.json", mode: 0644},
+			{tmplPath: "templates/amp/AGENTS.md.tmpl", outPath: "AGENTS.md", mode: 0644},
+			{tmplPath: "templates/shared/startup.sh.tmpl", outPath: "scripts/thrum-startup.sh", mode: 0755, managed: true},
+		}
 	case "cli-only":
 		return []runtimeTemplate{
 			{tmplPath: "templates/shared/startup.sh.tmpl", outPath: "scripts/thrum-startup.sh", mode: 0755, managed: true},
@@ -199,7 +205,7 @@ func RuntimeInit(opts RuntimeInitOptions) (*RuntimeInitResult, error) {
 	for _, rt := range runtimes {
 		tmpls := runtimeTemplates(rt)
 		if tmpls == nil {
-			return nil, fmt.Errorf("unknown runtime: %q", rt)
+			return nil, fmt.Errorf("no config templates for runtime %q", rt)
 		}
 
 		for _, tmpl := range tmpls {
@@ -314,6 +314,40 @@ func TestRuntimeInit_ForceOverwrite(t *testing.T) {
 	}
 }
 
+func TestRuntimeInit_SkipsExistingWithoutForce(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// A user-owned AGENTS.md must survive a later codex generate.
+	_ = os.WriteFile(filepath.Join(tmpDir, "AGENTS.md"), []byte("existing"), 0600)
+
+	result, err := RuntimeInit(RuntimeInitOptions{
+		RepoPath:  tmpDir,
+		Runtime:   "codex",
+		AgentName: "test_agent",
+	})
+	if err != nil {
+		t.Fatalf("RuntimeInit failed: %v", err)
+	}
+
+	var skipped bool
+	for _, f := range result.Files {
+		if f.Path == "AGENTS.md" && f.Skipped {
+			skipped = true
+		}
+	}
+	if !skipped {
+		t.Error("existing AGENTS.md should be reported as skipped without --force")
+	}
+
+	content, _ := os.ReadFile(filepath.Clean(filepath.Join(tmpDir, "AGENTS.md")))
+	if string(content) != "existing" {
+		t.Error("existing file should be left untouched without --force")
+	}
+	if !strings.Contains(FormatRuntimeInit(result), "SKIP") {
+		t.Error("formatted output should list the skipped file")
+	}
+}
+
 func TestRuntimeInit_AllRuntimes(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -417,6 +451,62 @@ func TestRuntimeInit_Auggie(t *testing.T) {
 	}
 }
 
+func TestRuntimeInit_Amp(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	opts := RuntimeInitOptions{
+		RepoPath:  tmpDir,
+		Runtime:   "amp",
+		AgentName: "test_agent",
+		AgentRole: "implementer",
+		AgentMod:  "backend",
+	}
+
+	dry := opts
+	dry.DryRun = true
+	preview, err := RuntimeInit(dry)
+	if err != nil {
+		t.Fatalf("RuntimeInit dry run for amp failed: %v", err)
+	}
+	var previewPaths []string
+	for _, f := range preview.Files {
+		previewPaths = append(previewPaths, f.Path)
+	}
+	if got := strings.Join(previewPaths, ","); got != ".amp/settings.json,AGENTS.md,scripts/thrum-startup.sh" {
+		t.Errorf("dry-run files = %s", got)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, ".amp")); !os.IsNotExist(err) {
+		t.Error("dry run should not create .amp/")
+	}
+
+	if _, err := RuntimeInit(opts); err != nil {
+		t.Fatalf("RuntimeInit for amp failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, ".amp", "settings.json"))
+	if err != nil {
+		t.Fatalf("failed to read .amp/settings.json: %v", err)
+	}
+	var settings map[string]map[string]struct {
+		Command string   `json:"command"`
+		Args    []string `json:"args"`
+	}
+	if err := json.Unmarshal(content, &settings); err != nil {
+		t.Fatalf(".amp/settings.json is not valid JSON: %v", err)
+	}
+	if server := settings["amp.mcpServers"]["thrum"]; server.Command != "thrum" || strings.Join(server.Args, " ") != "mcp serve" {
+		t.Errorf("amp.mcpServers.thrum = %+v, want thrum mcp serve", server)
+	}
+
+	content, err = os.ReadFile(filepath.Join(tmpDir, "AGENTS.md"))
+	if err != nil {
+		t.Fatalf("failed to read AGENTS.md: %v", err)
+	}
+	if !strings.Contains(string(content), "quickstart --name test_agent --role implementer --module backend") {
+		t.Error("AGENTS.md should contain the quickstart command")
+	}
+}
+
 func TestRuntimeInit_InvalidRuntime(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -662,7 +752,7 @@ func TestClaudeTemplateMatchesCanonicalHooks(t *testing.T) {
 }
 
 func TestEachRuntimeTemplateSet(t *testing.T) {
-	runtimes := []string{"claude", "codex", "cursor", "gemini", "auggie", "cli-only"}
+	runtimes := []string{"claude", "codex", "cursor", "gemini", "auggie", "amp", "cli-only"}
 
 	for _, rt := range runtimes {
 		t.Run(rt, func(t *testing.T) {
@@ -3,20 +3,31 @@ package cli
 import (
 	"encoding/json"
 	"fmt"
+	"path/filepath"
 	"strings"
+
+	"github.com/leonletto/thrum/internal/types"
 )
 
 // SendOptions contains options for sending a message.
 type SendOptions struct {
-	Content       string
-	Scopes        []string // Format: "type:value"
-	Refs          []string // Format: "type:value"
-	Mentions      []string // Format: "@role"
-	ReplyTo       string   // Message ID to reply to
-	Structured    string   // JSON string
-	Format        string
-	To            string // Direct recipient (e.g., "@reviewer" or "@everyone")
-	CallerAgentID string // Caller's resolved agent ID (for worktree identity)
+	Content        string
+	Scopes         []string // Format: "type:value"
+	Refs           []string // Format: "type:value"
+	Mentions       []string // Format: "@role"
+	SnapshotGroups []string // Groups expanded to members at send time (format: "@group")
+	Tags           []string // Free-form labels, filterable via inbox --tag
+	Priority       string   // "low", "normal", or "high"; filterable via inbox --priority
+	TTL            string   // Go duration after which the daemon deletes the message (--ttl)
+	Schedule       string   // RFC 3339 time or "+DURATION" to hold the message until (--schedule)
+	Attachments    []string // Files copied onto the sync branch with the message (--attach)
+	ReplyTo        string   // Message ID to reply to
+	NoThread       bool     // Don't start a thread when ReplyTo has none
+	Structured     string   // JSON string
+	Format         string
+	To             string // Direct recipient (e.g., "@reviewer" or "@everyone")
+	IdempotencyKey string // Retries with the same key return the first send's result
+	CallerAgentID  string // Caller's resolved agent ID (for worktree identity)
 }
 
 // SendResult contains the result of sending a message.
@@ -24,6 +35,8 @@ type SendResult struct {
 	MessageID  string           `json:"message_id"`
 	ThreadID   string           `json:"thread_id,omitempty"`
 	CreatedAt  string           `json:"created_at"`
+	ExpiresAt  string           `json:"expires_at,omitempty"`
+	DeliverAt  string           `json:"deliver_at,omitempty"`
 	ResolvedTo int              `json:"resolved_to"`
 	Warnings   []string         `json:"warnings,omitempty"`
 	Audiences  []Audience       `json:"audiences,omitempty"`
@@ -46,6 +59,106 @@ type RecipientState struct {
 
 // Send sends a message via the daemon.
 func Send(client *Client, opts SendOptions) (*SendResult, error) {
+	params, err := sendParams(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Call RPC
+	var result SendResult
+	if err := client.Call("message.send", params, &result); err != nil {
+		return nil, withBodyTooLargeHint(err)
+	}
+
+	return &result, nil
+}
+
+// withBodyTooLargeHint adds a next step to the daemon's size-cap
+// rejection. --file only moves where the body is read from, so the hint
+// points at --attach, which keeps the big content out of the body.
+func withBodyTooLargeHint(err error) error {
+	if !strings.Contains(err.Error(), "message body too large") {
+		return err
+	}
+	return fmt.Errorf("%w (try: write the full content to a file and send a short message with --attach FILE; --file reads the body from a file but the same limit applies)", err)
+}
+
+// ResolveResult is the daemon's preview of a send (message.resolve): who
+// would receive it and which scopes and refs it would carry.
+type ResolveResult struct {
+	AgentID    string        `json:"agent_id"`
+	ThreadID   string        `json:"thread_id,omitempty"`
+	NewThread  bool          `json:"new_thread,omitempty"`
+	ResolvedTo int           `json:"resolved_to"`
+	Warnings   []string      `json:"warnings,omitempty"`
+	Audiences  []Audience    `json:"audiences,omitempty"`
+	Recipients []string      `json:"recipients"`
+	Scopes     []types.Scope `json:"scopes"`
+	Refs       []types.Ref   `json:"refs"`
+}
+
+// Resolve runs the daemon's send-time validation and recipient resolution
+// for opts without sending anything (thrum send --dry-run). It returns the
+// same error a real send would.
+func Resolve(client *Client, opts SendOptions) (*ResolveResult, error) {
+	params, err := sendParams(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var result ResolveResult
+	if err := client.Call("message.resolve", params, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// FormatResolve formats a send dry run for display.
+func FormatResolve(result *ResolveResult) string {
+	var out strings.Builder
+	out.WriteString("Dry run — nothing sent\n")
+	fmt.Fprintf(&out, "  From: %s\n", result.AgentID)
+	if len(result.Audiences) > 0 {
+		parts := make([]string, len(result.Audiences))
+		for i, audience := range result.Audiences {
+			parts[i] = audience.Type + ":" + audience.Value
+		}
+		fmt.Fprintf(&out, "  To: %s\n", strings.Join(parts, ", "))
+	}
+	if len(result.Recipients) == 0 {
+		out.WriteString("  Recipients (0): none\n")
+	} else {
+		fmt.Fprintf(&out, "  Recipients (%d): %s\n", len(result.Recipients), strings.Join(result.Recipients, ", "))
+	}
+	if len(result.Scopes) > 0 {
+		parts := make([]string, len(result.Scopes))
+		for i, s := range result.Scopes {
+			parts[i] = s.Type + ":" + s.Value
+		}
+		fmt.Fprintf(&out, "  Scopes: %s\n", strings.Join(parts, ", "))
+	}
+	if len(result.Refs) > 0 {
+		parts := make([]string, len(result.Refs))
+		for i, r := range result.Refs {
+			parts[i] = r.Type + ":" + r.Value
+		}
+		fmt.Fprintf(&out, "  Refs: %s\n", strings.Join(parts, ", "))
+	}
+	switch {
+	case result.NewThread:
+		out.WriteString("  Thread: new (the reply starts one)\n")
+	case result.ThreadID != "":
+		fmt.Fprintf(&out, "  Thread: %s\n", result.ThreadID)
+	}
+	for _, w := range result.Warnings {
+		fmt.Fprintf(&out, "  warning: %s\n", w)
+	}
+	return out.String()
+}
+
+// sendParams builds the message.send / message.resolve request from opts.
+func sendParams(opts SendOptions) (map[string]any, error) {
 	// Parse scopes
 	scopes, err := parseScopes(opts.Scopes)
 	if err != nil {
@@ -93,6 +206,9 @@ func Send(client *Client, opts SendOptions) (*SendResult, error) {
 	if opts.ReplyTo != "" {
 		params["reply_to"] = opts.ReplyTo
 	}
+	if opts.NoThread {
+		params["no_thread"] = true
+	}
 
 	if len(scopes) > 0 {
 		params["scopes"] = scopes
@@ -110,17 +226,49 @@ func Send(client *Client, opts SendOptions) (*SendResult, error) {
 		params["mentions"] = opts.Mentions
 	}
 
-	if opts.CallerAgentID != "" {
-		params["caller_agent_id"] = opts.CallerAgentID
+	if len(opts.SnapshotGroups) > 0 {
+		params["snapshot_groups"] = opts.SnapshotGroups
 	}
 
-	// Call RPC
-	var result SendResult
-	if err := client.Call("message.send", params, &result); err != nil {
-		return nil, err
+	if len(opts.Tags) > 0 {
+		params["tags"] = opts.Tags
 	}
 
-	return &result, nil
+	if opts.Priority != "" {
+		params["priority"] = opts.Priority
+	}
+
+	if opts.TTL != "" {
+		params["ttl"] = opts.TTL
+	}
+
+	if opts.Schedule != "" {
+		params["schedule"] = opts.Schedule
+	}
+
+	// The daemon reads attachments from its own filesystem, so relative
+	// paths are resolved here against the caller's working directory.
+	if len(opts.Attachments) > 0 {
+		attachments := make([]string, len(opts.Attachments))
+		for i, p := range opts.Attachments {
+			abs, err := filepath.Abs(p)
+			if err != nil {
+				return nil, fmt.Errorf("invalid attachment %s: %w", p, err)
+			}
+			attachments[i] = abs
+		}
+		params["attachments"] = attachments
+	}
+
+	if opts.IdempotencyKey != "" {
+		params["idempotency_key"] = opts.IdempotencyKey
+	}
+
+	if opts.CallerAgentID != "" {
+		params["caller_agent_id"] = opts.CallerAgentID
+	}
+
+	return params, nil
 }
 
 // parseScopes parses scope strings in "type:value" format.
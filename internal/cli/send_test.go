@@ -2,7 +2,9 @@ package cli
 
 import (
 	"encoding/json"
+	"errors"
 	"net"
+	"strings"
 	"testing"
 )
 
@@ -234,6 +236,132 @@ func TestSend_WithRefs(t *testing.T) {
 	}
 }
 
+func TestSend_WithTags(t *testing.T) {
+	daemon, socketPath := newMockDaemon(t)
+	defer daemon.stop()
+
+	var receivedParams map[string]any
+
+	daemon.start(t, func(conn net.Conn) {
+		defer func() { _ = conn.Close() }()
+
+		decoder := json.NewDecoder(conn)
+		encoder := json.NewEncoder(conn)
+
+		var request map[string]any
+		if err := decoder.Decode(&request); err != nil {
+			return
+		}
+
+		var ok bool
+		receivedParams, ok = request["params"].(map[string]any)
+		if !ok {
+			t.Error("params should be map[string]any")
+			return
+		}
+
+		response := map[string]any{
+			"jsonrpc": "2.0",
+			"id":      request["id"],
+			"result": map[string]any{
+				"message_id": "msg_01HXE8Z7",
+				"created_at": "2026-02-03T10:00:00Z",
+			},
+		}
+
+		_ = encoder.Encode(response)
+	})
+
+	<-daemon.Ready()
+
+	client, err := NewClient(socketPath)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	if _, err := Send(client, SendOptions{Content: "Use SQLite", Tags: []string{"decision", "db"}}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	tags, ok := receivedParams["tags"].([]any)
+	if !ok || len(tags) != 2 || tags[0] != "decision" || tags[1] != "db" {
+		t.Fatalf("Expected tags [decision db], got %v", receivedParams["tags"])
+	}
+}
+
+func TestSendParams_IdempotencyKey(t *testing.T) {
+	params, err := sendParams(SendOptions{Content: "deploy done", IdempotencyKey: "idk_1"})
+	if err != nil {
+		t.Fatalf("sendParams: %v", err)
+	}
+	if params["idempotency_key"] != "idk_1" {
+		t.Errorf("idempotency_key = %v, want idk_1", params["idempotency_key"])
+	}
+
+	params, err = sendParams(SendOptions{Content: "deploy done"})
+	if err != nil {
+		t.Fatalf("sendParams: %v", err)
+	}
+	if _, ok := params["idempotency_key"]; ok {
+		t.Error("idempotency_key set without a key, want it omitted")
+	}
+}
+
+func TestSend_WithSnapshotGroups(t *testing.T) {
+	daemon, socketPath := newMockDaemon(t)
+	defer daemon.stop()
+
+	var receivedParams map[string]any
+
+	daemon.start(t, func(conn net.Conn) {
+		defer func() { _ = conn.Close() }()
+
+		decoder := json.NewDecoder(conn)
+		encoder := json.NewEncoder(conn)
+
+		var request map[string]any
+		if err := decoder.Decode(&request); err != nil {
+			return
+		}
+
+		var ok bool
+		receivedParams, ok = request["params"].(map[string]any)
+		if !ok {
+			t.Error("params should be map[string]any")
+			return
+		}
+
+		response := map[string]any{
+			"jsonrpc": "2.0",
+			"id":      request["id"],
+			"result": map[string]any{
+				"message_id": "msg_01HXE8Z7",
+				"created_at": "2026-02-03T10:00:00Z",
+			},
+		}
+
+		_ = encoder.Encode(response)
+	})
+
+	<-daemon.Ready()
+
+	client, err := NewClient(socketPath)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	if _, err := Send(client, SendOptions{Content: "Freeze starts now", SnapshotGroups: []string{"@release"}}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	groups, ok := receivedParams["snapshot_groups"].([]any)
+	if !ok || len(groups) != 1 || groups[0] != "@release" {
+		t.Fatalf("Expected snapshot_groups [@release], got %v", receivedParams["snapshot_groups"])
+	}
+}
+
 func TestSend_WithStructured(t *testing.T) {
 	daemon, socketPath := newMockDaemon(t)
 	defer daemon.stop()
@@ -307,6 +435,68 @@ func TestSend_WithStructured(t *testing.T) {
 	}
 }
 
+func TestResolve(t *testing.T) {
+	daemon, socketPath := newMockDaemon(t)
+	defer daemon.stop()
+
+	var method string
+	daemon.start(t, func(conn net.Conn) {
+		defer func() { _ = conn.Close() }()
+
+		decoder := json.NewDecoder(conn)
+		encoder := json.NewEncoder(conn)
+
+		var request map[string]any
+		if err := decoder.Decode(&request); err != nil {
+			return
+		}
+		method, _ = request["method"].(string)
+
+		response := map[string]any{
+			"jsonrpc": "2.0",
+			"id":      request["id"],
+			"result": map[string]any{
+				"agent_id":    "ops_1",
+				"resolved_to": 1,
+				"audiences":   []map[string]string{{"type": "broadcast", "value": "everyone"}},
+				"recipients":  []string{"impl_api", "reviewer_1"},
+				"scopes":      []map[string]string{{"type": "broadcast", "value": "everyone"}},
+				"refs":        []map[string]string{{"type": "broadcast", "value": "everyone"}},
+			},
+		}
+		_ = encoder.Encode(response)
+	})
+
+	<-daemon.Ready()
+
+	client, err := NewClient(socketPath)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	result, err := Resolve(client, SendOptions{Content: "deploy at noon", To: "@everyone"})
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if method != "message.resolve" {
+		t.Errorf("method = %q, want message.resolve", method)
+	}
+
+	out := FormatResolve(result)
+	for _, want := range []string{
+		"Dry run — nothing sent\n",
+		"  From: ops_1\n",
+		"  To: broadcast:everyone\n",
+		"  Recipients (2): impl_api, reviewer_1\n",
+		"  Scopes: broadcast:everyone\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("FormatResolve missing %q:\n%s", want, out)
+		}
+	}
+}
+
 func TestParseScopes(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -426,3 +616,14 @@ func TestParseRefs(t *testing.T) {
 		})
 	}
 }
+
+func TestWithBodyTooLargeHint(t *testing.T) {
+	capErr := errors.New("RPC error -32602: message body too large: 70000 bytes exceeds the daemon limit of 65536 bytes")
+	if got := withBodyTooLargeHint(capErr); !errors.Is(got, capErr) || !strings.Contains(got.Error(), "file") {
+		t.Errorf("withBodyTooLargeHint(cap error) = %v; want the original error plus a file hint", got)
+	}
+	other := errors.New("RPC error -32000: unknown recipient")
+	if got := withBodyTooLargeHint(other); got != other {
+		t.Errorf("withBodyTooLargeHint(other) = %v; want it unchanged", got)
+	}
+}
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -35,6 +36,27 @@ type SessionEndResponse struct {
 	Duration  int64  `json:"duration_ms"`
 }
 
+// SessionResumeRequest represents the request for session.resume RPC.
+type SessionResumeRequest struct {
+	AgentID   string      `json:"agent_id"`
+	SessionID string      `json:"session_id,omitempty"`
+	Refs      []types.Ref `json:"refs,omitempty"`
+}
+
+// SessionResumeResponse represents the response from session.resume RPC.
+type SessionResumeResponse struct {
+	Resumed     bool          `json:"resumed"`
+	SessionID   string        `json:"session_id,omitempty"`
+	AgentID     string        `json:"agent_id"`
+	StartedAt   string        `json:"started_at,omitempty"`
+	LastSeenAt  string        `json:"last_seen_at,omitempty"`
+	Intent      string        `json:"intent,omitempty"`
+	CurrentTask string        `json:"current_task,omitempty"`
+	Scopes      []types.Scope `json:"scopes,omitempty"`
+	Refs        []types.Ref   `json:"refs,omitempty"`
+	Superseded  []string      `json:"superseded,omitempty"`
+}
+
 // SessionStartOptions contains options for starting a session.
 type SessionStartOptions struct {
 	AgentID string
@@ -42,12 +64,51 @@ type SessionStartOptions struct {
 	Refs    []types.Ref
 }
 
+// SessionResumeOptions contains options for resuming a session.
+type SessionResumeOptions struct {
+	AgentID   string
+	SessionID string
+	Refs      []types.Ref
+}
+
 // SessionEndOptions contains options for ending a session.
 type SessionEndOptions struct {
 	SessionID string
 	Reason    string
 }
 
+// DeclaredFileScopes turns `session start --files` arguments into "file"
+// session scopes. Paths are made relative to worktreeRoot (relative
+// arguments are taken from the current directory) so they match the
+// repo-relative paths git reports; a path outside the worktree, or any path
+// when worktreeRoot is empty, is kept as given after cleaning. Duplicates are
+// dropped.
+func DeclaredFileScopes(worktreeRoot string, files []string) []types.Scope {
+	var scopes []types.Scope
+	seen := make(map[string]bool)
+	for _, f := range files {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		path := filepath.Clean(f)
+		if worktreeRoot != "" {
+			if abs, err := filepath.Abs(f); err == nil {
+				if rel, err := filepath.Rel(worktreeRoot, abs); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+					path = rel
+				}
+			}
+		}
+		path = filepath.ToSlash(path)
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		scopes = append(scopes, types.Scope{Type: "file", Value: path})
+	}
+	return scopes
+}
+
 // SessionStart starts a new session.
 func SessionStart(client *Client, opts SessionStartOptions) (*SessionStartResponse, error) {
 	req := SessionStartRequest(opts)
@@ -72,6 +133,19 @@ func SessionEnd(client *Client, opts SessionEndOptions) (*SessionEndResponse, er
 	return &result, nil
 }
 
+// SessionResume reattaches to an orphaned session. The response has
+// Resumed=false when the agent has no open session.
+func SessionResume(client *Client, opts SessionResumeOptions) (*SessionResumeResponse, error) {
+	req := SessionResumeRequest(opts)
+
+	var result SessionResumeResponse
+	if err := client.Call("session.resume", req, &result); err != nil {
+		return nil, fmt.Errorf("session.resume RPC failed: %w", err)
+	}
+
+	return &result, nil
+}
+
 // FormatSessionStart formats the session start response for display.
 func FormatSessionStart(result *SessionStartResponse) string {
 	output := fmt.Sprintf("✓ Session started: %s\n", result.SessionID)
@@ -89,6 +163,43 @@ func FormatSessionStart(result *SessionStartResponse) string {
 	return output
 }
 
+// FormatSessionResume formats the session resume response for display.
+func FormatSessionResume(result *SessionResumeResponse) string {
+	output := fmt.Sprintf("✓ Session resumed: %s\n", result.SessionID)
+	output += fmt.Sprintf("  Agent:      %s\n", result.AgentID)
+	if result.StartedAt != "" {
+		if t, err := time.Parse(time.RFC3339, result.StartedAt); err == nil {
+			output += fmt.Sprintf("  Started:    %s\n", t.Format("2006-01-02 15:04:05"))
+		} else {
+			output += fmt.Sprintf("  Started:    %s\n", result.StartedAt)
+		}
+	}
+	if result.LastSeenAt != "" {
+		if t, err := time.Parse(time.RFC3339, result.LastSeenAt); err == nil {
+			output += fmt.Sprintf("  Last seen:  %s\n", t.Format("2006-01-02 15:04:05"))
+		}
+	}
+	if result.Intent != "" {
+		output += fmt.Sprintf("  Intent:     %s\n", result.Intent)
+	}
+	if result.CurrentTask != "" {
+		output += fmt.Sprintf("  Task:       %s\n", result.CurrentTask)
+	}
+	var files []string
+	for _, sc := range result.Scopes {
+		if sc.Type == "file" {
+			files = append(files, sc.Value)
+		}
+	}
+	if len(files) > 0 {
+		output += fmt.Sprintf("  Files:      %s\n", strings.Join(files, ", "))
+	}
+	if len(result.Superseded) > 0 {
+		output += fmt.Sprintf("  Superseded: %s\n", strings.Join(result.Superseded, ", "))
+	}
+	return output
+}
+
 // FormatSessionEnd formats the session end response for display.
 func FormatSessionEnd(result *SessionEndResponse) string {
 	output := fmt.Sprintf("✓ Session ended: %s\n", result.SessionID)
@@ -181,10 +292,105 @@ func FormatSetTask(result *SetTaskResponse) string {
 	return fmt.Sprintf("✓ Task set: %s\n", result.CurrentTask)
 }
 
+// SessionTransferRequest represents the request for session.transfer RPC.
+type SessionTransferRequest struct {
+	FromAgentID string `json:"from_agent_id"`
+	ToAgentID   string `json:"to_agent_id"`
+	EndSource   bool   `json:"end_source,omitempty"`
+}
+
+// SessionTransferResponse represents the response from session.transfer RPC.
+// ContextBytes and HandoffMessageID are filled in by `thrum agent transfer`
+// after the RPC, when it copies the context file and sends the handoff.
+type SessionTransferResponse struct {
+	FromAgentID      string        `json:"from_agent_id"`
+	FromSessionID    string        `json:"from_session_id"`
+	ToAgentID        string        `json:"to_agent_id"`
+	ToSessionID      string        `json:"to_session_id"`
+	Started          bool          `json:"started"`
+	Intent           string        `json:"intent,omitempty"`
+	CurrentTask      string        `json:"current_task,omitempty"`
+	Scopes           []types.Scope `json:"scopes,omitempty"`
+	SourceEnded      bool          `json:"source_ended,omitempty"`
+	ContextBytes     int           `json:"context_bytes,omitempty"`
+	HandoffMessageID string        `json:"handoff_message_id,omitempty"`
+}
+
+// SessionTransfer copies the intent, task and scopes of one agent's active
+// session onto another's, optionally ending the source session.
+func SessionTransfer(client *Client, req SessionTransferRequest) (*SessionTransferResponse, error) {
+	var result SessionTransferResponse
+	if err := client.Call("session.transfer", req, &result); err != nil {
+		return nil, fmt.Errorf("session.transfer RPC failed: %w", err)
+	}
+
+	return &result, nil
+}
+
+// HandoffMessage is the body of the message `thrum agent transfer` sends to
+// record a handoff.
+func HandoffMessage(result *SessionTransferResponse) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Handoff: @%s is taking over from @%s.", result.ToAgentID, result.FromAgentID)
+	if result.Intent != "" {
+		fmt.Fprintf(&b, "\nIntent: %s", result.Intent)
+	}
+	if result.CurrentTask != "" {
+		fmt.Fprintf(&b, "\nTask: %s", result.CurrentTask)
+	}
+	if len(result.Scopes) > 0 {
+		fmt.Fprintf(&b, "\nScopes: %s", formatScopes(result.Scopes))
+	}
+	if result.ContextBytes > 0 {
+		fmt.Fprintf(&b, "\nSaved context copied (%d bytes).", result.ContextBytes)
+	}
+	return b.String()
+}
+
+// FormatSessionTransfer formats the session transfer result for display.
+func FormatSessionTransfer(result *SessionTransferResponse) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "✓ Transferred %s → %s\n", result.FromAgentID, result.ToAgentID)
+	if result.Started {
+		fmt.Fprintf(&b, "  Session:    %s (started)\n", result.ToSessionID)
+	} else {
+		fmt.Fprintf(&b, "  Session:    %s\n", result.ToSessionID)
+	}
+	if result.Intent != "" {
+		fmt.Fprintf(&b, "  Intent:     %s\n", result.Intent)
+	}
+	if result.CurrentTask != "" {
+		fmt.Fprintf(&b, "  Task:       %s\n", result.CurrentTask)
+	}
+	if len(result.Scopes) > 0 {
+		fmt.Fprintf(&b, "  Scopes:     %s\n", formatScopes(result.Scopes))
+	}
+	if result.ContextBytes > 0 {
+		fmt.Fprintf(&b, "  Context:    copied (%d bytes)\n", result.ContextBytes)
+	}
+	if result.HandoffMessageID != "" {
+		fmt.Fprintf(&b, "  Handoff:    %s\n", result.HandoffMessageID)
+	}
+	if result.SourceEnded {
+		fmt.Fprintf(&b, "  Ended:      %s (handoff)\n", result.FromSessionID)
+	}
+	return b.String()
+}
+
+// formatScopes joins scopes as "type:value, ...".
+func formatScopes(scopes []types.Scope) string {
+	parts := make([]string, len(scopes))
+	for i, sc := range scopes {
+		parts[i] = sc.Type + ":" + sc.Value
+	}
+	return strings.Join(parts, ", ")
+}
+
 // ListSessionsRequest represents the request for session.list RPC.
 type ListSessionsRequest struct {
 	AgentID    string `json:"agent_id,omitempty"`
 	ActiveOnly bool   `json:"active_only,omitempty"`
+	Since      string `json:"since,omitempty"`
 }
 
 // ListSessionsResponse represents the response from session.list RPC.
@@ -208,6 +414,7 @@ type SessionSummary struct {
 type SessionListOptions struct {
 	AgentID    string
 	ActiveOnly bool
+	Since      string // RFC3339; only sessions active at or after it
 }
 
 // SessionList lists sessions.
@@ -233,6 +440,7 @@ func FormatSessionList(result *ListSessionsResponse) string {
 
 	fmt.Fprintf(&output, "Sessions (%d):\n", len(result.Sessions))
 
+	now := time.Now()
 	for _, s := range result.Sessions {
 		var statusStr string
 		if s.Status == "active" {
@@ -242,34 +450,61 @@ func FormatSessionList(result *ListSessionsResponse) string {
 		}
 
 		fmt.Fprintf(&output, "\n  %s [%s]\n", s.SessionID, statusStr)
-		fmt.Fprintf(&output, "    Agent:   %s\n", s.AgentID)
+		fmt.Fprintf(&output, "    Agent:    %s\n", s.AgentID)
 
 		if t, err := time.Parse(time.RFC3339Nano, s.StartedAt); err == nil {
-			fmt.Fprintf(&output, "    Started: %s\n", t.Format("2006-01-02 15:04:05"))
+			fmt.Fprintf(&output, "    Started:  %s\n", t.Format("2006-01-02 15:04:05"))
 		} else {
-			fmt.Fprintf(&output, "    Started: %s\n", s.StartedAt)
+			fmt.Fprintf(&output, "    Started:  %s\n", s.StartedAt)
 		}
 
 		if s.EndedAt != "" {
 			if t, err := time.Parse(time.RFC3339Nano, s.EndedAt); err == nil {
-				fmt.Fprintf(&output, "    Ended:   %s\n", t.Format("2006-01-02 15:04:05"))
+				fmt.Fprintf(&output, "    Ended:    %s\n", t.Format("2006-01-02 15:04:05"))
 			} else {
-				fmt.Fprintf(&output, "    Ended:   %s\n", s.EndedAt)
+				fmt.Fprintf(&output, "    Ended:    %s\n", s.EndedAt)
 			}
 		}
 
+		fmt.Fprintf(&output, "    Duration: %s\n", sessionDuration(s, now))
+
 		if s.Intent != "" {
-			fmt.Fprintf(&output, "    Intent:  %s\n", s.Intent)
+			fmt.Fprintf(&output, "    Intent:   %s\n", s.Intent)
 		}
 
 		if s.EndReason != "" && s.EndReason != "normal" {
-			fmt.Fprintf(&output, "    Reason:  %s\n", s.EndReason)
+			fmt.Fprintf(&output, "    Reason:   %s\n", s.EndReason)
 		}
 	}
 
 	return output.String()
 }
 
+// sessionDuration is ended_at − started_at for an ended session, or
+// "active Nm" for a live one. A crashed session's ended_at is when the
+// daemon noticed, not when the agent stopped, so its duration is "unknown",
+// as is any that would come out negative or from unparseable timestamps.
+func sessionDuration(s SessionSummary, now time.Time) string {
+	started, err := time.Parse(time.RFC3339Nano, s.StartedAt)
+	if err != nil {
+		return "unknown"
+	}
+	if s.EndedAt == "" {
+		if d := now.Sub(started); d >= 0 {
+			return "active " + formatDuration(d)
+		}
+		return "active"
+	}
+	if s.EndReason == "crash" || s.EndReason == "crash_recovered" {
+		return "unknown"
+	}
+	ended, err := time.Parse(time.RFC3339Nano, s.EndedAt)
+	if err != nil || ended.Before(started) {
+		return "unknown"
+	}
+	return formatDuration(ended.Sub(started))
+}
+
 // HeartbeatRequest represents the request for session.heartbeat RPC.
 type HeartbeatRequest struct {
 	SessionID    string        `json:"session_id"`
@@ -277,6 +512,10 @@ type HeartbeatRequest struct {
 	RemoveScopes []types.Scope `json:"remove_scopes,omitempty"`
 	AddRefs      []types.Ref   `json:"add_refs,omitempty"`
 	RemoveRefs   []types.Ref   `json:"remove_refs,omitempty"`
+	// Intent and CurrentTask, when non-nil, update the session's work
+	// context in the same round-trip. An empty string clears the field.
+	Intent      *string `json:"intent,omitempty"`
+	CurrentTask *string `json:"current_task,omitempty"`
 }
 
 // HeartbeatResponse represents the response from session.heartbeat RPC.
@@ -295,6 +534,8 @@ type HeartbeatOptions struct {
 	RemoveScopes []types.Scope
 	AddRefs      []types.Ref
 	RemoveRefs   []types.Ref
+	Intent       *string // nil leaves the intent unchanged; "" clears it
+	CurrentTask  *string // nil leaves the task unchanged; "" clears it
 }
 
 // SessionHeartbeat sends a heartbeat for the session.
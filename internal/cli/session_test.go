@@ -3,7 +3,11 @@ package cli
 import (
 	"encoding/json"
 	"net"
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/leonletto/thrum/internal/types"
 )
@@ -149,6 +153,39 @@ func TestSessionEnd(t *testing.T) {
 	}
 }
 
+func TestDeclaredFileScopes(t *testing.T) {
+	// Resolve symlinks (macOS /var → /private/var) so the root matches
+	// the working directory Getwd reports.
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, "internal"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(filepath.Join(root, "internal"))
+
+	got := DeclaredFileScopes(root, []string{
+		"auth/login.go",                   // relative to cwd
+		filepath.Join(root, "README.md"),  // absolute inside the worktree
+		"./auth/login.go",                 // duplicate after normalizing
+		"",                                // ignored
+		filepath.Join(root, "..", "x.go"), // outside the worktree: kept as given
+	})
+	want := []types.Scope{
+		{Type: "file", Value: "internal/auth/login.go"},
+		{Type: "file", Value: "README.md"},
+		{Type: "file", Value: filepath.ToSlash(filepath.Join(filepath.Dir(root), "x.go"))},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DeclaredFileScopes() = %v, want %v", got, want)
+	}
+
+	if got := DeclaredFileScopes("", []string{"a/./b.go"}); len(got) != 1 || got[0].Value != "a/b.go" {
+		t.Errorf("without a worktree root = %v, want a/b.go", got)
+	}
+}
+
 func TestFormatSessionStart(t *testing.T) {
 	result := SessionStartResponse{
 		SessionID: "ses_01HXE...",
@@ -171,6 +208,57 @@ func TestFormatSessionStart(t *testing.T) {
 	}
 }
 
+func TestFormatSessionResume(t *testing.T) {
+	result := SessionResumeResponse{
+		Resumed:    true,
+		SessionID:  "ses_01HXE...",
+		AgentID:    "agent:implementer:ABC123",
+		StartedAt:  "2026-02-03T10:00:00Z",
+		LastSeenAt: "2026-02-03T11:30:00Z",
+		Intent:     "Refactoring auth",
+		Scopes:     []types.Scope{{Type: "file", Value: "auth.go"}, {Type: "module", Value: "auth"}},
+		Superseded: []string{"ses_OLD"},
+	}
+
+	output := FormatSessionResume(&result)
+
+	for _, field := range []string{"Session resumed: ses_01HXE...", "Last seen", "Refactoring auth", "Files:      auth.go\n", "ses_OLD"} {
+		if !contains(output, field) {
+			t.Errorf("Output should contain %q:\n%s", field, output)
+		}
+	}
+}
+
+func TestFormatSessionTransfer(t *testing.T) {
+	result := SessionTransferResponse{
+		FromAgentID:      "alice",
+		FromSessionID:    "ses_FROM",
+		ToAgentID:        "bob",
+		ToSessionID:      "ses_TO",
+		Started:          true,
+		Intent:           "Refactoring auth",
+		CurrentTask:      "beads:thrum-abc",
+		Scopes:           []types.Scope{{Type: "module", Value: "auth"}},
+		SourceEnded:      true,
+		ContextBytes:     120,
+		HandoffMessageID: "msg_01",
+	}
+
+	output := FormatSessionTransfer(&result)
+	for _, field := range []string{"Transferred alice → bob", "ses_TO (started)", "module:auth", "copied (120 bytes)", "msg_01", "ses_FROM (handoff)"} {
+		if !contains(output, field) {
+			t.Errorf("Output should contain %q:\n%s", field, output)
+		}
+	}
+
+	msg := HandoffMessage(&result)
+	for _, field := range []string{"@bob is taking over from @alice", "Intent: Refactoring auth", "Task: beads:thrum-abc", "Scopes: module:auth"} {
+		if !contains(msg, field) {
+			t.Errorf("Handoff message should contain %q:\n%s", field, msg)
+		}
+	}
+}
+
 func TestSessionHeartbeat(t *testing.T) {
 	mockResponse := HeartbeatResponse{
 		SessionID:  "ses_01HXE...",
@@ -303,3 +391,23 @@ func TestFormatSessionEnd(t *testing.T) {
 		}
 	}
 }
+
+func TestSessionDuration(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		s    SessionSummary
+		want string
+	}{
+		{"ended", SessionSummary{StartedAt: "2026-03-01T09:00:00Z", EndedAt: "2026-03-01T10:30:00Z", EndReason: "normal"}, "1h30m"},
+		{"active", SessionSummary{StartedAt: "2026-03-01T11:48:00Z"}, "active 12m"},
+		{"crashed", SessionSummary{StartedAt: "2026-03-01T09:00:00Z", EndedAt: "2026-03-01T11:00:00Z", EndReason: "crash_recovered"}, "unknown"},
+		{"ended before started", SessionSummary{StartedAt: "2026-03-01T09:00:00Z", EndedAt: "2026-03-01T08:00:00Z"}, "unknown"},
+		{"bad start", SessionSummary{StartedAt: "yesterday"}, "unknown"},
+	}
+	for _, tt := range tests {
+		if got := sessionDuration(tt.s, now); got != tt.want {
+			t.Errorf("%s: sessionDuration = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -13,13 +13,15 @@ import (
 
 // HealthResult contains daemon health information.
 type HealthResult struct {
-	Status    string             `json:"status"`
-	UptimeMs  int64              `json:"uptime_ms"`
-	Version   string             `json:"version"`
-	RepoID    string             `json:"repo_id"`
-	SyncState string             `json:"sync_state"`
-	Tailscale *TailscaleSyncInfo `json:"tailscale,omitempty"`
-	Identity  *IdentityInfo      `json:"identity,omitempty"`
+	Status          string             `json:"status"`
+	UptimeMs        int64              `json:"uptime_ms"`
+	Version         string             `json:"version"`
+	RepoID          string             `json:"repo_id"`
+	SyncState       string             `json:"sync_state"`
+	LocalOnly       bool               `json:"local_only"`
+	LocalOnlyReason string             `json:"local_only_reason,omitempty"`
+	Tailscale       *TailscaleSyncInfo `json:"tailscale,omitempty"`
+	Identity        *IdentityInfo      `json:"identity,omitempty"`
 }
 
 // IdentityInfo mirrors the RPC IdentityInfo type for CLI deserialization.
@@ -38,15 +40,15 @@ type TailscaleSyncInfo struct {
 	Hostname       string          `json:"hostname"`
 	ConnectedPeers int             `json:"connected_peers"`
 	Peers          []TailscalePeer `json:"peers,omitempty"`
+	LastSync       string          `json:"last_sync,omitempty"`
 	SyncStatus     string          `json:"sync_status"`
 }
 
 // TailscalePeer represents a peer in the Tailscale sync status.
 type TailscalePeer struct {
 	DaemonID string `json:"daemon_id"`
-	Hostname string `json:"hostname"`
+	Name     string `json:"name"`
 	LastSync string `json:"last_sync"`
-	Status   string `json:"status"`
 }
 
 // WhoamiResult contains current agent information.
@@ -242,7 +244,7 @@ func FormatStatus(result *StatusResult) string {
 	if ts := result.Health.Tailscale; ts != nil && ts.Enabled {
 		fmt.Fprintf(&output, "Tailscale: %s (%d peers)\n", ts.Hostname, ts.ConnectedPeers)
 		for _, peer := range ts.Peers {
-			fmt.Fprintf(&output, "  - %s (last sync: %s)\n", peer.Hostname, peer.LastSync)
+			fmt.Fprintf(&output, "  - %s (last sync: %s)\n", peer.Name, peer.LastSync)
 		}
 	}
 
@@ -2,11 +2,17 @@ package cli
 
 import (
 	"fmt"
+	"strings"
 	"time"
 )
 
 // SyncForceRequest represents a request to force a sync.
-type SyncForceRequest struct{}
+type SyncForceRequest struct {
+	PushOnly bool `json:"push_only,omitempty"`
+	PullOnly bool `json:"pull_only,omitempty"`
+
+	ForceEvenIfPaused bool `json:"force_even_if_paused,omitempty"`
+}
 
 // SyncForceResponse represents the response from a force sync.
 type SyncForceResponse struct {
@@ -14,6 +20,7 @@ type SyncForceResponse struct {
 	LastSyncAt string `json:"last_sync_at"`
 	SyncState  string `json:"sync_state"`
 	LocalOnly  bool   `json:"local_only"`
+	Direction  string `json:"direction,omitempty"`
 }
 
 // SyncStatusRequest represents a request for sync status.
@@ -26,11 +33,59 @@ type SyncStatusResponse struct {
 	LastError  string `json:"last_error,omitempty"`
 	SyncState  string `json:"sync_state"`
 	LocalOnly  bool   `json:"local_only"`
+
+	Paused      bool   `json:"paused,omitempty"`
+	PausedAt    string `json:"paused_at,omitempty"`
+	PausedUntil string `json:"paused_until,omitempty"`
+
+	LastSyncUnix        int64 `json:"last_sync_unix"`
+	ConsecutiveFailures int   `json:"consecutive_failures"`
+	PendingPushCount    *int  `json:"pending_push_count,omitempty"`
 }
 
-// SyncForce triggers an immediate sync.
-func SyncForce(client *Client) (*SyncForceResponse, error) {
-	req := SyncForceRequest{}
+// SyncPauseRequest represents a request to pause sync.
+type SyncPauseRequest struct {
+	Duration string `json:"duration,omitempty"`
+}
+
+// SyncPauseResponse represents the response from sync.pause and sync.resume.
+type SyncPauseResponse struct {
+	Paused      bool   `json:"paused"`
+	WasPaused   bool   `json:"was_paused"`
+	PausedUntil string `json:"paused_until,omitempty"`
+}
+
+// SyncLogEntry is one sync attempt recorded by the daemon.
+type SyncLogEntry struct {
+	StartedAt  string         `json:"started_at"`
+	DurationMS int64          `json:"duration_ms"`
+	Direction  string         `json:"direction"`
+	Fetched    int            `json:"fetched"`
+	Pushed     int            `json:"pushed"`
+	Retries    []SyncLogRetry `json:"retries,omitempty"`
+	Error      string         `json:"error,omitempty"`
+}
+
+// SyncLogRetry is a rejected push that the daemon retried within an attempt.
+type SyncLogRetry struct {
+	Attempt int    `json:"attempt"`
+	DelayMS int64  `json:"delay_ms"`
+	Error   string `json:"error"`
+}
+
+// SyncLogResponse represents the recent sync attempts, newest first.
+type SyncLogResponse struct {
+	Attempts   []SyncLogEntry `json:"attempts"`
+	Capacity   int            `json:"capacity"`
+	Persistent bool           `json:"persistent"`
+}
+
+// SyncForce triggers an immediate sync. pushOnly and pullOnly restrict the
+// cycle to one direction; the daemon rejects both being set. While sync is
+// paused the daemon refuses unless forceEvenIfPaused is set, in which case
+// one cycle runs and the pause stays in place.
+func SyncForce(client *Client, pushOnly, pullOnly, forceEvenIfPaused bool) (*SyncForceResponse, error) {
+	req := SyncForceRequest{PushOnly: pushOnly, PullOnly: pullOnly, ForceEvenIfPaused: forceEvenIfPaused}
 
 	var result SyncForceResponse
 	if err := client.Call("sync.force", req, &result); err != nil {
@@ -52,10 +107,51 @@ func SyncStatus(client *Client) (*SyncStatusResponse, error) {
 	return &result, nil
 }
 
+// SyncPause stops the daemon's sync loop from running git operations.
+// duration is a Go duration after which sync resumes on its own; empty
+// pauses until SyncResume.
+func SyncPause(client *Client, duration string) (*SyncPauseResponse, error) {
+	req := SyncPauseRequest{Duration: duration}
+
+	var result SyncPauseResponse
+	if err := client.Call("sync.pause", req, &result); err != nil {
+		return nil, fmt.Errorf("sync.pause RPC failed: %w", err)
+	}
+
+	return &result, nil
+}
+
+// SyncResume lifts a pause set by SyncPause.
+func SyncResume(client *Client) (*SyncPauseResponse, error) {
+	var result SyncPauseResponse
+	if err := client.Call("sync.resume", struct{}{}, &result); err != nil {
+		return nil, fmt.Errorf("sync.resume RPC failed: %w", err)
+	}
+
+	return &result, nil
+}
+
+// SyncLog retrieves the daemon's in-memory log of recent sync attempts.
+func SyncLog(client *Client) (*SyncLogResponse, error) {
+	var result SyncLogResponse
+	if err := client.Call("sync.log", struct{}{}, &result); err != nil {
+		return nil, fmt.Errorf("sync.log RPC failed: %w", err)
+	}
+
+	return &result, nil
+}
+
 // FormatSyncForce formats the sync force response for display.
 func FormatSyncForce(result *SyncForceResponse) string {
 	output := "✓ Sync triggered\n"
 
+	switch result.Direction {
+	case "push":
+		output += "  Direction:  push only (remote not fetched)\n"
+	case "pull":
+		output += "  Direction:  pull only (local changes not pushed)\n"
+	}
+
 	if result.LocalOnly {
 		output += "  Mode:       local-only (remote sync disabled)\n"
 	}
@@ -116,10 +212,21 @@ func FormatSyncStatus(result *SyncStatusResponse) string {
 		output += "State:      ✗ error\n"
 	case "stopped":
 		output += "State:      stopped\n"
+	case "paused":
+		output += "State:      ⏸ paused\n"
 	default:
 		output += fmt.Sprintf("State:      %s\n", result.SyncState)
 	}
 
+	if result.Paused {
+		if t, err := time.Parse(time.RFC3339, result.PausedUntil); err == nil {
+			output += fmt.Sprintf("Paused:     until %s (in %s)\n",
+				t.Local().Format("2006-01-02 15:04:05"), formatDuration(time.Until(t)))
+		} else {
+			output += "Paused:     until 'thrum sync resume'\n"
+		}
+	}
+
 	// Last sync time
 	if result.LastSyncAt != "" {
 		if t, err := time.Parse(time.RFC3339, result.LastSyncAt); err == nil {
@@ -137,6 +244,63 @@ func FormatSyncStatus(result *SyncStatusResponse) string {
 	if result.LastError != "" {
 		output += fmt.Sprintf("Last error: %s\n", result.LastError)
 	}
+	if result.ConsecutiveFailures > 1 {
+		output += fmt.Sprintf("Failures:   %d in a row\n", result.ConsecutiveFailures)
+	}
+	if result.PendingPushCount != nil && *result.PendingPushCount > 0 {
+		output += fmt.Sprintf("Unpushed:   %d commit(s) on a-sync\n", *result.PendingPushCount)
+	}
+
+	return output
+}
+
+// FormatSyncPause formats the sync.pause or sync.resume response for display.
+func FormatSyncPause(result *SyncPauseResponse) string {
+	if !result.Paused {
+		if !result.WasPaused {
+			return "Sync was not paused\n"
+		}
+		return "✓ Sync resumed\n"
+	}
 
+	output := "✓ Sync paused\n"
+	if t, err := time.Parse(time.RFC3339, result.PausedUntil); err == nil {
+		output += fmt.Sprintf("  Resumes:    %s (in %s)\n",
+			t.Local().Format("2006-01-02 15:04:05"), formatDuration(time.Until(t)))
+	} else {
+		output += "  Resumes:    on 'thrum sync resume'\n"
+	}
 	return output
 }
+
+// FormatSyncLog formats the sync log for display, one attempt per line.
+func FormatSyncLog(result *SyncLogResponse) string {
+	var output strings.Builder
+
+	fmt.Fprintf(&output, "Recent sync attempts (in memory, last %d kept; cleared on daemon restart)\n\n", result.Capacity)
+	if len(result.Attempts) == 0 {
+		output.WriteString("No sync attempts since the daemon started.\n")
+		return output.String()
+	}
+
+	for _, a := range result.Attempts {
+		started := a.StartedAt
+		if t, err := time.Parse(time.RFC3339Nano, a.StartedAt); err == nil {
+			started = t.Local().Format("2006-01-02 15:04:05")
+		}
+		status := "✓"
+		if a.Error != "" {
+			status = "✗"
+		}
+		fmt.Fprintf(&output, "%s %s  %-4s  fetched %d, pushed %d  (%dms)\n",
+			status, started, a.Direction, a.Fetched, a.Pushed, a.DurationMS)
+		for _, r := range a.Retries {
+			fmt.Fprintf(&output, "    push %d rejected, retried after %dms\n", r.Attempt, r.DelayMS)
+		}
+		if a.Error != "" {
+			fmt.Fprintf(&output, "    error: %s\n", a.Error)
+		}
+	}
+
+	return output.String()
+}
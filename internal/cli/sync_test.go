@@ -58,7 +58,7 @@ func TestSyncForce(t *testing.T) {
 	defer func() { _ = client.Close() }()
 
 	// Call SyncForce
-	result, err := SyncForce(client)
+	result, err := SyncForce(client, false, false, false)
 	if err != nil {
 		t.Fatalf("SyncForce() error = %v", err)
 	}
@@ -193,6 +193,38 @@ func TestFormatSyncStatus(t *testing.T) {
 			},
 			contains: []string{"error", "connection failed"},
 		},
+		{
+			name: "repeated_failures_unpushed",
+			response: SyncStatusResponse{
+				Running:             true,
+				SyncState:           "error",
+				LastError:           "push rejected",
+				ConsecutiveFailures: 3,
+				PendingPushCount:    func() *int { n := 2; return &n }(),
+			},
+			contains: []string{"Failures:   3 in a row", "Unpushed:   2 commit(s)"},
+		},
+		{
+			name: "paused_until_resume",
+			response: SyncStatusResponse{
+				Running:   true,
+				SyncState: "paused",
+				Paused:    true,
+				PausedAt:  "2026-02-03T12:30:00Z",
+			},
+			contains: []string{"paused", "until 'thrum sync resume'"},
+		},
+		{
+			name: "paused_with_deadline",
+			response: SyncStatusResponse{
+				Running:     true,
+				SyncState:   "paused",
+				Paused:      true,
+				PausedAt:    "2026-02-03T12:30:00Z",
+				PausedUntil: "2026-02-03T12:40:00Z",
+			},
+			contains: []string{"paused", "Paused:     until 2026-02-03"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -206,3 +238,48 @@ func TestFormatSyncStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatSyncLog(t *testing.T) {
+	empty := FormatSyncLog(&SyncLogResponse{Capacity: 50})
+	for _, substr := range []string{"in memory", "cleared on daemon restart", "No sync attempts"} {
+		if !contains(empty, substr) {
+			t.Errorf("empty log should contain %q, got:\n%s", substr, empty)
+		}
+	}
+
+	output := FormatSyncLog(&SyncLogResponse{
+		Capacity: 50,
+		Attempts: []SyncLogEntry{
+			{StartedAt: "2026-02-03T12:31:00Z", Direction: "push", Error: "commit and push: pushing: timeout"},
+			{StartedAt: "2026-02-03T12:30:00Z", Direction: "both", Fetched: 3, Pushed: 2, DurationMS: 840,
+				Retries: []SyncLogRetry{{Attempt: 1, DelayMS: 312, Error: "push failed: non-fast-forward"}}},
+		},
+	})
+	for _, substr := range []string{"✗", "error: commit and push: pushing: timeout", "✓", "fetched 3, pushed 2", "(840ms)", "push 1 rejected, retried after 312ms"} {
+		if !contains(output, substr) {
+			t.Errorf("output should contain %q, got:\n%s", substr, output)
+		}
+	}
+}
+
+func TestFormatSyncPause(t *testing.T) {
+	tests := []struct {
+		name     string
+		response SyncPauseResponse
+		want     string
+	}{
+		{"paused_indefinitely", SyncPauseResponse{Paused: true}, "on 'thrum sync resume'"},
+		{"paused_with_duration", SyncPauseResponse{Paused: true, PausedUntil: "2026-02-03T12:40:00Z"}, "Resumes:    2026-02-03"},
+		{"resumed", SyncPauseResponse{WasPaused: true}, "Sync resumed"},
+		{"not_paused", SyncPauseResponse{}, "Sync was not paused"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := FormatSyncPause(&tt.response)
+			if !contains(output, tt.want) {
+				t.Errorf("output should contain %q, got:\n%s", tt.want, output)
+			}
+		})
+	}
+}
@@ -56,8 +56,10 @@ type TeamMember struct {
 	Branch          string       `json:"branch,omitempty"`
 	UnmergedCommits int          `json:"unmerged_commits"`
 	FileChanges     []FileChange `json:"file_changes,omitempty"`
+	DeclaredFiles   []string     `json:"declared_files,omitempty"`
 	InboxTotal      int          `json:"inbox_total"`
 	InboxUnread     int          `json:"inbox_unread"`
+	UnreadCount     int          `json:"unread_count"`
 	Status          string       `json:"status"`
 	TmuxSession     string       `json:"tmux_session,omitempty"`
 	TmuxState       string       `json:"tmux_state,omitempty"`
@@ -105,7 +107,11 @@ func FormatTeam(resp *TeamListResponse) string {
 			Branch:  m.Branch,
 			Status:  m.Status,
 		}
-		out.WriteString(FormatAgentSummaryCompact(summary) + "\n")
+		out.WriteString(FormatAgentSummaryCompact(summary))
+		if m.UnreadCount > 0 {
+			fmt.Fprintf(&out, " (%d unread)", m.UnreadCount)
+		}
+		out.WriteString("\n")
 
 		// PID liveness indicator
 		if m.AgentPID > 0 {
@@ -148,6 +154,12 @@ func FormatTeam(resp *TeamListResponse) string {
 					duration = fmt.Sprintf(" (active %s)", formatDuration(time.Since(t)))
 				}
 			}
+			if m.Status == "away" {
+				duration += " — away"
+				if t, err := time.Parse(time.RFC3339, m.LastSeen); err == nil {
+					duration += fmt.Sprintf(", last heartbeat %s", formatTimeAgo(t))
+				}
+			}
 			fmt.Fprintf(&out, "Session:  %s%s\n", sessionDisplay, duration)
 		} else if m.Status == "offline" {
 			lastSeen := ""
@@ -197,9 +209,17 @@ func FormatTeam(resp *TeamListResponse) string {
 				}
 				fmt.Fprintf(&out, "  %-30s %-8s +%-4d -%d\n", f.Path, timeAgo, f.Additions, f.Deletions)
 			}
-		} else if m.Status == "active" {
+		} else if m.Status == "active" || m.Status == "away" {
 			out.WriteString("Files:    (no changes)\n")
 		}
+
+		// Declared files (session start --files), separate from git-inferred
+		if len(m.DeclaredFiles) > 0 {
+			out.WriteString("Declared:\n")
+			for _, f := range m.DeclaredFiles {
+				fmt.Fprintf(&out, "  %s\n", f)
+			}
+		}
 	}
 
 	// Footer: shared messages (broadcasts + groups)
@@ -215,3 +235,41 @@ func FormatTeam(resp *TeamListResponse) string {
 
 	return out.String()
 }
+
+// FlatTeamMember is one record of `thrum team --json --flat`: the handful of
+// fields dashboards read, with no nesting. Every field is always present;
+// Branch and Intent are null when unset (e.g. for offline agents) so jq
+// filters see a stable shape.
+type FlatTeamMember struct {
+	Agent  string  `json:"agent"`
+	Role   string  `json:"role"`
+	Module string  `json:"module"`
+	Online bool    `json:"online"`
+	Unread int     `json:"unread"`
+	Branch *string `json:"branch"`
+	Intent *string `json:"intent"`
+}
+
+// FlattenTeam converts members to flat records, preserving order. An agent is
+// online when its status is active or away (has a session).
+func FlattenTeam(members []TeamMember) []FlatTeamMember {
+	nullable := func(s string) *string {
+		if s == "" {
+			return nil
+		}
+		return &s
+	}
+	out := make([]FlatTeamMember, 0, len(members))
+	for _, m := range members {
+		out = append(out, FlatTeamMember{
+			Agent:  m.AgentID,
+			Role:   m.Role,
+			Module: m.Module,
+			Online: m.Status == "active" || m.Status == "away",
+			Unread: m.UnreadCount,
+			Branch: nullable(m.Branch),
+			Intent: nullable(m.Intent),
+		})
+	}
+	return out
+}
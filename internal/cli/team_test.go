@@ -118,6 +118,24 @@ func TestFormatTeam_Offline(t *testing.T) {
 	}
 }
 
+func TestFormatTeam_UnreadCount(t *testing.T) {
+	resp := &TeamListResponse{
+		Members: []TeamMember{
+			{AgentID: "backlog", Module: "auth", Status: "offline", UnreadCount: 7},
+			{AgentID: "caught_up", Module: "api", Status: "active"},
+		},
+	}
+
+	result := FormatTeam(resp)
+
+	if !strings.Contains(result, "○ @backlog (auth) (7 unread)\n") {
+		t.Errorf("expected unread count on offline header, got: %s", result)
+	}
+	if !strings.Contains(result, "● @caught_up (api)\n") {
+		t.Errorf("zero unread should leave the header unchanged, got: %s", result)
+	}
+}
+
 func TestFormatTeam_NoChanges(t *testing.T) {
 	resp := &TeamListResponse{
 		Members: []TeamMember{
@@ -140,6 +158,29 @@ func TestFormatTeam_NoChanges(t *testing.T) {
 	}
 }
 
+func TestFormatTeam_DeclaredFiles(t *testing.T) {
+	resp := &TeamListResponse{
+		Members: []TeamMember{
+			{
+				AgentID:       "reviewer",
+				Role:          "reviewer",
+				Module:        "all",
+				SessionID:     "ses_test",
+				Status:        "active",
+				DeclaredFiles: []string{"internal/auth/login.go", "internal/auth/token.go"},
+			},
+		},
+	}
+
+	result := FormatTeam(resp)
+
+	for _, want := range []string{"Files:    (no changes)", "Declared:\n  internal/auth/login.go\n  internal/auth/token.go\n"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("expected %q, got: %s", want, result)
+		}
+	}
+}
+
 func TestFormatTeam_Multiple(t *testing.T) {
 	now := time.Now().UTC()
 	resp := &TeamListResponse{
@@ -452,3 +493,25 @@ func TestTeamMember_JSONRoundtripPreservesOriginDaemon(t *testing.T) {
 		}
 	}
 }
+
+func TestFlattenTeam(t *testing.T) {
+	members := []TeamMember{
+		{AgentID: "alice", Role: "implementer", Module: "auth", Status: "active", UnreadCount: 3, Branch: "feat/auth", Intent: "Fixing login"},
+		{AgentID: "bob", Role: "reviewer", Module: "api", Status: "offline"},
+	}
+
+	data, err := json.Marshal(FlattenTeam(members))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	want := `[{"agent":"alice","role":"implementer","module":"auth","online":true,"unread":3,"branch":"feat/auth","intent":"Fixing login"},` +
+		`{"agent":"bob","role":"reviewer","module":"api","online":false,"unread":0,"branch":null,"intent":null}]`
+	if string(data) != want {
+		t.Errorf("got  %s\nwant %s", data, want)
+	}
+
+	// An empty team is an empty array, not null.
+	if data, _ := json.Marshal(FlattenTeam(nil)); string(data) != "[]" {
+		t.Errorf("empty team = %s, want []", data)
+	}
+}
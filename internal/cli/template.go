@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"text/template"
+	"text/template/parse"
+	"time"
+)
+
+// CompileItemTemplate parses a --template value. An empty value returns a nil
+// template. Commands call it before any RPC so a bad template fails fast.
+func CompileItemTemplate(text string) (*template.Template, error) {
+	if text == "" {
+		return nil, nil
+	}
+	tmpl, err := template.New("item").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// RenderItems executes tmpl once per item and writes each result on its own
+// line. Fields are addressed by their Go names ({{.AgentID}},
+// {{.Body.Content}}); a field that doesn't exist renders empty instead of
+// failing the command.
+func RenderItems[T any](w io.Writer, tmpl *template.Template, items []T) error {
+	names := templateFieldNames(tmpl)
+	var buf bytes.Buffer
+	for _, item := range items {
+		buf.Reset()
+		if err := tmpl.Execute(&buf, templateData(reflect.ValueOf(item), names)); err != nil {
+			return fmt.Errorf("render --template: %w", err)
+		}
+		if _, err := fmt.Fprintln(w, buf.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// templateFieldNames returns every field name tmpl refers to, in any of its
+// associated templates: {{.A.B}}, {{$x.C}} and {{(f).D}}.
+func templateFieldNames(tmpl *template.Template) map[string]bool {
+	names := make(map[string]bool)
+	var walk func(n parse.Node)
+	walkBranch := func(b *parse.BranchNode) {
+		walk(b.Pipe)
+		walk(b.List)
+		walk(b.ElseList)
+	}
+	walk = func(n parse.Node) {
+		switch n := n.(type) {
+		case *parse.ListNode:
+			if n != nil {
+				for _, c := range n.Nodes {
+					walk(c)
+				}
+			}
+		case *parse.PipeNode:
+			if n != nil {
+				for _, c := range n.Cmds {
+					walk(c)
+				}
+			}
+		case *parse.CommandNode:
+			for _, a := range n.Args {
+				walk(a)
+			}
+		case *parse.ActionNode:
+			walk(n.Pipe)
+		case *parse.TemplateNode:
+			walk(n.Pipe)
+		case *parse.IfNode:
+			walkBranch(&n.BranchNode)
+		case *parse.RangeNode:
+			walkBranch(&n.BranchNode)
+		case *parse.WithNode:
+			walkBranch(&n.BranchNode)
+		case *parse.ChainNode:
+			walk(n.Node)
+			for _, f := range n.Field {
+				names[f] = true
+			}
+		case *parse.FieldNode:
+			for _, id := range n.Ident {
+				names[id] = true
+			}
+		case *parse.VariableNode:
+			for _, id := range n.Ident[1:] {
+				names[id] = true
+			}
+		}
+	}
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil {
+			walk(t.Root)
+		}
+	}
+	return names
+}
+
+// templateData converts structs to maps keyed by exported field name. Every
+// name in names the struct lacks is added as "", as are nil values, so an
+// unknown field renders empty rather than as an execution error or
+// text/template's "<no value>". time.Time is kept as-is so its methods stay
+// callable.
+func templateData(v reflect.Value, names map[string]bool) any {
+	if !v.IsValid() {
+		return ""
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return ""
+		}
+		return templateData(v.Elem(), names)
+	case reflect.Struct:
+		if v.Type() == reflect.TypeOf(time.Time{}) {
+			return v.Interface()
+		}
+		out := make(map[string]any, v.NumField())
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			out[field.Name] = templateData(v.Field(i), names)
+		}
+		for name := range names {
+			if _, ok := out[name]; !ok {
+				out[name] = ""
+			}
+		}
+		return out
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return []any(nil)
+		}
+		out := make([]any, v.Len())
+		for i := range out {
+			out[i] = templateData(v.Index(i), names)
+		}
+		return out
+	default:
+		return v.Interface()
+	}
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompileItemTemplate(t *testing.T) {
+	tmpl, err := CompileItemTemplate("")
+	if err != nil || tmpl != nil {
+		t.Errorf("empty template = %v, %v; want nil, nil", tmpl, err)
+	}
+
+	_, err = CompileItemTemplate("{{.AgentID")
+	if err == nil || !strings.Contains(err.Error(), "invalid --template") {
+		t.Errorf("unterminated action: err = %v", err)
+	}
+}
+
+func TestRenderItems(t *testing.T) {
+	var msgs []Message
+	for _, id := range []string{"msg_1", "msg_2"} {
+		var m Message
+		m.MessageID = id
+		m.AgentID = "alice"
+		m.Body.Content = "hello " + id
+		msgs = append(msgs, m)
+	}
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"nested field", "{{.AgentID}}: {{.Body.Content}}", "alice: hello msg_1\nalice: hello msg_2\n"},
+		{"missing field renders empty", "{{.MessageID}}|{{.NoSuchField}}|", "msg_1||\nmsg_2||\n"},
+		{"missing nested field renders empty", "{{.Body.Nope}}.", ".\n.\n"},
+		{"functions work", `{{printf "%-6s" .MessageID}}{{len .Body.Content}}`, "msg_1 11\nmsg_2 11\n"},
+		{"missing field in range and with", "{{with .Body}}{{.Nope}}{{.Content}}{{end}}", "hello msg_1\nhello msg_2\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := CompileItemTemplate(tt.text)
+			if err != nil {
+				t.Fatalf("compile: %v", err)
+			}
+			var b strings.Builder
+			if err := RenderItems(&b, tmpl, msgs); err != nil {
+				t.Fatalf("render: %v", err)
+			}
+			if b.String() != tt.want {
+				t.Errorf("got %q, want %q", b.String(), tt.want)
+			}
+		})
+	}
+
+	// Content that happens to contain text/template's missing-value marker is
+	// printed as-is.
+	literal := []Message{msgs[0]}
+	literal[0].Body.Content = "see <no value> here"
+	tmpl, _ := CompileItemTemplate("{{.Body.Content}}|{{.Missing}}|")
+	var lb strings.Builder
+	if err := RenderItems(&lb, tmpl, literal); err != nil {
+		t.Fatalf("render literal: %v", err)
+	}
+	if lb.String() != "see <no value> here||\n" {
+		t.Errorf("literal = %q, want the content untouched", lb.String())
+	}
+
+	agents := []AgentInfo{{AgentID: "impl", Role: "implementer", Capabilities: []string{"go", "sql"}}}
+	tmpl, _ = CompileItemTemplate(`{{.AgentID}} {{range .Capabilities}}[{{.}}]{{end}}`)
+	var b strings.Builder
+	if err := RenderItems(&b, tmpl, agents); err != nil {
+		t.Fatalf("render agents: %v", err)
+	}
+	if b.String() != "impl [go][sql]\n" {
+		t.Errorf("agents = %q", b.String())
+	}
+}
@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/leonletto/thrum/internal/types"
+)
+
+// ThreadGetResponse represents the response from thread.get RPC.
+type ThreadGetResponse struct {
+	ThreadID     string        `json:"thread_id"`
+	MessageCount int           `json:"message_count"`
+	Roots        []*ThreadNode `json:"roots"`
+}
+
+// ThreadNode is one message in a thread tree, with its replies nested.
+type ThreadNode struct {
+	MessageID   string            `json:"message_id"`
+	AgentID     string            `json:"agent_id,omitempty"`
+	CreatedAt   string            `json:"created_at,omitempty"`
+	Body        types.MessageBody `json:"body"`
+	Deleted     bool              `json:"deleted"`
+	Placeholder bool              `json:"placeholder,omitempty"` // Parent no longer exists
+	Replies     []*ThreadNode     `json:"replies"`
+}
+
+// threadPreviewWidth caps the body preview shown under each tree entry.
+const threadPreviewWidth = 100
+
+// ThreadGet retrieves a thread as a reply tree.
+func ThreadGet(client *Client, threadID string) (*ThreadGetResponse, error) {
+	req := map[string]string{"thread_id": threadID}
+	var resp ThreadGetResponse
+	if err := client.Call("thread.get", req, &resp); err != nil {
+		return nil, fmt.Errorf("thread.get RPC failed: %w", err)
+	}
+	return &resp, nil
+}
+
+// FormatThread renders a thread as an indented tree, two spaces per reply
+// level, with a one-line body preview under each message.
+func FormatThread(resp *ThreadGetResponse) string {
+	var out strings.Builder
+	noun := "messages"
+	if resp.MessageCount == 1 {
+		noun = "message"
+	}
+	fmt.Fprintf(&out, "Thread %s (%d %s)\n\n", resp.ThreadID, resp.MessageCount, noun)
+	for _, root := range resp.Roots {
+		formatThreadNode(&out, root, 0)
+	}
+	return out.String()
+}
+
+func formatThreadNode(out *strings.Builder, n *ThreadNode, depth int) {
+	indent := strings.Repeat("  ", depth)
+	marker, bodyIndent := "", indent+"  "
+	if depth > 0 {
+		marker, bodyIndent = "↳ ", indent+"    "
+	}
+	switch {
+	case n.Placeholder:
+		fmt.Fprintf(out, "%s%s%s  (deleted)\n", indent, marker, n.MessageID)
+	case n.Deleted:
+		fmt.Fprintf(out, "%s%s%s  %s  %s  (deleted)\n", indent, marker, n.MessageID, extractAgentName(n.AgentID), formatRelativeTime(n.CreatedAt))
+	default:
+		fmt.Fprintf(out, "%s%s%s  %s  %s\n", indent, marker, n.MessageID, extractAgentName(n.AgentID), formatRelativeTime(n.CreatedAt))
+		fmt.Fprintf(out, "%s%s\n", bodyIndent, threadPreview(n.Body.Content))
+	}
+	for _, r := range n.Replies {
+		formatThreadNode(out, r, depth+1)
+	}
+}
+
+// threadPreview collapses a body onto one line and truncates it.
+func threadPreview(content string) string {
+	line := strings.Join(strings.Fields(Plain(content)), " ")
+	if r := []rune(line); len(r) > threadPreviewWidth {
+		line = string(r[:threadPreviewWidth-1]) + "…"
+	}
+	return line
+}
@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/leonletto/thrum/internal/types"
+)
+
+func TestFormatThread(t *testing.T) {
+	resp := &ThreadGetResponse{
+		ThreadID:     "thr_1",
+		MessageCount: 3,
+		Roots: []*ThreadNode{
+			{MessageID: "msg_gone", Deleted: true, Placeholder: true, Replies: []*ThreadNode{
+				{MessageID: "msg_orphan", AgentID: "ops", Body: types.MessageBody{Content: "still here"}},
+			}},
+			{MessageID: "msg_root", AgentID: "planner", Body: types.MessageBody{Content: "kickoff\n\nplan below"}, Replies: []*ThreadNode{
+				{MessageID: "msg_reply", AgentID: "ops", Body: types.MessageBody{Content: strings.Repeat("x", 150)}},
+			}},
+		},
+	}
+
+	out := FormatThread(resp)
+	lines := strings.Split(out, "\n")
+	want := []string{
+		"Thread thr_1 (3 messages)",
+		"msg_gone  (deleted)",
+		"  ↳ msg_orphan  @ops",
+		"      still here",
+		"msg_root  @planner",
+		"  kickoff plan below",
+		"  ↳ msg_reply  @ops",
+		"      " + strings.Repeat("x", 99) + "…",
+	}
+	i := 0
+	for _, line := range lines {
+		if i < len(want) && strings.HasPrefix(line, want[i]) {
+			i++
+		}
+	}
+	if i != len(want) {
+		t.Errorf("missing or out of order: %q\n\nfull output:\n%s", want[i], out)
+	}
+}
@@ -173,6 +173,9 @@ func Wait(socketPath string, opts WaitOptions) (*Message, error) {
 				}
 			}
 			listParams["exclude_self"] = true
+			// A muted agent (agent mute) is only woken by what the mute
+			// lets through; the messages still land in its inbox.
+			listParams["respect_mute"] = true
 
 			if err := client.Call("message.list", listParams, &inbox); err != nil {
 				// Connection failed — daemon may have restarted.
@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"slices"
 	"strings"
+	"time"
 )
 
 // ThrumConfig represents the top-level .thrum/config.json file.
@@ -22,6 +23,7 @@ type ThrumConfig struct {
 	Nudge         NudgeConfig         `json:"nudge,omitzero"` // omitzero: drop block when all fields default
 	Worktrees     WorktreesConfig     `json:"worktrees,omitempty"`
 	Orchestration OrchestrationConfig `json:"orchestration,omitempty"`
+	Agents        AgentsConfig        `json:"agents,omitempty"`
 
 	// IdentityGuard is the per-guard enforcement matrix. RawMessage to
 	// avoid an import cycle; internal/identity/guard parses it at load.
@@ -72,6 +74,12 @@ type OrchestrationConfig struct {
 	DefaultAutonomy string `json:"default_autonomy,omitempty"`
 }
 
+// AgentsConfig holds defaults for `thrum agent register --from-git`.
+type AgentsConfig struct {
+	DefaultRole string   `json:"default_role,omitempty"` // role when neither --role nor THRUM_ROLE is set
+	ModuleDirs  []string `json:"module_dirs,omitempty"`  // container dirs whose children are modules; empty uses identity.DefaultModuleDirs
+}
+
 // TelegramConfig holds Telegram bridge settings.
 // The bridge is disabled when Token is empty.
 type TelegramConfig struct {
@@ -262,7 +270,17 @@ type DaemonConfig struct {
 	LogLevel                  string      `json:"log_level,omitempty"`                    // "debug", "info", "warn", "error"; default "info"
 	EventsRetentionDays       int         `json:"events_retention_days,omitempty"`        // retention window for .thrum/events.jsonl + SQLite events table (default 2)
 	CompactionSizeThresholdMB int         `json:"compaction_size_threshold_mb,omitempty"` // per-file size threshold above which compaction rewrites the file (default 10)
+	MetricsEnabled            bool        `json:"metrics_enabled,omitempty"`              // serve Prometheus text metrics at GET /metrics on the WebSocket port (loopback clients only)
+	HTTPGatewayEnabled        bool        `json:"http_gateway_enabled,omitempty"`         // serve POST /rpc/{method} (plain HTTP+JSON) on the WebSocket port (loopback clients only)
+	HTTPGatewayMethods        []string    `json:"http_gateway_methods,omitempty"`         // sync.*/peer.*/pair.* methods the HTTP gateway exposes anyway (default: none)
 	MaxMessageBodyBytes       int         `json:"max_message_body_bytes,omitempty"`       // hard cap on a single message.create body.content size at write (default 1 MB; thrum-mhwt). 0 = use default. Negative = disable cap (operator override). Applies to LOCAL writes only: message.send and message.edit RPCs are gated; peer-synced events arriving via sync_apply.go are NOT (they were already committed on the originating peer and the projector applies them unconditionally — a peer with a higher cap can still land oversized bodies in our local DB).
+	SyncPushRetries           int         `json:"sync_push_retries,omitempty"`            // retries after a rejected (non-fast-forward) sync push, each after fetch+merge (default 3). 0 = use default. Negative = no retries.
+	SyncPushRetryDelayMS      int         `json:"sync_push_retry_delay_ms,omitempty"`     // base backoff before the first push retry in milliseconds, doubled per retry (default 500). 0 = use default.
+	IdleThreshold             string      `json:"idle_threshold,omitempty"`               // Go duration after the last heartbeat at which an agent with an open session is reported "away" (default "5m"). "0" or negative = never away.
+	SendRateLimitPerMinute    int         `json:"send_rate_limit_per_minute,omitempty"`   // sustained message.send rate allowed per agent. 0 (default) or negative = no limit.
+	SendRateLimitBurst        int         `json:"send_rate_limit_burst,omitempty"`        // sends an agent may make back to back before the per-minute rate applies (default: the per-minute rate).
+	CleanupInterval           string      `json:"cleanup_interval,omitempty"`             // Go duration between cleanup passes that drop stale work contexts and delete expired (send --ttl) messages (default "10m"). "0" or negative = run once at startup only.
+	GCInterval                string      `json:"gc_interval,omitempty"`                  // Go duration between automatic gc passes (the same work as thrum daemon gc). Unset (default), "0" or negative = off. A pass is skipped while any peer is behind.
 }
 
 // DefaultMaxMessageBodyBytes bounds a single message body at 1 MB. Above
@@ -283,6 +301,89 @@ func (d DaemonConfig) MaxMessageBodyBytesEffective() int {
 	return d.MaxMessageBodyBytes
 }
 
+// Sync push retry defaults: a rejected push is retried three times, backing
+// off from half a second. Enough to ride out two daemons racing to push.
+const (
+	DefaultSyncPushRetries      = 3
+	DefaultSyncPushRetryDelayMS = 500
+)
+
+// SyncPushRetriesEffective returns the configured push retry count or
+// DefaultSyncPushRetries when unset. Negative values disable retries.
+func (d DaemonConfig) SyncPushRetriesEffective() int {
+	switch {
+	case d.SyncPushRetries == 0:
+		return DefaultSyncPushRetries
+	case d.SyncPushRetries < 0:
+		return 0
+	}
+	return d.SyncPushRetries
+}
+
+// SyncPushRetryDelayEffective returns the configured base push retry delay,
+// or DefaultSyncPushRetryDelayMS when unset or negative.
+func (d DaemonConfig) SyncPushRetryDelayEffective() time.Duration {
+	if d.SyncPushRetryDelayMS <= 0 {
+		return DefaultSyncPushRetryDelayMS * time.Millisecond
+	}
+	return time.Duration(d.SyncPushRetryDelayMS) * time.Millisecond
+}
+
+// DefaultIdleThreshold is how long an agent's session may go without a
+// heartbeat before ping and team report it "away" instead of "active".
+const DefaultIdleThreshold = 5 * time.Minute
+
+// IdleThresholdEffective returns the configured idle threshold, or
+// DefaultIdleThreshold when unset or unparseable. Zero or negative disables
+// away detection and returns 0.
+func (d DaemonConfig) IdleThresholdEffective() time.Duration {
+	if d.IdleThreshold == "" {
+		return DefaultIdleThreshold
+	}
+	v, err := time.ParseDuration(d.IdleThreshold)
+	if err != nil {
+		return DefaultIdleThreshold
+	}
+	if v <= 0 {
+		return 0
+	}
+	return v
+}
+
+// SendRateLimitEffective returns the per-agent message.send rate and burst.
+// Rate limiting is off (0, 0) unless send_rate_limit_per_minute is positive;
+// an unset or non-positive burst defaults to the per-minute rate.
+func (d DaemonConfig) SendRateLimitEffective() (perMinute, burst int) {
+	if d.SendRateLimitPerMinute <= 0 {
+		return 0, 0
+	}
+	if d.SendRateLimitBurst <= 0 {
+		return d.SendRateLimitPerMinute, d.SendRateLimitPerMinute
+	}
+	return d.SendRateLimitPerMinute, d.SendRateLimitBurst
+}
+
+// DefaultCleanupInterval is how often the daemon drops stale work contexts
+// and deletes expired messages.
+const DefaultCleanupInterval = 10 * time.Minute
+
+// CleanupIntervalEffective returns the configured cleanup interval, or
+// DefaultCleanupInterval when unset or unparseable. Zero or negative
+// disables the periodic pass and returns 0.
+func (d DaemonConfig) CleanupIntervalEffective() time.Duration {
+	if d.CleanupInterval == "" {
+		return DefaultCleanupInterval
+	}
+	v, err := time.ParseDuration(d.CleanupInterval)
+	if err != nil {
+		return DefaultCleanupInterval
+	}
+	if v <= 0 {
+		return 0
+	}
+	return v
+}
+
 // BackupConfig holds backup-related settings.
 type BackupConfig struct {
 	Dir        string          `json:"dir,omitempty"`
@@ -454,7 +555,13 @@ func LoadThrumConfig(thrumDir string) (*ThrumConfig, error) {
 		}
 		return nil, err
 	}
+	return decodeThrumConfig(data)
+}
 
+// decodeThrumConfig parses config.json contents, applies defaults, and
+// validates the result. Shared by LoadThrumConfig and SetThrumConfigValue so
+// a value written by `thrum config set` is checked exactly as it will be read.
+func decodeThrumConfig(data []byte) (*ThrumConfig, error) {
 	// thrum-1k00: detect whether the "peers" key is present in the raw
 	// JSON. A stanza present with zero-values is distinguishable from
 	// an absent stanza only at the raw JSON level — json.Unmarshal
@@ -675,3 +782,16 @@ func (cfg *ThrumConfig) RemovePlugin(name string) bool {
 	}
 	return false
 }
+
+// GCIntervalEffective returns the configured gc interval, or 0 (no periodic
+// gc) when unset, unparseable, zero or negative.
+func (d DaemonConfig) GCIntervalEffective() time.Duration {
+	if d.GCInterval == "" {
+		return 0
+	}
+	v, err := time.ParseDuration(d.GCInterval)
+	if err != nil || v <= 0 {
+		return 0
+	}
+	return v
+}
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/leonletto/thrum/internal/config"
 )
@@ -912,6 +913,107 @@ func TestDaemonConfig_MaxMessageBodyBytesEffective(t *testing.T) {
 	}
 }
 
+// TestDaemonConfig_SyncPushRetryEffective: 0 → defaults; negative retries
+// → no retries; negative delay → default delay.
+func TestDaemonConfig_SyncPushRetryEffective(t *testing.T) {
+	cases := []struct {
+		name        string
+		in          config.DaemonConfig
+		wantRetries int
+		wantDelay   time.Duration
+	}{
+		{"zero_returns_defaults", config.DaemonConfig{}, config.DefaultSyncPushRetries, config.DefaultSyncPushRetryDelayMS * time.Millisecond},
+		{"positive_returns_self", config.DaemonConfig{SyncPushRetries: 5, SyncPushRetryDelayMS: 200}, 5, 200 * time.Millisecond},
+		{"negative_disables_retries", config.DaemonConfig{SyncPushRetries: -1, SyncPushRetryDelayMS: -1}, 0, config.DefaultSyncPushRetryDelayMS * time.Millisecond},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.in.SyncPushRetriesEffective(); got != tt.wantRetries {
+				t.Errorf("SyncPushRetriesEffective() = %d, want %d", got, tt.wantRetries)
+			}
+			if got := tt.in.SyncPushRetryDelayEffective(); got != tt.wantDelay {
+				t.Errorf("SyncPushRetryDelayEffective() = %v, want %v", got, tt.wantDelay)
+			}
+		})
+	}
+}
+
+func TestDaemonConfig_IdleThresholdEffective(t *testing.T) {
+	cases := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"", config.DefaultIdleThreshold},
+		{"10m", 10 * time.Minute},
+		{"90s", 90 * time.Second},
+		{"0", 0},
+		{"-1m", 0},
+		{"soon", config.DefaultIdleThreshold},
+	}
+	for _, tt := range cases {
+		if got := (config.DaemonConfig{IdleThreshold: tt.in}).IdleThresholdEffective(); got != tt.want {
+			t.Errorf("IdleThresholdEffective(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDaemonConfig_SendRateLimitEffective(t *testing.T) {
+	cases := []struct {
+		perMinute, burst         int
+		wantPerMinute, wantBurst int
+	}{
+		{0, 0, 0, 0},
+		{0, 10, 0, 0},
+		{-5, 10, 0, 0},
+		{60, 0, 60, 60},
+		{60, -1, 60, 60},
+		{60, 10, 60, 10},
+	}
+	for _, tt := range cases {
+		d := config.DaemonConfig{SendRateLimitPerMinute: tt.perMinute, SendRateLimitBurst: tt.burst}
+		if pm, b := d.SendRateLimitEffective(); pm != tt.wantPerMinute || b != tt.wantBurst {
+			t.Errorf("SendRateLimitEffective(%d, %d) = (%d, %d), want (%d, %d)",
+				tt.perMinute, tt.burst, pm, b, tt.wantPerMinute, tt.wantBurst)
+		}
+	}
+}
+
+func TestDaemonConfig_CleanupIntervalEffective(t *testing.T) {
+	cases := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"", config.DefaultCleanupInterval},
+		{"1h", time.Hour},
+		{"0", 0},
+		{"-1m", 0},
+		{"often", config.DefaultCleanupInterval},
+	}
+	for _, tt := range cases {
+		if got := (config.DaemonConfig{CleanupInterval: tt.in}).CleanupIntervalEffective(); got != tt.want {
+			t.Errorf("CleanupIntervalEffective(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDaemonConfig_GCIntervalEffective(t *testing.T) {
+	cases := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"", 0},
+		{"24h", 24 * time.Hour},
+		{"0", 0},
+		{"-1h", 0},
+		{"daily", 0},
+	}
+	for _, tt := range cases {
+		if got := (config.DaemonConfig{GCInterval: tt.in}).GCIntervalEffective(); got != tt.want {
+			t.Errorf("GCIntervalEffective(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
 func TestNudgeConfig_SilenceGate(t *testing.T) {
 	cases := []struct {
 		name        string
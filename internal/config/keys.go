@@ -0,0 +1,224 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// configKey describes one dotted key addressable by `thrum config get/set`.
+// path holds the JSON field names from the ThrumConfig root; index holds the
+// matching struct field indexes for reflection.
+type configKey struct {
+	path  []string
+	index []int
+	typ   reflect.Type
+}
+
+// readOnlySections are written by the daemon and may be read but not set.
+var readOnlySections = map[string]bool{"identity": true}
+
+var configKeys = func() map[string]configKey {
+	keys := make(map[string]configKey)
+	collectConfigKeys(reflect.TypeFor[ThrumConfig](), nil, nil, keys)
+	return keys
+}()
+
+// collectConfigKeys walks t's JSON-tagged fields, recording every scalar (or
+// scalar slice) leaf. Slices of structs and raw JSON blocks are skipped —
+// they have no single-value text form and are edited in the file directly.
+func collectConfigKeys(t reflect.Type, path []string, index []int, keys map[string]configKey) {
+	for i := range t.NumField() {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "" || name == "-" || !f.IsExported() {
+			continue
+		}
+		p := append(append([]string(nil), path...), name)
+		idx := append(append([]int(nil), index...), i)
+
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer && ft.Elem().Kind() == reflect.Struct {
+			collectConfigKeys(ft.Elem(), p, idx, keys)
+			continue
+		}
+		if ft.Kind() == reflect.Struct {
+			collectConfigKeys(ft, p, idx, keys)
+			continue
+		}
+		if configValueKind(ft) == reflect.Invalid {
+			continue
+		}
+		keys[strings.Join(p, ".")] = configKey{path: p, index: idx, typ: ft}
+	}
+}
+
+// configValueKind returns the scalar kind a settable field holds, looking
+// through one level of pointer or slice, or reflect.Invalid when the field
+// has no text form.
+func configValueKind(t reflect.Type) reflect.Kind {
+	if t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool, reflect.Int, reflect.Int64:
+		return t.Kind()
+	}
+	return reflect.Invalid
+}
+
+// ThrumConfigKeys returns every dotted key accepted by GetThrumConfigValue,
+// sorted.
+func ThrumConfigKeys() []string {
+	keys := make([]string, 0, len(configKeys))
+	for k := range configKeys {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func lookupConfigKey(key string) (configKey, error) {
+	k, ok := configKeys[key]
+	if !ok {
+		return configKey{}, fmt.Errorf("unknown config key %q; valid keys:\n  %s", key, strings.Join(ThrumConfigKeys(), "\n  "))
+	}
+	return k, nil
+}
+
+// GetThrumConfigValue returns the effective value of a dotted key (e.g.
+// "daemon.log_level") as text. Defaults applied by LoadThrumConfig are
+// included; unset optional values return "". Slices are comma-separated.
+func GetThrumConfigValue(cfg *ThrumConfig, key string) (string, error) {
+	k, err := lookupConfigKey(key)
+	if err != nil {
+		return "", err
+	}
+	v := reflect.ValueOf(cfg).Elem()
+	for _, i := range k.index {
+		if v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return "", nil
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+	return formatConfigValue(v), nil
+}
+
+func formatConfigValue(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return ""
+		}
+		return formatConfigValue(v.Elem())
+	case reflect.Slice:
+		parts := make([]string, v.Len())
+		for i := range parts {
+			parts[i] = formatConfigValue(v.Index(i))
+		}
+		return strings.Join(parts, ",")
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	default:
+		return v.String()
+	}
+}
+
+// parseConfigValue converts text to the JSON value stored for a key of type
+// t. Slices take a comma-separated list; an empty string clears them.
+func parseConfigValue(key string, t reflect.Type, text string) (any, error) {
+	if t.Kind() == reflect.Slice {
+		items := []any{}
+		if strings.TrimSpace(text) == "" {
+			return items, nil
+		}
+		for part := range strings.SplitSeq(text, ",") {
+			item, err := parseConfigValue(key, t.Elem(), strings.TrimSpace(part))
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	}
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return nil, fmt.Errorf("%s expects true or false, got %q", key, text)
+		}
+		return b, nil
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s expects an integer, got %q", key, text)
+		}
+		return n, nil
+	default:
+		return text, nil
+	}
+}
+
+// SetThrumConfigValue sets a dotted key in thrumDir/config.json. Only the
+// addressed value changes; every other key in the file, including ones this
+// build doesn't know, is preserved. The result must load cleanly (e.g. pass
+// ValidateSync) or nothing is written.
+func SetThrumConfigValue(thrumDir, key, text string) error {
+	k, err := lookupConfigKey(key)
+	if err != nil {
+		return err
+	}
+	if readOnlySections[k.path[0]] {
+		return fmt.Errorf("%s is managed by the daemon and cannot be set", key)
+	}
+	value, err := parseConfigValue(key, k.typ, text)
+	if err != nil {
+		return err
+	}
+
+	configPath := filepath.Join(thrumDir, "config.json")
+	root := make(map[string]any)
+	data, err := os.ReadFile(configPath) // #nosec G304 -- configPath is .thrum/config.json, an internal config file
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, &root); err != nil {
+			return fmt.Errorf("parse %s: %w", configPath, err)
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return err
+	}
+
+	section := root
+	for _, name := range k.path[:len(k.path)-1] {
+		next, ok := section[name].(map[string]any)
+		if !ok {
+			next = make(map[string]any)
+			section[name] = next
+		}
+		section = next
+	}
+	section[k.path[len(k.path)-1]] = value
+
+	data, err = json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return err
+	}
+	if _, err := decodeThrumConfig(data); err != nil {
+		return fmt.Errorf("invalid value for %s: %w", key, err)
+	}
+	return os.WriteFile(configPath, append(data, '\n'), 0600)
+}
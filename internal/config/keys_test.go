@@ -0,0 +1,120 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/leonletto/thrum/internal/config"
+)
+
+func TestThrumConfigKeys(t *testing.T) {
+	keys := config.ThrumConfigKeys()
+	for _, want := range []string{
+		"daemon.log_level", "daemon.sync.enabled", "backup.retention.daily",
+		"telegram.allow_from", "permission_supervisors", "runtime.primary",
+	} {
+		if !slices.Contains(keys, want) {
+			t.Errorf("keys missing %q", want)
+		}
+	}
+	// Struct slices and raw JSON have no single-value form.
+	for _, skip := range []string{"telegram.groups", "daemon.sync.mechanisms", "identity_guard"} {
+		if slices.Contains(keys, skip) {
+			t.Errorf("keys should not include %q", skip)
+		}
+	}
+}
+
+func TestSetThrumConfigValue(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(path, []byte(`{"daemon":{"ws_port":"9999"},"future_key":{"x":1}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	for key, value := range map[string]string{
+		"daemon.events_retention_days": "7",
+		"peers.auto_connect":           "false",
+		"backup.retention.monthly":     "12",
+		"telegram.allow_from":          "1, 2",
+		"nudge.chrome_quiet_seconds":   "-1",
+	} {
+		if err := config.SetThrumConfigValue(dir, key, value); err != nil {
+			t.Fatalf("set %s: %v", key, err)
+		}
+	}
+
+	cfg, err := config.LoadThrumConfig(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Daemon.WSPort != "9999" || cfg.Daemon.EventsRetentionDays != 7 || cfg.Peers.AutoConnect ||
+		cfg.Backup.Retention.RetentionMonthly() != 12 || !slices.Equal(cfg.Telegram.AllowFrom, []int64{1, 2}) ||
+		cfg.Nudge.ChromeQuietSeconds != -1 {
+		t.Errorf("config after set = %+v", cfg)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "future_key") {
+		t.Error("set dropped a key it does not manage")
+	}
+
+	for key, value := range map[string]string{
+		"daemon.events_retention_days": "seven",
+		"peers.auto_connect":           "maybe",
+		"daemon.sync_interval":         "15",
+		"identity.daemon_id":           "d_x",
+	} {
+		if err := config.SetThrumConfigValue(dir, key, value); err == nil {
+			t.Errorf("set %s=%s should fail", key, value)
+		}
+	}
+}
+
+func TestSetThrumConfigValue_UnknownKeyListsValid(t *testing.T) {
+	err := config.SetThrumConfigValue(t.TempDir(), "daemon.nope", "1")
+	if err == nil || !strings.Contains(err.Error(), "daemon.log_level") {
+		t.Errorf("err = %v, want list of valid keys", err)
+	}
+}
+
+func TestSetThrumConfigValue_RejectsInvalidResult(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	original := `{"daemon":{"sync":{"enabled":false,"mechanisms":[{"mechanism":"a-sync","scope":"directed"}]}}}`
+	if err := os.WriteFile(path, []byte(original), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.SetThrumConfigValue(dir, "daemon.sync.enabled", "true"); err == nil {
+		t.Fatal("enabling an invalid sync stanza should fail")
+	}
+	if data, _ := os.ReadFile(path); string(data) != original {
+		t.Errorf("config.json changed after a rejected set: %s", data)
+	}
+}
+
+func TestGetThrumConfigValue(t *testing.T) {
+	dir := t.TempDir()
+	if err := config.SetThrumConfigValue(dir, "permission_supervisors", "coordinator,@coordinator_main"); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.LoadThrumConfig(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[string]string{
+		"permission_supervisors":   "coordinator,@coordinator_main",
+		"daemon.log_level":         config.DefaultLogLevel,
+		"backup.retention.daily":   "5",
+		"telegram.enabled":         "",
+		"daemon.sync.enabled":      "true",
+		"daemon.single_agent_mode": "false",
+	} {
+		got, err := config.GetThrumConfigValue(cfg, key)
+		if err != nil || got != want {
+			t.Errorf("get %s = %q, %v; want %q", key, got, err, want)
+		}
+	}
+}
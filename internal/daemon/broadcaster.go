@@ -70,6 +70,18 @@ func (b *Broadcaster) Notify(sessionID string, notification any) error {
 	return lastErr
 }
 
+// BroadcastAll sends a notification to every connected WebSocket client,
+// including ones that never registered a session. Unix socket clients are
+// only reachable by session ID, so they don't receive it.
+func (b *Broadcaster) BroadcastAll(notification any) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if all, ok := b.wsClients.(interface{ BroadcastAll(notification any) }); ok {
+		all.BroadcastAll(notification)
+	}
+}
+
 // getNotificationMethod extracts the method name from the notification payload.
 func getNotificationMethod(notification map[string]any) string {
 	if method, ok := notification["method"].(string); ok {
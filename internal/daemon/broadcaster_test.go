@@ -181,3 +181,27 @@ func TestConvertToNotifyParams_InvalidParams(t *testing.T) {
 		t.Errorf("Expected empty message_id, got %s", result.MessageID)
 	}
 }
+
+// mockWSBroadcaster is a mockWSClientNotifier that can reach every client.
+type mockWSBroadcaster struct {
+	*mockWSClientNotifier
+	broadcasts []any
+}
+
+func (m *mockWSBroadcaster) BroadcastAll(notification any) {
+	m.broadcasts = append(m.broadcasts, notification)
+}
+
+func TestBroadcaster_BroadcastAll(t *testing.T) {
+	notification := map[string]any{"method": "notification.context.updated"}
+
+	wsClients := &mockWSBroadcaster{mockWSClientNotifier: newMockWSClientNotifier()}
+	NewBroadcaster(nil, wsClients).BroadcastAll(notification)
+	if len(wsClients.broadcasts) != 1 {
+		t.Fatalf("Expected 1 broadcast, got %d", len(wsClients.broadcasts))
+	}
+
+	// A registry that can only notify by session is skipped, not panicked on.
+	NewBroadcaster(nil, newMockWSClientNotifier()).BroadcastAll(notification)
+	NewBroadcaster(nil, nil).BroadcastAll(notification)
+}
@@ -0,0 +1,99 @@
+package cleanup
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/leonletto/thrum/internal/daemon/state"
+	"github.com/leonletto/thrum/internal/types"
+)
+
+// ExpireMessages soft-deletes TTL messages (send --ttl) whose expires_at has
+// passed by writing a message.delete event with reason "expired" for each.
+// Only messages by local or unknown authors are expired here: a peer
+// daemon expires its own agents' messages and the deletes sync over, so
+// two daemons never race to delete the same message. Returns the number
+// of messages deleted.
+func ExpireMessages(ctx context.Context, st *state.State, now time.Time) (int, error) {
+	rows, err := st.DB().QueryContext(ctx, `
+		SELECT m.message_id FROM messages m
+		WHERE m.expires_at IS NOT NULL AND m.expires_at <= ? AND m.deleted = 0
+		  AND NOT EXISTS (
+			SELECT 1 FROM agents a
+			WHERE a.agent_id = m.agent_id AND a.origin_daemon != '' AND a.origin_daemon != ?
+		  )
+		ORDER BY m.expires_at
+	`, now.UTC().Format(time.RFC3339), st.DaemonID())
+	if err != nil {
+		return 0, fmt.Errorf("query expired messages: %w", err)
+	}
+	var expired []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			_ = rows.Close()
+			return 0, fmt.Errorf("scan expired message: %w", err)
+		}
+		expired = append(expired, id)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("iterate expired messages: %w", err)
+	}
+
+	deleted := 0
+	for _, id := range expired {
+		event := types.MessageDeleteEvent{
+			Type:      "message.delete",
+			Timestamp: now.UTC().Format(time.RFC3339Nano),
+			MessageID: id,
+			Reason:    "expired",
+		}
+		st.Lock()
+		postCommit, err := st.WriteEvent(ctx, event)
+		st.Unlock()
+		if err != nil {
+			return deleted, fmt.Errorf("write message.delete for %s: %w", id, err)
+		}
+		st.GoPostCommit(postCommit)
+		deleted++
+	}
+	return deleted, nil
+}
+
+// Run performs one cleanup pass: stale work contexts, then expired
+// messages. Failures are logged, not returned, so one step failing does
+// not skip the other.
+func Run(ctx context.Context, st *state.State, now time.Time) {
+	if deleted, err := CleanupStaleContexts(ctx, st.DB(), now); err != nil {
+		slog.Warn("[cleanup] stale work context cleanup failed", "err", err)
+	} else if deleted > 0 {
+		slog.Info("[cleanup] removed stale work contexts", "count", deleted)
+	}
+	if deleted, err := ExpireMessages(ctx, st, now); err != nil {
+		slog.Warn("[cleanup] message expiry failed", "err", err)
+	} else if deleted > 0 {
+		slog.Info("[cleanup] deleted expired messages", "count", deleted)
+	}
+}
+
+// Start runs a cleanup pass immediately and then every interval until ctx
+// is canceled. interval <= 0 runs the startup pass only.
+func Start(ctx context.Context, st *state.State, interval time.Duration) {
+	Run(ctx, st, time.Now().UTC())
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			Run(ctx, st, time.Now().UTC())
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package cleanup_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/leonletto/thrum/internal/daemon/cleanup"
+	"github.com/leonletto/thrum/internal/daemon/state"
+)
+
+func TestExpireMessages(t *testing.T) {
+	tmpDir := t.TempDir()
+	syncDir := filepath.Join(tmpDir, "sync")
+	if err := os.MkdirAll(syncDir, 0o750); err != nil {
+		t.Fatalf("create sync dir: %v", err)
+	}
+	const localDaemonID = "d_local_01"
+	st, err := state.NewState(filepath.Join(tmpDir, ".thrum"), syncDir, "repo_expire", localDaemonID)
+	if err != nil {
+		t.Fatalf("create state: %v", err)
+	}
+	defer func() { _ = st.Close() }()
+
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Minute).Format(time.RFC3339)
+	future := now.Add(time.Hour).Format(time.RFC3339)
+
+	db := st.RawDB()
+	if _, err := db.ExecContext(ctx, `INSERT INTO agents
+		(agent_id, kind, role, module, origin_daemon, registered_at)
+		VALUES ('agent_local', 'agent', 'worker', 'test', ?, ?),
+		       ('agent_remote', 'agent', 'worker', 'test', 'd_peer_02', ?)`,
+		localDaemonID, past, past); err != nil {
+		t.Fatalf("insert agents: %v", err)
+	}
+	for _, m := range []struct {
+		id, agent string
+		expires   any
+	}{
+		{"msg_expired", "agent_local", past},
+		{"msg_live", "agent_local", future},
+		{"msg_no_ttl", "agent_local", nil},
+		{"msg_remote", "agent_remote", past},
+	} {
+		if _, err := db.ExecContext(ctx, `INSERT INTO messages
+			(message_id, agent_id, session_id, created_at, body_format, body_content, expires_at)
+			VALUES (?, ?, 'ses_x', ?, 'markdown', 'hi', ?)`, m.id, m.agent, past, m.expires); err != nil {
+			t.Fatalf("insert %s: %v", m.id, err)
+		}
+	}
+
+	n, err := cleanup.ExpireMessages(ctx, st, now)
+	if err != nil {
+		t.Fatalf("ExpireMessages: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("deleted = %d, want 1", n)
+	}
+
+	deleted := func(id string) bool {
+		t.Helper()
+		var d int
+		if err := db.QueryRowContext(ctx, `SELECT deleted FROM messages WHERE message_id = ?`, id).Scan(&d); err != nil {
+			t.Fatalf("read %s: %v", id, err)
+		}
+		return d == 1
+	}
+	for id, want := range map[string]bool{
+		"msg_expired": true,
+		"msg_live":    false,
+		"msg_no_ttl":  false,
+		"msg_remote":  false, // the peer daemon that owns the author expires it
+	} {
+		if got := deleted(id); got != want {
+			t.Errorf("%s deleted = %v, want %v", id, got, want)
+		}
+	}
+
+	// A second pass finds nothing left to do.
+	if n, err := cleanup.ExpireMessages(ctx, st, now); err != nil || n != 0 {
+		t.Errorf("second pass = (%d, %v), want (0, nil)", n, err)
+	}
+}
@@ -107,6 +107,23 @@ func (g *dialGate) claim(peerID string) bool {
 	return true
 }
 
+// backoffRemaining reports whether peerID is currently held back and, if so,
+// how long until claim would admit it. Read-only: nothing is reserved.
+func (g *dialGate) backoffRemaining(peerID string) (time.Duration, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	st, ok := g.states[peerID]
+	if !ok {
+		return 0, false
+	}
+	wait := st.nextAttempt.Sub(g.now())
+	if wait <= 0 {
+		return 0, false
+	}
+	return wait, true
+}
+
 // OnSuccess clears all failure state for peerID — a reachable peer is re-admitted
 // immediately (no residual backoff or quarantine).
 func (g *dialGate) OnSuccess(peerID string) {
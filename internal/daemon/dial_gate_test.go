@@ -188,3 +188,25 @@ func TestRecordDialOutcome_SentinelDiscrimination(t *testing.T) {
 		}
 	})
 }
+
+func TestDialGate_BackoffRemaining(t *testing.T) {
+	g, clk := newTestDialGate()
+	if _, held := g.backoffRemaining("peer"); held {
+		t.Fatal("healthy peer must not be held back")
+	}
+
+	g.OnFailure("peer")
+	wait, held := g.backoffRemaining("peer")
+	if !held || wait != dialBackoffBase/2 {
+		t.Fatalf("after one failure: wait=%s held=%v, want %s true", wait, held, dialBackoffBase/2)
+	}
+	// Read-only: asking must not consume the window the way claim does.
+	if again, _ := g.backoffRemaining("peer"); again != wait {
+		t.Errorf("second query = %s, want %s", again, wait)
+	}
+
+	clk.advance(wait)
+	if _, held := g.backoffRemaining("peer"); held {
+		t.Error("peer must be released once the window has passed")
+	}
+}
@@ -0,0 +1,198 @@
+// Package gc reclaims space from a long-lived repo: it snapshots the
+// database and events journal, drops events superseded by later deletes
+// (compact.Compactor.GC), and VACUUMs the database. It backs
+// `thrum daemon gc` (run with the daemon stopped) and the optional periodic
+// pass (daemon.gc_interval).
+//
+// Peers pull our events table by sequence, so an event may only go once
+// every paired peer has pulled past it. Run refuses with ErrPeersBehind
+// unless Options.Force is set.
+package gc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/leonletto/thrum/internal/daemon/safedb"
+	"github.com/leonletto/thrum/internal/daemon/state"
+	"github.com/leonletto/thrum/internal/sync/compact"
+)
+
+// ErrPeersBehind is returned when a paired peer has not yet pulled every
+// event and Options.Force is not set. Result.LaggingPeers lists them.
+var ErrPeersBehind = errors.New("peers have not pulled every event")
+
+// Peer is a paired peer's pull progress (PeerInfo.PulledSequence).
+type Peer struct {
+	Name           string `json:"name"`
+	DaemonID       string `json:"daemon_id"`
+	PulledSequence int64  `json:"pulled_sequence"`
+}
+
+// Options configures Run.
+type Options struct {
+	ThrumDir  string             // .thrum (events.jsonl, var/messages.db)
+	Compactor *compact.Compactor // rewrites the journal and sync state files
+	Peers     []Peer             // every paired peer
+	Force     bool               // run even when a peer is behind
+}
+
+// Result reports what Run did.
+type Result struct {
+	LatestSequence int64    `json:"latest_sequence"`
+	LaggingPeers   []Peer   `json:"lagging_peers,omitempty"`
+	Forced         bool     `json:"forced,omitempty"`
+	Snapshot       []string `json:"snapshot,omitempty"`
+	EventsDropped  int      `json:"events_dropped"`
+	JournalDropped int      `json:"journal_dropped"`
+	DBBytesBefore  int64    `json:"db_bytes_before"`
+	DBBytesAfter   int64    `json:"db_bytes_after"`
+}
+
+// Run snapshots the database and journal, runs Compactor.GC and VACUUMs.
+// Other writers must be kept out: either the daemon is stopped, or (the
+// periodic pass) the state lock is held and opts.Compactor is the daemon's
+// own, whose single-flight gate keeps sync-trigger compaction away.
+//
+// The snapshot is written to var/messages.db.pre-gc.bak and
+// var/events.jsonl.pre-gc.bak, replacing the previous run's, so repeated
+// runs do not pile up copies.
+func Run(ctx context.Context, db *safedb.DB, opts Options) (*Result, error) {
+	res := &Result{}
+
+	if err := db.QueryRowContext(ctx,
+		`SELECT COALESCE(MAX(sequence), 0) FROM events`,
+	).Scan(&res.LatestSequence); err != nil {
+		return nil, fmt.Errorf("load latest sequence: %w", err)
+	}
+	for _, p := range opts.Peers {
+		if p.PulledSequence < res.LatestSequence {
+			res.LaggingPeers = append(res.LaggingPeers, p)
+		}
+	}
+	if len(res.LaggingPeers) > 0 {
+		if !opts.Force {
+			return res, ErrPeersBehind
+		}
+		res.Forced = true
+	}
+
+	varDir := filepath.Join(opts.ThrumDir, "var")
+	dbPath := filepath.Join(varDir, "messages.db")
+
+	snapshot, err := takeSnapshot(ctx, db, varDir, filepath.Join(opts.ThrumDir, "events.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+	res.Snapshot = snapshot
+	res.DBBytesBefore = dbSize(dbPath)
+
+	stats, err := opts.Compactor.GC(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	res.EventsDropped, res.JournalDropped = stats.EventsDropped, stats.JournalDropped
+
+	if _, err := db.ExecContext(ctx, `VACUUM`); err != nil {
+		return nil, fmt.Errorf("vacuum: %w", err)
+	}
+	if _, err := db.ExecContext(ctx, `PRAGMA wal_checkpoint(TRUNCATE)`); err != nil {
+		return nil, fmt.Errorf("checkpoint wal: %w", err)
+	}
+	res.DBBytesAfter = dbSize(dbPath)
+
+	return res, nil
+}
+
+// takeSnapshot copies the database (VACUUM INTO, consistent while open) and
+// the journal into varDir, replacing any earlier snapshot.
+func takeSnapshot(ctx context.Context, db *safedb.DB, varDir, journalPath string) ([]string, error) {
+	dbSnap := filepath.Join(varDir, "messages.db.pre-gc.bak")
+	if err := os.Remove(dbSnap); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("remove old snapshot: %w", err)
+	}
+	if _, err := db.ExecContext(ctx, `VACUUM INTO ?`, dbSnap); err != nil {
+		return nil, fmt.Errorf("snapshot database: %w", err)
+	}
+	snapshot := []string{dbSnap}
+
+	src, err := os.Open(journalPath) // #nosec G304 -- path is the internal events journal
+	if os.IsNotExist(err) {
+		return snapshot, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open journal: %w", err)
+	}
+	defer func() { _ = src.Close() }()
+
+	journalSnap := filepath.Join(varDir, "events.jsonl.pre-gc.bak")
+	dst, err := os.OpenFile(journalSnap, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600) // #nosec G304 -- derived from internal path
+	if err != nil {
+		return nil, fmt.Errorf("create journal snapshot: %w", err)
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		_ = dst.Close()
+		return nil, fmt.Errorf("snapshot journal: %w", err)
+	}
+	if err := dst.Close(); err != nil {
+		return nil, fmt.Errorf("snapshot journal: %w", err)
+	}
+	return append(snapshot, journalSnap), nil
+}
+
+// dbSize is the on-disk size of the database including its WAL.
+func dbSize(path string) int64 {
+	var total int64
+	for _, p := range []string{path, path + "-wal"} {
+		if info, err := os.Stat(p); err == nil {
+			total += info.Size()
+		}
+	}
+	return total
+}
+
+// Start runs a gc pass every interval until ctx is canceled. A pass is
+// skipped, not forced, while any peer is behind or another compaction is
+// running; peers returns the current pull progress of every paired peer.
+// interval <= 0 does nothing.
+func Start(ctx context.Context, st *state.State, thrumDir string, compactor *compact.Compactor, interval time.Duration, peers func() []Peer) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			st.Lock()
+			res, err := Run(ctx, st.DB(), Options{
+				ThrumDir:  thrumDir,
+				Compactor: compactor,
+				Peers:     peers(),
+			})
+			st.Unlock()
+			switch {
+			case errors.Is(err, ErrPeersBehind):
+				slog.Info("[gc] skipped, peers behind", "lagging", len(res.LaggingPeers))
+			case errors.Is(err, compact.ErrInFlight):
+				slog.Info("[gc] skipped, compaction running")
+			case err != nil:
+				slog.Warn("[gc] pass failed", "err", err)
+			default:
+				slog.Info("[gc] pass complete",
+					"events_dropped", res.EventsDropped,
+					"journal_dropped", res.JournalDropped,
+					"db_bytes_before", res.DBBytesBefore,
+					"db_bytes_after", res.DBBytesAfter)
+			}
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package gc_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/leonletto/thrum/internal/daemon/gc"
+	"github.com/leonletto/thrum/internal/daemon/state"
+	"github.com/leonletto/thrum/internal/sync/compact"
+	"github.com/leonletto/thrum/internal/types"
+)
+
+// newGCState returns a state with one message created and edited twice, and
+// a second message created, pinned, bumped and deleted.
+func newGCState(t *testing.T) (*state.State, string) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	thrumDir := filepath.Join(tmpDir, ".thrum")
+	syncDir := filepath.Join(tmpDir, "sync")
+	if err := os.MkdirAll(syncDir, 0o750); err != nil {
+		t.Fatalf("create sync dir: %v", err)
+	}
+	st, err := state.NewState(thrumDir, syncDir, "repo_gc", "d_local_01")
+	if err != nil {
+		t.Fatalf("create state: %v", err)
+	}
+	t.Cleanup(func() { _ = st.Close() })
+
+	ctx := context.Background()
+	ts := time.Now().UTC().Format(time.RFC3339Nano)
+	body := func(s string) types.MessageBody { return types.MessageBody{Format: "markdown", Content: s} }
+	for _, ev := range []any{
+		types.MessageCreateEvent{Type: "message.create", Timestamp: ts, MessageID: "msg_kept", AgentID: "alice", Body: body("v1")},
+		types.MessageEditEvent{Type: "message.edit", Timestamp: ts, MessageID: "msg_kept", Body: body("v2")},
+		types.MessageEditEvent{Type: "message.edit", Timestamp: ts, MessageID: "msg_kept", Body: body("v3")},
+		types.MessageCreateEvent{Type: "message.create", Timestamp: ts, MessageID: "msg_gone", AgentID: "alice", Body: body("bye")},
+		types.MessagePinEvent{Type: "message.pin", Timestamp: ts, MessageID: "msg_gone", AgentID: "alice"},
+		types.MessageBumpEvent{Type: "message.bump", Timestamp: ts, MessageID: "msg_gone", AgentID: "alice"},
+		types.MessageDeleteEvent{Type: "message.delete", Timestamp: ts, MessageID: "msg_gone"},
+	} {
+		if _, err := st.WriteEvent(ctx, ev); err != nil {
+			t.Fatalf("write event: %v", err)
+		}
+	}
+	return st, thrumDir
+}
+
+func runOpts(st *state.State, thrumDir string, peers []gc.Peer, force bool) gc.Options {
+	return gc.Options{
+		ThrumDir:  thrumDir,
+		Compactor: compact.New(thrumDir, st.SyncDir(), 0, 10),
+		Peers:     peers,
+		Force:     force,
+	}
+}
+
+func countEvents(t *testing.T, st *state.State) int {
+	t.Helper()
+	var n int
+	if err := st.RawDB().QueryRow(`SELECT COUNT(*) FROM events`).Scan(&n); err != nil {
+		t.Fatalf("count events: %v", err)
+	}
+	return n
+}
+
+func TestRun_RefusesWhilePeerBehind(t *testing.T) {
+	st, thrumDir := newGCState(t)
+	peers := []gc.Peer{
+		{Name: "laptop", DaemonID: "d_laptop", PulledSequence: 7},
+		{Name: "server", DaemonID: "d_server", PulledSequence: 3},
+	}
+
+	res, err := gc.Run(context.Background(), st.DB(), runOpts(st, thrumDir, peers, false))
+	if !errors.Is(err, gc.ErrPeersBehind) {
+		t.Fatalf("err = %v, want ErrPeersBehind", err)
+	}
+	if res.LatestSequence != 7 {
+		t.Errorf("LatestSequence = %d, want 7", res.LatestSequence)
+	}
+	if len(res.LaggingPeers) != 1 || res.LaggingPeers[0].Name != "server" {
+		t.Errorf("LaggingPeers = %+v, want only server", res.LaggingPeers)
+	}
+	if n := countEvents(t, st); n != 7 {
+		t.Errorf("events = %d after refusal, want all 7 kept", n)
+	}
+	if _, err := os.Stat(filepath.Join(thrumDir, "var", "messages.db.pre-gc.bak")); !os.IsNotExist(err) {
+		t.Errorf("snapshot written despite refusal (stat err %v)", err)
+	}
+}
+
+func TestRun_CaughtUpDropsAndSnapshots(t *testing.T) {
+	st, thrumDir := newGCState(t)
+	peers := []gc.Peer{{Name: "laptop", DaemonID: "d_laptop", PulledSequence: 7}}
+
+	res, err := gc.Run(context.Background(), st.DB(), runOpts(st, thrumDir, peers, false))
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	// msg_gone's pin and bump are superseded by its delete; every edit stays.
+	if res.EventsDropped != 2 || res.JournalDropped != 2 {
+		t.Errorf("dropped %d events / %d journal lines, want 2 / 2", res.EventsDropped, res.JournalDropped)
+	}
+	if n := countEvents(t, st); n != 5 {
+		t.Errorf("events = %d, want 5", n)
+	}
+	if res.Forced {
+		t.Error("Forced set with every peer caught up")
+	}
+	for _, name := range []string{"messages.db.pre-gc.bak", "events.jsonl.pre-gc.bak"} {
+		if _, err := os.Stat(filepath.Join(thrumDir, "var", name)); err != nil {
+			t.Errorf("snapshot %s: %v", name, err)
+		}
+	}
+
+	// A second run replaces the snapshot instead of failing on it.
+	if _, err := gc.Run(context.Background(), st.DB(), runOpts(st, thrumDir, peers, false)); err != nil {
+		t.Fatalf("second Run: %v", err)
+	}
+
+	// The projection is untouched: the message keeps its latest edit.
+	var content string
+	if err := st.RawDB().QueryRow(`SELECT body_content FROM messages WHERE message_id = 'msg_kept'`).Scan(&content); err != nil {
+		t.Fatalf("read message: %v", err)
+	}
+	if content != "v3" {
+		t.Errorf("msg_kept content = %q, want v3", content)
+	}
+}
+
+func TestRun_ForceProceedsPastLaggingPeer(t *testing.T) {
+	st, thrumDir := newGCState(t)
+	peers := []gc.Peer{{Name: "server", DaemonID: "d_server"}}
+
+	res, err := gc.Run(context.Background(), st.DB(), runOpts(st, thrumDir, peers, true))
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !res.Forced || len(res.LaggingPeers) != 1 {
+		t.Errorf("Forced = %v, LaggingPeers = %+v; want forced past server", res.Forced, res.LaggingPeers)
+	}
+	if res.EventsDropped != 2 {
+		t.Errorf("EventsDropped = %d, want 2", res.EventsDropped)
+	}
+}
@@ -42,6 +42,7 @@ type Lifecycle struct {
 	shutdownOnce  sync.Once
 	preShutdownMu sync.Mutex                  // guards tsnetShutdown against a shutdown/Set race
 	tsnetShutdown func(context.Context) error // releases the inbound tsnet node; called before PID removal
+	reload        func(context.Context) error // re-reads config on SIGHUP; nil ignores SIGHUP
 }
 
 // NewLifecycle creates a new lifecycle manager.
@@ -81,6 +82,13 @@ func (l *Lifecycle) SetTsnetShutdown(fn func(context.Context) error) {
 	l.preShutdownMu.Unlock()
 }
 
+// SetReload registers the config reload hook run on SIGHUP. The hook applies
+// what it can live and reports its own changes; an error is logged and the
+// daemon keeps running. This should be called before Run().
+func (l *Lifecycle) SetReload(fn func(context.Context) error) {
+	l.reload = fn
+}
+
 // Run starts the server and handles signals until shutdown.
 func (l *Lifecycle) Run(ctx context.Context) error {
 	// 1. Acquire file lock for SIGKILL resilience (if configured)
@@ -180,17 +188,25 @@ func (l *Lifecycle) Run(ctx context.Context) error {
 	return l.shutdown()
 }
 
-// handleSignals listens for OS signals and triggers shutdown.
-func (l *Lifecycle) handleSignals(_ context.Context) {
+// handleSignals listens for OS signals: SIGHUP reloads config, SIGTERM and
+// SIGINT trigger shutdown.
+func (l *Lifecycle) handleSignals(ctx context.Context) {
 	sigCh := make(chan os.Signal, 1)
 
-	// Register for SIGTERM and SIGINT (graceful shutdown)
-	signal.Notify(sigCh, syscall.SIGTERM, syscall.SIGINT)
-
-	// Note: SIGHUP for reload config is reserved for future implementation
+	// Register for SIGTERM and SIGINT (graceful shutdown) and SIGHUP (reload)
+	signal.Notify(sigCh, syscall.SIGTERM, syscall.SIGINT, syscall.SIGHUP)
 
-	// Wait for signal
+	// Wait for a shutdown signal, reloading on each SIGHUP along the way
 	sig := <-sigCh
+	for sig == syscall.SIGHUP {
+		if l.reload != nil {
+			fmt.Fprintln(os.Stderr, "Received SIGHUP, reloading config...")
+			if err := l.reload(ctx); err != nil {
+				fmt.Fprintf(os.Stderr, "Config reload failed: %v\n", err)
+			}
+		}
+		sig = <-sigCh
+	}
 
 	fmt.Fprintf(os.Stderr, "Received signal %v, initiating graceful shutdown...\n", sig)
 
@@ -87,6 +87,34 @@ func ParseLogLevel(level string) slog.Level {
 	}
 }
 
+// LogLevelEnv overrides the configured daemon log level when set.
+const LogLevelEnv = "THRUM_LOG_LEVEL"
+
+// ResolveLogLevel picks the daemon log level: the --log-level flag wins,
+// then THRUM_LOG_LEVEL, then the config.json value, then "info". An
+// explicit flag or env value that ParseLogLevel doesn't recognize is an
+// error rather than a silent fallback, so a typo can't hide debug output.
+func ResolveLogLevel(flagLevel, configLevel string) (string, error) {
+	for _, src := range []struct{ name, value string }{
+		{"--log-level", flagLevel},
+		{LogLevelEnv, os.Getenv(LogLevelEnv)},
+	} {
+		level := strings.ToLower(strings.TrimSpace(src.value))
+		if level == "" {
+			continue
+		}
+		switch level {
+		case "debug", "info", "warn", "warning", "error":
+			return level, nil
+		}
+		return "", fmt.Errorf("invalid %s %q (must be debug, info, warn, or error)", src.name, src.value)
+	}
+	if configLevel != "" {
+		return configLevel, nil
+	}
+	return "info", nil
+}
+
 // ConfigureSlog installs a slog.Logger writing to w at the given level and
 // sets it as the package-default slog logger. The handler format matches the
 // standard log package prefix so `thrum daemon logs` parses timestamps
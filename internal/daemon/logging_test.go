@@ -114,6 +114,33 @@ func TestParseLogLevel(t *testing.T) {
 	}
 }
 
+func TestResolveLogLevel(t *testing.T) {
+	tests := []struct {
+		name, flag, env, config string
+		want                    string
+		wantErr                 bool
+	}{
+		{name: "default", want: "info"},
+		{name: "config", config: "warn", want: "warn"},
+		{name: "env over config", env: "debug", config: "warn", want: "debug"},
+		{name: "flag over env", flag: "ERROR", env: "debug", want: "error"},
+		{name: "bad flag", flag: "verbose", wantErr: true},
+		{name: "bad env", env: "loud", config: "warn", wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(LogLevelEnv, tc.env)
+			got, err := ResolveLogLevel(tc.flag, tc.config)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ResolveLogLevel() err = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("ResolveLogLevel() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
 func TestConfigureSlog_LevelFiltering(t *testing.T) {
 	// Save and restore default slog logger.
 	origDefault := slog.Default()
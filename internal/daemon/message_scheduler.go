@@ -0,0 +1,158 @@
+package daemon
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/leonletto/thrum/internal/daemon/state"
+	"github.com/leonletto/thrum/internal/types"
+)
+
+// maxMessageSchedulerSleep caps how long the message scheduler sleeps
+// between passes, so a wall-clock jump can delay a release by at most this
+// much.
+const maxMessageSchedulerSleep = time.Minute
+
+// MessageScheduler releases scheduled messages (send --schedule). The
+// message.create event is written and synced when the message is sent, but
+// the event write hook skips its notifications (subscription dispatch,
+// WebSocket broadcast, tmux nudge, inbox spool) and queues it here instead.
+// Once deliver_at passes, the scheduler hands the event to release, which
+// runs them. The queue lives in the daemon-local scheduled_messages table,
+// so messages still pending at shutdown are picked up on the next start.
+type MessageScheduler struct {
+	state   *state.State
+	release func(context.Context, types.MessageCreateEvent)
+	wake    chan struct{}
+}
+
+// NewMessageScheduler constructs a scheduler that passes each due message
+// to release.
+func NewMessageScheduler(st *state.State, release func(context.Context, types.MessageCreateEvent)) *MessageScheduler {
+	return &MessageScheduler{
+		state:   st,
+		release: release,
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// Enqueue queues a scheduled message.create; event is the persisted
+// payload the event write hook received. Queuing the same message twice
+// is a no-op. It runs inside the hook, whose caller already holds the state
+// lock, so it writes without taking it.
+func (s *MessageScheduler) Enqueue(ctx context.Context, messageID, deliverAt string, event []byte) error {
+	if _, err := s.state.DB().ExecContext(ctx,
+		`INSERT OR IGNORE INTO scheduled_messages (message_id, deliver_at, event_json) VALUES (?, ?, ?)`,
+		messageID, deliverAt, string(event)); err != nil {
+		return err
+	}
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Start blocks until the context is canceled. It releases whatever is
+// already due, including messages that came due while the daemon was down,
+// then sleeps until the next deliver_at or until Enqueue adds a message.
+func (s *MessageScheduler) Start(ctx context.Context) {
+	log.Printf("message_scheduler: starting")
+	for {
+		wait := maxMessageSchedulerSleep
+		if next := s.ReleaseDue(ctx, time.Now().UTC()); !next.IsZero() {
+			wait = min(max(time.Until(next), 0), maxMessageSchedulerSleep)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			log.Printf("message_scheduler: stopping")
+			return
+		case <-s.wake:
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+}
+
+// ReleaseDue releases every queued message whose deliver_at is at or
+// before now and returns the earliest deliver_at still queued (zero when
+// the queue is empty). Messages canceled with message.cancel, or otherwise
+// deleted since they were queued, are dropped without a release. A message
+// leaves the queue before it is released, so a crash mid-release skips its
+// notifications rather than sending them twice.
+func (s *MessageScheduler) ReleaseDue(ctx context.Context, now time.Time) time.Time {
+	type queued struct {
+		messageID string
+		event     string
+		live      bool
+	}
+	s.state.RLock()
+	rows, err := s.state.DB().QueryContext(ctx, `
+		SELECT q.message_id, q.event_json, COALESCE(m.deleted, 1) = 0
+		FROM scheduled_messages q
+		LEFT JOIN messages m ON m.message_id = q.message_id
+		WHERE q.deliver_at <= ?
+		ORDER BY q.deliver_at, q.message_id
+	`, now.UTC().Format(time.RFC3339))
+	var due []queued
+	if err == nil {
+		for rows.Next() {
+			var q queued
+			if err = rows.Scan(&q.messageID, &q.event, &q.live); err != nil {
+				break
+			}
+			due = append(due, q)
+		}
+		_ = rows.Close()
+		if err == nil {
+			err = rows.Err()
+		}
+	}
+	s.state.RUnlock()
+	if err != nil {
+		log.Printf("message_scheduler: query due messages failed: %v", err)
+		return now.Add(maxMessageSchedulerSleep)
+	}
+
+	for _, q := range due {
+		s.state.Lock()
+		_, err := s.state.DB().ExecContext(ctx, `DELETE FROM scheduled_messages WHERE message_id = ?`, q.messageID)
+		s.state.Unlock()
+		if err != nil {
+			log.Printf("message_scheduler: dequeue failed: msg=%s err=%v", q.messageID, err)
+			continue
+		}
+		if !q.live {
+			log.Printf("message_scheduler: dropped canceled message: msg=%s", q.messageID)
+			continue
+		}
+		var evt types.MessageCreateEvent
+		if err := json.Unmarshal([]byte(q.event), &evt); err != nil {
+			log.Printf("message_scheduler: decode queued event failed: msg=%s err=%v", q.messageID, err)
+			continue
+		}
+		s.release(ctx, evt)
+	}
+
+	var next sql.NullString
+	s.state.RLock()
+	err = s.state.DB().QueryRowContext(ctx, `SELECT MIN(deliver_at) FROM scheduled_messages`).Scan(&next)
+	s.state.RUnlock()
+	if err != nil {
+		log.Printf("message_scheduler: query next deliver_at failed: %v", err)
+		return now.Add(maxMessageSchedulerSleep)
+	}
+	if !next.Valid {
+		return time.Time{}
+	}
+	at, err := time.Parse(time.RFC3339, next.String)
+	if err != nil {
+		return now.Add(maxMessageSchedulerSleep)
+	}
+	return at
+}
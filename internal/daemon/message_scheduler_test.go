@@ -0,0 +1,126 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/leonletto/thrum/internal/types"
+)
+
+func TestMessageScheduler_ReleaseDue(t *testing.T) {
+	st := createTestStateForSync(t)
+	ctx := context.Background()
+	base := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	var released []string
+	newScheduler := func() *MessageScheduler {
+		return NewMessageScheduler(st, func(_ context.Context, evt types.MessageCreateEvent) {
+			released = append(released, evt.MessageID)
+		})
+	}
+	s := newScheduler()
+
+	schedule := func(id string, at time.Time) {
+		t.Helper()
+		evt := types.MessageCreateEvent{
+			Type:      "message.create",
+			Timestamp: base.Add(-time.Hour).Format(time.RFC3339Nano),
+			MessageID: id,
+			AgentID:   "sender",
+			SessionID: "ses_sender",
+			Body:      types.MessageBody{Format: "markdown", Content: "later"},
+			DeliverAt: at.Format(time.RFC3339),
+		}
+		if _, err := st.WriteEvent(ctx, evt); err != nil {
+			t.Fatalf("write %s: %v", id, err)
+		}
+		raw, _ := json.Marshal(evt)
+		// Queuing twice, as a replayed hook would, keeps one entry.
+		for range 2 {
+			if err := s.Enqueue(ctx, id, evt.DeliverAt, raw); err != nil {
+				t.Fatalf("enqueue %s: %v", id, err)
+			}
+		}
+	}
+	schedule("msg_first", base.Add(time.Hour))
+	schedule("msg_second", base.Add(2*time.Hour))
+	schedule("msg_canceled", base.Add(90*time.Minute))
+	if _, err := st.WriteEvent(ctx, types.MessageDeleteEvent{
+		Type:      "message.delete",
+		Timestamp: base.Format(time.RFC3339Nano),
+		MessageID: "msg_canceled",
+		Reason:    "canceled",
+	}); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+
+	if next := s.ReleaseDue(ctx, base); !next.Equal(base.Add(time.Hour)) || len(released) != 0 {
+		t.Errorf("before any deliver_at: next = %v, released = %v", next, released)
+	}
+	if next := s.ReleaseDue(ctx, base.Add(time.Hour)); !next.Equal(base.Add(90 * time.Minute)) {
+		t.Errorf("next after first release = %v, want %v", next, base.Add(90*time.Minute))
+	}
+	if !slices.Equal(released, []string{"msg_first"}) {
+		t.Errorf("released = %v, want [msg_first]", released)
+	}
+
+	// A restarted daemon picks the queue back up; the canceled message is
+	// dropped without a release.
+	s = newScheduler()
+	if next := s.ReleaseDue(ctx, base.Add(3*time.Hour)); !next.IsZero() {
+		t.Errorf("next with empty queue = %v, want zero", next)
+	}
+	if !slices.Equal(released, []string{"msg_first", "msg_second"}) {
+		t.Errorf("released = %v, want [msg_first msg_second]", released)
+	}
+	var queued int
+	if err := st.RawDB().QueryRow(`SELECT COUNT(*) FROM scheduled_messages`).Scan(&queued); err != nil {
+		t.Fatalf("count queue: %v", err)
+	}
+	if queued != 0 {
+		t.Errorf("scheduled_messages has %d rows, want 0", queued)
+	}
+}
+
+func TestMessageScheduler_StartReleasesOnEnqueue(t *testing.T) {
+	st := createTestStateForSync(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	released := make(chan string, 1)
+	s := NewMessageScheduler(st, func(_ context.Context, evt types.MessageCreateEvent) {
+		released <- evt.MessageID
+	})
+	go s.Start(ctx)
+
+	evt := types.MessageCreateEvent{
+		Type:      "message.create",
+		Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
+		MessageID: "msg_due",
+		AgentID:   "sender",
+		SessionID: "ses_sender",
+		Body:      types.MessageBody{Format: "markdown", Content: "now"},
+		DeliverAt: time.Now().UTC().Add(-time.Second).Format(time.RFC3339),
+	}
+	if _, err := st.WriteEvent(ctx, evt); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	raw, _ := json.Marshal(evt)
+	if err := s.Enqueue(ctx, evt.MessageID, evt.DeliverAt, raw); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+
+	// Enqueue wakes the sleeping scheduler; without it the release would
+	// wait out maxMessageSchedulerSleep.
+	select {
+	case id := <-released:
+		if id != "msg_due" {
+			t.Errorf("released %s, want msg_due", id)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("due message not released after Enqueue")
+	}
+}
@@ -0,0 +1,192 @@
+// Package metrics exposes daemon counters and gauges in the Prometheus text
+// exposition format (version 0.0.4) without pulling in the Prometheus client
+// library.
+//
+// Every metric is backed by a read function evaluated at scrape time, so the
+// daemon keeps no extra state for metrics it can already answer (client
+// registry size, sync loop status, a COUNT(*) on the DB). The endpoint is
+// read-only and gated behind daemon.metrics_enabled in .thrum/config.json.
+package metrics
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Kind is the Prometheus metric type written on the # TYPE line.
+type Kind string
+
+const (
+	// Counter is a monotonically increasing value (reset on daemon restart).
+	Counter Kind = "counter"
+	// Gauge is a value that can go up and down.
+	Gauge Kind = "gauge"
+)
+
+// ReadFunc returns the current value of a metric. ok=false omits the sample
+// from this scrape (e.g. sync lag before the first successful sync).
+type ReadFunc func() (value float64, ok bool)
+
+// LabeledReadFunc returns the current value of a one-label metric for each
+// label value. An empty map omits the metric from this scrape.
+type LabeledReadFunc func() map[string]float64
+
+type metric struct {
+	name string
+	help string
+	kind Kind
+	read ReadFunc
+	// label and readLabeled are set instead of read for one-label metrics.
+	label       string
+	readLabeled LabeledReadFunc
+}
+
+// Registry holds the set of metrics served by Handler.
+type Registry struct {
+	mu      sync.Mutex
+	metrics map[string]metric
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{metrics: make(map[string]metric)}
+}
+
+// Register adds a metric. Registering the same name twice replaces the
+// earlier definition.
+func (r *Registry) Register(name, help string, kind Kind, read ReadFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.metrics[name] = metric{name: name, help: help, kind: kind, read: read}
+}
+
+// RegisterLabeled adds a metric with one label, written as one sample per
+// label value (name{label="value"}). Registering the same name twice replaces
+// the earlier definition.
+func (r *Registry) RegisterLabeled(name, help string, kind Kind, label string, read LabeledReadFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.metrics[name] = metric{name: name, help: help, kind: kind, label: label, readLabeled: read}
+}
+
+// Write renders every metric in name order in the Prometheus text format.
+func (r *Registry) Write(b *strings.Builder) {
+	r.mu.Lock()
+	ms := make([]metric, 0, len(r.metrics))
+	for _, m := range r.metrics {
+		ms = append(ms, m)
+	}
+	r.mu.Unlock()
+
+	sort.Slice(ms, func(i, j int) bool { return ms[i].name < ms[j].name })
+	for _, m := range ms {
+		if m.readLabeled != nil {
+			writeLabeled(b, m)
+			continue
+		}
+		v, ok := m.read()
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(b, "# HELP %s %s\n", m.name, m.help)
+		fmt.Fprintf(b, "# TYPE %s %s\n", m.name, m.kind)
+		fmt.Fprintf(b, "%s %s\n", m.name, formatValue(v))
+	}
+}
+
+// writeLabeled renders a one-label metric with its samples in label value
+// order.
+func writeLabeled(b *strings.Builder, m metric) {
+	values := m.readLabeled()
+	if len(values) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	fmt.Fprintf(b, "# HELP %s %s\n", m.name, m.help)
+	fmt.Fprintf(b, "# TYPE %s %s\n", m.name, m.kind)
+	for _, k := range keys {
+		fmt.Fprintf(b, "%s{%s=\"%s\"} %s\n", m.name, m.label, labelEscaper.Replace(k), formatValue(values[k]))
+	}
+}
+
+// labelEscaper escapes a label value as the text format requires.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// CounterVec is a set of counters keyed by one label value, for metrics
+// counted on hot paths such as RPC dispatch. It is safe for concurrent use,
+// and Inc takes no lock once a label value has been seen. The zero value is
+// ready to use. Label values should come from a small fixed set (method
+// names, not caller input), since each one is kept until the daemon exits.
+type CounterVec struct {
+	counts sync.Map // label value -> *atomic.Uint64
+}
+
+// Inc adds one to the counter for label.
+func (c *CounterVec) Inc(label string) {
+	v, ok := c.counts.Load(label)
+	if !ok {
+		v, _ = c.counts.LoadOrStore(label, new(atomic.Uint64))
+	}
+	v.(*atomic.Uint64).Add(1)
+}
+
+// Snapshot returns the current count for every label value seen so far,
+// in the form RegisterLabeled reads.
+func (c *CounterVec) Snapshot() map[string]float64 {
+	out := make(map[string]float64)
+	c.counts.Range(func(k, v any) bool {
+		out[k.(string)] = float64(v.(*atomic.Uint64).Load())
+		return true
+	})
+	return out
+}
+
+// Handler serves the registry at GET /metrics. Like the unauthenticated
+// WebSocket path, it only answers loopback clients: the daemon's HTTP handler
+// is also reachable through LAN/tailnet listeners, and metrics are not meant
+// to leave the host.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if !isLoopback(req.RemoteAddr) {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		var b strings.Builder
+		r.Write(&b)
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_, _ = w.Write([]byte(b.String()))
+	})
+}
+
+// formatValue renders whole numbers without an exponent or trailing ".0" so
+// counters read naturally; fractional values use the shortest repr.
+func formatValue(v float64) string {
+	if v == float64(int64(v)) {
+		return fmt.Sprintf("%d", int64(v))
+	}
+	return fmt.Sprintf("%g", v)
+}
+
+func isLoopback(remoteAddr string) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
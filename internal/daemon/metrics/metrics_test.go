@@ -0,0 +1,111 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestRegistryWrite(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register("thrum_b_total", "B counter.", Counter, func() (float64, bool) { return 42, true })
+	reg.Register("thrum_a", "A gauge.", Gauge, func() (float64, bool) { return 1.5, true })
+	reg.Register("thrum_skipped", "Omitted.", Gauge, func() (float64, bool) { return 0, false })
+
+	var b strings.Builder
+	reg.Write(&b)
+
+	want := "# HELP thrum_a A gauge.\n" +
+		"# TYPE thrum_a gauge\n" +
+		"thrum_a 1.5\n" +
+		"# HELP thrum_b_total B counter.\n" +
+		"# TYPE thrum_b_total counter\n" +
+		"thrum_b_total 42\n"
+	if got := b.String(); got != want {
+		t.Errorf("Write() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRegistryWrite_Labeled(t *testing.T) {
+	reg := NewRegistry()
+	reg.RegisterLabeled("thrum_rpc_calls_total", "RPC calls.", Counter, "method", func() map[string]float64 {
+		return map[string]float64{"session.start": 2, "message.send": 5, `odd"name`: 1}
+	})
+	reg.RegisterLabeled("thrum_empty_total", "Omitted.", Counter, "method", func() map[string]float64 { return nil })
+
+	var b strings.Builder
+	reg.Write(&b)
+
+	want := "# HELP thrum_rpc_calls_total RPC calls.\n" +
+		"# TYPE thrum_rpc_calls_total counter\n" +
+		"thrum_rpc_calls_total{method=\"message.send\"} 5\n" +
+		"thrum_rpc_calls_total{method=\"odd\\\"name\"} 1\n" +
+		"thrum_rpc_calls_total{method=\"session.start\"} 2\n"
+	if got := b.String(); got != want {
+		t.Errorf("Write() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestCounterVec_Concurrent(t *testing.T) {
+	var c CounterVec
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				c.Inc("message.send")
+				if j%2 == 0 {
+					c.Inc("session.heartbeat")
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	got := c.Snapshot()
+	if got["message.send"] != 8000 || got["session.heartbeat"] != 4000 || len(got) != 2 {
+		t.Errorf("Snapshot() = %v, want message.send=8000 session.heartbeat=4000", got)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register("thrum_agents", "Registered agents.", Gauge, func() (float64, bool) { return 3, true })
+	h := reg.Handler()
+
+	tests := []struct {
+		name       string
+		method     string
+		remoteAddr string
+		wantStatus int
+	}{
+		{"loopback GET", http.MethodGet, "127.0.0.1:5555", http.StatusOK},
+		{"loopback IPv6 GET", http.MethodGet, "[::1]:5555", http.StatusOK},
+		{"non-loopback GET", http.MethodGet, "192.168.1.20:5555", http.StatusForbidden},
+		{"POST", http.MethodPost, "127.0.0.1:5555", http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/metrics", nil)
+			req.RemoteAddr = tt.remoteAddr
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+				t.Errorf("Content-Type = %q", ct)
+			}
+			if !strings.Contains(rec.Body.String(), "thrum_agents 3\n") {
+				t.Errorf("body missing sample:\n%s", rec.Body.String())
+			}
+		})
+	}
+}
@@ -89,6 +89,10 @@ type PeerInfo struct {
 	// and failed (unreachable or stored token rejected); user should run
 	// 'thrum peer join --type repair <name>' to re-pair.
 	ReconcileStatus string `json:"reconcile_status,omitempty"` // xir.29
+	// PulledSequence is the highest after_sequence this peer has sent in a
+	// sync.pull, i.e. how much of our event log it is known to hold.
+	// thrum daemon gc only drops events once every peer is past them.
+	PulledSequence int64 `json:"pulled_sequence,omitempty"`
 }
 
 // Addr returns the network address for connecting to this peer.
@@ -288,6 +292,33 @@ func (r *PeerRegistry) RemovePeer(daemonID string) error {
 	return r.saveLocked()
 }
 
+// RenamePeer changes a peer's display name, keyed by daemon ID so the
+// token, address, and proxy prefix are untouched. Names must be unique
+// among peers; renaming a peer to its current name is a no-op.
+func (r *PeerRegistry) RenamePeer(daemonID, newName string) error {
+	newName = strings.TrimSpace(newName)
+	if newName == "" {
+		return fmt.Errorf("peer name is required")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p, ok := r.peers[daemonID]
+	if !ok {
+		return fmt.Errorf("peer %s not found", daemonID)
+	}
+	if p.Name == newName {
+		return nil
+	}
+	if other := r.findByNameLocked(newName); other != nil {
+		return fmt.Errorf("peer name %q is already used by %s", newName, other.DaemonID)
+	}
+
+	p.Name = newName
+	return r.saveLocked()
+}
+
 // SetReconcileStatus updates the xir.29 auto-reconcile status of a peer
 // by daemon_id and persists atomically. Empty status clears the drift
 // marker; "drift_reconcile_failed" flags the peer for manual --type repair.
@@ -320,6 +351,29 @@ func (r *PeerRegistry) UpdateLastSync(daemonID string) error {
 	return r.saveLocked()
 }
 
+// RecordPull advances the PulledSequence of the peer holding token to
+// afterSeq. It never moves backwards, and unknown tokens and failures to
+// persist are ignored: pull progress is advisory and sync must not fail
+// over it.
+func (r *PeerRegistry) RecordPull(token string, afterSeq int64) {
+	if token == "" {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, p := range r.peers {
+		if p.Token != token {
+			continue
+		}
+		if afterSeq > p.PulledSequence {
+			p.PulledSequence = afterSeq
+			_ = r.saveLocked()
+		}
+		return
+	}
+}
+
 // RemoveStalePeers removes peers whose LastSync is older than the given timeout.
 // Returns the number of peers removed.
 func (r *PeerRegistry) RemoveStalePeers(timeout time.Duration) int {
@@ -104,6 +104,33 @@ func TestPeerRegistry_UpdateLastSync(t *testing.T) {
 	}
 }
 
+func TestPeerRegistry_RecordPull(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "peers.json")
+	reg, err := NewPeerRegistry(path)
+	if err != nil {
+		t.Fatalf("NewPeerRegistry: %v", err)
+	}
+	_ = reg.AddPeer(&PeerInfo{DaemonID: "d_alice", Name: "alice", Address: "alice:9100", Token: "tok_alice"})
+
+	reg.RecordPull("tok_alice", 40)
+	reg.RecordPull("tok_alice", 12) // an older batch must not move it back
+	reg.RecordPull("tok_unknown", 99)
+	reg.RecordPull("", 99)
+
+	if got := reg.GetPeer("d_alice").PulledSequence; got != 40 {
+		t.Errorf("PulledSequence = %d, want 40", got)
+	}
+
+	reloaded, err := NewPeerRegistry(path)
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if got := reloaded.GetPeer("d_alice").PulledSequence; got != 40 {
+		t.Errorf("PulledSequence after reload = %d, want 40", got)
+	}
+}
+
 func TestPeerRegistry_RemoveStalePeers(t *testing.T) {
 	dir := t.TempDir()
 	reg, err := NewPeerRegistry(filepath.Join(dir, "peers.json"))
@@ -668,6 +695,52 @@ func TestPeerRegistry_SetReconcileStatus(t *testing.T) {
 	}
 }
 
+func TestPeerRegistry_RenamePeer(t *testing.T) {
+	tmp := filepath.Join(t.TempDir(), "peers.json")
+	r, err := NewPeerRegistry(tmp)
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	if err := r.AddPeer(&PeerInfo{Name: "host-a.local", DaemonID: "01DA", Token: "ta", Address: "10.0.0.1:7731", ProxyPrefix: "repo-a"}); err != nil {
+		t.Fatalf("add a: %v", err)
+	}
+	if err := r.AddPeer(&PeerInfo{Name: "host-b.local", DaemonID: "01DB", Token: "tb"}); err != nil {
+		t.Fatalf("add b: %v", err)
+	}
+
+	if err := r.RenamePeer("01DA", "  laptop  "); err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+	if err := r.RenamePeer("01DA", "laptop"); err != nil {
+		t.Errorf("rename to current name: %v", err)
+	}
+	for _, tc := range []struct{ id, name string }{
+		{"01DB", "laptop"}, // taken
+		{"01DB", " "},      // empty
+		{"01DX", "spare"},  // unknown peer
+	} {
+		if err := r.RenamePeer(tc.id, tc.name); err == nil {
+			t.Errorf("RenamePeer(%q, %q): expected error", tc.id, tc.name)
+		}
+	}
+
+	// Reopen: the new name persisted and the token/address mapping held.
+	r2, err := NewPeerRegistry(tmp)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	got := r2.FindPeerByName("laptop")
+	if got == nil || got.DaemonID != "01DA" || got.Address != "10.0.0.1:7731" || got.ProxyPrefix != "repo-a" {
+		t.Fatalf("renamed peer = %+v", got)
+	}
+	if byToken := r2.FindPeerByToken("ta"); byToken == nil || byToken.Name != "laptop" {
+		t.Errorf("token lookup after rename = %+v", byToken)
+	}
+	if r2.FindPeerByName("host-a.local") != nil {
+		t.Error("old name still resolves")
+	}
+}
+
 // TestSanitizeProxyPrefix covers thrum-b6yv character-class rules.
 // TestSanitizeProxyPrefix covers thrum-b6yv character-class rules,
 // including the documented silent-drop behavior for non-ASCII runes.
@@ -2,6 +2,7 @@ package daemon
 
 import (
 	"context"
+	"errors"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
@@ -125,6 +126,41 @@ func TestPushSync_EndToEndEventSync(t *testing.T) {
 	}
 }
 
+func TestPullFromPeerByID(t *testing.T) {
+	daemonA := newTestDaemon(t, "daemon-a")
+	daemonB := newTestDaemon(t, "daemon-b")
+	writeTestEvent(t, daemonA.state, "message.create")
+
+	syncManager := NewDaemonSyncManager(daemonB.state, createTestPeerRegistry(t))
+	_ = syncManager.PeerRegistry().AddPeer(&PeerInfo{
+		DaemonID: daemonA.state.DaemonID(),
+		Name:     "daemon-a",
+		Address:  daemonA.addr(),
+	})
+
+	applied, _, err := syncManager.PullFromPeerByID(context.Background(), daemonA.state.DaemonID())
+	if err != nil || applied == 0 {
+		t.Fatalf("pull = applied %d, err %v; want events applied", applied, err)
+	}
+
+	if _, _, err := syncManager.PullFromPeerByID(context.Background(), "d_nope"); !errors.Is(err, ErrPeerNotFound) {
+		t.Errorf("unknown peer: err = %v, want ErrPeerNotFound", err)
+	}
+
+	// A port nothing listens on: the dial fails, and a second pull is held
+	// back by the dial gate — both are ErrPeerUnreachable, never a silent 0.
+	_ = syncManager.PeerRegistry().AddPeer(&PeerInfo{
+		DaemonID: "d_gone",
+		Name:     "gone",
+		Address:  "127.0.0.1:1",
+	})
+	for i := range 2 {
+		if _, _, err := syncManager.PullFromPeerByID(context.Background(), "d_gone"); !errors.Is(err, ErrPeerUnreachable) {
+			t.Errorf("unreachable peer, attempt %d: err = %v, want ErrPeerUnreachable", i+1, err)
+		}
+	}
+}
+
 func TestPushSync_BroadcastNotifyAllPeers(t *testing.T) {
 	// Create daemon A and two peer daemons
 	daemonA := newTestDaemon(t, "daemon-a")
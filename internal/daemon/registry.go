@@ -0,0 +1,116 @@
+package daemon
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RegistryEntry records a daemon started by `thrum daemon start` in the
+// per-user registry ($HOME/.thrum/daemons/), so `thrum daemon status --all`
+// can find daemons across repos without being run from each one.
+type RegistryEntry struct {
+	RepoPath   string    `json:"repo_path"`
+	ThrumDir   string    `json:"thrum_dir"`
+	PID        int       `json:"pid"`
+	SocketPath string    `json:"socket_path,omitempty"`
+	StartedAt  time.Time `json:"started_at,omitzero"`
+}
+
+// RegistryDir returns the daemon registry directory. os.UserHomeDir honors
+// $HOME, which tests set via t.Setenv.
+func RegistryDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot determine home dir: %w", err)
+	}
+	return filepath.Join(home, ".thrum", "daemons"), nil
+}
+
+// registryFile names an entry by a hash of its repo path, so restarting a
+// repo's daemon replaces its entry rather than adding one.
+func registryFile(dir, repoPath string) string {
+	sum := sha256.Sum256([]byte(filepath.Clean(repoPath)))
+	return filepath.Join(dir, hex.EncodeToString(sum[:8])+".json")
+}
+
+// RegisterDaemon writes entry to the registry, replacing any earlier entry
+// for the same repo.
+func RegisterDaemon(entry RegistryEntry) error {
+	dir, err := RegistryDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create daemon registry: %w", err)
+	}
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal registry entry: %w", err)
+	}
+	if err := os.WriteFile(registryFile(dir, entry.RepoPath), data, 0600); err != nil {
+		return fmt.Errorf("failed to write registry entry: %w", err)
+	}
+	return nil
+}
+
+// ListRegisteredDaemons returns the registered daemons that are still
+// running, sorted by repo path, and removes the stale entries, which it
+// returns as pruned. An entry is live when the PID file under its thrum
+// directory names a running process serving its repo; the PID is taken
+// from there, since the daemon may have been restarted since registering.
+// A missing registry is not an error.
+func ListRegisteredDaemons() (live, pruned []RegistryEntry, err error) {
+	dir, err := RegistryDir()
+	if err != nil {
+		return nil, nil, err
+	}
+	files, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read daemon registry: %w", err)
+	}
+
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), ".json") {
+			continue
+		}
+		path := filepath.Join(dir, f.Name())
+		data, err := os.ReadFile(path) // #nosec G304 -- path is an entry in the user's own daemon registry
+		if err != nil {
+			continue
+		}
+		var entry RegistryEntry
+		if json.Unmarshal(data, &entry) != nil || entry.RepoPath == "" || entry.ThrumDir == "" {
+			_ = os.Remove(path)
+			pruned = append(pruned, entry)
+			continue
+		}
+
+		running, info, err := CheckPIDFileJSON(filepath.Join(entry.ThrumDir, "var", "thrum.pid"))
+		if err != nil || !running || !ValidatePIDRepo(info, entry.RepoPath) {
+			_ = os.Remove(path)
+			pruned = append(pruned, entry)
+			continue
+		}
+		entry.PID = info.PID
+		if info.SocketPath != "" {
+			entry.SocketPath = info.SocketPath
+		}
+		if !info.StartedAt.IsZero() {
+			entry.StartedAt = info.StartedAt
+		}
+		live = append(live, entry)
+	}
+
+	sort.Slice(live, func(i, j int) bool { return live[i].RepoPath < live[j].RepoPath })
+	return live, pruned, nil
+}
@@ -0,0 +1,71 @@
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestListRegisteredDaemons_PrunesStale(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	// A live daemon: its PID file names this (running) process and repo.
+	liveRepo := t.TempDir()
+	liveThrum := filepath.Join(liveRepo, ".thrum")
+	if err := WritePIDFileJSON(filepath.Join(liveThrum, "var", "thrum.pid"), PIDInfo{
+		PID: os.Getpid(), RepoPath: liveRepo, SocketPath: "/tmp/live.sock",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	// A dead one: its PID file was removed when the daemon stopped.
+	deadRepo := t.TempDir()
+
+	for _, e := range []RegistryEntry{
+		{RepoPath: liveRepo, ThrumDir: liveThrum, PID: 1},
+		{RepoPath: deadRepo, ThrumDir: filepath.Join(deadRepo, ".thrum"), PID: 999999},
+	} {
+		if err := RegisterDaemon(e); err != nil {
+			t.Fatalf("RegisterDaemon: %v", err)
+		}
+	}
+
+	live, pruned, err := ListRegisteredDaemons()
+	if err != nil {
+		t.Fatalf("ListRegisteredDaemons: %v", err)
+	}
+	if len(live) != 1 || live[0].RepoPath != liveRepo || live[0].PID != os.Getpid() || live[0].SocketPath != "/tmp/live.sock" {
+		t.Errorf("live = %+v, want the live repo with the PID file's PID and socket", live)
+	}
+	if len(pruned) != 1 || pruned[0].RepoPath != deadRepo {
+		t.Errorf("pruned = %+v, want the dead repo", pruned)
+	}
+
+	dir, _ := RegistryDir()
+	files, _ := os.ReadDir(dir)
+	if len(files) != 1 {
+		t.Errorf("registry has %d entries after pruning, want 1", len(files))
+	}
+}
+
+func TestRegisterDaemon_ReplacesRepoEntry(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	repo := t.TempDir()
+	for _, pid := range []int{10, 20} {
+		if err := RegisterDaemon(RegistryEntry{RepoPath: repo, ThrumDir: filepath.Join(repo, ".thrum"), PID: pid}); err != nil {
+			t.Fatalf("RegisterDaemon: %v", err)
+		}
+	}
+	dir, _ := RegistryDir()
+	files, _ := os.ReadDir(dir)
+	if len(files) != 1 {
+		t.Errorf("registry has %d entries, want 1 per repo", len(files))
+	}
+}
+
+func TestListRegisteredDaemons_NoRegistry(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	live, pruned, err := ListRegisteredDaemons()
+	if err != nil || live != nil || pruned != nil {
+		t.Errorf("ListRegisteredDaemons = %v, %v, %v; want nothing", live, pruned, err)
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/leonletto/thrum/internal/identity/guard"
 	"github.com/leonletto/thrum/internal/jsonl"
 	"github.com/leonletto/thrum/internal/process"
+	"github.com/leonletto/thrum/internal/schema"
 	ttmux "github.com/leonletto/thrum/internal/tmux"
 	"github.com/leonletto/thrum/internal/types"
 	wtpkg "github.com/leonletto/thrum/internal/worktree"
@@ -37,6 +38,9 @@ type RegisterRequest struct {
 	Force      bool   `json:"force,omitempty"`       // CLI --force: re-register existing agent, overriding stored fields (thrum-ufv5.2)
 	ReRegister bool   `json:"re_register,omitempty"` // Same agent returning
 	AgentPID   int    `json:"agent_pid,omitempty"`   // Claude process PID for identity resolution
+	// Capabilities replaces the agent's capability tags when non-empty;
+	// omitted keeps the current set (agent.capabilities.set edits it later).
+	Capabilities []string `json:"capabilities,omitempty"`
 }
 
 // RegisterResponse represents the response from agent.register RPC.
@@ -58,8 +62,9 @@ type ConflictInfo struct {
 
 // ListAgentsRequest represents the request for agent.list RPC.
 type ListAgentsRequest struct {
-	Role   string `json:"role,omitempty"`   // Filter by role
-	Module string `json:"module,omitempty"` // Filter by module
+	Role       string `json:"role,omitempty"`       // Filter by role
+	Module     string `json:"module,omitempty"`     // Filter by module
+	Capability string `json:"capability,omitempty"` // Filter by advertised capability
 }
 
 // ListAgentsResponse represents the response from agent.list RPC.
@@ -69,14 +74,16 @@ type ListAgentsResponse struct {
 
 // AgentInfo represents information about a registered agent.
 type AgentInfo struct {
-	AgentID      string `json:"agent_id"`
-	Kind         string `json:"kind"`
-	Role         string `json:"role"`
-	Module       string `json:"module"`
-	Display      string `json:"display"`
-	RegisteredAt string `json:"registered_at"`
-	LastSeenAt   string `json:"last_seen_at,omitempty"`
-	AgentPID     int    `json:"agent_pid,omitempty"` // Claude process PID for identity resolution
+	AgentID      string   `json:"agent_id"`
+	Kind         string   `json:"kind"`
+	Role         string   `json:"role"`
+	Module       string   `json:"module"`
+	Display      string   `json:"display"`
+	RegisteredAt string   `json:"registered_at"`
+	LastSeenAt   string   `json:"last_seen_at,omitempty"`
+	AgentPID     int      `json:"agent_pid,omitempty"` // Claude process PID for identity resolution
+	Aliases      []string `json:"aliases,omitempty"`   // Nicknames set via agent.alias.set
+	Capabilities []string `json:"capabilities,omitempty"`
 }
 
 // WhoamiResponse represents the response from agent.whoami RPC.
@@ -126,6 +133,18 @@ type CleanupAgentRequest struct {
 	DryRun    bool `json:"dry_run"`
 	Force     bool `json:"force"`
 	Threshold int  `json:"threshold"` // Days since last seen
+	// StaleSessions also reports open sessions the idle threshold considers
+	// away, and with Force ends them with reason "stale".
+	StaleSessions bool `json:"stale_sessions,omitempty"`
+}
+
+// StaleSession is an open session whose last heartbeat is older than the
+// daemon's idle threshold.
+type StaleSession struct {
+	SessionID   string `json:"session_id"`
+	AgentID     string `json:"agent_id"`
+	LastSeenAt  string `json:"last_seen_at"`
+	IdleSeconds int64  `json:"idle_seconds"`
 }
 
 // OrphanedAgent represents an orphaned agent.
@@ -144,10 +163,12 @@ type OrphanedAgent struct {
 
 // CleanupAgentResponse represents the response from agent.cleanup RPC.
 type CleanupAgentResponse struct {
-	Orphans []OrphanedAgent `json:"orphans"`
-	Deleted []string        `json:"deleted"` // List of deleted agent IDs
-	DryRun  bool            `json:"dry_run"`
-	Message string          `json:"message,omitempty"`
+	Orphans       []OrphanedAgent `json:"orphans"`
+	Deleted       []string        `json:"deleted"` // List of deleted agent IDs
+	StaleSessions []StaleSession  `json:"stale_sessions,omitempty"`
+	EndedSessions []string        `json:"ended_sessions,omitempty"` // Stale sessions ended (force only)
+	DryRun        bool            `json:"dry_run"`
+	Message       string          `json:"message,omitempty"`
 }
 
 // AgentWorkContext represents an agent's work context.
@@ -165,11 +186,15 @@ type AgentWorkContext struct {
 	TaskUpdatedAt    string                 `json:"task_updated_at,omitempty"`
 	Intent           string                 `json:"intent,omitempty"`
 	IntentUpdatedAt  string                 `json:"intent_updated_at,omitempty"`
+	DeclaredFiles    []string               `json:"declared_files,omitempty"` // Declared via session start --files
+	LastSeenAt       string                 `json:"last_seen_at,omitempty"`   // session's last heartbeat
+	Status           string                 `json:"status"`                   // "active", or "away" when the last heartbeat is older than daemon.idle_threshold
 }
 
 // AgentHandler handles agent-related RPC methods.
 type AgentHandler struct {
 	state *state.State
+	idle  *IdleThreshold // nil = never report "away"
 }
 
 // NewAgentHandler creates a new agent handler.
@@ -177,6 +202,12 @@ func NewAgentHandler(s *state.State) *AgentHandler {
 	return &AgentHandler{state: s}
 }
 
+// SetIdleThreshold wires the shared idle threshold used to report a silent
+// agent as "away". Call once during daemon startup.
+func (h *AgentHandler) SetIdleThreshold(t *IdleThreshold) {
+	h.idle = t
+}
+
 // HandleRegister handles the agent.register RPC method.
 func (h *AgentHandler) HandleRegister(ctx context.Context, params json.RawMessage) (any, error) {
 	var req RegisterRequest
@@ -199,6 +230,14 @@ func (h *AgentHandler) HandleRegister(ctx context.Context, params json.RawMessag
 		}
 	}
 
+	var caps []string
+	if len(req.Capabilities) > 0 {
+		var err error
+		if caps, err = normalizeCapabilities(req.Capabilities); err != nil {
+			return nil, err
+		}
+	}
+
 	// Generate agent ID
 	repoID := h.state.RepoID()
 	agentID := identity.GenerateAgentID(repoID, req.Role, req.Module, req.Name)
@@ -368,6 +407,12 @@ func (h *AgentHandler) HandleRegister(ctx context.Context, params json.RawMessag
 		if regErr != nil {
 			return nil, regErr
 		}
+		var capsPostCommit func()
+		if caps != nil {
+			if _, capsPostCommit, regErr = h.writeCapabilities(ctx, agentID, caps); regErr != nil {
+				return nil, regErr
+			}
+		}
 
 		// Auto-resurrect (thrum-xir.18): if the agent has no active
 		// session and the caller's PID is alive, emit a fresh
@@ -393,6 +438,7 @@ func (h *AgentHandler) HandleRegister(ctx context.Context, params json.RawMessag
 		// surfacing on every under-lock SELECT, not just
 		// ensureActiveSession's "check active session" path.
 		h.state.GoPostCommit(postCommit)
+		h.state.GoPostCommit(capsPostCommit)
 
 		resumedID, resumeErr := h.ensureActiveSession(ctx, agentID, req.AgentPID)
 		if resumeErr != nil {
@@ -435,12 +481,19 @@ func (h *AgentHandler) HandleRegister(ctx context.Context, params json.RawMessag
 	if err != nil {
 		return resp, err
 	}
+	var capsPostCommit func()
+	if caps != nil {
+		if _, capsPostCommit, err = h.writeCapabilities(ctx, agentID, caps); err != nil {
+			return nil, err
+		}
+	}
 	// thrum-bsn7: release state.Lock() BEFORE invoking the agent.register
 	// sync trigger. Walker+compactor under the lock starves concurrent
 	// HandleRegister/message.create on the same lock.
 	h.state.Unlock()
 	stateLocked = false
 	h.state.GoPostCommit(postCommit)
+	h.state.GoPostCommit(capsPostCommit)
 	h.enforceWorktreeIdentity(ctx, agentIdentityName(req.Name, agentID))
 	return resp, nil
 }
@@ -571,6 +624,10 @@ func (h *AgentHandler) HandleList(ctx context.Context, params json.RawMessage) (
 		query += " AND module = ?"
 		args = append(args, req.Module)
 	}
+	if req.Capability != "" {
+		query += " AND agent_id IN (SELECT agent_id FROM agent_capabilities WHERE capability = ?)"
+		args = append(args, strings.ToLower(strings.TrimSpace(req.Capability)))
+	}
 
 	query += " ORDER BY registered_at DESC"
 
@@ -612,6 +669,19 @@ func (h *AgentHandler) HandleList(ctx context.Context, params json.RawMessage) (
 		return nil, fmt.Errorf("iterate agents: %w", err)
 	}
 
+	aliases, err := h.loadAliases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	capabilities, err := h.loadCapabilities(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range agents {
+		agents[i].Aliases = aliases[agents[i].AgentID]
+		agents[i].Capabilities = capabilities[agents[i].AgentID]
+	}
+
 	return &ListAgentsResponse{Agents: agents}, nil
 }
 
@@ -1077,9 +1147,11 @@ func (h *AgentHandler) HandleListContext(ctx context.Context, params json.RawMes
 	// Build query with filters — only return contexts for active (non-ended) sessions
 	query := `SELECT wc.session_id, wc.agent_id, wc.branch, wc.worktree_path,
 	                 wc.unmerged_commits, wc.uncommitted_files, wc.changed_files, wc.file_changes, wc.git_updated_at,
-	                 wc.current_task, wc.task_updated_at, wc.intent, wc.intent_updated_at
+	                 wc.current_task, wc.task_updated_at, wc.intent, wc.intent_updated_at,
+	                 s.last_seen_at, a.origin_daemon
 	          FROM agent_work_contexts wc
 	          JOIN sessions s ON wc.session_id = s.session_id AND s.ended_at IS NULL
+	          LEFT JOIN agents a ON a.agent_id = wc.agent_id
 	          WHERE 1=1`
 
 	args := []any{}
@@ -1096,11 +1168,13 @@ func (h *AgentHandler) HandleListContext(ctx context.Context, params json.RawMes
 		args = append(args, req.Branch)
 	}
 
-	// Filter by file (in changed_files or uncommitted_files)
+	// Filter by file (in changed_files, uncommitted_files, or declared)
 	if req.File != "" {
-		query += ` AND (wc.changed_files LIKE ? OR wc.uncommitted_files LIKE ?)`
+		query += ` AND (wc.changed_files LIKE ? OR wc.uncommitted_files LIKE ?
+		           OR EXISTS (SELECT 1 FROM session_scopes ss
+		                      WHERE ss.session_id = wc.session_id AND ss.scope_type = 'file' AND ss.scope_value = ?))`
 		filePattern := fmt.Sprintf("%%\"%s\"%%", req.File)
-		args = append(args, filePattern, filePattern)
+		args = append(args, filePattern, filePattern, req.File)
 	}
 
 	query += " ORDER BY wc.git_updated_at DESC"
@@ -1113,11 +1187,14 @@ func (h *AgentHandler) HandleListContext(ctx context.Context, params json.RawMes
 	defer func() { _ = rows.Close() }()
 
 	contexts := []AgentWorkContext{}
+	localDaemonID := h.state.DaemonID()
+	now := time.Now()
 
 	for rows.Next() {
 		var ctx AgentWorkContext
 		var branch, worktreePath, unmergedCommitsJSON, uncommittedFilesJSON, changedFilesJSON, fileChangesJSON, gitUpdatedAt sql.NullString
 		var currentTask, taskUpdatedAt, intent, intentUpdatedAt sql.NullString
+		var lastSeenAt, originDaemon sql.NullString
 
 		err := rows.Scan(
 			&ctx.SessionID,
@@ -1133,12 +1210,23 @@ func (h *AgentHandler) HandleListContext(ctx context.Context, params json.RawMes
 			&taskUpdatedAt,
 			&intent,
 			&intentUpdatedAt,
+			&lastSeenAt,
+			&originDaemon,
 		)
 		if err != nil {
 			h.state.RUnlock()
 			return nil, fmt.Errorf("scan row: %w", err)
 		}
 
+		// Away: the session is open but silent past the idle threshold.
+		// Local agents only — remote heartbeats never reach this daemon.
+		ctx.LastSeenAt = lastSeenAt.String
+		ctx.Status = "active"
+		isLocal := originDaemon.String == "" || originDaemon.String == localDaemonID
+		if isLocal && h.idle.IsAway(ctx.LastSeenAt, now) {
+			ctx.Status = "away"
+		}
+
 		// Unmarshal JSON fields
 		if unmergedCommitsJSON.Valid && unmergedCommitsJSON.String != "" {
 			if err := json.Unmarshal([]byte(unmergedCommitsJSON.String), &ctx.UnmergedCommits); err != nil {
@@ -1204,6 +1292,15 @@ func (h *AgentHandler) HandleListContext(ctx context.Context, params json.RawMes
 		return nil, fmt.Errorf("iterate rows: %w", err)
 	}
 
+	declared, err := loadDeclaredFiles(ctx, h.state.DB())
+	if err != nil {
+		h.state.RUnlock()
+		return nil, err
+	}
+	for i := range contexts {
+		contexts[i].DeclaredFiles = declared[contexts[i].SessionID]
+	}
+
 	h.state.RUnlock()
 
 	// Live git extraction: re-extract from worktree paths so callers see
@@ -1296,41 +1393,52 @@ func (h *AgentHandler) HandleDelete(ctx context.Context, params json.RawMessage)
 	h.state.Lock()
 
 	// Delete orphaned messages for this agent before removing the agent row.
+	// Reactions the agent left on other messages go too, as do assignments
+	// with the agent as assignee; ones it handed out to others stay.
 	_, err = h.state.DB().ExecContext(ctx,
-		"DELETE FROM message_edits WHERE message_id IN (SELECT message_id FROM messages WHERE agent_id = ?)", req.Name)
+		"DELETE FROM message_reactions WHERE agent_id = ?", req.Name)
 	if err != nil {
 		h.state.Unlock()
-		return nil, fmt.Errorf("delete message edits for agent: %w", err)
+		return nil, fmt.Errorf("delete message reactions for agent: %w", err)
 	}
 	_, err = h.state.DB().ExecContext(ctx,
-		"DELETE FROM message_reads WHERE message_id IN (SELECT message_id FROM messages WHERE agent_id = ?)", req.Name)
+		"DELETE FROM message_assignments WHERE assignee = ?", req.Name)
 	if err != nil {
 		h.state.Unlock()
-		return nil, fmt.Errorf("delete message reads for agent: %w", err)
+		return nil, fmt.Errorf("delete message assignments for agent: %w", err)
+	}
+	for _, table := range schema.MessageChildTables {
+		//nolint:gosec // table name is a hardcoded constant, not user input
+		q := `DELETE FROM ` + table + ` WHERE message_id IN (SELECT message_id FROM messages WHERE agent_id = ?)`
+		if _, err := h.state.DB().ExecContext(ctx, q, req.Name); err != nil {
+			h.state.Unlock()
+			return nil, fmt.Errorf("delete %s for agent: %w", table, err)
+		}
 	}
 	_, err = h.state.DB().ExecContext(ctx,
-		"DELETE FROM message_deliveries WHERE message_id IN (SELECT message_id FROM messages WHERE agent_id = ?)", req.Name)
+		"DELETE FROM messages WHERE agent_id = ?", req.Name)
 	if err != nil {
 		h.state.Unlock()
-		return nil, fmt.Errorf("delete message deliveries for agent: %w", err)
+		return nil, fmt.Errorf("delete messages for agent: %w", err)
 	}
+
 	_, err = h.state.DB().ExecContext(ctx,
-		"DELETE FROM message_refs WHERE message_id IN (SELECT message_id FROM messages WHERE agent_id = ?)", req.Name)
+		"DELETE FROM agent_aliases WHERE agent_id = ?", req.Name)
 	if err != nil {
 		h.state.Unlock()
-		return nil, fmt.Errorf("delete message refs for agent: %w", err)
+		return nil, fmt.Errorf("delete aliases for agent: %w", err)
 	}
 	_, err = h.state.DB().ExecContext(ctx,
-		"DELETE FROM message_scopes WHERE message_id IN (SELECT message_id FROM messages WHERE agent_id = ?)", req.Name)
+		"DELETE FROM agent_capabilities WHERE agent_id = ?", req.Name)
 	if err != nil {
 		h.state.Unlock()
-		return nil, fmt.Errorf("delete message scopes for agent: %w", err)
+		return nil, fmt.Errorf("delete capabilities for agent: %w", err)
 	}
 	_, err = h.state.DB().ExecContext(ctx,
-		"DELETE FROM messages WHERE agent_id = ?", req.Name)
+		"DELETE FROM agent_mutes WHERE agent_id = ?", req.Name)
 	if err != nil {
 		h.state.Unlock()
-		return nil, fmt.Errorf("delete messages for agent: %w", err)
+		return nil, fmt.Errorf("delete mute for agent: %w", err)
 	}
 
 	// Delete orphaned sessions for this agent.
@@ -1399,6 +1507,9 @@ func (h *AgentHandler) HandleCleanup(ctx context.Context, params json.RawMessage
 	if err := json.Unmarshal(params, &req); err != nil {
 		return nil, fmt.Errorf("invalid request: %w", err)
 	}
+	if req.StaleSessions && h.idle.Get() <= 0 {
+		return nil, fmt.Errorf("stale session cleanup needs an idle threshold, and daemon.idle_threshold disables it")
+	}
 
 	// Lock for DB query to get agent list
 	h.state.RLock()
@@ -1513,28 +1624,60 @@ func (h *AgentHandler) HandleCleanup(ctx context.Context, params json.RawMessage
 		}
 	}
 
+	var stale []StaleSession
+	if req.StaleSessions {
+		h.state.RLock()
+		stale, err = h.staleSessions(ctx, time.Now())
+		h.state.RUnlock()
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// If dry-run, just return the orphans
 	if req.DryRun {
+		msg := fmt.Sprintf("Found %d orphaned agent(s)", len(orphans))
+		if req.StaleSessions {
+			msg += fmt.Sprintf(" and %d stale session(s)", len(stale))
+		}
 		return &CleanupAgentResponse{
-			Orphans: orphans,
-			Deleted: []string{},
-			DryRun:  true,
-			Message: fmt.Sprintf("Found %d orphaned agent(s)", len(orphans)),
+			Orphans:       orphans,
+			Deleted:       []string{},
+			StaleSessions: stale,
+			DryRun:        true,
+			Message:       msg,
 		}, nil
 	}
 
 	// If not force mode, return orphans for interactive confirmation
-	// (The CLI will handle interactive confirmation and call agent.delete for each)
+	// (The CLI will handle interactive confirmation and call agent.delete
+	// for each orphan and session.end for each stale session)
 	if !req.Force {
+		msg := "Use --force to delete all orphans without prompting"
+		if req.StaleSessions {
+			msg = "Use --force to delete all orphans and end stale sessions without prompting"
+		}
 		return &CleanupAgentResponse{
-			Orphans: orphans,
-			Deleted: []string{},
-			DryRun:  false,
-			Message: "Use --force to delete all orphans without prompting",
+			Orphans:       orphans,
+			Deleted:       []string{},
+			StaleSessions: stale,
+			DryRun:        false,
+			Message:       msg,
 		}, nil
 	}
 
-	// Force mode: delete all orphans
+	// Force mode: end stale sessions, then delete all orphans
+	var ended []string
+	for _, sess := range stale {
+		ok, err := h.endStaleSession(ctx, sess.SessionID)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			ended = append(ended, sess.SessionID)
+		}
+	}
+
 	deleted := []string{}
 	for _, orphan := range orphans {
 		// Call HandleDelete for each orphan
@@ -1548,14 +1691,103 @@ func (h *AgentHandler) HandleCleanup(ctx context.Context, params json.RawMessage
 		}
 	}
 
+	msg := fmt.Sprintf("Deleted %d orphaned agent(s)", len(deleted))
+	if req.StaleSessions {
+		msg += fmt.Sprintf(" and ended %d stale session(s)", len(ended))
+	}
 	return &CleanupAgentResponse{
-		Orphans: orphans,
-		Deleted: deleted,
-		DryRun:  false,
-		Message: fmt.Sprintf("Deleted %d orphaned agent(s)", len(deleted)),
+		Orphans:       orphans,
+		Deleted:       deleted,
+		StaleSessions: stale,
+		EndedSessions: ended,
+		DryRun:        false,
+		Message:       msg,
 	}, nil
 }
 
+// staleSessions returns the open sessions the idle threshold reports away,
+// oldest heartbeat first. Only local agents are considered: heartbeats are
+// not synced, so a remote agent's session always looks silent here. Users
+// are skipped, as in orphan detection. Callers must hold the state lock.
+func (h *AgentHandler) staleSessions(ctx context.Context, now time.Time) ([]StaleSession, error) {
+	rows, err := h.state.DB().QueryContext(ctx, `
+		SELECT s.session_id, s.agent_id, COALESCE(s.last_seen_at, ''),
+		       COALESCE(a.origin_daemon, ''), COALESCE(a.kind, '')
+		FROM sessions s
+		LEFT JOIN agents a ON a.agent_id = s.agent_id
+		WHERE s.ended_at IS NULL
+		ORDER BY s.last_seen_at, s.session_id`)
+	if err != nil {
+		return nil, fmt.Errorf("query open sessions: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	localDaemonID := h.state.DaemonID()
+	var stale []StaleSession
+	for rows.Next() {
+		var sess StaleSession
+		var originDaemon, kind string
+		if err := rows.Scan(&sess.SessionID, &sess.AgentID, &sess.LastSeenAt, &originDaemon, &kind); err != nil {
+			return nil, fmt.Errorf("scan open session: %w", err)
+		}
+		if kind == "user" || (originDaemon != "" && originDaemon != localDaemonID) {
+			continue
+		}
+		if !h.idle.IsAway(sess.LastSeenAt, now) {
+			continue
+		}
+		seen, _ := time.Parse(time.RFC3339Nano, sess.LastSeenAt)
+		sess.IdleSeconds = int64(now.Sub(seen).Seconds())
+		stale = append(stale, sess)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate open sessions: %w", err)
+	}
+	return stale, nil
+}
+
+// endStaleSession ends sessionID with reason "stale" and drops its
+// subscriptions, as session.end does. The session is re-checked under the
+// write lock: one that ended or heartbeated since the scan is left alone and
+// reported as not ended.
+func (h *AgentHandler) endStaleSession(ctx context.Context, sessionID string) (bool, error) {
+	h.state.Lock()
+	var lastSeen string
+	err := h.state.DB().QueryRowContext(ctx,
+		`SELECT COALESCE(last_seen_at, '') FROM sessions WHERE session_id = ? AND ended_at IS NULL`, sessionID,
+	).Scan(&lastSeen)
+	if errors.Is(err, sql.ErrNoRows) {
+		h.state.Unlock()
+		return false, nil
+	}
+	if err != nil {
+		h.state.Unlock()
+		return false, fmt.Errorf("query session %s: %w", sessionID, err)
+	}
+	if !h.idle.IsAway(lastSeen, time.Now()) {
+		h.state.Unlock()
+		return false, nil
+	}
+
+	if _, err := h.state.DB().ExecContext(ctx,
+		"DELETE FROM subscriptions WHERE session_id = ?", sessionID); err != nil {
+		h.state.Unlock()
+		return false, fmt.Errorf("cleanup subscriptions for session %s: %w", sessionID, err)
+	}
+	postCommit, err := h.state.WriteEvent(ctx, types.AgentSessionEndEvent{
+		Type:      "agent.session.end",
+		Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
+		SessionID: sessionID,
+		Reason:    "stale",
+	})
+	h.state.Unlock()
+	if err != nil {
+		return false, fmt.Errorf("write session.end event for %s: %w", sessionID, err)
+	}
+	h.state.GoPostCommit(postCommit)
+	return true, nil
+}
+
 // worktreeExists reports whether the stored worktree identifier maps
 // to a live directory. Accepts either an absolute path (post
 // thrum-x6e8.2 / nu16 identity files) or a bare basename (legacy).
@@ -0,0 +1,177 @@
+package rpc
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/leonletto/thrum/internal/identity"
+	"github.com/leonletto/thrum/internal/types"
+)
+
+// AliasSetRequest represents the request for agent.alias.set RPC.
+type AliasSetRequest struct {
+	Name  string `json:"name"`  // Agent ID (or an existing alias of it)
+	Alias string `json:"alias"` // Nickname to assign
+}
+
+// AliasRemoveRequest represents the request for agent.alias.remove RPC.
+type AliasRemoveRequest struct {
+	Alias string `json:"alias"`
+}
+
+// AliasResponse represents the response from agent.alias.set and
+// agent.alias.remove.
+type AliasResponse struct {
+	AgentID string `json:"agent_id"`
+	Alias   string `json:"alias"`
+	Removed bool   `json:"removed,omitempty"`
+}
+
+// HandleAliasSet handles the agent.alias.set RPC method. The alias must not
+// collide with an agent ID, a role, a group name, or another agent's alias.
+// Re-assigning an alias to the agent that already holds it is a no-op.
+func (h *AgentHandler) HandleAliasSet(ctx context.Context, params json.RawMessage) (any, error) {
+	var req AliasSetRequest
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	name := strings.TrimPrefix(strings.TrimSpace(req.Name), "@")
+	alias := strings.TrimPrefix(strings.TrimSpace(req.Alias), "@")
+	if name == "" {
+		return nil, errors.New("agent name is required")
+	}
+	if err := identity.ValidateAlias(alias); err != nil {
+		return nil, err
+	}
+
+	// Checks and write share one lock so two concurrent sets can't both
+	// claim the same alias.
+	h.state.Lock()
+	agentID, err := identity.ResolveAlias(ctx, h.state.DB(), name)
+	if err != nil {
+		h.state.Unlock()
+		return nil, err
+	}
+	if _, err := h.getAgentByID(ctx, agentID); err != nil {
+		h.state.Unlock()
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("agent not found: %s", name)
+		}
+		return nil, fmt.Errorf("check agent existence: %w", err)
+	}
+
+	var owner string
+	err = h.state.DB().QueryRowContext(ctx,
+		`SELECT agent_id FROM agent_aliases WHERE alias = ?`, alias).Scan(&owner)
+	switch {
+	case err == nil && owner == agentID:
+		h.state.Unlock()
+		return &AliasResponse{AgentID: agentID, Alias: alias}, nil
+	case err == nil:
+		h.state.Unlock()
+		return nil, fmt.Errorf("alias %q is already assigned to @%s", alias, owner)
+	case !errors.Is(err, sql.ErrNoRows):
+		h.state.Unlock()
+		return nil, fmt.Errorf("query alias: %w", err)
+	}
+
+	var collision string
+	err = h.state.DB().QueryRowContext(ctx,
+		`SELECT CASE
+		     WHEN EXISTS (SELECT 1 FROM agents WHERE agent_id = ?1) THEN 'an agent name'
+		     WHEN EXISTS (SELECT 1 FROM agents WHERE role = ?1) THEN 'a role'
+		     WHEN EXISTS (SELECT 1 FROM groups WHERE name = ?1) THEN 'a group name'
+		     ELSE '' END`, alias).Scan(&collision)
+	if err != nil {
+		h.state.Unlock()
+		return nil, fmt.Errorf("check alias collisions: %w", err)
+	}
+	if collision != "" {
+		h.state.Unlock()
+		return nil, fmt.Errorf("alias %q collides with %s", alias, collision)
+	}
+
+	event := types.AgentAliasEvent{
+		Type:      "agent.alias",
+		Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
+		AgentID:   agentID,
+		Alias:     alias,
+	}
+	postCommit, err := h.state.WriteEvent(ctx, event)
+	h.state.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("write agent.alias event: %w", err)
+	}
+	h.state.GoPostCommit(postCommit)
+
+	return &AliasResponse{AgentID: agentID, Alias: alias}, nil
+}
+
+// HandleAliasRemove handles the agent.alias.remove RPC method.
+func (h *AgentHandler) HandleAliasRemove(ctx context.Context, params json.RawMessage) (any, error) {
+	var req AliasRemoveRequest
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	alias := strings.TrimPrefix(strings.TrimSpace(req.Alias), "@")
+	if alias == "" {
+		return nil, errors.New("alias is required")
+	}
+
+	h.state.Lock()
+	var agentID string
+	err := h.state.DB().QueryRowContext(ctx,
+		`SELECT agent_id FROM agent_aliases WHERE alias = ?`, alias).Scan(&agentID)
+	if errors.Is(err, sql.ErrNoRows) {
+		h.state.Unlock()
+		return nil, fmt.Errorf("alias not found: %s", alias)
+	}
+	if err != nil {
+		h.state.Unlock()
+		return nil, fmt.Errorf("query alias: %w", err)
+	}
+
+	event := types.AgentAliasEvent{
+		Type:      "agent.alias",
+		Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
+		AgentID:   agentID,
+		Alias:     alias,
+		Removed:   true,
+	}
+	postCommit, err := h.state.WriteEvent(ctx, event)
+	h.state.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("write agent.alias event: %w", err)
+	}
+	h.state.GoPostCommit(postCommit)
+
+	return &AliasResponse{AgentID: agentID, Alias: alias, Removed: true}, nil
+}
+
+// loadAliases returns agent_id → aliases (sorted). Callers must hold the
+// state lock (read or write).
+func (h *AgentHandler) loadAliases(ctx context.Context) (map[string][]string, error) {
+	rows, err := h.state.DB().QueryContext(ctx, `SELECT agent_id, alias FROM agent_aliases ORDER BY alias`)
+	if err != nil {
+		return nil, fmt.Errorf("query aliases: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	aliases := make(map[string][]string)
+	for rows.Next() {
+		var agentID, alias string
+		if err := rows.Scan(&agentID, &alias); err != nil {
+			return nil, fmt.Errorf("scan alias: %w", err)
+		}
+		aliases[agentID] = append(aliases[agentID], alias)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate aliases: %w", err)
+	}
+	return aliases, nil
+}
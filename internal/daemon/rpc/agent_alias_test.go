@@ -0,0 +1,107 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/leonletto/thrum/internal/identity"
+)
+
+func TestAgentAlias(t *testing.T) {
+	handler, agentID, cleanup := setupFilterTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	agents := NewAgentHandler(handler.state)
+	opsID := identity.GenerateAgentID("r_FILTER_TEST", "ops", "core", "")
+
+	setAlias := func(name, alias string) error {
+		t.Helper()
+		params, _ := json.Marshal(AliasSetRequest{Name: name, Alias: alias})
+		_, err := agents.HandleAliasSet(ctx, params)
+		return err
+	}
+
+	if err := setAlias("@"+opsID, "@oscar"); err != nil {
+		t.Fatalf("set alias: %v", err)
+	}
+	if err := setAlias(opsID, "oscar"); err != nil {
+		t.Errorf("re-setting the same alias should be a no-op, got %v", err)
+	}
+
+	for alias, want := range map[string]string{
+		"reviewer": "collides with a role",
+		agentID:    "collides with an agent name",
+		"everyone": "reserved",
+	} {
+		if err := setAlias(opsID, alias); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("set alias %q: err = %v, want %q", alias, err, want)
+		}
+	}
+	if err := setAlias(agentID, "oscar"); err == nil || !strings.Contains(err.Error(), "already assigned") {
+		t.Errorf("stealing an alias: err = %v, want already assigned", err)
+	}
+	if err := setAlias("nobody", "nemo"); err == nil || !strings.Contains(err.Error(), "agent not found") {
+		t.Errorf("alias for unknown agent: err = %v, want agent not found", err)
+	}
+
+	listResp, err := agents.HandleList(ctx, json.RawMessage(`{}`))
+	if err != nil {
+		t.Fatalf("HandleList: %v", err)
+	}
+	for _, a := range listResp.(*ListAgentsResponse).Agents {
+		if a.AgentID == opsID && (len(a.Aliases) != 1 || a.Aliases[0] != "oscar") {
+			t.Errorf("ops aliases = %v, want [oscar]", a.Aliases)
+		}
+	}
+
+	// --to and mentions both resolve the alias to the agent.
+	for _, req := range []SendRequest{
+		{Content: "direct", To: "@oscar", CallerAgentID: agentID},
+		{Content: "mention", Mentions: []string{"@oscar"}, CallerAgentID: agentID},
+	} {
+		params, _ := json.Marshal(req)
+		resp, err := handler.HandleSend(ctx, params)
+		if err != nil {
+			t.Fatalf("send %q: %v", req.Content, err)
+		}
+		recipients := resp.(*SendResponse).Recipients
+		if len(recipients) != 1 || recipients[0].AgentID != opsID {
+			t.Errorf("send %q recipients = %+v, want only %s", req.Content, recipients, opsID)
+		}
+	}
+
+	lookupResp, err := NewAgentLookupHandler(handler.state).HandleLookup(ctx, json.RawMessage(`{"name":"oscar"}`))
+	if err != nil {
+		t.Fatalf("HandleLookup: %v", err)
+	}
+	if m := lookupResp.(*AgentLookupResponse).Member; m == nil || m.AgentID != opsID {
+		t.Errorf("lookup by alias = %+v, want %s", m, opsID)
+	}
+
+	removeParams, _ := json.Marshal(AliasRemoveRequest{Alias: "oscar"})
+	if _, err := agents.HandleAliasRemove(ctx, removeParams); err != nil {
+		t.Fatalf("remove alias: %v", err)
+	}
+	if _, err := agents.HandleAliasRemove(ctx, removeParams); err == nil {
+		t.Error("removing a missing alias should fail")
+	}
+
+	// Deleting the agent drops its aliases.
+	if err := setAlias(opsID, "oz"); err != nil {
+		t.Fatalf("set alias: %v", err)
+	}
+	deleteParams, _ := json.Marshal(DeleteAgentRequest{Name: opsID})
+	if _, err := agents.HandleDelete(ctx, deleteParams); err != nil {
+		t.Fatalf("HandleDelete: %v", err)
+	}
+	var n int
+	if err := handler.state.RawDB().QueryRow(`SELECT COUNT(*) FROM agent_aliases WHERE agent_id = ?`, opsID).Scan(&n); err != nil {
+		t.Fatalf("count aliases: %v", err)
+	}
+	if n != 0 {
+		t.Errorf("aliases after agent delete = %d, want 0", n)
+	}
+}
@@ -0,0 +1,165 @@
+package rpc
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/leonletto/thrum/internal/identity"
+	"github.com/leonletto/thrum/internal/types"
+)
+
+// capabilityRegex matches a capability tag: lowercase letters, digits, and
+// ". _ + -", starting with a letter or digit (go, frontend, c++, k8s.ops).
+var capabilityRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9._+-]{0,63}$`)
+
+// CapabilitiesSetRequest represents the request for agent.capabilities.set RPC.
+type CapabilitiesSetRequest struct {
+	Name         string   `json:"name"`         // Agent ID (or an alias of it)
+	Capabilities []string `json:"capabilities"` // Full replacement set; empty clears
+}
+
+// CapabilitiesResponse represents the response from agent.capabilities.set.
+type CapabilitiesResponse struct {
+	AgentID      string   `json:"agent_id"`
+	Capabilities []string `json:"capabilities"`
+	Changed      bool     `json:"changed"`
+}
+
+// normalizeCapabilities lowercases, validates, de-duplicates, and sorts
+// capability tags. The result is never nil so an empty set round-trips as
+// [] rather than null.
+func normalizeCapabilities(caps []string) ([]string, error) {
+	out := []string{}
+	for _, c := range caps {
+		c = strings.ToLower(strings.TrimSpace(c))
+		if c == "" {
+			return nil, errors.New("capability cannot be empty")
+		}
+		if !capabilityRegex.MatchString(c) {
+			return nil, fmt.Errorf("capability %q is invalid; use lowercase letters, digits, '.', '_', '+', or '-' (max 64 characters)", c)
+		}
+		out = append(out, c)
+	}
+	slices.Sort(out)
+	return slices.Compact(out), nil
+}
+
+// HandleCapabilitiesSet handles the agent.capabilities.set RPC method. It
+// replaces the agent's capability set without re-registering the agent.
+func (h *AgentHandler) HandleCapabilitiesSet(ctx context.Context, params json.RawMessage) (any, error) {
+	var req CapabilitiesSetRequest
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	name := strings.TrimPrefix(strings.TrimSpace(req.Name), "@")
+	if name == "" {
+		return nil, errors.New("agent name is required")
+	}
+	caps, err := normalizeCapabilities(req.Capabilities)
+	if err != nil {
+		return nil, err
+	}
+
+	h.state.Lock()
+	agentID, err := identity.ResolveAlias(ctx, h.state.DB(), name)
+	if err != nil {
+		h.state.Unlock()
+		return nil, err
+	}
+	if _, err := h.getAgentByID(ctx, agentID); err != nil {
+		h.state.Unlock()
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("agent not found: %s", name)
+		}
+		return nil, fmt.Errorf("check agent existence: %w", err)
+	}
+	changed, postCommit, err := h.writeCapabilities(ctx, agentID, caps)
+	h.state.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	h.state.GoPostCommit(postCommit)
+
+	return &CapabilitiesResponse{AgentID: agentID, Capabilities: caps, Changed: changed}, nil
+}
+
+// writeCapabilities emits an agent.capabilities event replacing agentID's
+// set with caps (already normalized). It writes nothing when the set is
+// unchanged. Callers must hold the state write lock and fire the returned
+// postCommit after unlocking.
+func (h *AgentHandler) writeCapabilities(ctx context.Context, agentID string, caps []string) (bool, func(), error) {
+	current, err := h.agentCapabilities(ctx, agentID)
+	if err != nil {
+		return false, nil, err
+	}
+	if slices.Equal(current, caps) {
+		return false, nil, nil
+	}
+
+	event := types.AgentCapabilitiesEvent{
+		Type:         "agent.capabilities",
+		Timestamp:    time.Now().UTC().Format(time.RFC3339Nano),
+		AgentID:      agentID,
+		Capabilities: caps,
+	}
+	postCommit, err := h.state.WriteEvent(ctx, event)
+	if err != nil {
+		return false, nil, fmt.Errorf("write agent.capabilities event: %w", err)
+	}
+	return true, postCommit, nil
+}
+
+// agentCapabilities returns agentID's capabilities, sorted. Callers must
+// hold the state lock (read or write).
+func (h *AgentHandler) agentCapabilities(ctx context.Context, agentID string) ([]string, error) {
+	rows, err := h.state.DB().QueryContext(ctx,
+		`SELECT capability FROM agent_capabilities WHERE agent_id = ? ORDER BY capability`, agentID)
+	if err != nil {
+		return nil, fmt.Errorf("query capabilities: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	caps := []string{}
+	for rows.Next() {
+		var c string
+		if err := rows.Scan(&c); err != nil {
+			return nil, fmt.Errorf("scan capability: %w", err)
+		}
+		caps = append(caps, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate capabilities: %w", err)
+	}
+	return caps, nil
+}
+
+// loadCapabilities returns agent_id → capabilities (sorted). Callers must
+// hold the state lock (read or write).
+func (h *AgentHandler) loadCapabilities(ctx context.Context) (map[string][]string, error) {
+	rows, err := h.state.DB().QueryContext(ctx,
+		`SELECT agent_id, capability FROM agent_capabilities ORDER BY capability`)
+	if err != nil {
+		return nil, fmt.Errorf("query capabilities: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	caps := make(map[string][]string)
+	for rows.Next() {
+		var agentID, c string
+		if err := rows.Scan(&agentID, &c); err != nil {
+			return nil, fmt.Errorf("scan capability: %w", err)
+		}
+		caps[agentID] = append(caps[agentID], c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate capabilities: %w", err)
+	}
+	return caps, nil
+}
@@ -0,0 +1,138 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/leonletto/thrum/internal/identity"
+)
+
+func TestAgentCapabilities(t *testing.T) {
+	handler, agentID, cleanup := setupFilterTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	agents := NewAgentHandler(handler.state)
+	opsID := identity.GenerateAgentID("r_FILTER_TEST", "ops", "core", "")
+
+	// Register declares capabilities, normalized and de-duplicated.
+	regParams, _ := json.Marshal(RegisterRequest{
+		Name: "cap_builder", Role: "builder", Module: "api",
+		Capabilities: []string{"Go", " sql ", "go"},
+	})
+	regResp, err := agents.HandleRegister(ctx, regParams)
+	if err != nil {
+		t.Fatalf("HandleRegister: %v", err)
+	}
+	builderID := regResp.(*RegisterResponse).AgentID
+
+	whoCan := func(capability string) []string {
+		t.Helper()
+		params, _ := json.Marshal(ListAgentsRequest{Capability: capability})
+		resp, err := agents.HandleList(ctx, params)
+		if err != nil {
+			t.Fatalf("HandleList(capability=%s): %v", capability, err)
+		}
+		var ids []string
+		for _, a := range resp.(*ListAgentsResponse).Agents {
+			ids = append(ids, a.AgentID)
+		}
+		slices.Sort(ids)
+		return ids
+	}
+	setCaps := func(name string, caps ...string) (*CapabilitiesResponse, error) {
+		t.Helper()
+		params, _ := json.Marshal(CapabilitiesSetRequest{Name: name, Capabilities: caps})
+		resp, err := agents.HandleCapabilitiesSet(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		return resp.(*CapabilitiesResponse), nil
+	}
+
+	if got := whoCan("go"); !slices.Equal(got, []string{builderID}) {
+		t.Errorf("who-can go after register = %v, want [%s]", got, builderID)
+	}
+
+	// Editable later without re-registering; the set is replaced.
+	if _, err := setCaps("@"+opsID, "security", "go"); err != nil {
+		t.Fatalf("set ops capabilities: %v", err)
+	}
+	resp, err := setCaps(opsID, "go", "security")
+	if err != nil {
+		t.Fatalf("re-set ops capabilities: %v", err)
+	}
+	if resp.Changed {
+		t.Error("setting the same capabilities should report changed=false")
+	}
+	want := []string{builderID, opsID}
+	slices.Sort(want)
+	if got := whoCan("GO"); !slices.Equal(got, want) {
+		t.Errorf("who-can GO = %v, want %v", got, want)
+	}
+	if _, err := setCaps(builderID, "frontend"); err != nil {
+		t.Fatalf("replace builder capabilities: %v", err)
+	}
+	if got := whoCan("go"); !slices.Equal(got, []string{opsID}) {
+		t.Errorf("who-can go after replace = %v, want [%s]", got, opsID)
+	}
+
+	// Re-registering without capabilities keeps them.
+	regParams, _ = json.Marshal(RegisterRequest{Name: "cap_builder", Role: "builder", Module: "api", ReRegister: true})
+	if _, err := agents.HandleRegister(ctx, regParams); err != nil {
+		t.Fatalf("re-register: %v", err)
+	}
+	if got := whoCan("frontend"); !slices.Equal(got, []string{builderID}) {
+		t.Errorf("who-can frontend after plain re-register = %v, want [%s]", got, builderID)
+	}
+
+	for caps, wantErr := range map[string]string{
+		"":        "cannot be empty",
+		"go lang": "is invalid",
+		"-go":     "is invalid",
+	} {
+		if _, err := setCaps(opsID, caps); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("set capability %q: err = %v, want %q", caps, err, wantErr)
+		}
+	}
+	if _, err := setCaps("nobody", "go"); err == nil || !strings.Contains(err.Error(), "agent not found") {
+		t.Errorf("capabilities for unknown agent: err = %v, want agent not found", err)
+	}
+
+	// An empty set clears; list output carries capabilities.
+	if _, err := setCaps(opsID); err != nil {
+		t.Fatalf("clear capabilities: %v", err)
+	}
+	listResp, err := agents.HandleList(ctx, json.RawMessage(`{}`))
+	if err != nil {
+		t.Fatalf("HandleList: %v", err)
+	}
+	for _, a := range listResp.(*ListAgentsResponse).Agents {
+		switch a.AgentID {
+		case opsID, agentID:
+			if len(a.Capabilities) != 0 {
+				t.Errorf("%s capabilities = %v, want none", a.AgentID, a.Capabilities)
+			}
+		case builderID:
+			if !slices.Equal(a.Capabilities, []string{"frontend"}) {
+				t.Errorf("builder capabilities = %v, want [frontend]", a.Capabilities)
+			}
+		}
+	}
+
+	// Deleting the agent drops its capabilities.
+	deleteParams, _ := json.Marshal(DeleteAgentRequest{Name: builderID})
+	if _, err := agents.HandleDelete(ctx, deleteParams); err != nil {
+		t.Fatalf("HandleDelete: %v", err)
+	}
+	var n int
+	if err := handler.state.RawDB().QueryRow(`SELECT COUNT(*) FROM agent_capabilities WHERE agent_id = ?`, builderID).Scan(&n); err != nil {
+		t.Fatalf("count capabilities: %v", err)
+	}
+	if n != 0 {
+		t.Errorf("capabilities after agent delete = %d, want 0", n)
+	}
+}
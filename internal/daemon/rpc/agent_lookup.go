@@ -7,9 +7,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/leonletto/thrum/internal/config"
 	"github.com/leonletto/thrum/internal/daemon/state"
+	"github.com/leonletto/thrum/internal/identity"
 	"github.com/leonletto/thrum/internal/process"
 	ttmux "github.com/leonletto/thrum/internal/tmux"
 )
@@ -46,6 +48,7 @@ type AgentLookupResponse struct {
 // tmux_session for the recipient-stale hint.
 type AgentLookupHandler struct {
 	state *state.State
+	idle  *IdleThreshold // nil = never report "away"
 }
 
 // NewAgentLookupHandler creates a new agent.lookup handler.
@@ -53,6 +56,12 @@ func NewAgentLookupHandler(s *state.State) *AgentLookupHandler {
 	return &AgentLookupHandler{state: s}
 }
 
+// SetIdleThreshold wires the shared idle threshold used to report a silent
+// agent as "away". Call once during daemon startup.
+func (h *AgentLookupHandler) SetIdleThreshold(t *IdleThreshold) {
+	h.idle = t
+}
+
 // HandleLookup handles the agent.lookup RPC method.
 func (h *AgentLookupHandler) HandleLookup(ctx context.Context, params json.RawMessage) (any, error) {
 	var req AgentLookupRequest
@@ -86,7 +95,11 @@ func (h *AgentLookupHandler) HandleLookup(ctx context.Context, params json.RawMe
 	WHERE a.agent_id = ?
 	LIMIT 1`
 
-	row := h.state.DB().QueryRowContext(ctx, query, req.Name)
+	name, err := identity.ResolveAlias(ctx, h.state.DB(), req.Name)
+	if err != nil {
+		return nil, err
+	}
+	row := h.state.DB().QueryRowContext(ctx, query, name)
 
 	var m TeamMember
 	var display, hostname, originDaemon sql.NullString
@@ -140,6 +153,9 @@ func (h *AgentLookupHandler) HandleLookup(ctx context.Context, params json.RawMe
 
 	localDaemonID := h.state.DaemonID()
 	m.IsLocal = m.OriginDaemon == "" || m.OriginDaemon == localDaemonID
+	if m.Status == "active" && m.IsLocal && h.idle.IsAway(m.LastSeen, time.Now()) {
+		m.Status = "away"
+	}
 
 	// Identity-file enrichment: load the single agent's file directly
 	// instead of walking the whole identities dir. The os.ReadFile guard
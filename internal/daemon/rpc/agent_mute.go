@@ -0,0 +1,147 @@
+package rpc
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/leonletto/thrum/internal/identity"
+	"github.com/leonletto/thrum/internal/types"
+)
+
+// MuteRequest represents the request for agent.mute RPC.
+type MuteRequest struct {
+	Name          string `json:"name"`                     // Agent ID (or an alias of it)
+	Duration      string `json:"duration,omitempty"`       // Go duration; empty mutes until agent.unmute
+	AllowMentions bool   `json:"allow_mentions,omitempty"` // Direct @mentions still get pushed
+}
+
+// UnmuteRequest represents the request for agent.unmute RPC.
+type UnmuteRequest struct {
+	Name string `json:"name"` // Agent ID (or an alias of it)
+}
+
+// MuteResponse represents the response from agent.mute and agent.unmute.
+type MuteResponse struct {
+	AgentID       string `json:"agent_id"`
+	Muted         bool   `json:"muted"`
+	Until         string `json:"until,omitempty"` // RFC3339 UTC; empty when muted indefinitely
+	AllowMentions bool   `json:"allow_mentions,omitempty"`
+	Changed       bool   `json:"changed"` // false when unmuting an agent that wasn't muted
+}
+
+// HandleMute handles the agent.mute RPC method. The agent keeps receiving
+// messages in its inbox, but subscription pushes and wait wake-ups are held
+// back until the mute expires or is lifted. Muting again replaces the
+// previous mute.
+func (h *AgentHandler) HandleMute(ctx context.Context, params json.RawMessage) (any, error) {
+	var req MuteRequest
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	var duration time.Duration
+	if req.Duration != "" {
+		d, err := time.ParseDuration(req.Duration)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid duration %q: must be a positive duration like 30m or 2h", req.Duration)
+		}
+		duration = d
+	}
+
+	now := time.Now().UTC()
+	resp := &MuteResponse{Muted: true, AllowMentions: req.AllowMentions, Changed: true}
+	if duration > 0 {
+		resp.Until = now.Add(duration).Format(time.RFC3339)
+	}
+
+	h.state.Lock()
+	agentID, err := h.resolveMuteTarget(ctx, req.Name)
+	if err != nil {
+		h.state.Unlock()
+		return nil, err
+	}
+	resp.AgentID = agentID
+
+	event := types.AgentMuteEvent{
+		Type:          "agent.mute",
+		Timestamp:     now.Format(time.RFC3339Nano),
+		AgentID:       agentID,
+		Until:         resp.Until,
+		AllowMentions: req.AllowMentions,
+	}
+	postCommit, err := h.state.WriteEvent(ctx, event)
+	h.state.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("write agent.mute event: %w", err)
+	}
+	h.state.GoPostCommit(postCommit)
+
+	return resp, nil
+}
+
+// HandleUnmute handles the agent.unmute RPC method. Unmuting an agent that
+// isn't muted, or whose mute already expired, succeeds without writing.
+func (h *AgentHandler) HandleUnmute(ctx context.Context, params json.RawMessage) (any, error) {
+	var req UnmuteRequest
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	h.state.Lock()
+	agentID, err := h.resolveMuteTarget(ctx, req.Name)
+	if err != nil {
+		h.state.Unlock()
+		return nil, err
+	}
+
+	var exists bool
+	if err := h.state.DB().QueryRowContext(ctx,
+		`SELECT EXISTS(SELECT 1 FROM agent_mutes WHERE agent_id = ?)`, agentID,
+	).Scan(&exists); err != nil {
+		h.state.Unlock()
+		return nil, fmt.Errorf("query mute: %w", err)
+	}
+	if !exists {
+		h.state.Unlock()
+		return &MuteResponse{AgentID: agentID}, nil
+	}
+
+	event := types.AgentMuteEvent{
+		Type:      "agent.mute",
+		Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
+		AgentID:   agentID,
+		Removed:   true,
+	}
+	postCommit, err := h.state.WriteEvent(ctx, event)
+	h.state.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("write agent.mute event: %w", err)
+	}
+	h.state.GoPostCommit(postCommit)
+
+	return &MuteResponse{AgentID: agentID, Changed: true}, nil
+}
+
+// resolveMuteTarget resolves name (an agent ID or alias) to a registered
+// agent ID. Callers must hold the state lock.
+func (h *AgentHandler) resolveMuteTarget(ctx context.Context, name string) (string, error) {
+	name = strings.TrimPrefix(strings.TrimSpace(name), "@")
+	if name == "" {
+		return "", errors.New("agent name is required")
+	}
+	agentID, err := identity.ResolveAlias(ctx, h.state.DB(), name)
+	if err != nil {
+		return "", err
+	}
+	if _, err := h.getAgentByID(ctx, agentID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("agent not found: %s", name)
+		}
+		return "", fmt.Errorf("check agent existence: %w", err)
+	}
+	return agentID, nil
+}
@@ -0,0 +1,113 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/leonletto/thrum/internal/identity"
+)
+
+func TestAgentMute(t *testing.T) {
+	handler, agentID, cleanup := setupFilterTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	agents := NewAgentHandler(handler.state)
+	opsID := identity.GenerateAgentID("r_FILTER_TEST", "ops", "core", "")
+
+	mute := func(req MuteRequest) (*MuteResponse, error) {
+		t.Helper()
+		params, _ := json.Marshal(req)
+		resp, err := agents.HandleMute(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		return resp.(*MuteResponse), nil
+	}
+	unmute := func(name string) *MuteResponse {
+		t.Helper()
+		params, _ := json.Marshal(UnmuteRequest{Name: name})
+		resp, err := agents.HandleUnmute(ctx, params)
+		if err != nil {
+			t.Fatalf("HandleUnmute: %v", err)
+		}
+		return resp.(*MuteResponse)
+	}
+	send := func(to string) {
+		t.Helper()
+		params, _ := json.Marshal(SendRequest{Content: "hello " + to, To: to, CallerAgentID: agentID})
+		if _, err := handler.HandleSend(ctx, params); err != nil {
+			t.Fatalf("send to %s: %v", to, err)
+		}
+	}
+	waitSees := func() int {
+		t.Helper()
+		params, _ := json.Marshal(ListMessagesRequest{ForAgent: opsID, CallerAgentID: opsID, ExcludeSelf: true, RespectMute: true})
+		resp, err := handler.HandleList(ctx, params)
+		if err != nil {
+			t.Fatalf("HandleList: %v", err)
+		}
+		return len(resp.(*ListMessagesResponse).Messages)
+	}
+
+	send("@" + opsID)
+	send("@everyone")
+	if n := waitSees(); n != 2 {
+		t.Fatalf("unmuted wait sees %d messages, want 2", n)
+	}
+
+	resp, err := mute(MuteRequest{Name: "@" + opsID, Duration: "30m"})
+	if err != nil {
+		t.Fatalf("HandleMute: %v", err)
+	}
+	if resp.AgentID != opsID || !resp.Muted || resp.Until == "" {
+		t.Errorf("mute = %+v; want muted %s with an expiry", resp, opsID)
+	}
+	if n := waitSees(); n != 0 {
+		t.Errorf("muted wait sees %d messages, want 0", n)
+	}
+
+	// Direct mentions get through with allow_mentions; @everyone doesn't.
+	if _, err := mute(MuteRequest{Name: opsID, AllowMentions: true}); err != nil {
+		t.Fatalf("HandleMute allow_mentions: %v", err)
+	}
+	if n := waitSees(); n != 1 {
+		t.Errorf("wait with allow_mentions sees %d messages, want 1 (the direct mention)", n)
+	}
+
+	// The inbox itself is unaffected.
+	params, _ := json.Marshal(ListMessagesRequest{ForAgent: opsID, CallerAgentID: opsID, ExcludeSelf: true})
+	inbox, err := handler.HandleList(ctx, params)
+	if err != nil {
+		t.Fatalf("HandleList inbox: %v", err)
+	}
+	if n := len(inbox.(*ListMessagesResponse).Messages); n != 2 {
+		t.Errorf("muted inbox shows %d messages, want 2", n)
+	}
+
+	// An expired mute no longer hides anything.
+	if _, err := handler.state.DB().ExecContext(ctx,
+		`UPDATE agent_mutes SET muted_until = '2000-01-01T00:00:00Z', allow_mentions = 0 WHERE agent_id = ?`, opsID,
+	); err != nil {
+		t.Fatalf("expire mute: %v", err)
+	}
+	if n := waitSees(); n != 2 {
+		t.Errorf("wait after expiry sees %d messages, want 2", n)
+	}
+
+	if got := unmute(opsID); got.Muted || !got.Changed {
+		t.Errorf("unmute = %+v; want changed", got)
+	}
+	if got := unmute(opsID); got.Changed {
+		t.Errorf("second unmute = %+v; want unchanged", got)
+	}
+
+	if _, err := mute(MuteRequest{Name: opsID, Duration: "-5m"}); err == nil || !strings.Contains(err.Error(), "invalid duration") {
+		t.Errorf("negative duration: err = %v", err)
+	}
+	if _, err := mute(MuteRequest{Name: "nobody"}); err == nil || !strings.Contains(err.Error(), "agent not found") {
+		t.Errorf("unknown agent: err = %v", err)
+	}
+}
@@ -0,0 +1,142 @@
+package rpc
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/leonletto/thrum/internal/config"
+	agentcontext "github.com/leonletto/thrum/internal/context"
+	"github.com/leonletto/thrum/internal/identity"
+	"github.com/leonletto/thrum/internal/types"
+)
+
+// RenameAgentRequest represents the request for agent.rename RPC.
+type RenameAgentRequest struct {
+	Name    string `json:"name"`     // Current agent name (or an alias of it)
+	NewName string `json:"new_name"` // Name to rename to
+}
+
+// RenameAgentResponse represents the response from agent.rename RPC.
+type RenameAgentResponse struct {
+	AgentID    string `json:"agent_id"`
+	OldAgentID string `json:"old_agent_id"`
+}
+
+// HandleRename handles the agent.rename RPC method. The agent row and every
+// row keyed by its ID — sessions (including an active one), messages,
+// deliveries, reads, reactions, aliases, and agent group memberships — move
+// to the new name via an agent.rename event. The identity file and context
+// files are then renamed on disk.
+func (h *AgentHandler) HandleRename(ctx context.Context, params json.RawMessage) (any, error) {
+	var req RenameAgentRequest
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	name := strings.TrimPrefix(strings.TrimSpace(req.Name), "@")
+	newName := strings.TrimPrefix(strings.TrimSpace(req.NewName), "@")
+	if name == "" {
+		return nil, errors.New("agent name is required")
+	}
+	if err := identity.ValidateAgentName(newName); err != nil {
+		return nil, fmt.Errorf("invalid new name: %w", err)
+	}
+
+	// Checks and write share one lock so a concurrent register or rename
+	// can't claim the new name in between.
+	h.state.Lock()
+	oldID, err := identity.ResolveAlias(ctx, h.state.DB(), name)
+	if err != nil {
+		h.state.Unlock()
+		return nil, err
+	}
+	if _, err := h.getAgentByID(ctx, oldID); err != nil {
+		h.state.Unlock()
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("agent not found: %s", name)
+		}
+		return nil, fmt.Errorf("check agent existence: %w", err)
+	}
+	if newName == oldID {
+		h.state.Unlock()
+		return nil, fmt.Errorf("agent is already named %s", oldID)
+	}
+
+	var collision string
+	err = h.state.DB().QueryRowContext(ctx,
+		`SELECT CASE
+		     WHEN EXISTS (SELECT 1 FROM agents WHERE agent_id = ?1) THEN 'an existing agent'
+		     WHEN EXISTS (SELECT 1 FROM agent_aliases WHERE alias = ?1) THEN 'an alias'
+		     WHEN EXISTS (SELECT 1 FROM agents WHERE role = ?1) THEN 'a role'
+		     WHEN EXISTS (SELECT 1 FROM groups WHERE name = ?1) THEN 'a group name'
+		     ELSE '' END`, newName).Scan(&collision)
+	if err != nil {
+		h.state.Unlock()
+		return nil, fmt.Errorf("check name collisions: %w", err)
+	}
+	if collision != "" {
+		h.state.Unlock()
+		return nil, fmt.Errorf("name %q is already in use by %s", newName, collision)
+	}
+
+	event := types.AgentRenameEvent{
+		Type:       "agent.rename",
+		Timestamp:  time.Now().UTC().Format(time.RFC3339Nano),
+		AgentID:    newName,
+		OldAgentID: oldID,
+	}
+	postCommit, err := h.state.WriteEvent(ctx, event)
+	h.state.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("write agent.rename event: %w", err)
+	}
+	h.state.GoPostCommit(postCommit)
+
+	// File I/O without lock
+	if err := h.renameAgentFiles(ctx, oldID, newName); err != nil {
+		return nil, err
+	}
+
+	return &RenameAgentResponse{AgentID: newName, OldAgentID: oldID}, nil
+}
+
+// renameAgentFiles moves the identity file (wherever its worktree keeps it)
+// and the context and preamble files from oldName to newName. Missing files
+// are skipped: not every agent has a context file, and remote agents have no
+// local identity.
+func (h *AgentHandler) renameAgentFiles(ctx context.Context, oldName, newName string) error {
+	if idFile, idPath, err := h.findAgentIdentity(ctx, oldName); err == nil {
+		idThrumDir := filepath.Dir(filepath.Dir(idPath))
+		idFile.Agent.Name = newName
+		if idFile.Agent.Display == oldName {
+			idFile.Agent.Display = newName
+		}
+		if idFile.ContextFile == fmt.Sprintf("context/%s.md", oldName) {
+			idFile.ContextFile = fmt.Sprintf("context/%s.md", newName)
+		}
+		if err := config.SaveIdentityFile(idThrumDir, idFile); err != nil {
+			return fmt.Errorf("save identity file: %w", err)
+		}
+		if err := os.Remove(idPath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("remove old identity file: %w", err)
+		}
+	}
+
+	thrumDir := filepath.Join(h.state.RepoPath(), ".thrum")
+	renames := [][2]string{
+		{filepath.Join(thrumDir, "context", oldName+".md"), filepath.Join(thrumDir, "context", newName+".md")},
+		{agentcontext.PreamblePath(thrumDir, oldName), agentcontext.PreamblePath(thrumDir, newName)},
+	}
+	for _, r := range renames {
+		if err := os.Rename(r[0], r[1]); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("rename %s: %w", filepath.Base(r[0]), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/leonletto/thrum/internal/config"
+	"github.com/leonletto/thrum/internal/identity"
+)
+
+func TestAgentRename(t *testing.T) {
+	handler, agentID, cleanup := setupFilterTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	db := handler.state.RawDB()
+	agents := NewAgentHandler(handler.state)
+	opsID := identity.GenerateAgentID("r_FILTER_TEST", "ops", "core", "")
+	thrumDir := filepath.Join(handler.state.RepoPath(), ".thrum")
+
+	if err := config.SaveIdentityFile(thrumDir, &config.IdentityFile{
+		Version:     1,
+		Agent:       config.AgentConfig{Kind: "agent", Name: opsID, Role: "ops", Module: "core"},
+		ContextFile: "context/" + opsID + ".md",
+	}); err != nil {
+		t.Fatalf("save identity: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(thrumDir, "context"), 0o750); err != nil {
+		t.Fatalf("create context dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(thrumDir, "context", opsID+".md"), []byte("notes"), 0o600); err != nil {
+		t.Fatalf("write context: %v", err)
+	}
+
+	// ops sends one message and receives one; it also has an alias, two
+	// capabilities, and a group membership.
+	for _, req := range []SendRequest{
+		{Content: "from ops", To: "@" + agentID, CallerAgentID: opsID},
+		{Content: "to ops", To: "@" + opsID, CallerAgentID: agentID},
+	} {
+		params, _ := json.Marshal(req)
+		if _, err := handler.HandleSend(ctx, params); err != nil {
+			t.Fatalf("send %q: %v", req.Content, err)
+		}
+	}
+	aliasParams, _ := json.Marshal(AliasSetRequest{Name: opsID, Alias: "oscar"})
+	if _, err := agents.HandleAliasSet(ctx, aliasParams); err != nil {
+		t.Fatalf("set alias: %v", err)
+	}
+	capsParams, _ := json.Marshal(CapabilitiesSetRequest{Name: opsID, Capabilities: []string{"go", "security"}})
+	if _, err := agents.HandleCapabilitiesSet(ctx, capsParams); err != nil {
+		t.Fatalf("set capabilities: %v", err)
+	}
+	groups := NewGroupHandler(handler.state)
+	createParams, _ := json.Marshal(GroupCreateRequest{Name: "oncall", CallerAgentID: agentID})
+	if _, err := groups.HandleCreate(ctx, createParams); err != nil {
+		t.Fatalf("create group: %v", err)
+	}
+	addParams, _ := json.Marshal(GroupMemberAddRequest{Group: "oncall", MemberType: "agent", MemberValue: opsID, CallerAgentID: agentID})
+	if _, err := groups.HandleMemberAdd(ctx, addParams); err != nil {
+		t.Fatalf("add group member: %v", err)
+	}
+
+	rename := func(name, newName string) error {
+		t.Helper()
+		params, _ := json.Marshal(RenameAgentRequest{Name: name, NewName: newName})
+		_, err := agents.HandleRename(ctx, params)
+		return err
+	}
+
+	for newName, want := range map[string]string{
+		agentID:    "an existing agent",
+		"oscar":    "an alias",
+		"reviewer": "a role",
+		"oncall":   "a group name",
+		"Bad Name": "invalid new name",
+		opsID:      "already named",
+	} {
+		if err := rename(opsID, newName); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("rename to %q: err = %v, want %q", newName, err, want)
+		}
+	}
+	if err := rename("nobody", "somebody"); err == nil || !strings.Contains(err.Error(), "agent not found") {
+		t.Errorf("rename unknown agent: err = %v, want agent not found", err)
+	}
+
+	// Rename via the alias.
+	if err := rename("@oscar", "@operator"); err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+
+	count := func(query string, args ...any) int {
+		t.Helper()
+		var n int
+		if err := db.QueryRow(query, args...).Scan(&n); err != nil {
+			t.Fatalf("%s: %v", query, err)
+		}
+		return n
+	}
+	for _, c := range []struct {
+		what, query string
+		want        int
+	}{
+		{"agent row", `SELECT COUNT(*) FROM agents WHERE agent_id = ?`, 1},
+		{"active session", `SELECT COUNT(*) FROM sessions WHERE agent_id = ? AND ended_at IS NULL`, 1},
+		{"sent messages", `SELECT COUNT(*) FROM messages WHERE agent_id = ?`, 1},
+		{"deliveries (received + sender's own)", `SELECT COUNT(*) FROM message_deliveries WHERE recipient_agent_id = ?`, 2},
+		{"aliases", `SELECT COUNT(*) FROM agent_aliases WHERE agent_id = ?`, 1},
+		{"capabilities", `SELECT COUNT(*) FROM agent_capabilities WHERE agent_id = ?`, 2},
+		{"group memberships", `SELECT COUNT(*) FROM group_members WHERE member_type = 'agent' AND member_value = ?`, 1},
+	} {
+		if got := count(c.query, "operator"); got != c.want {
+			t.Errorf("%s for new name = %d, want %d", c.what, got, c.want)
+		}
+		if got := count(c.query, opsID); got != 0 {
+			t.Errorf("%s left on old name = %d, want 0", c.what, got)
+		}
+	}
+
+	idFile, err := loadTestIdentity(filepath.Join(thrumDir, "identities", "operator.json"))
+	if err != nil {
+		t.Fatalf("load renamed identity: %v", err)
+	}
+	if idFile.Agent.Name != "operator" || idFile.ContextFile != "context/operator.md" {
+		t.Errorf("identity = %+v, want name operator and context/operator.md", idFile)
+	}
+	if _, err := os.Stat(filepath.Join(thrumDir, "identities", opsID+".json")); !os.IsNotExist(err) {
+		t.Errorf("old identity file still present (stat err = %v)", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(thrumDir, "context", "operator.md")); err != nil || string(data) != "notes" {
+		t.Errorf("renamed context = %q, %v; want notes", data, err)
+	}
+
+	// The renamed agent is still reachable and can keep sending.
+	params, _ := json.Marshal(SendRequest{Content: "still here", To: "@" + agentID, CallerAgentID: "operator"})
+	if _, err := handler.HandleSend(ctx, params); err != nil {
+		t.Errorf("send as renamed agent: %v", err)
+	}
+}
+
+func loadTestIdentity(path string) (*config.IdentityFile, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- test path
+	if err != nil {
+		return nil, err
+	}
+	var idFile config.IdentityFile
+	if err := json.Unmarshal(data, &idFile); err != nil {
+		return nil, err
+	}
+	return &idFile, nil
+}
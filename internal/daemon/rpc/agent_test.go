@@ -1297,6 +1297,110 @@ func TestHandleCleanup_DryRun(t *testing.T) {
 	})
 }
 
+func TestHandleCleanup_StaleSessions(t *testing.T) {
+	tmpDir := t.TempDir()
+	thrumDir := filepath.Join(tmpDir, ".thrum")
+
+	s, err := state.NewState(thrumDir, thrumDir, "test_repo_123", "d_local")
+	if err != nil {
+		t.Fatalf("create state: %v", err)
+	}
+	defer func() { _ = s.Close() }()
+
+	agentHandler := NewAgentHandler(s)
+	sessionHandler := NewSessionHandler(s)
+	ctx := context.Background()
+
+	startSession := func(role string) string {
+		t.Helper()
+		registerJSON, _ := json.Marshal(RegisterRequest{Role: role, Module: "test"})
+		resp, err := agentHandler.HandleRegister(ctx, registerJSON)
+		if err != nil {
+			t.Fatalf("register %s: %v", role, err)
+		}
+		agentID := resp.(*RegisterResponse).AgentID
+		// An identity file keeps the agent out of the orphan pass, which
+		// would otherwise delete it along with its sessions under Force.
+		identityPath := filepath.Join(thrumDir, "identities", agentID+".json")
+		if err := os.MkdirAll(filepath.Dir(identityPath), 0o750); err != nil {
+			t.Fatalf("create identities dir: %v", err)
+		}
+		if err := os.WriteFile(identityPath, []byte(`{"agent":{"name":"`+agentID+`"}}`), 0o600); err != nil {
+			t.Fatalf("write identity file: %v", err)
+		}
+		startJSON, _ := json.Marshal(SessionStartRequest{AgentID: agentID})
+		sessResp, err := sessionHandler.HandleStart(ctx, startJSON)
+		if err != nil {
+			t.Fatalf("start session for %s: %v", role, err)
+		}
+		return sessResp.(*SessionStartResponse).SessionID
+	}
+	silentSession := startSession("tester")
+	liveSession := startSession("reviewer")
+
+	hourAgo := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339Nano)
+	if _, err := s.RawDB().Exec(`UPDATE sessions SET last_seen_at = ? WHERE session_id = ?`, hourAgo, silentSession); err != nil {
+		t.Fatalf("age session: %v", err)
+	}
+	// A peer's agent: its heartbeats never reach this daemon, so it always
+	// looks silent and must be left alone.
+	if _, err := s.RawDB().Exec(`INSERT INTO agents (agent_id, kind, role, module, registered_at, origin_daemon)
+		VALUES ('remote_agent', 'agent', 'tester', 'test', ?, 'd_peer')`, hourAgo); err != nil {
+		t.Fatalf("insert remote agent: %v", err)
+	}
+	if _, err := s.RawDB().Exec(`INSERT INTO sessions (session_id, agent_id, started_at, last_seen_at)
+		VALUES ('ses_remote', 'remote_agent', ?, ?)`, hourAgo, hourAgo); err != nil {
+		t.Fatalf("insert remote session: %v", err)
+	}
+
+	cleanup := func(req CleanupAgentRequest) (*CleanupAgentResponse, error) {
+		req.Threshold = 9999
+		req.StaleSessions = true
+		cleanupJSON, _ := json.Marshal(req)
+		resp, err := agentHandler.HandleCleanup(ctx, cleanupJSON)
+		if err != nil {
+			return nil, err
+		}
+		return resp.(*CleanupAgentResponse), nil
+	}
+
+	if _, err := cleanup(CleanupAgentRequest{DryRun: true}); err == nil {
+		t.Error("expected an error with away detection disabled")
+	}
+
+	idle := &IdleThreshold{}
+	idle.Set(5 * time.Minute)
+	agentHandler.SetIdleThreshold(idle)
+
+	dry, err := cleanup(CleanupAgentRequest{DryRun: true})
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if len(dry.StaleSessions) != 1 || dry.StaleSessions[0].SessionID != silentSession {
+		t.Fatalf("stale sessions = %+v, want only %s", dry.StaleSessions, silentSession)
+	}
+	if dry.StaleSessions[0].IdleSeconds < 3600 {
+		t.Errorf("idle_seconds = %d, want at least an hour", dry.StaleSessions[0].IdleSeconds)
+	}
+
+	forced, err := cleanup(CleanupAgentRequest{Force: true})
+	if err != nil {
+		t.Fatalf("force: %v", err)
+	}
+	if len(forced.EndedSessions) != 1 || forced.EndedSessions[0] != silentSession {
+		t.Errorf("ended sessions = %v, want [%s]", forced.EndedSessions, silentSession)
+	}
+	for id, want := range map[string]string{silentSession: "stale", liveSession: ""} {
+		var reason string
+		if err := s.RawDB().QueryRow(`SELECT COALESCE(end_reason, '') FROM sessions WHERE session_id = ?`, id).Scan(&reason); err != nil {
+			t.Fatalf("query %s: %v", id, err)
+		}
+		if reason != want {
+			t.Errorf("%s end_reason = %q, want %q", id, reason, want)
+		}
+	}
+}
+
 func TestGetMessageCount(t *testing.T) {
 	tmpDir := t.TempDir()
 	thrumDir := filepath.Join(tmpDir, ".thrum")
@@ -14,6 +14,7 @@ import (
 	"github.com/leonletto/thrum/internal/groups"
 	"github.com/leonletto/thrum/internal/identity"
 	"github.com/leonletto/thrum/internal/identity/guard"
+	"github.com/leonletto/thrum/internal/schema"
 	"github.com/leonletto/thrum/internal/types"
 )
 
@@ -21,6 +22,7 @@ import (
 type GroupHandler struct {
 	state    *state.State
 	resolver *groups.Resolver
+	idle     *IdleThreshold // nil = never report "away"
 }
 
 // NewGroupHandler creates a new group handler.
@@ -31,6 +33,12 @@ func NewGroupHandler(st *state.State) *GroupHandler {
 	}
 }
 
+// SetIdleThreshold wires the shared idle threshold used to report a silent
+// member as "away" in group.info. Call once during daemon startup.
+func (h *GroupHandler) SetIdleThreshold(t *IdleThreshold) {
+	h.idle = t
+}
+
 // -- Request/Response types --
 
 // GroupCreateRequest is the request for group.create RPC.
@@ -60,10 +68,42 @@ type GroupDeleteResponse struct {
 	DeletedAt string `json:"deleted_at"`
 }
 
+// GroupRenameRequest is the request for group.rename RPC.
+type GroupRenameRequest struct {
+	Name          string `json:"name"`
+	NewName       string `json:"new_name"`
+	CallerAgentID string `json:"caller_agent_id,omitempty"`
+}
+
+// GroupRenameResponse is the response from group.rename RPC.
+type GroupRenameResponse struct {
+	GroupID   string `json:"group_id"`
+	OldName   string `json:"old_name"`
+	Name      string `json:"name"`
+	RenamedAt string `json:"renamed_at"`
+}
+
+// GroupCopyRequest is the request for group.copy RPC.
+type GroupCopyRequest struct {
+	Source        string `json:"source"`
+	Dest          string `json:"dest"`
+	Expand        bool   `json:"expand,omitempty"` // Replace nested group members with their own members
+	CallerAgentID string `json:"caller_agent_id,omitempty"`
+}
+
+// GroupCopyResponse is the response from group.copy RPC.
+type GroupCopyResponse struct {
+	GroupID   string        `json:"group_id"`
+	Name      string        `json:"name"`
+	Source    string        `json:"source"`
+	Members   []GroupMember `json:"members"`
+	CreatedAt string        `json:"created_at"`
+}
+
 // GroupMemberAddRequest is the request for group.member.add RPC.
 type GroupMemberAddRequest struct {
 	Group         string `json:"group"`
-	MemberType    string `json:"member_type"` // "agent", "role"
+	MemberType    string `json:"member_type"` // "agent", "role", "group"
 	MemberValue   string `json:"member_value"`
 	CallerAgentID string `json:"caller_agent_id,omitempty"`
 }
@@ -120,6 +160,20 @@ type GroupInfoResponse struct {
 	CreatedAt   string        `json:"created_at"`
 	CreatedBy   string        `json:"created_by"`
 	Members     []GroupMember `json:"members"`
+	// Tree is Members resolved recursively: roles to their agents and nested
+	// groups to their own members, with each agent's presence.
+	Tree       []GroupTreeNode `json:"tree"`
+	AgentCount int             `json:"agent_count"` // distinct agents in Tree
+	Online     int             `json:"online"`      // of those, how many are "active"
+}
+
+// GroupTreeNode is one member in group.info's membership tree.
+type GroupTreeNode struct {
+	MemberType  string          `json:"member_type"` // "agent", "role", or "group"
+	MemberValue string          `json:"member_value"`
+	Status      string          `json:"status,omitempty"`   // agents only: "active", "away", or "offline"
+	Children    []GroupTreeNode `json:"children,omitempty"` // a role's agents or a nested group's members
+	Cycle       bool            `json:"cycle,omitempty"`    // group already above this node; not expanded
 }
 
 // GroupMember represents a member of a group.
@@ -140,6 +194,7 @@ type GroupMembersRequest struct {
 type GroupMembersResponse struct {
 	Members  []GroupMember `json:"members"`
 	Expanded []string      `json:"expanded,omitempty"`
+	Cycles   [][]string    `json:"cycles,omitempty"` // expand only: nested-group cycles, e.g. [a b a]
 }
 
 // resolveGroupCaller authenticates the caller for group-mutation RPCs
@@ -317,7 +372,7 @@ func (h *GroupHandler) HandleDelete(ctx context.Context, params json.RawMessage)
 			}
 			inClause := strings.Join(placeholders, ",")
 
-			for _, table := range []string{"message_edits", "message_reads", "message_deliveries", "message_refs", "message_scopes"} {
+			for _, table := range schema.MessageChildTables {
 				if _, err := h.state.DB().ExecContext(ctx,
 					fmt.Sprintf("DELETE FROM %s WHERE message_id IN (%s)", table, inClause),
 					args...); err != nil {
@@ -347,6 +402,227 @@ func (h *GroupHandler) HandleDelete(ctx context.Context, params json.RawMessage)
 	}, nil
 }
 
+// HandleRename handles the group.rename RPC method. The rename is written as a
+// group.update event; the projector moves group-scoped messages to the new
+// name so existing inbox filtering keeps working.
+func (h *GroupHandler) HandleRename(ctx context.Context, params json.RawMessage) (any, error) {
+	var req GroupRenameRequest
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	if req.Name == "" || req.NewName == "" {
+		return nil, fmt.Errorf("name and new_name are required")
+	}
+	if req.Name == "everyone" || req.NewName == "everyone" {
+		return nil, fmt.Errorf("cannot rename to or from built-in @everyone group")
+	}
+	if req.Name == req.NewName {
+		return nil, fmt.Errorf("group is already named %q", req.Name)
+	}
+
+	h.state.RLock()
+	var groupID string
+	var taken bool
+	err := h.state.DB().QueryRowContext(ctx, "SELECT group_id FROM groups WHERE name = ?", req.Name).Scan(&groupID)
+	if err == nil {
+		taken, err = h.resolver.IsGroup(ctx, req.NewName)
+	}
+	h.state.RUnlock()
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("group %q not found", req.Name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query group: %w", err)
+	}
+	if taken {
+		return nil, fmt.Errorf("group %q already exists", req.NewName)
+	}
+
+	updatedBy, err := h.resolveGroupCaller(ctx, req.CallerAgentID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+
+	event := types.GroupUpdateEvent{
+		Type:      "group.update",
+		Timestamp: now,
+		GroupID:   groupID,
+		UpdatedBy: updatedBy,
+		Fields:    map[string]string{"name": req.NewName},
+	}
+
+	// thrum-bsn7: release state.Lock() before postCommit fires.
+	h.state.Lock()
+	postCommit, err := h.state.WriteEvent(ctx, event)
+	h.state.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("write group.update event: %w", err)
+	}
+	h.state.GoPostCommit(postCommit)
+
+	return &GroupRenameResponse{
+		GroupID:   groupID,
+		OldName:   req.Name,
+		Name:      req.NewName,
+		RenamedAt: now,
+	}, nil
+}
+
+// HandleCopy handles the group.copy RPC method. It creates the destination
+// group with the source's description and gives it the source's member
+// rows. Nested group members are copied as group references, or with
+// Expand replaced by the agent and role members they contain.
+func (h *GroupHandler) HandleCopy(ctx context.Context, params json.RawMessage) (any, error) {
+	var req GroupCopyRequest
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	if req.Source == "" || req.Dest == "" {
+		return nil, fmt.Errorf("source and dest are required")
+	}
+	if req.Dest == "everyone" {
+		return nil, fmt.Errorf("cannot copy to built-in @everyone group")
+	}
+	if req.Source == req.Dest {
+		return nil, fmt.Errorf("source and dest are the same group %q", req.Source)
+	}
+
+	h.state.RLock()
+	var description sql.NullString
+	err := h.state.DB().QueryRowContext(ctx, "SELECT description FROM groups WHERE name = ?", req.Source).Scan(&description)
+	var taken bool
+	if err == nil {
+		taken, err = h.resolver.IsGroup(ctx, req.Dest)
+	}
+	var members []GroupMember
+	if err == nil && !taken {
+		members, err = h.copyMembers(ctx, req.Source, req.Expand)
+	}
+	h.state.RUnlock()
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("group %q not found", req.Source)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query group: %w", err)
+	}
+	if taken {
+		return nil, fmt.Errorf("group %q already exists", req.Dest)
+	}
+
+	createdBy, err := h.resolveGroupCaller(ctx, req.CallerAgentID)
+	if err != nil {
+		return nil, err
+	}
+	groupID := identity.GenerateGroupID()
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+
+	events := []any{types.GroupCreateEvent{
+		Type:        "group.create",
+		Timestamp:   now,
+		GroupID:     groupID,
+		Name:        req.Dest,
+		Description: description.String,
+		CreatedBy:   createdBy,
+	}}
+	for i := range members {
+		members[i].AddedAt = now
+		members[i].AddedBy = createdBy
+		events = append(events, types.GroupMemberAddEvent{
+			Type:        "group.member.add",
+			Timestamp:   now,
+			GroupID:     groupID,
+			MemberType:  members[i].MemberType,
+			MemberValue: members[i].MemberValue,
+			AddedBy:     createdBy,
+		})
+	}
+
+	// thrum-bsn7: release state.Lock() before the postCommits fire. The
+	// events are written under one lock so no other writer sees the new
+	// group half-filled.
+	var postCommits []func()
+	h.state.Lock()
+	for _, event := range events {
+		postCommit, err := h.state.WriteEvent(ctx, event)
+		if err != nil {
+			h.state.Unlock()
+			return nil, fmt.Errorf("write group.copy events: %w", err)
+		}
+		postCommits = append(postCommits, postCommit)
+	}
+	h.state.Unlock()
+	for _, postCommit := range postCommits {
+		h.state.GoPostCommit(postCommit)
+	}
+
+	return &GroupCopyResponse{
+		GroupID:   groupID,
+		Name:      req.Dest,
+		Source:    req.Source,
+		Members:   members,
+		CreatedAt: now,
+	}, nil
+}
+
+// copyMembers lists the member rows group.copy gives the new group: the
+// source group's own rows, or with expand its agent and role rows plus
+// those of every group nested below it. Each nested group is visited once,
+// so membership cycles end, and duplicate rows are dropped. Callers hold
+// the state read lock.
+func (h *GroupHandler) copyMembers(ctx context.Context, source string, expand bool) ([]GroupMember, error) {
+	members := []GroupMember{}
+	seen := map[GroupMember]bool{}
+	visited := map[string]bool{source: true}
+	queue := []string{source}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+
+		rows, err := h.state.DB().QueryContext(ctx, `
+			SELECT gm.member_type, gm.member_value
+			FROM group_members gm JOIN groups g ON g.group_id = gm.group_id
+			WHERE g.name = ?
+			ORDER BY gm.member_type, gm.member_value`, name)
+		if err != nil {
+			return nil, fmt.Errorf("query members of %q: %w", name, err)
+		}
+		var nested []GroupMember
+		for rows.Next() {
+			var m GroupMember
+			if err := rows.Scan(&m.MemberType, &m.MemberValue); err != nil {
+				_ = rows.Close()
+				return nil, fmt.Errorf("scan member: %w", err)
+			}
+			nested = append(nested, m)
+		}
+		err = rows.Err()
+		_ = rows.Close()
+		if err != nil {
+			return nil, fmt.Errorf("iterate members of %q: %w", name, err)
+		}
+
+		for _, m := range nested {
+			if expand && m.MemberType == "group" {
+				if !visited[m.MemberValue] {
+					visited[m.MemberValue] = true
+					queue = append(queue, m.MemberValue)
+				}
+				continue
+			}
+			if !seen[m] {
+				seen[m] = true
+				members = append(members, m)
+			}
+		}
+	}
+	return members, nil
+}
+
 // HandleMemberAdd handles the group.member.add RPC method.
 func (h *GroupHandler) HandleMemberAdd(ctx context.Context, params json.RawMessage) (any, error) {
 	var req GroupMemberAddRequest
@@ -361,9 +637,9 @@ func (h *GroupHandler) HandleMemberAdd(ctx context.Context, params json.RawMessa
 		return nil, fmt.Errorf("member_type and member_value are required")
 	}
 
-	// Validate member_type (only agent and role are allowed)
-	if req.MemberType != "agent" && req.MemberType != "role" {
-		return nil, fmt.Errorf("invalid member_type %q (must be 'agent' or 'role')", req.MemberType)
+	// Validate member_type (agent, role, or a nested group)
+	if req.MemberType != "agent" && req.MemberType != "role" && req.MemberType != "group" {
+		return nil, fmt.Errorf("invalid member_type %q (must be 'agent', 'role' or 'group')", req.MemberType)
 	}
 
 	// Prevent adding members to @everyone (protected)
@@ -388,6 +664,11 @@ func (h *GroupHandler) HandleMemberAdd(ctx context.Context, params json.RawMessa
 	h.state.RLock()
 	switch req.MemberType {
 	case "agent":
+		req.MemberValue, err = identity.ResolveAlias(ctx, h.state.DB(), req.MemberValue)
+		if err != nil {
+			h.state.RUnlock()
+			return nil, err
+		}
 		var exists bool
 		err = h.state.DB().QueryRowContext(ctx,
 			`SELECT EXISTS(SELECT 1 FROM agents WHERE agent_id = ?)`,
@@ -417,6 +698,27 @@ func (h *GroupHandler) HandleMemberAdd(ctx context.Context, params json.RawMessa
 		} else {
 			h.state.RUnlock()
 		}
+	case "group":
+		// Nested groups expand recursively at resolve time, where cycles
+		// are expanded once and reported by group.members --expand, so
+		// only the trivial self-reference is rejected.
+		req.MemberValue = strings.TrimPrefix(req.MemberValue, "@")
+		if req.MemberValue == req.Group {
+			h.state.RUnlock()
+			return nil, fmt.Errorf("group %q cannot be a member of itself", req.Group)
+		}
+		var exists bool
+		err = h.state.DB().QueryRowContext(ctx,
+			`SELECT EXISTS(SELECT 1 FROM groups WHERE name = ?)`,
+			req.MemberValue,
+		).Scan(&exists)
+		h.state.RUnlock()
+		if err != nil {
+			return nil, fmt.Errorf("validate group %q: %w", req.MemberValue, err)
+		}
+		if !exists {
+			return nil, fmt.Errorf("group %q not found", req.MemberValue)
+		}
 	default:
 		h.state.RUnlock()
 	}
@@ -606,10 +908,90 @@ func (h *GroupHandler) HandleInfo(ctx context.Context, params json.RawMessage) (
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("iterate members: %w", err)
 	}
+	_ = rows.Close()
+
+	tree, err := h.resolver.Tree(ctx, resp.Name)
+	if err != nil {
+		return nil, fmt.Errorf("resolve member tree: %w", err)
+	}
+	presence, err := h.agentPresence(ctx)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	resp.Tree = groupTreeNodes(tree, presence, func(agentID, status string) {
+		if !seen[agentID] {
+			seen[agentID] = true
+			resp.AgentCount++
+			if status == "active" {
+				resp.Online++
+			}
+		}
+	})
 
 	return &resp, nil
 }
 
+// groupTreeNodes converts a resolved membership tree, attaching each agent's
+// presence and reporting every agent node to visit.
+func groupTreeNodes(nodes []groups.Node, presence map[string]string, visit func(agentID, status string)) []GroupTreeNode {
+	out := make([]GroupTreeNode, 0, len(nodes))
+	for _, n := range nodes {
+		node := GroupTreeNode{MemberType: n.Type, MemberValue: n.Value, Cycle: n.Cycle}
+		if n.Type == "agent" {
+			node.Status = presence[n.Value]
+			if node.Status == "" {
+				node.Status = "offline"
+			}
+			visit(n.Value, node.Status)
+		}
+		if len(n.Children) > 0 {
+			node.Children = groupTreeNodes(n.Children, presence, visit)
+		}
+		out = append(out, node)
+	}
+	return out
+}
+
+// agentPresence maps each agent with an open session to "active", or to
+// "away" when the session has been silent past the idle threshold, the same
+// way agent.listContext reports it. Agents without an open session are
+// absent and count as offline. Caller holds the state lock.
+func (h *GroupHandler) agentPresence(ctx context.Context) (map[string]string, error) {
+	rows, err := h.state.DB().QueryContext(ctx, `
+		SELECT s.agent_id, MAX(s.last_seen_at), a.origin_daemon
+		FROM sessions s
+		LEFT JOIN agents a ON a.agent_id = s.agent_id
+		WHERE s.ended_at IS NULL
+		GROUP BY s.agent_id`)
+	if err != nil {
+		return nil, fmt.Errorf("query agent presence: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	presence := make(map[string]string)
+	localDaemonID := h.state.DaemonID()
+	now := time.Now()
+	for rows.Next() {
+		var agentID string
+		var lastSeen, originDaemon sql.NullString
+		if err := rows.Scan(&agentID, &lastSeen, &originDaemon); err != nil {
+			return nil, fmt.Errorf("scan agent presence: %w", err)
+		}
+		// Local agents only — remote heartbeats never reach this daemon.
+		isLocal := originDaemon.String == "" || originDaemon.String == localDaemonID
+		if isLocal && h.idle.IsAway(lastSeen.String, now) {
+			presence[agentID] = "away"
+		} else {
+			presence[agentID] = "active"
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate agent presence: %w", err)
+	}
+	return presence, nil
+}
+
 // HandleMembers handles the group.members RPC method.
 func (h *GroupHandler) HandleMembers(ctx context.Context, params json.RawMessage) (any, error) {
 	var req GroupMembersRequest
@@ -662,11 +1044,12 @@ func (h *GroupHandler) HandleMembers(ctx context.Context, params json.RawMessage
 
 	// Expand if requested
 	if req.Expand {
-		expanded, err := h.resolver.ExpandMembers(ctx, req.Name)
+		exp, err := h.resolver.Expand(ctx, req.Name)
 		if err != nil {
 			return nil, fmt.Errorf("expand members: %w", err)
 		}
-		resp.Expanded = expanded
+		resp.Expanded = exp.Members
+		resp.Cycles = exp.Cycles
 	}
 
 	return &resp, nil
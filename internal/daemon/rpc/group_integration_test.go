@@ -289,25 +289,152 @@ func TestGroupIntegration_DirectMention_StillWorks(t *testing.T) {
 	}
 }
 
-func TestGroupIntegration_NestedGroupRejected(t *testing.T) {
-	groupH, _, _, _, _, cleanup := setupGroupIntegrationTest(t)
+func TestGroupIntegration_NestedGroup(t *testing.T) {
+	groupH, msgH, _, aliceID, bobID, cleanup := setupGroupIntegrationTest(t)
 	defer cleanup()
+	ctx := context.Background()
 
-	// Create two groups
-	createA, _ := json.Marshal(GroupCreateRequest{Name: "watchers"})
-	if _, err := groupH.HandleCreate(context.Background(), createA); err != nil {
-		t.Fatalf("create watchers: %v", err)
+	for _, name := range []string{"watchers", "reviewers"} {
+		req, _ := json.Marshal(GroupCreateRequest{Name: name})
+		if _, err := groupH.HandleCreate(ctx, req); err != nil {
+			t.Fatalf("create %s: %v", name, err)
+		}
 	}
-	createB, _ := json.Marshal(GroupCreateRequest{Name: "reviewers"})
-	if _, err := groupH.HandleCreate(context.Background(), createB); err != nil {
-		t.Fatalf("create reviewers: %v", err)
+	addAlice, _ := json.Marshal(GroupMemberAddRequest{Group: "reviewers", MemberType: "agent", MemberValue: "alice"})
+	if _, err := groupH.HandleMemberAdd(ctx, addAlice); err != nil {
+		t.Fatalf("add alice: %v", err)
+	}
+	addNested, _ := json.Marshal(GroupMemberAddRequest{Group: "watchers", MemberType: "group", MemberValue: "@reviewers"})
+	if _, err := groupH.HandleMemberAdd(ctx, addNested); err != nil {
+		t.Fatalf("add nested group: %v", err)
 	}
 
-	// Adding a group as member should be rejected (flat groups only)
-	addNested, _ := json.Marshal(GroupMemberAddRequest{Group: "watchers", MemberType: "group", MemberValue: "reviewers"})
-	_, err := groupH.HandleMemberAdd(context.Background(), addNested)
-	if err == nil {
-		t.Fatal("expected error when adding group as member, got nil")
+	for _, bad := range []GroupMemberAddRequest{
+		{Group: "watchers", MemberType: "group", MemberValue: "watchers"},
+		{Group: "watchers", MemberType: "group", MemberValue: "nobody"},
+	} {
+		params, _ := json.Marshal(bad)
+		if _, err := groupH.HandleMemberAdd(ctx, params); err == nil {
+			t.Errorf("expected error adding group member %q", bad.MemberValue)
+		}
+	}
+
+	// Alice reaches @watchers through @reviewers; bob is in neither.
+	msgID := sendMessage(t, msgH, "Heads up", []string{"@watchers"}, bobID)
+	if inbox := listInbox(t, msgH, aliceID, "reviewer"); !containsID(inbox, msgID) {
+		t.Errorf("alice should see message to @watchers via nested @reviewers, inbox: %v", inbox)
+	}
+	if inbox := listInbox(t, msgH, bobID, "deployer"); containsID(inbox, msgID) {
+		t.Errorf("bob should NOT see message to @watchers")
+	}
+}
+
+func TestGroupIntegration_NestedGroupCycle(t *testing.T) {
+	groupH, msgH, _, aliceID, bobID, cleanup := setupGroupIntegrationTest(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	// @ping contains @pong and @pong contains @ping; alice is only in @pong.
+	for _, name := range []string{"ping", "pong"} {
+		req, _ := json.Marshal(GroupCreateRequest{Name: name})
+		if _, err := groupH.HandleCreate(ctx, req); err != nil {
+			t.Fatalf("create %s: %v", name, err)
+		}
+	}
+	for _, add := range []GroupMemberAddRequest{
+		{Group: "pong", MemberType: "agent", MemberValue: "alice"},
+		{Group: "ping", MemberType: "group", MemberValue: "pong"},
+		{Group: "pong", MemberType: "group", MemberValue: "ping"},
+	} {
+		params, _ := json.Marshal(add)
+		if _, err := groupH.HandleMemberAdd(ctx, params); err != nil {
+			t.Fatalf("add %s to %s: %v", add.MemberValue, add.Group, err)
+		}
+	}
+
+	msgID := sendMessage(t, msgH, "Loop check", []string{"@ping"}, bobID)
+	if inbox := listInbox(t, msgH, aliceID, "reviewer"); !containsID(inbox, msgID) {
+		t.Errorf("alice should see message to @ping via @pong despite the cycle, inbox: %v", inbox)
+	}
+}
+
+func TestGroupIntegration_SnapshotGroup(t *testing.T) {
+	groupH, msgH, st, aliceID, bobID, cleanup := setupGroupIntegrationTest(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	for _, name := range []string{"release", "reviewers"} {
+		req, _ := json.Marshal(GroupCreateRequest{Name: name})
+		if _, err := groupH.HandleCreate(ctx, req); err != nil {
+			t.Fatalf("create %s: %v", name, err)
+		}
+	}
+	for _, add := range []GroupMemberAddRequest{
+		{Group: "reviewers", MemberType: "role", MemberValue: "reviewer"},
+		{Group: "release", MemberType: "group", MemberValue: "reviewers"},
+	} {
+		params, _ := json.Marshal(add)
+		if _, err := groupH.HandleMemberAdd(ctx, params); err != nil {
+			t.Fatalf("add %s to %s: %v", add.MemberValue, add.Group, err)
+		}
+	}
+
+	params, _ := json.Marshal(SendRequest{Content: "freeze", SnapshotGroups: []string{"@release"}, CallerAgentID: bobID})
+	raw, err := msgH.HandleSend(ctx, params)
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	resp := raw.(*SendResponse)
+	if resp.ResolvedTo != 1 || len(resp.Recipients) != 1 || resp.Recipients[0].AgentID != aliceID {
+		t.Fatalf("resolved_to=%d recipients=%+v, want alice only", resp.ResolvedTo, resp.Recipients)
+	}
+
+	// Stored as a mention of alice plus an audit ref; no group scope.
+	rows, err := st.RawDB().QueryContext(ctx, `
+		SELECT 'ref:' || ref_type || ':' || ref_value FROM message_refs WHERE message_id = ?
+		UNION ALL
+		SELECT 'scope:' || scope_type || ':' || scope_value FROM message_scopes WHERE message_id = ?
+		ORDER BY 1`, resp.MessageID, resp.MessageID)
+	if err != nil {
+		t.Fatalf("query refs: %v", err)
+	}
+	var stored []string
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			t.Fatal(err)
+		}
+		stored = append(stored, v)
+	}
+	_ = rows.Close()
+	want := []string{"ref:mention:" + aliceID, "ref:snapshot_group:release"}
+	if len(stored) != len(want) || stored[0] != want[0] || stored[1] != want[1] {
+		t.Errorf("stored refs/scopes = %v, want %v", stored, want)
+	}
+
+	// Bob joining afterwards does not pull the message into his inbox.
+	addBob, _ := json.Marshal(GroupMemberAddRequest{Group: "release", MemberType: "agent", MemberValue: "bob"})
+	if _, err := groupH.HandleMemberAdd(ctx, addBob); err != nil {
+		t.Fatalf("add bob: %v", err)
+	}
+	if inbox := listInbox(t, msgH, bobID, "deployer"); containsID(inbox, resp.MessageID) {
+		t.Error("bob joined after the send and should not see the snapshot message")
+	}
+	if inbox := listInbox(t, msgH, aliceID, "reviewer"); !containsID(inbox, resp.MessageID) {
+		t.Errorf("alice should see the snapshot message, inbox: %v", inbox)
+	}
+
+	for _, bad := range []string{"@nobody", "@empty"} {
+		if bad == "@empty" {
+			req, _ := json.Marshal(GroupCreateRequest{Name: "empty"})
+			if _, err := groupH.HandleCreate(ctx, req); err != nil {
+				t.Fatalf("create empty: %v", err)
+			}
+		}
+		params, _ := json.Marshal(SendRequest{Content: "x", SnapshotGroups: []string{bad}, CallerAgentID: bobID})
+		if _, err := msgH.HandleSend(ctx, params); err == nil {
+			t.Errorf("expected error for snapshot group %s", bad)
+		}
 	}
 }
 
@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/leonletto/thrum/internal/daemon/state"
 	"github.com/leonletto/thrum/internal/identity"
@@ -352,6 +354,76 @@ func TestGroupInfo(t *testing.T) {
 	}
 }
 
+func TestGroupInfo_Tree(t *testing.T) {
+	handler, st, cleanup := setupGroupTest(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	registerTestAgent(t, st, "alice") // registered, no session
+	testerID := identity.GenerateAgentID("r_GROUP_TEST", "tester", "test-module", "")
+
+	// release → {agent alice, group core}; core → {role tester, group release}
+	for _, name := range []string{"release", "core"} {
+		createReq, _ := json.Marshal(GroupCreateRequest{Name: name})
+		if _, err := handler.HandleCreate(ctx, createReq); err != nil {
+			t.Fatalf("create %s: %v", name, err)
+		}
+	}
+	for _, m := range []GroupMemberAddRequest{
+		{Group: "release", MemberType: "agent", MemberValue: "alice"},
+		{Group: "release", MemberType: "group", MemberValue: "core"},
+		{Group: "core", MemberType: "role", MemberValue: "tester"},
+		{Group: "core", MemberType: "group", MemberValue: "release"},
+	} {
+		addReq, _ := json.Marshal(m)
+		if _, err := handler.HandleMemberAdd(ctx, addReq); err != nil {
+			t.Fatalf("add %s to %s: %v", m.MemberValue, m.Group, err)
+		}
+	}
+
+	info := func() *GroupInfoResponse {
+		t.Helper()
+		infoReq, _ := json.Marshal(GroupInfoRequest{Name: "release"})
+		resp, err := handler.HandleInfo(ctx, infoReq)
+		if err != nil {
+			t.Fatalf("HandleInfo: %v", err)
+		}
+		return resp.(*GroupInfoResponse)
+	}
+
+	resp := info()
+	if len(resp.Tree) != 2 {
+		t.Fatalf("tree = %+v, want alice and core", resp.Tree)
+	}
+	if alice := resp.Tree[0]; alice.MemberValue != "alice" || alice.Status != "offline" {
+		t.Errorf("alice node = %+v, want offline", alice)
+	}
+	core := resp.Tree[1]
+	if len(core.Children) != 2 || !core.Children[0].Cycle || core.Children[0].MemberValue != "release" {
+		t.Fatalf("core children = %+v, want the cycle back to release first", core.Children)
+	}
+	role := core.Children[1]
+	if len(role.Children) != 1 || role.Children[0].MemberValue != testerID || role.Children[0].Status != "active" {
+		t.Errorf("role tester children = %+v, want %s active", role.Children, testerID)
+	}
+	if resp.AgentCount != 2 || resp.Online != 1 {
+		t.Errorf("agents/online = %d/%d, want 2/1", resp.AgentCount, resp.Online)
+	}
+
+	// A session silent past the idle threshold is away, not online.
+	idle := &IdleThreshold{}
+	idle.Set(time.Minute)
+	handler.SetIdleThreshold(idle)
+	stale := time.Now().UTC().Add(-time.Hour).Format(time.RFC3339Nano)
+	if _, err := st.RawDB().Exec(`UPDATE sessions SET last_seen_at = ? WHERE agent_id = ?`, stale, testerID); err != nil {
+		t.Fatalf("age session: %v", err)
+	}
+	resp = info()
+	if got := resp.Tree[1].Children[1].Children[0].Status; got != "away" || resp.Online != 0 {
+		t.Errorf("stale tester status = %q, online = %d, want away and 0", got, resp.Online)
+	}
+}
+
 func TestGroupMembers_WithExpand(t *testing.T) {
 	handler, st, cleanup := setupGroupTest(t)
 	defer cleanup()
@@ -385,6 +457,46 @@ func TestGroupMembers_WithExpand(t *testing.T) {
 	}
 }
 
+func TestGroupMembers_ExpandReportsCycle(t *testing.T) {
+	handler, st, cleanup := setupGroupTest(t)
+	defer cleanup()
+
+	registerTestAgent(t, st, "alice")
+	registerTestAgent(t, st, "bob")
+
+	for _, name := range []string{"ping", "pong"} {
+		createReq, _ := json.Marshal(GroupCreateRequest{Name: name})
+		if _, err := handler.HandleCreate(context.Background(), createReq); err != nil {
+			t.Fatalf("create %s: %v", name, err)
+		}
+	}
+	for _, add := range []GroupMemberAddRequest{
+		{Group: "ping", MemberType: "agent", MemberValue: "alice"},
+		{Group: "ping", MemberType: "group", MemberValue: "pong"},
+		{Group: "pong", MemberType: "agent", MemberValue: "bob"},
+		{Group: "pong", MemberType: "agent", MemberValue: "alice"},
+		{Group: "pong", MemberType: "group", MemberValue: "ping"},
+	} {
+		addReq, _ := json.Marshal(add)
+		if _, err := handler.HandleMemberAdd(context.Background(), addReq); err != nil {
+			t.Fatalf("add %s to %s: %v", add.MemberValue, add.Group, err)
+		}
+	}
+
+	membersReq, _ := json.Marshal(GroupMembersRequest{Name: "ping", Expand: true})
+	resp, err := handler.HandleMembers(context.Background(), membersReq)
+	if err != nil {
+		t.Fatalf("HandleMembers: %v", err)
+	}
+	membersResp := resp.(*GroupMembersResponse)
+	if len(membersResp.Expanded) != 2 || membersResp.Expanded[0] != "alice" || membersResp.Expanded[1] != "bob" {
+		t.Errorf("expected expanded=[alice bob], got %v", membersResp.Expanded)
+	}
+	if len(membersResp.Cycles) != 1 || strings.Join(membersResp.Cycles[0], ",") != "ping,pong,ping" {
+		t.Errorf("expected cycle [ping pong ping], got %v", membersResp.Cycles)
+	}
+}
+
 func TestGroupDelete_NonExistent(t *testing.T) {
 	handler, _, cleanup := setupGroupTest(t)
 	defer cleanup()
@@ -577,3 +689,162 @@ func TestGroupDelete_WithDeleteMessages_False(t *testing.T) {
 		t.Errorf("expected 1 message scope to remain (delete_messages=false), got %d", scopeCount)
 	}
 }
+
+func TestGroupRename(t *testing.T) {
+	groupHandler, msgHandler, st, cleanup := setupGroupTestWithMessages(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	for _, name := range []string{"eng", "ops"} {
+		createReq, _ := json.Marshal(GroupCreateRequest{Name: name})
+		if _, err := groupHandler.HandleCreate(ctx, createReq); err != nil {
+			t.Fatalf("create group %s: %v", name, err)
+		}
+	}
+	sendParams, _ := json.Marshal(SendRequest{
+		Content: "Engineering message",
+		Scopes:  []types.Scope{{Type: "group", Value: "eng"}},
+	})
+	if _, err := msgHandler.HandleSend(ctx, sendParams); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+
+	renameReq, _ := json.Marshal(GroupRenameRequest{Name: "eng", NewName: "engineering"})
+	resp, err := groupHandler.HandleRename(ctx, renameReq)
+	if err != nil {
+		t.Fatalf("HandleRename: %v", err)
+	}
+	if r := resp.(*GroupRenameResponse); r.OldName != "eng" || r.Name != "engineering" {
+		t.Errorf("response = %+v, want eng → engineering", r)
+	}
+
+	count := func(query string, args ...any) int {
+		t.Helper()
+		var n int
+		if err := st.RawDB().QueryRow(query, args...).Scan(&n); err != nil {
+			t.Fatalf("count: %v", err)
+		}
+		return n
+	}
+	if n := count("SELECT COUNT(*) FROM groups WHERE name = ?", "engineering"); n != 1 {
+		t.Errorf("groups named engineering = %d, want 1", n)
+	}
+	if n := count("SELECT COUNT(*) FROM message_scopes WHERE scope_type = 'group' AND scope_value = ?", "engineering"); n != 1 {
+		t.Errorf("scopes moved to new name = %d, want 1", n)
+	}
+	if n := count("SELECT COUNT(*) FROM message_scopes WHERE scope_type = 'group' AND scope_value = ?", "eng"); n != 0 {
+		t.Errorf("scopes left on old name = %d, want 0", n)
+	}
+
+	tests := []struct {
+		name      string
+		req       GroupRenameRequest
+		wantError string
+	}{
+		{"taken", GroupRenameRequest{Name: "engineering", NewName: "ops"}, "already exists"},
+		{"old name gone", GroupRenameRequest{Name: "eng", NewName: "eng2"}, "not found"},
+		{"from everyone", GroupRenameRequest{Name: "everyone", NewName: "all"}, "@everyone"},
+		{"to everyone", GroupRenameRequest{Name: "ops", NewName: "everyone"}, "@everyone"},
+		{"missing new name", GroupRenameRequest{Name: "ops"}, "required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, _ := json.Marshal(tt.req)
+			_, err := groupHandler.HandleRename(ctx, params)
+			if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+				t.Errorf("HandleRename error = %v, want containing %q", err, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestGroupCopy(t *testing.T) {
+	handler, st, cleanup := setupGroupTest(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	registerTestAgent(t, st, "alice")
+	registerTestAgent(t, st, "bob")
+	for _, name := range []string{"reviewers", "leads"} {
+		createReq, _ := json.Marshal(GroupCreateRequest{Name: name, Description: name + " team"})
+		if _, err := handler.HandleCreate(ctx, createReq); err != nil {
+			t.Fatalf("create group %s: %v", name, err)
+		}
+	}
+	// leads nests reviewers back, so expansion has a cycle to stop at.
+	for _, add := range []GroupMemberAddRequest{
+		{Group: "reviewers", MemberType: "agent", MemberValue: "alice"},
+		{Group: "reviewers", MemberType: "group", MemberValue: "leads"},
+		{Group: "leads", MemberType: "agent", MemberValue: "bob"},
+		{Group: "leads", MemberType: "role", MemberValue: "alice_role"},
+		{Group: "leads", MemberType: "group", MemberValue: "reviewers"},
+	} {
+		addReq, _ := json.Marshal(add)
+		if _, err := handler.HandleMemberAdd(ctx, addReq); err != nil {
+			t.Fatalf("add %+v: %v", add, err)
+		}
+	}
+
+	members := func(t *testing.T, name string) []string {
+		t.Helper()
+		params, _ := json.Marshal(GroupMembersRequest{Name: name})
+		resp, err := handler.HandleMembers(ctx, params)
+		if err != nil {
+			t.Fatalf("HandleMembers(%s): %v", name, err)
+		}
+		var out []string
+		for _, m := range resp.(*GroupMembersResponse).Members {
+			out = append(out, m.MemberType+":"+m.MemberValue)
+		}
+		return out
+	}
+
+	t.Run("preserve", func(t *testing.T) {
+		params, _ := json.Marshal(GroupCopyRequest{Source: "reviewers", Dest: "reviewers-v2"})
+		resp, err := handler.HandleCopy(ctx, params)
+		if err != nil {
+			t.Fatalf("HandleCopy: %v", err)
+		}
+		if r := resp.(*GroupCopyResponse); r.Name != "reviewers-v2" || len(r.Members) != 2 {
+			t.Errorf("response = %+v, want reviewers-v2 with 2 members", r)
+		}
+		if got, want := members(t, "reviewers-v2"), []string{"agent:alice", "group:leads"}; !slices.Equal(got, want) {
+			t.Errorf("members = %v, want %v", got, want)
+		}
+		var desc string
+		if err := st.RawDB().QueryRow("SELECT description FROM groups WHERE name = 'reviewers-v2'").Scan(&desc); err != nil || desc != "reviewers team" {
+			t.Errorf("description = %q, %v; want the source's", desc, err)
+		}
+	})
+
+	t.Run("expand", func(t *testing.T) {
+		params, _ := json.Marshal(GroupCopyRequest{Source: "reviewers", Dest: "reviewers-flat", Expand: true})
+		if _, err := handler.HandleCopy(ctx, params); err != nil {
+			t.Fatalf("HandleCopy: %v", err)
+		}
+		if got, want := members(t, "reviewers-flat"), []string{"agent:alice", "agent:bob", "role:alice_role"}; !slices.Equal(got, want) {
+			t.Errorf("members = %v, want %v", got, want)
+		}
+	})
+
+	tests := []struct {
+		name      string
+		req       GroupCopyRequest
+		wantError string
+	}{
+		{"dest exists", GroupCopyRequest{Source: "reviewers", Dest: "leads"}, "already exists"},
+		{"source missing", GroupCopyRequest{Source: "nope", Dest: "new"}, "not found"},
+		{"same group", GroupCopyRequest{Source: "leads", Dest: "leads"}, "same group"},
+		{"to everyone", GroupCopyRequest{Source: "leads", Dest: "everyone"}, "@everyone"},
+		{"missing dest", GroupCopyRequest{Source: "leads"}, "required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, _ := json.Marshal(tt.req)
+			_, err := handler.HandleCopy(ctx, params)
+			if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+				t.Errorf("HandleCopy error = %v, want containing %q", err, tt.wantError)
+			}
+		})
+	}
+}
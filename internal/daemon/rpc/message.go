@@ -11,6 +11,7 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strings"
 	"sync/atomic"
@@ -25,24 +26,42 @@ import (
 	"github.com/leonletto/thrum/internal/identity/guard"
 	"github.com/leonletto/thrum/internal/profile"
 	"github.com/leonletto/thrum/internal/recipientgate"
+	"github.com/leonletto/thrum/internal/schema"
 	"github.com/leonletto/thrum/internal/subscriptions"
 	"github.com/leonletto/thrum/internal/types"
 )
 
 // SendRequest represents the request for message.send RPC.
 type SendRequest struct {
-	Content       string         `json:"content"`
-	Format        string         `json:"format,omitempty"`     // default: "markdown"
-	Structured    map[string]any `json:"structured,omitempty"` // optional typed payload
-	ReplyTo       string         `json:"reply_to,omitempty"`
-	Scopes        []types.Scope  `json:"scopes,omitempty"`
-	Refs          []types.Ref    `json:"refs,omitempty"`
-	To            string         `json:"to,omitempty"`       // strict: agent_id or "everyone" only
-	Mentions      []string       `json:"mentions,omitempty"` // permissive: agent_id, role, or group
-	Tags          []string       `json:"tags,omitempty"`
-	ActingAs      string         `json:"acting_as,omitempty"` // Impersonate this agent (users only)
-	Disclose      bool           `json:"disclose,omitempty"`  // Show [via user:X] in message
-	CallerAgentID string         `json:"caller_agent_id,omitempty"`
+	Content    string         `json:"content"`
+	Format     string         `json:"format,omitempty"`     // default: "markdown"
+	Structured map[string]any `json:"structured,omitempty"` // optional typed payload
+	ReplyTo    string         `json:"reply_to,omitempty"`
+	Scopes     []types.Scope  `json:"scopes,omitempty"`
+	Refs       []types.Ref    `json:"refs,omitempty"`
+	To         string         `json:"to,omitempty"`       // strict: agent_id or "everyone" only
+	Mentions   []string       `json:"mentions,omitempty"` // permissive: agent_id, role, or group
+	// SnapshotGroups are expanded to their current members at send time and
+	// stored as per-agent mentions instead of a group scope.
+	SnapshotGroups []string `json:"snapshot_groups,omitempty"`
+	Tags           []string `json:"tags,omitempty"`
+	Priority       string   `json:"priority,omitempty"`  // "low", "normal" (default), or "high"
+	TTL            string   `json:"ttl,omitempty"`       // Go duration; message expires this long after sending
+	Schedule       string   `json:"schedule,omitempty"`  // RFC 3339 time or "+DURATION"; held until then
+	ActingAs       string   `json:"acting_as,omitempty"` // Impersonate this agent (users only)
+	Disclose       bool     `json:"disclose,omitempty"`  // Show [via user:X] in message
+	// NoThread keeps a reply to a message outside any thread flat instead of
+	// starting a thread for the two. A parent already in a thread is joined
+	// either way.
+	NoThread bool `json:"no_thread,omitempty"`
+	// Attachments are absolute paths of files on the daemon host to copy
+	// onto the sync branch (send --attach). Unix socket callers only.
+	Attachments []string `json:"attachments,omitempty"`
+	// IdempotencyKey makes a retried send return the first send's response
+	// instead of sending again. Keys are per caller and expire after
+	// idempotencyWindow.
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+	CallerAgentID  string `json:"caller_agent_id,omitempty"`
 }
 
 // SendResponse represents the response from message.send RPC.
@@ -50,15 +69,33 @@ type SendResponse struct {
 	MessageID  string                  `json:"message_id"`
 	ThreadID   string                  `json:"thread_id,omitempty"`
 	CreatedAt  string                  `json:"created_at"`
-	ResolvedTo int                     `json:"resolved_to"`        // count of resolved mentions
-	Warnings   []string                `json:"warnings,omitempty"` // informational warnings
+	ExpiresAt  string                  `json:"expires_at,omitempty"` // set when the request had a ttl
+	DeliverAt  string                  `json:"deliver_at,omitempty"` // set when the request had a schedule
+	ResolvedTo int                     `json:"resolved_to"`          // count of resolved mentions
+	Warnings   []string                `json:"warnings,omitempty"`   // informational warnings
 	Audiences  []MessageAudience       `json:"audiences,omitempty"`
 	Recipients []MessageRecipientState `json:"recipients,omitempty"`
 }
 
+// ResolveResponse represents the response from message.resolve RPC, the
+// read-only preview behind `thrum send --dry-run`. The request is a
+// SendRequest; nothing is written.
+type ResolveResponse struct {
+	AgentID    string            `json:"agent_id"`             // author after acting_as
+	ThreadID   string            `json:"thread_id,omitempty"`  // thread a reply would join
+	NewThread  bool              `json:"new_thread,omitempty"` // reply_to parent has no thread yet; sending starts one
+	ResolvedTo int               `json:"resolved_to"`
+	Warnings   []string          `json:"warnings,omitempty"`
+	Audiences  []MessageAudience `json:"audiences,omitempty"`
+	Recipients []string          `json:"recipients"`
+	Scopes     []types.Scope     `json:"scopes"`
+	Refs       []types.Ref       `json:"refs"`
+}
+
 // GetMessageRequest represents the request for message.get RPC.
 type GetMessageRequest struct {
-	MessageID string `json:"message_id"`
+	MessageID     string `json:"message_id"`
+	CallerAgentID string `json:"caller_agent_id,omitempty"`
 }
 
 // GetMessageResponse represents the response from message.get RPC.
@@ -68,19 +105,22 @@ type GetMessageResponse struct {
 
 // MessageDetail represents detailed information about a message.
 type MessageDetail struct {
-	MessageID  string                  `json:"message_id"`
-	ThreadID   string                  `json:"thread_id,omitempty"`
-	ReplyTo    string                  `json:"reply_to,omitempty"`
-	Author     AuthorInfo              `json:"author"`
-	Body       types.MessageBody       `json:"body"`
-	Scopes     []types.Scope           `json:"scopes"`
-	Refs       []types.Ref             `json:"refs"`
-	Metadata   MessageMetadata         `json:"metadata"`
-	CreatedAt  string                  `json:"created_at"`
-	UpdatedAt  string                  `json:"updated_at,omitempty"`
-	Deleted    bool                    `json:"deleted"`
-	Audiences  []MessageAudience       `json:"audiences,omitempty"`
-	Recipients []MessageRecipientState `json:"recipients,omitempty"`
+	MessageID   string                  `json:"message_id"`
+	ThreadID    string                  `json:"thread_id,omitempty"`
+	ReplyTo     string                  `json:"reply_to,omitempty"`
+	Author      AuthorInfo              `json:"author"`
+	Body        types.MessageBody       `json:"body"`
+	Scopes      []types.Scope           `json:"scopes"`
+	Refs        []types.Ref             `json:"refs"`
+	Metadata    MessageMetadata         `json:"metadata"`
+	CreatedAt   string                  `json:"created_at"`
+	UpdatedAt   string                  `json:"updated_at,omitempty"`
+	Deleted     bool                    `json:"deleted"`
+	Audiences   []MessageAudience       `json:"audiences,omitempty"`
+	Recipients  []MessageRecipientState `json:"recipients,omitempty"`
+	Reactions   map[string][]string     `json:"reactions,omitempty"` // emoji → agent IDs
+	Tags        []string                `json:"tags,omitempty"`
+	Attachments []MessageAttachment     `json:"attachments,omitempty"` // parsed from the attachment refs
 }
 
 // AuthorInfo represents information about the message author.
@@ -98,17 +138,27 @@ type MessageMetadata struct {
 // ListMessagesRequest represents the request for message.list RPC.
 type ListMessagesRequest struct {
 	// Filters
-	Scope    *types.Scope `json:"scope,omitempty"`     // Filter by scope
-	Ref      *types.Ref   `json:"ref,omitempty"`       // Filter by ref
-	ThreadID string       `json:"thread_id,omitempty"` // Filter by thread
-	AuthorID string       `json:"author_id,omitempty"` // Filter by author
-	Mentions bool         `json:"mentions,omitempty"`  // Only mentioning current agent (resolved from config)
-	Unread   bool         `json:"unread,omitempty"`    // Only unread messages (resolved from config)
+	Scope      *types.Scope `json:"scope,omitempty"`       // Filter by scope
+	Ref        *types.Ref   `json:"ref,omitempty"`         // Filter by ref
+	ThreadID   string       `json:"thread_id,omitempty"`   // Filter by thread
+	AuthorID   string       `json:"author_id,omitempty"`   // Filter by author
+	AuthorRole string       `json:"author_role,omitempty"` // Filter by author's registered role (any agent holding it)
+	Tag        string       `json:"tag,omitempty"`         // Filter by tag (set via message.send tags)
+	Priority   string       `json:"priority,omitempty"`    // Filter by priority: "low", "normal", or "high"
+	Pinned     bool         `json:"pinned,omitempty"`      // Only pinned messages
+	AssignedTo string       `json:"assigned_to,omitempty"` // Only messages with an open assignment to this agent (message.assign)
+	Mentions   bool         `json:"mentions,omitempty"`    // Only mentioning current agent (resolved from config)
+	Unread     bool         `json:"unread,omitempty"`      // Only unread messages (resolved from config)
 
 	// Explicit filters (for remote callers like MCP server that can't use config resolution)
 	MentionRole    string `json:"mention_role,omitempty"`     // Filter to messages with mention ref matching this role
 	UnreadForAgent string `json:"unread_for_agent,omitempty"` // Filter to messages unread by this agent_id
 
+	// UnseenBy lists the backlog of ANOTHER agent: messages that agent has
+	// not read yet, excluding ones it authored. Coordinator-only — see
+	// unseenByAllowedRoles. Takes priority over Unread/UnreadForAgent.
+	UnseenBy string `json:"unseen_by,omitempty"`
+
 	// Inbox behavior
 	ExcludeSelf       bool   `json:"exclude_self,omitempty"`        // Exclude messages authored by the current agent (inbox mode)
 	CallerAgentID     string `json:"caller_agent_id,omitempty"`     // For worktree callers to pass their agent ID
@@ -118,15 +168,25 @@ type ListMessagesRequest struct {
 	ForAgent     string `json:"for_agent,omitempty"`      // Agent name to filter for (messages mentioning this name + broadcasts)
 	ForAgentRole string `json:"for_agent_role,omitempty"` // Agent role to filter for (messages mentioning this role + broadcasts)
 
-	// Pagination
-	PageSize int `json:"page_size,omitempty"` // Default: 10
-	Page     int `json:"page,omitempty"`      // Default: 1
+	// RespectMute hides everything from a muted ForAgent except, when the
+	// mute allows it, direct mentions. Set by wait so a muted agent isn't
+	// woken; the inbox leaves it off and shows every message.
+	RespectMute bool `json:"respect_mute,omitempty"`
+
+	// Pagination. AfterCursor is a previous response's next_cursor: the
+	// page starts right after that response's last row, so messages that
+	// arrive in between neither shift nor repeat rows. Excludes Page.
+	PageSize    int    `json:"page_size,omitempty"` // Default: 10
+	Page        int    `json:"page,omitempty"`      // Default: 1
+	AfterCursor string `json:"after_cursor,omitempty"`
 
 	// Time filter
-	CreatedAfter string `json:"created_after,omitempty"` // Only return messages created after this RFC3339 timestamp
+	CreatedAfter   string `json:"created_after,omitempty"`   // Only return messages created after this RFC3339 timestamp
+	IncludeExpired bool   `json:"include_expired,omitempty"` // Include TTL messages past expires_at that cleanup hasn't deleted yet
+	IncludeDeleted bool   `json:"include_deleted,omitempty"` // Include soft-deleted messages as tombstones (never counted as unread)
 
 	// Sorting
-	SortBy    string `json:"sort_by,omitempty"`    // "created_at", "updated_at"
+	SortBy    string `json:"sort_by,omitempty"`    // "created_at", "updated_at", "bumped_at" (last bump, else created_at), "sequence" (origin sequence, then origin daemon)
 	SortOrder string `json:"sort_order,omitempty"` // "asc", "desc"
 
 	// Chronological opts into the oldest-first, reply-clustered inbox view
@@ -137,6 +197,10 @@ type ListMessagesRequest struct {
 	// the newest N. Only meaningful in inbox mode (ForAgent/ForAgentRole set);
 	// ignored when an explicit SortOrder is given.
 	Chronological bool `json:"chronological,omitempty"`
+
+	// PrioritySort lists unread high-priority messages ahead of everything
+	// else, keeping the regular order within each group.
+	PrioritySort bool `json:"priority_sort,omitempty"`
 }
 
 // ListMessagesResponse represents the response from message.list RPC.
@@ -145,24 +209,36 @@ type ListMessagesResponse struct {
 	Total          int              `json:"total"`
 	Unread         int              `json:"unread"`
 	HiddenByFilter int              `json:"hidden_by_filter,omitempty"` // unread count that would be visible without the for-agent filter
+	PinnedCount    int              `json:"pinned_count,omitempty"`     // pinned messages visible to the for-agent filter, regardless of other filters
 	Page           int              `json:"page"`
 	PageSize       int              `json:"page_size"`
 	TotalPages     int              `json:"total_pages"`
+	NextCursor     string           `json:"next_cursor,omitempty"` // set while more rows follow; pass back as after_cursor
 }
 
 // MessageSummary represents a summary of a message for listing.
 type MessageSummary struct {
-	MessageID  string                  `json:"message_id"`
-	ThreadID   string                  `json:"thread_id,omitempty"`
-	ReplyTo    string                  `json:"reply_to,omitempty"`
-	AgentID    string                  `json:"agent_id"`
-	Body       types.MessageBody       `json:"body"`
-	CreatedAt  string                  `json:"created_at"`
-	Deleted    bool                    `json:"deleted"`
-	IsRead     bool                    `json:"is_read"` // Computed from durable message delivery receipts for this agent
-	Audiences  []MessageAudience       `json:"audiences,omitempty"`
-	Recipients []MessageRecipientState `json:"recipients,omitempty"`
-	ReadCount  int                     `json:"read_count,omitempty"`
+	MessageID    string                  `json:"message_id"`
+	ThreadID     string                  `json:"thread_id,omitempty"`
+	ReplyTo      string                  `json:"reply_to,omitempty"`
+	AgentID      string                  `json:"agent_id"`
+	Body         types.MessageBody       `json:"body"`
+	CreatedAt    string                  `json:"created_at"`
+	Deleted      bool                    `json:"deleted"`
+	IsRead       bool                    `json:"is_read"`            // Computed from durable message delivery receipts for this agent
+	Priority     string                  `json:"priority,omitempty"` // "low" or "high"; empty means normal
+	Pinned       bool                    `json:"pinned,omitempty"`
+	ExpiresAt    string                  `json:"expires_at,omitempty"`    // send --ttl messages only
+	DeliverAt    string                  `json:"deliver_at,omitempty"`    // send --schedule messages only
+	BumpedAt     string                  `json:"bumped_at,omitempty"`     // last message.bump, if any
+	DeletedAt    string                  `json:"deleted_at,omitempty"`    // tombstones only (message.list include_deleted)
+	DeleteReason string                  `json:"delete_reason,omitempty"` // tombstones only, when the deleter gave one
+	OriginDaemon string                  `json:"origin_daemon,omitempty"` // daemon that created the message
+	Sequence     int64                   `json:"sequence,omitempty"`      // origin daemon's event sequence; 0 if unknown
+	Audiences    []MessageAudience       `json:"audiences,omitempty"`
+	Recipients   []MessageRecipientState `json:"recipients,omitempty"`
+	ReadCount    int                     `json:"read_count,omitempty"`
+	Snippet      string                  `json:"snippet,omitempty"` // message.search only: matched text with surrounding context
 }
 
 // MessageAudience describes a send-time audience on a message.
@@ -210,6 +286,51 @@ type DeleteMessageResponse struct {
 	DeletedAt string `json:"deleted_at"`
 }
 
+// CancelMessageRequest represents the request for message.cancel RPC.
+type CancelMessageRequest struct {
+	MessageID     string `json:"message_id"`
+	CallerAgentID string `json:"caller_agent_id,omitempty"`
+}
+
+// CancelMessageResponse represents the response from message.cancel RPC.
+type CancelMessageResponse struct {
+	MessageID  string `json:"message_id"`
+	DeliverAt  string `json:"deliver_at"` // when the message would have been delivered
+	CanceledAt string `json:"canceled_at"`
+}
+
+// ReactRequest represents the request for message.react RPC.
+type ReactRequest struct {
+	MessageID     string `json:"message_id"`
+	Emoji         string `json:"emoji"`                     // ":thumbsup:" shortcode or a literal emoji
+	CallerAgentID string `json:"caller_agent_id,omitempty"` // CLI-resolved agent identity
+}
+
+// ReactResponse represents the response from message.react RPC.
+type ReactResponse struct {
+	MessageID string              `json:"message_id"`
+	Emoji     string              `json:"emoji"`
+	Removed   bool                `json:"removed"`   // true when the call toggled an existing reaction off
+	Reactions map[string][]string `json:"reactions"` // emoji → agent IDs after the change
+}
+
+// PinRequest represents the request for message.pin and message.unpin RPCs.
+type PinRequest struct {
+	MessageID     string `json:"message_id"`
+	CallerAgentID string `json:"caller_agent_id,omitempty"` // CLI-resolved agent identity
+}
+
+// PinResponse represents the response from message.pin and message.unpin RPCs.
+type PinResponse struct {
+	MessageID string `json:"message_id"`
+	Pinned    bool   `json:"pinned"`  // pin state after the call
+	Changed   bool   `json:"changed"` // false when the message was already in that state
+}
+
+// maxEmojiLen bounds a reaction token. Shortcodes and multi-codepoint emoji
+// (ZWJ sequences, skin tones) fit comfortably; anything longer is a message.
+const maxEmojiLen = 64
+
 // EditRequest represents the request for message.edit RPC.
 type EditRequest struct {
 	MessageID     string         `json:"message_id"`
@@ -269,22 +390,79 @@ type ArchiveResponse struct {
 	ArchivePath   string `json:"archive_path"`
 }
 
-// archiveRecord is the structure written per line in the JSONL archive file.
-type archiveRecord struct {
+// ArchiveRecord is the structure written per line in the JSONL archive file.
+type ArchiveRecord struct {
 	MessageID string        `json:"message_id"`
+	ThreadID  string        `json:"thread_id,omitempty"`
 	AgentID   string        `json:"agent_id"`
 	CreatedAt string        `json:"created_at"`
-	Body      archiveBody   `json:"body"`
+	Body      ArchiveBody   `json:"body"`
 	Scopes    []types.Scope `json:"scopes"`
 	Refs      []types.Ref   `json:"refs"`
+	Deleted   bool          `json:"deleted,omitempty"`    // tombstone; only in message.export with include_deleted
+	DeletedAt string        `json:"deleted_at,omitempty"` // set with Deleted
 }
 
-// archiveBody holds the body fields for an archived message.
-type archiveBody struct {
+// ArchiveBody holds the body fields for an archived message.
+type ArchiveBody struct {
 	Format  string `json:"format"`
 	Content string `json:"content"`
 }
 
+// ExportRequest represents the request for message.export RPC.
+type ExportRequest struct {
+	Since          string `json:"since,omitempty"`           // RFC3339; only messages created at or after it
+	IncludeDeleted bool   `json:"include_deleted,omitempty"` // include deleted messages as tombstones
+}
+
+// ExportResponse represents the response from message.export RPC.
+// Records are oldest first; Threads lists every thread a record belongs to.
+type ExportResponse struct {
+	Records []ArchiveRecord `json:"records"`
+	Threads []ExportThread  `json:"threads"`
+}
+
+// ExportThread describes a thread referenced by exported records.
+type ExportThread struct {
+	ThreadID  string `json:"thread_id"`
+	Title     string `json:"title,omitempty"`
+	CreatedAt string `json:"created_at"`
+	CreatedBy string `json:"created_by"`
+}
+
+// ImportRequest represents the request for message.import RPC.
+// Records use the message.archive JSONL format, one ArchiveRecord per line.
+type ImportRequest struct {
+	Records []ArchiveRecord `json:"records"`
+	Force   bool            `json:"force,omitempty"` // overwrite messages whose ID already exists
+}
+
+// ImportResponse represents the response from message.import RPC.
+type ImportResponse struct {
+	ImportedCount    int `json:"imported_count"`
+	SkippedCount     int `json:"skipped_count"`
+	OverwrittenCount int `json:"overwritten_count,omitempty"`
+	TombstoneCount   int `json:"tombstone_count,omitempty"` // deleted records skipped
+	// DanglingRefs lists reply_to/forwarded_from refs on imported records
+	// whose target is neither in the archive nor already in the repo. The
+	// refs are imported unchanged; this only reports them.
+	DanglingRefs []DanglingRef `json:"dangling_refs,omitempty"`
+}
+
+// DanglingRef is a message-ID ref whose target message does not exist.
+type DanglingRef struct {
+	MessageID string `json:"message_id"`
+	RefType   string `json:"ref_type"`
+	RefValue  string `json:"ref_value"`
+}
+
+// messageIDRefTypes are the ref types whose value is another message's ID.
+var messageIDRefTypes = map[string]bool{"reply_to": true, "forwarded_from": true}
+
+// importSessionID is stamped on imported messages. Archive records do not
+// carry the originating session, and messages.session_id is NOT NULL.
+const importSessionID = "ses_import"
+
 // WSBroadcaster is an interface for broadcasting notifications to all connected
 // WebSocket clients, regardless of session ID. Used to push events to passive
 // observers like the browser UI that never call thrum subscribe.
@@ -318,6 +496,24 @@ type MessageHandler struct {
 	// cap. Wired from DaemonConfig.MaxMessageBodyBytesEffective() in
 	// main.go. thrum-mhwt.
 	maxBodyBytes int
+	// sendLimiter throttles HandleSend per calling agent. nil (the default)
+	// disables limiting; see SetSendRateLimiter.
+	sendLimiter *SendRateLimiter
+	// sentCount counts messages written by HandleSend since daemon start;
+	// read by the metrics endpoint via SentCount.
+	sentCount atomic.Uint64
+}
+
+// SentCount returns the number of messages sent through this handler since
+// daemon start.
+func (h *MessageHandler) SentCount() uint64 {
+	return h.sentCount.Load()
+}
+
+// SetSendRateLimiter wires the per-agent message.send limiter. Call once
+// during daemon startup, before the handler serves requests.
+func (h *MessageHandler) SetSendRateLimiter(l *SendRateLimiter) {
+	h.sendLimiter = l
 }
 
 // SetWSBroadcaster configures a broadcaster that will be called after every
@@ -422,22 +618,44 @@ func NewMessageHandler(state *state.State) *MessageHandler {
 }
 
 // checkBodySize returns a typed RPC error with -32602 (Invalid Params)
-// when content exceeds h.maxBodyBytes, and nil otherwise. h.maxBodyBytes
-// == 0 disables the cap. Used by every write-side handler that accepts
-// caller-supplied message body content (HandleSend + HandleEdit) so the
-// rejection surface is uniform and machine-parseable. thrum-mhwt.
-func (h *MessageHandler) checkBodySize(content string) error {
-	if h.maxBodyBytes <= 0 || len(content) <= h.maxBodyBytes {
+// when a message body exceeds h.maxBodyBytes, and nil otherwise. The
+// body is content plus the JSON encoding of any structured payload, so
+// a large payload cannot slip past the cap by moving out of content.
+// h.maxBodyBytes == 0 disables the cap. Used by every write-side handler
+// that accepts caller-supplied message body content (HandleSend +
+// HandleEdit) so the rejection surface is uniform and machine-parseable:
+// Data carries the measured size and the limit. thrum-mhwt.
+func (h *MessageHandler) checkBodySize(content string, structured map[string]any) error {
+	if h.maxBodyBytes <= 0 {
+		return nil
+	}
+	size := len(content)
+	if structured != nil {
+		data, err := json.Marshal(structured)
+		if err != nil {
+			return fmt.Errorf("marshal structured data: %w", err)
+		}
+		size += len(data)
+	}
+	if size <= h.maxBodyBytes {
 		return nil
 	}
 	return &RPCError{
 		Code: -32602,
 		Message: fmt.Sprintf(
 			"message body too large: %d bytes exceeds the daemon limit of %d bytes (daemon.max_message_body_bytes). Reduce the body size or raise the config; for genuinely large payloads consider attachments instead of inline content",
-			len(content), h.maxBodyBytes),
+			size, h.maxBodyBytes),
+		Data: BodyTooLargeData{Size: size, MaxBytes: h.maxBodyBytes},
 	}
 }
 
+// BodyTooLargeData is the Data of the RPCError returned when a message
+// body (content plus structured payload) exceeds the daemon's cap.
+type BodyTooLargeData struct {
+	Size     int `json:"size"`      // measured body size in bytes
+	MaxBytes int `json:"max_bytes"` // effective daemon.max_message_body_bytes
+}
+
 // NewMessageHandlerWithDispatcher creates a new message handler with a custom dispatcher.
 // The dispatcher should have the client notifier configured for push notifications.
 // SupervisorID / supervisorLegacy are the canonical and pre-upgrade forms of the
@@ -460,7 +678,7 @@ func NewMessageHandlerWithDispatcher(state *state.State, dispatcher *subscriptio
 }
 
 // HandleSend handles the message.send RPC method.
-func (h *MessageHandler) HandleSend(ctx context.Context, params json.RawMessage) (any, error) {
+func (h *MessageHandler) HandleSend(ctx context.Context, params json.RawMessage) (result any, err error) {
 	// thrum-bpq5 substrate: end-to-end HandleSend timing. Gated by
 	// THRUM_PROFILE; zero cost when off.
 	hsStart := time.Now()
@@ -484,28 +702,8 @@ func (h *MessageHandler) HandleSend(ctx context.Context, params json.RawMessage)
 		return nil, fmt.Errorf("invalid request: %w", err)
 	}
 
-	// Validate required fields
-	if req.Content == "" {
-		return nil, fmt.Errorf("content is required")
-	}
-
-	// Default format to markdown
-	format := req.Format
-	if format == "" {
-		format = "markdown"
-	}
-
-	// Validate format
-	if format != "markdown" && format != "plain" && format != "json" {
-		return nil, fmt.Errorf("invalid format: %s (must be 'markdown', 'plain', or 'json')", format)
-	}
-
-	// thrum-mhwt: cap body.content size at write so a runaway operator
-	// or hot-loop client cannot inflate events.jsonl past the
-	// compactor's read ceiling. h.maxBodyBytes is the effective limit
-	// (set at construction from DaemonConfig.MaxMessageBodyBytesEffective);
-	// 0 disables the cap (test path).
-	if err := h.checkBodySize(req.Content); err != nil {
+	format, tags, priority, err := h.validateSendFields(&req)
+	if err != nil {
 		return nil, err
 	}
 
@@ -519,6 +717,31 @@ func (h *MessageHandler) HandleSend(ctx context.Context, params json.RawMessage)
 		return nil, fmt.Errorf("resolve agent and session: %w", err)
 	}
 
+	// A retry with a known idempotency key returns the first response
+	// without sending again, and before the rate limiter counts it.
+	if req.IdempotencyKey != "" {
+		prior, err := h.claimIdempotencyKey(ctx, callerID, req)
+		if err != nil {
+			return nil, err
+		}
+		if prior != nil {
+			return prior, nil
+		}
+		defer func() {
+			resp, _ := result.(*SendResponse)
+			if err != nil {
+				resp = nil
+			}
+			h.finishIdempotencyKey(ctx, callerID, req.IdempotencyKey, resp)
+		}()
+	}
+
+	// Throttle on the authenticated caller, not the acting-as agent: the
+	// runaway loop is whoever is making the calls.
+	if err := h.sendLimiter.Allow(callerID, time.Now()); err != nil {
+		return nil, err
+	}
+
 	// thrum-7nuj: advance last_seen_at for the caller so the
 	// send.recipient-stale hint doesn't false-positive on actively
 	// coordinating agents. Debounced in the state layer.
@@ -544,7 +767,22 @@ func (h *MessageHandler) HandleSend(ctx context.Context, params json.RawMessage)
 	}
 
 	// Prepare timestamp
-	now := time.Now().UTC().Format(time.RFC3339Nano)
+	sentAt := time.Now().UTC()
+	now := sentAt.Format(time.RFC3339Nano)
+	// A scheduled message's TTL runs from delivery, not from sending.
+	deliverAt, deliveredAt, expiryBase := "", now, sentAt
+	scheduled, err := parseSchedule(req.Schedule, sentAt)
+	if err != nil {
+		return nil, err
+	}
+	if !scheduled.IsZero() {
+		deliverAt = scheduled.Format(time.RFC3339)
+		deliveredAt, expiryBase = deliverAt, scheduled
+	}
+	var expiresAt string
+	if ttl, _ := parseTTL(req.TTL); ttl > 0 {
+		expiresAt = expiryBase.Add(ttl).Format(time.RFC3339)
+	}
 
 	// Marshal structured data if present
 	var structuredJSON string
@@ -556,9 +794,271 @@ func (h *MessageHandler) HandleSend(ctx context.Context, params json.RawMessage)
 		structuredJSON = string(data)
 	}
 
-	// Convert mentions to refs (with group detection and recipient validation)
-	refs := req.Refs
-	scopes := req.Scopes
+	res, err := h.resolveRecipients(ctx, &req, agentID)
+	if err != nil {
+		return nil, err
+	}
+	refs, scopes, recipients := res.refs, res.scopes, res.recipients
+	attachWarnings, err := checkAttachments(ctx, req.Attachments)
+	if err != nil {
+		return nil, err
+	}
+
+	// Handle reply_to: validate parent and add the reply_to ref. The thread
+	// the reply joins is settled under the write lock below.
+	if req.ReplyTo != "" {
+		var exists bool
+		if err := h.state.DB().QueryRowContext(ctx,
+			`SELECT EXISTS(SELECT 1 FROM messages WHERE message_id = ?)`, req.ReplyTo,
+		).Scan(&exists); err != nil || !exists {
+			return nil, fmt.Errorf("reply_to message not found: %s", req.ReplyTo)
+		}
+		refs = append(refs, types.Ref{Type: "reply_to", Value: req.ReplyTo})
+	}
+
+	// Copy attachments onto the sync branch last, once nothing else can
+	// reject the send; the next sync commits them with the event.
+	if len(req.Attachments) > 0 {
+		attachRefs, err := h.storeAttachments(messageID, req.Attachments)
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, attachRefs...)
+	}
+
+	// Build message.create event
+	event := types.MessageCreateEvent{
+		Type:      "message.create",
+		Timestamp: now,
+		MessageID: messageID,
+		AgentID:   agentID,
+		SessionID: sessionID,
+		Body: types.MessageBody{
+			Format:     format,
+			Content:    req.Content,
+			Structured: structuredJSON,
+		},
+		Scopes:     scopes,
+		Refs:       refs,
+		Recipients: recipients,
+		AuthoredBy: authoredBy,
+		Disclosed:  disclosed,
+		Tags:       tags,
+		Priority:   priority,
+		ExpiresAt:  expiresAt,
+		DeliverAt:  deliverAt,
+	}
+
+	phaseRecipientsMs = time.Since(recipientsStart).Milliseconds()
+
+	// Write event to JSONL and SQLite. Lock only for WriteEvent;
+	// thrum-bsn7: release state.Lock() BEFORE invoking postCommit so the
+	// structural-event walker+compactor (up to 90s wall-clock) cannot
+	// starve concurrent message.create / agent.register etc.
+	weStart := time.Now()
+	h.state.Lock()
+	var threadID string
+	var movePostCommit func()
+	if req.ReplyTo != "" {
+		threadID, movePostCommit, err = h.replyThreadLocked(ctx, req.ReplyTo, agentID, now, req.NoThread)
+		event.ThreadID = threadID
+	}
+	var postCommit func()
+	if err == nil {
+		postCommit, err = h.state.WriteEvent(ctx, event)
+		if err != nil {
+			err = fmt.Errorf("write message.create event: %w", err)
+		}
+	}
+	h.state.Unlock()
+	h.state.GoPostCommit(movePostCommit)
+	phaseWriteEventMs = time.Since(weStart).Milliseconds()
+	if err != nil {
+		if len(req.Attachments) > 0 {
+			h.removeAttachments(messageID)
+		}
+		return nil, err
+	}
+	h.sentCount.Add(1)
+	// thrum-1nkt.5: postCommit now runs async via GoPostCommit, so this
+	// metric measures only the goroutine-launch latency (sub-millisecond)
+	// rather than the walker+compactor wall-clock it tracked pre-1nkt.5.
+	// Profile consumers reading near-zero values here should NOT interpret
+	// that as a fast walker — the caller no longer blocks on walker
+	// completion at all. Use the walker's own profile.* slog records
+	// (snapshot.go / compact.go) for actual walker timing.
+	pcStart := time.Now()
+	h.state.GoPostCommit(postCommit)
+	phasePostCommitMs = time.Since(pcStart).Milliseconds()
+	dispatchStart := time.Now()
+	defer func() { phaseDispatchMs = time.Since(dispatchStart).Milliseconds() }()
+
+	// No lock for dispatch and emit (WebSocket I/O)
+	preview := req.Content
+	if len(preview) > 100 {
+		preview = preview[:100]
+	}
+
+	msgInfo := &subscriptions.MessageInfo{
+		MessageID: messageID,
+		ThreadID:  threadID,
+		AgentID:   agentID,
+		SessionID: sessionID,
+		Scopes:    event.Scopes,
+		Refs:      event.Refs,
+		Timestamp: now,
+		Preview:   preview,
+	}
+
+	// Find matching subscriptions and push notifications to connected
+	// clients. A scheduled message is dispatched by ReleaseScheduled once
+	// it is due instead.
+	if deliverAt == "" {
+		_, _ = h.dispatcher.DispatchForMessage(ctx, msgInfo)
+	}
+
+	// thrum-wvpv: tmux nudge dispatch moved into the SetOnEventWrite hook
+	// (cmd/thrum/main.go) so the same code path covers BOTH local writes
+	// (this handler) and synced writes (peer sync, cross-repo bridge).
+	// The hook receives the persisted event payload, including the
+	// recipients list, and calls nudge.DispatchTmux. Removing the inline
+	// block here prevents double-nudging on the local path.
+
+	// thrum-48kt.1: WebSocket notification.message broadcast MOVED into
+	// the SetOnEventWrite hook so writers that bypass HandleSend
+	// (permission.SendSupervisorMessage, peer-synced events) also trigger
+	// OutboundRelay → Telegram forwarding. Keeping it inline here would
+	// double-fire on the HandleSend path. Hook location:
+	// cmd/thrum/main.go SetOnEventWrite closure → messageHandler.NotifyMessageCreate.
+
+	// Emit thread.updated event for real-time updates
+	if threadID != "" {
+		_ = h.emitThreadUpdated(ctx, threadID)
+	}
+
+	return &SendResponse{
+		MessageID:  messageID,
+		ThreadID:   threadID,
+		CreatedAt:  now,
+		ExpiresAt:  expiresAt,
+		DeliverAt:  deliverAt,
+		ResolvedTo: res.resolvedTo,
+		Warnings:   append(res.warnings, attachWarnings...),
+		Audiences:  res.audiences,
+		Recipients: buildDeliveredRecipients(recipients, deliveredAt),
+	}, nil
+}
+
+// replyThreadLocked returns the thread a reply to parentID joins. A parent in
+// a thread keeps it. A parent outside any thread starts a new one, written
+// as a message.move of the parent so peers and rebuilds put it in the thread
+// too; with noThread it stays flat and so does the reply. The returned
+// postCommit belongs to that move. The caller holds the state write lock, so
+// concurrent first replies to the same parent share one thread.
+func (h *MessageHandler) replyThreadLocked(ctx context.Context, parentID, agentID, now string, noThread bool) (string, func(), error) {
+	var parentThreadID sql.NullString
+	if err := h.state.DB().QueryRowContext(ctx,
+		`SELECT thread_id FROM messages WHERE message_id = ?`, parentID,
+	).Scan(&parentThreadID); err != nil {
+		return "", nil, fmt.Errorf("reply_to message not found: %s", parentID)
+	}
+	if parentThreadID.String != "" || noThread {
+		return parentThreadID.String, nil, nil
+	}
+
+	threadID := identity.GenerateThreadID()
+	postCommit, err := h.state.WriteEvent(ctx, types.MessageMoveEvent{
+		Type:      "message.move",
+		Timestamp: now,
+		MessageID: parentID,
+		ThreadID:  threadID,
+		AgentID:   agentID,
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("write message.move event: %w", err)
+	}
+	return threadID, postCommit, nil
+}
+
+// validateSendFields checks the non-recipient fields of a message.send
+// request and returns the effective format (default markdown), normalized
+// tags, and priority.
+func (h *MessageHandler) validateSendFields(req *SendRequest) (format string, tags []string, priority string, err error) {
+	// Validate required fields
+	if req.Content == "" {
+		return "", nil, "", fmt.Errorf("content is required")
+	}
+
+	// Default format to markdown
+	format = req.Format
+	if format == "" {
+		format = "markdown"
+	}
+
+	// Validate format
+	if format != "markdown" && format != "plain" && format != "json" {
+		return "", nil, "", fmt.Errorf("invalid format: %s (must be 'markdown', 'plain', or 'json')", format)
+	}
+
+	// A json body is consumed by machines; reject it here rather than store
+	// something downstream parsers choke on. Content is stored as sent.
+	if format == "json" {
+		var v any
+		if err := json.Unmarshal([]byte(req.Content), &v); err != nil {
+			return "", nil, "", fmt.Errorf("content is not valid JSON (format 'json'): %w", err)
+		}
+	}
+
+	tags, err = normalizeTags(req.Tags)
+	if err != nil {
+		return "", nil, "", err
+	}
+	priority, err = normalizePriority(req.Priority)
+	if err != nil {
+		return "", nil, "", err
+	}
+	if _, err := parseTTL(req.TTL); err != nil {
+		return "", nil, "", err
+	}
+	if _, err := parseSchedule(req.Schedule, time.Now().UTC()); err != nil {
+		return "", nil, "", err
+	}
+	for _, ref := range req.Refs {
+		if ref.Type == "attachment" {
+			return "", nil, "", fmt.Errorf("attachment refs are added by attachments (send --attach), not passed as refs")
+		}
+	}
+
+	// thrum-mhwt: cap body.content size at write so a runaway operator
+	// or hot-loop client cannot inflate events.jsonl past the
+	// compactor's read ceiling. h.maxBodyBytes is the effective limit
+	// (set at construction from DaemonConfig.MaxMessageBodyBytesEffective);
+	// 0 disables the cap (test path). Structured payloads count too.
+	if err := h.checkBodySize(req.Content, req.Structured); err != nil {
+		return "", nil, "", err
+	}
+	return format, tags, priority, nil
+}
+
+// sendResolution is the outcome of resolving a message.send request's
+// --to and mentions: the scopes/refs to store and who receives it.
+type sendResolution struct {
+	scopes     []types.Scope
+	refs       []types.Ref
+	resolvedTo int
+	warnings   []string
+	audiences  []MessageAudience
+	recipients []string // sorted agent IDs
+}
+
+// resolveRecipients converts req.To, req.Mentions and req.SnapshotGroups into
+// scopes, refs and the recipient set for a message authored by agentID, with
+// group detection and recipient validation. Unknown recipients are an error. Shared by
+// HandleSend and HandleResolve (send --dry-run) so a dry run fails exactly
+// where the real send would. Read-only.
+func (h *MessageHandler) resolveRecipients(ctx context.Context, req *SendRequest, agentID string) (*sendResolution, error) {
+	refs := append([]types.Ref(nil), req.Refs...)
+	scopes := append([]types.Scope(nil), req.Scopes...)
 	resolvedTo := 0
 	var warnings []string
 	var unknownRecipients []string
@@ -571,6 +1071,10 @@ func (h *MessageHandler) HandleSend(ctx context.Context, params json.RawMessage)
 		if len(toVal) > 0 && toVal[0] == '@' {
 			toVal = toVal[1:]
 		}
+		toVal, err := identity.ResolveAlias(ctx, h.state.DB(), toVal)
+		if err != nil {
+			return nil, err
+		}
 		if toVal == "everyone" {
 			// Direct broadcast — no group expansion. Scoped to this daemon only.
 			scopes = append(scopes, types.Scope{Type: "broadcast", Value: "everyone"})
@@ -610,6 +1114,10 @@ func (h *MessageHandler) HandleSend(ctx context.Context, params json.RawMessage)
 		if len(role) > 0 && role[0] == '@' {
 			role = role[1:]
 		}
+		role, err := identity.ResolveAlias(ctx, h.state.DB(), role)
+		if err != nil {
+			return nil, err
+		}
 
 		// @everyone → direct broadcast (not group-based, fixes cross-repo sync leak)
 		if role == "everyone" {
@@ -678,6 +1186,45 @@ func (h *MessageHandler) HandleSend(ctx context.Context, params json.RawMessage)
 		}
 	}
 
+	// Snapshot groups (push model): expand the group now, recursively through
+	// nested groups and roles, and address each member with a mention ref, so
+	// later membership changes do not alter who received the message. The
+	// snapshot_group ref records which group the mentions came from.
+	mentioned := make(map[string]bool)
+	for _, ref := range refs {
+		if ref.Type == "mention" {
+			mentioned[ref.Value] = true
+		}
+	}
+	for _, g := range req.SnapshotGroups {
+		name := strings.TrimPrefix(g, "@")
+		isGroup, err := h.groupResolver.IsGroup(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("check group %q: %w", name, err)
+		}
+		if !isGroup {
+			unknownRecipients = append(unknownRecipients, "@"+name)
+			continue
+		}
+		members, err := h.groupResolver.ExpandMembers(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("expand group %q: %w", name, err)
+		}
+		if len(members) == 0 {
+			return nil, fmt.Errorf("group @%s has no members to snapshot", name)
+		}
+		refs = append(refs, types.Ref{Type: "snapshot_group", Value: name})
+		for _, member := range members {
+			if !mentioned[member] {
+				mentioned[member] = true
+				refs = append(refs, types.Ref{Type: "mention", Value: member})
+				audiences = append(audiences, MessageAudience{Type: "agent", Value: member})
+			}
+			recipientSet[member] = struct{}{}
+		}
+		resolvedTo++
+	}
+
 	// Fail hard if any recipients could not be resolved
 	if len(unknownRecipients) > 0 {
 		return nil, fmt.Errorf("unknown recipient: %s — send to agents directly with --to @agent_name",
@@ -685,7 +1232,7 @@ func (h *MessageHandler) HandleSend(ctx context.Context, params json.RawMessage)
 	}
 
 	// Messages without an explicit audience remain broadcast/general messages.
-	if len(req.Mentions) == 0 && req.To == "" {
+	if len(req.Mentions) == 0 && req.To == "" && len(req.SnapshotGroups) == 0 {
 		audiences = append(audiences, MessageAudience{Type: "broadcast", Value: "everyone"})
 		allAgents, err := h.queryAllOtherAgents(ctx, agentID)
 		if err != nil {
@@ -702,129 +1249,87 @@ func (h *MessageHandler) HandleSend(ctx context.Context, params json.RawMessage)
 	}
 	sort.Strings(recipients)
 
-	// Handle reply_to: validate parent, auto-thread, add reply_to ref
-	var threadID string
-	if req.ReplyTo != "" {
-		var parentThreadID sql.NullString
-		err := h.state.DB().QueryRowContext(ctx,
-			`SELECT thread_id FROM messages WHERE message_id = ?`, req.ReplyTo,
-		).Scan(&parentThreadID)
-		if err != nil {
-			return nil, fmt.Errorf("reply_to message not found: %s", req.ReplyTo)
-		}
-		refs = append(refs, types.Ref{Type: "reply_to", Value: req.ReplyTo})
+	return &sendResolution{
+		scopes:     scopes,
+		refs:       refs,
+		resolvedTo: resolvedTo,
+		warnings:   warnings,
+		audiences:  audiences,
+		recipients: recipients,
+	}, nil
+}
 
-		// Auto-thread: propagate existing or create new thread_id
-		if parentThreadID.Valid && parentThreadID.String != "" {
-			threadID = parentThreadID.String
-		} else {
-			threadID = identity.GenerateThreadID()
-			// Update parent to join the thread
-			_, _ = h.state.DB().ExecContext(ctx,
-				`UPDATE messages SET thread_id = ? WHERE message_id = ?`,
-				threadID, req.ReplyTo,
-			)
-		}
+// HandleResolve handles the message.resolve RPC method: it runs message.send's
+// validation and recipient resolution for a SendRequest and reports who would
+// receive it, without writing an event. It fails with the same errors the
+// real send would (unknown recipients, bad format, missing reply_to parent).
+func (h *MessageHandler) HandleResolve(ctx context.Context, params json.RawMessage) (any, error) {
+	var req SendRequest
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
 	}
-
-	// Build message.create event
-	event := types.MessageCreateEvent{
-		Type:      "message.create",
-		Timestamp: now,
-		MessageID: messageID,
-		ThreadID:  threadID,
-		AgentID:   agentID,
-		SessionID: sessionID,
-		Body: types.MessageBody{
-			Format:     format,
-			Content:    req.Content,
-			Structured: structuredJSON,
-		},
-		Scopes:     scopes,
-		Refs:       refs,
-		Recipients: recipients,
-		AuthoredBy: authoredBy,
-		Disclosed:  disclosed,
+	if _, _, _, err := h.validateSendFields(&req); err != nil {
+		return nil, err
 	}
 
-	phaseRecipientsMs = time.Since(recipientsStart).Milliseconds()
-
-	// Write event to JSONL and SQLite. Lock only for WriteEvent;
-	// thrum-bsn7: release state.Lock() BEFORE invoking postCommit so the
-	// structural-event walker+compactor (up to 90s wall-clock) cannot
-	// starve concurrent message.create / agent.register etc.
-	weStart := time.Now()
-	h.state.Lock()
-	postCommit, err := h.state.WriteEvent(ctx, event)
-	h.state.Unlock()
-	phaseWriteEventMs = time.Since(weStart).Milliseconds()
+	callerID, _, err := h.resolveAgentAndSession(ctx, req.CallerAgentID)
 	if err != nil {
-		return nil, fmt.Errorf("write message.create event: %w", err)
+		return nil, fmt.Errorf("resolve agent and session: %w", err)
 	}
-	// thrum-1nkt.5: postCommit now runs async via GoPostCommit, so this
-	// metric measures only the goroutine-launch latency (sub-millisecond)
-	// rather than the walker+compactor wall-clock it tracked pre-1nkt.5.
-	// Profile consumers reading near-zero values here should NOT interpret
-	// that as a fast walker — the caller no longer blocks on walker
-	// completion at all. Use the walker's own profile.* slog records
-	// (snapshot.go / compact.go) for actual walker timing.
-	pcStart := time.Now()
-	h.state.GoPostCommit(postCommit)
-	phasePostCommitMs = time.Since(pcStart).Milliseconds()
-	dispatchStart := time.Now()
-	defer func() { phaseDispatchMs = time.Since(dispatchStart).Milliseconds() }()
-
-	// No lock for dispatch and emit (WebSocket I/O)
-	preview := req.Content
-	if len(preview) > 100 {
-		preview = preview[:100]
+	agentID := callerID
+	if req.ActingAs != "" {
+		if err := h.validateImpersonation(ctx, callerID, req.ActingAs); err != nil {
+			return nil, err
+		}
+		agentID = req.ActingAs
 	}
 
-	msgInfo := &subscriptions.MessageInfo{
-		MessageID: messageID,
-		ThreadID:  threadID,
-		AgentID:   agentID,
-		SessionID: sessionID,
-		Scopes:    event.Scopes,
-		Refs:      event.Refs,
-		Timestamp: now,
-		Preview:   preview,
+	res, err := h.resolveRecipients(ctx, &req, agentID)
+	if err != nil {
+		return nil, err
+	}
+	attachWarnings, err := checkAttachments(ctx, req.Attachments)
+	if err != nil {
+		return nil, err
 	}
 
-	// Find matching subscriptions and push notifications to connected clients
-	_, _ = h.dispatcher.DispatchForMessage(ctx, msgInfo)
-
-	// thrum-wvpv: tmux nudge dispatch moved into the SetOnEventWrite hook
-	// (cmd/thrum/main.go) so the same code path covers BOTH local writes
-	// (this handler) and synced writes (peer sync, cross-repo bridge).
-	// The hook receives the persisted event payload, including the
-	// recipients list, and calls nudge.DispatchTmux. Removing the inline
-	// block here prevents double-nudging on the local path.
-
-	// thrum-48kt.1: WebSocket notification.message broadcast MOVED into
-	// the SetOnEventWrite hook so writers that bypass HandleSend
-	// (permission.SendSupervisorMessage, peer-synced events) also trigger
-	// OutboundRelay → Telegram forwarding. Keeping it inline here would
-	// double-fire on the HandleSend path. Hook location:
-	// cmd/thrum/main.go SetOnEventWrite closure → messageHandler.NotifyMessageCreate.
-
-	// Emit thread.updated event for real-time updates
-	if threadID != "" {
-		_ = h.emitThreadUpdated(ctx, threadID)
+	resp := &ResolveResponse{
+		AgentID:    agentID,
+		ResolvedTo: res.resolvedTo,
+		Warnings:   append(res.warnings, attachWarnings...),
+		Audiences:  res.audiences,
+		Recipients: res.recipients,
+		Scopes:     res.scopes,
+		Refs:       res.refs,
+	}
+	if req.ReplyTo != "" {
+		// Same lookup as HandleSend, minus its auto-thread UPDATE.
+		var parentThreadID sql.NullString
+		err := h.state.DB().QueryRowContext(ctx,
+			`SELECT thread_id FROM messages WHERE message_id = ?`, req.ReplyTo,
+		).Scan(&parentThreadID)
+		if err != nil {
+			return nil, fmt.Errorf("reply_to message not found: %s", req.ReplyTo)
+		}
+		resp.Refs = append(resp.Refs, types.Ref{Type: "reply_to", Value: req.ReplyTo})
+		resp.ThreadID = parentThreadID.String
+		resp.NewThread = resp.ThreadID == "" && !req.NoThread
 	}
-
-	return &SendResponse{
-		MessageID:  messageID,
-		ThreadID:   threadID,
-		CreatedAt:  now,
-		ResolvedTo: resolvedTo,
-		Warnings:   warnings,
-		Audiences:  audiences,
-		Recipients: buildDeliveredRecipients(recipients, now),
-	}, nil
+	if resp.Recipients == nil {
+		resp.Recipients = []string{}
+	}
+	if resp.Scopes == nil {
+		resp.Scopes = []types.Scope{}
+	}
+	if resp.Refs == nil {
+		resp.Refs = []types.Ref{}
+	}
+	return resp, nil
 }
 
-// HandleGet handles the message.get RPC method.
+// HandleGet handles the message.get RPC method. A message hidden by
+// visibilityClause (scheduled and not yet delivered, or expired) is reported
+// as not found to everyone but its author.
 func (h *MessageHandler) HandleGet(ctx context.Context, params json.RawMessage) (any, error) {
 	var req GetMessageRequest
 	if err := json.Unmarshal(params, &req); err != nil {
@@ -840,16 +1345,19 @@ func (h *MessageHandler) HandleGet(ctx context.Context, params json.RawMessage)
 	defer h.state.RUnlock()
 
 	// Query message
-	query := `SELECT message_id, thread_id, agent_id, session_id, created_at, updated_at,
-	                 body_format, body_content, body_structured, deleted, deleted_at, delete_reason
-	          FROM messages
-	          WHERE message_id = ?`
+	visClause, visArgs := visibilityClause(time.Now(), false)
+	query := `SELECT m.message_id, m.thread_id, m.agent_id, m.session_id, m.created_at, m.updated_at,
+	                 m.body_format, m.body_content, m.body_structured, m.deleted, m.deleted_at, m.delete_reason,
+	                 (1` + visClause + `)
+	          FROM messages m
+	          WHERE m.message_id = ?`
 
 	var msg MessageDetail
 	var threadID, updatedAt, bodyStructured, deletedAt, deleteReason sql.NullString
 	var deleted int
+	var visible bool
 
-	err := h.state.DB().QueryRowContext(ctx, query, req.MessageID).Scan(
+	err := h.state.DB().QueryRowContext(ctx, query, append(visArgs, req.MessageID)...).Scan(
 		&msg.MessageID,
 		&threadID,
 		&msg.Author.AgentID,
@@ -862,6 +1370,7 @@ func (h *MessageHandler) HandleGet(ctx context.Context, params json.RawMessage)
 		&deleted,
 		&deletedAt,
 		&deleteReason,
+		&visible,
 	)
 
 	if err == sql.ErrNoRows {
@@ -870,6 +1379,14 @@ func (h *MessageHandler) HandleGet(ctx context.Context, params json.RawMessage)
 	if err != nil {
 		return nil, fmt.Errorf("query message: %w", err)
 	}
+	if !visible {
+		// Only resolve the caller for hidden messages; like HandleList this
+		// is safe with the RLock held.
+		callerID, guardErr := h.resolveAgentOnly(ctx, req.CallerAgentID)
+		if guardErr != nil || callerID == "" || callerID != msg.Author.AgentID {
+			return nil, fmt.Errorf("message not found: %s", req.MessageID)
+		}
+	}
 
 	// Set optional fields
 	if threadID.Valid {
@@ -949,6 +1466,17 @@ func (h *MessageHandler) HandleGet(ctx context.Context, params json.RawMessage)
 	}
 	msg.Recipients = recipients[req.MessageID]
 
+	msg.Reactions, err = h.loadReactions(ctx, req.MessageID)
+	if err != nil {
+		return nil, err
+	}
+
+	msg.Tags, err = h.loadTags(ctx, req.MessageID)
+	if err != nil {
+		return nil, err
+	}
+	msg.Attachments = attachmentsFromRefs(msg.Refs)
+
 	return &GetMessageResponse{Message: msg}, nil
 }
 
@@ -977,8 +1505,8 @@ func (h *MessageHandler) HandleList(ctx context.Context, params json.RawMessage)
 	if sortBy == "" {
 		sortBy = "created_at"
 	}
-	if sortBy != "created_at" && sortBy != "updated_at" {
-		return nil, fmt.Errorf("invalid sort_by: %s (must be 'created_at' or 'updated_at')", sortBy)
+	if sortBy != "created_at" && sortBy != "updated_at" && sortBy != "bumped_at" && sortBy != "sequence" {
+		return nil, fmt.Errorf("invalid sort_by: %s (must be 'created_at', 'updated_at', 'bumped_at' or 'sequence')", sortBy)
 	}
 
 	sortOrder := req.SortOrder
@@ -989,6 +1517,32 @@ func (h *MessageHandler) HandleList(ctx context.Context, params json.RawMessage)
 		return nil, fmt.Errorf("invalid sort_order: %s (must be 'asc' or 'desc')", sortOrder)
 	}
 
+	// The reply-clustered inbox view and priority_sort don't order on a
+	// single key, so they have no cursor.
+	clustered := (req.ForAgent != "" || req.ForAgentRole != "") && req.SortOrder == "" && req.Chronological
+	keyset := !clustered && !req.PrioritySort
+
+	// A cursor carries the sort it was issued under; the page continues in
+	// that order rather than silently resuming a different one.
+	var cursor *messageCursor
+	if req.AfterCursor != "" {
+		c, err := decodeMessageCursor(req.AfterCursor)
+		if err != nil {
+			return nil, err
+		}
+		if (req.SortBy != "" && req.SortBy != c.SortBy) || (req.SortOrder != "" && req.SortOrder != c.SortOrder) {
+			return nil, fmt.Errorf("after_cursor was issued for sort_by %s %s, not %s %s", c.SortBy, c.SortOrder, sortBy, sortOrder)
+		}
+		if req.Page > 1 {
+			return nil, fmt.Errorf("after_cursor and page are mutually exclusive")
+		}
+		if !keyset {
+			return nil, fmt.Errorf("after_cursor is not supported with chronological or priority_sort")
+		}
+		sortBy, sortOrder = c.SortBy, c.SortOrder
+		cursor = &c
+	}
+
 	h.state.RLock()
 	defer h.state.RUnlock()
 
@@ -1061,6 +1615,24 @@ func (h *MessageHandler) HandleList(ctx context.Context, params json.RawMessage)
 		}
 	}
 
+	// Unseen-by (coordinator backlog view): the caller inspects ANOTHER
+	// agent's read state, so only coordinator roles (and user: callers,
+	// who may already impersonate any agent) are allowed to ask.
+	var unseenByRole string
+	if req.UnseenBy != "" {
+		if err := h.checkUnseenByAllowed(ctx, currentAgentID); err != nil {
+			return nil, err
+		}
+		err := h.state.DB().QueryRowContext(ctx,
+			`SELECT role FROM agents WHERE agent_id = ?`, req.UnseenBy).Scan(&unseenByRole)
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("unknown agent for unseen_by: %s", req.UnseenBy)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("check unseen_by agent: %w", err)
+		}
+	}
+
 	// Determine which identity to use for the is_read correlated subquery.
 	// Priority: explicit currentAgentID > for_agent values > none (default 0).
 	forAgentValues := buildForAgentValues(req.ForAgent, req.ForAgentRole)
@@ -1081,12 +1653,18 @@ func (h *MessageHandler) HandleList(ctx context.Context, params json.RawMessage)
 		selectCols = `SELECT m.message_id, m.thread_id, m.agent_id, m.created_at, m.updated_at,
 		                     m.body_format, m.body_content, m.body_structured, m.deleted,
 		                     CASE WHEN EXISTS(SELECT 1 FROM message_deliveries md WHERE md.message_id = m.message_id AND md.recipient_agent_id IN (` + strings.Join(placeholders, ",") + `) AND md.read_at IS NOT NULL) THEN 1 ELSE 0 END as is_read,
-		                     reply_ref.ref_value as reply_to`
+		                     reply_ref.ref_value as reply_to, m.priority,
+		                     EXISTS(SELECT 1 FROM message_pins mp WHERE mp.message_id = m.message_id) as pinned,
+		                     m.expires_at, m.deleted_at, m.delete_reason, m.bumped_at,
+		                     m.origin_daemon, m.origin_sequence, m.deliver_at`
 	} else {
 		selectCols = `SELECT m.message_id, m.thread_id, m.agent_id, m.created_at, m.updated_at,
 		                     m.body_format, m.body_content, m.body_structured, m.deleted,
 		                     0 as is_read,
-		                     reply_ref.ref_value as reply_to`
+		                     reply_ref.ref_value as reply_to, m.priority,
+		                     EXISTS(SELECT 1 FROM message_pins mp WHERE mp.message_id = m.message_id) as pinned,
+		                     m.expires_at, m.deleted_at, m.delete_reason, m.bumped_at,
+		                     m.origin_daemon, m.origin_sequence, m.deliver_at`
 	}
 	query := selectCols + "\n\t          FROM messages m" +
 		"\n\t          LEFT JOIN message_refs reply_ref ON reply_ref.message_id = m.message_id AND reply_ref.ref_type = 'reply_to'"
@@ -1099,6 +1677,10 @@ func (h *MessageHandler) HandleList(ctx context.Context, params json.RawMessage)
 	if req.Ref != nil {
 		joins += " INNER JOIN message_refs mr ON m.message_id = mr.message_id"
 	}
+	req.Tag = strings.TrimSpace(req.Tag)
+	if req.Tag != "" {
+		joins += " INNER JOIN message_tags mt ON m.message_id = mt.message_id"
+	}
 
 	query += joins + " WHERE 1=1"
 
@@ -1119,6 +1701,11 @@ func (h *MessageHandler) HandleList(ctx context.Context, params json.RawMessage)
 		args = append(args, req.AuthorID)
 	}
 
+	if req.AuthorRole != "" {
+		query += " AND m.agent_id IN (SELECT agent_id FROM agents WHERE role = ?)"
+		args = append(args, req.AuthorRole)
+	}
+
 	// Exclude messages authored by the current agent (inbox mode)
 	var excludeAgentID string
 	if req.ExcludeSelf && currentAgentID != "" {
@@ -1137,6 +1724,31 @@ func (h *MessageHandler) HandleList(ctx context.Context, params json.RawMessage)
 		args = append(args, req.Ref.Type, req.Ref.Value)
 	}
 
+	if req.Tag != "" {
+		query += " AND mt.tag = ?"
+		args = append(args, req.Tag)
+	}
+
+	filterPriority := req.Priority != ""
+	priority, err := normalizePriority(req.Priority)
+	if err != nil {
+		return nil, err
+	}
+	if filterPriority {
+		query += " AND m.priority = ?"
+		args = append(args, priority)
+	}
+
+	const pinnedClause = " AND m.message_id IN (SELECT message_id FROM message_pins)"
+	if req.Pinned {
+		query += pinnedClause
+	}
+	const assignedClause = " AND m.message_id IN (SELECT message_id FROM message_assignments WHERE assignee = ? AND status = 'open')"
+	if req.AssignedTo != "" {
+		query += assignedClause
+		args = append(args, req.AssignedTo)
+	}
+
 	// Mentions filter: explicit MentionRole takes priority, then CallerMentionRole, falls back to config when Mentions=true
 	mentionRole := req.MentionRole
 	if mentionRole == "" && req.CallerMentionRole != "" && req.Mentions {
@@ -1150,14 +1762,34 @@ func (h *MessageHandler) HandleList(ctx context.Context, params json.RawMessage)
 	}
 	mentionClause, mentionArgs := buildMentionFilterClause(mentionRole)
 
-	// Unread filter: explicit UnreadForAgent takes priority, falls back to config when Unread=true
+	// Unread filter: UnseenBy wins, then explicit UnreadForAgent, then config
+	// resolution when Unread=true. UnseenBy also drops the target's own
+	// messages — an agent never "catches up" on what it wrote itself — and
+	// keeps only the target's audience, so the backlog matches its inbox.
 	unreadAgentID := req.UnreadForAgent
+	if req.UnseenBy != "" {
+		unreadAgentID = req.UnseenBy
+	}
 	if unreadAgentID == "" && req.Unread {
 		agentID, _, resolveErr := h.resolveAgentAndSession(ctx, req.CallerAgentID)
 		if resolveErr == nil {
 			unreadAgentID = agentID
 		}
 	}
+	unreadClause := ""
+	var unreadClauseArgs []any
+	if unreadAgentID != "" {
+		unreadClause = " AND m.message_id NOT IN (SELECT md.message_id FROM message_deliveries md WHERE md.recipient_agent_id = ? AND md.read_at IS NOT NULL)"
+		unreadClauseArgs = []any{unreadAgentID}
+		if req.UnseenBy != "" {
+			unreadClause += " AND m.agent_id != ?"
+			unreadClauseArgs = append(unreadClauseArgs, req.UnseenBy)
+			audienceClause, audienceArgs := buildForAgentClause(
+				buildForAgentValues(req.UnseenBy, unseenByRole), req.UnseenBy, unseenByRole)
+			unreadClause += audienceClause
+			unreadClauseArgs = append(unreadClauseArgs, audienceArgs...)
+		}
+	}
 
 	// Time filter: only return messages created after a given timestamp
 	createdAfterClause := ""
@@ -1169,8 +1801,9 @@ func (h *MessageHandler) HandleList(ctx context.Context, params json.RawMessage)
 
 	// For-agent floor: when filtering for a specific agent, use the agent's
 	// registered_at as a floor for CreatedAfter so historical group/broadcast
-	// messages sent before the agent existed are excluded.
-	if req.ForAgent != "" {
+	// messages sent before the agent existed are excluded. Pinned listings
+	// skip the floor — a pin is meant for everyone, including later arrivals.
+	if req.ForAgent != "" && !req.Pinned {
 		var registeredAt string
 		err := h.state.DB().QueryRowContext(ctx,
 			"SELECT registered_at FROM agents WHERE agent_id = ? LIMIT 1",
@@ -1184,6 +1817,37 @@ func (h *MessageHandler) HandleList(ctx context.Context, params json.RawMessage)
 		}
 	}
 
+	// Scheduled and expired messages (visibilityClause) ride along with the
+	// time filter so every count below agrees with the listing.
+	expiryClause, expiryArgs := visibilityClause(time.Now(), req.IncludeExpired)
+	createdAfterClause += expiryClause
+	createdAfterArgs = append(createdAfterArgs, expiryArgs...)
+
+	// Tombstones stay out of listings unless asked for. Unlike expiry this
+	// is kept off the unread and hidden counts below, which always skip
+	// deleted rows: a tombstone is never something left to read.
+	deletedClause := ""
+	if !req.IncludeDeleted {
+		deletedClause = " AND m.deleted = 0"
+	}
+
+	// Muted agent (agent mute): a waiting listener only sees what the mute
+	// lets through. Added to the time filter for the same reason as expiry.
+	if req.RespectMute && req.ForAgent != "" {
+		mute, err := subscriptions.ActiveMute(ctx, h.state.DB(), req.ForAgent, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case mute == nil:
+		case mute.AllowMentions:
+			createdAfterClause += " AND EXISTS (SELECT 1 FROM message_refs mr WHERE mr.message_id = m.message_id AND mr.ref_type = 'mention' AND mr.ref_value = ?)"
+			createdAfterArgs = append(createdAfterArgs, req.ForAgent)
+		default:
+			createdAfterClause += " AND 0"
+		}
+	}
+
 	// For-agent filter: show messages mentioning me + messages scoped to my groups
 	// (forAgentValues already computed above for is_read)
 	forAgentClause, forAgentArgs := buildForAgentClause(forAgentValues, req.ForAgent, req.ForAgentRole)
@@ -1200,13 +1864,19 @@ func (h *MessageHandler) HandleList(ctx context.Context, params json.RawMessage)
 		query += forAgentClause
 		args = append(args, forAgentArgs...)
 	}
-	if unreadAgentID != "" {
-		query += " AND m.message_id NOT IN (SELECT md.message_id FROM message_deliveries md WHERE md.recipient_agent_id = ? AND md.read_at IS NOT NULL)"
-		args = append(args, unreadAgentID)
-	}
-	query += createdAfterClause
+	query += unreadClause
+	args = append(args, unreadClauseArgs...)
+	query += createdAfterClause + deletedClause
 	args = append(args, createdAfterArgs...)
 
+	// Cursor position: main query only, so total and the unread counts
+	// still describe the whole listing.
+	if cursor != nil {
+		cursorClause, cursorArgs := cursor.clause()
+		query += cursorClause
+		args = append(args, cursorArgs...)
+	}
+
 	// Add sorting (thrum-3vl0 / thrum-4yjc). Inbox mode (for_agent/for_agent_role
 	// set) with NO explicit sort_order now defaults to NEWEST-FIRST so a recent
 	// message is never buried under backlog and `--limit N` returns the newest N
@@ -1217,11 +1887,22 @@ func (h *MessageHandler) HandleList(ctx context.Context, params json.RawMessage)
 	// (e.g. wait/MCP pass desc/asc directly), so those callers are unaffected;
 	// the newest-first default falls through to the shared sortBy/sortOrder path
 	// below (sortOrder defaults to "desc").
+	query += " ORDER BY "
+	if req.PrioritySort {
+		query += "CASE WHEN m.priority = 'high' AND is_read = 0 THEN 0 ELSE 1 END, "
+	}
 	switch {
-	case (req.ForAgent != "" || req.ForAgentRole != "") && req.SortOrder == "" && req.Chronological:
-		query += " ORDER BY COALESCE(reply_ref.ref_value, m.message_id) ASC, m.created_at ASC"
+	case clustered:
+		query += "COALESCE(reply_ref.ref_value, m.message_id) ASC, m.created_at ASC"
+	case sortBy == "sequence":
+		// Sequences are per daemon, so two peers' messages can share one;
+		// the origin daemon ID breaks the tie, and message_id makes the
+		// order total for legacy rows that predate origin_sequence (0, '').
+		query += fmt.Sprintf("m.origin_sequence %[1]s, m.origin_daemon %[1]s, m.message_id %[1]s", sortOrder)
 	default:
-		query += fmt.Sprintf(" ORDER BY m.%s %s", sortBy, sortOrder)
+		// message_id makes the order total, so a cursor taken at a tie
+		// resumes at the right row.
+		query += fmt.Sprintf("%[1]s %[2]s, m.message_id %[2]s", messageSortKey(sortBy), sortOrder)
 	}
 
 	// Count total matching messages (use same filters as main query)
@@ -1235,6 +1916,10 @@ func (h *MessageHandler) HandleList(ctx context.Context, params json.RawMessage)
 		countQuery += " AND m.agent_id = ?"
 		countArgs = append(countArgs, req.AuthorID)
 	}
+	if req.AuthorRole != "" {
+		countQuery += " AND m.agent_id IN (SELECT agent_id FROM agents WHERE role = ?)"
+		countArgs = append(countArgs, req.AuthorRole)
+	}
 	if excludeAgentID != "" {
 		countQuery += " AND m.agent_id != ?"
 		countArgs = append(countArgs, excludeAgentID)
@@ -1247,6 +1932,21 @@ func (h *MessageHandler) HandleList(ctx context.Context, params json.RawMessage)
 		countQuery += " AND mr.ref_type = ? AND mr.ref_value = ?"
 		countArgs = append(countArgs, req.Ref.Type, req.Ref.Value)
 	}
+	if req.Tag != "" {
+		countQuery += " AND mt.tag = ?"
+		countArgs = append(countArgs, req.Tag)
+	}
+	if filterPriority {
+		countQuery += " AND m.priority = ?"
+		countArgs = append(countArgs, priority)
+	}
+	if req.Pinned {
+		countQuery += pinnedClause
+	}
+	if req.AssignedTo != "" {
+		countQuery += assignedClause
+		countArgs = append(countArgs, req.AssignedTo)
+	}
 	switch {
 	case mentionClause != "" && forAgentClause != "":
 		countQuery += combineFilterClauses(mentionClause, forAgentClause)
@@ -1259,11 +1959,9 @@ func (h *MessageHandler) HandleList(ctx context.Context, params json.RawMessage)
 		countQuery += forAgentClause
 		countArgs = append(countArgs, forAgentArgs...)
 	}
-	if unreadAgentID != "" {
-		countQuery += " AND m.message_id NOT IN (SELECT md.message_id FROM message_deliveries md WHERE md.recipient_agent_id = ? AND md.read_at IS NOT NULL)"
-		countArgs = append(countArgs, unreadAgentID)
-	}
-	countQuery += createdAfterClause
+	countQuery += unreadClause
+	countArgs = append(countArgs, unreadClauseArgs...)
+	countQuery += createdAfterClause + deletedClause
 	countArgs = append(countArgs, createdAfterArgs...)
 
 	var total int
@@ -1273,11 +1971,15 @@ func (h *MessageHandler) HandleList(ctx context.Context, params json.RawMessage)
 
 	// Calculate pagination
 	offset := (page - 1) * pageSize
+	if cursor != nil {
+		offset = 0
+	}
 	totalPages := (total + pageSize - 1) / pageSize // Ceiling division
 
-	// Add pagination
+	// Add pagination. One row past the page tells whether a next_cursor
+	// is needed.
 	query += " LIMIT ? OFFSET ?"
-	args = append(args, pageSize, offset)
+	args = append(args, pageSize+1, offset)
 
 	// Execute query
 	rows, err := h.state.DB().QueryContext(ctx, query, args...)
@@ -1287,10 +1989,17 @@ func (h *MessageHandler) HandleList(ctx context.Context, params json.RawMessage)
 	defer func() { _ = rows.Close() }()
 
 	messages := []MessageSummary{}
+	var lastUpdatedAt string // the page's last updated_at, for its cursor
+	more := false
 	for rows.Next() {
 		var msg MessageSummary
-		var threadID, updatedAt, bodyStructured, replyTo sql.NullString
-		var deleted, isRead int
+		var threadID, updatedAt, bodyStructured, replyTo, expiresAt, deletedAt, deleteReason, bumpedAt, deliverAt sql.NullString
+		var deleted, isRead, pinned int
+
+		if len(messages) == pageSize {
+			more = true // the extra row
+			break
+		}
 
 		if err := rows.Scan(
 			&msg.MessageID,
@@ -1304,6 +2013,15 @@ func (h *MessageHandler) HandleList(ctx context.Context, params json.RawMessage)
 			&deleted,
 			&isRead,
 			&replyTo,
+			&msg.Priority,
+			&pinned,
+			&expiresAt,
+			&deletedAt,
+			&deleteReason,
+			&bumpedAt,
+			&msg.OriginDaemon,
+			&msg.Sequence,
+			&deliverAt,
 		); err != nil {
 			return nil, fmt.Errorf("scan message: %w", err)
 		}
@@ -1319,6 +2037,13 @@ func (h *MessageHandler) HandleList(ctx context.Context, params json.RawMessage)
 		}
 		msg.Deleted = deleted == 1
 		msg.IsRead = isRead == 1
+		msg.Pinned = pinned == 1
+		msg.ExpiresAt = expiresAt.String
+		msg.DeliverAt = deliverAt.String
+		msg.BumpedAt = bumpedAt.String
+		msg.DeletedAt = deletedAt.String
+		msg.DeleteReason = deleteReason.String
+		lastUpdatedAt = updatedAt.String
 
 		messages = append(messages, msg)
 	}
@@ -1326,6 +2051,29 @@ func (h *MessageHandler) HandleList(ctx context.Context, params json.RawMessage)
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("iterate messages: %w", err)
 	}
+	// Release the connection now: stopping at the extra row leaves rows
+	// open, and the count queries below need it.
+	_ = rows.Close()
+
+	var nextCursor string
+	if more && keyset {
+		last := messages[len(messages)-1]
+		c := messageCursor{SortBy: sortBy, SortOrder: sortOrder, MessageID: last.MessageID}
+		switch sortBy {
+		case "sequence":
+			c.Sequence, c.Daemon = last.Sequence, last.OriginDaemon
+		case "updated_at":
+			c.Key = lastUpdatedAt
+		case "bumped_at":
+			c.Key = last.BumpedAt
+			if c.Key == "" {
+				c.Key = last.CreatedAt
+			}
+		default:
+			c.Key = last.CreatedAt
+		}
+		nextCursor = encodeMessageCursor(c)
+	}
 
 	// Calculate unread count — must apply the same filters as the messages query
 	// so the count matches the visible message set (for_agent, mention, scope, etc.).
@@ -1349,6 +2097,21 @@ func (h *MessageHandler) HandleList(ctx context.Context, params json.RawMessage)
 			unreadQuery += " AND mr.ref_type = ? AND mr.ref_value = ?"
 			unreadArgs = append(unreadArgs, req.Ref.Type, req.Ref.Value)
 		}
+		if req.Tag != "" {
+			unreadQuery += " AND mt.tag = ?"
+			unreadArgs = append(unreadArgs, req.Tag)
+		}
+		if filterPriority {
+			unreadQuery += " AND m.priority = ?"
+			unreadArgs = append(unreadArgs, priority)
+		}
+		if req.Pinned {
+			unreadQuery += pinnedClause
+		}
+		if req.AssignedTo != "" {
+			unreadQuery += assignedClause
+			unreadArgs = append(unreadArgs, req.AssignedTo)
+		}
 		switch {
 		case mentionClause != "" && forAgentClause != "":
 			unreadQuery += combineFilterClauses(mentionClause, forAgentClause)
@@ -1361,7 +2124,7 @@ func (h *MessageHandler) HandleList(ctx context.Context, params json.RawMessage)
 			unreadQuery += forAgentClause
 			unreadArgs = append(unreadArgs, forAgentArgs...)
 		}
-		unreadQuery += createdAfterClause
+		unreadQuery += createdAfterClause + " AND m.deleted = 0"
 		unreadArgs = append(unreadArgs, createdAfterArgs...)
 		unreadQuery += " AND m.message_id NOT IN (SELECT md2.message_id FROM message_deliveries md2 WHERE md2.recipient_agent_id = ? AND md2.read_at IS NOT NULL)"
 		unreadArgs = append(unreadArgs, currentAgentID)
@@ -1409,6 +2172,21 @@ func (h *MessageHandler) HandleList(ctx context.Context, params json.RawMessage)
 			hiddenQuery += " AND mr.ref_type = ? AND mr.ref_value = ?"
 			hiddenArgs = append(hiddenArgs, req.Ref.Type, req.Ref.Value)
 		}
+		if req.Tag != "" {
+			hiddenQuery += " AND mt.tag = ?"
+			hiddenArgs = append(hiddenArgs, req.Tag)
+		}
+		if filterPriority {
+			hiddenQuery += " AND m.priority = ?"
+			hiddenArgs = append(hiddenArgs, priority)
+		}
+		if req.Pinned {
+			hiddenQuery += pinnedClause
+		}
+		if req.AssignedTo != "" {
+			hiddenQuery += assignedClause
+			hiddenArgs = append(hiddenArgs, req.AssignedTo)
+		}
 		// Intentionally omits forAgentClause — that's the filter we're
 		// measuring "hidden by." mentionClause stays because it's an
 		// identity-relevant filter (mentions of THIS agent's role).
@@ -1416,7 +2194,7 @@ func (h *MessageHandler) HandleList(ctx context.Context, params json.RawMessage)
 			hiddenQuery += mentionClause
 			hiddenArgs = append(hiddenArgs, mentionArgs...)
 		}
-		hiddenQuery += createdAfterClause
+		hiddenQuery += createdAfterClause + " AND m.deleted = 0"
 		hiddenArgs = append(hiddenArgs, createdAfterArgs...)
 		hiddenQuery += " AND m.message_id NOT IN (SELECT md3.message_id FROM message_deliveries md3 WHERE md3.recipient_agent_id = ? AND md3.read_at IS NOT NULL)"
 		hiddenArgs = append(hiddenArgs, currentAgentID)
@@ -1446,14 +2224,26 @@ func (h *MessageHandler) HandleList(ctx context.Context, params json.RawMessage)
 		}
 	}
 
+	// Pinned count backs the inbox's "📌 N pinned" hint, so it ignores
+	// every filter except for-agent visibility: the hint reports what
+	// --pinned would list.
+	pinnedCount := 0
+	pinnedQuery := "SELECT COUNT(*) FROM messages m WHERE m.deleted = 0" + pinnedClause + forAgentClause + expiryClause
+	pinnedArgs := append(append([]any{}, forAgentArgs...), expiryArgs...)
+	if err := h.state.DB().QueryRowContext(ctx, pinnedQuery, pinnedArgs...).Scan(&pinnedCount); err != nil {
+		return nil, fmt.Errorf("count pinned messages: %w", err)
+	}
+
 	return &ListMessagesResponse{
 		Messages:       messages,
 		Total:          total,
 		Unread:         unread,
 		HiddenByFilter: hiddenByFilter,
+		PinnedCount:    pinnedCount,
 		Page:           page,
 		PageSize:       pageSize,
 		TotalPages:     totalPages,
+		NextCursor:     nextCursor,
 	}, nil
 }
 
@@ -1543,7 +2333,7 @@ func (h *MessageHandler) HandleOutbox(ctx context.Context, params json.RawMessag
 	rowsQuery := `SELECT m.message_id, m.thread_id, m.agent_id, m.created_at, m.updated_at,
 	                     m.body_format, m.body_content, m.body_structured, m.deleted,
 	                     0 as is_read,
-	                     reply_ref.ref_value as reply_to` +
+	                     reply_ref.ref_value as reply_to, m.deliver_at` +
 		fromClause +
 		whereClause +
 		`
@@ -1564,7 +2354,7 @@ func (h *MessageHandler) HandleOutbox(ctx context.Context, params json.RawMessag
 		var updatedAt sql.NullString
 		var bodyStructured sql.NullString
 		var deletedInt int
-		var replyTo sql.NullString
+		var replyTo, deliverAt sql.NullString
 		if err := rows.Scan(
 			&msg.MessageID,
 			&threadID,
@@ -1577,9 +2367,11 @@ func (h *MessageHandler) HandleOutbox(ctx context.Context, params json.RawMessag
 			&deletedInt,
 			&msg.IsRead,
 			&replyTo,
+			&deliverAt,
 		); err != nil {
 			return nil, fmt.Errorf("scan outbox message: %w", err)
 		}
+		msg.DeliverAt = deliverAt.String
 		if threadID.Valid {
 			msg.ThreadID = threadID.String
 		}
@@ -1687,32 +2479,329 @@ func (h *MessageHandler) HandleDelete(ctx context.Context, params json.RawMessag
 		return nil, fmt.Errorf("only message author can delete (author: %s, current: %s)", authorAgentID, agentID)
 	}
 
-	// Prepare timestamp
-	now := time.Now().UTC().Format(time.RFC3339Nano)
-
-	// Build message.delete event
-	event := types.MessageDeleteEvent{
-		Type:      "message.delete",
-		Timestamp: now,
+	// Prepare timestamp
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+
+	// Build message.delete event
+	event := types.MessageDeleteEvent{
+		Type:      "message.delete",
+		Timestamp: now,
+		MessageID: req.MessageID,
+		Reason:    req.Reason,
+	}
+
+	// Write event to JSONL and SQLite.
+	// thrum-bsn7: release state.Lock() before invoking postCommit
+	// (no-op for non-structural events; pattern uniform across handlers).
+	h.state.Lock()
+	postCommit, err := h.state.WriteEvent(ctx, event)
+	h.state.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("write message.delete event: %w", err)
+	}
+	h.state.GoPostCommit(postCommit)
+
+	return &DeleteMessageResponse{
+		MessageID: req.MessageID,
+		DeletedAt: now,
+	}, nil
+}
+
+// HandleCancel handles the message.cancel RPC method. It withdraws a
+// scheduled message (send --schedule) before its deliver_at by soft-deleting
+// it with reason "canceled"; the message scheduler skips deleted messages,
+// so no notification is ever sent. Only the author can cancel.
+func (h *MessageHandler) HandleCancel(ctx context.Context, params json.RawMessage) (any, error) {
+	var req CancelMessageRequest
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	if req.MessageID == "" {
+		return nil, fmt.Errorf("message_id is required")
+	}
+
+	agentID, _, err := h.resolveAgentAndSession(ctx, req.CallerAgentID)
+	if err != nil {
+		return nil, fmt.Errorf("resolve agent and session: %w", err)
+	}
+
+	// Checked and written under one lock so the message can't be canceled
+	// twice or deleted in between.
+	h.state.Lock()
+	var authorAgentID string
+	var deleted int
+	var deliverAt sql.NullString
+	err = h.state.DB().QueryRowContext(ctx,
+		`SELECT agent_id, deleted, deliver_at FROM messages WHERE message_id = ?`, req.MessageID).
+		Scan(&authorAgentID, &deleted, &deliverAt)
+	if err == sql.ErrNoRows {
+		h.state.Unlock()
+		return nil, fmt.Errorf("message not found: %s", req.MessageID)
+	}
+	if err != nil {
+		h.state.Unlock()
+		return nil, fmt.Errorf("query message: %w", err)
+	}
+	now := time.Now().UTC()
+	switch {
+	case deleted == 1:
+		err = fmt.Errorf("message already deleted: %s", req.MessageID)
+	case authorAgentID != agentID:
+		err = fmt.Errorf("only message author can cancel (author: %s, current: %s)", authorAgentID, agentID)
+	case !deliverAt.Valid:
+		err = fmt.Errorf("message %s is not scheduled (send --schedule)", req.MessageID)
+	case deliverAt.String <= now.Format(time.RFC3339):
+		err = fmt.Errorf("message %s was already delivered at %s", req.MessageID, deliverAt.String)
+	}
+	if err != nil {
+		h.state.Unlock()
+		return nil, err
+	}
+
+	canceledAt := now.Format(time.RFC3339Nano)
+	postCommit, err := h.state.WriteEvent(ctx, types.MessageDeleteEvent{
+		Type:      "message.delete",
+		Timestamp: canceledAt,
+		MessageID: req.MessageID,
+		Reason:    "canceled",
+	})
+	h.state.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("write message.delete event: %w", err)
+	}
+	h.state.GoPostCommit(postCommit)
+
+	return &CancelMessageResponse{
+		MessageID:  req.MessageID,
+		DeliverAt:  deliverAt.String,
+		CanceledAt: canceledAt,
+	}, nil
+}
+
+// ReleaseScheduled runs the subscription dispatch HandleSend skipped for a
+// scheduled message (send --schedule) now that the message scheduler found
+// it due. Like NotifyMessageCreate, only messages this daemon authored are
+// dispatched: HandleSend never dispatches peer-synced messages either.
+func (h *MessageHandler) ReleaseScheduled(ctx context.Context, evt types.MessageCreateEvent) {
+	if evt.OriginDaemon != "" && evt.OriginDaemon != h.state.DaemonID() {
+		return
+	}
+	preview := evt.Body.Content
+	if len(preview) > 100 {
+		preview = preview[:100]
+	}
+	_, _ = h.dispatcher.DispatchForMessage(ctx, &subscriptions.MessageInfo{
+		MessageID: evt.MessageID,
+		ThreadID:  evt.ThreadID,
+		AgentID:   evt.AgentID,
+		SessionID: evt.SessionID,
+		Scopes:    evt.Scopes,
+		Refs:      evt.Refs,
+		Timestamp: evt.DeliverAt,
+		Preview:   preview,
+	})
+}
+
+// HandleReact handles the message.react RPC method. Reacting with an emoji
+// the caller already left on the message removes it. Reactions never touch
+// read or delivery state.
+func (h *MessageHandler) HandleReact(ctx context.Context, params json.RawMessage) (any, error) {
+	var req ReactRequest
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	if req.MessageID == "" {
+		return nil, fmt.Errorf("message_id is required")
+	}
+	req.Emoji = strings.TrimSpace(req.Emoji)
+	if req.Emoji == "" {
+		return nil, fmt.Errorf("emoji is required")
+	}
+	if len(req.Emoji) > maxEmojiLen || strings.ContainsAny(req.Emoji, " \t\r\n") {
+		return nil, fmt.Errorf("invalid emoji %q: expected a :shortcode: or a single emoji", req.Emoji)
+	}
+
+	agentID, _, err := h.resolveAgentAndSession(ctx, req.CallerAgentID)
+	if err != nil {
+		return nil, fmt.Errorf("resolve agent and session: %w", err)
+	}
+
+	// The toggle is decided and written under one lock so two concurrent
+	// reacts from the same agent cannot both observe "absent".
+	h.state.Lock()
+	var deleted int
+	err = h.state.DB().QueryRowContext(ctx,
+		`SELECT deleted FROM messages WHERE message_id = ?`, req.MessageID).Scan(&deleted)
+	if err == sql.ErrNoRows {
+		h.state.Unlock()
+		return nil, fmt.Errorf("message not found: %s", req.MessageID)
+	}
+	if err != nil {
+		h.state.Unlock()
+		return nil, fmt.Errorf("query message: %w", err)
+	}
+	if deleted == 1 {
+		h.state.Unlock()
+		return nil, fmt.Errorf("message deleted: %s", req.MessageID)
+	}
+
+	var exists int
+	err = h.state.DB().QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM message_reactions WHERE message_id = ? AND agent_id = ? AND emoji = ?`,
+		req.MessageID, agentID, req.Emoji).Scan(&exists)
+	if err != nil {
+		h.state.Unlock()
+		return nil, fmt.Errorf("query reaction: %w", err)
+	}
+
+	event := types.MessageReactEvent{
+		Type:      "message.react",
+		Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
+		MessageID: req.MessageID,
+		AgentID:   agentID,
+		Emoji:     req.Emoji,
+		Removed:   exists > 0,
+	}
+	postCommit, err := h.state.WriteEvent(ctx, event)
+	if err != nil {
+		h.state.Unlock()
+		return nil, fmt.Errorf("write message.react event: %w", err)
+	}
+	reactions, err := h.loadReactions(ctx, req.MessageID)
+	h.state.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	h.state.GoPostCommit(postCommit)
+
+	if reactions == nil {
+		reactions = map[string][]string{}
+	}
+	return &ReactResponse{
+		MessageID: req.MessageID,
+		Emoji:     req.Emoji,
+		Removed:   event.Removed,
+		Reactions: reactions,
+	}, nil
+}
+
+// HandlePin handles the message.pin RPC method. Pins are shared by every
+// agent in the repo; pinning an already-pinned message is a no-op.
+func (h *MessageHandler) HandlePin(ctx context.Context, params json.RawMessage) (any, error) {
+	return h.setPinned(ctx, params, true)
+}
+
+// HandleUnpin handles the message.unpin RPC method. Unpinning a message that
+// isn't pinned is a no-op reported via Changed=false.
+func (h *MessageHandler) HandleUnpin(ctx context.Context, params json.RawMessage) (any, error) {
+	return h.setPinned(ctx, params, false)
+}
+
+func (h *MessageHandler) setPinned(ctx context.Context, params json.RawMessage, pin bool) (any, error) {
+	var req PinRequest
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	if req.MessageID == "" {
+		return nil, fmt.Errorf("message_id is required")
+	}
+
+	agentID, _, err := h.resolveAgentAndSession(ctx, req.CallerAgentID)
+	if err != nil {
+		return nil, fmt.Errorf("resolve agent and session: %w", err)
+	}
+
+	// Decide and write under one lock, as in HandleReact.
+	h.state.Lock()
+	var deleted, pinned int
+	err = h.state.DB().QueryRowContext(ctx,
+		`SELECT m.deleted, EXISTS (SELECT 1 FROM message_pins WHERE message_id = m.message_id)
+		 FROM messages m WHERE m.message_id = ?`, req.MessageID).Scan(&deleted, &pinned)
+	if err == sql.ErrNoRows {
+		h.state.Unlock()
+		return nil, fmt.Errorf("message not found: %s", req.MessageID)
+	}
+	if err != nil {
+		h.state.Unlock()
+		return nil, fmt.Errorf("query message: %w", err)
+	}
+	if pin && deleted == 1 {
+		h.state.Unlock()
+		return nil, fmt.Errorf("cannot pin deleted message: %s", req.MessageID)
+	}
+	if (pinned == 1) == pin {
+		h.state.Unlock()
+		return &PinResponse{MessageID: req.MessageID, Pinned: pin}, nil
+	}
+
+	event := types.MessagePinEvent{
+		Type:      "message.pin",
+		Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
 		MessageID: req.MessageID,
-		Reason:    req.Reason,
+		AgentID:   agentID,
+		Unpinned:  !pin,
 	}
-
-	// Write event to JSONL and SQLite.
-	// thrum-bsn7: release state.Lock() before invoking postCommit
-	// (no-op for non-structural events; pattern uniform across handlers).
-	h.state.Lock()
 	postCommit, err := h.state.WriteEvent(ctx, event)
 	h.state.Unlock()
 	if err != nil {
-		return nil, fmt.Errorf("write message.delete event: %w", err)
+		return nil, fmt.Errorf("write message.pin event: %w", err)
 	}
 	h.state.GoPostCommit(postCommit)
 
-	return &DeleteMessageResponse{
-		MessageID: req.MessageID,
-		DeletedAt: now,
-	}, nil
+	return &PinResponse{MessageID: req.MessageID, Pinned: pin, Changed: true}, nil
+}
+
+// loadReactions returns emoji → agent IDs for a message, or nil when it has
+// none. Agent lists are sorted. Callers must hold the state lock (read or
+// write).
+func (h *MessageHandler) loadReactions(ctx context.Context, messageID string) (map[string][]string, error) {
+	rows, err := h.state.DB().QueryContext(ctx,
+		`SELECT emoji, agent_id FROM message_reactions WHERE message_id = ? ORDER BY agent_id`, messageID)
+	if err != nil {
+		return nil, fmt.Errorf("query reactions: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var reactions map[string][]string
+	for rows.Next() {
+		var emoji, agent string
+		if err := rows.Scan(&emoji, &agent); err != nil {
+			return nil, fmt.Errorf("scan reaction: %w", err)
+		}
+		if reactions == nil {
+			reactions = make(map[string][]string)
+		}
+		reactions[emoji] = append(reactions[emoji], agent)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate reactions: %w", err)
+	}
+	return reactions, nil
+}
+
+// loadTags returns a message's tags in alphabetical order, or nil when it
+// has none.
+func (h *MessageHandler) loadTags(ctx context.Context, messageID string) ([]string, error) {
+	rows, err := h.state.DB().QueryContext(ctx,
+		`SELECT tag FROM message_tags WHERE message_id = ? ORDER BY tag`, messageID)
+	if err != nil {
+		return nil, fmt.Errorf("query tags: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var tags []string
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, fmt.Errorf("scan tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate tags: %w", err)
+	}
+	return tags, nil
 }
 
 // HandleEdit handles the message.edit RPC method.
@@ -1736,7 +2825,7 @@ func (h *MessageHandler) HandleEdit(ctx context.Context, params json.RawMessage)
 	// could send a small message and then edit it to an arbitrary
 	// size, bypassing the write-side cap. Same error surface
 	// (-32602 RPCError) so machine consumers handle both alike.
-	if err := h.checkBodySize(req.Content); err != nil {
+	if err := h.checkBodySize(req.Content, req.Structured); err != nil {
 		return nil, err
 	}
 
@@ -1927,8 +3016,9 @@ func buildForAgentClause(forAgentValues []string, forAgent, forAgentRole string)
 		args = append(args, v)
 	}
 
-	// Part 2: group membership subquery (flat groups only)
-	// Messages scoped to groups the agent belongs to (via agent name or role).
+	// Part 2: group membership subquery
+	// Messages scoped to groups the agent belongs to (via agent name or role),
+	// directly or through nested group members. UNION stops at cycles.
 	// Only include the wildcard role match ('*') when the caller has an explicit role —
 	// human users (no role) should not match role-wildcard groups like @everyone.
 	agentVal := forAgent
@@ -1950,10 +3040,17 @@ func buildForAgentClause(forAgentValues []string, forAgent, forAgentRole string)
 		SELECT ms_g.message_id FROM message_scopes ms_g
 		WHERE ms_g.scope_type = 'group'
 		AND ms_g.scope_value IN (
-			SELECT g.name FROM groups g
-			JOIN group_members gm ON g.group_id = gm.group_id
-			WHERE (gm.member_type = 'agent' AND gm.member_value = ?)
-			   OR ` + roleCondition + `
+			WITH RECURSIVE member_groups(name) AS (
+				SELECT g.name FROM groups g
+				JOIN group_members gm ON g.group_id = gm.group_id
+				WHERE (gm.member_type = 'agent' AND gm.member_value = ?)
+				   OR ` + roleCondition + `
+				UNION
+				SELECT g.name FROM groups g
+				JOIN group_members gm ON g.group_id = gm.group_id
+				JOIN member_groups mg ON gm.member_type = 'group' AND gm.member_value = mg.name
+			)
+			SELECT name FROM member_groups
 		)
 	)`
 	args = append(args, agentVal, roleVal)
@@ -1986,6 +3083,23 @@ func buildForAgentClause(forAgentValues []string, forAgent, forAgentRole string)
 	return clause, args
 }
 
+// visibilityClause returns the condition (alias m) hiding messages that
+// readers must not see yet or any more: scheduled messages (send --schedule)
+// before their deliver_at, and expired TTL messages (send --ttl) the cleanup
+// pass has not soft-deleted yet unless includeExpired is set. Every read path
+// (list, search, thread, get, team counts) uses it so they agree; the sender
+// sees pending scheduled messages in message.outbox.
+func visibilityClause(now time.Time, includeExpired bool) (string, []any) {
+	ts := now.UTC().Format(time.RFC3339)
+	clause := " AND (m.deliver_at IS NULL OR m.deliver_at <= ?)"
+	args := []any{ts}
+	if !includeExpired {
+		clause += " AND (m.expires_at IS NULL OR m.expires_at > ?)"
+		args = append(args, ts)
+	}
+	return clause, args
+}
+
 func buildMentionFilterClause(mentionRole string) (string, []any) {
 	if mentionRole == "" {
 		return "", nil
@@ -2094,6 +3208,30 @@ func isSupervisorRecipient(h *MessageHandler, recipient string) bool {
 	return false
 }
 
+// unseenByAllowedRoles are the agent roles permitted to list another agent's
+// unread backlog via message.list unseen_by.
+var unseenByAllowedRoles = map[string]bool{
+	"coordinator": true,
+}
+
+// checkUnseenByAllowed reports whether callerID may use the unseen_by filter.
+// user: callers are always allowed (they can already impersonate any agent);
+// agents must hold a role in unseenByAllowedRoles. An unknown caller or a DB
+// error refuses fail-closed. Lock-free like validateImpersonation — HandleList
+// calls it with state.RLock already held.
+func (h *MessageHandler) checkUnseenByAllowed(ctx context.Context, callerID string) error {
+	if strings.HasPrefix(callerID, "user:") {
+		return nil
+	}
+	var role string
+	err := h.state.DB().QueryRowContext(ctx,
+		`SELECT role FROM agents WHERE agent_id = ?`, callerID).Scan(&role)
+	if err != nil || !unseenByAllowedRoles[role] {
+		return fmt.Errorf("unseen_by is restricted to coordinator roles (caller %q, role %q)", callerID, role)
+	}
+	return nil
+}
+
 // queryAgentByID checks if an agent with the exact agent_id exists.
 // Unlike queryAgentsByRecipient, this does NOT fall back to role matching.
 func (h *MessageHandler) queryAgentByID(ctx context.Context, agentID string) (bool, error) {
@@ -2222,6 +3360,83 @@ func normalizeAudienceTarget(target string) string {
 	return strings.ToLower(target)
 }
 
+// tagRegex matches a valid message tag: lowercase alphanumerics and dashes.
+var tagRegex = regexp.MustCompile(`^[a-z0-9-]{1,64}$`)
+
+// normalizeTags trims tags, drops empties and duplicates (keeping the
+// caller's order), and rejects any tag that doesn't match tagRegex.
+func normalizeTags(tags []string) ([]string, error) {
+	var out []string
+	seen := make(map[string]struct{}, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		if !tagRegex.MatchString(tag) {
+			return nil, fmt.Errorf("invalid tag %q: must be lowercase letters, digits, or dashes (1-64 chars)", tag)
+		}
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+		out = append(out, tag)
+	}
+	return out, nil
+}
+
+// normalizePriority validates a message priority and returns its stored
+// form: "low" or "high", or "" for normal (the column default).
+func normalizePriority(priority string) (string, error) {
+	switch p := strings.ToLower(strings.TrimSpace(priority)); p {
+	case "", "normal":
+		return "", nil
+	case "low", "high":
+		return p, nil
+	default:
+		return "", fmt.Errorf("invalid priority %q (must be low, normal, or high)", priority)
+	}
+}
+
+// parseTTL parses a message.send ttl. Empty means no expiry.
+func parseTTL(ttl string) (time.Duration, error) {
+	if ttl == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(ttl)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid ttl %q: must be a positive duration like 30m or 2h", ttl)
+	}
+	return d, nil
+}
+
+// parseSchedule parses a message.send schedule: an RFC 3339 time or
+// "+DURATION" from now. Empty means deliver right away; a time that is not
+// in the future is an error.
+func parseSchedule(schedule string, now time.Time) (time.Time, error) {
+	if schedule == "" {
+		return time.Time{}, nil
+	}
+	var at time.Time
+	if rest, ok := strings.CutPrefix(schedule, "+"); ok {
+		d, err := time.ParseDuration(rest)
+		if err != nil || d <= 0 {
+			return time.Time{}, fmt.Errorf("invalid schedule %q: must be an RFC 3339 time or +DURATION like +30m", schedule)
+		}
+		at = now.Add(d)
+	} else {
+		t, err := time.Parse(time.RFC3339, schedule)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid schedule %q: must be an RFC 3339 time or +DURATION like +30m", schedule)
+		}
+		if !t.After(now) {
+			return time.Time{}, fmt.Errorf("invalid schedule %q: time is not in the future", schedule)
+		}
+		at = t
+	}
+	return at.UTC().Truncate(time.Second), nil
+}
+
 func totalPages(total, pageSize int) int {
 	if pageSize <= 0 {
 		return 0
@@ -2949,68 +4164,13 @@ func (h *MessageHandler) HandleArchive(ctx context.Context, params json.RawMessa
 	}
 
 	// Build full archive records (message body + scopes + refs) under the same read lock
-	records := make([]archiveRecord, 0, len(messageIDs))
+	records := make([]ArchiveRecord, 0, len(messageIDs))
 	for _, msgID := range messageIDs {
-		var rec archiveRecord
-		rec.MessageID = msgID
-
-		err := h.state.DB().QueryRowContext(ctx,
-			`SELECT agent_id, created_at, body_format, body_content FROM messages WHERE message_id = ?`,
-			msgID,
-		).Scan(&rec.AgentID, &rec.CreatedAt, &rec.Body.Format, &rec.Body.Content)
-		if err != nil {
-			h.state.RUnlock()
-			return nil, fmt.Errorf("query message %s: %w", msgID, err)
-		}
-
-		// Scopes
-		scopeRows, err := h.state.DB().QueryContext(ctx,
-			`SELECT scope_type, scope_value FROM message_scopes WHERE message_id = ?`, msgID)
-		if err != nil {
-			h.state.RUnlock()
-			return nil, fmt.Errorf("query scopes for %s: %w", msgID, err)
-		}
-		rec.Scopes = []types.Scope{}
-		for scopeRows.Next() {
-			var s types.Scope
-			if err := scopeRows.Scan(&s.Type, &s.Value); err != nil {
-				_ = scopeRows.Close()
-				h.state.RUnlock()
-				return nil, fmt.Errorf("scan scope: %w", err)
-			}
-			rec.Scopes = append(rec.Scopes, s)
-		}
-		if err := scopeRows.Err(); err != nil {
-			_ = scopeRows.Close()
-			h.state.RUnlock()
-			return nil, fmt.Errorf("iterate scopes: %w", err)
-		}
-		_ = scopeRows.Close()
-
-		// Refs
-		refRows, err := h.state.DB().QueryContext(ctx,
-			`SELECT ref_type, ref_value FROM message_refs WHERE message_id = ?`, msgID)
+		rec, err := h.loadArchiveRecord(ctx, msgID)
 		if err != nil {
 			h.state.RUnlock()
-			return nil, fmt.Errorf("query refs for %s: %w", msgID, err)
-		}
-		rec.Refs = []types.Ref{}
-		for refRows.Next() {
-			var r types.Ref
-			if err := refRows.Scan(&r.Type, &r.Value); err != nil {
-				_ = refRows.Close()
-				h.state.RUnlock()
-				return nil, fmt.Errorf("scan ref: %w", err)
-			}
-			rec.Refs = append(rec.Refs, r)
-		}
-		if err := refRows.Err(); err != nil {
-			_ = refRows.Close()
-			h.state.RUnlock()
-			return nil, fmt.Errorf("iterate refs: %w", err)
+			return nil, err
 		}
-		_ = refRows.Close()
-
 		records = append(records, rec)
 	}
 
@@ -3083,6 +4243,22 @@ func (h *MessageHandler) HandleArchive(ctx context.Context, params json.RawMessa
 			_ = tx.Rollback()
 			return nil, fmt.Errorf("delete edits for %s: %w", msgID, err)
 		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM message_reactions WHERE message_id = ?`, msgID); err != nil {
+			_ = tx.Rollback()
+			return nil, fmt.Errorf("delete reactions for %s: %w", msgID, err)
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM message_pins WHERE message_id = ?`, msgID); err != nil {
+			_ = tx.Rollback()
+			return nil, fmt.Errorf("delete pin for %s: %w", msgID, err)
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM message_assignments WHERE message_id = ?`, msgID); err != nil {
+			_ = tx.Rollback()
+			return nil, fmt.Errorf("delete assignments for %s: %w", msgID, err)
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM message_tags WHERE message_id = ?`, msgID); err != nil {
+			_ = tx.Rollback()
+			return nil, fmt.Errorf("delete tags for %s: %w", msgID, err)
+		}
 		if _, err := tx.ExecContext(ctx, `DELETE FROM messages WHERE message_id = ?`, msgID); err != nil {
 			_ = tx.Rollback()
 			return nil, fmt.Errorf("delete message %s: %w", msgID, err)
@@ -3099,6 +4275,299 @@ func (h *MessageHandler) HandleArchive(ctx context.Context, params json.RawMessa
 	}, nil
 }
 
+// loadArchiveRecord reads one message with its scopes and refs.
+// Caller must hold the state read lock.
+func (h *MessageHandler) loadArchiveRecord(ctx context.Context, msgID string) (ArchiveRecord, error) {
+	rec := ArchiveRecord{MessageID: msgID}
+
+	var threadID, deletedAt sql.NullString
+	var deleted int
+	err := h.state.DB().QueryRowContext(ctx,
+		`SELECT thread_id, agent_id, created_at, body_format, body_content, deleted, deleted_at
+		 FROM messages WHERE message_id = ?`,
+		msgID,
+	).Scan(&threadID, &rec.AgentID, &rec.CreatedAt, &rec.Body.Format, &rec.Body.Content, &deleted, &deletedAt)
+	if err != nil {
+		return rec, fmt.Errorf("query message %s: %w", msgID, err)
+	}
+	rec.ThreadID = threadID.String
+	rec.Deleted = deleted != 0
+	rec.DeletedAt = deletedAt.String
+
+	// Scopes
+	scopeRows, err := h.state.DB().QueryContext(ctx,
+		`SELECT scope_type, scope_value FROM message_scopes WHERE message_id = ?`, msgID)
+	if err != nil {
+		return rec, fmt.Errorf("query scopes for %s: %w", msgID, err)
+	}
+	defer func() { _ = scopeRows.Close() }()
+	rec.Scopes = []types.Scope{}
+	for scopeRows.Next() {
+		var s types.Scope
+		if err := scopeRows.Scan(&s.Type, &s.Value); err != nil {
+			return rec, fmt.Errorf("scan scope: %w", err)
+		}
+		rec.Scopes = append(rec.Scopes, s)
+	}
+	if err := scopeRows.Err(); err != nil {
+		return rec, fmt.Errorf("iterate scopes: %w", err)
+	}
+
+	// Refs
+	refRows, err := h.state.DB().QueryContext(ctx,
+		`SELECT ref_type, ref_value FROM message_refs WHERE message_id = ?`, msgID)
+	if err != nil {
+		return rec, fmt.Errorf("query refs for %s: %w", msgID, err)
+	}
+	defer func() { _ = refRows.Close() }()
+	rec.Refs = []types.Ref{}
+	for refRows.Next() {
+		var r types.Ref
+		if err := refRows.Scan(&r.Type, &r.Value); err != nil {
+			return rec, fmt.Errorf("scan ref: %w", err)
+		}
+		rec.Refs = append(rec.Refs, r)
+	}
+	if err := refRows.Err(); err != nil {
+		return rec, fmt.Errorf("iterate refs: %w", err)
+	}
+
+	return rec, nil
+}
+
+// HandleExport handles the message.export RPC method.
+// It returns every message (oldest first) in the message.archive record
+// format, plus the threads those messages belong to. Unlike message.archive
+// it writes nothing and deletes nothing; the caller decides where the
+// archive goes. Deleted messages are left out unless IncludeDeleted is set.
+func (h *MessageHandler) HandleExport(ctx context.Context, params json.RawMessage) (any, error) {
+	var req ExportRequest
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &req); err != nil {
+			return nil, fmt.Errorf("invalid request: %w", err)
+		}
+	}
+
+	query := `SELECT message_id FROM messages WHERE 1=1`
+	var args []any
+	if !req.IncludeDeleted {
+		query += ` AND deleted = 0`
+	}
+	if req.Since != "" {
+		since, err := time.Parse(time.RFC3339Nano, req.Since)
+		if err != nil {
+			return nil, fmt.Errorf("invalid since %q: %w", req.Since, err)
+		}
+		query += ` AND created_at >= ?`
+		args = append(args, since.UTC().Format(time.RFC3339Nano))
+	}
+	query += ` ORDER BY created_at ASC, message_id ASC`
+
+	h.state.RLock()
+	defer h.state.RUnlock()
+
+	rows, err := h.state.DB().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query messages: %w", err)
+	}
+	var messageIDs []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("scan message_id: %w", err)
+		}
+		messageIDs = append(messageIDs, id)
+	}
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		return nil, fmt.Errorf("iterate messages: %w", err)
+	}
+	_ = rows.Close()
+
+	resp := &ExportResponse{
+		Records: make([]ArchiveRecord, 0, len(messageIDs)),
+		Threads: []ExportThread{},
+	}
+	seenThreads := make(map[string]bool)
+	for _, msgID := range messageIDs {
+		rec, err := h.loadArchiveRecord(ctx, msgID)
+		if err != nil {
+			return nil, err
+		}
+		resp.Records = append(resp.Records, rec)
+
+		if rec.ThreadID == "" || seenThreads[rec.ThreadID] {
+			continue
+		}
+		seenThreads[rec.ThreadID] = true
+		thread := ExportThread{ThreadID: rec.ThreadID}
+		var title sql.NullString
+		err = h.state.DB().QueryRowContext(ctx,
+			`SELECT title, created_at, created_by FROM threads WHERE thread_id = ?`, rec.ThreadID,
+		).Scan(&title, &thread.CreatedAt, &thread.CreatedBy)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			// Replies can name a thread whose thread.create never reached
+			// this daemon; fall back to the first message we have in it.
+			thread.CreatedAt = rec.CreatedAt
+			thread.CreatedBy = rec.AgentID
+		case err != nil:
+			return nil, fmt.Errorf("query thread %s: %w", rec.ThreadID, err)
+		}
+		thread.Title = title.String
+		resp.Threads = append(resp.Threads, thread)
+	}
+
+	return resp, nil
+}
+
+// HandleImport handles the message.import RPC method.
+// Replays exported archive records as message.create events, preserving
+// message IDs, timestamps, authors, scopes and refs. Records whose message_id
+// already exists are skipped unless Force is set, in which case the existing
+// row and its related rows are hard-deleted before the create is replayed
+// (the projector's INSERT OR IGNORE would otherwise keep the old row).
+// Imported messages carry no recipient snapshot, so they reach inboxes via
+// their scopes only. Refs to messages missing from both the archive and the
+// repo are kept and reported in DanglingRefs.
+func (h *MessageHandler) HandleImport(ctx context.Context, params json.RawMessage) (any, error) {
+	var req ImportRequest
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	// Validate every record before writing anything so a malformed file
+	// does not leave a half-imported repo behind.
+	for i, rec := range req.Records {
+		if rec.MessageID == "" || rec.AgentID == "" || rec.CreatedAt == "" {
+			return nil, fmt.Errorf("record %d: message_id, agent_id and created_at are required", i+1)
+		}
+		if _, err := time.Parse(time.RFC3339Nano, rec.CreatedAt); err != nil {
+			return nil, fmt.Errorf("record %d (%s): invalid created_at %q: %w", i+1, rec.MessageID, rec.CreatedAt, err)
+		}
+	}
+
+	inArchive := make(map[string]bool, len(req.Records))
+	for _, rec := range req.Records {
+		if !rec.Deleted {
+			inArchive[rec.MessageID] = true
+		}
+	}
+
+	resp := &ImportResponse{}
+	var written []ArchiveRecord
+	for _, rec := range req.Records {
+		// Tombstones from `thrum export --include-deleted` stay deleted.
+		if rec.Deleted {
+			resp.TombstoneCount++
+			continue
+		}
+		h.state.Lock()
+		var count int
+		if err := h.state.DB().QueryRowContext(ctx,
+			`SELECT COUNT(*) FROM messages WHERE message_id = ?`, rec.MessageID,
+		).Scan(&count); err != nil {
+			h.state.Unlock()
+			return nil, fmt.Errorf("check message %s: %w", rec.MessageID, err)
+		}
+		if count > 0 {
+			if !req.Force {
+				h.state.Unlock()
+				resp.SkippedCount++
+				continue
+			}
+			if err := h.hardDeleteMessage(ctx, rec.MessageID); err != nil {
+				h.state.Unlock()
+				return nil, err
+			}
+			resp.OverwrittenCount++
+		}
+
+		format := rec.Body.Format
+		if format == "" {
+			format = "markdown"
+		}
+		event := types.MessageCreateEvent{
+			Type:      "message.create",
+			Timestamp: rec.CreatedAt,
+			MessageID: rec.MessageID,
+			ThreadID:  rec.ThreadID,
+			AgentID:   rec.AgentID,
+			SessionID: importSessionID,
+			Body: types.MessageBody{
+				Format:  format,
+				Content: rec.Body.Content,
+			},
+			Scopes: rec.Scopes,
+			Refs:   rec.Refs,
+		}
+		postCommit, err := h.state.WriteEvent(ctx, event)
+		h.state.Unlock()
+		if err != nil {
+			return nil, fmt.Errorf("write event for %s: %w", rec.MessageID, err)
+		}
+		h.state.GoPostCommit(postCommit)
+		resp.ImportedCount++
+		written = append(written, rec)
+	}
+
+	dangling, err := h.findDanglingRefs(ctx, written, inArchive)
+	if err != nil {
+		return nil, err
+	}
+	resp.DanglingRefs = dangling
+
+	return resp, nil
+}
+
+// findDanglingRefs returns the message-ID refs on records whose target is
+// neither in the archive nor in the messages table.
+func (h *MessageHandler) findDanglingRefs(ctx context.Context, records []ArchiveRecord, inArchive map[string]bool) ([]DanglingRef, error) {
+	h.state.RLock()
+	defer h.state.RUnlock()
+
+	var dangling []DanglingRef
+	for _, rec := range records {
+		for _, ref := range rec.Refs {
+			if !messageIDRefTypes[ref.Type] || inArchive[ref.Value] {
+				continue
+			}
+			var count int
+			if err := h.state.DB().QueryRowContext(ctx,
+				`SELECT COUNT(*) FROM messages WHERE message_id = ?`, ref.Value,
+			).Scan(&count); err != nil {
+				return nil, fmt.Errorf("check ref target %s: %w", ref.Value, err)
+			}
+			if count == 0 {
+				dangling = append(dangling, DanglingRef{MessageID: rec.MessageID, RefType: ref.Type, RefValue: ref.Value})
+			}
+		}
+	}
+	return dangling, nil
+}
+
+// hardDeleteMessage removes a message and its related rows in one
+// transaction. Caller must hold the state write lock.
+func (h *MessageHandler) hardDeleteMessage(ctx context.Context, msgID string) error {
+	tx, err := h.state.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, table := range schema.MessageChildTables {
+		if _, err := tx.ExecContext(ctx,
+			fmt.Sprintf("DELETE FROM %s WHERE message_id = ?", table), msgID); err != nil {
+			return fmt.Errorf("delete from %s for %s: %w", table, msgID, err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM messages WHERE message_id = ?`, msgID); err != nil {
+		return fmt.Errorf("delete from messages for %s: %w", msgID, err)
+	}
+	return tx.Commit()
+}
+
 // DeleteByScopeRequest represents the request for message.deleteByScope RPC.
 type DeleteByScopeRequest struct {
 	ScopeType  string `json:"scope_type"`  // e.g., "group"
@@ -3176,7 +4645,7 @@ func (h *MessageHandler) HandleDeleteByScope(ctx context.Context, params json.Ra
 	inClause := strings.Join(placeholders, ",")
 
 	// Delete from related tables first
-	for _, table := range []string{"message_edits", "message_reads", "message_deliveries", "message_refs", "message_scopes"} {
+	for _, table := range schema.MessageChildTables {
 		_, err = h.state.DB().ExecContext(ctx,
 			fmt.Sprintf("DELETE FROM %s WHERE message_id IN (%s)", table, inClause),
 			args...)
@@ -3250,6 +4719,30 @@ func (h *MessageHandler) HandleDeleteByAgent(ctx context.Context, params json.Ra
 
 	// Hard delete all messages by this agent.
 	// Delete from child tables first to avoid FK constraint issues.
+	_, err = h.state.DB().ExecContext(ctx,
+		"DELETE FROM message_reactions WHERE message_id IN (SELECT message_id FROM messages WHERE agent_id = ?)", req.AgentID)
+	if err != nil {
+		return nil, fmt.Errorf("delete message reactions: %w", err)
+	}
+
+	_, err = h.state.DB().ExecContext(ctx,
+		"DELETE FROM message_pins WHERE message_id IN (SELECT message_id FROM messages WHERE agent_id = ?)", req.AgentID)
+	if err != nil {
+		return nil, fmt.Errorf("delete message pins: %w", err)
+	}
+
+	_, err = h.state.DB().ExecContext(ctx,
+		"DELETE FROM message_assignments WHERE message_id IN (SELECT message_id FROM messages WHERE agent_id = ?)", req.AgentID)
+	if err != nil {
+		return nil, fmt.Errorf("delete message assignments: %w", err)
+	}
+
+	_, err = h.state.DB().ExecContext(ctx,
+		"DELETE FROM message_tags WHERE message_id IN (SELECT message_id FROM messages WHERE agent_id = ?)", req.AgentID)
+	if err != nil {
+		return nil, fmt.Errorf("delete message tags: %w", err)
+	}
+
 	_, err = h.state.DB().ExecContext(ctx,
 		"DELETE FROM message_edits WHERE message_id IN (SELECT message_id FROM messages WHERE agent_id = ?)", req.AgentID)
 	if err != nil {
@@ -2,6 +2,7 @@ package rpc
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
 	"os"
@@ -436,3 +437,165 @@ func readJSONLLines(t *testing.T, path string) []map[string]any {
 	}
 	return lines
 }
+
+// TestImportArchivedMessages verifies that an archive round-trips through
+// message.import with IDs, timestamps, authors and scopes preserved, and that
+// existing IDs are skipped unless force is set.
+func TestImportArchivedMessages(t *testing.T) {
+	handler, st, agentID, cleanup := setupArchiveTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	scopes := []types.Scope{{Type: "group", Value: "backend"}}
+	msgID := sendArchiveTestMessage(t, handler, "Archived then imported", scopes, agentID)
+
+	var createdAt string
+	if err := st.DB().QueryRowContext(ctx,
+		`SELECT created_at FROM messages WHERE message_id = ?`, msgID,
+	).Scan(&createdAt); err != nil {
+		t.Fatalf("query created_at: %v", err)
+	}
+
+	archiveParams, _ := json.Marshal(ArchiveRequest{ArchiveType: "agent", Identifier: agentID})
+	result, err := handler.HandleArchive(ctx, archiveParams)
+	if err != nil {
+		t.Fatalf("HandleArchive: %v", err)
+	}
+	archivePath := result.(*ArchiveResponse).ArchivePath
+
+	data, err := os.ReadFile(archivePath) // #nosec G304 -- test path
+	if err != nil {
+		t.Fatalf("read archive: %v", err)
+	}
+	var rec ArchiveRecord
+	if err := json.Unmarshal(bytes.TrimSpace(data), &rec); err != nil {
+		t.Fatalf("parse archive record: %v", err)
+	}
+
+	importParams, _ := json.Marshal(ImportRequest{Records: []ArchiveRecord{rec}})
+	result, err = handler.HandleImport(ctx, importParams)
+	if err != nil {
+		t.Fatalf("HandleImport: %v", err)
+	}
+	if resp := result.(*ImportResponse); resp.ImportedCount != 1 || resp.SkippedCount != 0 {
+		t.Fatalf("first import = %+v, want 1 imported, 0 skipped", resp)
+	}
+
+	var gotAgent, gotCreated, gotContent, gotScope string
+	if err := st.DB().QueryRowContext(ctx,
+		`SELECT m.agent_id, m.created_at, m.body_content, ms.scope_value
+		 FROM messages m JOIN message_scopes ms ON m.message_id = ms.message_id
+		 WHERE m.message_id = ?`, msgID,
+	).Scan(&gotAgent, &gotCreated, &gotContent, &gotScope); err != nil {
+		t.Fatalf("query imported message: %v", err)
+	}
+	if gotAgent != agentID || gotCreated != createdAt || gotContent != "Archived then imported" || gotScope != "backend" {
+		t.Errorf("imported row = (%q, %q, %q, %q), want (%q, %q, %q, %q)",
+			gotAgent, gotCreated, gotContent, gotScope, agentID, createdAt, "Archived then imported", "backend")
+	}
+
+	// Re-import without force skips the existing ID.
+	result, err = handler.HandleImport(ctx, importParams)
+	if err != nil {
+		t.Fatalf("HandleImport (repeat): %v", err)
+	}
+	if resp := result.(*ImportResponse); resp.ImportedCount != 0 || resp.SkippedCount != 1 {
+		t.Errorf("repeat import = %+v, want 0 imported, 1 skipped", resp)
+	}
+
+	// With force, the existing row is replaced by the record's content.
+	rec.Body.Content = "Overwritten body"
+	forceParams, _ := json.Marshal(ImportRequest{Records: []ArchiveRecord{rec}, Force: true})
+	result, err = handler.HandleImport(ctx, forceParams)
+	if err != nil {
+		t.Fatalf("HandleImport (force): %v", err)
+	}
+	if resp := result.(*ImportResponse); resp.ImportedCount != 1 || resp.OverwrittenCount != 1 {
+		t.Errorf("force import = %+v, want 1 imported, 1 overwritten", resp)
+	}
+	if err := st.DB().QueryRowContext(ctx,
+		`SELECT body_content FROM messages WHERE message_id = ?`, msgID,
+	).Scan(&gotContent); err != nil {
+		t.Fatalf("query overwritten message: %v", err)
+	}
+	if gotContent != "Overwritten body" {
+		t.Errorf("body after force import = %q, want %q", gotContent, "Overwritten body")
+	}
+}
+
+// TestImportRejectsInvalidRecord verifies that a malformed record fails the
+// whole import before anything is written.
+func TestImportRejectsInvalidRecord(t *testing.T) {
+	handler, st, agentID, cleanup := setupArchiveTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	params, _ := json.Marshal(ImportRequest{Records: []ArchiveRecord{
+		{MessageID: "msg_IMPORT_OK", AgentID: agentID, CreatedAt: "2026-01-02T03:04:05Z", Body: ArchiveBody{Format: "markdown", Content: "ok"}},
+		{MessageID: "msg_IMPORT_BAD", AgentID: agentID, CreatedAt: "yesterday"},
+	}})
+	if _, err := handler.HandleImport(ctx, params); err == nil {
+		t.Fatal("HandleImport: expected error for invalid created_at")
+	}
+
+	var count int
+	if err := st.DB().QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM messages WHERE message_id LIKE 'msg_IMPORT_%'`,
+	).Scan(&count); err != nil {
+		t.Fatalf("count messages: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("expected no messages written, got %d", count)
+	}
+}
+
+// TestImportReportsDanglingRefs verifies that reply_to/forwarded_from refs
+// whose target is in neither the archive nor the repo are imported unchanged
+// and reported, while refs to archived or existing messages are not.
+func TestImportReportsDanglingRefs(t *testing.T) {
+	handler, st, agentID, cleanup := setupArchiveTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	existing := sendArchiveTestMessage(t, handler, "already here", nil, agentID)
+	record := func(id string, refs ...types.Ref) ArchiveRecord {
+		return ArchiveRecord{MessageID: id, AgentID: agentID, CreatedAt: "2026-01-02T03:04:05Z",
+			Body: ArchiveBody{Format: "markdown", Content: id}, Refs: refs}
+	}
+	params, _ := json.Marshal(ImportRequest{Records: []ArchiveRecord{
+		record("msg_IMPORT_ROOT"),
+		record("msg_IMPORT_REPLY", types.Ref{Type: "reply_to", Value: "msg_IMPORT_ROOT"}),
+		record("msg_IMPORT_OLD", types.Ref{Type: "reply_to", Value: existing}),
+		record("msg_IMPORT_LOST",
+			types.Ref{Type: "forwarded_from", Value: "msg_GONE"},
+			types.Ref{Type: "mention", Value: "reviewer"}),
+	}})
+	result, err := handler.HandleImport(ctx, params)
+	if err != nil {
+		t.Fatalf("HandleImport: %v", err)
+	}
+	resp := result.(*ImportResponse)
+	if resp.ImportedCount != 4 {
+		t.Errorf("ImportedCount = %d, want 4", resp.ImportedCount)
+	}
+	want := []DanglingRef{{MessageID: "msg_IMPORT_LOST", RefType: "forwarded_from", RefValue: "msg_GONE"}}
+	if len(resp.DanglingRefs) != 1 || resp.DanglingRefs[0] != want[0] {
+		t.Errorf("DanglingRefs = %+v, want %+v", resp.DanglingRefs, want)
+	}
+
+	var refValue string
+	if err := st.DB().QueryRowContext(ctx,
+		`SELECT ref_value FROM message_refs WHERE message_id = 'msg_IMPORT_LOST' AND ref_type = 'forwarded_from'`,
+	).Scan(&refValue); err != nil {
+		t.Fatalf("dangling ref not kept: %v", err)
+	}
+
+	// Re-importing writes nothing, so there is nothing new to report.
+	result, err = handler.HandleImport(ctx, params)
+	if err != nil {
+		t.Fatalf("HandleImport (repeat): %v", err)
+	}
+	if resp := result.(*ImportResponse); resp.SkippedCount != 4 || len(resp.DanglingRefs) != 0 {
+		t.Errorf("repeat import = %+v, want 4 skipped and no dangling refs", resp)
+	}
+}
@@ -0,0 +1,270 @@
+package rpc
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/leonletto/thrum/internal/identity"
+	"github.com/leonletto/thrum/internal/types"
+)
+
+// AssignRequest represents the request for message.assign RPC.
+type AssignRequest struct {
+	MessageID     string `json:"message_id"`
+	Assignee      string `json:"assignee"` // agent ID or alias, optional leading @
+	CallerAgentID string `json:"caller_agent_id,omitempty"`
+}
+
+// AssignResponse represents the response from message.assign RPC.
+type AssignResponse struct {
+	MessageID        string `json:"message_id"`
+	AssignmentID     string `json:"assignment_id"`
+	Assignee         string `json:"assignee"`
+	PreviousAssignee string `json:"previous_assignee,omitempty"` // set when this reassigned an open task
+	Changed          bool   `json:"changed"`                     // false when already assigned to the assignee
+}
+
+// CompleteRequest represents the request for message.complete RPC.
+type CompleteRequest struct {
+	MessageID     string `json:"message_id"`
+	CallerAgentID string `json:"caller_agent_id,omitempty"`
+}
+
+// CompleteResponse represents the response from message.complete RPC.
+type CompleteResponse struct {
+	MessageID      string `json:"message_id"`
+	AssignmentID   string `json:"assignment_id"`
+	AssignedBy     string `json:"assigned_by"`
+	NotificationID string `json:"notification_id,omitempty"` // @system message sent to the assigner
+}
+
+// openAssignment is the open row of message_assignments for one message.
+type openAssignment struct {
+	id, assignee, assignedBy string
+}
+
+// HandleAssign handles the message.assign RPC method. It turns a message into
+// a task for an agent. Assigning a message that already has an open task
+// reassigns it; the earlier assignment is kept as history.
+func (h *MessageHandler) HandleAssign(ctx context.Context, params json.RawMessage) (any, error) {
+	var req AssignRequest
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	req.MessageID = strings.TrimSpace(req.MessageID)
+	if req.MessageID == "" {
+		return nil, fmt.Errorf("message_id is required")
+	}
+	assigneeName := strings.TrimPrefix(strings.TrimSpace(req.Assignee), "@")
+	if assigneeName == "" {
+		return nil, fmt.Errorf("assignee is required")
+	}
+
+	agentID, _, err := h.resolveAgentAndSession(ctx, req.CallerAgentID)
+	if err != nil {
+		return nil, fmt.Errorf("resolve agent and session: %w", err)
+	}
+
+	h.state.Lock()
+	assignee, err := identity.ResolveAlias(ctx, h.state.DB(), assigneeName)
+	if err != nil {
+		h.state.Unlock()
+		return nil, err
+	}
+	var known bool
+	if err := h.state.DB().QueryRowContext(ctx,
+		`SELECT EXISTS(SELECT 1 FROM agents WHERE agent_id = ?)`, assignee,
+	).Scan(&known); err != nil {
+		h.state.Unlock()
+		return nil, fmt.Errorf("check assignee: %w", err)
+	}
+	if !known {
+		h.state.Unlock()
+		return nil, fmt.Errorf("agent not found: %s", assigneeName)
+	}
+
+	if err := h.checkAssignableMessage(ctx, req.MessageID); err != nil {
+		h.state.Unlock()
+		return nil, err
+	}
+	current, err := h.loadOpenAssignment(ctx, req.MessageID)
+	if err != nil {
+		h.state.Unlock()
+		return nil, err
+	}
+
+	resp := &AssignResponse{MessageID: req.MessageID, Assignee: assignee}
+	if current != nil {
+		if current.assignee == assignee {
+			h.state.Unlock()
+			resp.AssignmentID = current.id
+			return resp, nil
+		}
+		resp.PreviousAssignee = current.assignee
+	}
+	resp.AssignmentID = identity.GenerateAssignmentID()
+
+	event := types.MessageAssignEvent{
+		Type:         "message.assign",
+		Timestamp:    time.Now().UTC().Format(time.RFC3339Nano),
+		MessageID:    req.MessageID,
+		AssignmentID: resp.AssignmentID,
+		Assignee:     assignee,
+		AgentID:      agentID,
+	}
+	postCommit, err := h.state.WriteEvent(ctx, event)
+	h.state.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("write message.assign event: %w", err)
+	}
+	h.state.GoPostCommit(postCommit)
+	resp.Changed = true
+
+	return resp, nil
+}
+
+// HandleComplete handles the message.complete RPC method. Only the current
+// assignee can complete a task. Unless they assigned it to themselves, the
+// assigner is told with an @system message replying to the task.
+func (h *MessageHandler) HandleComplete(ctx context.Context, params json.RawMessage) (any, error) {
+	var req CompleteRequest
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	req.MessageID = strings.TrimSpace(req.MessageID)
+	if req.MessageID == "" {
+		return nil, fmt.Errorf("message_id is required")
+	}
+
+	agentID, _, err := h.resolveAgentAndSession(ctx, req.CallerAgentID)
+	if err != nil {
+		return nil, fmt.Errorf("resolve agent and session: %w", err)
+	}
+
+	h.state.Lock()
+	if err := h.checkAssignableMessage(ctx, req.MessageID); err != nil {
+		h.state.Unlock()
+		return nil, err
+	}
+	current, err := h.loadOpenAssignment(ctx, req.MessageID)
+	if err != nil {
+		h.state.Unlock()
+		return nil, err
+	}
+	if current == nil {
+		h.state.Unlock()
+		return nil, fmt.Errorf("message %s has no open assignment", req.MessageID)
+	}
+	if current.assignee != agentID {
+		h.state.Unlock()
+		return nil, fmt.Errorf("only the assignee can complete (assignee: %s, current: %s)", current.assignee, agentID)
+	}
+
+	var threadID sql.NullString
+	if err := h.state.DB().QueryRowContext(ctx,
+		`SELECT thread_id FROM messages WHERE message_id = ?`, req.MessageID,
+	).Scan(&threadID); err != nil {
+		h.state.Unlock()
+		return nil, fmt.Errorf("query message thread: %w", err)
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	event := types.MessageCompleteEvent{
+		Type:         "message.complete",
+		Timestamp:    now,
+		MessageID:    req.MessageID,
+		AssignmentID: current.id,
+		AgentID:      agentID,
+	}
+	postCommit, err := h.state.WriteEvent(ctx, event)
+	h.state.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("write message.complete event: %w", err)
+	}
+	h.state.GoPostCommit(postCommit)
+
+	resp := &CompleteResponse{
+		MessageID:    req.MessageID,
+		AssignmentID: current.id,
+		AssignedBy:   current.assignedBy,
+	}
+	if current.assignedBy == agentID {
+		return resp, nil
+	}
+
+	// Same @system shape as the tmux queue notices; the reply_to ref threads
+	// it under the task so the assigner sees which message was done.
+	notice := types.MessageCreateEvent{
+		Type:      "message.create",
+		Timestamp: now,
+		EventID:   identity.GenerateEventID(),
+		Version:   1,
+		MessageID: identity.GenerateMessageID(),
+		ThreadID:  threadID.String,
+		AgentID:   "system",
+		SessionID: "system",
+		Body: types.MessageBody{
+			Format:  "markdown",
+			Content: fmt.Sprintf("@%s completed task %s", agentID, req.MessageID),
+		},
+		Refs: []types.Ref{
+			{Type: "mention", Value: current.assignedBy},
+			{Type: "reply_to", Value: req.MessageID},
+		},
+		Recipients: []string{current.assignedBy},
+	}
+	h.state.Lock()
+	postCommit, err = h.state.WriteEvent(ctx, notice)
+	h.state.Unlock()
+	if err != nil {
+		// The task is already completed; a lost notice shouldn't turn that
+		// into a failure the caller would retry.
+		return resp, nil
+	}
+	h.state.GoPostCommit(postCommit)
+	resp.NotificationID = notice.MessageID
+
+	return resp, nil
+}
+
+// checkAssignableMessage reports an error unless messageID names a message
+// that isn't deleted. Callers must hold the state lock.
+func (h *MessageHandler) checkAssignableMessage(ctx context.Context, messageID string) error {
+	var deleted int
+	err := h.state.DB().QueryRowContext(ctx,
+		`SELECT deleted FROM messages WHERE message_id = ?`, messageID,
+	).Scan(&deleted)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("message not found: %s", messageID)
+	}
+	if err != nil {
+		return fmt.Errorf("query message: %w", err)
+	}
+	if deleted == 1 {
+		return fmt.Errorf("message is deleted: %s", messageID)
+	}
+	return nil
+}
+
+// loadOpenAssignment returns the open assignment of messageID, or nil when
+// it has none. Callers must hold the state lock.
+func (h *MessageHandler) loadOpenAssignment(ctx context.Context, messageID string) (*openAssignment, error) {
+	var a openAssignment
+	err := h.state.DB().QueryRowContext(ctx,
+		`SELECT assignment_id, assignee, assigned_by FROM message_assignments
+		 WHERE message_id = ? AND status = 'open'
+		 ORDER BY assigned_at DESC LIMIT 1`, messageID,
+	).Scan(&a.id, &a.assignee, &a.assignedBy)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query assignment: %w", err)
+	}
+	return &a, nil
+}
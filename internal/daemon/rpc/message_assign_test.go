@@ -0,0 +1,125 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/leonletto/thrum/internal/identity"
+)
+
+func TestMessageAssign(t *testing.T) {
+	handler, agentID, cleanup := setupFilterTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	opsID := identity.GenerateAgentID("r_FILTER_TEST", "ops", "core", "")
+
+	params, _ := json.Marshal(SendRequest{Content: "please cut the release", To: "@" + agentID, CallerAgentID: agentID})
+	sent, err := handler.HandleSend(ctx, params)
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	msgID := sent.(*SendResponse).MessageID
+
+	assign := func(assignee, caller string) (*AssignResponse, error) {
+		t.Helper()
+		params, _ := json.Marshal(AssignRequest{MessageID: msgID, Assignee: assignee, CallerAgentID: caller})
+		resp, err := handler.HandleAssign(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		return resp.(*AssignResponse), nil
+	}
+	complete := func(caller string) (*CompleteResponse, error) {
+		t.Helper()
+		params, _ := json.Marshal(CompleteRequest{MessageID: msgID, CallerAgentID: caller})
+		resp, err := handler.HandleComplete(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		return resp.(*CompleteResponse), nil
+	}
+	assignedTo := func(agent string) int {
+		t.Helper()
+		params, _ := json.Marshal(ListMessagesRequest{AssignedTo: agent})
+		resp, err := handler.HandleList(ctx, params)
+		if err != nil {
+			t.Fatalf("list assigned_to %s: %v", agent, err)
+		}
+		return resp.(*ListMessagesResponse).Total
+	}
+
+	first, err := assign("@"+opsID, agentID)
+	if err != nil || !first.Changed || first.Assignee != opsID || first.PreviousAssignee != "" {
+		t.Fatalf("assign to ops = %+v, %v", first, err)
+	}
+	if resp, err := assign(opsID, agentID); err != nil || resp.Changed || resp.AssignmentID != first.AssignmentID {
+		t.Errorf("repeat assign = %+v, %v; want unchanged %s", resp, err, first.AssignmentID)
+	}
+	if got := assignedTo(opsID); got != 1 {
+		t.Errorf("open tasks for ops = %d, want 1", got)
+	}
+
+	// Reassign from ops back to the sender, made by ops: the ops row stays
+	// as history and ops becomes the assigner to notify.
+	second, err := assign(agentID, opsID)
+	if err != nil || !second.Changed || second.PreviousAssignee != opsID {
+		t.Fatalf("reassign = %+v, %v", second, err)
+	}
+	var statuses []string
+	rows, err := handler.state.DB().QueryContext(ctx,
+		`SELECT status FROM message_assignments WHERE message_id = ? ORDER BY assigned_at, rowid`, msgID)
+	if err != nil {
+		t.Fatalf("query history: %v", err)
+	}
+	for rows.Next() {
+		var s string
+		_ = rows.Scan(&s)
+		statuses = append(statuses, s)
+	}
+	_ = rows.Close()
+	if strings.Join(statuses, ",") != "reassigned,open" {
+		t.Errorf("history = %v, want [reassigned open]", statuses)
+	}
+	if got := assignedTo(opsID); got != 0 {
+		t.Errorf("open tasks for ops after reassign = %d, want 0", got)
+	}
+	if got := assignedTo(agentID); got != 1 {
+		t.Errorf("open tasks for sender = %d, want 1", got)
+	}
+
+	if _, err := complete(opsID); err == nil || !strings.Contains(err.Error(), "only the assignee") {
+		t.Errorf("complete by non-assignee: err = %v", err)
+	}
+
+	done, err := complete(agentID)
+	if err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+	if done.AssignedBy != opsID || done.NotificationID == "" {
+		t.Fatalf("complete = %+v; want notification to %s", done, opsID)
+	}
+	var author, mention string
+	if err := handler.state.DB().QueryRowContext(ctx, `
+		SELECT m.agent_id, r.ref_value FROM messages m
+		JOIN message_refs r ON r.message_id = m.message_id AND r.ref_type = 'mention'
+		WHERE m.message_id = ?`, done.NotificationID,
+	).Scan(&author, &mention); err != nil {
+		t.Fatalf("query notification: %v", err)
+	}
+	if author != "system" || mention != opsID {
+		t.Errorf("notification author/mention = %s/%s, want system/%s", author, mention, opsID)
+	}
+	if got := assignedTo(agentID); got != 0 {
+		t.Errorf("open tasks after complete = %d, want 0", got)
+	}
+
+	if _, err := complete(agentID); err == nil || !strings.Contains(err.Error(), "no open assignment") {
+		t.Errorf("second complete: err = %v", err)
+	}
+	if _, err := assign("nobody_here", agentID); err == nil || !strings.Contains(err.Error(), "agent not found") {
+		t.Errorf("assign to unknown agent: err = %v", err)
+	}
+}
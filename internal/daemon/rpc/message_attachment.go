@@ -0,0 +1,269 @@
+package rpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/leonletto/thrum/internal/transport"
+	"github.com/leonletto/thrum/internal/types"
+)
+
+// AttachmentsDir is the directory in the sync worktree that holds message
+// attachments, one subdirectory per message ID. It rides the a-sync branch
+// with the JSONL data, so attachments reach peers through the normal sync.
+const AttachmentsDir = "attachments"
+
+// AttachmentWarnBytes is the size above which send --attach still copies the
+// file but warns: every peer clones the sync branch, so a large binary there
+// costs everyone disk and fetch time for good.
+const AttachmentWarnBytes = 10 << 20
+
+// MessageAttachment describes a file attached to a message with send --attach.
+type MessageAttachment struct {
+	Name   string `json:"name"`   // file name, unique within the message
+	Path   string `json:"path"`   // relative to the sync worktree, e.g. attachments/msg_x/build.log
+	SHA256 string `json:"sha256"` // hex digest recorded at send time
+}
+
+// attachmentRef encodes an attachment as an "attachment" ref value:
+// "<path>:<sha256>". The digest is hex, so the last colon splits it.
+func attachmentRef(a MessageAttachment) types.Ref {
+	return types.Ref{Type: "attachment", Value: a.Path + ":" + a.SHA256}
+}
+
+// parseAttachmentRef is the inverse of attachmentRef. ok is false for values
+// that are not "<path>:<sha256>".
+func parseAttachmentRef(value string) (MessageAttachment, bool) {
+	i := strings.LastIndex(value, ":")
+	if i <= 0 || len(value)-i-1 != sha256.Size*2 {
+		return MessageAttachment{}, false
+	}
+	path := value[:i]
+	return MessageAttachment{Name: filepath.Base(path), Path: path, SHA256: value[i+1:]}, true
+}
+
+// attachmentsFromRefs returns the attachments recorded in a message's refs,
+// in ref order.
+func attachmentsFromRefs(refs []types.Ref) []MessageAttachment {
+	var out []MessageAttachment
+	for _, ref := range refs {
+		if ref.Type != "attachment" {
+			continue
+		}
+		if a, ok := parseAttachmentRef(ref.Value); ok {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// checkAttachments validates send --attach paths without copying anything,
+// so message.resolve (send --dry-run) fails exactly where message.send would.
+// It returns a warning for each file over AttachmentWarnBytes.
+//
+// The daemon reads the paths from its own filesystem, so attachments are
+// only accepted over the Unix socket: a WebSocket or HTTP client could
+// otherwise publish any file the daemon can read to the sync branch.
+func checkAttachments(ctx context.Context, paths []string) ([]string, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+	switch transport.GetTransport(ctx) {
+	case transport.TransportUnixSocket, transport.TransportUnknown:
+	default:
+		return nil, fmt.Errorf("attachments are only accepted over the local Unix socket")
+	}
+	var warnings []string
+	seen := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		if !filepath.IsAbs(p) {
+			return nil, fmt.Errorf("attachment path must be absolute: %s", p)
+		}
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, fmt.Errorf("attachment %s: %w", p, err)
+		}
+		if !info.Mode().IsRegular() {
+			return nil, fmt.Errorf("attachment %s is not a regular file", p)
+		}
+		name := filepath.Base(p)
+		if seen[name] {
+			return nil, fmt.Errorf("two attachments are named %s; rename one", name)
+		}
+		seen[name] = true
+		if info.Size() > AttachmentWarnBytes {
+			warnings = append(warnings, fmt.Sprintf(
+				"attachment %s is %d MB; it is stored on the sync branch and every peer downloads it",
+				name, info.Size()>>20))
+		}
+	}
+	return warnings, nil
+}
+
+// storeAttachments copies paths into <syncDir>/attachments/<messageID>/ and
+// returns one attachment ref per file carrying the copy's SHA-256. Paths must
+// already have passed checkAttachments. On error nothing is left behind.
+func (h *MessageHandler) storeAttachments(messageID string, paths []string) ([]types.Ref, error) {
+	rel := filepath.ToSlash(filepath.Join(AttachmentsDir, messageID))
+	dir := filepath.Join(h.state.SyncDir(), AttachmentsDir, messageID)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("create attachment dir: %w", err)
+	}
+	refs := make([]types.Ref, 0, len(paths))
+	for _, p := range paths {
+		name := filepath.Base(p)
+		sum, err := copyFileSHA256(p, filepath.Join(dir, name))
+		if err != nil {
+			_ = os.RemoveAll(dir)
+			return nil, fmt.Errorf("attach %s: %w", name, err)
+		}
+		refs = append(refs, attachmentRef(MessageAttachment{Name: name, Path: rel + "/" + name, SHA256: sum}))
+	}
+	return refs, nil
+}
+
+// removeAttachments drops a message's attachment directory, used when the
+// send fails after the files were copied.
+func (h *MessageHandler) removeAttachments(messageID string) {
+	_ = os.RemoveAll(filepath.Join(h.state.SyncDir(), AttachmentsDir, messageID))
+}
+
+// copyFileSHA256 copies src to dst and returns the hex SHA-256 of the bytes
+// written.
+func copyFileSHA256(src, dst string) (string, error) {
+	in, err := os.Open(src) // #nosec G304 -- caller-chosen attachment, Unix socket only (checkAttachments)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = in.Close() }()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600) // #nosec G304 -- under the sync worktree
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(out, hash), in); err != nil {
+		_ = out.Close()
+		return "", err
+	}
+	if err := out.Close(); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// AttachmentRequest represents the request for message.attachment RPC.
+type AttachmentRequest struct {
+	MessageID string `json:"message_id"`
+	Name      string `json:"name"`
+}
+
+// AttachmentResponse represents the response from message.attachment RPC.
+type AttachmentResponse struct {
+	MessageAttachment
+	MessageID string `json:"message_id"`
+	LocalPath string `json:"local_path"` // absolute path of the synced copy on the daemon host
+	Size      int64  `json:"size"`
+}
+
+// HandleAttachment handles the message.attachment RPC method. It locates a
+// named attachment of a message in this daemon's sync worktree and returns
+// its path with the checksum recorded at send time. The caller copies the
+// file and checks the digest: the synced copy is not verified here, so the
+// check covers the bytes the caller actually ends up with.
+func (h *MessageHandler) HandleAttachment(ctx context.Context, params json.RawMessage) (any, error) {
+	var req AttachmentRequest
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	req.MessageID = strings.TrimSpace(req.MessageID)
+	if req.MessageID == "" {
+		return nil, fmt.Errorf("message_id is required")
+	}
+	if req.Name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+
+	h.state.RLock()
+	var deleted int
+	err := h.state.DB().QueryRowContext(ctx,
+		`SELECT deleted FROM messages WHERE message_id = ?`, req.MessageID,
+	).Scan(&deleted)
+	if errors.Is(err, sql.ErrNoRows) {
+		h.state.RUnlock()
+		return nil, fmt.Errorf("message not found: %s", req.MessageID)
+	}
+	if err != nil {
+		h.state.RUnlock()
+		return nil, fmt.Errorf("query message: %w", err)
+	}
+	var values []string
+	rows, err := h.state.DB().QueryContext(ctx,
+		`SELECT ref_value FROM message_refs WHERE message_id = ? AND ref_type = 'attachment'`, req.MessageID)
+	if err != nil {
+		h.state.RUnlock()
+		return nil, fmt.Errorf("query attachments: %w", err)
+	}
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			_ = rows.Close()
+			h.state.RUnlock()
+			return nil, fmt.Errorf("scan attachment: %w", err)
+		}
+		values = append(values, v)
+	}
+	err = rows.Err()
+	_ = rows.Close()
+	h.state.RUnlock()
+	if err != nil {
+		return nil, fmt.Errorf("iterate attachments: %w", err)
+	}
+	if deleted == 1 {
+		return nil, fmt.Errorf("message is deleted: %s", req.MessageID)
+	}
+
+	var names []string
+	for _, v := range values {
+		a, ok := parseAttachmentRef(v)
+		if !ok {
+			continue
+		}
+		if a.Name != req.Name {
+			names = append(names, a.Name)
+			continue
+		}
+		// The path comes from a synced event; refuse anything that would
+		// resolve outside this message's attachment directory.
+		want := filepath.ToSlash(filepath.Join(AttachmentsDir, req.MessageID, a.Name))
+		if a.Path != want {
+			return nil, fmt.Errorf("attachment %s has an unexpected path %q", a.Name, a.Path)
+		}
+		local := filepath.Join(h.state.SyncDir(), filepath.FromSlash(a.Path))
+		info, err := os.Stat(local)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("attachment %s has not been synced to this machine yet; try 'thrum sync force'", a.Name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("stat attachment: %w", err)
+		}
+		return &AttachmentResponse{
+			MessageAttachment: a,
+			MessageID:         req.MessageID,
+			LocalPath:         local,
+			Size:              info.Size(),
+		}, nil
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("message %s has no attachments", req.MessageID)
+	}
+	return nil, fmt.Errorf("message %s has no attachment named %s (has: %s)", req.MessageID, req.Name, strings.Join(names, ", "))
+}
@@ -0,0 +1,137 @@
+package rpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/leonletto/thrum/internal/transport"
+	"github.com/leonletto/thrum/internal/types"
+)
+
+func TestMessageAttachments(t *testing.T) {
+	handler, agentID, cleanup := setupFilterTest(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	src := filepath.Join(t.TempDir(), "build.log")
+	content := []byte("step 3 failed\n")
+	if err := os.WriteFile(src, content, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(content)
+
+	params, _ := json.Marshal(SendRequest{Content: "see log", Attachments: []string{src}, CallerAgentID: agentID})
+	resp, err := handler.HandleSend(ctx, params)
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	msgID := resp.(*SendResponse).MessageID
+
+	getParams, _ := json.Marshal(GetMessageRequest{MessageID: msgID})
+	got, err := handler.HandleGet(ctx, getParams)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	atts := got.(*GetMessageResponse).Message.Attachments
+	want := MessageAttachment{Name: "build.log", Path: "attachments/" + msgID + "/build.log", SHA256: hex.EncodeToString(sum[:])}
+	if len(atts) != 1 || atts[0] != want {
+		t.Fatalf("attachments = %+v, want [%+v]", atts, want)
+	}
+
+	fetchParams, _ := json.Marshal(AttachmentRequest{MessageID: msgID, Name: "build.log"})
+	fetched, err := handler.HandleAttachment(ctx, fetchParams)
+	if err != nil {
+		t.Fatalf("attachment: %v", err)
+	}
+	loc := fetched.(*AttachmentResponse)
+	data, err := os.ReadFile(loc.LocalPath)
+	if err != nil {
+		t.Fatalf("read synced copy: %v", err)
+	}
+	if string(data) != string(content) || loc.Size != int64(len(content)) || loc.SHA256 != want.SHA256 {
+		t.Errorf("attachment = %+v with %q; want the copied file", loc, data)
+	}
+
+	missingParams, _ := json.Marshal(AttachmentRequest{MessageID: msgID, Name: "other.txt"})
+	if _, err := handler.HandleAttachment(ctx, missingParams); err == nil || !strings.Contains(err.Error(), "has: build.log") {
+		t.Errorf("unknown name err = %v, want it to list build.log", err)
+	}
+}
+
+func TestMessageAttachments_Rejected(t *testing.T) {
+	handler, agentID, cleanup := setupFilterTest(t)
+	defer cleanup()
+
+	src := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(src, []byte("x"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		req  SendRequest
+		want string
+	}{
+		{"websocket", transport.WithTransport(context.Background(), transport.TransportWebSocket),
+			SendRequest{Content: "x", Attachments: []string{src}}, "Unix socket"},
+		{"relative", context.Background(),
+			SendRequest{Content: "x", Attachments: []string{"notes.txt"}}, "absolute"},
+		{"duplicate_name", context.Background(),
+			SendRequest{Content: "x", Attachments: []string{src, src}}, "two attachments"},
+		{"hand_written_ref", context.Background(),
+			SendRequest{Content: "x", Refs: []types.Ref{{Type: "attachment", Value: "attachments/m/a:00"}}}, "send --attach"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.CallerAgentID = agentID
+			params, _ := json.Marshal(tt.req)
+			_, err := handler.HandleSend(tt.ctx, params)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMessageAttachments_LargeFileWarns(t *testing.T) {
+	handler, agentID, cleanup := setupFilterTest(t)
+	defer cleanup()
+
+	src := filepath.Join(t.TempDir(), "core.dump")
+	if err := os.WriteFile(src, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Truncate(src, AttachmentWarnBytes+1); err != nil {
+		t.Fatal(err)
+	}
+
+	params, _ := json.Marshal(SendRequest{Content: "dump", Attachments: []string{src}, CallerAgentID: agentID})
+	resp, err := handler.HandleSend(context.Background(), params)
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	warnings := resp.(*SendResponse).Warnings
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "core.dump") {
+		t.Errorf("warnings = %v, want one about core.dump", warnings)
+	}
+}
+
+func TestParseAttachmentRef(t *testing.T) {
+	digest := strings.Repeat("ab", sha256.Size)
+	a, ok := parseAttachmentRef("attachments/msg_1/a:b.txt:" + digest)
+	if !ok || a.Name != "a:b.txt" || a.Path != "attachments/msg_1/a:b.txt" || a.SHA256 != digest {
+		t.Errorf("parseAttachmentRef = %+v, %v", a, ok)
+	}
+	for _, bad := range []string{"", "attachments/msg_1/a.txt", "attachments/msg_1/a.txt:abc", ":" + digest} {
+		if _, ok := parseAttachmentRef(bad); ok {
+			t.Errorf("parseAttachmentRef(%q) ok, want rejected", bad)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package rpc
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/leonletto/thrum/internal/subscriptions"
+	"github.com/leonletto/thrum/internal/types"
+)
+
+// bumpInterval is how long a message must wait between bumps.
+const bumpInterval = 5 * time.Minute
+
+// BumpRequest represents the request for message.bump RPC.
+type BumpRequest struct {
+	MessageID     string `json:"message_id"`
+	CallerAgentID string `json:"caller_agent_id,omitempty"`
+}
+
+// BumpResponse represents the response from message.bump RPC.
+type BumpResponse struct {
+	MessageID string `json:"message_id"`
+	BumpedAt  string `json:"bumped_at"`
+}
+
+// HandleBump handles the message.bump RPC method. It resurfaces a message by
+// re-dispatching its notification to every matching subscriber, as if it had
+// just been sent, and records the time in bumped_at for inbox --bump-sort.
+// Only the author may bump a message, deleted and still-scheduled messages
+// cannot be bumped, and a message can be bumped at most once per bumpInterval; the limit is kept
+// in bumped_at, so it holds across daemon restarts and bumps made on a peer.
+func (h *MessageHandler) HandleBump(ctx context.Context, params json.RawMessage) (any, error) {
+	var req BumpRequest
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	req.MessageID = strings.TrimSpace(req.MessageID)
+	if req.MessageID == "" {
+		return nil, fmt.Errorf("message_id is required")
+	}
+
+	agentID, sessionID, err := h.resolveAgentAndSession(ctx, req.CallerAgentID)
+	if err != nil {
+		return nil, fmt.Errorf("resolve agent and session: %w", err)
+	}
+
+	// Check and write under one lock so two concurrent bumps can't both pass
+	// the rate limit.
+	h.state.Lock()
+	var authorID, content string
+	var threadID, bumpedAt, deliverAt sql.NullString
+	var deleted int
+	err = h.state.DB().QueryRowContext(ctx,
+		`SELECT agent_id, thread_id, body_content, deleted, bumped_at, deliver_at FROM messages WHERE message_id = ?`, req.MessageID,
+	).Scan(&authorID, &threadID, &content, &deleted, &bumpedAt, &deliverAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		h.state.Unlock()
+		return nil, fmt.Errorf("message not found: %s", req.MessageID)
+	}
+	if err != nil {
+		h.state.Unlock()
+		return nil, fmt.Errorf("query message: %w", err)
+	}
+	if deleted == 1 {
+		h.state.Unlock()
+		return nil, fmt.Errorf("cannot bump deleted message: %s", req.MessageID)
+	}
+	if authorID != agentID {
+		h.state.Unlock()
+		return nil, fmt.Errorf("only message author can bump (author: %s, current: %s)", authorID, agentID)
+	}
+
+	now := time.Now().UTC()
+	if deliverAt.Valid && deliverAt.String > now.Format(time.RFC3339) {
+		h.state.Unlock()
+		return nil, fmt.Errorf("message is scheduled for %s; cancel or wait", deliverAt.String)
+	}
+	if last, perr := time.Parse(time.RFC3339Nano, bumpedAt.String); bumpedAt.Valid && perr == nil {
+		if wait := last.Add(bumpInterval).Sub(now); wait > 0 {
+			h.state.Unlock()
+			return nil, fmt.Errorf("message %s was bumped %s ago; a message can be bumped once every %s, retry in %s",
+				req.MessageID, now.Sub(last).Round(time.Second), bumpInterval, wait.Round(time.Second))
+		}
+	}
+
+	event := types.MessageBumpEvent{
+		Type:      "message.bump",
+		Timestamp: now.Format(time.RFC3339Nano),
+		MessageID: req.MessageID,
+		AgentID:   agentID,
+	}
+	postCommit, err := h.state.WriteEvent(ctx, event)
+	h.state.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("write message.bump event: %w", err)
+	}
+	h.state.GoPostCommit(postCommit)
+
+	// Dispatch without lock (DB queries + WebSocket I/O)
+	scopes, refs, err := h.messageScopesAndRefs(ctx, req.MessageID)
+	if err != nil {
+		return nil, err
+	}
+	preview := content
+	if len(preview) > 100 {
+		preview = preview[:100]
+	}
+	msgInfo := &subscriptions.MessageInfo{
+		MessageID: req.MessageID,
+		ThreadID:  threadID.String,
+		AgentID:   agentID,
+		SessionID: sessionID,
+		Scopes:    scopes,
+		Refs:      refs,
+		Timestamp: event.Timestamp,
+		Preview:   preview,
+	}
+	_, _ = h.dispatcher.DispatchForMessage(ctx, msgInfo)
+	if bc := h.loadBroadcaster(); bc != nil {
+		bc.BroadcastAll(buildWSNotification(msgInfo))
+	}
+
+	return &BumpResponse{MessageID: req.MessageID, BumpedAt: event.Timestamp}, nil
+}
+
+// messageScopesAndRefs loads a message's scopes and refs for re-dispatching
+// its notification.
+func (h *MessageHandler) messageScopesAndRefs(ctx context.Context, messageID string) ([]types.Scope, []types.Ref, error) {
+	db := h.state.DB()
+	rows, err := db.QueryContext(ctx,
+		`SELECT scope_type, scope_value FROM message_scopes WHERE message_id = ?`, messageID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("query scopes: %w", err)
+	}
+	var scopes []types.Scope
+	for rows.Next() {
+		var scope types.Scope
+		if err := rows.Scan(&scope.Type, &scope.Value); err != nil {
+			_ = rows.Close()
+			return nil, nil, fmt.Errorf("scan scope: %w", err)
+		}
+		scopes = append(scopes, scope)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("iterate scopes: %w", err)
+	}
+
+	rows, err = db.QueryContext(ctx,
+		`SELECT ref_type, ref_value FROM message_refs WHERE message_id = ?`, messageID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("query refs: %w", err)
+	}
+	var refs []types.Ref
+	for rows.Next() {
+		var ref types.Ref
+		if err := rows.Scan(&ref.Type, &ref.Value); err != nil {
+			_ = rows.Close()
+			return nil, nil, fmt.Errorf("scan ref: %w", err)
+		}
+		refs = append(refs, ref)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("iterate refs: %w", err)
+	}
+	return scopes, refs, nil
+}
@@ -0,0 +1,95 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leonletto/thrum/internal/identity"
+)
+
+func TestMessageBump(t *testing.T) {
+	handler, agentID, cleanup := setupFilterTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	opsID := identity.GenerateAgentID("r_FILTER_TEST", "ops", "core", "")
+
+	var ids []string
+	for _, content := range []string{"review please", "middle", "newest"} {
+		params, _ := json.Marshal(SendRequest{Content: content, Mentions: []string{"@reviewer"}, CallerAgentID: opsID})
+		resp, err := handler.HandleSend(ctx, params)
+		if err != nil {
+			t.Fatalf("send %q: %v", content, err)
+		}
+		ids = append(ids, resp.(*SendResponse).MessageID)
+	}
+	bump := func(msgID, caller string) (*BumpResponse, error) {
+		t.Helper()
+		params, _ := json.Marshal(BumpRequest{MessageID: msgID, CallerAgentID: caller})
+		resp, err := handler.HandleBump(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		return resp.(*BumpResponse), nil
+	}
+	inbox := func(sortBy string) []MessageSummary {
+		t.Helper()
+		params, _ := json.Marshal(ListMessagesRequest{ForAgent: agentID, ForAgentRole: "reviewer", PageSize: 100, SortBy: sortBy})
+		resp, err := handler.HandleList(ctx, params)
+		if err != nil {
+			t.Fatalf("HandleList: %v", err)
+		}
+		return resp.(*ListMessagesResponse).Messages
+	}
+
+	resp, err := bump(ids[0], opsID)
+	if err != nil {
+		t.Fatalf("bump: %v", err)
+	}
+	if resp.MessageID != ids[0] || resp.BumpedAt == "" {
+		t.Errorf("bump = %+v, want %s with a bumped_at", resp, ids[0])
+	}
+
+	msgs := inbox("bumped_at")
+	if len(msgs) != 3 || msgs[0].MessageID != ids[0] || msgs[0].BumpedAt != resp.BumpedAt {
+		t.Fatalf("bump-sorted inbox = %+v, want the bumped message first", msgs)
+	}
+	if msgs[1].MessageID != ids[2] || msgs[1].BumpedAt != "" {
+		t.Errorf("second = %s, want the newest unbumped message %s", msgs[1].MessageID, ids[2])
+	}
+	if msgs := inbox(""); msgs[0].MessageID != ids[2] {
+		t.Errorf("default inbox leads with %s, want newest-sent %s", msgs[0].MessageID, ids[2])
+	}
+
+	if _, err := bump(ids[0], opsID); err == nil || !strings.Contains(err.Error(), "retry in") {
+		t.Errorf("second bump within the interval: err = %v, want rate-limit error", err)
+	}
+	if _, err := bump(ids[1], agentID); err == nil || !strings.Contains(err.Error(), "only message author") {
+		t.Errorf("bump by non-author: err = %v", err)
+	}
+	if _, err := bump("msg_NONEXISTENT", opsID); err == nil || !strings.Contains(err.Error(), "message not found") {
+		t.Errorf("bump unknown message: err = %v", err)
+	}
+
+	// A scheduled message can't be bumped before it is delivered.
+	schedParams, _ := json.Marshal(SendRequest{Content: "later", Schedule: "+1h", Mentions: []string{"@reviewer"}, CallerAgentID: opsID})
+	schedResp, err := handler.HandleSend(ctx, schedParams)
+	if err != nil {
+		t.Fatalf("send scheduled: %v", err)
+	}
+	if _, err := bump(schedResp.(*SendResponse).MessageID, opsID); err == nil || !strings.Contains(err.Error(), "is scheduled for") {
+		t.Errorf("bump scheduled message: err = %v, want scheduled error", err)
+	}
+
+	// Once the interval has passed, the message can be bumped again.
+	earlier := time.Now().UTC().Add(-bumpInterval - time.Minute).Format(time.RFC3339Nano)
+	if _, err := handler.state.DB().ExecContext(ctx, `UPDATE messages SET bumped_at = ? WHERE message_id = ?`, earlier, ids[0]); err != nil {
+		t.Fatalf("rewind bumped_at: %v", err)
+	}
+	if _, err := bump(ids[0], opsID); err != nil {
+		t.Errorf("bump after the interval: %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+package rpc
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// messageCursor is the decoded form of a message.list cursor. It records
+// the sort the page was listed under and the sort key of the page's last
+// row, so the next page resumes right after that row (keyset pagination)
+// however many messages arrived in between. Clients treat the encoded
+// string as opaque.
+type messageCursor struct {
+	SortBy    string `json:"sort_by"`
+	SortOrder string `json:"sort_order"`
+	Key       string `json:"key,omitempty"`      // created_at, updated_at or bumped_at sort value
+	Sequence  int64  `json:"sequence,omitempty"` // sort_by "sequence": origin sequence
+	Daemon    string `json:"daemon,omitempty"`   // sort_by "sequence": origin daemon
+	MessageID string `json:"message_id"`
+}
+
+// errInvalidCursor is returned for an after_cursor that was not produced by
+// message.list.
+var errInvalidCursor = errors.New("invalid after_cursor")
+
+func encodeMessageCursor(c messageCursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeMessageCursor(s string) (messageCursor, error) {
+	var c messageCursor
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, errInvalidCursor
+	}
+	if err := json.Unmarshal(data, &c); err != nil || c.MessageID == "" {
+		return c, errInvalidCursor
+	}
+	switch c.SortBy {
+	case "created_at", "updated_at", "bumped_at", "sequence":
+	default:
+		return c, errInvalidCursor
+	}
+	if c.SortOrder != "asc" && c.SortOrder != "desc" {
+		return c, errInvalidCursor
+	}
+	return c, nil
+}
+
+// messageSortKey is the SQL expression message.list orders on for sortBy
+// (other than "sequence", which orders on three columns). Unedited
+// messages have no updated_at and sort first; a message never bumped sorts
+// by when it was sent.
+func messageSortKey(sortBy string) string {
+	switch sortBy {
+	case "updated_at":
+		return "COALESCE(m.updated_at, '')"
+	case "bumped_at":
+		return "COALESCE(m.bumped_at, m.created_at)"
+	default:
+		return "m.created_at"
+	}
+}
+
+// clause returns the WHERE clause selecting the rows that sort after c.
+// message_id breaks ties, matching the ORDER BY.
+func (c messageCursor) clause() (string, []any) {
+	op := ">"
+	if c.SortOrder == "desc" {
+		op = "<"
+	}
+	if c.SortBy == "sequence" {
+		return fmt.Sprintf(" AND (m.origin_sequence, m.origin_daemon, m.message_id) %s (?, ?, ?)", op),
+			[]any{c.Sequence, c.Daemon, c.MessageID}
+	}
+	return fmt.Sprintf(" AND (%s, m.message_id) %s (?, ?)", messageSortKey(c.SortBy), op),
+		[]any{c.Key, c.MessageID}
+}
@@ -0,0 +1,100 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/leonletto/thrum/internal/identity"
+)
+
+// TestMessageList_Cursor pages through message.list with after_cursor and
+// checks that a message sent between pages neither shifts nor repeats
+// rows, and that the cursor keeps the sort it was issued under.
+func TestMessageList_Cursor(t *testing.T) {
+	handler, _, cleanup := setupFilterTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	opsID := identity.GenerateAgentID("r_FILTER_TEST", "ops", "core", "")
+	send := func(content string) string {
+		t.Helper()
+		params, _ := json.Marshal(SendRequest{Content: content, Mentions: []string{"@reviewer"}, CallerAgentID: opsID})
+		resp, err := handler.HandleSend(ctx, params)
+		if err != nil {
+			t.Fatalf("send %q: %v", content, err)
+		}
+		return resp.(*SendResponse).MessageID
+	}
+	list := func(req ListMessagesRequest) *ListMessagesResponse {
+		t.Helper()
+		params, _ := json.Marshal(req)
+		resp, err := handler.HandleList(ctx, params)
+		if err != nil {
+			t.Fatalf("HandleList(%+v): %v", req, err)
+		}
+		return resp.(*ListMessagesResponse)
+	}
+	ids := func(msgs []MessageSummary) []string {
+		out := make([]string, 0, len(msgs))
+		for _, m := range msgs {
+			out = append(out, m.MessageID)
+		}
+		return out
+	}
+
+	var sent []string
+	for i := range 5 {
+		sent = append(sent, send(fmt.Sprintf("message %d", i)))
+	}
+	all := ids(list(ListMessagesRequest{PageSize: 100}).Messages)
+
+	first := list(ListMessagesRequest{PageSize: 2})
+	if first.NextCursor == "" {
+		t.Fatal("first page has no next_cursor")
+	}
+	// A newer message lands between pages; with offsets it would push
+	// the first page's last row onto page 2.
+	send("late arrival")
+
+	var walked []string
+	walked = append(walked, ids(first.Messages)...)
+	for cursor := first.NextCursor; cursor != ""; {
+		page := list(ListMessagesRequest{PageSize: 2, AfterCursor: cursor})
+		walked = append(walked, ids(page.Messages)...)
+		cursor = page.NextCursor
+	}
+	if !slices.Equal(walked, all) {
+		t.Errorf("cursor walk = %v, want %v", walked, all)
+	}
+
+	// A cursor resumes its own sort when none is given, here oldest first
+	// by sequence.
+	seq := list(ListMessagesRequest{PageSize: 3, SortBy: "sequence", SortOrder: "asc"})
+	rest := list(ListMessagesRequest{PageSize: 100, AfterCursor: seq.NextCursor})
+	if got := append(ids(seq.Messages), ids(rest.Messages)[:2]...); !slices.Equal(got, sent) {
+		t.Errorf("sequence walk = %v, want %v first", got, sent)
+	}
+	if rest.NextCursor != "" {
+		t.Errorf("last page next_cursor = %q, want none", rest.NextCursor)
+	}
+
+	for _, tc := range []struct {
+		name string
+		req  ListMessagesRequest
+		want string
+	}{
+		{"garbage", ListMessagesRequest{AfterCursor: "not-a-cursor"}, "invalid after_cursor"},
+		{"other sort", ListMessagesRequest{AfterCursor: first.NextCursor, SortBy: "sequence"}, "was issued for sort_by created_at desc"},
+		{"with page", ListMessagesRequest{AfterCursor: first.NextCursor, Page: 2}, "mutually exclusive"},
+		{"priority sort", ListMessagesRequest{AfterCursor: first.NextCursor, PrioritySort: true}, "not supported"},
+	} {
+		params, _ := json.Marshal(tc.req)
+		if _, err := handler.HandleList(ctx, params); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: err = %v, want it to contain %q", tc.name, err, tc.want)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestMessageExport(t *testing.T) {
+	handler, agentID, cleanup := setupFilterTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	send := func(content, replyTo string) string {
+		t.Helper()
+		params, _ := json.Marshal(SendRequest{Content: content, ReplyTo: replyTo, CallerAgentID: agentID})
+		resp, err := handler.HandleSend(ctx, params)
+		if err != nil {
+			t.Fatalf("HandleSend: %v", err)
+		}
+		return resp.(*SendResponse).MessageID
+	}
+	db := handler.state.RawDB()
+	setTime := func(id, ts string) {
+		t.Helper()
+		if _, err := db.Exec(`UPDATE messages SET created_at = ? WHERE message_id = ?`, ts, id); err != nil {
+			t.Fatalf("set created_at: %v", err)
+		}
+	}
+
+	root := send("root", "")
+	reply := send("reply", root)
+	gone := send("gone", "")
+	setTime(root, "2026-01-01T00:00:00Z")
+	setTime(reply, "2026-01-01T00:01:00Z")
+	setTime(gone, "2026-01-02T00:00:00Z")
+	delParams, _ := json.Marshal(DeleteMessageRequest{MessageID: gone, CallerAgentID: agentID})
+	if _, err := handler.HandleDelete(ctx, delParams); err != nil {
+		t.Fatalf("HandleDelete: %v", err)
+	}
+
+	export := func(req ExportRequest) *ExportResponse {
+		t.Helper()
+		params, _ := json.Marshal(req)
+		resp, err := handler.HandleExport(ctx, params)
+		if err != nil {
+			t.Fatalf("HandleExport: %v", err)
+		}
+		return resp.(*ExportResponse)
+	}
+	ids := func(resp *ExportResponse) []string {
+		var out []string
+		for _, rec := range resp.Records {
+			out = append(out, rec.MessageID)
+		}
+		return out
+	}
+
+	t.Run("default_skips_deleted", func(t *testing.T) {
+		resp := export(ExportRequest{})
+		if got := ids(resp); len(got) != 2 || got[0] != root || got[1] != reply {
+			t.Fatalf("records = %v, want [%s %s]", got, root, reply)
+		}
+		if resp.Records[1].ThreadID == "" || resp.Records[1].ThreadID != resp.Records[0].ThreadID {
+			t.Errorf("thread ids = %q, %q; want the same non-empty thread", resp.Records[0].ThreadID, resp.Records[1].ThreadID)
+		}
+		if len(resp.Threads) != 1 || resp.Threads[0].ThreadID != resp.Records[1].ThreadID {
+			t.Fatalf("threads = %+v, want the reply's thread", resp.Threads)
+		}
+		if resp.Threads[0].CreatedAt == "" || resp.Threads[0].CreatedBy == "" {
+			t.Errorf("thread missing created_at/created_by: %+v", resp.Threads[0])
+		}
+	})
+
+	t.Run("include_deleted_adds_tombstone", func(t *testing.T) {
+		resp := export(ExportRequest{IncludeDeleted: true})
+		if got := ids(resp); len(got) != 3 || got[2] != gone {
+			t.Fatalf("records = %v, want deleted %s last", got, gone)
+		}
+		if rec := resp.Records[2]; !rec.Deleted || rec.DeletedAt == "" {
+			t.Errorf("tombstone = %+v, want Deleted with DeletedAt", rec)
+		}
+	})
+
+	t.Run("since", func(t *testing.T) {
+		resp := export(ExportRequest{Since: "2026-01-01T00:01:00Z", IncludeDeleted: true})
+		if got := ids(resp); len(got) != 2 || got[0] != reply || got[1] != gone {
+			t.Fatalf("records = %v, want [%s %s]", got, reply, gone)
+		}
+	})
+
+	t.Run("invalid_since", func(t *testing.T) {
+		params, _ := json.Marshal(ExportRequest{Since: "yesterday"})
+		if _, err := handler.HandleExport(ctx, params); err == nil {
+			t.Fatal("expected error for invalid since")
+		}
+	})
+
+	t.Run("import_skips_tombstones", func(t *testing.T) {
+		resp := export(ExportRequest{IncludeDeleted: true})
+		params, _ := json.Marshal(ImportRequest{Records: resp.Records})
+		res, err := handler.HandleImport(ctx, params)
+		if err != nil {
+			t.Fatalf("HandleImport: %v", err)
+		}
+		imp := res.(*ImportResponse)
+		if imp.TombstoneCount != 1 || imp.SkippedCount != 2 || imp.ImportedCount != 0 {
+			t.Errorf("import = %+v, want 1 tombstone, 2 skipped", imp)
+		}
+	})
+}
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/leonletto/thrum/internal/daemon/state"
@@ -279,6 +281,278 @@ func TestMessageListUnreadFilter(t *testing.T) {
 	})
 }
 
+func TestMessageListUnseenBy(t *testing.T) {
+	handler, agentID, cleanup := setupFilterTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	coordID := identity.GenerateAgentID("r_FILTER_TEST", "coordinator", "core", "")
+	coordParams, _ := json.Marshal(RegisterRequest{Role: "coordinator", Module: "core"})
+	if _, err := NewAgentHandler(handler.state).HandleRegister(ctx, coordParams); err != nil {
+		t.Fatalf("register coordinator: %v", err)
+	}
+
+	// Two messages from ops to the reviewer, one from the reviewer itself,
+	// and one from ops to the coordinator that the reviewer never sees.
+	opsID := identity.GenerateAgentID("r_FILTER_TEST", "ops", "core", "")
+	var messageIDs []string
+	for i := 0; i < 2; i++ {
+		params, _ := json.Marshal(SendRequest{Content: "Backlog message", To: "@" + agentID, CallerAgentID: opsID})
+		resp, err := handler.HandleSend(ctx, params)
+		if err != nil {
+			t.Fatalf("send: %v", err)
+		}
+		messageIDs = append(messageIDs, resp.(*SendResponse).MessageID)
+	}
+	ownParams, _ := json.Marshal(SendRequest{Content: "Reviewer's own", To: "@" + opsID, CallerAgentID: agentID})
+	if _, err := handler.HandleSend(ctx, ownParams); err != nil {
+		t.Fatalf("send own: %v", err)
+	}
+	otherParams, _ := json.Marshal(SendRequest{Content: "For the coordinator", To: "@" + coordID, CallerAgentID: opsID})
+	if _, err := handler.HandleSend(ctx, otherParams); err != nil {
+		t.Fatalf("send to coordinator: %v", err)
+	}
+
+	markParams, _ := json.Marshal(MarkReadRequest{MessageIDs: []string{messageIDs[0]}, CallerAgentID: agentID})
+	if _, err := handler.HandleMarkRead(ctx, markParams); err != nil {
+		t.Fatalf("mark read: %v", err)
+	}
+
+	t.Run("coordinator sees target backlog excluding target's own", func(t *testing.T) {
+		params, _ := json.Marshal(ListMessagesRequest{UnseenBy: agentID, CallerAgentID: coordID})
+		resp, err := handler.HandleList(ctx, params)
+		if err != nil {
+			t.Fatalf("HandleList: %v", err)
+		}
+		listResp := resp.(*ListMessagesResponse)
+		if listResp.Total != 1 || len(listResp.Messages) != 1 {
+			t.Fatalf("expected 1 unseen message, got total=%d len=%d", listResp.Total, len(listResp.Messages))
+		}
+		if listResp.Messages[0].MessageID != messageIDs[1] {
+			t.Errorf("expected %s, got %s", messageIDs[1], listResp.Messages[0].MessageID)
+		}
+	})
+
+	t.Run("non-coordinator is refused", func(t *testing.T) {
+		params, _ := json.Marshal(ListMessagesRequest{UnseenBy: opsID, CallerAgentID: agentID})
+		if _, err := handler.HandleList(ctx, params); err == nil {
+			t.Fatal("expected error for non-coordinator caller")
+		}
+	})
+
+	t.Run("unknown target is refused", func(t *testing.T) {
+		params, _ := json.Marshal(ListMessagesRequest{UnseenBy: "nobody_here", CallerAgentID: coordID})
+		if _, err := handler.HandleList(ctx, params); err == nil {
+			t.Fatal("expected error for unknown unseen_by agent")
+		}
+	})
+}
+
+func TestMessageListAuthorRole(t *testing.T) {
+	handler, agentID, cleanup := setupFilterTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	// Two distinct ops agents plus the reviewer each send one message.
+	opsID := identity.GenerateAgentID("r_FILTER_TEST", "ops", "core", "")
+	opsUIID := identity.GenerateAgentID("r_FILTER_TEST", "ops", "ui", "")
+	regParams, _ := json.Marshal(RegisterRequest{Role: "ops", Module: "ui"})
+	if _, err := NewAgentHandler(handler.state).HandleRegister(ctx, regParams); err != nil {
+		t.Fatalf("register second ops agent: %v", err)
+	}
+	for _, author := range []string{opsID, opsUIID} {
+		sessParams, _ := json.Marshal(SessionStartRequest{AgentID: author})
+		if _, err := NewSessionHandler(handler.state).HandleStart(ctx, sessParams); err != nil {
+			t.Fatalf("start session for %s: %v", author, err)
+		}
+	}
+	for _, author := range []string{opsID, opsUIID, agentID} {
+		params, _ := json.Marshal(SendRequest{Content: "From " + author, CallerAgentID: author})
+		if _, err := handler.HandleSend(ctx, params); err != nil {
+			t.Fatalf("send as %s: %v", author, err)
+		}
+	}
+
+	params, _ := json.Marshal(ListMessagesRequest{AuthorRole: "ops", CallerAgentID: agentID})
+	resp, err := handler.HandleList(ctx, params)
+	if err != nil {
+		t.Fatalf("HandleList: %v", err)
+	}
+	listResp := resp.(*ListMessagesResponse)
+	if listResp.Total != 2 || len(listResp.Messages) != 2 {
+		t.Fatalf("expected 2 ops-authored messages, got total=%d len=%d", listResp.Total, len(listResp.Messages))
+	}
+	for _, m := range listResp.Messages {
+		if m.AgentID != opsID && m.AgentID != opsUIID {
+			t.Errorf("unexpected author %s in --author-role ops result", m.AgentID)
+		}
+	}
+
+	// Combines with AuthorID: narrows to one ops agent.
+	params, _ = json.Marshal(ListMessagesRequest{AuthorRole: "ops", AuthorID: opsUIID, CallerAgentID: agentID})
+	resp, err = handler.HandleList(ctx, params)
+	if err != nil {
+		t.Fatalf("HandleList (combined): %v", err)
+	}
+	if total := resp.(*ListMessagesResponse).Total; total != 1 {
+		t.Errorf("expected 1 message for role+author, got %d", total)
+	}
+}
+
+func TestMessageListTag(t *testing.T) {
+	handler, agentID, cleanup := setupFilterTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	send := func(content string, tags ...string) {
+		t.Helper()
+		params, _ := json.Marshal(SendRequest{Content: content, Tags: tags, CallerAgentID: agentID})
+		if _, err := handler.HandleSend(ctx, params); err != nil {
+			t.Fatalf("send %q: %v", content, err)
+		}
+	}
+	send("Use SQLite", "decision", " decision ", "db")
+	send("Ship Friday", "decision")
+	send("Lunch?")
+	send("Index tags", "db")
+
+	list := func(req ListMessagesRequest) *ListMessagesResponse {
+		t.Helper()
+		req.CallerAgentID = agentID
+		params, _ := json.Marshal(req)
+		resp, err := handler.HandleList(ctx, params)
+		if err != nil {
+			t.Fatalf("HandleList(%+v): %v", req, err)
+		}
+		return resp.(*ListMessagesResponse)
+	}
+
+	// Duplicate/whitespace tags on send collapse to one row, so the join
+	// doesn't double-count.
+	resp := list(ListMessagesRequest{Tag: "decision"})
+	if resp.Total != 2 || len(resp.Messages) != 2 {
+		t.Fatalf("tag=decision: total=%d len=%d, want 2/2", resp.Total, len(resp.Messages))
+	}
+
+	// Total reflects the tag filter across pages.
+	resp = list(ListMessagesRequest{Tag: "db", PageSize: 1})
+	if resp.Total != 2 || len(resp.Messages) != 1 || resp.TotalPages != 2 {
+		t.Errorf("tag=db page 1: total=%d len=%d pages=%d, want 2/1/2", resp.Total, len(resp.Messages), resp.TotalPages)
+	}
+
+	if resp := list(ListMessagesRequest{Tag: "nope"}); resp.Total != 0 {
+		t.Errorf("unknown tag total = %d, want 0", resp.Total)
+	}
+	if resp := list(ListMessagesRequest{}); resp.Total != 4 {
+		t.Errorf("unfiltered total = %d, want 4", resp.Total)
+	}
+}
+
+func TestSendTags(t *testing.T) {
+	handler, agentID, cleanup := setupFilterTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	params, _ := json.Marshal(SendRequest{Content: "Use SQLite", Tags: []string{"db", "decision", "db", " decision"}, CallerAgentID: agentID})
+	resp, err := handler.HandleSend(ctx, params)
+	if err != nil {
+		t.Fatalf("HandleSend: %v", err)
+	}
+	msgID := resp.(*SendResponse).MessageID
+
+	var n int
+	if err := handler.state.RawDB().QueryRow(`SELECT COUNT(*) FROM message_tags WHERE message_id = ?`, msgID).Scan(&n); err != nil {
+		t.Fatalf("count tags: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("stored tags = %d, want 2 (duplicates dropped)", n)
+	}
+
+	getParams, _ := json.Marshal(GetMessageRequest{MessageID: msgID})
+	getResp, err := handler.HandleGet(ctx, getParams)
+	if err != nil {
+		t.Fatalf("HandleGet: %v", err)
+	}
+	if got := getResp.(*GetMessageResponse).Message.Tags; len(got) != 2 || got[0] != "db" || got[1] != "decision" {
+		t.Errorf("message.get tags = %v, want [db decision]", got)
+	}
+
+	for _, bad := range []string{"Decision", "needs review", "a_b", strings.Repeat("x", 65)} {
+		params, _ := json.Marshal(SendRequest{Content: "x", Tags: []string{bad}, CallerAgentID: agentID})
+		if _, err := handler.HandleSend(ctx, params); err == nil || !strings.Contains(err.Error(), "invalid tag") {
+			t.Errorf("tag %q: err = %v, want invalid tag", bad, err)
+		}
+	}
+}
+
+func TestMessageListPriority(t *testing.T) {
+	handler, agentID, cleanup := setupFilterTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	opsID := identity.GenerateAgentID("r_FILTER_TEST", "ops", "core", "")
+
+	send := func(from, content, priority string) {
+		t.Helper()
+		params, _ := json.Marshal(SendRequest{Content: content, To: "@" + agentID, Priority: priority, CallerAgentID: from})
+		if _, err := handler.HandleSend(ctx, params); err != nil {
+			t.Fatalf("send %q: %v", content, err)
+		}
+	}
+	send(opsID, "urgent", "HIGH")
+	send(opsID, "later", "low")
+	send(opsID, "fyi", "normal")
+	send(agentID, "own high", "high") // self-delivered, so already read
+	send(opsID, "newest", "")
+
+	params, _ := json.Marshal(SendRequest{Content: "x", To: "@" + agentID, Priority: "urgent", CallerAgentID: opsID})
+	if _, err := handler.HandleSend(ctx, params); err == nil || !strings.Contains(err.Error(), "invalid priority") {
+		t.Errorf("send with priority=urgent: err = %v, want invalid priority", err)
+	}
+
+	list := func(req ListMessagesRequest) *ListMessagesResponse {
+		t.Helper()
+		req.CallerAgentID = agentID
+		params, _ := json.Marshal(req)
+		resp, err := handler.HandleList(ctx, params)
+		if err != nil {
+			t.Fatalf("HandleList(%+v): %v", req, err)
+		}
+		return resp.(*ListMessagesResponse)
+	}
+	contents := func(resp *ListMessagesResponse) []string {
+		var out []string
+		for _, m := range resp.Messages {
+			out = append(out, m.Body.Content)
+		}
+		return out
+	}
+
+	resp := list(ListMessagesRequest{Priority: "high"})
+	if resp.Total != 2 || resp.Messages[0].Priority != "high" {
+		t.Errorf("priority=high: total=%d messages=%v, want 2 high", resp.Total, contents(resp))
+	}
+	// Normal covers both an explicit "normal" and an unset priority.
+	if resp := list(ListMessagesRequest{Priority: "normal"}); resp.Total != 2 || resp.Messages[0].Priority != "" {
+		t.Errorf("priority=normal: total=%d messages=%v, want [newest fyi]", resp.Total, contents(resp))
+	}
+	if _, err := handler.HandleList(ctx, json.RawMessage(`{"priority":"urgent"}`)); err == nil {
+		t.Error("list with priority=urgent should fail")
+	}
+
+	// Default order is newest first; --priority-sort lifts the unread high
+	// message to the top but leaves the already-read one in place.
+	got := contents(list(ListMessagesRequest{PrioritySort: true}))
+	want := []string{"urgent", "newest", "own high", "fyi", "later"}
+	if !slices.Equal(got, want) {
+		t.Errorf("priority sort order = %v, want %v", got, want)
+	}
+}
+
 func TestMessageListCombinedFilters(t *testing.T) {
 	handler, agentID, cleanup := setupFilterTest(t)
 	defer cleanup()
@@ -502,3 +776,55 @@ func TestMessageListUnreadCountWithoutSession(t *testing.T) {
 		t.Errorf("expected 3 total messages, got %d", listResp.Total)
 	}
 }
+
+func TestMessageListIncludeDeleted(t *testing.T) {
+	handler, agentID, cleanup := setupFilterTest(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	var ids []string
+	for _, content := range []string{"keep me", "remove me"} {
+		params, _ := json.Marshal(SendRequest{Content: content, CallerAgentID: agentID})
+		resp, err := handler.HandleSend(ctx, params)
+		if err != nil {
+			t.Fatalf("send: %v", err)
+		}
+		ids = append(ids, resp.(*SendResponse).MessageID)
+	}
+	delParams, _ := json.Marshal(DeleteMessageRequest{MessageID: ids[1], Reason: "posted by mistake", CallerAgentID: agentID})
+	if _, err := handler.HandleDelete(ctx, delParams); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	list := func(req ListMessagesRequest) *ListMessagesResponse {
+		t.Helper()
+		params, _ := json.Marshal(req)
+		resp, err := handler.HandleList(ctx, params)
+		if err != nil {
+			t.Fatalf("list: %v", err)
+		}
+		return resp.(*ListMessagesResponse)
+	}
+
+	plain := list(ListMessagesRequest{CallerAgentID: agentID})
+	if plain.Total != 1 || len(plain.Messages) != 1 || plain.Messages[0].MessageID != ids[0] {
+		t.Fatalf("default listing = %d messages (total %d); want only %s", len(plain.Messages), plain.Total, ids[0])
+	}
+
+	all := list(ListMessagesRequest{CallerAgentID: agentID, IncludeDeleted: true})
+	if all.Total != 2 {
+		t.Fatalf("include_deleted total = %d, want 2", all.Total)
+	}
+	var tomb *MessageSummary
+	for i := range all.Messages {
+		if all.Messages[i].MessageID == ids[1] {
+			tomb = &all.Messages[i]
+		}
+	}
+	if tomb == nil || !tomb.Deleted || tomb.DeletedAt == "" || tomb.DeleteReason != "posted by mistake" {
+		t.Fatalf("tombstone = %+v; want deleted with reason and deleted_at", tomb)
+	}
+	if all.Unread != plain.Unread {
+		t.Errorf("unread with tombstones = %d, want %d (tombstones are never unread)", all.Unread, plain.Unread)
+	}
+}
@@ -0,0 +1,91 @@
+package rpc
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/leonletto/thrum/internal/types"
+)
+
+// ForwardRequest represents the request for message.forward RPC. The
+// audience fields behave exactly as in message.send.
+type ForwardRequest struct {
+	MessageID     string        `json:"message_id"`
+	To            string        `json:"to,omitempty"`       // strict: agent_id or "everyone" only
+	Mentions      []string      `json:"mentions,omitempty"` // permissive: agent_id, role, or group
+	Scopes        []types.Scope `json:"scopes,omitempty"`
+	CallerAgentID string        `json:"caller_agent_id,omitempty"`
+}
+
+// HandleForward handles the message.forward RPC method. It sends a new
+// message quoting the original's content under an attribution header and
+// carrying a forwarded_from ref to the source message_id. The send itself
+// goes through HandleSend, so audience resolution, validation, and the
+// response shape are identical to message.send. Deleted messages cannot be
+// forwarded.
+func (h *MessageHandler) HandleForward(ctx context.Context, params json.RawMessage) (any, error) {
+	var req ForwardRequest
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	req.MessageID = strings.TrimSpace(req.MessageID)
+	if req.MessageID == "" {
+		return nil, fmt.Errorf("message_id is required")
+	}
+	if req.To == "" && len(req.Mentions) == 0 && len(req.Scopes) == 0 {
+		return nil, fmt.Errorf("a recipient is required (to, mentions, or scopes)")
+	}
+
+	var authorID, createdAt, content string
+	var deleted int
+	h.state.RLock()
+	err := h.state.DB().QueryRowContext(ctx,
+		`SELECT agent_id, created_at, body_content, deleted FROM messages WHERE message_id = ?`,
+		req.MessageID,
+	).Scan(&authorID, &createdAt, &content, &deleted)
+	h.state.RUnlock()
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("message not found: %s", req.MessageID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query message: %w", err)
+	}
+	if deleted != 0 {
+		return nil, fmt.Errorf("cannot forward deleted message: %s", req.MessageID)
+	}
+
+	sendParams, err := json.Marshal(SendRequest{
+		Content:       forwardContent(req.MessageID, authorID, createdAt, content),
+		To:            req.To,
+		Mentions:      req.Mentions,
+		Scopes:        req.Scopes,
+		Refs:          []types.Ref{{Type: "forwarded_from", Value: req.MessageID}},
+		CallerAgentID: req.CallerAgentID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshal send request: %w", err)
+	}
+	return h.HandleSend(ctx, sendParams)
+}
+
+// forwardContent builds the forwarded body: an attribution line followed by
+// the original content as a markdown blockquote.
+func forwardContent(messageID, authorID, createdAt, content string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Forwarded from @%s (%s, %s):\n\n", authorID, messageID, createdAt)
+	for i, line := range strings.Split(content, "\n") {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		if line == "" {
+			b.WriteString(">")
+		} else {
+			b.WriteString("> " + line)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,78 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestMessageForward(t *testing.T) {
+	handler, agentID, cleanup := setupFilterTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	sendParams, _ := json.Marshal(SendRequest{Content: "disk is full\n\non db-2", To: agentID, CallerAgentID: agentID})
+	sendResp, err := handler.HandleSend(ctx, sendParams)
+	if err != nil {
+		t.Fatalf("HandleSend: %v", err)
+	}
+	srcID := sendResp.(*SendResponse).MessageID
+
+	forward := func(req ForwardRequest) (*SendResponse, error) {
+		t.Helper()
+		req.CallerAgentID = agentID
+		params, _ := json.Marshal(req)
+		resp, err := handler.HandleForward(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		return resp.(*SendResponse), nil
+	}
+
+	fwd, err := forward(ForwardRequest{MessageID: srcID, Mentions: []string{"@ops"}})
+	if err != nil {
+		t.Fatalf("HandleForward: %v", err)
+	}
+	if fwd.MessageID == srcID {
+		t.Fatal("forward should create a new message")
+	}
+	if len(fwd.Audiences) != 1 || fwd.Audiences[0].Value != "ops" {
+		t.Errorf("audiences = %+v, want the forwarded audience @ops", fwd.Audiences)
+	}
+
+	getParams, _ := json.Marshal(GetMessageRequest{MessageID: fwd.MessageID})
+	getResp, err := handler.HandleGet(ctx, getParams)
+	if err != nil {
+		t.Fatalf("HandleGet: %v", err)
+	}
+	msg := getResp.(*GetMessageResponse).Message
+	wantBody := "Forwarded from @" + agentID + " (" + srcID + ", "
+	if !strings.HasPrefix(msg.Body.Content, wantBody) || !strings.HasSuffix(msg.Body.Content, "> disk is full\n>\n> on db-2") {
+		t.Errorf("body = %q, want attribution header and quoted original", msg.Body.Content)
+	}
+	found := false
+	for _, ref := range msg.Refs {
+		if ref.Type == "forwarded_from" && ref.Value == srcID {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("refs = %+v, want forwarded_from:%s", msg.Refs, srcID)
+	}
+
+	if _, err := forward(ForwardRequest{MessageID: srcID}); err == nil || !strings.Contains(err.Error(), "recipient") {
+		t.Errorf("forward without audience: err = %v, want recipient error", err)
+	}
+	if _, err := forward(ForwardRequest{MessageID: "msg_NONEXISTENT", To: agentID}); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("forward unknown message: err = %v, want not found", err)
+	}
+
+	delParams, _ := json.Marshal(DeleteMessageRequest{MessageID: srcID, CallerAgentID: agentID})
+	if _, err := handler.HandleDelete(ctx, delParams); err != nil {
+		t.Fatalf("HandleDelete: %v", err)
+	}
+	if _, err := forward(ForwardRequest{MessageID: srcID, To: agentID}); err == nil || !strings.Contains(err.Error(), "cannot forward deleted message") {
+		t.Errorf("forward deleted message: err = %v, want deleted error", err)
+	}
+}
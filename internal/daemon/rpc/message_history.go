@@ -0,0 +1,97 @@
+package rpc
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// MessageHistoryRequest represents the request for message.history RPC.
+type MessageHistoryRequest struct {
+	MessageID string `json:"message_id"`
+}
+
+// MessageHistoryResponse represents the response from message.history RPC.
+type MessageHistoryResponse struct {
+	MessageID string           `json:"message_id"`
+	AgentID   string           `json:"agent_id"`
+	Deleted   bool             `json:"deleted"`
+	Versions  []MessageVersion `json:"versions"` // Oldest first; version 0 is the original
+}
+
+// MessageVersion is one revision of a message body.
+type MessageVersion struct {
+	Version    int    `json:"version"`
+	Content    string `json:"content"`
+	Structured string `json:"structured,omitempty"`
+	Timestamp  string `json:"timestamp"`           // created_at for version 0, edited_at after
+	EditedBy   string `json:"edited_by,omitempty"` // Session that made the edit (empty for version 0)
+}
+
+// HandleHistory handles the message.history RPC method. The original body is
+// reconstructed from the first message_edits row's old_content; each edit row
+// then contributes one version carrying its new_content. A message that was
+// never edited has a single version 0 holding its current body.
+func (h *MessageHandler) HandleHistory(ctx context.Context, params json.RawMessage) (any, error) {
+	var req MessageHistoryRequest
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	if req.MessageID == "" {
+		return nil, fmt.Errorf("message_id is required")
+	}
+
+	h.state.RLock()
+	defer h.state.RUnlock()
+
+	resp := &MessageHistoryResponse{MessageID: req.MessageID}
+	var original MessageVersion
+	var structured sql.NullString
+	var deleted int
+	err := h.state.DB().QueryRowContext(ctx,
+		`SELECT agent_id, created_at, body_content, body_structured, deleted
+		 FROM messages WHERE message_id = ?`, req.MessageID,
+	).Scan(&resp.AgentID, &original.Timestamp, &original.Content, &structured, &deleted)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("message not found: %s", req.MessageID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query message: %w", err)
+	}
+	original.Structured = structured.String
+	resp.Deleted = deleted != 0
+
+	rows, err := h.state.DB().QueryContext(ctx,
+		`SELECT edited_at, edited_by, old_content, new_content, old_structured, new_structured
+		 FROM message_edits WHERE message_id = ?
+		 ORDER BY edited_at, id`, req.MessageID)
+	if err != nil {
+		return nil, fmt.Errorf("query edits: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var edits []MessageVersion
+	for rows.Next() {
+		var v MessageVersion
+		var oldContent, newContent, oldStructured, newStructured sql.NullString
+		if err := rows.Scan(&v.Timestamp, &v.EditedBy, &oldContent, &newContent, &oldStructured, &newStructured); err != nil {
+			return nil, fmt.Errorf("scan edit: %w", err)
+		}
+		if len(edits) == 0 {
+			original.Content = oldContent.String
+			original.Structured = oldStructured.String
+		}
+		v.Version = len(edits) + 1
+		v.Content = newContent.String
+		v.Structured = newStructured.String
+		edits = append(edits, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate edits: %w", err)
+	}
+
+	resp.Versions = append([]MessageVersion{original}, edits...)
+	return resp, nil
+}
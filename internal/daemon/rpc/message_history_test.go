@@ -0,0 +1,63 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestMessageHistory(t *testing.T) {
+	handler, agentID, cleanup := setupFilterTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	sendParams, _ := json.Marshal(SendRequest{Content: "v0", CallerAgentID: agentID})
+	sendResp, err := handler.HandleSend(ctx, sendParams)
+	if err != nil {
+		t.Fatalf("HandleSend: %v", err)
+	}
+	msgID := sendResp.(*SendResponse).MessageID
+
+	history := func() *MessageHistoryResponse {
+		t.Helper()
+		params, _ := json.Marshal(MessageHistoryRequest{MessageID: msgID})
+		resp, err := handler.HandleHistory(ctx, params)
+		if err != nil {
+			t.Fatalf("HandleHistory: %v", err)
+		}
+		return resp.(*MessageHistoryResponse)
+	}
+
+	if got := history().Versions; len(got) != 1 || got[0].Content != "v0" || got[0].Version != 0 {
+		t.Fatalf("unedited history = %+v, want single version 0 %q", got, "v0")
+	}
+
+	for _, content := range []string{"v1", "v2"} {
+		params, _ := json.Marshal(EditRequest{MessageID: msgID, Content: content, CallerAgentID: agentID})
+		if _, err := handler.HandleEdit(ctx, params); err != nil {
+			t.Fatalf("HandleEdit(%s): %v", content, err)
+		}
+	}
+
+	resp := history()
+	if resp.AgentID != agentID {
+		t.Errorf("AgentID = %q, want %q", resp.AgentID, agentID)
+	}
+	if len(resp.Versions) != 3 {
+		t.Fatalf("versions = %d, want 3 (original + two edits)", len(resp.Versions))
+	}
+	for i, want := range []string{"v0", "v1", "v2"} {
+		v := resp.Versions[i]
+		if v.Version != i || v.Content != want {
+			t.Errorf("versions[%d] = {%d %q}, want {%d %q}", i, v.Version, v.Content, i, want)
+		}
+		if i > 0 && (v.EditedBy == "" || v.Timestamp < resp.Versions[i-1].Timestamp) {
+			t.Errorf("versions[%d] = %+v: missing editor or out of order", i, v)
+		}
+	}
+
+	params, _ := json.Marshal(MessageHistoryRequest{MessageID: "msg_NONEXISTENT"})
+	if _, err := handler.HandleHistory(ctx, params); err == nil {
+		t.Error("expected error for unknown message")
+	}
+}
@@ -0,0 +1,106 @@
+package rpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// idempotencyWindow is how long a message.send idempotency key is
+// remembered. A retry after the window sends a new message.
+const idempotencyWindow = 24 * time.Hour
+
+// sendRequestHash fingerprints a send request so a reused idempotency key
+// can be checked against the request it was first used with. The key and
+// caller are left out; everything else must match.
+func sendRequestHash(req SendRequest) (string, error) {
+	req.IdempotencyKey = ""
+	req.CallerAgentID = ""
+	data, err := json.Marshal(req)
+	if err != nil {
+		return "", fmt.Errorf("hash send request: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// claimIdempotencyKey reserves req.IdempotencyKey for agentID before a send.
+// If the key was already used inside the window it returns the original
+// response instead, or an error when the request differs from the first one
+// or the first send has not finished. Expired keys are swept here, so a key
+// past the window is claimed afresh.
+func (h *MessageHandler) claimIdempotencyKey(ctx context.Context, agentID string, req SendRequest) (*SendResponse, error) {
+	hash, err := sendRequestHash(req)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+
+	h.state.Lock()
+	defer h.state.Unlock()
+	db := h.state.DB()
+
+	if _, err := db.ExecContext(ctx,
+		`DELETE FROM send_idempotency WHERE created_at < ?`,
+		now.Add(-idempotencyWindow).Format(time.RFC3339Nano),
+	); err != nil {
+		return nil, fmt.Errorf("expire idempotency keys: %w", err)
+	}
+
+	var storedHash, stored string
+	err = db.QueryRowContext(ctx,
+		`SELECT request_hash, response FROM send_idempotency WHERE agent_id = ? AND idempotency_key = ?`,
+		agentID, req.IdempotencyKey,
+	).Scan(&storedHash, &stored)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		if _, err := db.ExecContext(ctx,
+			`INSERT INTO send_idempotency (agent_id, idempotency_key, request_hash, created_at) VALUES (?, ?, ?, ?)`,
+			agentID, req.IdempotencyKey, hash, now.Format(time.RFC3339Nano),
+		); err != nil {
+			return nil, fmt.Errorf("record idempotency key: %w", err)
+		}
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("query idempotency key: %w", err)
+	}
+
+	if storedHash != hash {
+		return nil, fmt.Errorf("idempotency key %q was already used with a different request", req.IdempotencyKey)
+	}
+	if stored == "" {
+		return nil, fmt.Errorf("a send with idempotency key %q is still in progress", req.IdempotencyKey)
+	}
+	var resp SendResponse
+	if err := json.Unmarshal([]byte(stored), &resp); err != nil {
+		return nil, fmt.Errorf("decode stored response for idempotency key %q: %w", req.IdempotencyKey, err)
+	}
+	return &resp, nil
+}
+
+// finishIdempotencyKey settles a key claimed by claimIdempotencyKey. A
+// successful send stores its response for retries to return; a failed one
+// releases the key so the caller can retry with it. It runs even when the
+// caller has gone away, since that is the case a retry is for.
+func (h *MessageHandler) finishIdempotencyKey(ctx context.Context, agentID, key string, resp *SendResponse) {
+	ctx = context.WithoutCancel(ctx)
+	h.state.Lock()
+	defer h.state.Unlock()
+	db := h.state.DB()
+
+	if resp != nil {
+		if data, err := json.Marshal(resp); err == nil {
+			_, _ = db.ExecContext(ctx,
+				`UPDATE send_idempotency SET response = ? WHERE agent_id = ? AND idempotency_key = ?`,
+				string(data), agentID, key)
+			return
+		}
+	}
+	_, _ = db.ExecContext(ctx,
+		`DELETE FROM send_idempotency WHERE agent_id = ? AND idempotency_key = ?`, agentID, key)
+}
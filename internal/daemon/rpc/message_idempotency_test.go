@@ -0,0 +1,85 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leonletto/thrum/internal/identity"
+)
+
+func TestMessageSend_IdempotencyKey(t *testing.T) {
+	handler, reviewerID, cleanup := setupFilterTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	opsID := identity.GenerateAgentID("r_FILTER_TEST", "ops", "core", "")
+	send := func(content, key, caller string) (*SendResponse, error) {
+		t.Helper()
+		params, _ := json.Marshal(SendRequest{Content: content, Mentions: []string{"@reviewer"}, IdempotencyKey: key, CallerAgentID: caller})
+		resp, err := handler.HandleSend(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		return resp.(*SendResponse), nil
+	}
+	countMessages := func() int {
+		t.Helper()
+		var n int
+		if err := handler.state.RawDB().QueryRow(`SELECT COUNT(*) FROM messages`).Scan(&n); err != nil {
+			t.Fatalf("count messages: %v", err)
+		}
+		return n
+	}
+
+	first, err := send("deploy done", "k1", opsID)
+	if err != nil {
+		t.Fatalf("first send: %v", err)
+	}
+	retry, err := send("deploy done", "k1", opsID)
+	if err != nil {
+		t.Fatalf("retried send: %v", err)
+	}
+	if retry.MessageID != first.MessageID || retry.CreatedAt != first.CreatedAt {
+		t.Errorf("retry = %s at %s, want the original %s at %s", retry.MessageID, retry.CreatedAt, first.MessageID, first.CreatedAt)
+	}
+	if n := countMessages(); n != 1 {
+		t.Errorf("messages after retry = %d, want 1", n)
+	}
+
+	if _, err := send("something else", "k1", opsID); err == nil || !strings.Contains(err.Error(), "different request") {
+		t.Errorf("reused key with different content: err = %v, want a different-request error", err)
+	}
+
+	// Keys are per caller: another agent's k1 is a new send.
+	other, err := send("deploy done", "k1", reviewerID)
+	if err != nil {
+		t.Fatalf("send from another agent: %v", err)
+	}
+	if other.MessageID == first.MessageID {
+		t.Error("another agent's key returned the first agent's message")
+	}
+
+	// Past the window the key is forgotten and sends again.
+	expired := time.Now().UTC().Add(-idempotencyWindow - time.Minute).Format(time.RFC3339Nano)
+	if _, err := handler.state.RawDB().Exec(`UPDATE send_idempotency SET created_at = ?`, expired); err != nil {
+		t.Fatalf("age keys: %v", err)
+	}
+	again, err := send("deploy done", "k1", opsID)
+	if err != nil {
+		t.Fatalf("send after expiry: %v", err)
+	}
+	if again.MessageID == first.MessageID {
+		t.Error("expired key returned the original message, want a new send")
+	}
+
+	// A failed send releases the key.
+	if _, err := send("", "k2", opsID); err == nil {
+		t.Fatal("empty send succeeded, want an error")
+	}
+	if _, err := send("now with content", "k2", opsID); err != nil {
+		t.Errorf("retry after a failed send: %v", err)
+	}
+}
@@ -0,0 +1,123 @@
+package rpc
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/leonletto/thrum/internal/types"
+)
+
+// MoveRequest represents the request for message.move RPC.
+type MoveRequest struct {
+	MessageID     string `json:"message_id"`
+	ThreadID      string `json:"thread_id"` // existing thread to move the message into
+	CallerAgentID string `json:"caller_agent_id,omitempty"`
+}
+
+// MoveResponse represents the response from message.move RPC.
+type MoveResponse struct {
+	MessageID        string `json:"message_id"`
+	ThreadID         string `json:"thread_id"`
+	PreviousThreadID string `json:"previous_thread_id,omitempty"` // empty when the message had no thread
+	Changed          bool   `json:"changed"`                      // false when it was already in the thread
+}
+
+// HandleMove handles the message.move RPC method. It reassigns a message to
+// an existing thread. Only the author may move a message, and deleted
+// messages cannot be moved. The message's refs, including reply_to, are
+// left as they are. Both the old and the new thread get a thread.updated
+// notification.
+func (h *MessageHandler) HandleMove(ctx context.Context, params json.RawMessage) (any, error) {
+	var req MoveRequest
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	req.MessageID = strings.TrimSpace(req.MessageID)
+	req.ThreadID = strings.TrimSpace(req.ThreadID)
+	if req.MessageID == "" {
+		return nil, fmt.Errorf("message_id is required")
+	}
+	if req.ThreadID == "" {
+		return nil, fmt.Errorf("thread_id is required")
+	}
+
+	agentID, _, err := h.resolveAgentAndSession(ctx, req.CallerAgentID)
+	if err != nil {
+		return nil, fmt.Errorf("resolve agent and session: %w", err)
+	}
+
+	// Validate and write under one lock so the thread can't vanish between
+	// the existence check and the event.
+	h.state.Lock()
+	var authorID string
+	var oldThread sql.NullString
+	var deleted int
+	err = h.state.DB().QueryRowContext(ctx,
+		`SELECT agent_id, thread_id, deleted FROM messages WHERE message_id = ?`, req.MessageID,
+	).Scan(&authorID, &oldThread, &deleted)
+	if errors.Is(err, sql.ErrNoRows) {
+		h.state.Unlock()
+		return nil, fmt.Errorf("message not found: %s", req.MessageID)
+	}
+	if err != nil {
+		h.state.Unlock()
+		return nil, fmt.Errorf("query message: %w", err)
+	}
+	if deleted == 1 {
+		h.state.Unlock()
+		return nil, fmt.Errorf("cannot move deleted message: %s", req.MessageID)
+	}
+	if authorID != agentID {
+		h.state.Unlock()
+		return nil, fmt.Errorf("only message author can move (author: %s, current: %s)", authorID, agentID)
+	}
+
+	resp := &MoveResponse{
+		MessageID:        req.MessageID,
+		ThreadID:         req.ThreadID,
+		PreviousThreadID: oldThread.String,
+	}
+	if oldThread.String == req.ThreadID {
+		h.state.Unlock()
+		return resp, nil
+	}
+
+	var exists bool
+	if err := h.state.DB().QueryRowContext(ctx,
+		`SELECT EXISTS(SELECT 1 FROM messages WHERE thread_id = ?)`, req.ThreadID,
+	).Scan(&exists); err != nil {
+		h.state.Unlock()
+		return nil, fmt.Errorf("query thread: %w", err)
+	}
+	if !exists {
+		h.state.Unlock()
+		return nil, fmt.Errorf("thread not found: %s", req.ThreadID)
+	}
+
+	event := types.MessageMoveEvent{
+		Type:      "message.move",
+		Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
+		MessageID: req.MessageID,
+		ThreadID:  req.ThreadID,
+		AgentID:   agentID,
+	}
+	postCommit, err := h.state.WriteEvent(ctx, event)
+	h.state.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("write message.move event: %w", err)
+	}
+	h.state.GoPostCommit(postCommit)
+	resp.Changed = true
+
+	if resp.PreviousThreadID != "" {
+		_ = h.emitThreadUpdated(ctx, resp.PreviousThreadID)
+	}
+	_ = h.emitThreadUpdated(ctx, req.ThreadID)
+
+	return resp, nil
+}
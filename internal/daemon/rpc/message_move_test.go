@@ -0,0 +1,90 @@
+package rpc
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/leonletto/thrum/internal/identity"
+)
+
+func TestMessageMove(t *testing.T) {
+	handler, agentID, cleanup := setupFilterTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	opsID := identity.GenerateAgentID("r_FILTER_TEST", "ops", "core", "")
+
+	send := func(content, replyTo, caller string) *SendResponse {
+		t.Helper()
+		params, _ := json.Marshal(SendRequest{Content: content, To: "@" + agentID, ReplyTo: replyTo, CallerAgentID: caller})
+		resp, err := handler.HandleSend(ctx, params)
+		if err != nil {
+			t.Fatalf("send %q: %v", content, err)
+		}
+		return resp.(*SendResponse)
+	}
+	move := func(msgID, threadID, caller string) (*MoveResponse, error) {
+		t.Helper()
+		params, _ := json.Marshal(MoveRequest{MessageID: msgID, ThreadID: threadID, CallerAgentID: caller})
+		resp, err := handler.HandleMove(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		return resp.(*MoveResponse), nil
+	}
+	threadOf := func(msgID string) string {
+		t.Helper()
+		var threadID sql.NullString
+		if err := handler.state.DB().QueryRowContext(ctx, `SELECT thread_id FROM messages WHERE message_id = ?`, msgID).Scan(&threadID); err != nil {
+			t.Fatalf("query thread_id: %v", err)
+		}
+		return threadID.String
+	}
+
+	rootA := send("release plan", "", agentID).MessageID
+	reply := send("misplaced follow-up", rootA, agentID)
+	rootB := send("incident notes", "", agentID).MessageID
+	threadB := send("status update", rootB, agentID).ThreadID
+	loose := send("no thread yet", "", agentID).MessageID
+
+	resp, err := move(reply.MessageID, threadB, agentID)
+	if err != nil {
+		t.Fatalf("move reply: %v", err)
+	}
+	if !resp.Changed || resp.PreviousThreadID != reply.ThreadID || resp.ThreadID != threadB {
+		t.Errorf("move = %+v; want changed from %s to %s", resp, reply.ThreadID, threadB)
+	}
+	if got := threadOf(reply.MessageID); got != threadB {
+		t.Errorf("thread_id after move = %q, want %q", got, threadB)
+	}
+	var replyTo string
+	if err := handler.state.DB().QueryRowContext(ctx,
+		`SELECT ref_value FROM message_refs WHERE message_id = ? AND ref_type = 'reply_to'`, reply.MessageID,
+	).Scan(&replyTo); err != nil || replyTo != rootA {
+		t.Errorf("reply_to after move = %q, %v; want %s", replyTo, err, rootA)
+	}
+
+	if resp, err := move(reply.MessageID, threadB, agentID); err != nil || resp.Changed {
+		t.Errorf("repeat move = %+v, %v; want unchanged", resp, err)
+	}
+
+	if resp, err := move(loose, threadB, agentID); err != nil || !resp.Changed || resp.PreviousThreadID != "" {
+		t.Errorf("move unthreaded = %+v, %v; want changed with no previous thread", resp, err)
+	}
+
+	if _, err := move(rootA, "thr_missing", agentID); err == nil || !strings.Contains(err.Error(), "thread not found") {
+		t.Errorf("move to missing thread: err = %v", err)
+	}
+
+	opsMsg := send("from ops", "", opsID).MessageID
+	if _, err := move(opsMsg, threadB, agentID); err == nil || !strings.Contains(err.Error(), "only message author can move") {
+		t.Errorf("move by non-author: err = %v", err)
+	}
+
+	if _, err := move("msg_missing", threadB, agentID); err == nil || !strings.Contains(err.Error(), "message not found") {
+		t.Errorf("move missing message: err = %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/leonletto/thrum/internal/identity"
+)
+
+func TestMessagePin(t *testing.T) {
+	handler, agentID, cleanup := setupFilterTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	opsID := identity.GenerateAgentID("r_FILTER_TEST", "ops", "core", "")
+
+	send := func(content string) string {
+		t.Helper()
+		params, _ := json.Marshal(SendRequest{Content: content, To: "@" + agentID, CallerAgentID: opsID})
+		resp, err := handler.HandleSend(ctx, params)
+		if err != nil {
+			t.Fatalf("send %q: %v", content, err)
+		}
+		return resp.(*SendResponse).MessageID
+	}
+	decision := send("we ship on friday")
+	chatter := send("lunch?")
+
+	pin := func(msgID string, on bool) (*PinResponse, error) {
+		t.Helper()
+		params, _ := json.Marshal(PinRequest{MessageID: msgID, CallerAgentID: agentID})
+		var resp any
+		var err error
+		if on {
+			resp, err = handler.HandlePin(ctx, params)
+		} else {
+			resp, err = handler.HandleUnpin(ctx, params)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return resp.(*PinResponse), nil
+	}
+	list := func(req ListMessagesRequest) *ListMessagesResponse {
+		t.Helper()
+		req.CallerAgentID = agentID
+		req.ForAgent = agentID
+		params, _ := json.Marshal(req)
+		resp, err := handler.HandleList(ctx, params)
+		if err != nil {
+			t.Fatalf("HandleList(%+v): %v", req, err)
+		}
+		return resp.(*ListMessagesResponse)
+	}
+
+	if resp, err := pin(decision, true); err != nil || !resp.Pinned || !resp.Changed {
+		t.Fatalf("pin = %+v, %v; want pinned and changed", resp, err)
+	}
+	if resp, err := pin(decision, true); err != nil || !resp.Pinned || resp.Changed {
+		t.Errorf("re-pin = %+v, %v; want pinned and unchanged", resp, err)
+	}
+
+	all := list(ListMessagesRequest{})
+	if all.Total != 2 || all.PinnedCount != 1 {
+		t.Errorf("inbox total=%d pinned_count=%d, want 2 and 1", all.Total, all.PinnedCount)
+	}
+	pinned := list(ListMessagesRequest{Pinned: true})
+	if pinned.Total != 1 || pinned.Messages[0].MessageID != decision || !pinned.Messages[0].Pinned {
+		t.Errorf("pinned listing = %+v, want only %s marked pinned", pinned.Messages, decision)
+	}
+
+	// Unpinning a message that isn't pinned is a reported no-op.
+	if resp, err := pin(chatter, false); err != nil || resp.Pinned || resp.Changed {
+		t.Errorf("unpin unpinned = %+v, %v; want unpinned and unchanged", resp, err)
+	}
+	if resp, err := pin(decision, false); err != nil || resp.Pinned || !resp.Changed {
+		t.Errorf("unpin = %+v, %v; want unpinned and changed", resp, err)
+	}
+	if n := list(ListMessagesRequest{}).PinnedCount; n != 0 {
+		t.Errorf("pinned_count after unpin = %d, want 0", n)
+	}
+
+	// Deleting a pinned message unpins it, and it can't be pinned again.
+	if _, err := pin(decision, true); err != nil {
+		t.Fatalf("pin again: %v", err)
+	}
+	delParams, _ := json.Marshal(DeleteMessageRequest{MessageID: decision, CallerAgentID: opsID})
+	if _, err := handler.HandleDelete(ctx, delParams); err != nil {
+		t.Fatalf("HandleDelete: %v", err)
+	}
+	if n := list(ListMessagesRequest{}).PinnedCount; n != 0 {
+		t.Errorf("pinned_count after delete = %d, want 0", n)
+	}
+	if _, err := pin(decision, true); err == nil || !strings.Contains(err.Error(), "cannot pin deleted message") {
+		t.Errorf("pin deleted: err = %v, want cannot pin deleted message", err)
+	}
+	if _, err := pin("msg_NONEXISTENT", true); err == nil || !strings.Contains(err.Error(), "message not found") {
+		t.Errorf("pin unknown: err = %v, want message not found", err)
+	}
+}
@@ -0,0 +1,107 @@
+package rpc
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/leonletto/thrum/internal/identity"
+)
+
+func TestMessageReact(t *testing.T) {
+	handler, agentID, cleanup := setupFilterTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	opsID := identity.GenerateAgentID("r_FILTER_TEST", "ops", "core", "")
+
+	// ops sends to the reviewer; the reviewer reacts as a recipient.
+	sendParams, _ := json.Marshal(SendRequest{Content: "deploy done", To: agentID, CallerAgentID: opsID})
+	sendResp, err := handler.HandleSend(ctx, sendParams)
+	if err != nil {
+		t.Fatalf("HandleSend: %v", err)
+	}
+	msgID := sendResp.(*SendResponse).MessageID
+
+	react := func(caller, emoji string) *ReactResponse {
+		t.Helper()
+		params, _ := json.Marshal(ReactRequest{MessageID: msgID, Emoji: emoji, CallerAgentID: caller})
+		resp, err := handler.HandleReact(ctx, params)
+		if err != nil {
+			t.Fatalf("HandleReact(%s, %s): %v", caller, emoji, err)
+		}
+		return resp.(*ReactResponse)
+	}
+
+	resp := react(agentID, ":thumbsup:")
+	if resp.Removed {
+		t.Error("first reaction reported removed")
+	}
+	react(opsID, ":thumbsup:")
+	react(opsID, "👀")
+
+	getParams, _ := json.Marshal(GetMessageRequest{MessageID: msgID})
+	got, err := handler.HandleGet(ctx, getParams)
+	if err != nil {
+		t.Fatalf("HandleGet: %v", err)
+	}
+	reactions := got.(*GetMessageResponse).Message.Reactions
+	if len(reactions) != 2 || len(reactions[":thumbsup:"]) != 2 || len(reactions["👀"]) != 1 {
+		t.Fatalf("unexpected reactions: %v", reactions)
+	}
+
+	// Reactions are not reads: the reviewer's delivery row stays unread.
+	var readAt sql.NullString
+	if err := handler.state.RawDB().QueryRow(
+		`SELECT read_at FROM message_deliveries WHERE message_id = ? AND recipient_agent_id = ?`,
+		msgID, agentID).Scan(&readAt); err != nil {
+		t.Fatalf("query delivery: %v", err)
+	}
+	if readAt.Valid {
+		t.Errorf("reaction marked the message read (read_at=%s)", readAt.String)
+	}
+
+	// Reacting again with the same emoji toggles it off.
+	resp = react(agentID, ":thumbsup:")
+	if !resp.Removed {
+		t.Error("second reaction did not toggle off")
+	}
+	if agents := resp.Reactions[":thumbsup:"]; len(agents) != 1 || agents[0] != opsID {
+		t.Errorf(":thumbsup: after toggle = %v, want [%s]", agents, opsID)
+	}
+}
+
+func TestMessageReactValidation(t *testing.T) {
+	handler, agentID, cleanup := setupFilterTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	sendParams, _ := json.Marshal(SendRequest{Content: "hello", CallerAgentID: agentID})
+	sendResp, err := handler.HandleSend(ctx, sendParams)
+	if err != nil {
+		t.Fatalf("HandleSend: %v", err)
+	}
+	msgID := sendResp.(*SendResponse).MessageID
+
+	tests := []struct {
+		name      string
+		req       ReactRequest
+		wantError string
+	}{
+		{"missing emoji", ReactRequest{MessageID: msgID}, "emoji is required"},
+		{"whitespace in emoji", ReactRequest{MessageID: msgID, Emoji: "looks good"}, "invalid emoji"},
+		{"unknown message", ReactRequest{MessageID: "msg_NONEXISTENT", Emoji: ":+1:"}, "message not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.CallerAgentID = agentID
+			params, _ := json.Marshal(tt.req)
+			_, err := handler.HandleReact(ctx, params)
+			if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+				t.Errorf("HandleReact error = %v, want containing %q", err, tt.wantError)
+			}
+		})
+	}
+}
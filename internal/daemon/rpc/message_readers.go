@@ -0,0 +1,131 @@
+package rpc
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/leonletto/thrum/internal/recipientgate"
+)
+
+// MessageReadersRequest represents the request for message.readers RPC.
+type MessageReadersRequest struct {
+	MessageID string `json:"message_id"`
+}
+
+// MessageReadersResponse represents the response from message.readers RPC.
+type MessageReadersResponse struct {
+	MessageID string          `json:"message_id"`
+	AgentID   string          `json:"agent_id"` // Author
+	Read      []MessageReader `json:"read"`     // Oldest read first
+	Unread    []MessageReader `json:"unread"`   // Intended recipients with no read receipt, by agent ID
+}
+
+// MessageReader is one recipient of a message and when they read it.
+type MessageReader struct {
+	AgentID string `json:"agent_id"`
+	Role    string `json:"role,omitempty"`
+	ReadAt  string `json:"read_at,omitempty"`
+}
+
+// HandleReaders handles the message.readers RPC method. Intended recipients
+// are the registered agents, other than the author, that recipientgate
+// accepts for the message: mentioned by id or role, in a targeted group, or
+// anyone for a broadcast. The roster is read at query time, so an @everyone
+// message counts agents registered after it was sent. A read receipt from an
+// agent that no longer qualifies (deleted, or removed from the group) is
+// still listed under Read.
+func (h *MessageHandler) HandleReaders(ctx context.Context, params json.RawMessage) (any, error) {
+	var req MessageReadersRequest
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	if req.MessageID == "" {
+		return nil, fmt.Errorf("message_id is required")
+	}
+
+	h.state.RLock()
+	defer h.state.RUnlock()
+
+	db := h.state.DB()
+	resp := &MessageReadersResponse{
+		MessageID: req.MessageID,
+		Read:      []MessageReader{},
+		Unread:    []MessageReader{},
+	}
+	err := db.QueryRowContext(ctx,
+		`SELECT agent_id FROM messages WHERE message_id = ?`, req.MessageID,
+	).Scan(&resp.AgentID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("message not found: %s", req.MessageID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query message: %w", err)
+	}
+
+	rows, err := db.QueryContext(ctx,
+		`SELECT agent_id, role FROM agents WHERE agent_id != ? ORDER BY agent_id`, resp.AgentID)
+	if err != nil {
+		return nil, fmt.Errorf("query agents: %w", err)
+	}
+	var roster []MessageReader
+	for rows.Next() {
+		var r MessageReader
+		if err := rows.Scan(&r.AgentID, &r.Role); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("scan agent: %w", err)
+		}
+		roster = append(roster, r)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate agents: %w", err)
+	}
+
+	readAt := make(map[string]string)
+	rows, err = db.QueryContext(ctx,
+		`SELECT recipient_agent_id, read_at FROM message_deliveries
+		 WHERE message_id = ? AND recipient_agent_id != ? AND read_at IS NOT NULL
+		 ORDER BY read_at, recipient_agent_id`, req.MessageID, resp.AgentID)
+	if err != nil {
+		return nil, fmt.Errorf("query deliveries: %w", err)
+	}
+	var readOrder []string
+	for rows.Next() {
+		var agentID, at string
+		if err := rows.Scan(&agentID, &at); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("scan delivery: %w", err)
+		}
+		readAt[agentID] = at
+		readOrder = append(readOrder, agentID)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate deliveries: %w", err)
+	}
+
+	roles := make(map[string]string, len(roster))
+	for _, r := range roster {
+		roles[r.AgentID] = r.Role
+		if _, ok := readAt[r.AgentID]; ok {
+			continue
+		}
+		var intended bool
+		args := append(recipientgate.Args(r.AgentID), req.MessageID)
+		if err := db.QueryRowContext(ctx,
+			`SELECT `+recipientgate.Predicate+` FROM messages m WHERE m.message_id = ?`, args...,
+		).Scan(&intended); err != nil {
+			return nil, fmt.Errorf("check recipient %s: %w", r.AgentID, err)
+		}
+		if intended {
+			resp.Unread = append(resp.Unread, r)
+		}
+	}
+	for _, agentID := range readOrder {
+		resp.Read = append(resp.Read, MessageReader{AgentID: agentID, Role: roles[agentID], ReadAt: readAt[agentID]})
+	}
+	return resp, nil
+}
@@ -0,0 +1,87 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/leonletto/thrum/internal/identity"
+)
+
+func TestMessageReaders(t *testing.T) {
+	handler, agentID, cleanup := setupFilterTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	opsID := identity.GenerateAgentID("r_FILTER_TEST", "ops", "core", "")
+
+	send := func(mention string) string {
+		t.Helper()
+		params, _ := json.Marshal(SendRequest{Content: "heads up", Mentions: []string{mention}, CallerAgentID: agentID})
+		resp, err := handler.HandleSend(ctx, params)
+		if err != nil {
+			t.Fatalf("HandleSend(%s): %v", mention, err)
+		}
+		return resp.(*SendResponse).MessageID
+	}
+	readers := func(msgID string) *MessageReadersResponse {
+		t.Helper()
+		params, _ := json.Marshal(MessageReadersRequest{MessageID: msgID})
+		resp, err := handler.HandleReaders(ctx, params)
+		if err != nil {
+			t.Fatalf("HandleReaders: %v", err)
+		}
+		return resp.(*MessageReadersResponse)
+	}
+	ids := func(rs []MessageReader) []string {
+		out := make([]string, 0, len(rs))
+		for _, r := range rs {
+			out = append(out, r.AgentID)
+		}
+		return out
+	}
+
+	t.Run("mention tracks the recipient until read", func(t *testing.T) {
+		msgID := send("@ops")
+
+		resp := readers(msgID)
+		if resp.AgentID != agentID || len(resp.Read) != 0 || len(resp.Unread) != 1 || resp.Unread[0].AgentID != opsID {
+			t.Fatalf("before read: %+v, want only %s unread", resp, opsID)
+		}
+
+		params, _ := json.Marshal(MarkReadRequest{MessageIDs: []string{msgID}, CallerAgentID: opsID})
+		if _, err := handler.HandleMarkRead(ctx, params); err != nil {
+			t.Fatalf("HandleMarkRead: %v", err)
+		}
+
+		resp = readers(msgID)
+		if len(resp.Unread) != 0 || len(resp.Read) != 1 || resp.Read[0].AgentID != opsID || resp.Read[0].ReadAt == "" {
+			t.Errorf("after read: %+v, want %s read with a timestamp", resp, opsID)
+		}
+		if resp.Read[0].Role != "ops" {
+			t.Errorf("reader role = %q, want ops", resp.Read[0].Role)
+		}
+	})
+
+	t.Run("everyone uses the roster at query time", func(t *testing.T) {
+		msgID := send("@everyone")
+		if got := ids(readers(msgID).Unread); len(got) != 1 || got[0] != opsID {
+			t.Fatalf("unread = %v, want [%s] (author excluded)", got, opsID)
+		}
+
+		params, _ := json.Marshal(RegisterRequest{Role: "tester", Module: "core"})
+		if _, err := NewAgentHandler(handler.state).HandleRegister(ctx, params); err != nil {
+			t.Fatalf("register tester: %v", err)
+		}
+		if got := readers(msgID).Unread; len(got) != 2 {
+			t.Errorf("unread = %v, want ops plus the newly registered tester", ids(got))
+		}
+	})
+
+	t.Run("unknown message", func(t *testing.T) {
+		params, _ := json.Marshal(MessageReadersRequest{MessageID: "msg_NONEXISTENT"})
+		if _, err := handler.HandleReaders(ctx, params); err == nil {
+			t.Error("expected error for unknown message")
+		}
+	})
+}
@@ -241,6 +241,92 @@ func TestAutoThreadOnReply(t *testing.T) {
 	}
 }
 
+// TestAutoThreadRecordsParentMove pins that a new thread puts the parent in
+// it through a message.move event, not only in this daemon's projection, so
+// a rebuild or a peer sees the parent in the thread too.
+func TestAutoThreadRecordsParentMove(t *testing.T) {
+	handler, st, agentID := setupReplyTest(t)
+	rootID := sendTestMessage(t, handler, "Root message", agentID)
+
+	replyParams, _ := json.Marshal(SendRequest{Content: "Reply to root", ReplyTo: rootID, CallerAgentID: agentID})
+	replyResp, err := handler.HandleSend(context.Background(), replyParams)
+	if err != nil {
+		t.Fatalf("send reply: %v", err)
+	}
+	threadID := replyResp.(*SendResponse).ThreadID
+
+	var moved string
+	if err := st.RawDB().QueryRow(
+		`SELECT json_extract(event_json, '$.thread_id') FROM events
+		 WHERE type = 'message.move' AND json_extract(event_json, '$.message_id') = ?`, rootID,
+	).Scan(&moved); err != nil {
+		t.Fatalf("query message.move for root: %v", err)
+	}
+	if moved != threadID {
+		t.Errorf("message.move thread_id = %q, want %q", moved, threadID)
+	}
+
+	// Joining the thread afterwards moves nothing.
+	reply2Params, _ := json.Marshal(SendRequest{Content: "Second reply", ReplyTo: rootID, CallerAgentID: agentID})
+	if _, err := handler.HandleSend(context.Background(), reply2Params); err != nil {
+		t.Fatalf("send second reply: %v", err)
+	}
+	var moves int
+	if err := st.RawDB().QueryRow(`SELECT COUNT(*) FROM events WHERE type = 'message.move'`).Scan(&moves); err != nil {
+		t.Fatalf("count message.move: %v", err)
+	}
+	if moves != 1 {
+		t.Errorf("message.move events = %d, want 1", moves)
+	}
+}
+
+func TestReplyNoThread(t *testing.T) {
+	handler, st, agentID := setupReplyTest(t)
+	threadOf := func(msgID string) string {
+		t.Helper()
+		var tid string
+		if err := st.RawDB().QueryRow(
+			`SELECT COALESCE(thread_id, '') FROM messages WHERE message_id = ?`, msgID,
+		).Scan(&tid); err != nil {
+			t.Fatalf("query %s thread_id: %v", msgID, err)
+		}
+		return tid
+	}
+	reply := func(parentID string, noThread bool) *SendResponse {
+		t.Helper()
+		params, _ := json.Marshal(SendRequest{Content: "reply", ReplyTo: parentID, NoThread: noThread, CallerAgentID: agentID})
+		resp, err := handler.HandleSend(context.Background(), params)
+		if err != nil {
+			t.Fatalf("send reply: %v", err)
+		}
+		return resp.(*SendResponse)
+	}
+
+	// A flat parent stays flat, and so does the reply.
+	flatID := sendTestMessage(t, handler, "Flat message", agentID)
+	flatReply := reply(flatID, true)
+	if flatReply.ThreadID != "" || threadOf(flatID) != "" || threadOf(flatReply.MessageID) != "" {
+		t.Errorf("no-thread reply threads: parent %q, reply %q (response %q), want all empty",
+			threadOf(flatID), threadOf(flatReply.MessageID), flatReply.ThreadID)
+	}
+	getParams, _ := json.Marshal(GetMessageRequest{MessageID: flatReply.MessageID})
+	getResp, err := handler.HandleGet(context.Background(), getParams)
+	if err != nil {
+		t.Fatalf("get reply: %v", err)
+	}
+	if got := getResp.(*GetMessageResponse).Message.ReplyTo; got != flatID {
+		t.Errorf("no-thread reply reply_to = %q, want %q", got, flatID)
+	}
+
+	// A parent already in a thread is joined regardless.
+	rootID := sendTestMessage(t, handler, "Root message", agentID)
+	threadID := reply(rootID, false).ThreadID
+	joined := reply(rootID, true)
+	if joined.ThreadID != threadID || threadOf(joined.MessageID) != threadID {
+		t.Errorf("no-thread reply to a threaded parent joined %q, want %q", joined.ThreadID, threadID)
+	}
+}
+
 func TestAutoThreadJoinsExistingThread(t *testing.T) {
 	handler, st, agentID := setupReplyTest(t)
 
@@ -0,0 +1,105 @@
+package rpc
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/leonletto/thrum/internal/identity"
+)
+
+func TestMessageResolve(t *testing.T) {
+	handler, agentID, cleanup := setupFilterTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	opsID := identity.GenerateAgentID("r_FILTER_TEST", "ops", "core", "")
+
+	countMessages := func() int {
+		t.Helper()
+		var n int
+		if err := handler.state.DB().QueryRowContext(ctx, `SELECT COUNT(*) FROM messages`).Scan(&n); err != nil {
+			t.Fatalf("count messages: %v", err)
+		}
+		return n
+	}
+	resolve := func(req SendRequest) (*ResolveResponse, error) {
+		t.Helper()
+		req.CallerAgentID = opsID
+		params, _ := json.Marshal(req)
+		resp, err := handler.HandleResolve(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		return resp.(*ResolveResponse), nil
+	}
+
+	t.Run("broadcast writes nothing", func(t *testing.T) {
+		before := countMessages()
+		resp, err := resolve(SendRequest{Content: "deploy at noon", To: "@everyone"})
+		if err != nil {
+			t.Fatalf("HandleResolve: %v", err)
+		}
+		if resp.AgentID != opsID {
+			t.Errorf("AgentID = %q, want %q", resp.AgentID, opsID)
+		}
+		if !slices.Contains(resp.Recipients, agentID) || slices.Contains(resp.Recipients, opsID) {
+			t.Errorf("Recipients = %v, want %s and not the sender", resp.Recipients, agentID)
+		}
+		if len(resp.Scopes) != 1 || resp.Scopes[0].Type != "broadcast" {
+			t.Errorf("Scopes = %+v, want broadcast:everyone", resp.Scopes)
+		}
+		if got := countMessages(); got != before {
+			t.Errorf("dry run wrote messages: %d → %d", before, got)
+		}
+	})
+
+	t.Run("unknown recipient errors like send", func(t *testing.T) {
+		req := SendRequest{Content: "hi", Mentions: []string{"@nobody"}, CallerAgentID: opsID}
+		params, _ := json.Marshal(req)
+		_, sendErr := handler.HandleSend(ctx, params)
+		_, resolveErr := resolve(req)
+		if sendErr == nil || resolveErr == nil {
+			t.Fatalf("expected both to fail; send=%v resolve=%v", sendErr, resolveErr)
+		}
+		if sendErr.Error() != resolveErr.Error() {
+			t.Errorf("resolve error %q differs from send error %q", resolveErr, sendErr)
+		}
+	})
+
+	t.Run("reply previews thread without creating it", func(t *testing.T) {
+		params, _ := json.Marshal(SendRequest{Content: "parent", To: "@" + opsID, CallerAgentID: agentID})
+		sent, err := handler.HandleSend(ctx, params)
+		if err != nil {
+			t.Fatalf("send parent: %v", err)
+		}
+		parentID := sent.(*SendResponse).MessageID
+
+		resp, err := resolve(SendRequest{Content: "reply", To: "@" + agentID, ReplyTo: parentID})
+		if err != nil {
+			t.Fatalf("HandleResolve: %v", err)
+		}
+		if !resp.NewThread || resp.ThreadID != "" {
+			t.Errorf("NewThread=%v ThreadID=%q, want new thread", resp.NewThread, resp.ThreadID)
+		}
+		last := resp.Refs[len(resp.Refs)-1]
+		if last.Type != "reply_to" || last.Value != parentID {
+			t.Errorf("last ref = %+v, want reply_to:%s", last, parentID)
+		}
+
+		var threadID sql.NullString
+		if err := handler.state.DB().QueryRowContext(ctx,
+			`SELECT thread_id FROM messages WHERE message_id = ?`, parentID).Scan(&threadID); err != nil {
+			t.Fatal(err)
+		}
+		if threadID.Valid && threadID.String != "" {
+			t.Errorf("dry run threaded the parent: thread_id = %q", threadID.String)
+		}
+
+		if _, err := resolve(SendRequest{Content: "reply", To: "@" + agentID, ReplyTo: "msg_missing"}); err == nil {
+			t.Error("expected error for missing reply_to parent")
+		}
+	})
+}
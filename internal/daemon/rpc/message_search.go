@@ -0,0 +1,168 @@
+package rpc
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/leonletto/thrum/internal/types"
+)
+
+// SearchMessagesRequest represents the request for message.search RPC.
+type SearchMessagesRequest struct {
+	Query    string       `json:"query"`               // Whitespace-separated terms; all must match
+	AuthorID string       `json:"author_id,omitempty"` // Filter by author
+	Scope    *types.Scope `json:"scope,omitempty"`     // Filter by scope
+	Limit    int          `json:"limit,omitempty"`     // Default 20, max 100
+}
+
+// SearchMessagesResponse represents the response from message.search RPC.
+type SearchMessagesResponse struct {
+	Messages []MessageSummary `json:"messages"`
+}
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+
+	// snippetContext is how many bytes of body are kept on each side of the
+	// first match in SearchMessagesResponse snippets.
+	snippetContext = 40
+)
+
+// HandleSearch handles the message.search RPC method. It queries the
+// messages_fts index ranked by bm25, newest first among equal ranks.
+// Deleted messages never match, nor do messages message.list hides by
+// time: scheduled ones before their deliver_at and expired TTL ones.
+func (h *MessageHandler) HandleSearch(ctx context.Context, params json.RawMessage) (any, error) {
+	var req SearchMessagesRequest
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	terms := strings.Fields(req.Query)
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("query is required")
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+
+	h.state.RLock()
+	defer h.state.RUnlock()
+
+	query := `SELECT m.message_id, m.thread_id, m.agent_id, m.created_at,
+	                 m.body_format, m.body_content, m.body_structured
+	          FROM messages_fts
+	          JOIN messages m ON m.message_id = messages_fts.message_id
+	          WHERE messages_fts MATCH ? AND m.deleted = 0`
+	args := []any{ftsMatchExpr(terms)}
+	visClause, visArgs := visibilityClause(time.Now(), false)
+	query += visClause
+	args = append(args, visArgs...)
+
+	if req.AuthorID != "" {
+		query += ` AND m.agent_id = ?`
+		args = append(args, req.AuthorID)
+	}
+	if req.Scope != nil {
+		query += ` AND EXISTS (SELECT 1 FROM message_scopes s
+		                       WHERE s.message_id = m.message_id AND s.scope_type = ? AND s.scope_value = ?)`
+		args = append(args, req.Scope.Type, req.Scope.Value)
+	}
+
+	query += ` ORDER BY bm25(messages_fts), m.created_at DESC LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := h.state.DB().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("search messages: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	messages := []MessageSummary{}
+	for rows.Next() {
+		var msg MessageSummary
+		var threadID, bodyStructured sql.NullString
+		if err := rows.Scan(
+			&msg.MessageID, &threadID, &msg.AgentID, &msg.CreatedAt,
+			&msg.Body.Format, &msg.Body.Content, &bodyStructured,
+		); err != nil {
+			return nil, fmt.Errorf("scan search result: %w", err)
+		}
+		msg.ThreadID = threadID.String
+		msg.Body.Structured = bodyStructured.String
+		msg.Snippet = searchSnippet(msg.Body.Content, terms)
+		messages = append(messages, msg)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate search results: %w", err)
+	}
+
+	return &SearchMessagesResponse{Messages: messages}, nil
+}
+
+// ftsMatchExpr quotes each term as an FTS5 string so user input containing
+// FTS operators or punctuation ("foo-bar", "a:b", quotes) can't produce a
+// syntax error. Adjacent strings are ANDed.
+func ftsMatchExpr(terms []string) string {
+	quoted := make([]string, len(terms))
+	for i, term := range terms {
+		quoted[i] = `"` + strings.ReplaceAll(term, `"`, `""`) + `"`
+	}
+	return strings.Join(quoted, " ")
+}
+
+// searchSnippet returns the body around the earliest case-insensitive
+// occurrence of any term, whitespace-collapsed onto one line, with "…" where
+// the body was cut. With no literal occurrence (FTS5 tokenizer folding) it
+// falls back to the start of the body.
+func searchSnippet(content string, terms []string) string {
+	start, end := -1, -1
+	for _, term := range terms {
+		if i := indexFold(content, term); i >= 0 && (start < 0 || i < start) {
+			start, end = i, i+len(term)
+		}
+	}
+	if start < 0 {
+		start, end = 0, 0
+	}
+
+	from := max(start-snippetContext, 0)
+	for from > 0 && !utf8.RuneStart(content[from]) {
+		from--
+	}
+	to := min(end+snippetContext, len(content))
+	for to < len(content) && !utf8.RuneStart(content[to]) {
+		to++
+	}
+
+	snippet := strings.Join(strings.Fields(content[from:to]), " ")
+	if from > 0 {
+		snippet = "…" + snippet
+	}
+	if to < len(content) {
+		snippet += "…"
+	}
+	return snippet
+}
+
+// indexFold is strings.Index with Unicode case folding.
+func indexFold(s, substr string) int {
+	n := len(substr)
+	for i := 0; i+n <= len(s); i++ {
+		if utf8.RuneStart(s[i]) && strings.EqualFold(s[i:i+n], substr) {
+			return i
+		}
+	}
+	return -1
+}
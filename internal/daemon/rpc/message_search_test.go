@@ -0,0 +1,122 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/leonletto/thrum/internal/identity"
+	"github.com/leonletto/thrum/internal/types"
+)
+
+func TestMessageSearch(t *testing.T) {
+	handler, agentID, cleanup := setupFilterTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	opsID := identity.GenerateAgentID("r_FILTER_TEST", "ops", "core", "")
+
+	send := func(author, content string, scopes ...types.Scope) string {
+		t.Helper()
+		params, _ := json.Marshal(SendRequest{Content: content, Scopes: scopes, CallerAgentID: author})
+		resp, err := handler.HandleSend(ctx, params)
+		if err != nil {
+			t.Fatalf("HandleSend: %v", err)
+		}
+		return resp.(*SendResponse).MessageID
+	}
+	search := func(req SearchMessagesRequest) *SearchMessagesResponse {
+		t.Helper()
+		params, _ := json.Marshal(req)
+		resp, err := handler.HandleSearch(ctx, params)
+		if err != nil {
+			t.Fatalf("HandleSearch(%+v): %v", req, err)
+		}
+		return resp.(*SearchMessagesResponse)
+	}
+	ids := func(resp *SearchMessagesResponse) []string {
+		out := make([]string, len(resp.Messages))
+		for i, m := range resp.Messages {
+			out[i] = m.MessageID
+		}
+		return out
+	}
+
+	leak := send(opsID, "Found a memory leak in the sync loop after the last deploy.")
+	leakAuth := send(agentID, "Memory usage is fine, but there is a leak in auth.", types.Scope{Type: "module", Value: "auth"})
+	send(agentID, "Unrelated status update.")
+	deleted := send(agentID, "memory leak duplicate report")
+	delParams, _ := json.Marshal(DeleteMessageRequest{MessageID: deleted, CallerAgentID: agentID})
+	if _, err := handler.HandleDelete(ctx, delParams); err != nil {
+		t.Fatalf("HandleDelete: %v", err)
+	}
+	// Scheduled and expired messages are hidden from search as from the inbox.
+	schedParams, _ := json.Marshal(SendRequest{Content: "memory leak scheduled report", Schedule: "+1h", CallerAgentID: agentID})
+	if _, err := handler.HandleSend(ctx, schedParams); err != nil {
+		t.Fatalf("HandleSend scheduled: %v", err)
+	}
+	expired := send(agentID, "memory leak expired report")
+	if _, err := handler.state.RawDB().Exec(`UPDATE messages SET expires_at = '2000-01-01T00:00:00Z' WHERE message_id = ?`, expired); err != nil {
+		t.Fatalf("expire message: %v", err)
+	}
+
+	resp := search(SearchMessagesRequest{Query: "memory leak"})
+	if got := ids(resp); len(got) != 2 {
+		t.Fatalf("hits = %v, want %s and %s (deleted, scheduled and expired messages excluded)", got, leak, leakAuth)
+	}
+	for _, m := range resp.Messages {
+		if !strings.Contains(strings.ToLower(m.Snippet), "memory") {
+			t.Errorf("snippet %q does not show the match", m.Snippet)
+		}
+	}
+
+	if got := ids(search(SearchMessagesRequest{Query: "memory leak", AuthorID: opsID})); len(got) != 1 || got[0] != leak {
+		t.Errorf("author filter hits = %v, want [%s]", got, leak)
+	}
+	scope := &types.Scope{Type: "module", Value: "auth"}
+	if got := ids(search(SearchMessagesRequest{Query: "leak", Scope: scope})); len(got) != 1 || got[0] != leakAuth {
+		t.Errorf("scope filter hits = %v, want [%s]", got, leakAuth)
+	}
+
+	// FTS operator characters in user input must not error.
+	search(SearchMessagesRequest{Query: `sync-loop "deploy" AND:`})
+
+	// Edits are re-indexed.
+	editParams, _ := json.Marshal(EditRequest{MessageID: leak, Content: "Fixed the goroutine backlog.", CallerAgentID: opsID})
+	if _, err := handler.HandleEdit(ctx, editParams); err != nil {
+		t.Fatalf("HandleEdit: %v", err)
+	}
+	if got := ids(search(SearchMessagesRequest{Query: "goroutine"})); len(got) != 1 || got[0] != leak {
+		t.Errorf("post-edit hits = %v, want [%s]", got, leak)
+	}
+	if got := ids(search(SearchMessagesRequest{Query: "deploy"})); len(got) != 0 {
+		t.Errorf("pre-edit text still matches: %v", got)
+	}
+
+	if _, err := handler.HandleSearch(ctx, json.RawMessage(`{"query":"  "}`)); err == nil {
+		t.Error("expected error for empty query")
+	}
+}
+
+func TestSearchSnippet(t *testing.T) {
+	long := strings.Repeat("lorem ipsum ", 10) + "the Memory\nleak was here " + strings.Repeat("dolor sit ", 10)
+
+	tests := []struct {
+		name    string
+		content string
+		terms   []string
+		want    string
+	}{
+		{"short body", "a memory leak", []string{"leak"}, "a memory leak"},
+		{"cut both sides", long, []string{"memory"}, "…lorem ipsum lorem ipsum lorem ipsum the Memory leak was here dolor sit dolor sit dolor…"},
+		{"no literal match", "résumé", []string{"resume"}, "résumé"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := searchSnippet(tt.content, tt.terms); got != tt.want {
+				t.Errorf("searchSnippet() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/leonletto/thrum/internal/identity"
+)
+
+// TestMessageList_SequenceOrder pins sort_by=sequence: messages merge across
+// daemons by origin sequence, and two daemons' messages that share a
+// sequence are ordered by origin daemon ID.
+func TestMessageList_SequenceOrder(t *testing.T) {
+	handler, _, cleanup := setupFilterTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	opsID := identity.GenerateAgentID("r_FILTER_TEST", "ops", "core", "")
+	localDaemon := handler.state.DaemonID()
+
+	for _, content := range []string{"local one", "local two"} {
+		params, _ := json.Marshal(SendRequest{Content: content, Mentions: []string{"@reviewer"}, CallerAgentID: opsID})
+		if _, err := handler.HandleSend(ctx, params); err != nil {
+			t.Fatalf("send %q: %v", content, err)
+		}
+	}
+	list := func(order string) []MessageSummary {
+		t.Helper()
+		params, _ := json.Marshal(ListMessagesRequest{PageSize: 100, SortBy: "sequence", SortOrder: order})
+		resp, err := handler.HandleList(ctx, params)
+		if err != nil {
+			t.Fatalf("HandleList: %v", err)
+		}
+		return resp.(*ListMessagesResponse).Messages
+	}
+
+	local := list("asc")
+	if len(local) != 2 || local[0].Sequence == 0 || local[0].Sequence >= local[1].Sequence {
+		t.Fatalf("local messages = %+v, want two with increasing sequences", local)
+	}
+	if local[0].OriginDaemon != localDaemon {
+		t.Errorf("origin_daemon = %q, want %q", local[0].OriginDaemon, localDaemon)
+	}
+
+	// Two peers each minted a message at the same sequence as "local two",
+	// and arrived here by sync after it.
+	tied := local[1].Sequence
+	for _, peer := range []string{"d_zzz_peer", "d_aaa_peer"} {
+		event, _ := json.Marshal(map[string]any{
+			"type":          "message.create",
+			"timestamp":     "2026-01-01T00:00:00Z",
+			"event_id":      "evt_" + peer,
+			"v":             1,
+			"origin_daemon": peer,
+			"sequence":      tied,
+			"message_id":    "msg_" + peer,
+			"agent_id":      opsID,
+			"session_id":    "ses_peer",
+			"body":          map[string]string{"format": "markdown", "content": peer},
+		})
+		if err := handler.state.IngestSyncedEvent(ctx, event); err != nil {
+			t.Fatalf("ingest %s: %v", peer, err)
+		}
+	}
+
+	tiedDaemons := []string{localDaemon, "d_aaa_peer", "d_zzz_peer"}
+	slices.Sort(tiedDaemons)
+	want := []string{localDaemon}
+	want = append(want, tiedDaemons...)
+
+	got := func(msgs []MessageSummary) []string {
+		out := make([]string, 0, len(msgs))
+		for _, m := range msgs {
+			out = append(out, m.OriginDaemon)
+		}
+		return out
+	}
+	if asc := got(list("asc")); !slices.Equal(asc, want) {
+		t.Errorf("asc origin order = %v, want %v", asc, want)
+	}
+	slices.Reverse(want)
+	if desc := got(list("desc")); !slices.Equal(desc, want) {
+		t.Errorf("desc origin order = %v, want %v", desc, want)
+	}
+}
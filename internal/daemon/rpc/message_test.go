@@ -355,6 +355,35 @@ func TestMessageSend(t *testing.T) {
 		}
 	})
 
+	t.Run("validation - json format rejects invalid JSON", func(t *testing.T) {
+		req := SendRequest{
+			Content:       "{bad",
+			Format:        "json",
+			CallerAgentID: agentID,
+		}
+		params, _ := json.Marshal(req)
+
+		_, err := handler.HandleSend(context.Background(), params)
+		if err == nil || !strings.Contains(err.Error(), "content is not valid JSON") {
+			t.Fatalf("expected invalid JSON error, got %v", err)
+		}
+	})
+
+	t.Run("json format accepts empty object and array", func(t *testing.T) {
+		for _, content := range []string{"{}", "[]", `{"status": "ok", "count": 3}`} {
+			req := SendRequest{
+				Content:       content,
+				Format:        "json",
+				CallerAgentID: agentID,
+			}
+			params, _ := json.Marshal(req)
+
+			if _, err := handler.HandleSend(context.Background(), params); err != nil {
+				t.Errorf("send %q: %v", content, err)
+			}
+		}
+	})
+
 	t.Run("no active session", func(t *testing.T) {
 		// End the session
 		endReq := SessionEndRequest{
@@ -2665,6 +2694,30 @@ func TestHandleSend_MaxBodyBytes(t *testing.T) {
 		}
 	})
 
+	t.Run("structured_counts_toward_limit", func(t *testing.T) {
+		// Small content with a large structured payload must still be
+		// refused; the Data reports the combined size and the limit.
+		const limit = 100
+		handler := NewMessageHandlerWithDispatcher(st, nil, "", "", "", limit)
+		req, _ := json.Marshal(SendRequest{
+			Content:       "see payload",
+			Structured:    map[string]any{"log": strings.Repeat("s", limit)},
+			CallerAgentID: agentID,
+		})
+		_, err := handler.HandleSend(context.Background(), req)
+		rpcErr, ok := err.(*RPCError)
+		if !ok {
+			t.Fatalf("HandleSend err = %v (%T), want *RPCError size-cap rejection", err, err)
+		}
+		data, ok := rpcErr.Data.(BodyTooLargeData)
+		if !ok {
+			t.Fatalf("RPCError.Data = %T, want BodyTooLargeData", rpcErr.Data)
+		}
+		if data.MaxBytes != limit || data.Size <= limit {
+			t.Errorf("Data = %+v, want max_bytes %d and size over it", data, limit)
+		}
+	})
+
 	t.Run("accepts_at_limit", func(t *testing.T) {
 		const limit = 200
 		handler := NewMessageHandler(st)
@@ -2685,3 +2738,326 @@ func TestHandleSend_MaxBodyBytes(t *testing.T) {
 		}
 	})
 }
+
+func TestHandleSend_RateLimit(t *testing.T) {
+	tmpDir := t.TempDir()
+	thrumDir := filepath.Join(tmpDir, ".thrum")
+	if err := os.MkdirAll(thrumDir, 0o750); err != nil {
+		t.Fatalf("create .thrum dir: %v", err)
+	}
+	writeGuardOffConfig(t, tmpDir)
+
+	repoID := "r_RATE_TEST"
+	st, err := state.NewState(thrumDir, thrumDir, repoID, "")
+	if err != nil {
+		t.Fatalf("create state: %v", err)
+	}
+	defer func() { _ = st.Close() }()
+
+	t.Setenv("THRUM_ROLE", "coordinator")
+	t.Setenv("THRUM_MODULE", "core")
+
+	agentID := identity.GenerateAgentID(repoID, "coordinator", "core", "")
+	regParams, _ := json.Marshal(RegisterRequest{Role: "coordinator", Module: "core"})
+	if _, err := NewAgentHandler(st).HandleRegister(context.Background(), regParams); err != nil {
+		t.Fatalf("register agent: %v", err)
+	}
+	sessionParams, _ := json.Marshal(SessionStartRequest{AgentID: agentID})
+	if _, err := NewSessionHandler(st).HandleStart(context.Background(), sessionParams); err != nil {
+		t.Fatalf("start session: %v", err)
+	}
+
+	handler := NewMessageHandler(st)
+	limiter := &SendRateLimiter{}
+	limiter.Set(1, 2)
+	handler.SetSendRateLimiter(limiter)
+
+	req, _ := json.Marshal(SendRequest{Content: "loop", CallerAgentID: agentID})
+	for i := range 2 {
+		if _, err := handler.HandleSend(context.Background(), req); err != nil {
+			t.Fatalf("send %d within burst: %v", i, err)
+		}
+	}
+	_, err = handler.HandleSend(context.Background(), req)
+	if err == nil || !strings.Contains(err.Error(), "send rate limit exceeded") {
+		t.Fatalf("send over burst: err = %v, want rate limit error", err)
+	}
+
+	var count int
+	if err := st.RawDB().QueryRow(`SELECT COUNT(*) FROM messages WHERE agent_id = ?`, agentID).Scan(&count); err != nil {
+		t.Fatalf("count messages: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("messages written = %d, want 2 (the throttled send must not write)", count)
+	}
+}
+
+func TestHandleSend_TTL(t *testing.T) {
+	tmpDir := t.TempDir()
+	thrumDir := filepath.Join(tmpDir, ".thrum")
+	if err := os.MkdirAll(thrumDir, 0o750); err != nil {
+		t.Fatalf("create .thrum dir: %v", err)
+	}
+	writeGuardOffConfig(t, tmpDir)
+
+	repoID := "r_TTL_TEST"
+	st, err := state.NewState(thrumDir, thrumDir, repoID, "")
+	if err != nil {
+		t.Fatalf("create state: %v", err)
+	}
+	defer func() { _ = st.Close() }()
+
+	t.Setenv("THRUM_ROLE", "coordinator")
+	t.Setenv("THRUM_MODULE", "core")
+
+	agentID := identity.GenerateAgentID(repoID, "coordinator", "core", "")
+	regParams, _ := json.Marshal(RegisterRequest{Role: "coordinator", Module: "core"})
+	if _, err := NewAgentHandler(st).HandleRegister(context.Background(), regParams); err != nil {
+		t.Fatalf("register agent: %v", err)
+	}
+	sessionParams, _ := json.Marshal(SessionStartRequest{AgentID: agentID})
+	if _, err := NewSessionHandler(st).HandleStart(context.Background(), sessionParams); err != nil {
+		t.Fatalf("start session: %v", err)
+	}
+	handler := NewMessageHandler(st)
+
+	for _, bad := range []string{"soon", "0s", "-5m"} {
+		req, _ := json.Marshal(SendRequest{Content: "x", TTL: bad, CallerAgentID: agentID})
+		if _, err := handler.HandleSend(context.Background(), req); err == nil || !strings.Contains(err.Error(), "invalid ttl") {
+			t.Errorf("ttl %q: err = %v, want invalid ttl", bad, err)
+		}
+	}
+
+	send := func(ttl string) *SendResponse {
+		t.Helper()
+		req, _ := json.Marshal(SendRequest{Content: "ttl " + ttl, TTL: ttl, CallerAgentID: agentID})
+		resp, err := handler.HandleSend(context.Background(), req)
+		if err != nil {
+			t.Fatalf("send ttl %q: %v", ttl, err)
+		}
+		return resp.(*SendResponse)
+	}
+	keep := send("1h")
+	if keep.ExpiresAt == "" {
+		t.Fatal("send with ttl returned no expires_at")
+	}
+	gone := send("1h")
+	plain := send("")
+	if plain.ExpiresAt != "" {
+		t.Errorf("send without ttl: expires_at = %q", plain.ExpiresAt)
+	}
+
+	// Backdate one message's expiry: it drops out of the listing before
+	// cleanup gets to it, unless include_expired is set.
+	if _, err := st.RawDB().Exec(`UPDATE messages SET expires_at = '2000-01-01T00:00:00Z' WHERE message_id = ?`, gone.MessageID); err != nil {
+		t.Fatalf("backdate expiry: %v", err)
+	}
+	list := func(includeExpired bool) map[string]MessageSummary {
+		t.Helper()
+		params, _ := json.Marshal(ListMessagesRequest{IncludeExpired: includeExpired})
+		resp, err := handler.HandleList(context.Background(), params)
+		if err != nil {
+			t.Fatalf("list: %v", err)
+		}
+		got := map[string]MessageSummary{}
+		for _, m := range resp.(*ListMessagesResponse).Messages {
+			got[m.MessageID] = m
+		}
+		return got
+	}
+	got := list(false)
+	if _, ok := got[gone.MessageID]; ok {
+		t.Error("expired message listed by default")
+	}
+	if m, ok := got[keep.MessageID]; !ok || m.ExpiresAt != keep.ExpiresAt {
+		t.Errorf("unexpired ttl message = %+v, want listed with expires_at %s", m, keep.ExpiresAt)
+	}
+	if _, ok := got[plain.MessageID]; !ok {
+		t.Error("message without ttl not listed")
+	}
+	if _, ok := list(true)[gone.MessageID]; !ok {
+		t.Error("include_expired did not list the expired message")
+	}
+}
+
+func TestHandleSend_Schedule(t *testing.T) {
+	tmpDir := t.TempDir()
+	thrumDir := filepath.Join(tmpDir, ".thrum")
+	if err := os.MkdirAll(thrumDir, 0o750); err != nil {
+		t.Fatalf("create .thrum dir: %v", err)
+	}
+	writeGuardOffConfig(t, tmpDir)
+
+	repoID := "r_SCHEDULE_TEST"
+	st, err := state.NewState(thrumDir, thrumDir, repoID, "")
+	if err != nil {
+		t.Fatalf("create state: %v", err)
+	}
+	defer func() { _ = st.Close() }()
+
+	t.Setenv("THRUM_ROLE", "coordinator")
+	t.Setenv("THRUM_MODULE", "core")
+
+	agentID := identity.GenerateAgentID(repoID, "coordinator", "core", "")
+	regParams, _ := json.Marshal(RegisterRequest{Role: "coordinator", Module: "core"})
+	if _, err := NewAgentHandler(st).HandleRegister(context.Background(), regParams); err != nil {
+		t.Fatalf("register agent: %v", err)
+	}
+	sessionParams, _ := json.Marshal(SessionStartRequest{AgentID: agentID})
+	if _, err := NewSessionHandler(st).HandleStart(context.Background(), sessionParams); err != nil {
+		t.Fatalf("start session: %v", err)
+	}
+	handler := NewMessageHandler(st)
+	ctx := context.Background()
+
+	for _, bad := range []string{"tomorrow", "+0s", "+-5m", "2000-01-01T00:00:00Z"} {
+		req, _ := json.Marshal(SendRequest{Content: "x", Schedule: bad, CallerAgentID: agentID})
+		if _, err := handler.HandleSend(ctx, req); err == nil || !strings.Contains(err.Error(), "invalid schedule") {
+			t.Errorf("schedule %q: err = %v, want invalid schedule", bad, err)
+		}
+	}
+
+	send := func(schedule, ttl string) *SendResponse {
+		t.Helper()
+		req, _ := json.Marshal(SendRequest{Content: "later", Schedule: schedule, TTL: ttl, CallerAgentID: agentID})
+		resp, err := handler.HandleSend(ctx, req)
+		if err != nil {
+			t.Fatalf("send schedule %q: %v", schedule, err)
+		}
+		return resp.(*SendResponse)
+	}
+	at := time.Now().UTC().Add(3 * time.Hour).Truncate(time.Second)
+	pending := send(at.Format(time.RFC3339), "1h")
+	if pending.DeliverAt != at.Format(time.RFC3339) {
+		t.Errorf("deliver_at = %q, want %q", pending.DeliverAt, at.Format(time.RFC3339))
+	}
+	// The TTL runs from delivery, not from sending.
+	if want := at.Add(time.Hour).Format(time.RFC3339); pending.ExpiresAt != want {
+		t.Errorf("expires_at = %q, want %q", pending.ExpiresAt, want)
+	}
+	due := send("+2h", "")
+	plain := send("", "")
+
+	// Bring one message's delivery time into the past: it is listed, the
+	// still-pending one is not.
+	if _, err := st.RawDB().Exec(`UPDATE messages SET deliver_at = '2000-01-01T00:00:00Z' WHERE message_id = ?`, due.MessageID); err != nil {
+		t.Fatalf("backdate deliver_at: %v", err)
+	}
+	params, _ := json.Marshal(ListMessagesRequest{})
+	resp, err := handler.HandleList(ctx, params)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	listed := map[string]bool{}
+	for _, m := range resp.(*ListMessagesResponse).Messages {
+		listed[m.MessageID] = true
+	}
+	if listed[pending.MessageID] {
+		t.Error("pending scheduled message listed before deliver_at")
+	}
+	if !listed[due.MessageID] || !listed[plain.MessageID] {
+		t.Errorf("listed = %v, want the delivered and the unscheduled message", listed)
+	}
+
+	// The sender still sees the pending message in message.outbox.
+	outParams, _ := json.Marshal(OutboxRequest{CallerAgentID: agentID})
+	outResp, err := handler.HandleOutbox(ctx, outParams)
+	if err != nil {
+		t.Fatalf("outbox: %v", err)
+	}
+	var outboxDeliverAt string
+	for _, m := range outResp.(*OutboxResponse).Messages {
+		if m.MessageID == pending.MessageID {
+			outboxDeliverAt = m.DeliverAt
+		}
+	}
+	if outboxDeliverAt != pending.DeliverAt {
+		t.Errorf("outbox deliver_at = %q, want %q", outboxDeliverAt, pending.DeliverAt)
+	}
+
+	cancel := func(id string) (*CancelMessageResponse, error) {
+		params, _ := json.Marshal(CancelMessageRequest{MessageID: id, CallerAgentID: agentID})
+		resp, err := handler.HandleCancel(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		return resp.(*CancelMessageResponse), nil
+	}
+	for _, tc := range []struct {
+		id, want string
+	}{
+		{plain.MessageID, "is not scheduled"},
+		{due.MessageID, "already delivered"},
+		{"msg_missing", "message not found"},
+	} {
+		if _, err := cancel(tc.id); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("cancel %s: err = %v, want %q", tc.id, err, tc.want)
+		}
+	}
+	canceled, err := cancel(pending.MessageID)
+	if err != nil {
+		t.Fatalf("cancel pending: %v", err)
+	}
+	if canceled.DeliverAt != pending.DeliverAt {
+		t.Errorf("canceled deliver_at = %q, want %q", canceled.DeliverAt, pending.DeliverAt)
+	}
+	var deleted int
+	var reason sql.NullString
+	if err := st.RawDB().QueryRow(`SELECT deleted, delete_reason FROM messages WHERE message_id = ?`, pending.MessageID).Scan(&deleted, &reason); err != nil {
+		t.Fatalf("read canceled message: %v", err)
+	}
+	if deleted != 1 || reason.String != "canceled" {
+		t.Errorf("canceled message deleted=%d reason=%q, want 1 %q", deleted, reason.String, "canceled")
+	}
+	if _, err := cancel(pending.MessageID); err == nil || !strings.Contains(err.Error(), "already deleted") {
+		t.Errorf("second cancel: err = %v, want already deleted", err)
+	}
+}
+
+// TestHandleGet_HiddenMessages pins message.get against visibilityClause: a
+// scheduled or expired message is not found for anyone but its author.
+func TestHandleGet_HiddenMessages(t *testing.T) {
+	handler, agentID, cleanup := setupFilterTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	opsID := identity.GenerateAgentID("r_FILTER_TEST", "ops", "core", "")
+
+	send := func(schedule string) string {
+		t.Helper()
+		params, _ := json.Marshal(SendRequest{Content: "later", Schedule: schedule, Mentions: []string{"@reviewer"}, CallerAgentID: opsID})
+		resp, err := handler.HandleSend(ctx, params)
+		if err != nil {
+			t.Fatalf("HandleSend: %v", err)
+		}
+		return resp.(*SendResponse).MessageID
+	}
+	get := func(id, caller string) error {
+		t.Helper()
+		params, _ := json.Marshal(GetMessageRequest{MessageID: id, CallerAgentID: caller})
+		_, err := handler.HandleGet(ctx, params)
+		return err
+	}
+
+	scheduled := send("+1h")
+	expired := send("")
+	if _, err := handler.state.RawDB().Exec(`UPDATE messages SET expires_at = '2000-01-01T00:00:00Z' WHERE message_id = ?`, expired); err != nil {
+		t.Fatalf("expire message: %v", err)
+	}
+	for _, id := range []string{scheduled, expired} {
+		if err := get(id, agentID); err == nil || !strings.Contains(err.Error(), "message not found") {
+			t.Errorf("get %s as recipient: err = %v, want message not found", id, err)
+		}
+		if err := get(id, opsID); err != nil {
+			t.Errorf("get %s as author: %v", id, err)
+		}
+	}
+
+	// Once delivered, the recipient can read it.
+	if _, err := handler.state.RawDB().Exec(`UPDATE messages SET deliver_at = '2000-01-01T00:00:00Z' WHERE message_id = ?`, scheduled); err != nil {
+		t.Fatalf("backdate deliver_at: %v", err)
+	}
+	if err := get(scheduled, agentID); err != nil {
+		t.Errorf("get delivered message as recipient: %v", err)
+	}
+}
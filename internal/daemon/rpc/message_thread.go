@@ -0,0 +1,194 @@
+package rpc
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/leonletto/thrum/internal/types"
+)
+
+// GetThreadRequest represents the request for thread.get RPC.
+type GetThreadRequest struct {
+	ThreadID string `json:"thread_id"`
+}
+
+// GetThreadResponse represents the response from thread.get RPC.
+type GetThreadResponse struct {
+	ThreadID     string        `json:"thread_id"`
+	MessageCount int           `json:"message_count"` // Visible messages with this thread_id, deleted included
+	Roots        []*ThreadNode `json:"roots"`
+}
+
+// ThreadNode is one message in a thread tree. Placeholder nodes stand in for
+// a reply_to parent that no longer exists; they carry only MessageID and
+// Deleted. Soft-deleted messages keep their position but not their body.
+type ThreadNode struct {
+	MessageID   string            `json:"message_id"`
+	AgentID     string            `json:"agent_id,omitempty"`
+	CreatedAt   string            `json:"created_at,omitempty"`
+	Body        types.MessageBody `json:"body"`
+	Deleted     bool              `json:"deleted"`
+	Placeholder bool              `json:"placeholder,omitempty"`
+	Replies     []*ThreadNode     `json:"replies"`
+
+	replyTo      string
+	lastActivity string
+}
+
+// HandleThreadGet handles the thread.get RPC method. It loads every message
+// carrying the thread_id and nests them by their reply_to refs. Siblings are
+// ordered by the latest activity in their subtree, oldest first, so the most
+// recently active branch renders last.
+//
+// Replies whose parent is gone are grouped under a placeholder root. reply_to
+// cycles (possible only via hand-edited or merged event logs) are broken at
+// the oldest message of the cycle, which becomes a root. Scheduled messages
+// before their deliver_at and expired TTL messages are left out, as in
+// message.list (visibilityClause).
+func (h *MessageHandler) HandleThreadGet(ctx context.Context, params json.RawMessage) (any, error) {
+	var req GetThreadRequest
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	req.ThreadID = strings.TrimSpace(req.ThreadID)
+	if req.ThreadID == "" {
+		return nil, fmt.Errorf("thread_id is required")
+	}
+
+	h.state.RLock()
+	defer h.state.RUnlock()
+
+	visClause, visArgs := visibilityClause(time.Now(), false)
+	rows, err := h.state.DB().QueryContext(ctx,
+		`SELECT m.message_id, m.agent_id, m.created_at, m.deleted,
+		        m.body_format, m.body_content, m.body_structured,
+		        reply_ref.ref_value
+		 FROM messages m
+		 LEFT JOIN message_refs reply_ref ON reply_ref.message_id = m.message_id AND reply_ref.ref_type = 'reply_to'
+		 WHERE m.thread_id = ?`+visClause+`
+		 ORDER BY m.created_at, m.message_id`, append([]any{req.ThreadID}, visArgs...)...)
+	if err != nil {
+		return nil, fmt.Errorf("query thread: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var nodes []*ThreadNode
+	byID := make(map[string]*ThreadNode)
+	for rows.Next() {
+		var n ThreadNode
+		var deleted int
+		var bodyStructured, replyTo sql.NullString
+		if err := rows.Scan(
+			&n.MessageID, &n.AgentID, &n.CreatedAt, &deleted,
+			&n.Body.Format, &n.Body.Content, &bodyStructured,
+			&replyTo,
+		); err != nil {
+			return nil, fmt.Errorf("scan thread message: %w", err)
+		}
+		// A message with several reply_to refs yields one row per ref; the
+		// first (in scan order) wins.
+		if _, dup := byID[n.MessageID]; dup {
+			continue
+		}
+		n.Body.Structured = bodyStructured.String
+		n.Deleted = deleted != 0
+		if n.Deleted {
+			n.Body = types.MessageBody{}
+		}
+		n.replyTo = replyTo.String
+		n.Replies = []*ThreadNode{}
+		byID[n.MessageID] = &n
+		nodes = append(nodes, &n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate thread messages: %w", err)
+	}
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("thread not found: %s", req.ThreadID)
+	}
+
+	return &GetThreadResponse{
+		ThreadID:     req.ThreadID,
+		MessageCount: len(nodes),
+		Roots:        buildThreadTree(nodes, byID),
+	}, nil
+}
+
+// buildThreadTree links nodes (sorted oldest first) into a forest. Each node
+// is attached exactly once, so the result is acyclic regardless of the
+// reply_to graph.
+func buildThreadTree(nodes []*ThreadNode, byID map[string]*ThreadNode) []*ThreadNode {
+	children := make(map[string][]*ThreadNode)
+	placeholders := make(map[string]*ThreadNode)
+	var roots []*ThreadNode
+	for _, n := range nodes {
+		switch {
+		case n.replyTo == "" || n.replyTo == n.MessageID:
+			roots = append(roots, n)
+		case byID[n.replyTo] != nil:
+			children[n.replyTo] = append(children[n.replyTo], n)
+		default:
+			p := placeholders[n.replyTo]
+			if p == nil {
+				p = &ThreadNode{MessageID: n.replyTo, Deleted: true, Placeholder: true, Replies: []*ThreadNode{}}
+				placeholders[n.replyTo] = p
+				roots = append(roots, p)
+			}
+			p.Replies = append(p.Replies, n)
+		}
+	}
+
+	attached := make(map[string]bool)
+	var attach func(n *ThreadNode)
+	attach = func(n *ThreadNode) {
+		attached[n.MessageID] = true
+		for _, c := range children[n.MessageID] {
+			if !attached[c.MessageID] {
+				n.Replies = append(n.Replies, c)
+				attach(c)
+			}
+		}
+	}
+	for _, r := range roots {
+		if r.Placeholder {
+			attached[r.MessageID] = true
+			for _, c := range r.Replies {
+				attach(c)
+			}
+			continue
+		}
+		attach(r)
+	}
+	// Anything still unattached sits on a reply_to cycle. nodes is oldest
+	// first, so the oldest message of each cycle becomes its root.
+	for _, n := range nodes {
+		if !attached[n.MessageID] {
+			roots = append(roots, n)
+			attach(n)
+		}
+	}
+
+	for _, r := range roots {
+		sortThreadReplies(r)
+	}
+	sort.SliceStable(roots, func(i, j int) bool { return roots[i].lastActivity < roots[j].lastActivity })
+	return roots
+}
+
+// sortThreadReplies orders n's subtree by last activity and records the
+// newest created_at under n.
+func sortThreadReplies(n *ThreadNode) {
+	n.lastActivity = n.CreatedAt
+	for _, c := range n.Replies {
+		sortThreadReplies(c)
+		if c.lastActivity > n.lastActivity {
+			n.lastActivity = c.lastActivity
+		}
+	}
+	sort.SliceStable(n.Replies, func(i, j int) bool { return n.Replies[i].lastActivity < n.Replies[j].lastActivity })
+}
@@ -0,0 +1,163 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestThreadGet(t *testing.T) {
+	handler, agentID, cleanup := setupFilterTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	send := func(content, replyTo string) string {
+		t.Helper()
+		params, _ := json.Marshal(SendRequest{Content: content, ReplyTo: replyTo, CallerAgentID: agentID})
+		resp, err := handler.HandleSend(ctx, params)
+		if err != nil {
+			t.Fatalf("HandleSend: %v", err)
+		}
+		return resp.(*SendResponse).MessageID
+	}
+	db := handler.state.RawDB()
+	setTime := func(id, ts string) {
+		t.Helper()
+		if _, err := db.Exec(`UPDATE messages SET created_at = ? WHERE message_id = ?`, ts, id); err != nil {
+			t.Fatalf("set created_at: %v", err)
+		}
+	}
+
+	root := send("root", "")
+	a := send("a", root)
+	b := send("b", root)
+	a1 := send("a1", a)
+	gone := send("gone", b)
+	orphan := send("orphan", gone)
+	setTime(root, "2026-01-01T00:00:00Z")
+	setTime(a, "2026-01-01T00:01:00Z")
+	setTime(b, "2026-01-01T00:02:00Z")
+	setTime(gone, "2026-01-01T00:03:00Z")
+	setTime(orphan, "2026-01-01T00:04:00Z")
+	setTime(a1, "2026-01-01T00:05:00Z") // a's branch is now the most recently active
+
+	var threadID string
+	if err := db.QueryRow(`SELECT thread_id FROM messages WHERE message_id = ?`, root).Scan(&threadID); err != nil {
+		t.Fatalf("thread_id: %v", err)
+	}
+	if _, err := db.Exec(`DELETE FROM messages WHERE message_id = ?`, gone); err != nil {
+		t.Fatalf("hard-delete parent: %v", err)
+	}
+
+	params, _ := json.Marshal(GetThreadRequest{ThreadID: threadID})
+	got, err := handler.HandleThreadGet(ctx, params)
+	if err != nil {
+		t.Fatalf("HandleThreadGet: %v", err)
+	}
+	resp := got.(*GetThreadResponse)
+	if resp.MessageCount != 5 {
+		t.Errorf("MessageCount = %d, want 5", resp.MessageCount)
+	}
+	if len(resp.Roots) != 2 {
+		t.Fatalf("roots = %d, want 2 (root + placeholder)", len(resp.Roots))
+	}
+
+	// Placeholder (last activity 00:04) sorts before root (last activity 00:05).
+	ph := resp.Roots[0]
+	if !ph.Placeholder || ph.MessageID != gone || len(ph.Replies) != 1 || ph.Replies[0].MessageID != orphan {
+		t.Errorf("placeholder root = %+v, want %s holding %s", ph, gone, orphan)
+	}
+	r := resp.Roots[1]
+	if r.MessageID != root || len(r.Replies) != 2 {
+		t.Fatalf("root = %s with %d replies, want %s with 2", r.MessageID, len(r.Replies), root)
+	}
+	if r.Replies[0].MessageID != b || r.Replies[1].MessageID != a {
+		t.Errorf("reply order = [%s %s], want [%s %s] (newest activity last)",
+			r.Replies[0].MessageID, r.Replies[1].MessageID, b, a)
+	}
+	if len(r.Replies[1].Replies) != 1 || r.Replies[1].Replies[0].MessageID != a1 {
+		t.Errorf("a1 not nested under a: %+v", r.Replies[1].Replies)
+	}
+
+	params, _ = json.Marshal(GetThreadRequest{ThreadID: "thr_NONEXISTENT"})
+	if _, err := handler.HandleThreadGet(ctx, params); err == nil {
+		t.Error("expected error for unknown thread")
+	}
+}
+
+func TestBuildThreadTree_Cycle(t *testing.T) {
+	a := &ThreadNode{MessageID: "a", CreatedAt: "1", replyTo: "c", Replies: []*ThreadNode{}}
+	b := &ThreadNode{MessageID: "b", CreatedAt: "2", replyTo: "a", Replies: []*ThreadNode{}}
+	c := &ThreadNode{MessageID: "c", CreatedAt: "3", replyTo: "b", Replies: []*ThreadNode{}}
+	nodes := []*ThreadNode{a, b, c}
+	byID := map[string]*ThreadNode{"a": a, "b": b, "c": c}
+
+	roots := buildThreadTree(nodes, byID)
+	if len(roots) != 1 || roots[0] != a {
+		t.Fatalf("roots = %v, want [a]", roots)
+	}
+	if len(a.Replies) != 1 || a.Replies[0] != b || len(b.Replies) != 1 || b.Replies[0] != c || len(c.Replies) != 0 {
+		t.Errorf("cycle not broken into a → b → c")
+	}
+}
+
+// TestThreadGet_HidesScheduled pins thread.get against visibilityClause: a
+// scheduled reply stays out of the thread until its deliver_at passes, and an
+// expired one is left out.
+func TestThreadGet_HidesScheduled(t *testing.T) {
+	handler, agentID, cleanup := setupFilterTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	send := func(content, replyTo, schedule string) string {
+		t.Helper()
+		params, _ := json.Marshal(SendRequest{Content: content, ReplyTo: replyTo, Schedule: schedule, CallerAgentID: agentID})
+		resp, err := handler.HandleSend(ctx, params)
+		if err != nil {
+			t.Fatalf("HandleSend: %v", err)
+		}
+		return resp.(*SendResponse).MessageID
+	}
+	db := handler.state.RawDB()
+
+	root := send("root", "", "")
+	later := send("later", root, "+1h")
+	expired := send("expired", root, "")
+	if _, err := db.Exec(`UPDATE messages SET expires_at = '2000-01-01T00:00:00Z' WHERE message_id = ?`, expired); err != nil {
+		t.Fatalf("expire reply: %v", err)
+	}
+	var threadID string
+	if err := db.QueryRow(`SELECT thread_id FROM messages WHERE message_id = ?`, root).Scan(&threadID); err != nil {
+		t.Fatalf("thread_id: %v", err)
+	}
+	replies := func() []string {
+		t.Helper()
+		params, _ := json.Marshal(GetThreadRequest{ThreadID: threadID})
+		got, err := handler.HandleThreadGet(ctx, params)
+		if err != nil {
+			t.Fatalf("HandleThreadGet: %v", err)
+		}
+		resp := got.(*GetThreadResponse)
+		if len(resp.Roots) != 1 || resp.Roots[0].MessageID != root {
+			t.Fatalf("roots = %+v, want only %s", resp.Roots, root)
+		}
+		var ids []string
+		for _, n := range resp.Roots[0].Replies {
+			ids = append(ids, n.MessageID)
+		}
+		if resp.MessageCount != 1+len(ids) {
+			t.Errorf("MessageCount = %d, want %d", resp.MessageCount, 1+len(ids))
+		}
+		return ids
+	}
+
+	if got := replies(); len(got) != 0 {
+		t.Errorf("replies before delivery = %v, want none", got)
+	}
+	if _, err := db.Exec(`UPDATE messages SET deliver_at = '2000-01-01T00:00:00Z' WHERE message_id = ?`, later); err != nil {
+		t.Fatalf("backdate deliver_at: %v", err)
+	}
+	if got := replies(); len(got) != 1 || got[0] != later {
+		t.Errorf("replies after delivery = %v, want [%s]", got, later)
+	}
+}
@@ -3,8 +3,11 @@ package rpc
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -48,6 +51,19 @@ type RemovePeerFunc func(daemonID string) error
 // FindPeerByNameFunc resolves a peer name to a daemon ID.
 type FindPeerByNameFunc func(name string) (daemonID string, found bool)
 
+// RenamePeerFunc sets a peer's display name by daemon ID.
+type RenamePeerFunc func(daemonID, newName string) error
+
+// PullPeerFunc pulls new events from a peer by daemon ID now.
+type PullPeerFunc func(ctx context.Context, daemonID string) (applied, skipped int, err error)
+
+// ProbePeerFunc calls a peer by daemon ID and returns the round-trip time.
+type ProbePeerFunc func(ctx context.Context, daemonID string) (time.Duration, error)
+
+// peerProbeTimeout bounds each peer.status probe, so a dead peer reports
+// unreachable quickly instead of holding the response for the dial timeout.
+const peerProbeTimeout = 3 * time.Second
+
 // --- Request/Response types ---
 
 // PeerStartPairingRequest is the params for peer.start_pairing.
@@ -118,6 +134,52 @@ type PeerRemoveRequest struct {
 	DaemonID string `json:"daemon_id,omitempty"`
 }
 
+// PeerRenameRequest is the params for peer.rename. The peer is identified by
+// its current Name or by DaemonID; a Name that matches no peer is tried as a
+// daemon ID.
+type PeerRenameRequest struct {
+	Name     string `json:"name,omitempty"`
+	DaemonID string `json:"daemon_id,omitempty"`
+	NewName  string `json:"new_name"`
+}
+
+// PeerRenameResponse is the result of peer.rename.
+type PeerRenameResponse struct {
+	DaemonID string `json:"daemon_id"`
+	OldName  string `json:"old_name,omitempty"`
+	NewName  string `json:"new_name"`
+}
+
+// PeerSyncRequest is the params for peer.sync.
+type PeerSyncRequest struct {
+	Name     string `json:"name,omitempty"`
+	DaemonID string `json:"daemon_id,omitempty"`
+}
+
+// PeerSyncResponse is the result of peer.sync.
+type PeerSyncResponse struct {
+	DaemonID string `json:"daemon_id"`
+	Name     string `json:"name,omitempty"`
+	Applied  int    `json:"applied"` // events pulled and applied
+	Skipped  int    `json:"skipped"` // events pulled that were already present
+}
+
+// PeerStatusRequest is the optional params for peer.status. Name limits the
+// result to one peer, matched by name or daemon ID. Probe calls each peer
+// now and reports whether it answered.
+type PeerStatusRequest struct {
+	Name  string `json:"name,omitempty"`
+	Probe bool   `json:"probe,omitempty"`
+}
+
+// PeerProbe is the result of calling a peer's sync.peer_info for
+// peer.status probe.
+type PeerProbe struct {
+	Reachable bool    `json:"reachable"`
+	RTTMs     float64 `json:"rtt_ms,omitempty"` // round trip, including the connect
+	Error     string  `json:"error,omitempty"`
+}
+
 // PeerDetailedStatus is the detailed status of a single peer.
 type PeerDetailedStatus struct {
 	DaemonID string `json:"daemon_id"`
@@ -127,6 +189,8 @@ type PeerDetailedStatus struct {
 	PairedAt string `json:"paired_at"`
 	LastSync string `json:"last_sync"`
 	LastSeq  int64  `json:"last_synced_seq"`
+	// Probe is set when peer.status was called with probe.
+	Probe *PeerProbe `json:"probe,omitempty"`
 }
 
 // PeerListEntry is a single peer in the compact list.
@@ -299,19 +363,167 @@ func (h *PeerRemoveHandler) Handle(_ context.Context, params json.RawMessage) (a
 	return map[string]string{"status": "ok"}, nil
 }
 
+// PeerRenameHandler handles the peer.rename RPC.
+type PeerRenameHandler struct {
+	renamePeer RenamePeerFunc
+	findByName FindPeerByNameFunc
+}
+
+// NewPeerRenameHandler creates a new handler.
+func NewPeerRenameHandler(renameFn RenamePeerFunc, findByNameFn FindPeerByNameFunc) *PeerRenameHandler {
+	return &PeerRenameHandler{renamePeer: renameFn, findByName: findByNameFn}
+}
+
+// Handle renames a peer identified by its current name or daemon ID. The
+// daemon ID, and with it routing and the stored token, does not change.
+func (h *PeerRenameHandler) Handle(_ context.Context, params json.RawMessage) (any, error) {
+	if params == nil {
+		return nil, fmt.Errorf("missing params")
+	}
+
+	var req PeerRenameRequest
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, fmt.Errorf("invalid params: %w", err)
+	}
+	if strings.TrimSpace(req.NewName) == "" {
+		return nil, fmt.Errorf("new_name is required")
+	}
+
+	daemonID, oldName := req.DaemonID, ""
+	if daemonID == "" && req.Name != "" {
+		daemonID = req.Name
+		if id, found := h.findByName(req.Name); found {
+			daemonID, oldName = id, req.Name
+		}
+	}
+	if daemonID == "" {
+		return nil, fmt.Errorf("name or daemon_id is required")
+	}
+
+	newName := strings.TrimSpace(req.NewName)
+	if err := h.renamePeer(daemonID, newName); err != nil {
+		if oldName == "" && req.Name != "" && strings.Contains(err.Error(), "not found") {
+			return nil, fmt.Errorf("peer %q not found", req.Name)
+		}
+		return nil, err
+	}
+
+	return PeerRenameResponse{DaemonID: daemonID, OldName: oldName, NewName: newName}, nil
+}
+
+// PeerSyncHandler handles the peer.sync RPC.
+type PeerSyncHandler struct {
+	pullPeer   PullPeerFunc
+	findByName FindPeerByNameFunc
+}
+
+// NewPeerSyncHandler creates a new handler.
+func NewPeerSyncHandler(pullFn PullPeerFunc, findByNameFn FindPeerByNameFunc) *PeerSyncHandler {
+	return &PeerSyncHandler{pullPeer: pullFn, findByName: findByNameFn}
+}
+
+// Handle pulls from one peer, named by name or daemon ID, and reports how
+// many events came back. Errors from the pull (e.g. an unreachable peer)
+// are returned with the peer name so the caller knows which one failed.
+func (h *PeerSyncHandler) Handle(ctx context.Context, params json.RawMessage) (any, error) {
+	if params == nil {
+		return nil, fmt.Errorf("missing params")
+	}
+
+	var req PeerSyncRequest
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, fmt.Errorf("invalid params: %w", err)
+	}
+
+	daemonID := req.DaemonID
+	if daemonID == "" && req.Name != "" {
+		id, found := h.findByName(req.Name)
+		if !found {
+			return nil, fmt.Errorf("peer %q not found; run 'thrum peer list' to see paired peers", req.Name)
+		}
+		daemonID = id
+	}
+	if daemonID == "" {
+		return nil, fmt.Errorf("name or daemon_id is required")
+	}
+
+	applied, skipped, err := h.pullPeer(ctx, daemonID)
+	if err != nil {
+		return nil, err
+	}
+
+	return PeerSyncResponse{DaemonID: daemonID, Name: req.Name, Applied: applied, Skipped: skipped}, nil
+}
+
 // PeerStatusHandler handles the peer.status RPC.
 type PeerStatusHandler struct {
 	getStatus func() []PeerDetailedStatus
+	probePeer ProbePeerFunc
 }
 
 // NewPeerStatusHandler creates a new handler.
-func NewPeerStatusHandler(fn func() []PeerDetailedStatus) *PeerStatusHandler {
-	return &PeerStatusHandler{getStatus: fn}
+func NewPeerStatusHandler(fn func() []PeerDetailedStatus, probeFn ProbePeerFunc) *PeerStatusHandler {
+	return &PeerStatusHandler{getStatus: fn, probePeer: probeFn}
 }
 
-// Handle returns detailed status for all peers.
-func (h *PeerStatusHandler) Handle(_ context.Context, _ json.RawMessage) (any, error) {
-	return h.getStatus(), nil
+// Handle returns detailed status for all peers, or for the one peer named
+// by params.name (matched against name or daemon ID). With params.probe each
+// returned peer is also called now; see probe.
+func (h *PeerStatusHandler) Handle(ctx context.Context, params json.RawMessage) (any, error) {
+	var req PeerStatusRequest
+	if params != nil {
+		if err := json.Unmarshal(params, &req); err != nil {
+			return nil, fmt.Errorf("invalid params: %w", err)
+		}
+	}
+
+	statuses := h.getStatus()
+	if req.Name != "" {
+		var match []PeerDetailedStatus
+		for _, s := range statuses {
+			if s.Name == req.Name || s.DaemonID == req.Name {
+				match = []PeerDetailedStatus{s}
+				break
+			}
+		}
+		if match == nil {
+			return nil, fmt.Errorf("peer %q not found", req.Name)
+		}
+		statuses = match
+	}
+
+	if req.Probe {
+		if h.probePeer == nil {
+			return nil, fmt.Errorf("peer probing is not available on this daemon")
+		}
+		h.probe(ctx, statuses)
+	}
+	return statuses, nil
+}
+
+// probe calls every peer in statuses concurrently, each bounded by
+// peerProbeTimeout, so one dead peer doesn't hold up the others, and
+// records the outcome in its Probe field.
+func (h *PeerStatusHandler) probe(ctx context.Context, statuses []PeerDetailedStatus) {
+	var wg sync.WaitGroup
+	for i := range statuses {
+		s := &statuses[i]
+		wg.Go(func() {
+			probeCtx, cancel := context.WithTimeout(ctx, peerProbeTimeout)
+			defer cancel()
+
+			rtt, err := h.probePeer(probeCtx, s.DaemonID)
+			switch {
+			case errors.Is(err, context.DeadlineExceeded):
+				s.Probe = &PeerProbe{Error: fmt.Sprintf("no response within %s", peerProbeTimeout)}
+			case err != nil:
+				s.Probe = &PeerProbe{Error: err.Error()}
+			default:
+				s.Probe = &PeerProbe{Reachable: true, RTTMs: float64(rtt.Microseconds()) / 1000}
+			}
+		})
+	}
+	wg.Wait()
 }
 
 // PeerListHandler handles the peer.list RPC.
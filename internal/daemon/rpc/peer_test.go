@@ -3,7 +3,12 @@ package rpc_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/leonletto/thrum/internal/daemon/rpc"
 )
@@ -74,3 +79,147 @@ func countOccurrences(s, sub string) int {
 	}
 	return n
 }
+
+func TestPeerRenameHandler(t *testing.T) {
+	names := map[string]string{"01DA": "host-a.local", "01DB": "host-b.local"}
+	find := func(name string) (string, bool) {
+		for id, n := range names {
+			if n == name {
+				return id, true
+			}
+		}
+		return "", false
+	}
+	rename := func(daemonID, newName string) error {
+		if _, ok := names[daemonID]; !ok {
+			return fmt.Errorf("peer %s not found", daemonID)
+		}
+		names[daemonID] = newName
+		return nil
+	}
+	h := rpc.NewPeerRenameHandler(rename, find)
+
+	out, err := h.Handle(context.Background(), json.RawMessage(`{"name":"host-a.local","new_name":" laptop "}`))
+	if err != nil {
+		t.Fatalf("rename by name: %v", err)
+	}
+	if resp := out.(rpc.PeerRenameResponse); resp.DaemonID != "01DA" || resp.OldName != "host-a.local" || resp.NewName != "laptop" {
+		t.Errorf("response = %+v", resp)
+	}
+	if names["01DA"] != "laptop" {
+		t.Errorf("name = %q, want laptop", names["01DA"])
+	}
+
+	// A name that matches no peer is tried as a daemon ID.
+	if _, err := h.Handle(context.Background(), json.RawMessage(`{"name":"01DB","new_name":"desktop"}`)); err != nil {
+		t.Fatalf("rename by daemon ID: %v", err)
+	}
+	if names["01DB"] != "desktop" {
+		t.Errorf("name = %q, want desktop", names["01DB"])
+	}
+
+	for _, params := range []string{
+		`{"name":"nobody","new_name":"x"}`,
+		`{"name":"laptop","new_name":"  "}`,
+		`{"new_name":"x"}`,
+	} {
+		if _, err := h.Handle(context.Background(), json.RawMessage(params)); err == nil {
+			t.Errorf("Handle(%s): expected error", params)
+		}
+	}
+}
+
+func TestPeerSyncHandler(t *testing.T) {
+	find := func(name string) (string, bool) {
+		if name == "laptop" {
+			return "01DA", true
+		}
+		return "", false
+	}
+	var pulled string
+	pull := func(_ context.Context, daemonID string) (int, int, error) {
+		pulled = daemonID
+		return 3, 1, nil
+	}
+	h := rpc.NewPeerSyncHandler(pull, find)
+
+	out, err := h.Handle(context.Background(), json.RawMessage(`{"name":"laptop"}`))
+	if err != nil {
+		t.Fatalf("sync by name: %v", err)
+	}
+	if resp := out.(rpc.PeerSyncResponse); resp.DaemonID != "01DA" || resp.Applied != 3 || resp.Skipped != 1 || pulled != "01DA" {
+		t.Errorf("response = %+v, pulled %q", resp, pulled)
+	}
+
+	_, err = h.Handle(context.Background(), json.RawMessage(`{"name":"nobody"}`))
+	if err == nil || !strings.Contains(err.Error(), "thrum peer list") {
+		t.Errorf("unknown peer: err = %v, want a pointer to peer list", err)
+	}
+	if _, err := h.Handle(context.Background(), json.RawMessage(`{}`)); err == nil {
+		t.Error("empty params: expected error")
+	}
+}
+
+func TestPeerStatusHandler_FilterByName(t *testing.T) {
+	h := rpc.NewPeerStatusHandler(func() []rpc.PeerDetailedStatus {
+		return []rpc.PeerDetailedStatus{{DaemonID: "01DA", Name: "laptop"}, {DaemonID: "01DB", Name: "desktop"}}
+	}, nil)
+	for _, name := range []string{"desktop", "01DB"} {
+		out, err := h.Handle(context.Background(), json.RawMessage(`{"name":"`+name+`"}`))
+		if err != nil {
+			t.Fatalf("status %s: %v", name, err)
+		}
+		if got := out.([]rpc.PeerDetailedStatus); len(got) != 1 || got[0].DaemonID != "01DB" {
+			t.Errorf("status %s = %+v, want only 01DB", name, got)
+		}
+	}
+	if out, err := h.Handle(context.Background(), nil); err != nil || len(out.([]rpc.PeerDetailedStatus)) != 2 {
+		t.Errorf("unfiltered status = %v, %v", out, err)
+	}
+	if _, err := h.Handle(context.Background(), json.RawMessage(`{"name":"nobody"}`)); err == nil {
+		t.Error("expected error for unknown peer")
+	}
+}
+
+func TestPeerStatusHandler_Probe(t *testing.T) {
+	// Each probe waits until both have started, so the test only passes
+	// when they run concurrently; run one after the other, the first
+	// would time out.
+	var started sync.WaitGroup
+	started.Add(2)
+	probe := func(ctx context.Context, daemonID string) (time.Duration, error) {
+		started.Done()
+		done := make(chan struct{})
+		go func() { started.Wait(); close(done) }()
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+		if daemonID == "01DB" {
+			return 0, errors.New("connect to ws://desktop:9100/ws: connection refused")
+		}
+		return 1500 * time.Microsecond, nil
+	}
+	h := rpc.NewPeerStatusHandler(func() []rpc.PeerDetailedStatus {
+		return []rpc.PeerDetailedStatus{{DaemonID: "01DA", Name: "laptop"}, {DaemonID: "01DB", Name: "desktop"}}
+	}, probe)
+
+	out, err := h.Handle(context.Background(), json.RawMessage(`{"probe":true}`))
+	if err != nil {
+		t.Fatalf("probe: %v", err)
+	}
+	got := out.([]rpc.PeerDetailedStatus)
+	if p := got[0].Probe; p == nil || !p.Reachable || p.RTTMs != 1.5 {
+		t.Errorf("laptop probe = %+v, want reachable in 1.5ms", p)
+	}
+	if p := got[1].Probe; p == nil || p.Reachable || !strings.Contains(p.Error, "connection refused") {
+		t.Errorf("desktop probe = %+v, want unreachable with the dial error", p)
+	}
+
+	// Without probe, peers are not called.
+	out, err = h.Handle(context.Background(), nil)
+	if err != nil || out.([]rpc.PeerDetailedStatus)[0].Probe != nil {
+		t.Errorf("status without probe = %+v, %v; want no probe result", out, err)
+	}
+}
@@ -0,0 +1,52 @@
+package rpc
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// IdleThreshold is the heartbeat age after which an agent whose session is
+// still open is reported "away" rather than "active" (daemon.idle_threshold).
+// A crashed agent stops heartbeating and goes away; a busy one keeps its
+// session alive and stays active. Status is computed at read time from
+// sessions.last_seen_at, so the next heartbeat flips an away agent straight
+// back to active.
+//
+// One IdleThreshold is shared by the handlers that report presence
+// (team.list, agent.lookup, agent.listContext) and updated in place on
+// daemon reload. The zero value and a nil pointer disable away detection.
+type IdleThreshold struct {
+	d atomic.Int64
+}
+
+// Set replaces the threshold; d <= 0 disables away detection.
+func (t *IdleThreshold) Set(d time.Duration) {
+	t.d.Store(int64(d))
+}
+
+// Get returns the current threshold (0 when disabled).
+func (t *IdleThreshold) Get() time.Duration {
+	if t == nil {
+		return 0
+	}
+	return time.Duration(t.d.Load())
+}
+
+// IsAway reports whether a session last seen at lastSeen (RFC 3339) has been
+// silent longer than the threshold. Unparseable or empty timestamps are
+// never away: there is no evidence the agent stopped heartbeating.
+//
+// Only apply this to local agents. Heartbeats are DB-only and do not
+// propagate across peer daemons, so a remote agent's last_seen_at goes
+// stale while it is perfectly healthy (see TeamMember.IsLocal).
+func (t *IdleThreshold) IsAway(lastSeen string, now time.Time) bool {
+	threshold := t.Get()
+	if threshold <= 0 || lastSeen == "" {
+		return false
+	}
+	seen, err := time.Parse(time.RFC3339Nano, lastSeen)
+	if err != nil {
+		return false
+	}
+	return now.Sub(seen) > threshold
+}
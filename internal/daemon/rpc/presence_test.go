@@ -0,0 +1,133 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/leonletto/thrum/internal/daemon/state"
+)
+
+func TestIdleThreshold_IsAway(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	ago := func(d time.Duration) string { return now.Add(-d).Format(time.RFC3339Nano) }
+
+	idle := &IdleThreshold{}
+	idle.Set(5 * time.Minute)
+
+	for _, tc := range []struct {
+		name     string
+		t        *IdleThreshold
+		lastSeen string
+		want     bool
+	}{
+		{"fresh", idle, ago(time.Minute), false},
+		{"stale", idle, ago(6 * time.Minute), true},
+		{"second precision", idle, now.Add(-time.Hour).Format(time.RFC3339), true},
+		{"no heartbeat", idle, "", false},
+		{"unparseable", idle, "yesterday", false},
+		{"nil threshold", nil, ago(time.Hour), false},
+		{"disabled", &IdleThreshold{}, ago(time.Hour), false},
+	} {
+		if got := tc.t.IsAway(tc.lastSeen, now); got != tc.want {
+			t.Errorf("%s: IsAway(%q) = %v, want %v", tc.name, tc.lastSeen, got, tc.want)
+		}
+	}
+}
+
+// TestPresence_AwayAcrossHandlers covers team.list, agent.lookup and
+// agent.listContext reporting a silent local agent as away, leaving remote
+// agents alone, and flipping back to active once a heartbeat lands.
+func TestPresence_AwayAcrossHandlers(t *testing.T) {
+	tmpDir := t.TempDir()
+	syncDir := filepath.Join(tmpDir, "sync")
+	if err := os.MkdirAll(syncDir, 0o750); err != nil {
+		t.Fatalf("create sync dir: %v", err)
+	}
+	const localDaemonID = "d_local_01"
+	s, err := state.NewState(filepath.Join(tmpDir, ".thrum"), syncDir, "repo_presence", localDaemonID)
+	if err != nil {
+		t.Fatalf("create state: %v", err)
+	}
+	defer func() { _ = s.Close() }()
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+	fresh := now.Format(time.RFC3339Nano)
+	stale := now.Add(-10 * time.Minute).Format(time.RFC3339Nano)
+
+	if _, err := s.RawDB().ExecContext(ctx, `INSERT INTO agents
+		(agent_id, kind, role, module, origin_daemon, registered_at)
+		VALUES
+		('agent_busy',   'agent', 'worker', 'test', ?, ?),
+		('agent_idle',   'agent', 'worker', 'test', ?, ?),
+		('agent_remote', 'agent', 'worker', 'test', 'd_peer_02', ?)`,
+		localDaemonID, stale, localDaemonID, stale, stale); err != nil {
+		t.Fatalf("insert agents: %v", err)
+	}
+	if _, err := s.RawDB().ExecContext(ctx, `INSERT INTO sessions
+		(session_id, agent_id, started_at, last_seen_at)
+		VALUES
+		('ses_busy',   'agent_busy',   ?, ?),
+		('ses_idle',   'agent_idle',   ?, ?),
+		('ses_remote', 'agent_remote', ?, ?)`,
+		stale, fresh, stale, stale, stale, stale); err != nil {
+		t.Fatalf("insert sessions: %v", err)
+	}
+	if _, err := s.RawDB().ExecContext(ctx, `INSERT INTO agent_work_contexts (session_id, agent_id)
+		VALUES ('ses_busy', 'agent_busy'), ('ses_idle', 'agent_idle'), ('ses_remote', 'agent_remote')`); err != nil {
+		t.Fatalf("insert work contexts: %v", err)
+	}
+
+	idle := &IdleThreshold{}
+	idle.Set(5 * time.Minute)
+	team := NewTeamHandler(s, "", nil)
+	team.SetIdleThreshold(idle)
+	lookup := NewAgentLookupHandler(s)
+	lookup.SetIdleThreshold(idle)
+	agents := NewAgentHandler(s)
+	agents.SetIdleThreshold(idle)
+
+	check := func(want map[string]string) {
+		t.Helper()
+		raw, err := team.HandleList(ctx, json.RawMessage(`{}`))
+		if err != nil {
+			t.Fatalf("team.list: %v", err)
+		}
+		for _, m := range raw.(*TeamListResponse).Members {
+			if m.Status != want[m.AgentID] {
+				t.Errorf("team.list %s: status %q, want %q", m.AgentID, m.Status, want[m.AgentID])
+			}
+		}
+
+		raw, err = agents.HandleListContext(ctx, json.RawMessage(`{}`))
+		if err != nil {
+			t.Fatalf("agent.listContext: %v", err)
+		}
+		for _, c := range raw.(*ListContextResponse).Contexts {
+			if c.Status != want[c.AgentID] {
+				t.Errorf("agent.listContext %s: status %q, want %q", c.AgentID, c.Status, want[c.AgentID])
+			}
+		}
+
+		raw, err = lookup.HandleLookup(ctx, json.RawMessage(`{"name":"agent_idle"}`))
+		if err != nil {
+			t.Fatalf("agent.lookup: %v", err)
+		}
+		if m := raw.(*AgentLookupResponse).Member; m == nil || m.Status != want["agent_idle"] {
+			t.Errorf("agent.lookup agent_idle: %+v, want status %q", m, want["agent_idle"])
+		}
+	}
+
+	check(map[string]string{"agent_busy": "active", "agent_idle": "away", "agent_remote": "active"})
+
+	// The next heartbeat flips the agent straight back to active.
+	if _, err := s.RawDB().ExecContext(ctx,
+		`UPDATE sessions SET last_seen_at = ? WHERE session_id = 'ses_idle'`, fresh); err != nil {
+		t.Fatalf("heartbeat: %v", err)
+	}
+	check(map[string]string{"agent_busy": "active", "agent_idle": "active", "agent_remote": "active"})
+}
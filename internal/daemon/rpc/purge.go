@@ -10,6 +10,7 @@ import (
 
 	"github.com/leonletto/thrum/internal/daemon/state"
 	"github.com/leonletto/thrum/internal/jsonl"
+	"github.com/leonletto/thrum/internal/schema"
 	"github.com/leonletto/thrum/internal/types"
 )
 
@@ -149,14 +150,7 @@ func (h *PurgeHandler) execute(ctx context.Context, before string, cutoff time.T
 	}
 
 	// --- Delete message child tables first (FK safety) ---
-	childMessageTables := []string{
-		"message_edits",
-		"message_reads",
-		"message_deliveries",
-		"message_refs",
-		"message_scopes",
-	}
-	for _, table := range childMessageTables {
+	for _, table := range schema.MessageChildTables {
 		//nolint:gosec // table name is a hardcoded constant, not user input
 		q := `DELETE FROM ` + table + ` WHERE message_id IN (SELECT message_id FROM messages WHERE created_at < ?)`
 		if _, err := h.state.DB().ExecContext(ctx, q, before); err != nil {
@@ -0,0 +1,87 @@
+package rpc
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// SendRateLimiter throttles message.send per sending agent with a token
+// bucket (daemon.send_rate_limit_per_minute / send_rate_limit_burst). It is
+// a safety valve against an agent stuck in a loop, not a fairness mechanism:
+// an agent may send burst messages back to back, then perMinute messages a
+// minute sustained.
+//
+// One SendRateLimiter is shared by the socket and WebSocket message.send
+// handlers and updated in place on daemon reload; all methods are safe for
+// concurrent use. The zero value and a nil pointer allow every send.
+type SendRateLimiter struct {
+	mu        sync.Mutex
+	perMinute int
+	burst     int
+	buckets   map[string]*sendBucket
+}
+
+type sendBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// Set replaces the limits; perMinute <= 0 disables limiting. Buckets are
+// reset whenever the limits change, so every agent starts again with a full
+// burst.
+func (l *SendRateLimiter) Set(perMinute, burst int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if perMinute <= 0 {
+		perMinute, burst = 0, 0
+	} else if burst <= 0 {
+		burst = 1
+	}
+	if perMinute != l.perMinute || burst != l.burst {
+		l.buckets = nil
+	}
+	l.perMinute, l.burst = perMinute, burst
+}
+
+// Limits returns the current per-minute rate and burst (0, 0 when disabled).
+func (l *SendRateLimiter) Limits() (perMinute, burst int) {
+	if l == nil {
+		return 0, 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.perMinute, l.burst
+}
+
+// Allow takes one token from agentID's bucket. When the bucket is empty it
+// returns an error saying how long until the next send would be accepted.
+func (l *SendRateLimiter) Allow(agentID string, now time.Time) error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.perMinute <= 0 {
+		return nil
+	}
+	if l.buckets == nil {
+		l.buckets = make(map[string]*sendBucket)
+	}
+	b, ok := l.buckets[agentID]
+	if !ok {
+		b = &sendBucket{tokens: float64(l.burst), last: now}
+		l.buckets[agentID] = b
+	}
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = min(float64(l.burst), b.tokens+elapsed.Minutes()*float64(l.perMinute))
+		b.last = now
+	}
+	if b.tokens >= 1 {
+		b.tokens--
+		return nil
+	}
+	wait := time.Duration((1 - b.tokens) / float64(l.perMinute) * float64(time.Minute))
+	return fmt.Errorf("send rate limit exceeded for %s: at most %d messages per minute (burst %d, daemon.send_rate_limit_per_minute); retry in %s",
+		agentID, l.perMinute, l.burst, wait.Round(time.Second))
+}
@@ -0,0 +1,77 @@
+package rpc
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSendRateLimiter(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	var disabled *SendRateLimiter
+	if err := disabled.Allow("agent_a", now); err != nil {
+		t.Errorf("nil limiter: %v", err)
+	}
+	zero := &SendRateLimiter{}
+	for range 100 {
+		if err := zero.Allow("agent_a", now); err != nil {
+			t.Fatalf("zero limiter: %v", err)
+		}
+	}
+
+	l := &SendRateLimiter{}
+	l.Set(6, 3) // one token every 10s, three up front
+	for i := range 3 {
+		if err := l.Allow("agent_a", now); err != nil {
+			t.Fatalf("burst send %d: %v", i, err)
+		}
+	}
+	err := l.Allow("agent_a", now)
+	if err == nil || !strings.Contains(err.Error(), "retry in 10s") {
+		t.Errorf("over burst: err = %v, want retry in 10s", err)
+	}
+	if err := l.Allow("agent_b", now); err != nil {
+		t.Errorf("buckets must be per agent: %v", err)
+	}
+	if err := l.Allow("agent_a", now.Add(10*time.Second)); err != nil {
+		t.Errorf("after refill: %v", err)
+	}
+	if err := l.Allow("agent_a", now.Add(10*time.Second)); err == nil {
+		t.Error("refill must add one token per 10s")
+	}
+
+	// Changing the limits resets the buckets; disabling lets everything through.
+	l.Set(6, 1)
+	if err := l.Allow("agent_a", now.Add(10*time.Second)); err != nil {
+		t.Errorf("after Set: %v", err)
+	}
+	l.Set(0, 0)
+	if err := l.Allow("agent_a", now.Add(10*time.Second)); err != nil {
+		t.Errorf("disabled: %v", err)
+	}
+}
+
+func TestSendRateLimiter_Concurrent(t *testing.T) {
+	l := &SendRateLimiter{}
+	l.Set(1, 50)
+	now := time.Now()
+
+	var mu sync.Mutex
+	allowed := 0
+	var wg sync.WaitGroup
+	for range 200 {
+		wg.Go(func() {
+			if l.Allow("agent_a", now) == nil {
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+			}
+		})
+	}
+	wg.Wait()
+	if allowed != 50 {
+		t.Errorf("allowed = %d, want exactly the burst of 50", allowed)
+	}
+}
@@ -0,0 +1,38 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// ConfigChange is one setting that differs between the running daemon and
+// .thrum/config.json.
+type ConfigChange struct {
+	Setting string `json:"setting"` // config key, e.g. "local_only"
+	Old     string `json:"old"`
+	New     string `json:"new"`
+}
+
+// DaemonReloadResponse is the result of the daemon.reload RPC method.
+// Changed lists settings applied live; RestartRequired lists settings that
+// changed in config.json but only take effect after a daemon restart.
+type DaemonReloadResponse struct {
+	Changed         []ConfigChange `json:"changed"`
+	RestartRequired []ConfigChange `json:"restart_required"`
+}
+
+// ReloadHandler handles the daemon.reload RPC method.
+type ReloadHandler struct {
+	reload func(context.Context) (*DaemonReloadResponse, error)
+}
+
+// NewReloadHandler creates a ReloadHandler around the daemon's reload
+// function, which re-reads config.json and applies what it can live.
+func NewReloadHandler(reload func(context.Context) (*DaemonReloadResponse, error)) *ReloadHandler {
+	return &ReloadHandler{reload: reload}
+}
+
+// Handle handles the daemon.reload RPC method.
+func (h *ReloadHandler) Handle(ctx context.Context, _ json.RawMessage) (any, error) {
+	return h.reload(ctx)
+}
@@ -6,12 +6,16 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/leonletto/thrum/internal/daemon/cleanup"
+	"github.com/leonletto/thrum/internal/daemon/safedb"
 	"github.com/leonletto/thrum/internal/daemon/state"
 	"github.com/leonletto/thrum/internal/gitctx"
 	"github.com/leonletto/thrum/internal/identity"
+	"github.com/leonletto/thrum/internal/subscriptions"
 	"github.com/leonletto/thrum/internal/types"
 	wtpkg "github.com/leonletto/thrum/internal/worktree"
 )
@@ -33,7 +37,7 @@ type SessionStartResponse struct {
 // SessionEndRequest represents the request for session.end RPC.
 type SessionEndRequest struct {
 	SessionID string `json:"session_id"`       // Required: which session to end
-	Reason    string `json:"reason,omitempty"` // "normal", "crash", "superseded"
+	Reason    string `json:"reason,omitempty"` // "normal", "crash", "superseded", "stale"
 }
 
 // SessionEndResponse represents the response from session.end RPC.
@@ -43,6 +47,29 @@ type SessionEndResponse struct {
 	Duration  int64  `json:"duration_ms"`
 }
 
+// SessionResumeRequest represents the request for session.resume RPC.
+type SessionResumeRequest struct {
+	AgentID   string      `json:"agent_id"`             // Required: whose session to resume
+	SessionID string      `json:"session_id,omitempty"` // Required when the agent has several open sessions
+	Refs      []types.Ref `json:"refs,omitempty"`       // Added to the resumed session (e.g. the caller's worktree)
+}
+
+// SessionResumeResponse represents the response from session.resume RPC.
+// Resumed is false, with no session fields set, when the agent has no open
+// session to resume.
+type SessionResumeResponse struct {
+	Resumed     bool          `json:"resumed"`
+	SessionID   string        `json:"session_id,omitempty"`
+	AgentID     string        `json:"agent_id"`
+	StartedAt   string        `json:"started_at,omitempty"`
+	LastSeenAt  string        `json:"last_seen_at,omitempty"` // last heartbeat before the resume
+	Intent      string        `json:"intent,omitempty"`
+	CurrentTask string        `json:"current_task,omitempty"`
+	Scopes      []types.Scope `json:"scopes,omitempty"`
+	Refs        []types.Ref   `json:"refs,omitempty"`
+	Superseded  []string      `json:"superseded,omitempty"` // other open sessions ended by the resume
+}
+
 // HeartbeatRequest represents the request for session.heartbeat RPC.
 type HeartbeatRequest struct {
 	SessionID    string        `json:"session_id"`
@@ -50,6 +77,10 @@ type HeartbeatRequest struct {
 	RemoveScopes []types.Scope `json:"remove_scopes,omitempty"`
 	AddRefs      []types.Ref   `json:"add_refs,omitempty"`
 	RemoveRefs   []types.Ref   `json:"remove_refs,omitempty"`
+	// Intent and CurrentTask, when non-nil, update the session's work
+	// context in the same round-trip. An empty string clears the field.
+	Intent      *string `json:"intent,omitempty"`
+	CurrentTask *string `json:"current_task,omitempty"`
 }
 
 // HeartbeatResponse represents the response from session.heartbeat RPC.
@@ -91,6 +122,7 @@ type SetTaskResponse struct {
 type ListSessionsRequest struct {
 	AgentID    string `json:"agent_id,omitempty"`    // Filter by agent
 	ActiveOnly bool   `json:"active_only,omitempty"` // Only active sessions
+	Since      string `json:"since,omitempty"`       // RFC3339; only sessions still active at or after it
 }
 
 // ListSessionsResponse represents the response from session.list RPC.
@@ -112,7 +144,8 @@ type SessionSummary struct {
 
 // SessionHandler handles session-related RPC methods.
 type SessionHandler struct {
-	state *state.State
+	state      *state.State
+	dispatcher *subscriptions.Dispatcher // nil until SetDispatcher
 }
 
 // NewSessionHandler creates a new session handler.
@@ -120,6 +153,26 @@ func NewSessionHandler(state *state.State) *SessionHandler {
 	return &SessionHandler{state: state}
 }
 
+// SetDispatcher configures the dispatcher used to push
+// notification.context.updated when a session's work context changes.
+// Call it during daemon startup, before the handler serves requests.
+func (h *SessionHandler) SetDispatcher(d *subscriptions.Dispatcher) {
+	h.dispatcher = d
+}
+
+// notifyContextUpdated pushes notification.context.updated for a session,
+// best-effort. A no-op when no dispatcher is configured.
+func (h *SessionHandler) notifyContextUpdated(ctx context.Context, agentID, sessionID string) {
+	if h.dispatcher == nil {
+		return
+	}
+	_ = h.dispatcher.DispatchContextUpdated(ctx, &subscriptions.ContextUpdateInfo{
+		AgentID:   agentID,
+		SessionID: sessionID,
+		Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
+	})
+}
+
 // HandleStart handles the session.start RPC method.
 func (h *SessionHandler) HandleStart(ctx context.Context, params json.RawMessage) (any, error) {
 	var req SessionStartRequest
@@ -140,6 +193,12 @@ func (h *SessionHandler) HandleStart(ctx context.Context, params json.RawMessage
 		return nil, fmt.Errorf("agent not found: %w", err)
 	}
 
+	return h.startSession(ctx, req)
+}
+
+// startSession starts a session for req.AgentID, ending any sessions it left
+// open. The caller holds the state lock and has checked the agent exists.
+func (h *SessionHandler) startSession(ctx context.Context, req SessionStartRequest) (*SessionStartResponse, error) {
 	// Check for orphaned sessions and recover them
 	if err := h.recoverOrphanedSessions(ctx, req.AgentID); err != nil {
 		return nil, fmt.Errorf("recover orphaned sessions: %w", err)
@@ -213,6 +272,18 @@ func (h *SessionHandler) HandleStart(ctx context.Context, params json.RawMessage
 		}
 	}
 
+	// who-has and team read declared files through the session's work
+	// context, so make sure one exists before the first heartbeat.
+	for _, scope := range req.Scopes {
+		if scope.Type == declaredFileScope {
+			_, _ = h.state.DB().ExecContext(ctx, `
+				INSERT OR IGNORE INTO agent_work_contexts (session_id, agent_id)
+				VALUES (?, ?)
+			`, sessionID, req.AgentID)
+			break
+		}
+	}
+
 	return &SessionStartResponse{
 		SessionID: sessionID,
 		AgentID:   req.AgentID,
@@ -220,6 +291,189 @@ func (h *SessionHandler) HandleStart(ctx context.Context, params json.RawMessage
 	}, nil
 }
 
+// HandleResume handles the session.resume RPC method. It reattaches the
+// caller to one of the agent's sessions that never ended — typically left
+// behind when a shell died — instead of starting a fresh one, so the
+// session's intent, task, scopes and refs carry over. The daemon cannot tell
+// a live session from an orphan, so every open session is a candidate: with
+// exactly one it is resumed, with several req.SessionID must pick one (the
+// rest are ended as superseded, as session.start would have done), and with
+// none the response has Resumed=false so the caller can start a new session.
+func (h *SessionHandler) HandleResume(ctx context.Context, params json.RawMessage) (any, error) {
+	var req SessionResumeRequest
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	if req.AgentID == "" {
+		return nil, fmt.Errorf("agent_id is required")
+	}
+
+	h.state.Lock()
+	defer h.state.Unlock()
+
+	if err := h.verifyAgentExists(ctx, req.AgentID); err != nil {
+		return nil, fmt.Errorf("agent not found: %w", err)
+	}
+
+	rows, err := h.state.DB().QueryContext(ctx, `
+		SELECT session_id FROM sessions
+		WHERE agent_id = ? AND ended_at IS NULL
+		ORDER BY started_at DESC`, req.AgentID)
+	if err != nil {
+		return nil, fmt.Errorf("query open sessions: %w", err)
+	}
+	var open []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("scan open session: %w", err)
+		}
+		open = append(open, id)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate open sessions: %w", err)
+	}
+
+	sessionID := req.SessionID
+	switch {
+	case sessionID != "":
+		if !slices.Contains(open, sessionID) {
+			session, err := h.getSession(ctx, sessionID)
+			switch {
+			case err != nil:
+				return nil, fmt.Errorf("session %s not found", sessionID)
+			case session.AgentID != req.AgentID:
+				return nil, fmt.Errorf("session %s belongs to %s, not %s", sessionID, session.AgentID, req.AgentID)
+			default:
+				return nil, fmt.Errorf("session %s has already ended; start a new one with 'thrum session start'", sessionID)
+			}
+		}
+	case len(open) == 0:
+		return &SessionResumeResponse{AgentID: req.AgentID}, nil
+	case len(open) > 1:
+		return nil, fmt.Errorf("%d open sessions for %s (%s); pass the SESSION_ID to resume",
+			len(open), req.AgentID, strings.Join(open, ", "))
+	default:
+		sessionID = open[0]
+	}
+
+	session, err := h.getSession(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+	resp := &SessionResumeResponse{
+		Resumed:    true,
+		SessionID:  sessionID,
+		AgentID:    req.AgentID,
+		StartedAt:  session.StartedAt,
+		LastSeenAt: session.LastSeenAt,
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	for _, other := range open {
+		if other == sessionID {
+			continue
+		}
+		event := types.AgentSessionEndEvent{
+			Type:      "agent.session.end",
+			Timestamp: now,
+			SessionID: other,
+			Reason:    "superseded",
+		}
+		postCommit, err := h.state.WriteEvent(ctx, event)
+		if err != nil {
+			return nil, fmt.Errorf("end superseded session %s: %w", other, err)
+		}
+		h.state.GoPostCommit(postCommit)
+		resp.Superseded = append(resp.Superseded, other)
+	}
+
+	if _, err := h.state.DB().ExecContext(ctx,
+		`UPDATE sessions SET last_seen_at = ? WHERE session_id = ?`, now, sessionID); err != nil {
+		return nil, fmt.Errorf("update last_seen_at: %w", err)
+	}
+	_, _ = h.state.DB().ExecContext(ctx,
+		`UPDATE agents SET last_seen_at = ? WHERE agent_id = ?`, now, req.AgentID)
+
+	// Same ref handling as session.start: worktree paths are canonicalized
+	// and seed the work context so peercred matches the new shell's CWD.
+	for _, ref := range req.Refs {
+		refValue := ref.Value
+		if ref.Type == "worktree" {
+			refValue = wtpkg.CanonicalizeWorktreePath(refValue)
+			if refValue != "" {
+				_, _ = h.state.DB().ExecContext(ctx, `
+					INSERT INTO agent_work_contexts (session_id, agent_id, worktree_path)
+					VALUES (?, ?, ?)
+					ON CONFLICT(session_id) DO UPDATE SET
+						worktree_path = excluded.worktree_path
+				`, sessionID, req.AgentID, refValue)
+			}
+		}
+		if _, err := h.state.DB().ExecContext(ctx, `
+			INSERT OR IGNORE INTO session_refs (session_id, ref_type, ref_value, added_at)
+			VALUES (?, ?, ?, ?)
+		`, sessionID, ref.Type, refValue, now); err != nil {
+			return nil, fmt.Errorf("add ref: %w", err)
+		}
+	}
+
+	if err := h.loadResumedContext(ctx, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// loadResumedContext fills resp's intent, task, scopes and refs from the
+// session's stored state. Callers must hold the state lock.
+func (h *SessionHandler) loadResumedContext(ctx context.Context, resp *SessionResumeResponse) error {
+	var intent, task sql.NullString
+	err := h.state.DB().QueryRowContext(ctx,
+		`SELECT intent, current_task FROM agent_work_contexts WHERE session_id = ?`,
+		resp.SessionID).Scan(&intent, &task)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("load work context: %w", err)
+	}
+	resp.Intent, resp.CurrentTask = intent.String, task.String
+
+	scopeRows, err := h.state.DB().QueryContext(ctx,
+		`SELECT scope_type, scope_value FROM session_scopes WHERE session_id = ? ORDER BY scope_type, scope_value`,
+		resp.SessionID)
+	if err != nil {
+		return fmt.Errorf("load scopes: %w", err)
+	}
+	for scopeRows.Next() {
+		var sc types.Scope
+		if err := scopeRows.Scan(&sc.Type, &sc.Value); err != nil {
+			_ = scopeRows.Close()
+			return fmt.Errorf("scan scope: %w", err)
+		}
+		resp.Scopes = append(resp.Scopes, sc)
+	}
+	_ = scopeRows.Close()
+	if err := scopeRows.Err(); err != nil {
+		return fmt.Errorf("iterate scopes: %w", err)
+	}
+
+	refRows, err := h.state.DB().QueryContext(ctx,
+		`SELECT ref_type, ref_value FROM session_refs WHERE session_id = ? ORDER BY ref_type, ref_value`,
+		resp.SessionID)
+	if err != nil {
+		return fmt.Errorf("load refs: %w", err)
+	}
+	defer func() { _ = refRows.Close() }()
+	for refRows.Next() {
+		var ref types.Ref
+		if err := refRows.Scan(&ref.Type, &ref.Value); err != nil {
+			return fmt.Errorf("scan ref: %w", err)
+		}
+		resp.Refs = append(resp.Refs, ref)
+	}
+	return refRows.Err()
+}
+
 // HandleEnd handles the session.end RPC method.
 func (h *SessionHandler) HandleEnd(ctx context.Context, params json.RawMessage) (any, error) {
 	var req SessionEndRequest
@@ -320,6 +574,17 @@ func (h *SessionHandler) HandleList(ctx context.Context, params json.RawMessage)
 		query += " AND s.ended_at IS NULL"
 	}
 
+	// A session overlaps the window if it has not ended or ended inside it;
+	// one that started inside the window cannot have ended before it.
+	if req.Since != "" {
+		since, err := time.Parse(time.RFC3339Nano, req.Since)
+		if err != nil {
+			return nil, fmt.Errorf("invalid since %q: %w", req.Since, err)
+		}
+		query += " AND (s.ended_at IS NULL OR s.ended_at >= ?)"
+		args = append(args, since.UTC().Format(time.RFC3339Nano))
+	}
+
 	query += " ORDER BY s.started_at DESC"
 
 	rows, err := h.state.DB().QueryContext(ctx, query, args...)
@@ -462,6 +727,35 @@ func (h *SessionHandler) HandleHeartbeat(ctx context.Context, params json.RawMes
 		}
 	}
 
+	// Intent / task updates ride along so hooks can refresh presence and
+	// work context in one call. Same upserts as setIntent / setTask.
+	if req.Intent != nil {
+		_, err := h.state.DB().ExecContext(ctx, `
+			INSERT INTO agent_work_contexts (session_id, agent_id, intent, intent_updated_at)
+			VALUES (?, ?, ?, ?)
+			ON CONFLICT(session_id) DO UPDATE SET
+				intent = excluded.intent,
+				intent_updated_at = excluded.intent_updated_at
+		`, req.SessionID, session.AgentID, *req.Intent, now)
+		if err != nil {
+			h.state.Unlock()
+			return nil, fmt.Errorf("update intent: %w", err)
+		}
+	}
+	if req.CurrentTask != nil {
+		_, err := h.state.DB().ExecContext(ctx, `
+			INSERT INTO agent_work_contexts (session_id, agent_id, current_task, task_updated_at)
+			VALUES (?, ?, ?, ?)
+			ON CONFLICT(session_id) DO UPDATE SET
+				current_task = excluded.current_task,
+				task_updated_at = excluded.task_updated_at
+		`, req.SessionID, session.AgentID, *req.CurrentTask, now)
+		if err != nil {
+			h.state.Unlock()
+			return nil, fmt.Errorf("update task: %w", err)
+		}
+	}
+
 	// Copy data needed for git extraction
 	sessionID := req.SessionID
 	agentID := session.AgentID
@@ -495,6 +789,8 @@ func (h *SessionHandler) HandleHeartbeat(ctx context.Context, params json.RawMes
 		}
 	}
 
+	h.notifyContextUpdated(ctx, agentID, sessionID)
+
 	return resp, nil
 }
 
@@ -549,6 +845,39 @@ func (h *SessionHandler) recoverOrphanedSessions(ctx context.Context, agentID st
 	return nil
 }
 
+// declaredFileScope is the session scope type for files an agent declares it
+// will touch (session start --files). Declared files are cleared when the
+// session ends.
+const declaredFileScope = "file"
+
+// loadDeclaredFiles returns session_id → declared files (sorted) for every
+// active session. Callers must hold the state lock (read or write).
+func loadDeclaredFiles(ctx context.Context, db *safedb.DB) (map[string][]string, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT ss.session_id, ss.scope_value
+		FROM session_scopes ss
+		JOIN sessions s ON s.session_id = ss.session_id AND s.ended_at IS NULL
+		WHERE ss.scope_type = ?
+		ORDER BY ss.scope_value`, declaredFileScope)
+	if err != nil {
+		return nil, fmt.Errorf("query declared files: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	files := make(map[string][]string)
+	for rows.Next() {
+		var sessionID, file string
+		if err := rows.Scan(&sessionID, &file); err != nil {
+			return nil, fmt.Errorf("scan declared file: %w", err)
+		}
+		files[sessionID] = append(files[sessionID], file)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate declared files: %w", err)
+	}
+	return files, nil
+}
+
 // verifyAgentExists checks if an agent with the given ID exists.
 func (h *SessionHandler) verifyAgentExists(ctx context.Context, agentID string) error {
 	var exists bool
@@ -565,18 +894,19 @@ func (h *SessionHandler) verifyAgentExists(ctx context.Context, agentID string)
 
 // getSession retrieves session information.
 func (h *SessionHandler) getSession(ctx context.Context, sessionID string) (*sessionInfo, error) {
-	query := `SELECT session_id, agent_id, started_at, ended_at
+	query := `SELECT session_id, agent_id, started_at, ended_at, last_seen_at
 	          FROM sessions
 	          WHERE session_id = ?`
 
 	var session sessionInfo
-	var endedAt sql.NullString
+	var endedAt, lastSeenAt sql.NullString
 
 	err := h.state.DB().QueryRowContext(ctx, query, sessionID).Scan(
 		&session.SessionID,
 		&session.AgentID,
 		&session.StartedAt,
 		&endedAt,
+		&lastSeenAt,
 	)
 
 	if err != nil {
@@ -586,16 +916,18 @@ func (h *SessionHandler) getSession(ctx context.Context, sessionID string) (*ses
 	if endedAt.Valid {
 		session.EndedAt = endedAt.String
 	}
+	session.LastSeenAt = lastSeenAt.String
 
 	return &session, nil
 }
 
 // sessionInfo represents session information from the database.
 type sessionInfo struct {
-	SessionID string
-	AgentID   string
-	StartedAt string
-	EndedAt   string
+	SessionID  string
+	AgentID    string
+	StartedAt  string
+	EndedAt    string
+	LastSeenAt string
 }
 
 // getWorktreePath returns the worktree path for a session from session_refs.
@@ -701,6 +1033,8 @@ func (h *SessionHandler) HandleSetIntent(ctx context.Context, params json.RawMes
 	// last_seen so the hint doesn't false-positive.
 	_ = h.state.TouchAgentLastSeen(ctx, session.AgentID)
 
+	h.notifyContextUpdated(ctx, session.AgentID, req.SessionID)
+
 	return &SetIntentResponse{
 		SessionID:       req.SessionID,
 		Intent:          req.Intent,
@@ -750,6 +1084,8 @@ func (h *SessionHandler) HandleSetTask(ctx context.Context, params json.RawMessa
 	// last_seen so the hint doesn't false-positive.
 	_ = h.state.TouchAgentLastSeen(ctx, session.AgentID)
 
+	h.notifyContextUpdated(ctx, session.AgentID, req.SessionID)
+
 	return &SetTaskResponse{
 		SessionID:     req.SessionID,
 		CurrentTask:   req.CurrentTask,
@@ -7,10 +7,14 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/leonletto/thrum/internal/daemon/state"
+	"github.com/leonletto/thrum/internal/subscriptions"
 	"github.com/leonletto/thrum/internal/types"
 	wtpkg "github.com/leonletto/thrum/internal/worktree"
 )
@@ -318,6 +322,105 @@ func TestSessionCrashRecovery(t *testing.T) {
 	}
 }
 
+func TestSessionResume(t *testing.T) {
+	tmpDir := t.TempDir()
+	s, err := state.NewState(filepath.Join(tmpDir, ".thrum"), filepath.Join(tmpDir, ".thrum"), "test_repo_123", "")
+	if err != nil {
+		t.Fatalf("create state: %v", err)
+	}
+	defer func() { _ = s.Close() }()
+	ctx := context.Background()
+
+	registerReqJSON, _ := json.Marshal(RegisterRequest{Role: "implementer", Module: "test"})
+	registerResp, err := NewAgentHandler(s).HandleRegister(ctx, registerReqJSON)
+	if err != nil {
+		t.Fatalf("register agent: %v", err)
+	}
+	agentID := registerResp.(*RegisterResponse).AgentID
+
+	sessionHandler := NewSessionHandler(s)
+	resume := func(sessionID string) (*SessionResumeResponse, error) {
+		t.Helper()
+		reqJSON, _ := json.Marshal(SessionResumeRequest{AgentID: agentID, SessionID: sessionID})
+		resp, err := sessionHandler.HandleResume(ctx, reqJSON)
+		if err != nil {
+			return nil, err
+		}
+		return resp.(*SessionResumeResponse), nil
+	}
+
+	// No open session: nothing to resume.
+	resp, err := resume("")
+	if err != nil {
+		t.Fatalf("resume with no sessions: %v", err)
+	}
+	if resp.Resumed || resp.SessionID != "" {
+		t.Errorf("expected Resumed=false, got %+v", resp)
+	}
+
+	startReqJSON, _ := json.Marshal(SessionStartRequest{
+		AgentID: agentID,
+		Scopes:  []types.Scope{{Type: "file", Value: "auth.go"}},
+	})
+	startResp, err := sessionHandler.HandleStart(ctx, startReqJSON)
+	if err != nil {
+		t.Fatalf("start session: %v", err)
+	}
+	orphan := startResp.(*SessionStartResponse).SessionID
+	intentJSON, _ := json.Marshal(SetIntentRequest{SessionID: orphan, Intent: "Refactoring auth"})
+	if _, err := sessionHandler.HandleSetIntent(ctx, intentJSON); err != nil {
+		t.Fatalf("set intent: %v", err)
+	}
+
+	// One open session: resumed with its context intact.
+	resp, err = resume("")
+	if err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	if !resp.Resumed || resp.SessionID != orphan {
+		t.Fatalf("expected to resume %s, got %+v", orphan, resp)
+	}
+	if resp.Intent != "Refactoring auth" {
+		t.Errorf("Intent = %q, want %q", resp.Intent, "Refactoring auth")
+	}
+	if len(resp.Scopes) != 1 || resp.Scopes[0].Value != "auth.go" {
+		t.Errorf("Scopes = %+v, want auth.go", resp.Scopes)
+	}
+
+	// Two open sessions: the caller has to pick one.
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	if _, err := s.RawDB().Exec(`INSERT INTO sessions (session_id, agent_id, started_at, last_seen_at)
+		VALUES ('ses_OTHER', ?, ?, ?)`, agentID, now, now); err != nil {
+		t.Fatalf("insert second session: %v", err)
+	}
+	if _, err := resume(""); err == nil || !strings.Contains(err.Error(), "pass the SESSION_ID") {
+		t.Errorf("expected ambiguity error, got %v", err)
+	}
+
+	resp, err = resume(orphan)
+	if err != nil {
+		t.Fatalf("resume by id: %v", err)
+	}
+	if !resp.Resumed || len(resp.Superseded) != 1 || resp.Superseded[0] != "ses_OTHER" {
+		t.Errorf("expected ses_OTHER superseded, got %+v", resp)
+	}
+	var endReason string
+	if err := s.RawDB().QueryRow(`SELECT end_reason FROM sessions WHERE session_id = 'ses_OTHER'`).Scan(&endReason); err != nil {
+		t.Fatalf("query superseded session: %v", err)
+	}
+	if endReason != "superseded" {
+		t.Errorf("end_reason = %q, want superseded", endReason)
+	}
+
+	// An ended session cannot be resumed.
+	if _, err := resume("ses_OTHER"); err == nil || !strings.Contains(err.Error(), "already ended") {
+		t.Errorf("expected already-ended error, got %v", err)
+	}
+	if _, err := resume("ses_MISSING"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected not-found error, got %v", err)
+	}
+}
+
 func TestSessionHeartbeat(t *testing.T) {
 	tmpDir := t.TempDir()
 	thrumDir := filepath.Join(tmpDir, ".thrum")
@@ -760,6 +863,73 @@ func TestHeartbeat_NoWorktreeRef(t *testing.T) {
 	}
 }
 
+// TestHeartbeat_IntentAndTask verifies that heartbeat updates intent and task
+// when supplied, leaves them untouched when omitted, and clears on "".
+func TestHeartbeat_IntentAndTask(t *testing.T) {
+	tmpDir := t.TempDir()
+	thrumDir := filepath.Join(tmpDir, ".thrum")
+
+	s, err := state.NewState(thrumDir, thrumDir, "test_repo_hb_intent", "")
+	if err != nil {
+		t.Fatalf("create state: %v", err)
+	}
+	defer func() { _ = s.Close() }()
+
+	agentHandler := NewAgentHandler(s)
+	registerReqJSON, _ := json.Marshal(RegisterRequest{Role: "implementer", Module: "test"})
+	registerResp, err := agentHandler.HandleRegister(context.Background(), registerReqJSON)
+	if err != nil {
+		t.Fatalf("register agent: %v", err)
+	}
+	agentID := registerResp.(*RegisterResponse).AgentID
+
+	sessionHandler := NewSessionHandler(s)
+	startReqJSON, _ := json.Marshal(SessionStartRequest{AgentID: agentID})
+	startResp, err := sessionHandler.HandleStart(context.Background(), startReqJSON)
+	if err != nil {
+		t.Fatalf("start session: %v", err)
+	}
+	sessionID := startResp.(*SessionStartResponse).SessionID
+
+	heartbeat := func(req HeartbeatRequest) {
+		t.Helper()
+		req.SessionID = sessionID
+		reqJSON, _ := json.Marshal(req)
+		if _, err := sessionHandler.HandleHeartbeat(context.Background(), reqJSON); err != nil {
+			t.Fatalf("heartbeat: %v", err)
+		}
+	}
+	workContext := func() (string, string) {
+		t.Helper()
+		var intent, task sql.NullString
+		if err := s.RawDB().QueryRow(`
+			SELECT intent, current_task FROM agent_work_contexts WHERE session_id = ?
+		`, sessionID).Scan(&intent, &task); err != nil {
+			t.Fatalf("query work context: %v", err)
+		}
+		return intent.String, task.String
+	}
+
+	intent, task := "Fixing auth", "beads:thrum-42"
+	heartbeat(HeartbeatRequest{Intent: &intent, CurrentTask: &task})
+	if gotIntent, gotTask := workContext(); gotIntent != intent || gotTask != task {
+		t.Errorf("after set: intent=%q task=%q, want %q %q", gotIntent, gotTask, intent, task)
+	}
+
+	// Omitted fields are left alone.
+	heartbeat(HeartbeatRequest{})
+	if gotIntent, gotTask := workContext(); gotIntent != intent || gotTask != task {
+		t.Errorf("after plain heartbeat: intent=%q task=%q, want unchanged", gotIntent, gotTask)
+	}
+
+	// Empty string clears.
+	empty := ""
+	heartbeat(HeartbeatRequest{Intent: &empty})
+	if gotIntent, gotTask := workContext(); gotIntent != "" || gotTask != task {
+		t.Errorf("after clear: intent=%q task=%q, want \"\" %q", gotIntent, gotTask, task)
+	}
+}
+
 // setupTestGitRepo creates a minimal git repository for testing.
 func setupTestGitRepo(t *testing.T) string {
 	t.Helper()
@@ -1325,6 +1495,33 @@ func TestSessionList(t *testing.T) {
 			t.Errorf("Expected 0 sessions for nonexistent agent, got %d", len(listResp.Sessions))
 		}
 	})
+
+	// Test 6: --since drops sessions that ended before the window
+	t.Run("list_since", func(t *testing.T) {
+		longAgo := time.Now().UTC().Add(-3 * time.Hour).Format(time.RFC3339Nano)
+		if _, err := s.RawDB().Exec(`UPDATE sessions SET ended_at = ? WHERE session_id = ?`, longAgo, sessionID1); err != nil {
+			t.Fatalf("backdate session 1: %v", err)
+		}
+
+		since := time.Now().UTC().Add(-time.Hour).Format(time.RFC3339Nano)
+		listReqJSON, _ := json.Marshal(ListSessionsRequest{Since: since})
+		resp, err := sessionHandler.HandleList(context.Background(), listReqJSON)
+		if err != nil {
+			t.Fatalf("list sessions since: %v", err)
+		}
+		var ids []string
+		for _, sess := range resp.(*ListSessionsResponse).Sessions {
+			ids = append(ids, sess.SessionID)
+		}
+		if slices.Contains(ids, sessionID1) || !slices.Contains(ids, sessionID3) {
+			t.Errorf("sessions since %s = %v, want session 3 but not session 1", since, ids)
+		}
+
+		badJSON, _ := json.Marshal(ListSessionsRequest{Since: "2h"})
+		if _, err := sessionHandler.HandleList(context.Background(), badJSON); err == nil {
+			t.Error("list with a non-RFC3339 since succeeded, want an error")
+		}
+	})
 }
 
 func TestSessionSetIntent(t *testing.T) {
@@ -1482,3 +1679,150 @@ func TestSessionSetIntent(t *testing.T) {
 		}
 	})
 }
+
+func TestSessionDeclaredFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	thrumDir := filepath.Join(tmpDir, ".thrum")
+
+	s, err := state.NewState(thrumDir, thrumDir, "test_repo_123", "")
+	if err != nil {
+		t.Fatalf("create state: %v", err)
+	}
+	defer func() { _ = s.Close() }()
+
+	ctx := context.Background()
+	agentHandler := NewAgentHandler(s)
+	registerReqJSON, _ := json.Marshal(RegisterRequest{Role: "implementer", Module: "test"})
+	registerResp, err := agentHandler.HandleRegister(ctx, registerReqJSON)
+	if err != nil {
+		t.Fatalf("register agent: %v", err)
+	}
+	agentID := registerResp.(*RegisterResponse).AgentID
+
+	sessionHandler := NewSessionHandler(s)
+	startReqJSON, _ := json.Marshal(SessionStartRequest{
+		AgentID: agentID,
+		Scopes: []types.Scope{
+			{Type: "file", Value: "internal/auth/login.go"},
+			{Type: "file", Value: "internal/auth/token.go"},
+		},
+	})
+	startResp, err := sessionHandler.HandleStart(ctx, startReqJSON)
+	if err != nil {
+		t.Fatalf("start session: %v", err)
+	}
+	sessionID := startResp.(*SessionStartResponse).SessionID
+
+	whoHas := func(file string) []AgentWorkContext {
+		t.Helper()
+		params, _ := json.Marshal(ListContextRequest{File: file})
+		resp, err := agentHandler.HandleListContext(ctx, params)
+		if err != nil {
+			t.Fatalf("list context: %v", err)
+		}
+		return resp.(*ListContextResponse).Contexts
+	}
+
+	// Declared before any heartbeat or git change.
+	contexts := whoHas("internal/auth/login.go")
+	if len(contexts) != 1 || contexts[0].AgentID != agentID {
+		t.Fatalf("who-has declared file = %+v, want %s", contexts, agentID)
+	}
+	if got := contexts[0].DeclaredFiles; len(got) != 2 || got[0] != "internal/auth/login.go" {
+		t.Errorf("declared files = %v, want both auth files", got)
+	}
+	if got := whoHas("internal/other.go"); len(got) != 0 {
+		t.Errorf("who-has undeclared file = %+v, want none", got)
+	}
+
+	teamResp, err := NewTeamHandler(s, "", nil).HandleList(ctx, json.RawMessage(`{}`))
+	if err != nil {
+		t.Fatalf("team list: %v", err)
+	}
+	members := teamResp.(*TeamListResponse).Members
+	if len(members) != 1 || len(members[0].DeclaredFiles) != 2 {
+		t.Errorf("team members = %+v, want one member with 2 declared files", members)
+	}
+
+	endReqJSON, _ := json.Marshal(SessionEndRequest{SessionID: sessionID})
+	if _, err := sessionHandler.HandleEnd(ctx, endReqJSON); err != nil {
+		t.Fatalf("end session: %v", err)
+	}
+	var n int
+	if err := s.RawDB().QueryRow(`SELECT COUNT(*) FROM session_scopes WHERE session_id = ? AND scope_type = 'file'`, sessionID).Scan(&n); err != nil {
+		t.Fatalf("count scopes: %v", err)
+	}
+	if n != 0 {
+		t.Errorf("declared files after session end = %d, want 0", n)
+	}
+}
+
+// broadcastRecorder is a subscriptions.ClientNotifier that can reach every
+// client, like the daemon's Broadcaster.
+type broadcastRecorder struct {
+	mu     sync.Mutex
+	params []map[string]any
+}
+
+func (r *broadcastRecorder) Notify(string, any) error { return nil }
+
+func (r *broadcastRecorder) BroadcastAll(notification any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if n, ok := notification.(map[string]any); ok && n["method"] == "notification.context.updated" {
+		r.params = append(r.params, n["params"].(map[string]any))
+	}
+}
+
+func TestSessionContextUpdatedNotifications(t *testing.T) {
+	tmpDir := t.TempDir()
+	thrumDir := filepath.Join(tmpDir, ".thrum")
+
+	s, err := state.NewState(thrumDir, thrumDir, "test_repo_ctxnotify", "")
+	if err != nil {
+		t.Fatalf("create state: %v", err)
+	}
+	defer func() { _ = s.Close() }()
+
+	regJSON, _ := json.Marshal(RegisterRequest{Role: "implementer", Module: "test"})
+	regResp, err := NewAgentHandler(s).HandleRegister(context.Background(), regJSON)
+	if err != nil {
+		t.Fatalf("register agent: %v", err)
+	}
+	agentID := regResp.(*RegisterResponse).AgentID
+
+	recorder := &broadcastRecorder{}
+	dispatcher := subscriptions.NewDispatcher(s.DB())
+	dispatcher.SetClientNotifier(recorder)
+	sessionHandler := NewSessionHandler(s)
+	sessionHandler.SetDispatcher(dispatcher)
+
+	startJSON, _ := json.Marshal(SessionStartRequest{AgentID: agentID})
+	startResp, err := sessionHandler.HandleStart(context.Background(), startJSON)
+	if err != nil {
+		t.Fatalf("start session: %v", err)
+	}
+	sessionID := startResp.(*SessionStartResponse).SessionID
+
+	intentJSON, _ := json.Marshal(SetIntentRequest{SessionID: sessionID, Intent: "pairing"})
+	if _, err := sessionHandler.HandleSetIntent(context.Background(), intentJSON); err != nil {
+		t.Fatalf("set intent: %v", err)
+	}
+	taskJSON, _ := json.Marshal(SetTaskRequest{SessionID: sessionID, CurrentTask: "thrum-1"})
+	if _, err := sessionHandler.HandleSetTask(context.Background(), taskJSON); err != nil {
+		t.Fatalf("set task: %v", err)
+	}
+	hbJSON, _ := json.Marshal(HeartbeatRequest{SessionID: sessionID})
+	if _, err := sessionHandler.HandleHeartbeat(context.Background(), hbJSON); err != nil {
+		t.Fatalf("heartbeat: %v", err)
+	}
+
+	if len(recorder.params) != 3 {
+		t.Fatalf("got %d context.updated notifications, want 3 (intent, task, heartbeat)", len(recorder.params))
+	}
+	for _, p := range recorder.params {
+		if p["agent_id"] != agentID || p["session_id"] != sessionID {
+			t.Errorf("params = %v, want agent %s session %s", p, agentID, sessionID)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package rpc
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/leonletto/thrum/internal/types"
+)
+
+// SessionTransferRequest represents the request for session.transfer RPC.
+type SessionTransferRequest struct {
+	FromAgentID string `json:"from_agent_id"`        // Required: agent handing off
+	ToAgentID   string `json:"to_agent_id"`          // Required: agent taking over
+	EndSource   bool   `json:"end_source,omitempty"` // End the source session with reason "handoff"
+}
+
+// SessionTransferResponse represents the response from session.transfer RPC.
+type SessionTransferResponse struct {
+	FromAgentID   string        `json:"from_agent_id"`
+	FromSessionID string        `json:"from_session_id"`
+	ToAgentID     string        `json:"to_agent_id"`
+	ToSessionID   string        `json:"to_session_id"`
+	Started       bool          `json:"started"` // the target had no active session, so one was started
+	Intent        string        `json:"intent,omitempty"`
+	CurrentTask   string        `json:"current_task,omitempty"`
+	Scopes        []types.Scope `json:"scopes,omitempty"`
+	SourceEnded   bool          `json:"source_ended,omitempty"`
+}
+
+// HandleTransfer handles the session.transfer RPC method. It copies the
+// intent, task and scopes of the source agent's active session onto the
+// target agent's active session, starting one if the target has none.
+// Empty source fields leave the target's unchanged, and refs are not copied:
+// they describe where the source agent works. With EndSource the source
+// session is then ended with reason "handoff".
+func (h *SessionHandler) HandleTransfer(ctx context.Context, params json.RawMessage) (any, error) {
+	var req SessionTransferRequest
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	if req.FromAgentID == "" || req.ToAgentID == "" {
+		return nil, fmt.Errorf("from_agent_id and to_agent_id are required")
+	}
+	if req.FromAgentID == req.ToAgentID {
+		return nil, fmt.Errorf("cannot transfer %s's work to itself", req.FromAgentID)
+	}
+
+	resp, err := h.transferSession(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	h.notifyContextUpdated(ctx, resp.ToAgentID, resp.ToSessionID)
+
+	if req.EndSource {
+		endParams, _ := json.Marshal(SessionEndRequest{SessionID: resp.FromSessionID, Reason: "handoff"})
+		if _, err := h.HandleEnd(ctx, endParams); err != nil {
+			return nil, fmt.Errorf("work transferred to %s, but ending %s failed: %w", resp.ToSessionID, resp.FromSessionID, err)
+		}
+		resp.SourceEnded = true
+	}
+	return resp, nil
+}
+
+// transferSession does the copy for HandleTransfer under the state lock.
+func (h *SessionHandler) transferSession(ctx context.Context, req SessionTransferRequest) (*SessionTransferResponse, error) {
+	h.state.Lock()
+	defer h.state.Unlock()
+
+	for _, agentID := range []string{req.FromAgentID, req.ToAgentID} {
+		if err := h.verifyAgentExists(ctx, agentID); err != nil {
+			return nil, fmt.Errorf("agent not found: %w", err)
+		}
+	}
+
+	fromSession, err := h.activeSessionID(ctx, req.FromAgentID)
+	if err != nil {
+		return nil, err
+	}
+	if fromSession == "" {
+		return nil, fmt.Errorf("agent %s has no active session to transfer", req.FromAgentID)
+	}
+	// loadResumedContext reads a session's intent, task, scopes and refs.
+	source := &SessionResumeResponse{SessionID: fromSession}
+	if err := h.loadResumedContext(ctx, source); err != nil {
+		return nil, err
+	}
+
+	resp := &SessionTransferResponse{
+		FromAgentID:   req.FromAgentID,
+		FromSessionID: fromSession,
+		ToAgentID:     req.ToAgentID,
+		Intent:        source.Intent,
+		CurrentTask:   source.CurrentTask,
+		Scopes:        source.Scopes,
+	}
+
+	resp.ToSessionID, err = h.activeSessionID(ctx, req.ToAgentID)
+	if err != nil {
+		return nil, err
+	}
+	if resp.ToSessionID == "" {
+		started, err := h.startSession(ctx, SessionStartRequest{AgentID: req.ToAgentID})
+		if err != nil {
+			return nil, fmt.Errorf("start session for %s: %w", req.ToAgentID, err)
+		}
+		resp.ToSessionID = started.SessionID
+		resp.Started = true
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	db := h.state.DB()
+	for _, scope := range source.Scopes {
+		if _, err := db.ExecContext(ctx, `
+			INSERT OR IGNORE INTO session_scopes (session_id, scope_type, scope_value, added_at)
+			VALUES (?, ?, ?, ?)
+		`, resp.ToSessionID, scope.Type, scope.Value, now); err != nil {
+			return nil, fmt.Errorf("add scope: %w", err)
+		}
+	}
+
+	if _, err := db.ExecContext(ctx, `
+		INSERT OR IGNORE INTO agent_work_contexts (session_id, agent_id)
+		VALUES (?, ?)
+	`, resp.ToSessionID, req.ToAgentID); err != nil {
+		return nil, fmt.Errorf("create work context: %w", err)
+	}
+	if source.Intent != "" {
+		if _, err := db.ExecContext(ctx, `
+			UPDATE agent_work_contexts SET intent = ?, intent_updated_at = ? WHERE session_id = ?
+		`, source.Intent, now, resp.ToSessionID); err != nil {
+			return nil, fmt.Errorf("update intent: %w", err)
+		}
+	}
+	if source.CurrentTask != "" {
+		if _, err := db.ExecContext(ctx, `
+			UPDATE agent_work_contexts SET current_task = ?, task_updated_at = ? WHERE session_id = ?
+		`, source.CurrentTask, now, resp.ToSessionID); err != nil {
+			return nil, fmt.Errorf("update task: %w", err)
+		}
+	}
+
+	return resp, nil
+}
+
+// activeSessionID returns the agent's most recently started open session,
+// or "" when it has none.
+func (h *SessionHandler) activeSessionID(ctx context.Context, agentID string) (string, error) {
+	var sessionID string
+	err := h.state.DB().QueryRowContext(ctx, `
+		SELECT session_id FROM sessions
+		WHERE agent_id = ? AND ended_at IS NULL
+		ORDER BY started_at DESC LIMIT 1
+	`, agentID).Scan(&sessionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("query active session for %s: %w", agentID, err)
+	}
+	return sessionID, nil
+}
@@ -0,0 +1,111 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/leonletto/thrum/internal/daemon/state"
+	"github.com/leonletto/thrum/internal/types"
+)
+
+func TestSessionTransfer(t *testing.T) {
+	tmpDir := t.TempDir()
+	s, err := state.NewState(filepath.Join(tmpDir, ".thrum"), filepath.Join(tmpDir, ".thrum"), "test_repo_123", "")
+	if err != nil {
+		t.Fatalf("create state: %v", err)
+	}
+	defer func() { _ = s.Close() }()
+	ctx := context.Background()
+
+	register := func(role string) string {
+		t.Helper()
+		reqJSON, _ := json.Marshal(RegisterRequest{Role: role, Module: "test"})
+		resp, err := NewAgentHandler(s).HandleRegister(ctx, reqJSON)
+		if err != nil {
+			t.Fatalf("register %s: %v", role, err)
+		}
+		return resp.(*RegisterResponse).AgentID
+	}
+	fromID, toID := register("implementer"), register("reviewer")
+
+	sessionHandler := NewSessionHandler(s)
+	transfer := func(req SessionTransferRequest) (*SessionTransferResponse, error) {
+		t.Helper()
+		reqJSON, _ := json.Marshal(req)
+		resp, err := sessionHandler.HandleTransfer(ctx, reqJSON)
+		if err != nil {
+			return nil, err
+		}
+		return resp.(*SessionTransferResponse), nil
+	}
+
+	if _, err := transfer(SessionTransferRequest{FromAgentID: fromID, ToAgentID: toID}); err == nil || !strings.Contains(err.Error(), "no active session") {
+		t.Errorf("transfer without a source session: err = %v, want no-active-session error", err)
+	}
+	if _, err := transfer(SessionTransferRequest{FromAgentID: fromID, ToAgentID: fromID}); err == nil {
+		t.Error("transfer to self succeeded, want an error")
+	}
+
+	startJSON, _ := json.Marshal(SessionStartRequest{
+		AgentID: fromID,
+		Scopes:  []types.Scope{{Type: "module", Value: "auth"}},
+	})
+	startResp, err := sessionHandler.HandleStart(ctx, startJSON)
+	if err != nil {
+		t.Fatalf("start source session: %v", err)
+	}
+	fromSession := startResp.(*SessionStartResponse).SessionID
+	intentJSON, _ := json.Marshal(SetIntentRequest{SessionID: fromSession, Intent: "Refactoring auth"})
+	if _, err := sessionHandler.HandleSetIntent(ctx, intentJSON); err != nil {
+		t.Fatalf("set intent: %v", err)
+	}
+	taskJSON, _ := json.Marshal(SetTaskRequest{SessionID: fromSession, CurrentTask: "beads:thrum-abc"})
+	if _, err := sessionHandler.HandleSetTask(ctx, taskJSON); err != nil {
+		t.Fatalf("set task: %v", err)
+	}
+
+	// The target has no session yet, so one is started for it.
+	resp, err := transfer(SessionTransferRequest{FromAgentID: fromID, ToAgentID: toID, EndSource: true})
+	if err != nil {
+		t.Fatalf("transfer: %v", err)
+	}
+	if !resp.Started || resp.FromSessionID != fromSession || resp.ToSessionID == "" || !resp.SourceEnded {
+		t.Errorf("transfer = %+v, want a started target session and the source ended", resp)
+	}
+
+	var intent, task string
+	if err := s.RawDB().QueryRow(`SELECT intent, current_task FROM agent_work_contexts WHERE session_id = ?`, resp.ToSessionID).Scan(&intent, &task); err != nil {
+		t.Fatalf("read target work context: %v", err)
+	}
+	if intent != "Refactoring auth" || task != "beads:thrum-abc" {
+		t.Errorf("target intent/task = %q/%q, want the source's", intent, task)
+	}
+	var scope string
+	if err := s.RawDB().QueryRow(`SELECT scope_value FROM session_scopes WHERE session_id = ? AND scope_type = 'module'`, resp.ToSessionID).Scan(&scope); err != nil || scope != "auth" {
+		t.Errorf("target module scope = %q (err %v), want auth", scope, err)
+	}
+	var endReason string
+	if err := s.RawDB().QueryRow(`SELECT end_reason FROM sessions WHERE session_id = ?`, fromSession).Scan(&endReason); err != nil {
+		t.Fatalf("read source session: %v", err)
+	}
+	if endReason != "handoff" {
+		t.Errorf("source end_reason = %q, want handoff", endReason)
+	}
+
+	// Transferring back onto fromID's new session reuses it rather than
+	// starting another, and leaves the source open without EndSource.
+	restartJSON, _ := json.Marshal(SessionStartRequest{AgentID: fromID})
+	if _, err := sessionHandler.HandleStart(ctx, restartJSON); err != nil {
+		t.Fatalf("restart source session: %v", err)
+	}
+	back, err := transfer(SessionTransferRequest{FromAgentID: toID, ToAgentID: fromID})
+	if err != nil {
+		t.Fatalf("transfer back: %v", err)
+	}
+	if back.Started || back.SourceEnded {
+		t.Errorf("transfer back = %+v, want the existing session reused and the source left open", back)
+	}
+}
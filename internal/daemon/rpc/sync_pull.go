@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/leonletto/thrum/internal/daemon/eventlog"
+	"github.com/leonletto/thrum/internal/transport"
 )
 
 // MaxSyncBatchSize is the maximum number of events returned in a single sync.pull response.
@@ -34,9 +35,16 @@ type EventQuerier interface {
 	GetEventsSince(ctx context.Context, afterSeq int64, limit int) ([]eventlog.Event, int64, bool, error)
 }
 
+// PullRecorder records how far a peer has pulled our event log. A peer
+// asking for events after N already holds everything up to N.
+type PullRecorder interface {
+	RecordPull(token string, afterSeq int64)
+}
+
 // SyncPullHandler handles the sync.pull RPC method.
 type SyncPullHandler struct {
-	querier EventQuerier
+	querier  EventQuerier
+	recorder PullRecorder
 }
 
 // NewSyncPullHandler creates a new sync.pull handler.
@@ -44,6 +52,12 @@ func NewSyncPullHandler(querier EventQuerier) *SyncPullHandler {
 	return &SyncPullHandler{querier: querier}
 }
 
+// SetPullRecorder sets where peer pull progress is recorded. thrum daemon gc
+// reads it to check every peer is caught up before dropping events.
+func (h *SyncPullHandler) SetPullRecorder(r PullRecorder) {
+	h.recorder = r
+}
+
 // Handle handles a sync.pull request.
 func (h *SyncPullHandler) Handle(ctx context.Context, params json.RawMessage) (any, error) {
 	var req SyncPullRequest
@@ -72,6 +86,12 @@ func (h *SyncPullHandler) Handle(ctx context.Context, params json.RawMessage) (a
 		events = []eventlog.Event{}
 	}
 
+	if h.recorder != nil {
+		if token := transport.PeerToken(ctx); token != "" {
+			h.recorder.RecordPull(token, req.AfterSequence)
+		}
+	}
+
 	return SyncPullResponse{
 		Events:        events,
 		NextSequence:  nextSeq,
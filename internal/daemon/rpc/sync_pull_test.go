@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/leonletto/thrum/internal/daemon/eventlog"
+	"github.com/leonletto/thrum/internal/transport"
 )
 
 // mockEventQuerier implements EventQuerier for testing.
@@ -162,3 +163,33 @@ func TestSyncPullHandler_NegativeSequence(t *testing.T) {
 		t.Error("expected error for negative after_sequence")
 	}
 }
+
+// pullRecorder records RecordPull calls.
+type pullRecorder struct {
+	tokens []string
+	seqs   []int64
+}
+
+func (r *pullRecorder) RecordPull(token string, afterSeq int64) {
+	r.tokens = append(r.tokens, token)
+	r.seqs = append(r.seqs, afterSeq)
+}
+
+func TestSyncPullHandler_RecordsPeerProgress(t *testing.T) {
+	h := NewSyncPullHandler(&mockEventQuerier{})
+	rec := &pullRecorder{}
+	h.SetPullRecorder(rec)
+
+	params, _ := json.Marshal(SyncPullRequest{AfterSequence: 17})
+	if _, err := h.Handle(transport.WithPeerToken(context.Background(), "tok_peer"), params); err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+	// A caller that did not authenticate as a peer is not recorded.
+	if _, err := h.Handle(context.Background(), params); err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+
+	if len(rec.tokens) != 1 || rec.tokens[0] != "tok_peer" || rec.seqs[0] != 17 {
+		t.Errorf("recorded %v / %v, want one pull by tok_peer after 17", rec.tokens, rec.seqs)
+	}
+}
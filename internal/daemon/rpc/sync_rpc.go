@@ -3,12 +3,21 @@ package rpc
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"time"
 
 	"github.com/leonletto/thrum/internal/sync"
 )
 
-// SyncForceRequest represents a request to force a sync.
-type SyncForceRequest struct{}
+// SyncForceRequest represents a request to force a sync. PushOnly and
+// PullOnly are mutually exclusive; neither set syncs both directions.
+type SyncForceRequest struct {
+	PushOnly bool `json:"push_only,omitempty"` // Commit + push local events without fetching
+	PullOnly bool `json:"pull_only,omitempty"` // Fetch + merge remote events without pushing
+	// ForceEvenIfPaused runs the sync although sync is paused (sync.pause).
+	// Without it a paused loop rejects the request. The pause stays in place.
+	ForceEvenIfPaused bool `json:"force_even_if_paused,omitempty"`
+}
 
 // SyncForceResponse represents the response from a force sync.
 type SyncForceResponse struct {
@@ -17,6 +26,7 @@ type SyncForceResponse struct {
 	SyncState       string `json:"sync_state"`   // "running", "idle", "local-only"
 	LocalOnly       bool   `json:"local_only"`   // Whether running in local-only mode
 	LocalOnlyReason string `json:"local_only_reason,omitempty"`
+	Direction       string `json:"direction"` // "both", "push", or "pull"
 }
 
 // SyncStatusRequest represents a request for sync status.
@@ -27,9 +37,42 @@ type SyncStatusResponse struct {
 	Running         bool   `json:"running"`      // Whether sync loop is running
 	LastSyncAt      string `json:"last_sync_at"` // ISO 8601 timestamp of last sync
 	LastError       string `json:"last_error,omitempty"`
-	SyncState       string `json:"sync_state"` // "running", "idle", "error", "local-only"
+	SyncState       string `json:"sync_state"` // "running", "idle", "error", "local-only", "paused"
 	LocalOnly       bool   `json:"local_only"` // Whether running in local-only mode
 	LocalOnlyReason string `json:"local_only_reason,omitempty"`
+	Paused          bool   `json:"paused,omitempty"`
+	PausedAt        string `json:"paused_at,omitempty"`    // ISO 8601
+	PausedUntil     string `json:"paused_until,omitempty"` // ISO 8601 auto-resume time; omitted when paused until sync.resume
+
+	// Monitoring fields. LastSyncUnix is 0 before the first successful
+	// sync; PendingPushCount is omitted when git cannot count it.
+	LastSyncUnix        int64 `json:"last_sync_unix"`
+	ConsecutiveFailures int   `json:"consecutive_failures"`
+	PendingPushCount    *int  `json:"pending_push_count,omitempty"` // a-sync commits not yet on origin/a-sync
+}
+
+// SyncPauseRequest represents a request to pause git sync.
+type SyncPauseRequest struct {
+	Duration string `json:"duration,omitempty"` // Go duration until auto-resume; empty = until sync.resume
+}
+
+// SyncPauseResponse is returned by sync.pause and sync.resume.
+type SyncPauseResponse struct {
+	Paused      bool   `json:"paused"`
+	WasPaused   bool   `json:"was_paused"`
+	PausedUntil string `json:"paused_until,omitempty"` // ISO 8601
+}
+
+// SyncLogRequest represents a request for the sync attempt log.
+type SyncLogRequest struct{}
+
+// SyncLogResponse lists recent sync attempts, newest first. The log lives
+// in daemon memory: Persistent is always false, and the log starts empty
+// after a daemon restart.
+type SyncLogResponse struct {
+	Attempts   []sync.SyncAttempt `json:"attempts"`
+	Capacity   int                `json:"capacity"` // Max attempts kept
+	Persistent bool               `json:"persistent"`
 }
 
 // SyncForceHandler handles forced sync requests.
@@ -46,8 +89,32 @@ func NewSyncForceHandler(syncLoop *sync.SyncLoop) *SyncForceHandler {
 
 // Handle triggers a manual sync.
 func (h *SyncForceHandler) Handle(ctx context.Context, params json.RawMessage) (any, error) {
+	var req SyncForceRequest
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &req); err != nil {
+			return nil, fmt.Errorf("invalid request: %w", err)
+		}
+	}
+	if req.PushOnly && req.PullOnly {
+		return nil, fmt.Errorf("push_only and pull_only are mutually exclusive")
+	}
+	direction := sync.DirectionBoth
+	switch {
+	case req.PushOnly:
+		direction = sync.DirectionPushOnly
+	case req.PullOnly:
+		direction = sync.DirectionPullOnly
+	}
+
 	// Trigger manual sync (non-blocking)
-	h.syncLoop.TriggerSync()
+	switch {
+	case !h.syncLoop.IsPaused():
+		h.syncLoop.TriggerSyncDirection(direction)
+	case req.ForceEvenIfPaused:
+		h.syncLoop.ForceSyncDirection(direction)
+	default:
+		return nil, fmt.Errorf("sync is paused; run 'thrum sync resume' first, or pass --force-even-if-paused to sync once anyway")
+	}
 
 	// Get current status
 	status := h.syncLoop.GetStatus()
@@ -57,6 +124,7 @@ func (h *SyncForceHandler) Handle(ctx context.Context, params json.RawMessage) (
 		SyncState:       getSyncState(status),
 		LocalOnly:       status.LocalOnly,
 		LocalOnlyReason: status.LocalOnlyReason,
+		Direction:       string(direction),
 	}
 
 	if !status.LastSyncAt.IsZero() {
@@ -88,15 +156,93 @@ func (h *SyncStatusHandler) Handle(ctx context.Context, params json.RawMessage)
 		SyncState:       getSyncState(status),
 		LocalOnly:       status.LocalOnly,
 		LocalOnlyReason: status.LocalOnlyReason,
+		Paused:          status.Paused,
+
+		ConsecutiveFailures: status.ConsecutiveFailures,
 	}
 
 	if !status.LastSyncAt.IsZero() {
 		response.LastSyncAt = status.LastSyncAt.Format("2006-01-02T15:04:05Z07:00")
+		response.LastSyncUnix = status.LastSyncAt.Unix()
+	}
+	if n, err := h.syncLoop.PendingPushCount(ctx); err == nil {
+		response.PendingPushCount = &n
+	}
+	if !status.PausedAt.IsZero() {
+		response.PausedAt = status.PausedAt.Format(time.RFC3339)
+	}
+	if !status.PausedUntil.IsZero() {
+		response.PausedUntil = status.PausedUntil.Format(time.RFC3339)
 	}
 
 	return response, nil
 }
 
+// SyncPauseHandler handles sync.pause and sync.resume.
+type SyncPauseHandler struct {
+	syncLoop *sync.SyncLoop
+}
+
+// NewSyncPauseHandler creates a new sync pause handler.
+func NewSyncPauseHandler(syncLoop *sync.SyncLoop) *SyncPauseHandler {
+	return &SyncPauseHandler{
+		syncLoop: syncLoop,
+	}
+}
+
+// HandlePause stops git fetch/commit/push until sync.resume or until the
+// requested duration passes. The sync loop itself keeps running.
+func (h *SyncPauseHandler) HandlePause(ctx context.Context, params json.RawMessage) (any, error) {
+	var req SyncPauseRequest
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &req); err != nil {
+			return nil, fmt.Errorf("invalid request: %w", err)
+		}
+	}
+	var d time.Duration
+	if req.Duration != "" {
+		var err error
+		d, err = time.ParseDuration(req.Duration)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid duration %q: must be a positive duration like 10m", req.Duration)
+		}
+	}
+
+	wasPaused := h.syncLoop.IsPaused()
+	h.syncLoop.Pause(d)
+
+	response := SyncPauseResponse{Paused: true, WasPaused: wasPaused}
+	if until := h.syncLoop.GetStatus().PausedUntil; !until.IsZero() {
+		response.PausedUntil = until.Format(time.RFC3339)
+	}
+	return response, nil
+}
+
+// HandleResume lifts a pause and triggers a catch-up sync.
+func (h *SyncPauseHandler) HandleResume(ctx context.Context, params json.RawMessage) (any, error) {
+	return SyncPauseResponse{WasPaused: h.syncLoop.Resume()}, nil
+}
+
+// SyncLogHandler handles sync log requests.
+type SyncLogHandler struct {
+	syncLoop *sync.SyncLoop
+}
+
+// NewSyncLogHandler creates a new sync log handler.
+func NewSyncLogHandler(syncLoop *sync.SyncLoop) *SyncLogHandler {
+	return &SyncLogHandler{
+		syncLoop: syncLoop,
+	}
+}
+
+// Handle returns the recent sync attempts recorded by the sync loop.
+func (h *SyncLogHandler) Handle(ctx context.Context, params json.RawMessage) (any, error) {
+	return SyncLogResponse{
+		Attempts: h.syncLoop.SyncLog(),
+		Capacity: sync.SyncLogSize,
+	}, nil
+}
+
 // DeriveSyncState is the exported wrapper over getSyncState so callers outside
 // this package (cmd/thrum's health SyncStatusProvider) map a SyncStatus to its
 // state string through the SAME logic — no second copy of the state vocabulary.
@@ -112,6 +258,9 @@ func getSyncState(status sync.SyncStatus) string {
 	if !status.Running {
 		return "stopped"
 	}
+	if status.Paused {
+		return "paused"
+	}
 	if status.LastError != "" {
 		return "error"
 	}
@@ -3,9 +3,11 @@ package rpc
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -127,6 +129,15 @@ func TestSyncStatusHandler_Handle(t *testing.T) {
 			if statusResp2.SyncState == "" {
 				t.Error("Expected non-empty SyncState")
 			}
+			if statusResp2.LastSyncUnix == 0 {
+				t.Error("Expected last_sync_unix to be set alongside last_sync_at")
+			}
+			if statusResp2.ConsecutiveFailures != 0 {
+				t.Errorf("ConsecutiveFailures = %d after a successful sync, want 0", statusResp2.ConsecutiveFailures)
+			}
+			if statusResp2.PendingPushCount == nil {
+				t.Error("Expected pending_push_count once a-sync exists")
+			}
 			break
 		}
 		select {
@@ -185,6 +196,46 @@ func TestSyncForceHandler_LocalOnlyMode(t *testing.T) {
 	}
 }
 
+func TestSyncForceHandler_Direction(t *testing.T) {
+	tmpDir := setupTestRepo(t)
+	setupThrumFiles(t, tmpDir)
+	syncDir := filepath.Join(tmpDir, ".git", "thrum-sync", "a-sync")
+
+	syncer := sync.NewSyncer(tmpDir, syncDir, true)
+	projector := setupTestProjector(t, tmpDir)
+	loop := sync.NewSyncLoop(syncer, projector, tmpDir, syncDir, filepath.Join(tmpDir, ".thrum"), true)
+
+	ctx := context.Background()
+	if err := loop.Start(ctx); err != nil {
+		t.Fatalf("Failed to start loop: %v", err)
+	}
+	defer func() { _ = loop.Stop() }()
+
+	handler := NewSyncForceHandler(loop)
+
+	tests := []struct {
+		params string
+		want   string
+	}{
+		{`{}`, "both"},
+		{`{"push_only":true}`, "push"},
+		{`{"pull_only":true}`, "pull"},
+	}
+	for _, tt := range tests {
+		resp, err := handler.Handle(ctx, json.RawMessage(tt.params))
+		if err != nil {
+			t.Fatalf("Handle(%s) failed: %v", tt.params, err)
+		}
+		if got := resp.(SyncForceResponse).Direction; got != tt.want {
+			t.Errorf("Handle(%s).Direction = %q, want %q", tt.params, got, tt.want)
+		}
+	}
+
+	if _, err := handler.Handle(ctx, json.RawMessage(`{"push_only":true,"pull_only":true}`)); err == nil {
+		t.Error("expected error when push_only and pull_only are both set")
+	}
+}
+
 func TestSyncStatusHandler_LocalOnlyMode(t *testing.T) {
 	tmpDir := setupTestRepo(t)
 	setupThrumFiles(t, tmpDir)
@@ -486,3 +537,117 @@ func TestSyncStatus_ExposesLocalOnlyReason(t *testing.T) {
 		t.Error("response must carry LocalOnlyReason")
 	}
 }
+
+func TestSyncLogHandler_Handle(t *testing.T) {
+	tmpDir := setupTestRepo(t)
+	setupThrumFiles(t, tmpDir)
+	syncDir := filepath.Join(tmpDir, ".git", "thrum-sync", "a-sync")
+
+	syncer := sync.NewSyncer(tmpDir, syncDir, true)
+	projector := setupTestProjector(t, tmpDir)
+	loop := sync.NewSyncLoop(syncer, projector, tmpDir, syncDir, filepath.Join(tmpDir, ".thrum"), true)
+
+	ctx := context.Background()
+	handler := NewSyncLogHandler(loop)
+
+	resp, err := handler.Handle(ctx, nil)
+	if err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+	logResp := resp.(SyncLogResponse)
+	if len(logResp.Attempts) != 0 || logResp.Persistent || logResp.Capacity != sync.SyncLogSize {
+		t.Fatalf("before start = %+v, want empty, non-persistent, capacity %d", logResp, sync.SyncLogSize)
+	}
+
+	if err := loop.Start(ctx); err != nil {
+		t.Fatalf("Failed to start loop: %v", err)
+	}
+	defer func() { _ = loop.Stop() }()
+
+	deadline := time.After(2 * time.Second)
+	for len(logResp.Attempts) == 0 {
+		select {
+		case <-deadline:
+			t.Fatal("startup sync never appeared in the log")
+		default:
+			time.Sleep(20 * time.Millisecond)
+		}
+		resp, err = handler.Handle(ctx, nil)
+		if err != nil {
+			t.Fatalf("Handle failed: %v", err)
+		}
+		logResp = resp.(SyncLogResponse)
+	}
+	if logResp.Attempts[0].Direction != sync.DirectionBoth {
+		t.Errorf("startup attempt direction = %q, want both", logResp.Attempts[0].Direction)
+	}
+}
+
+func TestSyncPauseHandler(t *testing.T) {
+	tmpDir := setupTestRepo(t)
+	setupThrumFiles(t, tmpDir)
+	syncDir := filepath.Join(tmpDir, ".git", "thrum-sync", "a-sync")
+
+	syncer := sync.NewSyncer(tmpDir, syncDir, false)
+	projector := setupTestProjector(t, tmpDir)
+	loop := sync.NewSyncLoop(syncer, projector, tmpDir, syncDir, filepath.Join(tmpDir, ".thrum"), false)
+
+	ctx := context.Background()
+	if err := loop.Start(ctx); err != nil {
+		t.Fatalf("Failed to start loop: %v", err)
+	}
+	defer func() { _ = loop.Stop() }()
+
+	pause := NewSyncPauseHandler(loop)
+	force := NewSyncForceHandler(loop)
+	status := NewSyncStatusHandler(loop)
+
+	for _, bad := range []string{`{"duration":"soon"}`, `{"duration":"-5m"}`} {
+		if _, err := pause.HandlePause(ctx, json.RawMessage(bad)); err == nil {
+			t.Errorf("HandlePause(%s) should fail", bad)
+		}
+	}
+	if loop.IsPaused() {
+		t.Fatal("rejected pause request must not pause sync")
+	}
+
+	resp, err := pause.HandlePause(ctx, json.RawMessage(`{"duration":"1h"}`))
+	if err != nil {
+		t.Fatalf("HandlePause failed: %v", err)
+	}
+	pauseResp := resp.(SyncPauseResponse)
+	if !pauseResp.Paused || pauseResp.WasPaused || pauseResp.PausedUntil == "" {
+		t.Errorf("pause response = %+v, want paused with a deadline", pauseResp)
+	}
+
+	resp, err = status.Handle(ctx, nil)
+	if err != nil {
+		t.Fatalf("status Handle failed: %v", err)
+	}
+	statusResp := resp.(SyncStatusResponse)
+	if statusResp.SyncState != "paused" || !statusResp.Paused || statusResp.PausedAt == "" || statusResp.PausedUntil == "" {
+		t.Errorf("status while paused = %+v, want paused with timestamps", statusResp)
+	}
+
+	if _, err := force.Handle(ctx, json.RawMessage(`{}`)); err == nil || !strings.Contains(err.Error(), "--force-even-if-paused") {
+		t.Errorf("force while paused error = %v, want one naming --force-even-if-paused", err)
+	}
+	if _, err := force.Handle(ctx, json.RawMessage(`{"force_even_if_paused":true}`)); err != nil {
+		t.Errorf("force with override failed: %v", err)
+	}
+	if !loop.IsPaused() {
+		t.Error("forcing a sync must not lift the pause")
+	}
+
+	resp, err = pause.HandleResume(ctx, nil)
+	if err != nil {
+		t.Fatalf("HandleResume failed: %v", err)
+	}
+	if got := resp.(SyncPauseResponse); got.Paused || !got.WasPaused {
+		t.Errorf("resume response = %+v, want was_paused and not paused", got)
+	}
+	resp, _ = pause.HandleResume(ctx, nil)
+	if resp.(SyncPauseResponse).WasPaused {
+		t.Error("second resume should report was_paused=false")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
 	"github.com/leonletto/thrum/internal/config"
 	"github.com/leonletto/thrum/internal/daemon/state"
@@ -62,9 +63,11 @@ type TeamMember struct {
 	Branch          string             `json:"branch,omitempty"`
 	UnmergedCommits int                `json:"unmerged_commits"`
 	FileChanges     []types.FileChange `json:"file_changes,omitempty"`
+	DeclaredFiles   []string           `json:"declared_files,omitempty"` // Declared via session start --files
 	InboxTotal      int                `json:"inbox_total"`
 	InboxUnread     int                `json:"inbox_unread"`
-	Status          string             `json:"status"` // "active", "offline", or "reserved"
+	UnreadCount     int                `json:"unread_count"` // Unread across everything addressed to the agent (mentions, groups, broadcasts)
+	Status          string             `json:"status"`       // "active", "away", "offline", or "reserved"
 	TmuxSession     string             `json:"tmux_session,omitempty"`
 	TmuxState       string             `json:"tmux_state,omitempty"` // alive, stale, dead, or empty
 
@@ -87,6 +90,7 @@ type TeamHandler struct {
 	state              *state.State
 	thrumDir           string
 	supervisorIdentity *config.IdentityFile // synthesized virtual-supervisor identity; nil in tests
+	idle               *IdleThreshold       // nil = never report "away"
 }
 
 // NewTeamHandler creates a new team handler.
@@ -101,6 +105,12 @@ func NewTeamHandler(state *state.State, thrumDir string, supervisorIdentity *con
 	}
 }
 
+// SetIdleThreshold wires the shared idle threshold used to report a silent
+// agent as "away". Call once during daemon startup.
+func (h *TeamHandler) SetIdleThreshold(t *IdleThreshold) {
+	h.idle = t
+}
+
 // HandleList handles the team.list RPC method.
 //
 // Two-phase lock discipline (post thrum-1nkt.6, team.list is pure-read):
@@ -198,9 +208,17 @@ func (h *TeamHandler) HandleList(ctx context.Context, params json.RawMessage) (a
 	// as is an OriginDaemon that matches this daemon's own ID. Any other value
 	// means the agent lives on a remote peer daemon. This mirrors the self-heal
 	// skip guard at the top of Phase 1 (thrum-iyrt).
+	//
+	// Live local agents whose last heartbeat is older than the idle
+	// threshold are reported "away". Remote agents are skipped for the same
+	// reason: their heartbeats never reach this daemon.
+	now := time.Now()
 	for i := range members {
 		od := members[i].OriginDaemon
 		members[i].IsLocal = od == "" || od == localDaemonID
+		if members[i].Status == "active" && members[i].IsLocal && h.idle.IsAway(members[i].LastSeen, now) {
+			members[i].Status = "away"
+		}
 	}
 
 	var sharedPtr *SharedMessages
@@ -338,6 +356,14 @@ func (h *TeamHandler) buildTeamListLocked(ctx context.Context, req TeamListReque
 		return nil, nil, nil, fmt.Errorf("iterate team members: %w", err)
 	}
 
+	declared, err := loadDeclaredFiles(ctx, h.state.DB())
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	for i := range members {
+		members[i].DeclaredFiles = declared[members[i].SessionID]
+	}
+
 	// Enrich with identity file data from ALL worktrees. The identity file
 	// is authoritative for runtime, tmux_session, and tmux_state; the DB is
 	// authoritative for agent_pid. The identityMap is returned to the
@@ -463,6 +489,10 @@ func (h *TeamHandler) buildTeamListLocked(ctx context.Context, req TeamListReque
 	// (still valid after the optional filter above).
 	_ = memberIndex
 
+	// Every count below skips messages readers can't see yet or any more
+	// (scheduled, expired), the same as message.list.
+	visClause, visArgs := visibilityClause(time.Now(), false)
+
 	// Query 2: Per-agent directed message counts (mentions only, not broadcasts/groups)
 	for i, m := range members {
 		values := buildForAgentValues(m.AgentID, m.Role)
@@ -478,11 +508,12 @@ func (h *TeamHandler) buildTeamListLocked(ctx context.Context, req TeamListReque
 			 AND m.message_id IN (
 				SELECT mr.message_id FROM message_refs mr
 				WHERE mr.ref_type = 'mention' AND mr.ref_value IN (%s)
-			 )`, placeholders)
+			 )`, placeholders) + visClause
 		args := []any{m.AgentID}
 		for _, v := range values {
 			args = append(args, v)
 		}
+		args = append(args, visArgs...)
 		_ = h.state.DB().QueryRowContext(ctx, mentionQuery, args...).Scan(&members[i].InboxTotal)
 
 		// Unread: same filter, minus messages already read. thrum-b6qw (port of
@@ -499,6 +530,12 @@ func (h *TeamHandler) buildTeamListLocked(ctx context.Context, req TeamListReque
 		_ = h.state.DB().QueryRowContext(ctx, unreadQuery, unreadArgs...).Scan(&members[i].InboxUnread)
 	}
 
+	// Query 2b: Per-agent unread across the full for-agent audience, in one
+	// grouped query for the whole team.
+	if err := h.countUnreadLocked(ctx, members, visClause, visArgs); err != nil {
+		return nil, nil, nil, err
+	}
+
 	// Query 3: Shared message counts (broadcasts + per-group)
 	shared := &SharedMessages{}
 
@@ -506,15 +543,16 @@ func (h *TeamHandler) buildTeamListLocked(ctx context.Context, req TeamListReque
 	_ = h.state.DB().QueryRowContext(ctx, `SELECT COUNT(*) FROM messages m
 		WHERE m.deleted = 0
 		AND m.message_id NOT IN (SELECT mr.message_id FROM message_refs mr WHERE mr.ref_type = 'mention')
-		AND m.message_id NOT IN (SELECT ms.message_id FROM message_scopes ms WHERE ms.scope_type = 'group')`).Scan(&shared.BroadcastTotal)
+		AND m.message_id NOT IN (SELECT ms.message_id FROM message_scopes ms WHERE ms.scope_type = 'group')`+visClause,
+		visArgs...).Scan(&shared.BroadcastTotal)
 
 	// Per-group message counts
 	groupRows, err := h.state.DB().QueryContext(ctx, `SELECT ms.scope_value, COUNT(DISTINCT m.message_id)
 		FROM messages m
 		JOIN message_scopes ms ON m.message_id = ms.message_id AND ms.scope_type = 'group'
-		WHERE m.deleted = 0
+		WHERE m.deleted = 0`+visClause+`
 		GROUP BY ms.scope_value
-		ORDER BY COUNT(DISTINCT m.message_id) DESC`)
+		ORDER BY COUNT(DISTINCT m.message_id) DESC`, visArgs...)
 	if err == nil {
 		defer func() { _ = groupRows.Close() }()
 		for groupRows.Next() {
@@ -532,6 +570,101 @@ func (h *TeamHandler) buildTeamListLocked(ctx context.Context, req TeamListReque
 	return members, shared, identityMap, nil
 }
 
+// countUnreadLocked sets UnreadCount on every member: messages addressed to
+// the agent under the same rules as buildForAgentClause (mention of its name
+// or role, membership of a scoped group directly or through nested groups,
+// legacy unaddressed broadcast, or a broadcast delivered to it), excluding its
+// own and deleted messages, with no read receipt in message_deliveries for it.
+// visClause and visArgs come from visibilityClause.
+//
+// buildForAgentClause is a per-agent WHERE clause; running it per member
+// would cost one scan of messages per agent. Instead the targeting rules are
+// expressed as joins that emit (agent, message) candidate pairs, so the team
+// is counted with a single GROUP BY. member_groups walks nested group
+// membership for every member at once; UNION stops at cycles. Only
+// unaddressed broadcasts fan out to every member, which is inherent to their
+// meaning. The caller MUST hold h.state.RLock().
+func (h *TeamHandler) countUnreadLocked(ctx context.Context, members []TeamMember, visClause string, visArgs []any) error {
+	if len(members) == 0 {
+		return nil
+	}
+
+	rows := make([]string, len(members))
+	args := make([]any, 0, 2*len(members))
+	for i, m := range members {
+		rows[i] = "(?, ?)"
+		args = append(args, m.AgentID, m.Role)
+	}
+
+	query := `WITH RECURSIVE team(agent_id, role) AS (VALUES ` + strings.Join(rows, ", ") + `),
+	member_groups(agent_id, name) AS (
+		SELECT t.agent_id, g.name
+		FROM team t
+		JOIN group_members gm ON (gm.member_type = 'agent' AND gm.member_value = t.agent_id)
+			OR (gm.member_type = 'role' AND (gm.member_value = COALESCE(NULLIF(t.role, ''), t.agent_id)
+				OR (t.role != '' AND gm.member_value = '*')))
+		JOIN groups g ON g.group_id = gm.group_id
+		UNION
+		SELECT mg.agent_id, g.name
+		FROM member_groups mg
+		JOIN group_members gm ON gm.member_type = 'group' AND gm.member_value = mg.name
+		JOIN groups g ON g.group_id = gm.group_id
+	),
+	addressed(agent_id, message_id) AS (
+		SELECT t.agent_id, mr.message_id
+		FROM team t
+		JOIN message_refs mr ON mr.ref_type = 'mention'
+			AND (mr.ref_value = t.agent_id OR mr.ref_value = 'user:' || t.agent_id OR (t.role != '' AND mr.ref_value = t.role))
+		UNION
+		SELECT mg.agent_id, ms.message_id
+		FROM member_groups mg
+		JOIN message_scopes ms ON ms.scope_type = 'group' AND ms.scope_value = mg.name
+		UNION
+		SELECT t.agent_id, md.message_id
+		FROM team t
+		JOIN message_deliveries md ON md.recipient_agent_id = t.agent_id
+		WHERE EXISTS (SELECT 1 FROM message_scopes ms WHERE ms.message_id = md.message_id AND ms.scope_type = 'broadcast')
+		UNION
+		SELECT t.agent_id, m.message_id
+		FROM messages m, team t
+		WHERE NOT EXISTS (SELECT 1 FROM message_refs mr WHERE mr.message_id = m.message_id AND mr.ref_type IN ('mention', 'group', 'broadcast'))
+		  AND NOT EXISTS (SELECT 1 FROM message_scopes ms WHERE ms.message_id = m.message_id AND ms.scope_type IN ('group', 'broadcast'))
+	)
+	SELECT a.agent_id, COUNT(*)
+	FROM addressed a
+	JOIN messages m ON m.message_id = a.message_id
+	WHERE m.deleted = 0 AND m.agent_id != a.agent_id
+	  AND NOT EXISTS (
+		SELECT 1 FROM message_deliveries md
+		WHERE md.message_id = a.message_id AND md.recipient_agent_id = a.agent_id AND md.read_at IS NOT NULL
+	  )` + visClause + `
+	GROUP BY a.agent_id`
+	args = append(args, visArgs...)
+
+	result, err := h.state.DB().QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("count unread per agent: %w", err)
+	}
+	defer func() { _ = result.Close() }()
+
+	counts := make(map[string]int, len(members))
+	for result.Next() {
+		var agentID string
+		var n int
+		if err := result.Scan(&agentID, &n); err != nil {
+			return fmt.Errorf("scan unread count: %w", err)
+		}
+		counts[agentID] = n
+	}
+	if err := result.Err(); err != nil {
+		return fmt.Errorf("iterate unread counts: %w", err)
+	}
+	for i := range members {
+		members[i].UnreadCount = counts[members[i].AgentID]
+	}
+	return nil
+}
+
 // parseSessionName extracts the tmux session name portion from a
 // "session:window.pane" target string.
 func parseSessionName(target string) string {
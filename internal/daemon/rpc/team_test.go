@@ -183,6 +183,102 @@ func TestTeamHandleList(t *testing.T) {
 	})
 }
 
+// TestTeamList_UnreadCount pins UnreadCount: every message addressed to the
+// agent (mention, role mention, group, nested group, broadcast) minus its own,
+// deleted, and read ones — including for offline agents listed via IncludeOffline.
+func TestTeamList_UnreadCount(t *testing.T) {
+	tmpDir := t.TempDir()
+	thrumDir := filepath.Join(tmpDir, ".thrum")
+	syncDir := filepath.Join(thrumDir, "sync")
+	if err := os.MkdirAll(syncDir, 0750); err != nil {
+		t.Fatalf("create sync dir: %v", err)
+	}
+	s, err := state.NewState(thrumDir, syncDir, "test_repo_team_unread", "")
+	if err != nil {
+		t.Fatalf("create state: %v", err)
+	}
+	defer func() { _ = s.Close() }()
+
+	ctx := context.Background()
+	agentHandler := NewAgentHandler(s)
+	register := func(role string) string {
+		t.Helper()
+		params, _ := json.Marshal(RegisterRequest{Role: role, Module: "core"})
+		resp, err := agentHandler.HandleRegister(ctx, params)
+		if err != nil {
+			t.Fatalf("register %s: %v", role, err)
+		}
+		return resp.(*RegisterResponse).AgentID
+	}
+	author := register("implementer")
+	reader := register("reviewer") // never starts a session: offline
+
+	db := s.RawDB()
+	exec := func(query string, args ...any) {
+		t.Helper()
+		if _, err := db.Exec(query, args...); err != nil {
+			t.Fatalf("exec %q: %v", query, err)
+		}
+	}
+	insertMsg := func(id string, deleted int) {
+		t.Helper()
+		exec(`INSERT INTO messages (message_id, agent_id, session_id, created_at, body_format, body_content, deleted)
+			VALUES (?, ?, 'ses_x', datetime('now'), 'markdown', 'x', ?)`, id, author, deleted)
+	}
+
+	insertMsg("msg_mention", 0)
+	exec(`INSERT INTO message_refs VALUES ('msg_mention', 'mention', ?)`, reader)
+	insertMsg("msg_role", 0)
+	exec(`INSERT INTO message_refs VALUES ('msg_role', 'mention', 'reviewer')`)
+	insertMsg("msg_group", 0)
+	exec(`INSERT INTO groups (group_id, name, created_at, created_by) VALUES ('grp_1', 'eng', datetime('now'), 'x')`)
+	exec(`INSERT INTO group_members (group_id, member_type, member_value, added_at) VALUES ('grp_1', 'agent', ?, datetime('now'))`, reader)
+	exec(`INSERT INTO message_scopes VALUES ('msg_group', 'group', 'eng')`)
+	insertMsg("msg_nested_group", 0)
+	exec(`INSERT INTO groups (group_id, name, created_at, created_by) VALUES ('grp_2', 'org', datetime('now'), 'x')`)
+	exec(`INSERT INTO group_members (group_id, member_type, member_value, added_at) VALUES ('grp_2', 'group', 'eng', datetime('now'))`)
+	exec(`INSERT INTO message_scopes VALUES ('msg_nested_group', 'group', 'org')`)
+	// A membership cycle (eng in org, org in eng) must not loop or double count.
+	exec(`INSERT INTO group_members (group_id, member_type, member_value, added_at) VALUES ('grp_1', 'group', 'org', datetime('now'))`)
+	insertMsg("msg_legacy_broadcast", 0)
+	insertMsg("msg_broadcast", 0)
+	exec(`INSERT INTO message_scopes VALUES ('msg_broadcast', 'broadcast', 'everyone')`)
+	exec(`INSERT INTO message_deliveries (message_id, recipient_agent_id, delivered_at) VALUES ('msg_broadcast', ?, datetime('now'))`, reader)
+	insertMsg("msg_read", 0)
+	exec(`INSERT INTO message_refs VALUES ('msg_read', 'mention', ?)`, reader)
+	exec(`INSERT INTO message_deliveries (message_id, recipient_agent_id, delivered_at, read_at) VALUES ('msg_read', ?, datetime('now'), datetime('now'))`, reader)
+	insertMsg("msg_deleted", 1)
+	exec(`INSERT INTO message_refs VALUES ('msg_deleted', 'mention', ?)`, reader)
+	// Scheduled and expired messages don't count until (or after) they are
+	// visible.
+	insertMsg("msg_scheduled", 0)
+	exec(`INSERT INTO message_refs VALUES ('msg_scheduled', 'mention', ?)`, reader)
+	exec(`UPDATE messages SET deliver_at = '2999-01-01T00:00:00Z' WHERE message_id = 'msg_scheduled'`)
+	insertMsg("msg_expired", 0)
+	exec(`INSERT INTO message_refs VALUES ('msg_expired', 'mention', ?)`, reader)
+	exec(`UPDATE messages SET expires_at = '2000-01-01T00:00:00Z' WHERE message_id = 'msg_expired'`)
+	insertMsg("msg_other_group", 0)
+	exec(`INSERT INTO message_refs VALUES ('msg_other_group', 'mention', 'coordinator')`)
+
+	params, _ := json.Marshal(TeamListRequest{IncludeOffline: true})
+	resp, err := NewTeamHandler(s, "", nil).HandleList(ctx, params)
+	if err != nil {
+		t.Fatalf("HandleList: %v", err)
+	}
+	counts := map[string]int{}
+	for _, m := range resp.(*TeamListResponse).Members {
+		counts[m.AgentID] = m.UnreadCount
+	}
+	// mention + role mention + group + nested group + legacy broadcast +
+	// delivered broadcast
+	if counts[reader] != 6 {
+		t.Errorf("reader UnreadCount = %d, want 6", counts[reader])
+	}
+	if counts[author] != 0 {
+		t.Errorf("author UnreadCount = %d, want 0 (own messages excluded)", counts[author])
+	}
+}
+
 func TestResolveHostname(t *testing.T) {
 	t.Run("env_override", func(t *testing.T) {
 		t.Setenv("THRUM_HOSTNAME", "my-machine")
@@ -34,6 +34,7 @@ type Server struct {
 	connsMu          sync.Mutex            // protects conns
 	conns            map[net.Conn]struct{} // active client connections
 	identityResolver peercred.Resolver     // optional; nil disables per-connection identity resolution (tests, early boot)
+	callObserver     func(method string)   // optional; see SetCallObserver
 }
 
 // NewServer creates a new RPC server.
@@ -81,6 +82,16 @@ func (s *Server) SetIdentityResolver(r peercred.Resolver) {
 	s.identityResolver = r
 }
 
+// SetCallObserver registers fn to be called with the method name of every
+// request that reaches a registered handler. Used to count RPC calls for the
+// metrics endpoint; fn must be cheap and safe for concurrent use. Call it
+// before Start.
+func (s *Server) SetCallObserver(fn func(method string)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.callObserver = fn
+}
+
 // anonymousAllowedMethods is the allowlist of JSON-RPC methods that callers
 // without a resolved peercred identity may still invoke. The list is the
 // union of:
@@ -147,6 +158,9 @@ var anonymousAllowedMethods = map[string]bool{
 	"agent.register":    true,
 	"session.start":     true,
 	"session.setIntent": true,
+	// session.resume is the crash-recovery counterpart of session.start and
+	// runs from a fresh shell whose connection peercred may not yet match.
+	"session.resume": true,
 	// Bootstrap (thrum-5oui — DO NOT REMOVE without re-reading the safety
 	// invariant below): `thrum tmux start` is the agent-restart entry point.
 	// On ONE connection it calls tmux.create (no_agent=true) THEN tmux.launch:
@@ -368,6 +382,7 @@ func (s *Server) handleConnection(ctx context.Context, conn net.Conn) {
 		// Get handler
 		s.mu.RLock()
 		handler, ok := s.handlers[req.Method]
+		observe := s.callObserver
 		s.mu.RUnlock()
 
 		if !ok {
@@ -386,6 +401,10 @@ func (s *Server) handleConnection(ctx context.Context, conn net.Conn) {
 			continue
 		}
 
+		if observe != nil {
+			observe(req.Method)
+		}
+
 		// Default nil params to empty JSON object so handlers can always unmarshal.
 		reqParams := req.Params
 		if reqParams == nil {
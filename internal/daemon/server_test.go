@@ -168,6 +168,49 @@ func TestServerMethodNotFound(t *testing.T) {
 	}
 }
 
+func TestServerCallObserver(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "test.sock")
+	server := NewServer(socketPath)
+	server.RegisterHandler("test_method", func(ctx context.Context, params json.RawMessage) (any, error) {
+		return map[string]string{"status": "ok"}, nil
+	})
+	observed := make(chan string, 4)
+	server.SetCallObserver(func(method string) { observed <- method })
+
+	if err := server.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start server: %v", err)
+	}
+	defer func() { _ = server.Stop() }()
+	waitForSocketReady(t, socketPath)
+
+	conn, err := net.Dial("unix", socketPath)
+	if err != nil {
+		t.Fatalf("failed to connect to server: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	decoder := json.NewDecoder(conn)
+	for i, method := range []string{"test_method", "nonexistent_method", "test_method"} {
+		requestJSON, _ := json.Marshal(map[string]any{"jsonrpc": "2.0", "method": method, "id": i})
+		if _, err := conn.Write(append(requestJSON, '\n')); err != nil {
+			t.Fatalf("failed to write request: %v", err)
+		}
+		var resp jsonRPCResponse
+		if err := decoder.Decode(&resp); err != nil {
+			t.Fatalf("failed to read response: %v", err)
+		}
+	}
+
+	close(observed)
+	var got []string
+	for m := range observed {
+		got = append(got, m)
+	}
+	if len(got) != 2 || got[0] != "test_method" || got[1] != "test_method" {
+		t.Errorf("observed = %v, want two test_method calls and no unknown method", got)
+	}
+}
+
 func TestServerInvalidJSONRPC(t *testing.T) {
 	tmpDir := t.TempDir()
 	socketPath := filepath.Join(tmpDir, "test.sock")
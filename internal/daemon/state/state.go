@@ -183,6 +183,17 @@ func NewState(thrumDir string, syncDir string, repoID string, daemonID string) (
 		thrumDir:     thrumDir,
 		syncDir:      syncDir,
 	}
+	// Compaction and purge delete events but not the messages projected from
+	// them, so the events table alone can rewind the counter below sequences
+	// this daemon already gave out. Messages record theirs (origin_sequence);
+	// never hand one out twice.
+	var maxMsgSeq int64
+	if err := db.QueryRow("SELECT COALESCE(MAX(origin_sequence), 0) FROM messages WHERE origin_daemon = ?", daemonID).Scan(&maxMsgSeq); err != nil {
+		_ = eventsWriter.Close()
+		_ = db.Close()
+		return nil, fmt.Errorf("load max message sequence: %w", err)
+	}
+	maxSeq = max(maxSeq, maxMsgSeq)
 	s.sequence.Store(maxSeq)
 
 	// thrum-b6qw (port of tcqw): one-time read-state backfill at the v39→v40
@@ -776,3 +776,68 @@ func TestStateClose_DrainsInflightPostCommits(t *testing.T) {
 		t.Errorf("Close elapsed = %v, want ≥ %v (must have waited for the in-flight goroutine)", elapsed, work/2)
 	}
 }
+
+// TestNewState_MessageSequenceSurvivesEventCompaction pins that a daemon
+// restarted after its events were compacted away does not reuse a sequence
+// already stamped on one of its messages: the counter resumes from the
+// highest origin_sequence among this daemon's messages.
+func TestNewState_MessageSequenceSurvivesEventCompaction(t *testing.T) {
+	tmp := t.TempDir()
+	thrumDir := filepath.Join(tmp, ".thrum")
+	_ = os.MkdirAll(thrumDir, 0o750)
+
+	s, err := NewState(thrumDir, thrumDir, "r_test_seq", "d_local")
+	if err != nil {
+		t.Fatalf("NewState: %v", err)
+	}
+	for i, id := range []string{"msg_seq_1", "msg_seq_2"} {
+		event := types.MessageCreateEvent{
+			Type:      "message.create",
+			Timestamp: fmt.Sprintf("2026-01-01T12:00:0%dZ", i),
+			MessageID: id,
+			AgentID:   "agent:test:ABC123",
+			SessionID: "ses_test456",
+			Body:      types.MessageBody{Format: "markdown", Content: id},
+		}
+		if _, err := s.WriteEvent(context.Background(), event); err != nil {
+			t.Fatalf("write %s: %v", id, err)
+		}
+	}
+	var origin string
+	var lastSeq int64
+	if err := s.RawDB().QueryRow(`SELECT origin_daemon, origin_sequence FROM messages WHERE message_id = 'msg_seq_2'`).Scan(&origin, &lastSeq); err != nil {
+		t.Fatalf("read message sequence: %v", err)
+	}
+	if origin != "d_local" || lastSeq == 0 {
+		t.Fatalf("msg_seq_2 origin = (%q, %d), want d_local with a non-zero sequence", origin, lastSeq)
+	}
+	// Compaction: the events go, the projected messages stay.
+	if _, err := s.RawDB().Exec(`DELETE FROM events`); err != nil {
+		t.Fatalf("compact events: %v", err)
+	}
+	_ = s.Close()
+
+	s, err = NewState(thrumDir, thrumDir, "r_test_seq", "d_local")
+	if err != nil {
+		t.Fatalf("reopen NewState: %v", err)
+	}
+	defer func() { _ = s.Close() }()
+	event := types.MessageCreateEvent{
+		Type:      "message.create",
+		Timestamp: "2026-01-01T12:00:05Z",
+		MessageID: "msg_seq_3",
+		AgentID:   "agent:test:ABC123",
+		SessionID: "ses_test456",
+		Body:      types.MessageBody{Format: "markdown", Content: "after compaction"},
+	}
+	if _, err := s.WriteEvent(context.Background(), event); err != nil {
+		t.Fatalf("write after reopen: %v", err)
+	}
+	var seq int64
+	if err := s.RawDB().QueryRow(`SELECT origin_sequence FROM messages WHERE message_id = 'msg_seq_3'`).Scan(&seq); err != nil {
+		t.Fatalf("read new message sequence: %v", err)
+	}
+	if seq <= lastSeq {
+		t.Errorf("sequence after compaction = %d, want > %d", seq, lastSeq)
+	}
+}
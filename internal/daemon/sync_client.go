@@ -188,8 +188,7 @@ func (c *SyncClient) SendNotify(peerAddr string, daemonID string, latestSeq int6
 
 // QueryPeerInfo calls sync.peer_info on a peer and returns daemon identity.
 // Token is sent as an Authorization: Bearer header.
-func (c *SyncClient) QueryPeerInfo(peerAddr string, token string) (*PeerInfoResult, error) {
-	ctx := context.Background()
+func (c *SyncClient) QueryPeerInfo(ctx context.Context, peerAddr string, token string) (*PeerInfoResult, error) {
 	wsURL := syncWSURL(peerAddr)
 
 	raw, err := c.wsCall(ctx, wsURL, "sync.peer_info", nil, tokenDialOpts(token)...)
@@ -112,7 +112,7 @@ func TestSyncClient_QueryPeerInfo(t *testing.T) {
 	addr, _ := newTestWSServer(t, reg)
 
 	client := NewSyncClient()
-	info, err := client.QueryPeerInfo(addr, "")
+	info, err := client.QueryPeerInfo(context.Background(), addr, "")
 	if err != nil {
 		t.Fatalf("QueryPeerInfo: %v", err)
 	}
@@ -406,7 +406,7 @@ func TestPullSyncPeerInfo(t *testing.T) {
 	daemonA := newTestDaemon(t, "alice")
 	client := NewSyncClient()
 
-	info, err := client.QueryPeerInfo(daemonA.addr(), "")
+	info, err := client.QueryPeerInfo(context.Background(), daemonA.addr(), "")
 	if err != nil {
 		t.Fatalf("QueryPeerInfo: %v", err)
 	}
@@ -2,6 +2,7 @@ package daemon
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"sync"
@@ -194,7 +195,7 @@ func (m *DaemonSyncManager) AddPeer(hostname string, port int) error {
 	addr := fmt.Sprintf("%s:%d", hostname, port)
 
 	// Try to query peer info (no token for initial discovery)
-	info, err := m.client.QueryPeerInfo(addr, "")
+	info, err := m.client.QueryPeerInfo(context.Background(), addr, "")
 	if err != nil {
 		// Add with hostname-derived ID if we can't reach the peer
 		return m.peers.AddPeer(&PeerInfo{
@@ -241,17 +242,23 @@ func (m *DaemonSyncManager) JoinPeer(peerAddr, code string, local PairMetadata)
 	return peer, nil
 }
 
+// ErrPeerNotFound is returned by PullFromPeerByID for a daemon ID that is not
+// a paired peer.
+var ErrPeerNotFound = errors.New("peer not found")
+
+// ErrPeerUnreachable is returned by PullFromPeerByID when the peer could not
+// be dialed, or is held back by dial backoff after earlier failures.
+var ErrPeerUnreachable = errors.New("peer unreachable")
+
 // SyncFromPeerByID resolves a daemon ID to its address and triggers a pull sync.
 // This is used by the sync.notify handler to trigger syncs from notifications.
 func (m *DaemonSyncManager) SyncFromPeerByID(daemonID string) {
-	peer := m.peers.GetPeer(daemonID)
-	if peer == nil {
+	if m.peers.GetPeer(daemonID) == nil {
 		log.Printf("sync.notify: unknown peer %s, ignoring", daemonID)
 		return
 	}
 
-	addr := peer.Addr()
-	applied, skipped, err := m.SyncFromPeer(context.Background(), addr, daemonID)
+	applied, skipped, err := m.PullFromPeerByID(context.Background(), daemonID)
 	if err != nil {
 		log.Printf("sync.notify: sync from %s failed: %v", daemonID, err)
 		return
@@ -260,6 +267,30 @@ func (m *DaemonSyncManager) SyncFromPeerByID(daemonID string) {
 	log.Printf("sync.notify: synced from %s — applied=%d skipped=%d", daemonID, applied, skipped)
 }
 
+// PullFromPeerByID pulls new events from one paired peer now and returns the
+// counts. Unlike SyncFromPeer, a peer in dial backoff is reported as
+// ErrPeerUnreachable instead of looking like "nothing new", since the caller
+// (peer.sync) is a person debugging that peer.
+func (m *DaemonSyncManager) PullFromPeerByID(ctx context.Context, daemonID string) (applied, skipped int, err error) {
+	peer := m.peers.GetPeer(daemonID)
+	if peer == nil {
+		return 0, 0, fmt.Errorf("%w: %s", ErrPeerNotFound, daemonID)
+	}
+
+	addr := peer.Addr()
+	if wait, held := m.dials.backoffRemaining(daemonID); held {
+		return 0, 0, fmt.Errorf("%w: %s at %s failed recent dials, next attempt allowed in %s; check its daemon is running (thrum peer status %s)",
+			ErrPeerUnreachable, peer.Name, addr, wait.Round(time.Second), peer.Name)
+	}
+
+	applied, skipped, err = m.SyncFromPeer(ctx, addr, daemonID)
+	if errors.Is(err, errDialFailed) {
+		return 0, 0, fmt.Errorf("%w: %s at %s: %w; check its daemon is running (thrum peer status %s)",
+			ErrPeerUnreachable, peer.Name, addr, err, peer.Name)
+	}
+	return applied, skipped, err
+}
+
 // BroadcastNotify sends sync.notify to all known peers.
 // Each peer's stored token is included for authentication.
 // This is fire-and-forget — failures are logged but don't block.
@@ -335,6 +366,24 @@ func (m *DaemonSyncManager) TailscaleSyncStatus(hostname string) (int, []PeerSta
 	return len(peerList), statuses
 }
 
+// ProbePeer calls sync.peer_info on one paired peer and returns the round
+// trip, for peer status --probe. It dials a peer in dial backoff too, since
+// the point is to find out whether it is back, but leaves the backoff state
+// alone. Callers bound ctx; a dead peer otherwise holds the call for the
+// dial timeout.
+func (m *DaemonSyncManager) ProbePeer(ctx context.Context, daemonID string) (time.Duration, error) {
+	peer := m.peers.GetPeer(daemonID)
+	if peer == nil {
+		return 0, fmt.Errorf("%w: %s", ErrPeerNotFound, daemonID)
+	}
+
+	start := time.Now()
+	if _, err := m.client.QueryPeerInfo(ctx, peer.Addr(), peer.Token); err != nil {
+		return 0, err
+	}
+	return time.Since(start), nil
+}
+
 // DetailedPeerInfo is the detailed status of a single peer.
 type DetailedPeerInfo struct {
 	DaemonID string
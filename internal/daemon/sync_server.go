@@ -131,7 +131,7 @@ func (r *SyncRegistry) ServeSyncRPC(ctx context.Context, conn net.Conn, peerID s
 		}
 
 		// Token auth for sync.* methods (pair.request is exempt)
-		var authedPeerID string
+		var authedPeerID, peerToken string
 		if r.peers != nil && methodRequiresAuth(req.Method) {
 			var te tokenExtract
 			if req.Params != nil {
@@ -148,9 +148,14 @@ func (r *SyncRegistry) ServeSyncRPC(ctx context.Context, conn net.Conn, peerID s
 				continue
 			}
 			authedPeerID = peer.DaemonID
+			peerToken = te.Token
 		}
 
-		result, err := handler(ctx, req.Params)
+		callCtx := ctx
+		if authedPeerID != "" {
+			callCtx = transport.WithPeerToken(ctx, peerToken)
+		}
+		result, err := handler(callCtx, req.Params)
 		if err != nil {
 			resp := jsonRPCResponse{
 				JSONRPC: "2.0",
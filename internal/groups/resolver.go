@@ -1,8 +1,10 @@
 package groups
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"slices"
 
 	"github.com/leonletto/thrum/internal/daemon/safedb"
 )
@@ -40,13 +42,162 @@ func (r *Resolver) IsMember(ctx context.Context, groupName, agentID, agentRole s
 }
 
 // ExpandMembers resolves a group to a deduplicated list of agent IDs.
-// Handles agent and role members (flat groups only, no nesting).
+// Handles agent and role members, and expands nested group members
+// recursively. A group reached twice (including a cycle back to an
+// ancestor) is expanded only once.
 func (r *Resolver) ExpandMembers(ctx context.Context, groupName string) ([]string, error) {
-	// Collect all members first, then close the cursor before sub-queries.
-	// SQLite with SetMaxOpenConns(1) deadlocks if we query inside an open rows cursor.
-	type member struct {
-		typ, value string
+	exp, err := r.Expand(ctx, groupName)
+	if err != nil {
+		return nil, err
+	}
+	return exp.Members, nil
+}
+
+// Expansion is a group resolved to its agents.
+type Expansion struct {
+	Members []string // deduplicated agent IDs, in resolution order
+	// Cycles lists each membership cycle met while expanding, as the group
+	// path that leads back to its first entry (ping → pong → ping is
+	// ["ping", "pong", "ping"]). Members are still complete: every group on
+	// a cycle is expanded exactly once.
+	Cycles [][]string
+}
+
+// Expand resolves a group like ExpandMembers and also reports membership
+// cycles. A group reached twice through different parents (a diamond) is not
+// a cycle.
+func (r *Resolver) Expand(ctx context.Context, groupName string) (*Expansion, error) {
+	w := &expansionWalk{visited: make(map[string]bool), seen: make(map[string]bool)}
+	if err := r.expand(ctx, groupName, w); err != nil {
+		return nil, err
+	}
+	return &w.exp, nil
+}
+
+// expansionWalk is the state shared across one Expand call.
+type expansionWalk struct {
+	exp     Expansion
+	visited map[string]bool // groups already expanded
+	seen    map[string]bool // agents already in exp.Members
+	path    []string        // groups from the root down to the current one
+}
+
+// expand appends groupName's agents to w.exp.Members, skipping agents already
+// seen and groups already visited. A group that is already on the current
+// path closes a cycle, which is recorded instead of followed.
+func (r *Resolver) expand(ctx context.Context, groupName string, w *expansionWalk) error {
+	if i := slices.Index(w.path, groupName); i >= 0 {
+		cycle := append(slices.Clone(w.path[i:]), groupName)
+		w.exp.Cycles = append(w.exp.Cycles, cycle)
+		return nil
+	}
+	if w.visited[groupName] {
+		return nil
+	}
+	w.visited[groupName] = true
+	w.path = append(w.path, groupName)
+	defer func() { w.path = w.path[:len(w.path)-1] }()
+
+	members, err := r.directMembers(ctx, groupName)
+	if err != nil {
+		return err
+	}
+
+	// Now resolve roles and nested groups with the cursor closed.
+	add := func(agentID string) {
+		if !w.seen[agentID] {
+			w.exp.Members = append(w.exp.Members, agentID)
+			w.seen[agentID] = true
+		}
+	}
+	for _, m := range members {
+		switch m.typ {
+		case "agent":
+			add(m.value)
+		case "role":
+			roleAgents, err := r.roleAgents(ctx, m.value)
+			if err != nil {
+				return err
+			}
+			for _, a := range roleAgents {
+				add(a)
+			}
+		case "group":
+			if err := r.expand(ctx, m.value, w); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Node is one member in a group's membership tree. Role members hold the
+// agents with that role as children, and group members hold the nested
+// group's own members.
+type Node struct {
+	Type     string // "agent", "role", or "group"
+	Value    string
+	Children []Node
+	// Cycle marks a group member that is already an ancestor on this branch.
+	// It is not expanded again, so its Children are empty.
+	Cycle bool
+}
+
+// Tree resolves a group to its membership tree, expanding roles to agents
+// and nested groups recursively. Members are sorted by type, then value. Unlike Expand, a group reached through two
+// parents appears under each; only a group that leads back to one of its
+// own ancestors is cut off, as a Cycle node.
+func (r *Resolver) Tree(ctx context.Context, groupName string) ([]Node, error) {
+	return r.tree(ctx, groupName, []string{groupName})
+}
+
+// tree builds the nodes for groupName's members; path is the chain of
+// groups from the root down to and including groupName.
+func (r *Resolver) tree(ctx context.Context, groupName string, path []string) ([]Node, error) {
+	members, err := r.directMembers(ctx, groupName)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(members, func(a, b member) int {
+		return cmp.Or(cmp.Compare(a.typ, b.typ), cmp.Compare(a.value, b.value))
+	})
+
+	nodes := make([]Node, 0, len(members))
+	for _, m := range members {
+		n := Node{Type: m.typ, Value: m.value}
+		switch m.typ {
+		case "role":
+			agents, err := r.roleAgents(ctx, m.value)
+			if err != nil {
+				return nil, err
+			}
+			slices.Sort(agents)
+			for _, a := range agents {
+				n.Children = append(n.Children, Node{Type: "agent", Value: a})
+			}
+		case "group":
+			if slices.Contains(path, m.value) {
+				n.Cycle = true
+				break
+			}
+			n.Children, err = r.tree(ctx, m.value, append(slices.Clone(path), m.value))
+			if err != nil {
+				return nil, err
+			}
+		}
+		nodes = append(nodes, n)
 	}
+	return nodes, nil
+}
+
+// member is a raw group_members row.
+type member struct {
+	typ, value string
+}
+
+// directMembers returns groupName's own members in insertion order. It collects every row and closes the cursor before returning: SQLite with
+// SetMaxOpenConns(1) deadlocks if callers query inside an open rows cursor.
+func (r *Resolver) directMembers(ctx context.Context, groupName string) ([]member, error) {
 	rows, err := r.db.QueryContext(ctx, `
 		SELECT gm.member_type, gm.member_value
 		FROM group_members gm
@@ -56,53 +207,28 @@ func (r *Resolver) ExpandMembers(ctx context.Context, groupName string) ([]strin
 	if err != nil {
 		return nil, fmt.Errorf("query group members: %w", err)
 	}
+	defer func() { _ = rows.Close() }()
 
 	var members []member
 	for rows.Next() {
 		var m member
 		if err := rows.Scan(&m.typ, &m.value); err != nil {
-			_ = rows.Close()
 			return nil, fmt.Errorf("scan member: %w", err)
 		}
 		members = append(members, m)
 	}
 	if err := rows.Err(); err != nil {
-		_ = rows.Close()
 		return nil, fmt.Errorf("iterate members: %w", err)
 	}
-	_ = rows.Close()
-
-	// Now resolve roles with the cursor closed.
-	var agents []string
-	seen := make(map[string]bool)
+	return members, nil
+}
 
-	for _, m := range members {
-		switch m.typ {
-		case "agent":
-			if !seen[m.value] {
-				agents = append(agents, m.value)
-				seen[m.value] = true
-			}
-		case "role":
-			var roleAgents []string
-			if m.value == "*" {
-				roleAgents, err = r.queryAllAgents(ctx)
-			} else {
-				roleAgents, err = r.queryAgentsByRole(ctx, m.value)
-			}
-			if err != nil {
-				return nil, err
-			}
-			for _, a := range roleAgents {
-				if !seen[a] {
-					agents = append(agents, a)
-					seen[a] = true
-				}
-			}
-		}
+// roleAgents returns the agents with role, or every agent for "*".
+func (r *Resolver) roleAgents(ctx context.Context, role string) ([]string, error) {
+	if role == "*" {
+		return r.queryAllAgents(ctx)
 	}
-
-	return agents, nil
+	return r.queryAgentsByRole(ctx, role)
 }
 
 func (r *Resolver) queryAgentsByRole(ctx context.Context, role string) ([]string, error) {
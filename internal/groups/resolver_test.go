@@ -3,6 +3,7 @@ package groups
 import (
 	"context"
 	"database/sql"
+	"strings"
 	"testing"
 
 	"github.com/leonletto/thrum/internal/daemon/safedb"
@@ -217,6 +218,135 @@ func TestExpandMembers_Deduplication(t *testing.T) {
 	}
 }
 
+func TestExpandMembers_NestedGroups(t *testing.T) {
+	db := setupTestDB(t)
+	sdb := safedb.New(db)
+	r := NewResolver(sdb)
+
+	insertAgent(t, db, "alice", "lead")
+	insertAgent(t, db, "bob", "reviewer")
+	insertAgent(t, db, "carol", "reviewer")
+
+	insertGroup(t, db, "grp_rev", "reviewers", "")
+	insertMember(t, db, "grp_rev", "role", "reviewer")
+	insertGroup(t, db, "grp_mid", "core", "")
+	insertMember(t, db, "grp_mid", "group", "reviewers")
+	insertMember(t, db, "grp_mid", "agent", "bob") // also reached via reviewers
+	insertGroup(t, db, "grp_top", "release", "")
+	insertMember(t, db, "grp_top", "agent", "alice")
+	insertMember(t, db, "grp_top", "group", "core")
+
+	members, err := r.ExpandMembers(context.Background(), "release")
+	if err != nil {
+		t.Fatalf("ExpandMembers: %v", err)
+	}
+	got := map[string]int{}
+	for _, m := range members {
+		got[m]++
+	}
+	if len(members) != 3 || got["alice"] != 1 || got["bob"] != 1 || got["carol"] != 1 {
+		t.Errorf("expected alice, bob, carol once each, got %v", members)
+	}
+}
+
+func TestExpandMembers_GroupCycle(t *testing.T) {
+	db := setupTestDB(t)
+	sdb := safedb.New(db)
+	r := NewResolver(sdb)
+
+	insertGroup(t, db, "grp_a", "ping", "")
+	insertMember(t, db, "grp_a", "agent", "alice")
+	insertMember(t, db, "grp_a", "group", "pong")
+	insertGroup(t, db, "grp_b", "pong", "")
+	insertMember(t, db, "grp_b", "agent", "bob")
+	insertMember(t, db, "grp_b", "group", "ping")
+
+	members, err := r.ExpandMembers(context.Background(), "ping")
+	if err != nil {
+		t.Fatalf("ExpandMembers: %v", err)
+	}
+	if len(members) != 2 || members[0] != "alice" || members[1] != "bob" {
+		t.Errorf("expected [alice bob], got %v", members)
+	}
+
+	exp, err := r.Expand(context.Background(), "ping")
+	if err != nil {
+		t.Fatalf("Expand: %v", err)
+	}
+	if len(exp.Cycles) != 1 || strings.Join(exp.Cycles[0], ",") != "ping,pong,ping" {
+		t.Errorf("expected cycle [ping pong ping], got %v", exp.Cycles)
+	}
+}
+
+func TestExpand_DiamondIsNotACycle(t *testing.T) {
+	db := setupTestDB(t)
+	sdb := safedb.New(db)
+	r := NewResolver(sdb)
+
+	// top → left → shared, top → right → shared
+	insertGroup(t, db, "grp_shared", "shared", "")
+	insertMember(t, db, "grp_shared", "agent", "carol")
+	insertGroup(t, db, "grp_left", "left", "")
+	insertMember(t, db, "grp_left", "group", "shared")
+	insertGroup(t, db, "grp_right", "right", "")
+	insertMember(t, db, "grp_right", "group", "shared")
+	insertGroup(t, db, "grp_top", "top", "")
+	insertMember(t, db, "grp_top", "group", "left")
+	insertMember(t, db, "grp_top", "group", "right")
+
+	exp, err := r.Expand(context.Background(), "top")
+	if err != nil {
+		t.Fatalf("Expand: %v", err)
+	}
+	if len(exp.Members) != 1 || exp.Members[0] != "carol" {
+		t.Errorf("expected [carol], got %v", exp.Members)
+	}
+	if len(exp.Cycles) != 0 {
+		t.Errorf("expected no cycles, got %v", exp.Cycles)
+	}
+}
+
+func TestTree(t *testing.T) {
+	db := setupTestDB(t)
+	sdb := safedb.New(db)
+	r := NewResolver(sdb)
+
+	insertAgent(t, db, "bob", "reviewer")
+	insertAgent(t, db, "carol", "reviewer")
+
+	// release → {agent alice, group core}; core → {role reviewer, group release}
+	insertGroup(t, db, "grp_top", "release", "")
+	insertMember(t, db, "grp_top", "group", "core")
+	insertMember(t, db, "grp_top", "agent", "alice")
+	insertGroup(t, db, "grp_core", "core", "")
+	insertMember(t, db, "grp_core", "role", "reviewer")
+	insertMember(t, db, "grp_core", "group", "release")
+
+	nodes, err := r.Tree(context.Background(), "release")
+	if err != nil {
+		t.Fatalf("Tree: %v", err)
+	}
+	var render func(nodes []Node) string
+	render = func(nodes []Node) string {
+		var parts []string
+		for _, n := range nodes {
+			part := n.Type + ":" + n.Value
+			if n.Cycle {
+				part += "!"
+			}
+			if len(n.Children) > 0 {
+				part += "(" + render(n.Children) + ")"
+			}
+			parts = append(parts, part)
+		}
+		return strings.Join(parts, " ")
+	}
+	want := "agent:alice group:core(group:release! role:reviewer(agent:bob agent:carol))"
+	if got := render(nodes); got != want {
+		t.Errorf("tree = %s, want %s", got, want)
+	}
+}
+
 func TestIsMember(t *testing.T) {
 	db := setupTestDB(t)
 	sdb := safedb.New(db)
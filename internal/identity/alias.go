@@ -0,0 +1,48 @@
+package identity
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// RowQuerier is the single-row read side of a database handle. Both
+// *sql.DB and the daemon's safedb wrapper satisfy it.
+type RowQuerier interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// ValidateAlias validates an agent alias. Aliases share the agent-name
+// character set (without colons, which are reserved for proxy agents) and
+// reserved words, and may not be "everyone".
+func ValidateAlias(alias string) error {
+	if alias == "" {
+		return fmt.Errorf("alias cannot be empty")
+	}
+	if reservedNames[alias] || alias == "everyone" {
+		return fmt.Errorf("alias '%s' is reserved and cannot be used", alias)
+	}
+	if !agentNameRegex.MatchString(alias) || strings.Contains(alias, ":") {
+		return fmt.Errorf("alias '%s' contains invalid characters; only lowercase letters (a-z), digits (0-9), underscores (_), and hyphens (-) are allowed", alias)
+	}
+	return nil
+}
+
+// ResolveAlias returns the agent_id that name is an alias for, or name
+// unchanged when it is not an alias. Call it before any agent lookup so
+// aliases take precedence everywhere an agent is resolved.
+func ResolveAlias(ctx context.Context, db RowQuerier, name string) (string, error) {
+	var agentID string
+	err := db.QueryRowContext(ctx,
+		`SELECT agent_id FROM agent_aliases WHERE alias = ?`, name,
+	).Scan(&agentID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return name, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("resolve alias %q: %w", name, err)
+	}
+	return agentID, nil
+}
@@ -0,0 +1,29 @@
+package identity_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/leonletto/thrum/internal/identity"
+)
+
+func TestValidateAlias(t *testing.T) {
+	for _, alias := range []string{"rev", "coord-1", "big_boss"} {
+		if err := identity.ValidateAlias(alias); err != nil {
+			t.Errorf("ValidateAlias(%q) = %v, want nil", alias, err)
+		}
+	}
+	for alias, want := range map[string]string{
+		"":         "cannot be empty",
+		"everyone": "reserved",
+		"daemon":   "reserved",
+		"Rev":      "invalid characters",
+		"tg:ops":   "invalid characters",
+		"a b":      "invalid characters",
+	} {
+		err := identity.ValidateAlias(alias)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ValidateAlias(%q) = %v, want error containing %q", alias, err, want)
+		}
+	}
+}
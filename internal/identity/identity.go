@@ -136,12 +136,26 @@ func GenerateEventID() string {
 	return "evt_" + generateULID()
 }
 
+// GenerateAssignmentID generates a unique message assignment ID using ULID.
+// Format: "asg_" + ulid().
+func GenerateAssignmentID() string {
+	return "asg_" + generateULID()
+}
+
 // GenerateGroupID generates a unique group ID using ULID.
 // Format: "grp_" + ulid().
 func GenerateGroupID() string {
 	return "grp_" + generateULID()
 }
 
+// GenerateIdempotencyKey generates a message.send idempotency key using ULID.
+// Format: "idk_" + ulid()
+// thrum send makes one per invocation so the client's retries don't
+// send twice.
+func GenerateIdempotencyKey() string {
+	return "idk_" + generateULID()
+}
+
 // GenerateDaemonID generates a new random ULID-based daemon id.
 // Format: "d_" + ulid().
 // Daemon ids are per-repo, generated once at thrum init and persisted in
@@ -179,6 +179,18 @@ func TestGenerateThreadID(t *testing.T) {
 	}
 }
 
+func TestGenerateIdempotencyKey(t *testing.T) {
+	key := identity.GenerateIdempotencyKey()
+
+	if !strings.HasPrefix(key, "idk_") {
+		t.Errorf("Idempotency key should start with 'idk_', got %s", key)
+	}
+
+	if key == identity.GenerateIdempotencyKey() {
+		t.Errorf("Idempotency keys should be unique, got %s twice", key)
+	}
+}
+
 func TestULIDTimestamp(t *testing.T) {
 	// Generate a fresh ULID
 	id := identity.GenerateSessionID()
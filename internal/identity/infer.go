@@ -0,0 +1,96 @@
+package identity
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DefaultModuleDirs are the mono-repo container directories whose children
+// are modules, used by InferModule when config sets no agents.module_dirs:
+// in services/billing/... the module is billing, not services.
+var DefaultModuleDirs = []string{"apps", "cmd", "components", "libs", "modules", "packages", "pkg", "plugins", "services"}
+
+// ModuleInference is the result of InferModule. Exactly one of Module and
+// Candidates is set: Module when dir settles it, Candidates (sorted) when
+// it does not and the caller has to choose.
+type ModuleInference struct {
+	Module     string
+	Candidates []string
+	Path       string // dir relative to topLevel, "." at the root
+}
+
+// InferModule derives an agent module from where dir sits inside the
+// worktree rooted at topLevel (git rev-parse --show-toplevel): the first
+// path component below topLevel, or the second when the first is one of
+// moduleDirs (DefaultModuleDirs when empty). It is ambiguous at the
+// worktree root and directly inside a container directory; Candidates then
+// lists the non-hidden subdirectories that would each be a module. A dir
+// outside topLevel is an error.
+func InferModule(topLevel, dir string, moduleDirs []string) (ModuleInference, error) {
+	if len(moduleDirs) == 0 {
+		moduleDirs = DefaultModuleDirs
+	}
+	rel, err := filepath.Rel(topLevel, dir)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return ModuleInference{}, fmt.Errorf("%s is not inside the worktree %s", dir, topLevel)
+	}
+	inf := ModuleInference{Path: filepath.ToSlash(rel)}
+
+	parts := strings.Split(inf.Path, "/")
+	if inf.Path == "." {
+		parts = nil
+	}
+	isContainer := func(name string) bool {
+		for _, d := range moduleDirs {
+			if name == d {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch {
+	case len(parts) >= 2 && isContainer(parts[0]):
+		inf.Module = parts[1]
+	case len(parts) == 1 && isContainer(parts[0]):
+		inf.Candidates, err = moduleCandidates(filepath.Join(topLevel, parts[0]), nil)
+	case len(parts) >= 1:
+		inf.Module = parts[0]
+	default:
+		inf.Candidates, err = moduleCandidates(topLevel, isContainer)
+	}
+	if err != nil {
+		return ModuleInference{}, err
+	}
+	return inf, nil
+}
+
+// moduleCandidates lists the non-hidden subdirectories of dir. When
+// container is set, a container subdirectory contributes its own children
+// instead of itself.
+func moduleCandidates(dir string, container func(string) bool) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("list %s: %w", dir, err)
+	}
+	var out []string
+	for _, e := range entries {
+		if !e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		if container != nil && container(e.Name()) {
+			nested, err := moduleCandidates(filepath.Join(dir, e.Name()), nil)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, nested...)
+			continue
+		}
+		out = append(out, e.Name())
+	}
+	sort.Strings(out)
+	return out, nil
+}
@@ -0,0 +1,47 @@
+package identity_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/leonletto/thrum/internal/identity"
+)
+
+func TestInferModule(t *testing.T) {
+	root := t.TempDir()
+	for _, d := range []string{"docs", "web/src", "services/billing/api", "services/auth", ".github"} {
+		if err := os.MkdirAll(filepath.Join(root, d), 0o750); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases := []struct {
+		dir        string
+		moduleDirs []string
+		module     string
+		candidates string
+	}{
+		{"web/src", nil, "web", ""},
+		{"services/billing/api", nil, "billing", ""},
+		{"services", nil, "", "auth,billing"},
+		{".", nil, "", "auth,billing,docs,web"},
+		// A custom container list replaces the defaults.
+		{"services/billing", []string{"web"}, "services", ""},
+		{"web/src", []string{"web"}, "src", ""},
+	}
+	for _, tc := range cases {
+		inf, err := identity.InferModule(root, filepath.Join(root, tc.dir), tc.moduleDirs)
+		if err != nil {
+			t.Fatalf("InferModule(%s): %v", tc.dir, err)
+		}
+		if inf.Module != tc.module || strings.Join(inf.Candidates, ",") != tc.candidates {
+			t.Errorf("InferModule(%s, %v) = %+v, want module %q candidates %q", tc.dir, tc.moduleDirs, inf, tc.module, tc.candidates)
+		}
+	}
+
+	if _, err := identity.InferModule(root, filepath.Dir(root), nil); err == nil {
+		t.Error("expected an error for a directory outside the worktree")
+	}
+}
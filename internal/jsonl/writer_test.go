@@ -574,6 +574,50 @@ func TestRemoveBeforeTimestamp(t *testing.T) {
 	})
 }
 
+func TestRemoveWhere(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "events.jsonl")
+
+	type seqEvent struct {
+		EventID  string `json:"event_id"`
+		Sequence int    `json:"sequence"`
+	}
+	w, err := jsonl.NewWriter(path)
+	if err != nil {
+		t.Fatalf("NewWriter: %v", err)
+	}
+	for i, id := range []string{"e1", "e2", "e3", "e4"} {
+		if err := w.Append(seqEvent{EventID: id, Sequence: i + 1}); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+	_ = w.Close()
+
+	// Non-string fields are visible to the predicate as raw JSON.
+	removed, err := jsonl.RemoveWhere(path, func(obj map[string]json.RawMessage) bool {
+		var seq int
+		return json.Unmarshal(obj["sequence"], &seq) == nil && seq%2 == 0
+	})
+	if err != nil {
+		t.Fatalf("RemoveWhere: %v", err)
+	}
+	if removed != 2 {
+		t.Errorf("expected 2 removed, got %d", removed)
+	}
+
+	r, _ := jsonl.NewReader(path)
+	lines, _ := r.ReadAll()
+	var ids []string
+	for _, line := range lines {
+		var e seqEvent
+		_ = json.Unmarshal(line, &e)
+		ids = append(ids, e.EventID)
+	}
+	if len(ids) != 2 || ids[0] != "e1" || ids[1] != "e3" {
+		t.Errorf("remaining = %v, want [e1 e3]", ids)
+	}
+}
+
 // TestScannerHandlesLargeLines — thrum-10j0 regression. Production
 // observed a single 177KB events.jsonl line (a coordinator multi-page
 // message body) that hit bufio.Scanner's default 64KB MaxScanTokenSize
@@ -14,6 +14,7 @@ import (
 	"github.com/leonletto/thrum/internal/daemon/safedb"
 	"github.com/leonletto/thrum/internal/jsonl"
 	"github.com/leonletto/thrum/internal/recipientgate"
+	"github.com/leonletto/thrum/internal/schema"
 	"github.com/leonletto/thrum/internal/sync/pending"
 	"github.com/leonletto/thrum/internal/types"
 )
@@ -112,6 +113,18 @@ func (p *Projector) Apply(ctx context.Context, event json.RawMessage) error {
 		return p.applyMessageDelete(ctx, event)
 	case "message.receipt":
 		return p.applyMessageReceipt(ctx, event)
+	case "message.react":
+		return p.applyMessageReact(ctx, event)
+	case "message.pin":
+		return p.applyMessagePin(ctx, event)
+	case "message.move":
+		return p.applyMessageMove(ctx, event)
+	case "message.bump":
+		return p.applyMessageBump(ctx, event)
+	case "message.assign":
+		return p.applyMessageAssign(ctx, event)
+	case "message.complete":
+		return p.applyMessageComplete(ctx, event)
 	case "agent.register":
 		return p.applyAgentRegister(ctx, event)
 	case "agent.session.start":
@@ -122,6 +135,14 @@ func (p *Projector) Apply(ctx context.Context, event json.RawMessage) error {
 		return p.applyAgentUpdate(ctx, event)
 	case "agent.cleanup":
 		return p.applyAgentCleanup(ctx, event)
+	case "agent.alias":
+		return p.applyAgentAlias(ctx, event)
+	case "agent.capabilities":
+		return p.applyAgentCapabilities(ctx, event)
+	case "agent.mute":
+		return p.applyAgentMute(ctx, event)
+	case "agent.rename":
+		return p.applyAgentRename(ctx, event)
 	case "purge.executed":
 		return p.applyPurgeExecuted(ctx, event)
 	case "group.create":
@@ -233,6 +254,12 @@ func (p *Projector) applyMessageCreate(ctx context.Context, data json.RawMessage
 	if err := json.Unmarshal(data, &event); err != nil {
 		return fmt.Errorf("unmarshal message.create: %w", err)
 	}
+	// A scheduled message (send --schedule) is listed, and reaches its
+	// recipients, at its delivery time rather than when it was sent.
+	createdAt := event.Timestamp
+	if event.DeliverAt != "" {
+		createdAt = event.DeliverAt
+	}
 
 	// Determine whether any referenced state files are missing on disk.
 	// This check is only active when SetPendingPool has been called (syncDir != "").
@@ -280,20 +307,26 @@ func (p *Projector) applyMessageCreate(ctx context.Context, data json.RawMessage
 		INSERT OR IGNORE INTO messages (
 			message_id, thread_id, agent_id, session_id, created_at,
 			body_format, body_content, body_structured, authored_by, disclosed,
-			pending_route_resolution
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			pending_route_resolution, priority, expires_at,
+			origin_daemon, origin_sequence, deliver_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`,
 		event.MessageID,
 		sqlNullString(event.ThreadID),
 		event.AgentID,
 		event.SessionID,
-		event.Timestamp,
+		createdAt,
 		event.Body.Format,
 		event.Body.Content,
 		sqlNullString(event.Body.Structured),
 		sqlNullString(event.AuthoredBy),
 		boolToInt(event.Disclosed),
 		pendingFlag,
+		event.Priority,
+		sqlNullString(event.ExpiresAt),
+		event.OriginDaemon,
+		event.Sequence,
+		sqlNullString(event.DeliverAt),
 	)
 	if err != nil {
 		return fmt.Errorf("insert message: %w", err)
@@ -307,6 +340,20 @@ func (p *Projector) applyMessageCreate(ctx context.Context, data json.RawMessage
 		return tx.Commit()
 	}
 
+	// Index body for message.search (messages_fts shadow, v53).
+	if _, err := tx.Exec(`INSERT INTO messages_fts (message_id, body_content) VALUES (?, ?)`,
+		event.MessageID, event.Body.Content); err != nil {
+		return fmt.Errorf("index message: %w", err)
+	}
+
+	// Insert tags
+	for _, tag := range event.Tags {
+		if _, err := tx.Exec(`INSERT OR IGNORE INTO message_tags (message_id, tag) VALUES (?, ?)`,
+			event.MessageID, tag); err != nil {
+			return fmt.Errorf("insert tag: %w", err)
+		}
+	}
+
 	// Insert scopes
 	for _, scope := range event.Scopes {
 		_, err = tx.Exec(`
@@ -349,7 +396,7 @@ func (p *Projector) applyMessageCreate(ctx context.Context, data json.RawMessage
 				INSERT OR IGNORE INTO message_deliveries (
 					message_id, recipient_agent_id, delivered_at
 				) VALUES (?, ?, ?)
-			`, event.MessageID, recipientAgentID, event.Timestamp)
+			`, event.MessageID, recipientAgentID, createdAt)
 		}
 		if err != nil {
 			return fmt.Errorf("insert message delivery: %w", err)
@@ -376,6 +423,14 @@ func (p *Projector) applyMessageCreate(ctx context.Context, data json.RawMessage
 		}
 	}
 
+	if err := replayPendingReceipts(tx, event.MessageID); err != nil {
+		return err
+	}
+
+	if err := extendThreadExpiry(tx, &event); err != nil {
+		return err
+	}
+
 	if err := tx.Commit(); err != nil {
 		return err
 	}
@@ -468,9 +523,83 @@ func (p *Projector) applyMessageEdit(ctx context.Context, data json.RawMessage)
 		return fmt.Errorf("update message: %w", err)
 	}
 
+	// Re-index the edited body. The insert is gated on the message existing
+	// so an edit that arrives before its create doesn't leave an orphan row.
+	if _, err := tx.Exec(`DELETE FROM messages_fts WHERE message_id = ?`, event.MessageID); err != nil {
+		return fmt.Errorf("unindex message: %w", err)
+	}
+	if _, err := tx.Exec(`
+		INSERT INTO messages_fts (message_id, body_content)
+		SELECT message_id, body_content FROM messages WHERE message_id = ? AND deleted = 0
+	`, event.MessageID); err != nil {
+		return fmt.Errorf("index message: %w", err)
+	}
+
 	return tx.Commit()
 }
 
+// extendThreadExpiry keeps a conversation's TTL messages (send --ttl) alive
+// while it is active: a new message in the thread, or a reply to a TTL
+// message, pushes each unexpired TTL message out to the new message's time
+// plus that message's original TTL. Expiry never moves earlier. Computed
+// from event timestamps only, so every daemon replaying the event agrees.
+func extendThreadExpiry(tx *sql.Tx, event *types.MessageCreateEvent) error {
+	var replyTo string
+	for _, ref := range event.Refs {
+		if ref.Type == "reply_to" {
+			replyTo = ref.Value
+			break
+		}
+	}
+	if replyTo == "" && event.ThreadID == "" {
+		return nil
+	}
+	at, err := time.Parse(time.RFC3339Nano, event.Timestamp)
+	if err != nil {
+		return nil // unparseable timestamp: leave expiry alone
+	}
+	atStr := at.UTC().Format(time.RFC3339)
+
+	rows, err := tx.Query(`
+		SELECT message_id, created_at, expires_at FROM messages
+		WHERE expires_at IS NOT NULL AND expires_at > ? AND deleted = 0
+		  AND message_id != ?
+		  AND (message_id = ? OR (thread_id IS NOT NULL AND thread_id = ?))
+	`, atStr, event.MessageID, replyTo, event.ThreadID)
+	if err != nil {
+		return fmt.Errorf("query thread expiry: %w", err)
+	}
+	type extension struct{ messageID, expiresAt string }
+	var extend []extension
+	for rows.Next() {
+		var id, createdAt, expiresAt string
+		if err := rows.Scan(&id, &createdAt, &expiresAt); err != nil {
+			_ = rows.Close()
+			return fmt.Errorf("scan thread expiry: %w", err)
+		}
+		created, cErr := time.Parse(time.RFC3339Nano, createdAt)
+		expires, eErr := time.Parse(time.RFC3339Nano, expiresAt)
+		if cErr != nil || eErr != nil {
+			continue
+		}
+		next := at.Add(expires.Sub(created)).UTC().Format(time.RFC3339)
+		if next > expiresAt {
+			extend = append(extend, extension{id, next})
+		}
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate thread expiry: %w", err)
+	}
+
+	for _, e := range extend {
+		if _, err := tx.Exec(`UPDATE messages SET expires_at = ? WHERE message_id = ?`, e.expiresAt, e.messageID); err != nil {
+			return fmt.Errorf("extend expiry: %w", err)
+		}
+	}
+	return nil
+}
+
 func (p *Projector) applyMessageDelete(ctx context.Context, data json.RawMessage) error {
 	var event types.MessageDeleteEvent
 	if err := json.Unmarshal(data, &event); err != nil {
@@ -490,6 +619,164 @@ func (p *Projector) applyMessageDelete(ctx context.Context, data json.RawMessage
 		return fmt.Errorf("delete message: %w", err)
 	}
 
+	if _, err := p.db.ExecContext(ctx, `DELETE FROM messages_fts WHERE message_id = ?`, event.MessageID); err != nil {
+		return fmt.Errorf("unindex message: %w", err)
+	}
+
+	// A deleted message can't stay pinned.
+	if _, err := p.db.ExecContext(ctx, `DELETE FROM message_pins WHERE message_id = ?`, event.MessageID); err != nil {
+		return fmt.Errorf("unpin deleted message: %w", err)
+	}
+
+	return nil
+}
+
+func (p *Projector) applyMessagePin(ctx context.Context, data json.RawMessage) error {
+	var event types.MessagePinEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("unmarshal message.pin: %w", err)
+	}
+
+	if event.Unpinned {
+		if _, err := p.db.ExecContext(ctx,
+			`DELETE FROM message_pins WHERE message_id = ?`, event.MessageID,
+		); err != nil {
+			return fmt.Errorf("delete pin: %w", err)
+		}
+		return nil
+	}
+
+	// Same skip-if-absent rule as applyMessageReact; a deleted message is
+	// never pinned, even if the pin event was replayed after the delete.
+	if _, err := p.db.ExecContext(ctx, `
+		INSERT OR IGNORE INTO message_pins (message_id, pinned_by, pinned_at)
+		SELECT ?, ?, ? WHERE EXISTS (SELECT 1 FROM messages WHERE message_id = ? AND deleted = 0)
+	`,
+		event.MessageID, event.AgentID, event.Timestamp, event.MessageID,
+	); err != nil {
+		return fmt.Errorf("insert pin: %w", err)
+	}
+
+	return nil
+}
+
+func (p *Projector) applyMessageMove(ctx context.Context, data json.RawMessage) error {
+	var event types.MessageMoveEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("unmarshal message.move: %w", err)
+	}
+
+	// A move for a message this replica never saw is a no-op.
+	if _, err := p.db.ExecContext(ctx,
+		`UPDATE messages SET thread_id = ? WHERE message_id = ?`,
+		event.ThreadID, event.MessageID,
+	); err != nil {
+		return fmt.Errorf("move message: %w", err)
+	}
+
+	return nil
+}
+
+func (p *Projector) applyMessageBump(ctx context.Context, data json.RawMessage) error {
+	var event types.MessageBumpEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("unmarshal message.bump: %w", err)
+	}
+
+	// Keep the latest bump, so events replayed or synced out of order can't
+	// move bumped_at backwards. A bump for an unknown message is a no-op.
+	if _, err := p.db.ExecContext(ctx,
+		`UPDATE messages SET bumped_at = ?
+		 WHERE message_id = ? AND (bumped_at IS NULL OR julianday(bumped_at) < julianday(?))`,
+		event.Timestamp, event.MessageID, event.Timestamp,
+	); err != nil {
+		return fmt.Errorf("bump message: %w", err)
+	}
+
+	return nil
+}
+
+func (p *Projector) applyMessageAssign(ctx context.Context, data json.RawMessage) error {
+	var event types.MessageAssignEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("unmarshal message.assign: %w", err)
+	}
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE message_assignments SET status = 'reassigned', closed_at = ?
+		WHERE message_id = ? AND status = 'open' AND assignment_id != ?
+	`,
+		event.Timestamp, event.MessageID, event.AssignmentID,
+	); err != nil {
+		return fmt.Errorf("close previous assignment: %w", err)
+	}
+	// Same guard as reactions: skip messages that aren't projected locally.
+	if _, err := tx.ExecContext(ctx, `
+		INSERT OR IGNORE INTO message_assignments (assignment_id, message_id, assignee, assigned_by, status, assigned_at)
+		SELECT ?, ?, ?, ?, 'open', ? WHERE EXISTS (SELECT 1 FROM messages WHERE message_id = ?)
+	`,
+		event.AssignmentID, event.MessageID, event.Assignee, event.AgentID, event.Timestamp, event.MessageID,
+	); err != nil {
+		return fmt.Errorf("insert assignment: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (p *Projector) applyMessageComplete(ctx context.Context, data json.RawMessage) error {
+	var event types.MessageCompleteEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("unmarshal message.complete: %w", err)
+	}
+
+	// Only an open assignment completes; a replay after a later reassign
+	// leaves the reassigned row alone.
+	if _, err := p.db.ExecContext(ctx, `
+		UPDATE message_assignments SET status = 'completed', closed_at = ?
+		WHERE assignment_id = ? AND status = 'open'
+	`,
+		event.Timestamp, event.AssignmentID,
+	); err != nil {
+		return fmt.Errorf("complete assignment: %w", err)
+	}
+
+	return nil
+}
+
+func (p *Projector) applyMessageReact(ctx context.Context, data json.RawMessage) error {
+	var event types.MessageReactEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("unmarshal message.react: %w", err)
+	}
+
+	if event.Removed {
+		if _, err := p.db.ExecContext(ctx,
+			`DELETE FROM message_reactions WHERE message_id = ? AND agent_id = ? AND emoji = ?`,
+			event.MessageID, event.AgentID, event.Emoji,
+		); err != nil {
+			return fmt.Errorf("delete reaction: %w", err)
+		}
+		return nil
+	}
+
+	// Skip reactions whose message isn't projected locally (purged, or not
+	// yet applied) rather than failing the FK and poisoning the apply loop.
+	// Same graceful-degradation trade-off as applyMessageEdit.
+	if _, err := p.db.ExecContext(ctx, `
+		INSERT OR IGNORE INTO message_reactions (message_id, agent_id, emoji, reacted_at)
+		SELECT ?, ?, ?, ? WHERE EXISTS (SELECT 1 FROM messages WHERE message_id = ?)
+	`,
+		event.MessageID, event.AgentID, event.Emoji, event.Timestamp, event.MessageID,
+	); err != nil {
+		return fmt.Errorf("insert reaction: %w", err)
+	}
+
 	return nil
 }
 
@@ -506,47 +793,66 @@ func (p *Projector) applyMessageReceipt(ctx context.Context, data json.RawMessag
 	defer func() { _ = tx.Rollback() }()
 
 	// Check if the referenced message exists locally. If it doesn't (out-of-order
-	// sync from a peer), skip the delivery/receipt projection entirely. The event
-	// is still stored in JSONL and the events table — it just won't create delivery
-	// rows until the message.create arrives. This prevents FK constraint failures
-	// from poisoning the sync apply loop.
-	//
-	// Same design trade-off as applyMessageEdit: receipts that arrive before the
-	// message are permanently lost from the live projection but preserved in JSONL.
+	// sync from a peer), hold the receipt in pending_receipts instead: it would
+	// otherwise create no delivery row and the read would be lost, leaving the
+	// message unread on this machine for good. applyMessageCreate replays the
+	// held receipts once the message lands. The event itself is stored in JSONL
+	// and the events table either way.
 	var exists int
 	err = tx.QueryRow(`SELECT 1 FROM messages WHERE message_id = ?`, event.MessageID).Scan(&exists)
 	if errors.Is(err, sql.ErrNoRows) {
-		// Message not synced yet — skip delivery projection, commit empty tx
+		if err := bufferReceipt(tx, event.MessageID, event.AgentID, event.ReceiptType, event.Timestamp); err != nil {
+			return err
+		}
 		return tx.Commit()
 	}
 	if err != nil {
 		return fmt.Errorf("check message exists: %w", err)
 	}
 
-	// thrum-qb62: gate the INSERT on recipient legitimacy to prevent phantom
-	// delivery rows. Previously any receipt event unconditionally inserted a
-	// delivery row, which let `thrum message read --all` fabricate rows for
-	// messages the agent was never targeted for — making send targeting look
-	// like it had fanned out. The row is now only created when the agent is a
-	// legitimate recipient: mentioned by agent_id or role, in a targeted
-	// group, or on a broadcast-scoped message. Pre-v14 messages with legitimate
-	// recipients still get their row created the first time they read.
-	//
-	// Note: if the delivery row already exists (the normal post-v14 path), the
-	// INSERT is a no-op via OR IGNORE and the subsequent UPDATE sets seen_at /
-	// read_at as usual. If no row exists and the agent is not a legitimate
-	// recipient, no row is created and the UPDATE below is also a no-op —
-	// the receipt event is still stored in JSONL + events for auditability.
-	//
-	// thrum-1846: the legitimacy predicate now lives in internal/recipientgate
-	// (correlated to alias `m`), shared verbatim with HandleMarkRead's
-	// receipt-EMISSION gate so the two can never drift. The OR-arm logic is
-	// byte-equivalent to the original inline qb62 gate; only the message-id
-	// binding moved from a literal `?` to the correlated `m.message_id`, which
-	// is why the INSERT now selects from `messages m WHERE m.message_id = ?`
-	// (the existence check above guarantees exactly that one row matches).
-	insertArgs := append([]any{event.AgentID, event.Timestamp, event.MessageID}, recipientgate.Args(event.AgentID)...)
-	_, err = tx.Exec(`
+	if err := projectReceipt(tx, event.MessageID, event.AgentID, event.ReceiptType, event.Timestamp); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// projectReceipt stamps a seen/read receipt onto the agent's delivery row for
+// an existing message, creating the row when the agent is a legitimate
+// recipient.
+//
+// thrum-qb62: gate the INSERT on recipient legitimacy to prevent phantom
+// delivery rows. Previously any receipt event unconditionally inserted a
+// delivery row, which let `thrum message read --all` fabricate rows for
+// messages the agent was never targeted for — making send targeting look
+// like it had fanned out. The row is now only created when the agent is a
+// legitimate recipient: mentioned by agent_id or role, in a targeted
+// group, or on a broadcast-scoped message. Pre-v14 messages with legitimate
+// recipients still get their row created the first time they read.
+//
+// Note: if the delivery row already exists (the normal post-v14 path), the
+// INSERT is a no-op via OR IGNORE and the subsequent UPDATE sets seen_at /
+// read_at as usual. If no row exists and the agent is not a legitimate
+// recipient, no row is created and the UPDATE below is also a no-op —
+// the receipt event is still stored in JSONL + events for auditability.
+//
+// thrum-1846: the legitimacy predicate now lives in internal/recipientgate
+// (correlated to alias `m`), shared verbatim with HandleMarkRead's
+// receipt-EMISSION gate so the two can never drift. The OR-arm logic is
+// byte-equivalent to the original inline qb62 gate; only the message-id
+// binding moved from a literal `?` to the correlated `m.message_id`, which
+// is why the INSERT now selects from `messages m WHERE m.message_id = ?`
+// (callers only project receipts for messages that exist, so exactly that
+// one row matches).
+//
+// The same read can reach a peer more than once with different timestamps
+// (an agent reading on two machines, or a replayed pending receipt). The
+// earliest timestamp wins regardless of apply order, so every peer converges
+// on the same seen_at / read_at. julianday compares the instants; RFC 3339
+// strings with trimmed fractional seconds don't sort lexically.
+func projectReceipt(tx *sql.Tx, messageID, agentID, receiptType, timestamp string) error {
+	insertArgs := append([]any{agentID, timestamp, messageID}, recipientgate.Args(agentID)...)
+	_, err := tx.Exec(`
 		INSERT OR IGNORE INTO message_deliveries (message_id, recipient_agent_id, delivered_at)
 		SELECT m.message_id, ?, ?
 		FROM messages m
@@ -557,28 +863,84 @@ func (p *Projector) applyMessageReceipt(ctx context.Context, data json.RawMessag
 		return fmt.Errorf("ensure message delivery: %w", err)
 	}
 
-	switch event.ReceiptType {
+	switch receiptType {
 	case "seen":
 		_, err = tx.Exec(`
 			UPDATE message_deliveries
-			SET seen_at = COALESCE(seen_at, ?)
-			WHERE message_id = ? AND recipient_agent_id = ?
-		`, event.Timestamp, event.MessageID, event.AgentID)
+			SET seen_at = CASE WHEN seen_at IS NULL OR julianday(?1) < julianday(seen_at) THEN ?1 ELSE seen_at END
+			WHERE message_id = ?2 AND recipient_agent_id = ?3
+		`, timestamp, messageID, agentID)
 	case "read":
 		_, err = tx.Exec(`
 			UPDATE message_deliveries
-			SET seen_at = COALESCE(seen_at, ?),
-			    read_at = COALESCE(read_at, ?)
-			WHERE message_id = ? AND recipient_agent_id = ?
-		`, event.Timestamp, event.Timestamp, event.MessageID, event.AgentID)
+			SET seen_at = CASE WHEN seen_at IS NULL OR julianday(?1) < julianday(seen_at) THEN ?1 ELSE seen_at END,
+			    read_at = CASE WHEN read_at IS NULL OR julianday(?1) < julianday(read_at) THEN ?1 ELSE read_at END
+			WHERE message_id = ?2 AND recipient_agent_id = ?3
+		`, timestamp, messageID, agentID)
 	default:
-		return fmt.Errorf("unknown receipt_type %q", event.ReceiptType)
+		return fmt.Errorf("unknown receipt_type %q", receiptType)
 	}
 	if err != nil {
 		return fmt.Errorf("update message delivery receipt: %w", err)
 	}
+	return nil
+}
 
-	return tx.Commit()
+// bufferReceipt records a receipt for a message that has not been projected
+// yet, keeping the earliest timestamp per (message, agent, type). Unknown
+// receipt types are dropped, as they always were for missing messages, so a
+// bad peer event can't fail the sync apply batch.
+func bufferReceipt(tx *sql.Tx, messageID, agentID, receiptType, timestamp string) error {
+	if receiptType != "seen" && receiptType != "read" {
+		return nil
+	}
+	if _, err := tx.Exec(`
+		INSERT INTO pending_receipts (message_id, agent_id, receipt_type, timestamp)
+		VALUES (?1, ?2, ?3, ?4)
+		ON CONFLICT(message_id, agent_id, receipt_type) DO UPDATE SET
+			timestamp = CASE WHEN julianday(excluded.timestamp) < julianday(timestamp) THEN excluded.timestamp ELSE timestamp END
+	`, messageID, agentID, receiptType, timestamp); err != nil {
+		return fmt.Errorf("buffer receipt: %w", err)
+	}
+	return nil
+}
+
+// replayPendingReceipts applies and clears the receipts held for messageID.
+// Called from applyMessageCreate inside its transaction, after the delivery
+// rows exist so recipient receipts update them in place.
+func replayPendingReceipts(tx *sql.Tx, messageID string) error {
+	rows, err := tx.Query(`SELECT agent_id, receipt_type, timestamp FROM pending_receipts WHERE message_id = ?`, messageID)
+	if err != nil {
+		return fmt.Errorf("query pending receipts: %w", err)
+	}
+	type held struct{ agentID, receiptType, timestamp string }
+	var receipts []held
+	for rows.Next() {
+		var r held
+		if err := rows.Scan(&r.agentID, &r.receiptType, &r.timestamp); err != nil {
+			_ = rows.Close()
+			return fmt.Errorf("scan pending receipt: %w", err)
+		}
+		receipts = append(receipts, r)
+	}
+	err = rows.Err()
+	_ = rows.Close()
+	if err != nil {
+		return fmt.Errorf("iterate pending receipts: %w", err)
+	}
+	if len(receipts) == 0 {
+		return nil
+	}
+
+	for _, r := range receipts {
+		if err := projectReceipt(tx, messageID, r.agentID, r.receiptType, r.timestamp); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.Exec(`DELETE FROM pending_receipts WHERE message_id = ?`, messageID); err != nil {
+		return fmt.Errorf("clear pending receipts: %w", err)
+	}
+	return nil
 }
 
 func (p *Projector) applyAgentRegister(ctx context.Context, data json.RawMessage) error {
@@ -701,6 +1063,15 @@ func (p *Projector) applySessionEnd(ctx context.Context, data json.RawMessage) e
 		return fmt.Errorf("update session: %w", err)
 	}
 
+	// Files declared with session start --files only hold while the session
+	// is live; drop them however it ended (normal, crash recovery, sweeper).
+	if _, err := p.db.ExecContext(ctx,
+		`DELETE FROM session_scopes WHERE session_id = ? AND scope_type = 'file'`,
+		event.SessionID,
+	); err != nil {
+		return fmt.Errorf("clear declared files: %w", err)
+	}
+
 	return nil
 }
 
@@ -930,7 +1301,7 @@ func (p *Projector) applyAgentCleanup(ctx context.Context, data json.RawMessage)
 	agentID := event.AgentID
 
 	// Delete message child tables
-	for _, table := range []string{"message_edits", "message_reads", "message_deliveries", "message_refs", "message_scopes"} {
+	for _, table := range schema.MessageChildTables {
 		//nolint:gosec // table name is a hardcoded constant, not user input
 		q := `DELETE FROM ` + table + ` WHERE message_id IN (SELECT message_id FROM messages WHERE agent_id = ?)`
 		if _, err := p.db.ExecContext(ctx, q, agentID); err != nil {
@@ -967,6 +1338,19 @@ func (p *Projector) applyAgentCleanup(ctx context.Context, data json.RawMessage)
 		return fmt.Errorf("delete events for agent: %w", err)
 	}
 
+	if _, err := p.db.ExecContext(ctx, `DELETE FROM agent_aliases WHERE agent_id = ?`, agentID); err != nil {
+		return fmt.Errorf("delete aliases for agent: %w", err)
+	}
+	if _, err := p.db.ExecContext(ctx, `DELETE FROM agent_capabilities WHERE agent_id = ?`, agentID); err != nil {
+		return fmt.Errorf("delete capabilities for agent: %w", err)
+	}
+	if _, err := p.db.ExecContext(ctx, `DELETE FROM agent_mutes WHERE agent_id = ?`, agentID); err != nil {
+		return fmt.Errorf("delete mute for agent: %w", err)
+	}
+	if _, err := p.db.ExecContext(ctx, `DELETE FROM message_assignments WHERE assignee = ?`, agentID); err != nil {
+		return fmt.Errorf("delete assignments for agent: %w", err)
+	}
+
 	// Delete agent row
 	if _, err := p.db.ExecContext(ctx, `DELETE FROM agents WHERE agent_id = ?`, agentID); err != nil {
 		return fmt.Errorf("delete agent: %w", err)
@@ -975,6 +1359,165 @@ func (p *Projector) applyAgentCleanup(ctx context.Context, data json.RawMessage)
 	return nil
 }
 
+func (p *Projector) applyAgentAlias(ctx context.Context, data json.RawMessage) error {
+	var event types.AgentAliasEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("unmarshal agent.alias: %w", err)
+	}
+
+	if event.Removed {
+		if _, err := p.db.ExecContext(ctx,
+			`DELETE FROM agent_aliases WHERE alias = ? AND agent_id = ?`,
+			event.Alias, event.AgentID,
+		); err != nil {
+			return fmt.Errorf("delete alias: %w", err)
+		}
+		return nil
+	}
+
+	// Skip aliases for agents that aren't projected (deleted, or not yet
+	// applied) so a stale alias can't outlive its agent.
+	if _, err := p.db.ExecContext(ctx, `
+		INSERT INTO agent_aliases (alias, agent_id, created_at)
+		SELECT ?, ?, ? WHERE EXISTS (SELECT 1 FROM agents WHERE agent_id = ?)
+		ON CONFLICT(alias) DO UPDATE SET agent_id = excluded.agent_id, created_at = excluded.created_at
+	`,
+		event.Alias, event.AgentID, event.Timestamp, event.AgentID,
+	); err != nil {
+		return fmt.Errorf("insert alias: %w", err)
+	}
+
+	return nil
+}
+
+func (p *Projector) applyAgentCapabilities(ctx context.Context, data json.RawMessage) error {
+	var event types.AgentCapabilitiesEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("unmarshal agent.capabilities: %w", err)
+	}
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM agent_capabilities WHERE agent_id = ?`, event.AgentID); err != nil {
+		return fmt.Errorf("clear capabilities: %w", err)
+	}
+	// Same guard as aliases: no capabilities for an agent that isn't projected.
+	for _, capability := range event.Capabilities {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT OR IGNORE INTO agent_capabilities (agent_id, capability, created_at)
+			SELECT ?, ?, ? WHERE EXISTS (SELECT 1 FROM agents WHERE agent_id = ?)
+		`,
+			event.AgentID, capability, event.Timestamp, event.AgentID,
+		); err != nil {
+			return fmt.Errorf("insert capability: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (p *Projector) applyAgentMute(ctx context.Context, data json.RawMessage) error {
+	var event types.AgentMuteEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("unmarshal agent.mute: %w", err)
+	}
+
+	if event.Removed {
+		if _, err := p.db.ExecContext(ctx, `DELETE FROM agent_mutes WHERE agent_id = ?`, event.AgentID); err != nil {
+			return fmt.Errorf("delete mute: %w", err)
+		}
+		return nil
+	}
+
+	var until sql.NullString
+	if event.Until != "" {
+		until = sql.NullString{String: event.Until, Valid: true}
+	}
+	// Same guard as aliases: no mute for an agent that isn't projected.
+	if _, err := p.db.ExecContext(ctx, `
+		INSERT INTO agent_mutes (agent_id, muted_until, allow_mentions, muted_at)
+		SELECT ?, ?, ?, ? WHERE EXISTS (SELECT 1 FROM agents WHERE agent_id = ?)
+		ON CONFLICT(agent_id) DO UPDATE SET
+			muted_until = excluded.muted_until,
+			allow_mentions = excluded.allow_mentions,
+			muted_at = excluded.muted_at
+	`,
+		event.AgentID, until, event.AllowMentions, event.Timestamp, event.AgentID,
+	); err != nil {
+		return fmt.Errorf("upsert mute: %w", err)
+	}
+
+	return nil
+}
+
+// agentRenameUpdates move every agent_id-keyed row from the old name (?2) to
+// the new one (?1). OR IGNORE keeps a row that would collide on a composite
+// key with one already owned by the new name; the leftovers are removed
+// after. Group membership and mention refs match only agent-typed values so
+// a role or group that happens to share the name is untouched.
+var agentRenameUpdates = []string{
+	`UPDATE agents SET agent_id = ?1, display = CASE WHEN display = ?2 THEN ?1 ELSE display END WHERE agent_id = ?2`,
+	`UPDATE sessions SET agent_id = ?1 WHERE agent_id = ?2`,
+	`UPDATE agent_work_contexts SET agent_id = ?1 WHERE agent_id = ?2`,
+	`UPDATE messages SET agent_id = ?1 WHERE agent_id = ?2`,
+	`UPDATE message_reads SET agent_id = ?1 WHERE agent_id = ?2`,
+	`UPDATE OR IGNORE message_deliveries SET recipient_agent_id = ?1 WHERE recipient_agent_id = ?2`,
+	`DELETE FROM message_deliveries WHERE recipient_agent_id = ?2`,
+	`UPDATE OR IGNORE message_reactions SET agent_id = ?1 WHERE agent_id = ?2`,
+	`DELETE FROM message_reactions WHERE agent_id = ?2`,
+	`UPDATE message_refs SET ref_value = ?1 WHERE ref_type = 'mention' AND ref_value = ?2`,
+	`UPDATE OR IGNORE group_members SET member_value = ?1 WHERE member_type = 'agent' AND member_value = ?2`,
+	`DELETE FROM group_members WHERE member_type = 'agent' AND member_value = ?2`,
+	`UPDATE agent_aliases SET agent_id = ?1 WHERE agent_id = ?2`,
+	`UPDATE agent_capabilities SET agent_id = ?1 WHERE agent_id = ?2`,
+	`UPDATE agent_mutes SET agent_id = ?1 WHERE agent_id = ?2`,
+	`UPDATE message_pins SET pinned_by = ?1 WHERE pinned_by = ?2`,
+	`UPDATE message_assignments SET assignee = ?1 WHERE assignee = ?2`,
+	`UPDATE message_assignments SET assigned_by = ?1 WHERE assigned_by = ?2`,
+	`UPDATE OR IGNORE pending_receipts SET agent_id = ?1 WHERE agent_id = ?2`,
+	`DELETE FROM pending_receipts WHERE agent_id = ?2`,
+}
+
+func (p *Projector) applyAgentRename(ctx context.Context, data json.RawMessage) error {
+	var event types.AgentRenameEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("unmarshal agent.rename: %w", err)
+	}
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// Replay is a no-op when the old agent is gone (already renamed or
+	// deleted) or the new name is taken, so re-applying never merges two
+	// agents.
+	var movable bool
+	if err := tx.QueryRow(`
+		SELECT EXISTS (SELECT 1 FROM agents WHERE agent_id = ?)
+		   AND NOT EXISTS (SELECT 1 FROM agents WHERE agent_id = ?)`,
+		event.OldAgentID, event.AgentID,
+	).Scan(&movable); err != nil {
+		return fmt.Errorf("check rename: %w", err)
+	}
+	if !movable {
+		return nil
+	}
+
+	for _, stmt := range agentRenameUpdates {
+		if _, err := tx.Exec(stmt, event.AgentID, event.OldAgentID); err != nil {
+			return fmt.Errorf("rename agent %s: %w", event.OldAgentID, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
 func (p *Projector) applyPurgeExecuted(ctx context.Context, data json.RawMessage) error {
 	var event types.PurgeExecutedEvent
 	if err := json.Unmarshal(data, &event); err != nil {
@@ -994,7 +1537,7 @@ func (p *Projector) applyPurgeExecuted(ctx context.Context, data json.RawMessage
 	}
 
 	// Delete old messages (child tables first)
-	for _, table := range []string{"message_edits", "message_reads", "message_deliveries", "message_refs", "message_scopes"} {
+	for _, table := range schema.MessageChildTables {
 		//nolint:gosec // table name is a hardcoded constant, not user input
 		q := `DELETE FROM ` + table + ` WHERE message_id IN (SELECT message_id FROM messages WHERE created_at < ?)`
 		if _, err := p.db.ExecContext(ctx, q, cutoff); err != nil {
@@ -1115,17 +1658,59 @@ func (p *Projector) applyGroupUpdate(ctx context.Context, data json.RawMessage)
 	}
 
 	if name, ok := event.Fields["name"]; ok {
-		_, err := p.db.ExecContext(ctx, `
-			UPDATE groups SET name = ?, updated_at = ? WHERE group_id = ?
-		`, name, event.Timestamp, event.GroupID)
-		if err != nil {
-			return fmt.Errorf("update group name: %w", err)
+		if err := p.renameGroup(ctx, event.GroupID, name, event.Timestamp); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
+// renameGroup renames a group and rewrites the group scopes and refs of
+// messages addressed to the old name, so inbox filtering keeps matching them.
+// A group that isn't projected locally yet (out-of-order sync) is a no-op.
+func (p *Projector) renameGroup(ctx context.Context, groupID, newName, timestamp string) error {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var oldName string
+	err = tx.QueryRowContext(ctx, `SELECT name FROM groups WHERE group_id = ?`, groupID).Scan(&oldName)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("query group name: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE groups SET name = ?, updated_at = ? WHERE group_id = ?`,
+		newName, timestamp, groupID); err != nil {
+		return fmt.Errorf("update group name: %w", err)
+	}
+	if oldName != newName {
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE OR IGNORE message_scopes SET scope_value = ? WHERE scope_type = 'group' AND scope_value = ?`,
+			newName, oldName); err != nil {
+			return fmt.Errorf("rename group scopes: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE OR IGNORE message_refs SET ref_value = ? WHERE ref_type = 'group' AND ref_value = ?`,
+			newName, oldName); err != nil {
+			return fmt.Errorf("rename group refs: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE OR IGNORE group_members SET member_value = ? WHERE member_type = 'group' AND member_value = ?`,
+			newName, oldName); err != nil {
+			return fmt.Errorf("rename nested group members: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
 func (p *Projector) applyGroupDelete(ctx context.Context, data json.RawMessage) error {
 	var event types.GroupDeleteEvent
 	if err := json.Unmarshal(data, &event); err != nil {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -55,6 +56,8 @@ func TestProjector_ApplyMessageCreate(t *testing.T) {
 		Refs: []types.Ref{
 			{Type: "spec", Value: "docs/spec.md"},
 		},
+		Tags:     []string{"decision"},
+		Priority: "high",
 	}
 
 	data, _ := json.Marshal(event)
@@ -91,6 +94,24 @@ func TestProjector_ApplyMessageCreate(t *testing.T) {
 	if refValue != "docs/spec.md" {
 		t.Errorf("Expected ref_value 'docs/spec.md', got '%s'", refValue)
 	}
+
+	// Verify tag was inserted
+	var tag string
+	err = db.QueryRow("SELECT tag FROM message_tags WHERE message_id = ?", "msg_001").Scan(&tag)
+	if err != nil {
+		t.Fatalf("Query tag failed: %v", err)
+	}
+	if tag != "decision" {
+		t.Errorf("Expected tag 'decision', got '%s'", tag)
+	}
+
+	var priority string
+	if err := db.QueryRow("SELECT priority FROM messages WHERE message_id = ?", "msg_001").Scan(&priority); err != nil {
+		t.Fatalf("Query priority failed: %v", err)
+	}
+	if priority != "high" {
+		t.Errorf("Expected priority 'high', got '%s'", priority)
+	}
 }
 
 // TestApplyMessageCreate_SelfDelivery_StampsReadAt verifies the projector stamps
@@ -754,6 +775,7 @@ func TestProjector_AgentCleanup_FullScrub(t *testing.T) {
 	_, _ = db.ExecContext(ctx, `INSERT INTO messages (message_id, agent_id, session_id, created_at, body_format, body_content) VALUES ('msg_d1', 'doomed', 'sess_d1', '2026-03-20T01:00:00Z', 'text', 'hi')`)
 	_, _ = db.ExecContext(ctx, `INSERT INTO sessions (session_id, agent_id, started_at) VALUES ('sess_d1', 'doomed', '2026-03-20T00:00:00Z')`)
 	_, _ = db.ExecContext(ctx, `INSERT INTO events (event_id, sequence, type, timestamp, origin_daemon, event_json) VALUES ('evt_d1', 10, 'agent.register', '2026-03-20T00:00:00Z', 'peer_1', '{"agent_id":"doomed"}')`)
+	_, _ = db.ExecContext(ctx, `INSERT INTO agent_aliases (alias, agent_id, created_at) VALUES ('dee', 'doomed', '2026-03-20T00:00:00Z')`)
 
 	// Also insert another agent's data to ensure it's not affected
 	_, _ = db.ExecContext(ctx, `INSERT INTO agents (agent_id, kind, role, module, registered_at) VALUES ('keeper', 'agent', 'test', 'test', '2026-03-20T00:00:00Z')`)
@@ -794,6 +816,12 @@ func TestProjector_AgentCleanup_FullScrub(t *testing.T) {
 		t.Error("doomed agent's events should be deleted")
 	}
 
+	var aliasCount int
+	_ = db.QueryRowContext(ctx, `SELECT COUNT(*) FROM agent_aliases WHERE agent_id = 'doomed'`).Scan(&aliasCount)
+	if aliasCount != 0 {
+		t.Error("doomed agent's aliases should be deleted")
+	}
+
 	// Verify keeper agent is untouched
 	_ = db.QueryRowContext(ctx, `SELECT COUNT(*) FROM agents WHERE agent_id = 'keeper'`).Scan(&agentCount)
 	if agentCount != 1 {
@@ -859,6 +887,57 @@ func TestProjector_GroupMemberAddUnknownGroup(t *testing.T) {
 	}
 }
 
+// TestProjector_GroupRename verifies that a group.update renaming a group
+// moves group scopes and refs on existing messages to the new name, and that
+// renaming a group that isn't projected yet is a no-op.
+func TestProjector_GroupRename(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	p := projection.NewProjector(safedb.New(db))
+	ctx := context.Background()
+	apply := func(event any) {
+		t.Helper()
+		data, _ := json.Marshal(event)
+		if err := p.Apply(ctx, data); err != nil {
+			t.Fatalf("apply %T: %v", event, err)
+		}
+	}
+
+	apply(types.GroupCreateEvent{Type: "group.create", Timestamp: "2026-01-01T10:00:00Z", GroupID: "grp_1", Name: "eng", CreatedBy: "admin"})
+	apply(types.MessageCreateEvent{
+		Type:      "message.create",
+		Timestamp: "2026-01-01T10:01:00Z",
+		MessageID: "msg_eng",
+		AgentID:   "admin",
+		Body:      types.MessageBody{Format: "markdown", Content: "hi eng"},
+		Scopes:    []types.Scope{{Type: "group", Value: "eng"}},
+		Refs:      []types.Ref{{Type: "group", Value: "eng"}},
+	})
+	apply(types.GroupCreateEvent{Type: "group.create", Timestamp: "2026-01-01T10:01:30Z", GroupID: "grp_2", Name: "all", CreatedBy: "admin"})
+	apply(types.GroupMemberAddEvent{Type: "group.member.add", Timestamp: "2026-01-01T10:01:40Z", GroupID: "grp_2", MemberType: "group", MemberValue: "eng", AddedBy: "admin"})
+	apply(types.GroupUpdateEvent{Type: "group.update", Timestamp: "2026-01-01T10:02:00Z", GroupID: "grp_1", Fields: map[string]string{"name": "engineering"}})
+	apply(types.GroupUpdateEvent{Type: "group.update", Timestamp: "2026-01-01T10:03:00Z", GroupID: "grp_UNKNOWN", Fields: map[string]string{"name": "x"}})
+
+	var name, scope, ref string
+	if err := db.QueryRow(`SELECT name FROM groups WHERE group_id = 'grp_1'`).Scan(&name); err != nil {
+		t.Fatalf("query group: %v", err)
+	}
+	if err := db.QueryRow(`SELECT scope_value FROM message_scopes WHERE message_id = 'msg_eng' AND scope_type = 'group'`).Scan(&scope); err != nil {
+		t.Fatalf("query scope: %v", err)
+	}
+	if err := db.QueryRow(`SELECT ref_value FROM message_refs WHERE message_id = 'msg_eng' AND ref_type = 'group'`).Scan(&ref); err != nil {
+		t.Fatalf("query ref: %v", err)
+	}
+	var nested string
+	if err := db.QueryRow(`SELECT member_value FROM group_members WHERE group_id = 'grp_2' AND member_type = 'group'`).Scan(&nested); err != nil {
+		t.Fatalf("query nested member: %v", err)
+	}
+	if name != "engineering" || scope != "engineering" || ref != "engineering" || nested != "engineering" {
+		t.Errorf("after rename: group=%q scope=%q ref=%q nested=%q, want all %q", name, scope, ref, nested, "engineering")
+	}
+}
+
 // TestProjector_AgentUpdateUnknownSession verifies that an agent.update event
 // with a work context referencing a session that doesn't exist locally
 // succeeds gracefully — contexts with unknown session_ids are skipped instead
@@ -1072,6 +1151,66 @@ func TestProjector_ApplyMessageReceipt_ExistingRecipientRowIsUpdated(t *testing.
 	}
 }
 
+func TestProjector_ApplyMessageReceipt_EarliestReadWins(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	p := projection.NewProjector(safedb.New(db))
+
+	insertAgent(t, db, "alice", "implementer")
+	insertMessageWithRef(t, p, "msg_twice", "alice", []string{"alice"})
+
+	// Alice read on two machines; the later read syncs in first. The
+	// fractional-second timestamp sorts after the whole-second one as a
+	// string but is the earlier instant.
+	applyReceipt(t, p, "msg_twice", "alice", "read", "2026-01-01T00:00:09Z")
+	applyReceipt(t, p, "msg_twice", "alice", "read", "2026-01-01T00:00:05.5Z")
+	applyReceipt(t, p, "msg_twice", "alice", "read", "2026-01-01T00:00:07Z")
+
+	if readAt := readAtOf(t, db, "msg_twice", "alice"); readAt.String != "2026-01-01T00:00:05.5Z" {
+		t.Fatalf("read_at = %v, want the earliest read 2026-01-01T00:00:05.5Z", readAt)
+	}
+}
+
+func TestProjector_ApplyMessageReceipt_BufferedUntilMessageArrives(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	p := projection.NewProjector(safedb.New(db))
+
+	insertAgent(t, db, "alice", "implementer")
+	insertAgent(t, db, "bob", "implementer")
+
+	// Out-of-order peer sync: reads land before the message.create. bob is
+	// not a recipient, so his receipt must still not fabricate a row.
+	applyReceipt(t, p, "msg_late", "alice", "read", "2026-01-01T00:00:08Z")
+	applyReceipt(t, p, "msg_late", "alice", "read", "2026-01-01T00:00:06Z")
+	applyReceipt(t, p, "msg_late", "bob", "read", "2026-01-01T00:00:07Z")
+
+	var pending int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM pending_receipts WHERE message_id = 'msg_late'`).Scan(&pending); err != nil {
+		t.Fatalf("count pending receipts: %v", err)
+	}
+	if pending != 2 {
+		t.Fatalf("pending receipts = %d, want 2 (alice and bob, deduplicated)", pending)
+	}
+
+	insertMessageWithRef(t, p, "msg_late", "alice", []string{"alice"})
+
+	if readAt := readAtOf(t, db, "msg_late", "alice"); readAt.String != "2026-01-01T00:00:06Z" {
+		t.Fatalf("alice read_at = %v, want the earliest buffered read", readAt)
+	}
+	if got := deliveryCount(t, db, "msg_late", "bob"); got != 0 {
+		t.Fatalf("bob is not a recipient — expected 0 delivery rows, got %d", got)
+	}
+	if err := db.QueryRow(`SELECT COUNT(*) FROM pending_receipts WHERE message_id = 'msg_late'`).Scan(&pending); err != nil {
+		t.Fatalf("count pending receipts: %v", err)
+	}
+	if pending != 0 {
+		t.Fatalf("pending receipts after message.create = %d, want 0", pending)
+	}
+}
+
 func TestProjector_ApplyMessageReceipt_MentionedAgentCreatesRowForPreV14(t *testing.T) {
 	db := setupTestDB(t)
 	defer func() { _ = db.Close() }()
@@ -1499,3 +1638,515 @@ func TestProjector_MessageIngest_AllStateFilesPresent_NoPending(t *testing.T) {
 		t.Errorf("pending_route_resolution = %d, want 0 (all state files present)", flag)
 	}
 }
+
+func TestProjector_ApplyMessageReact(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	p := projection.NewProjector(safedb.New(db))
+	insertAgent(t, db, "alice", "implementer")
+	insertMessageWithRef(t, p, "msg_react", "alice", []string{"alice"})
+
+	apply := func(messageID, emoji string, removed bool) {
+		t.Helper()
+		data, _ := json.Marshal(types.MessageReactEvent{
+			Type:      "message.react",
+			Timestamp: "2026-01-01T00:00:05Z",
+			MessageID: messageID,
+			AgentID:   "alice",
+			Emoji:     emoji,
+			Removed:   removed,
+		})
+		if err := p.Apply(context.Background(), data); err != nil {
+			t.Fatalf("apply react %s on %s: %v", emoji, messageID, err)
+		}
+	}
+	count := func(messageID string) int {
+		t.Helper()
+		var n int
+		if err := db.QueryRow(`SELECT COUNT(*) FROM message_reactions WHERE message_id = ?`, messageID).Scan(&n); err != nil {
+			t.Fatalf("count reactions: %v", err)
+		}
+		return n
+	}
+
+	// Replaying the same add is idempotent.
+	apply("msg_react", ":+1:", false)
+	apply("msg_react", ":+1:", false)
+	if got := count("msg_react"); got != 1 {
+		t.Fatalf("reactions after duplicate add = %d, want 1", got)
+	}
+
+	// A reaction on a message that isn't projected is skipped, not an error.
+	apply("msg_missing", ":+1:", false)
+	if got := count("msg_missing"); got != 0 {
+		t.Fatalf("reactions on missing message = %d, want 0", got)
+	}
+
+	apply("msg_react", ":+1:", true)
+	if got := count("msg_react"); got != 0 {
+		t.Fatalf("reactions after remove = %d, want 0", got)
+	}
+}
+
+func TestProjector_ApplyMessagePin(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	p := projection.NewProjector(safedb.New(db))
+	insertAgent(t, db, "alice", "implementer")
+	insertMessageWithRef(t, p, "msg_pin", "alice", []string{"alice"})
+
+	apply := func(event any) {
+		t.Helper()
+		data, _ := json.Marshal(event)
+		if err := p.Apply(context.Background(), data); err != nil {
+			t.Fatalf("apply %+v: %v", event, err)
+		}
+	}
+	pin := func(messageID string, unpinned bool) types.MessagePinEvent {
+		return types.MessagePinEvent{
+			Type:      "message.pin",
+			Timestamp: "2026-01-01T00:00:05Z",
+			MessageID: messageID,
+			AgentID:   "alice",
+			Unpinned:  unpinned,
+		}
+	}
+	pinned := func(messageID string) bool {
+		t.Helper()
+		var n int
+		if err := db.QueryRow(`SELECT COUNT(*) FROM message_pins WHERE message_id = ?`, messageID).Scan(&n); err != nil {
+			t.Fatalf("count pins: %v", err)
+		}
+		return n > 0
+	}
+
+	apply(pin("msg_pin", false))
+	apply(pin("msg_pin", false))
+	if !pinned("msg_pin") {
+		t.Fatal("message not pinned after pin event")
+	}
+	apply(pin("msg_missing", false))
+	if pinned("msg_missing") {
+		t.Fatal("pin on a message that isn't projected was stored")
+	}
+	apply(pin("msg_pin", true))
+	if pinned("msg_pin") {
+		t.Fatal("message still pinned after unpin event")
+	}
+
+	// Deleting a pinned message drops the pin, and a replayed pin after
+	// the delete doesn't bring it back.
+	apply(pin("msg_pin", false))
+	apply(types.MessageDeleteEvent{Type: "message.delete", Timestamp: "2026-01-01T00:00:06Z", MessageID: "msg_pin"})
+	apply(pin("msg_pin", false))
+	if pinned("msg_pin") {
+		t.Fatal("deleted message is pinned")
+	}
+}
+
+func TestProjector_ApplyMessageMove(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	p := projection.NewProjector(safedb.New(db))
+	insertAgent(t, db, "alice", "implementer")
+	insertMessageWithRef(t, p, "msg_move", "alice", []string{"alice"})
+
+	event, _ := json.Marshal(types.MessageMoveEvent{
+		Type:      "message.move",
+		Timestamp: "2026-01-01T00:00:05Z",
+		MessageID: "msg_move",
+		ThreadID:  "thr_dest",
+		AgentID:   "alice",
+	})
+	if err := p.Apply(context.Background(), event); err != nil {
+		t.Fatalf("apply message.move: %v", err)
+	}
+
+	var threadID sql.NullString
+	if err := db.QueryRow(`SELECT thread_id FROM messages WHERE message_id = ?`, "msg_move").Scan(&threadID); err != nil {
+		t.Fatalf("query thread_id: %v", err)
+	}
+	if threadID.String != "thr_dest" {
+		t.Errorf("thread_id = %q, want thr_dest", threadID.String)
+	}
+}
+
+func TestProjector_ApplyMessageBump(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	p := projection.NewProjector(safedb.New(db))
+	insertAgent(t, db, "alice", "implementer")
+	insertMessageWithRef(t, p, "msg_bump", "alice", []string{"alice"})
+
+	bump := func(ts string) {
+		t.Helper()
+		event, _ := json.Marshal(types.MessageBumpEvent{Type: "message.bump", Timestamp: ts, MessageID: "msg_bump", AgentID: "alice"})
+		if err := p.Apply(context.Background(), event); err != nil {
+			t.Fatalf("apply message.bump: %v", err)
+		}
+	}
+	bumpedAt := func() string {
+		t.Helper()
+		var at sql.NullString
+		if err := db.QueryRow(`SELECT bumped_at FROM messages WHERE message_id = ?`, "msg_bump").Scan(&at); err != nil {
+			t.Fatalf("query bumped_at: %v", err)
+		}
+		return at.String
+	}
+
+	bump("2026-01-01T00:10:00Z")
+	if got := bumpedAt(); got != "2026-01-01T00:10:00Z" {
+		t.Fatalf("bumped_at = %q, want 2026-01-01T00:10:00Z", got)
+	}
+	// An older bump synced in late doesn't move bumped_at backwards.
+	bump("2026-01-01T00:05:00Z")
+	if got := bumpedAt(); got != "2026-01-01T00:10:00Z" {
+		t.Errorf("bumped_at after older bump = %q, want it unchanged", got)
+	}
+	bump("2026-01-01T00:20:00.5Z")
+	if got := bumpedAt(); got != "2026-01-01T00:20:00.5Z" {
+		t.Errorf("bumped_at after newer bump = %q", got)
+	}
+}
+
+func TestProjector_ApplyMessageAssign(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	p := projection.NewProjector(safedb.New(db))
+	insertAgent(t, db, "alice", "implementer")
+	insertMessageWithRef(t, p, "msg_task", "alice", []string{"alice"})
+
+	apply := func(v any) {
+		t.Helper()
+		event, _ := json.Marshal(v)
+		if err := p.Apply(context.Background(), event); err != nil {
+			t.Fatalf("apply %T: %v", v, err)
+		}
+	}
+	status := func(id string) string {
+		t.Helper()
+		var s string
+		if err := db.QueryRow(`SELECT status FROM message_assignments WHERE assignment_id = ?`, id).Scan(&s); err != nil {
+			t.Fatalf("query %s: %v", id, err)
+		}
+		return s
+	}
+
+	apply(types.MessageAssignEvent{Type: "message.assign", Timestamp: "2026-01-01T00:00:01Z", MessageID: "msg_task", AssignmentID: "asg_1", Assignee: "bob", AgentID: "alice"})
+	apply(types.MessageAssignEvent{Type: "message.assign", Timestamp: "2026-01-01T00:00:02Z", MessageID: "msg_task", AssignmentID: "asg_2", Assignee: "carol", AgentID: "alice"})
+	if got := status("asg_1"); got != "reassigned" {
+		t.Errorf("first assignment = %q, want reassigned", got)
+	}
+
+	// A complete for the reassigned row is ignored; the open one completes.
+	apply(types.MessageCompleteEvent{Type: "message.complete", Timestamp: "2026-01-01T00:00:03Z", MessageID: "msg_task", AssignmentID: "asg_1", AgentID: "bob"})
+	apply(types.MessageCompleteEvent{Type: "message.complete", Timestamp: "2026-01-01T00:00:04Z", MessageID: "msg_task", AssignmentID: "asg_2", AgentID: "carol"})
+	if got := status("asg_1"); got != "reassigned" {
+		t.Errorf("reassigned row after stale complete = %q", got)
+	}
+	if got := status("asg_2"); got != "completed" {
+		t.Errorf("second assignment = %q, want completed", got)
+	}
+
+	// Assignments for messages this replica never saw are skipped.
+	apply(types.MessageAssignEvent{Type: "message.assign", Timestamp: "2026-01-01T00:00:05Z", MessageID: "msg_missing", AssignmentID: "asg_3", Assignee: "bob", AgentID: "alice"})
+	var n int
+	_ = db.QueryRow(`SELECT COUNT(*) FROM message_assignments WHERE message_id = 'msg_missing'`).Scan(&n)
+	if n != 0 {
+		t.Errorf("assignment for unknown message projected")
+	}
+}
+
+func TestProjector_ReplyExtendsMessageExpiry(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	p := projection.NewProjector(safedb.New(db))
+	create := func(id, ts, expiresAt string, refs []types.Ref) {
+		t.Helper()
+		data, _ := json.Marshal(types.MessageCreateEvent{
+			Type:      "message.create",
+			Timestamp: ts,
+			MessageID: id,
+			AgentID:   "sender_test",
+			SessionID: "ses_sender",
+			Body:      types.MessageBody{Format: "markdown", Content: "hi"},
+			Refs:      refs,
+			ExpiresAt: expiresAt,
+		})
+		if err := p.Apply(context.Background(), data); err != nil {
+			t.Fatalf("apply create %s: %v", id, err)
+		}
+	}
+	expiry := func(id string) string {
+		t.Helper()
+		var v sql.NullString
+		if err := db.QueryRow(`SELECT expires_at FROM messages WHERE message_id = ?`, id).Scan(&v); err != nil {
+			t.Fatalf("read expires_at %s: %v", id, err)
+		}
+		return v.String
+	}
+
+	// One-hour TTL; a reply 30 minutes in pushes expiry to reply + 1h.
+	create("msg_ttl", "2026-01-01T00:00:00Z", "2026-01-01T01:00:00Z", nil)
+	create("msg_reply", "2026-01-01T00:30:00Z", "", []types.Ref{{Type: "reply_to", Value: "msg_ttl"}})
+	if got := expiry("msg_ttl"); got != "2026-01-01T01:30:00Z" {
+		t.Errorf("expiry after reply = %q, want 2026-01-01T01:30:00Z", got)
+	}
+	if got := expiry("msg_reply"); got != "" {
+		t.Errorf("reply without --ttl got expiry %q", got)
+	}
+
+	// A reply that lands after expiry doesn't resurrect the message, and
+	// an unrelated message leaves it alone.
+	create("msg_other", "2026-01-01T00:45:00Z", "", nil)
+	create("msg_late", "2026-01-01T02:00:00Z", "", []types.Ref{{Type: "reply_to", Value: "msg_ttl"}})
+	if got := expiry("msg_ttl"); got != "2026-01-01T01:30:00Z" {
+		t.Errorf("expiry after late reply = %q, want unchanged 2026-01-01T01:30:00Z", got)
+	}
+}
+
+func TestProjector_ScheduledMessageCreatedAtDelivery(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	p := projection.NewProjector(safedb.New(db))
+	data, _ := json.Marshal(types.MessageCreateEvent{
+		Type:       "message.create",
+		Timestamp:  "2026-01-01T18:00:00Z",
+		MessageID:  "msg_sched",
+		AgentID:    "sender_test",
+		SessionID:  "ses_sender",
+		Body:       types.MessageBody{Format: "markdown", Content: "good morning"},
+		Recipients: []string{"reader_test"},
+		DeliverAt:  "2026-01-02T09:00:00Z",
+	})
+	if err := p.Apply(context.Background(), data); err != nil {
+		t.Fatalf("apply create: %v", err)
+	}
+
+	var createdAt, deliverAt, deliveredAt string
+	if err := db.QueryRow(`SELECT created_at, deliver_at FROM messages WHERE message_id = 'msg_sched'`).Scan(&createdAt, &deliverAt); err != nil {
+		t.Fatalf("read message: %v", err)
+	}
+	if err := db.QueryRow(`SELECT delivered_at FROM message_deliveries WHERE message_id = 'msg_sched' AND recipient_agent_id = 'reader_test'`).Scan(&deliveredAt); err != nil {
+		t.Fatalf("read delivery: %v", err)
+	}
+	for name, got := range map[string]string{"created_at": createdAt, "deliver_at": deliverAt, "delivered_at": deliveredAt} {
+		if got != "2026-01-02T09:00:00Z" {
+			t.Errorf("%s = %q, want the deliver_at 2026-01-02T09:00:00Z", name, got)
+		}
+	}
+}
+
+func TestProjector_ApplyAgentAlias(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	p := projection.NewProjector(safedb.New(db))
+	insertAgent(t, db, "alice", "implementer")
+
+	apply := func(agentID, alias string, removed bool) {
+		t.Helper()
+		data, _ := json.Marshal(types.AgentAliasEvent{
+			Type:      "agent.alias",
+			Timestamp: "2026-01-01T00:00:05Z",
+			AgentID:   agentID,
+			Alias:     alias,
+			Removed:   removed,
+		})
+		if err := p.Apply(context.Background(), data); err != nil {
+			t.Fatalf("apply alias %s→%s: %v", alias, agentID, err)
+		}
+	}
+	owner := func(alias string) string {
+		t.Helper()
+		var agentID string
+		err := db.QueryRow(`SELECT agent_id FROM agent_aliases WHERE alias = ?`, alias).Scan(&agentID)
+		if err == sql.ErrNoRows {
+			return ""
+		}
+		if err != nil {
+			t.Fatalf("query alias: %v", err)
+		}
+		return agentID
+	}
+
+	apply("alice", "al", false)
+	apply("alice", "al", false)
+	if got := owner("al"); got != "alice" {
+		t.Fatalf("alias al → %q, want alice", got)
+	}
+
+	// An alias for an agent that isn't projected is skipped, not an error.
+	apply("ghost", "boo", false)
+	if got := owner("boo"); got != "" {
+		t.Fatalf("alias for missing agent → %q, want none", got)
+	}
+
+	apply("alice", "al", true)
+	if got := owner("al"); got != "" {
+		t.Fatalf("alias after remove → %q, want none", got)
+	}
+}
+
+func TestProjector_ApplyAgentCapabilities(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	p := projection.NewProjector(safedb.New(db))
+	insertAgent(t, db, "alice", "implementer")
+
+	apply := func(agentID string, caps ...string) {
+		t.Helper()
+		data, _ := json.Marshal(types.AgentCapabilitiesEvent{
+			Type:         "agent.capabilities",
+			Timestamp:    "2026-01-01T00:00:05Z",
+			AgentID:      agentID,
+			Capabilities: caps,
+		})
+		if err := p.Apply(context.Background(), data); err != nil {
+			t.Fatalf("apply capabilities for %s: %v", agentID, err)
+		}
+	}
+	capabilities := func(agentID string) string {
+		t.Helper()
+		var got sql.NullString
+		if err := db.QueryRow(`SELECT group_concat(capability, ',') FROM (SELECT capability FROM agent_capabilities WHERE agent_id = ? ORDER BY capability)`, agentID).Scan(&got); err != nil {
+			t.Fatalf("query capabilities: %v", err)
+		}
+		return got.String
+	}
+
+	apply("alice", "go", "sql")
+	apply("alice", "go", "sql")
+	if got := capabilities("alice"); got != "go,sql" {
+		t.Fatalf("capabilities = %q, want go,sql", got)
+	}
+
+	// Each event replaces the whole set.
+	apply("alice", "frontend")
+	if got := capabilities("alice"); got != "frontend" {
+		t.Fatalf("capabilities after replace = %q, want frontend", got)
+	}
+
+	// Capabilities for an agent that isn't projected are skipped.
+	apply("ghost", "go")
+	if got := capabilities("ghost"); got != "" {
+		t.Fatalf("capabilities for missing agent = %q, want none", got)
+	}
+
+	apply("alice")
+	if got := capabilities("alice"); got != "" {
+		t.Fatalf("capabilities after clear = %q, want none", got)
+	}
+}
+
+func TestProjector_ApplyAgentMute(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	p := projection.NewProjector(safedb.New(db))
+	insertAgent(t, db, "alice", "implementer")
+
+	apply := func(event types.AgentMuteEvent) {
+		t.Helper()
+		event.Type = "agent.mute"
+		event.Timestamp = "2026-01-01T00:00:05Z"
+		data, _ := json.Marshal(event)
+		if err := p.Apply(context.Background(), data); err != nil {
+			t.Fatalf("apply mute for %s: %v", event.AgentID, err)
+		}
+	}
+	mute := func(agentID string) (until sql.NullString, allowMentions bool, found bool) {
+		t.Helper()
+		err := db.QueryRow(`SELECT muted_until, allow_mentions FROM agent_mutes WHERE agent_id = ?`, agentID).Scan(&until, &allowMentions)
+		if errors.Is(err, sql.ErrNoRows) {
+			return until, false, false
+		}
+		if err != nil {
+			t.Fatalf("query mute: %v", err)
+		}
+		return until, allowMentions, true
+	}
+
+	apply(types.AgentMuteEvent{AgentID: "alice", Until: "2026-01-01T00:30:05Z"})
+	if until, allow, found := mute("alice"); !found || until.String != "2026-01-01T00:30:05Z" || allow {
+		t.Fatalf("mute = %v, %v, %v; want until 00:30:05 without mentions", until, allow, found)
+	}
+
+	// Muting again replaces the row; an empty Until means indefinite.
+	apply(types.AgentMuteEvent{AgentID: "alice", AllowMentions: true})
+	if until, allow, found := mute("alice"); !found || until.Valid || !allow {
+		t.Fatalf("mute after replace = %v, %v, %v; want indefinite with mentions", until, allow, found)
+	}
+
+	apply(types.AgentMuteEvent{AgentID: "ghost"})
+	if _, _, found := mute("ghost"); found {
+		t.Fatal("mute for missing agent was projected")
+	}
+
+	apply(types.AgentMuteEvent{AgentID: "alice", Removed: true})
+	if _, _, found := mute("alice"); found {
+		t.Fatal("mute still present after unmute")
+	}
+}
+
+func TestProjector_ApplyAgentRename(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	p := projection.NewProjector(safedb.New(db))
+	insertAgent(t, db, "alice", "implementer")
+	insertAgent(t, db, "bob", "reviewer")
+	_, _ = db.Exec(`INSERT INTO sessions (session_id, agent_id, started_at, last_seen_at) VALUES ('ses_a', 'alice', '2026-01-01T00:00:00Z', '2026-01-01T00:00:00Z')`)
+	_, _ = db.Exec(`INSERT INTO agent_aliases (alias, agent_id, created_at) VALUES ('al', 'alice', '2026-01-01T00:00:00Z')`)
+
+	apply := func(oldID, newID string) {
+		t.Helper()
+		data, _ := json.Marshal(types.AgentRenameEvent{
+			Type:       "agent.rename",
+			Timestamp:  "2026-01-01T00:00:05Z",
+			AgentID:    newID,
+			OldAgentID: oldID,
+		})
+		if err := p.Apply(context.Background(), data); err != nil {
+			t.Fatalf("apply rename %s→%s: %v", oldID, newID, err)
+		}
+	}
+	count := func(query, arg string) int {
+		t.Helper()
+		var n int
+		if err := db.QueryRow(query, arg).Scan(&n); err != nil {
+			t.Fatalf("%s: %v", query, err)
+		}
+		return n
+	}
+
+	apply("alice", "alicia")
+	for _, q := range []string{
+		`SELECT COUNT(*) FROM agents WHERE agent_id = ?`,
+		`SELECT COUNT(*) FROM sessions WHERE agent_id = ?`,
+		`SELECT COUNT(*) FROM agent_aliases WHERE agent_id = ?`,
+	} {
+		if got := count(q, "alicia"); got != 1 {
+			t.Errorf("%s [alicia] = %d, want 1", q, got)
+		}
+		if got := count(q, "alice"); got != 0 {
+			t.Errorf("%s [alice] = %d, want 0", q, got)
+		}
+	}
+
+	// Replaying is a no-op, and a rename onto an existing agent never
+	// merges the two.
+	apply("alice", "alicia")
+	apply("alicia", "bob")
+	if got := count(`SELECT COUNT(*) FROM agents WHERE agent_id = ?`, "alicia"); got != 1 {
+		t.Errorf("rename onto existing agent moved alicia (count = %d)", got)
+	}
+}
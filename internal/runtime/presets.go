@@ -148,11 +148,11 @@ var BuiltinPresets = map[string]RuntimePreset{
 		Name:             "amp",
 		DisplayName:      "Sourcegraph Amp",
 		Command:          "amp",
-		MCPSupported:     false,
+		MCPSupported:     true,
 		HooksSupported:   false,
-		InstructionsFile: "CLAUDE.md",
-		MCPConfigPath:    "",
-		SetupNotes:       "CLI-only integration (MCP support unknown)",
+		InstructionsFile: "AGENTS.md",
+		MCPConfigPath:    ".amp/settings.json",
+		SetupNotes:       "thrum init --runtime amp writes amp.mcpServers to .amp/settings.json and AGENTS.md",
 	},
 	"shell": {
 		Name:             "shell",
@@ -23,7 +23,7 @@ func TestGetPreset_Builtin(t *testing.T) {
 		{"cursor", "Cursor", "agent", true, false},
 		{"gemini", "Google Gemini Code Assist", "gemini", true, false},
 		{"auggie", "Augment (Auggie)", "auggie", false, false},
-		{"amp", "Sourcegraph Amp", "amp", false, false},
+		{"amp", "Sourcegraph Amp", "amp", true, false},
 		{"kiro-cli", "Amazon Kiro CLI", "kiro-cli chat", false, false},
 	}
 
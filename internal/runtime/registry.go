@@ -75,6 +75,7 @@ var builtinAgents = []AgentDefinition{
 	{
 		Name:        "amp",
 		DisplayName: "Sourcegraph Amp",
+		RepoMarkers: []string{".amp/"},
 		Binaries:    []BinaryCheck{{Name: "amp", VerifyArgs: []string{"--version"}, MatchAny: []string{"sourcegraph"}}},
 		SkillsDir:   ".agents/skills",
 	},
@@ -86,7 +86,52 @@ import (
 //     v46 was the post-rebuild read-state corrective — none have a runMigrations
 //     block, and the release line never references SchemaVersionReadStatePost-
 //     Rebuild, so no state.NewState change is needed.
-const CurrentVersion = 51
+//   - v52: message_reactions (message.react). Emoji acknowledgements keyed by
+//     (message_id, agent_id, emoji), projected from message.react events.
+//   - v53: messages_fts (message.search). FTS5 shadow of messages.body_content
+//     maintained by the projector; the migration backfills live messages.
+//   - v54: message_tags (send --tag, message.list tag filter). One row per
+//     (message_id, tag), projected from message.create tags.
+//   - v55: agent_aliases (agent alias set/remove). Nicknames that resolve to
+//     an agent_id, projected from agent.alias events.
+//   - v56: message_pins (message pin/unpin). One row per pinned message,
+//     shared by every agent in the repo, projected from message.pin events.
+//   - v57: messages.expires_at (send --ttl). NULL for messages without a TTL;
+//     a reply in the thread pushes it out, and the daemon cleanup pass
+//     soft-deletes messages past it.
+//   - v58: agent_capabilities (agent register --capability, agent
+//     set-capabilities, who-can). One row per (agent_id, capability),
+//     projected from agent.capabilities events.
+//   - v59: agent_mutes (agent mute/unmute). One row per muted agent,
+//     projected from agent.mute events; the subscription dispatcher and
+//     wait skip the agent until muted_until passes.
+//   - v60: message_assignments (message assign/complete). One row per
+//     assignment, projected from message.assign and message.complete
+//     events; reassigning closes the open row instead of replacing it.
+//   - v61: pending_receipts. Seen/read receipts whose message has not been
+//     projected yet (out-of-order peer sync), held until its message.create
+//     lands and then replayed onto message_deliveries.
+//   - v62: messages.bumped_at (message bump). NULL until the author bumps
+//     the message; inbox --bump-sort orders by it, falling back to
+//     created_at.
+//   - v63: send_idempotency (message.send idempotency_key). Daemon-local,
+//     not projected from events: one row per (agent_id, idempotency_key)
+//     holding the request hash and the original response, so a retried
+//     send returns the first result instead of sending twice.
+//   - v64: messages.origin_daemon + messages.origin_sequence (message list
+//     --order sequence). The daemon that created the message and the event
+//     sequence it assigned; (origin_sequence, origin_daemon) is a total
+//     order that survives event compaction. Backfilled from the events
+//     table where the message.create event is still present.
+//   - v65: subscriptions.scope_prefix. 1 when the subscription matches its
+//     scope value and every path segment below it (module:auth also
+//     matches module:auth/login); 0, the default, keeps exact matching.
+//   - v66: messages.deliver_at + scheduled_messages (send --schedule).
+//     deliver_at is NULL for messages sent right away; message.list hides
+//     the message until it passes. scheduled_messages is daemon-local, not
+//     projected from events: it holds each scheduled message.create until
+//     the daemon's message scheduler releases its notifications.
+const CurrentVersion = 66
 
 // SchemaVersionReadState is the read-state unification crossing (thrum-b6qw,
 // backport of thrum-tcqw): at the first boot where the pre-migration version is
@@ -94,6 +139,23 @@ const CurrentVersion = 51
 // the one-time BackfillReadState. Data-only; no DDL is attached to this version.
 const SchemaVersionReadState = 40
 
+// MessageChildTables are the tables holding per-message rows keyed by
+// message_id, including the messages_fts index. Every path that hard-deletes
+// messages (purge, agent delete and cleanup, group and scope deletes) clears
+// these first, so a new per-message table only needs adding here.
+var MessageChildTables = []string{
+	"messages_fts",
+	"message_pins",
+	"message_assignments",
+	"message_reactions",
+	"message_tags",
+	"message_edits",
+	"message_reads",
+	"message_deliveries",
+	"message_refs",
+	"message_scopes",
+}
+
 // InitDB initializes a new database with the current schema.
 func InitDB(db *sql.DB) error {
 	// Begin transaction
@@ -225,7 +287,12 @@ func createTables(tx *sql.Tx) error {
 			-- v47/v48 forward-port (thrum-399av): dead-end columns, no release-line reader.
 			visibility_class TEXT NOT NULL DEFAULT 'targeted',
 			retarget_fill_order TEXT,
-			priority TEXT NOT NULL DEFAULT ''
+			priority TEXT NOT NULL DEFAULT '',
+			expires_at TEXT,
+			bumped_at TEXT,
+			origin_daemon TEXT NOT NULL DEFAULT '',
+			origin_sequence INTEGER NOT NULL DEFAULT 0,
+			deliver_at TEXT
 		)`,
 
 		// Message scopes table
@@ -301,6 +368,7 @@ func createTables(tx *sql.Tx) error {
 			scope_value  TEXT,
 			mention_role TEXT,
 			created_at   TEXT NOT NULL,
+			scope_prefix INTEGER NOT NULL DEFAULT 0,
 			UNIQUE(session_id, scope_type, scope_value, mention_role)
 		)`,
 
@@ -794,6 +862,115 @@ func createTables(tx *sql.Tx) error {
 			subkind           TEXT NOT NULL DEFAULT '',
 			last_edited_by    TEXT NOT NULL DEFAULT ''
 		)`,
+
+		// Message reactions (v52): emoji acknowledgements, one row per
+		// (message, agent, emoji). Not a read receipt.
+		`CREATE TABLE IF NOT EXISTS message_reactions (
+			message_id TEXT NOT NULL,
+			agent_id   TEXT NOT NULL,
+			emoji      TEXT NOT NULL,
+			reacted_at TEXT NOT NULL,
+			PRIMARY KEY (message_id, agent_id, emoji),
+			FOREIGN KEY (message_id) REFERENCES messages(message_id) ON DELETE CASCADE
+		)`,
+
+		// messages_fts (v53): FTS5 SHADOW table for message.search, same
+		// shape as memory_fts (no content= clause). The projector keeps it
+		// in lockstep with messages.body_content on create/edit/delete;
+		// search joins back to messages, so rows orphaned by hard-delete
+		// paths never surface.
+		`CREATE VIRTUAL TABLE IF NOT EXISTS messages_fts USING fts5(
+			message_id UNINDEXED,
+			body_content
+		)`,
+
+		// Message tags (v54): free-form labels attached at send time.
+		`CREATE TABLE IF NOT EXISTS message_tags (
+			message_id TEXT NOT NULL,
+			tag        TEXT NOT NULL,
+			PRIMARY KEY (message_id, tag),
+			FOREIGN KEY (message_id) REFERENCES messages(message_id) ON DELETE CASCADE
+		)`,
+
+		// Agent aliases (v55): nicknames accepted wherever an agent is
+		// resolved (--to, mentions, ping, group members).
+		`CREATE TABLE IF NOT EXISTS agent_aliases (
+			alias      TEXT PRIMARY KEY,
+			agent_id   TEXT NOT NULL,
+			created_at TEXT NOT NULL
+		)`,
+
+		// Message pins (v56): repo-wide, not per agent — a message is
+		// either pinned for everyone or not at all.
+		`CREATE TABLE IF NOT EXISTS message_pins (
+			message_id TEXT PRIMARY KEY,
+			pinned_by  TEXT NOT NULL,
+			pinned_at  TEXT NOT NULL,
+			FOREIGN KEY (message_id) REFERENCES messages(message_id) ON DELETE CASCADE
+		)`,
+
+		// Agent capabilities (v58): skill tags an agent advertises, for
+		// routing work by what an agent can do rather than its role.
+		`CREATE TABLE IF NOT EXISTS agent_capabilities (
+			agent_id   TEXT NOT NULL,
+			capability TEXT NOT NULL,
+			created_at TEXT NOT NULL,
+			PRIMARY KEY (agent_id, capability)
+		)`,
+
+		// Agent mutes (v59): notification pushes paused per agent. A NULL
+		// muted_until means muted until unmuted; an expired row is simply
+		// ignored.
+		`CREATE TABLE IF NOT EXISTS agent_mutes (
+			agent_id       TEXT PRIMARY KEY,
+			muted_until    TEXT,
+			allow_mentions INTEGER NOT NULL DEFAULT 0,
+			muted_at       TEXT NOT NULL
+		)`,
+
+		// Message assignments (v60): a message turned into a task for an
+		// agent. At most one row per message is 'open'; reassigned and
+		// completed rows stay behind as history.
+		`CREATE TABLE IF NOT EXISTS message_assignments (
+			assignment_id TEXT PRIMARY KEY,
+			message_id    TEXT NOT NULL,
+			assignee      TEXT NOT NULL,
+			assigned_by   TEXT NOT NULL,
+			status        TEXT NOT NULL DEFAULT 'open',
+			assigned_at   TEXT NOT NULL,
+			closed_at     TEXT,
+			FOREIGN KEY (message_id) REFERENCES messages(message_id) ON DELETE CASCADE
+		)`,
+
+		// Pending receipts (v61): message.receipt events that arrived before
+		// their message. No foreign key — the message does not exist yet.
+		// Keeps the earliest timestamp per (message, agent, type).
+		`CREATE TABLE IF NOT EXISTS pending_receipts (
+			message_id   TEXT NOT NULL,
+			agent_id     TEXT NOT NULL,
+			receipt_type TEXT NOT NULL,
+			timestamp    TEXT NOT NULL,
+			PRIMARY KEY (message_id, agent_id, receipt_type)
+		)`,
+
+		// Send idempotency keys (v63): local to this daemon, never synced.
+		// response is empty while the first send is still in flight.
+		`CREATE TABLE IF NOT EXISTS send_idempotency (
+			agent_id        TEXT NOT NULL,
+			idempotency_key TEXT NOT NULL,
+			request_hash    TEXT NOT NULL,
+			response        TEXT NOT NULL DEFAULT '',
+			created_at      TEXT NOT NULL,
+			PRIMARY KEY (agent_id, idempotency_key)
+		)`,
+
+		// Scheduled message queue (v66): local to this daemon, never synced.
+		// One row per message.create with a deliver_at, deleted on release.
+		`CREATE TABLE IF NOT EXISTS scheduled_messages (
+			message_id TEXT PRIMARY KEY,
+			deliver_at TEXT NOT NULL,
+			event_json TEXT NOT NULL
+		)`,
 	}
 
 	for _, sql := range tables {
@@ -820,6 +997,10 @@ func createIndexes(tx *sql.Tx) error {
 		"CREATE INDEX IF NOT EXISTS idx_messages_agent ON messages(agent_id)",
 		"CREATE INDEX IF NOT EXISTS idx_messages_session ON messages(session_id)",
 		"CREATE INDEX IF NOT EXISTS idx_messages_not_deleted ON messages(deleted) WHERE deleted = 0",
+		"CREATE INDEX IF NOT EXISTS idx_messages_expires ON messages(expires_at) WHERE expires_at IS NOT NULL",
+		"CREATE INDEX IF NOT EXISTS idx_messages_origin_seq ON messages(origin_sequence, origin_daemon)",
+		"CREATE INDEX IF NOT EXISTS idx_messages_deliver ON messages(deliver_at) WHERE deliver_at IS NOT NULL",
+		"CREATE INDEX IF NOT EXISTS idx_scheduled_messages_deliver ON scheduled_messages(deliver_at)",
 
 		// Scope and ref indexes
 		"CREATE INDEX IF NOT EXISTS idx_scopes_lookup ON message_scopes(scope_type, scope_value)",
@@ -833,6 +1014,11 @@ func createIndexes(tx *sql.Tx) error {
 
 		// Message edits index
 		"CREATE INDEX IF NOT EXISTS idx_edits_message ON message_edits(message_id, edited_at)",
+		"CREATE INDEX IF NOT EXISTS idx_message_tags_tag ON message_tags(tag, message_id)",
+		"CREATE INDEX IF NOT EXISTS idx_agent_aliases_agent ON agent_aliases(agent_id)",
+		"CREATE INDEX IF NOT EXISTS idx_agent_capabilities_capability ON agent_capabilities(capability)",
+		"CREATE INDEX IF NOT EXISTS idx_message_assignments_message ON message_assignments(message_id)",
+		"CREATE INDEX IF NOT EXISTS idx_message_assignments_assignee ON message_assignments(assignee, status)",
 
 		// Session scopes and refs indexes
 		"CREATE INDEX IF NOT EXISTS idx_session_scopes_lookup ON session_scopes(scope_type, scope_value)",
@@ -2424,6 +2610,322 @@ func runMigrations(db *sql.DB, startVersion, endVersion int) error {
 		}
 	}
 
+	// v52: message_reactions (message.react).
+	if startVersion < 52 && endVersion >= 52 {
+		if _, err := tx.Exec(`CREATE TABLE IF NOT EXISTS message_reactions (
+			message_id TEXT NOT NULL,
+			agent_id   TEXT NOT NULL,
+			emoji      TEXT NOT NULL,
+			reacted_at TEXT NOT NULL,
+			PRIMARY KEY (message_id, agent_id, emoji),
+			FOREIGN KEY (message_id) REFERENCES messages(message_id) ON DELETE CASCADE
+		)`); err != nil {
+			return fmt.Errorf("migration 51→52: create message_reactions: %w", err)
+		}
+	}
+
+	// v53: messages_fts (message.search). Backfill live messages so search
+	// covers history written before the upgrade.
+	if startVersion < 53 && endVersion >= 53 {
+		if _, err := tx.Exec(`CREATE VIRTUAL TABLE IF NOT EXISTS messages_fts USING fts5(
+			message_id UNINDEXED,
+			body_content
+		)`); err != nil {
+			return fmt.Errorf("migration 52→53: create messages_fts: %w", err)
+		}
+		hasMessages, hasErr := tableExists(tx, "messages")
+		if hasErr != nil {
+			return fmt.Errorf("migration 52→53: check messages table: %w", hasErr)
+		}
+		if hasMessages {
+			cols, colErr := columnSet(tx, "messages")
+			if colErr != nil {
+				return fmt.Errorf("migration 52→53: read messages columns: %w", colErr)
+			}
+			backfill := `INSERT INTO messages_fts (message_id, body_content)
+				SELECT message_id, body_content FROM messages`
+			if cols["deleted"] {
+				backfill += ` WHERE deleted = 0`
+			}
+			if _, err := tx.Exec(backfill); err != nil {
+				return fmt.Errorf("migration 52→53: backfill messages_fts: %w", err)
+			}
+		}
+	}
+
+	// v54: message_tags. Nothing to backfill — tags were accepted on
+	// message.send but never persisted before this version.
+	if startVersion < 54 && endVersion >= 54 {
+		if _, err := tx.Exec(`CREATE TABLE IF NOT EXISTS message_tags (
+			message_id TEXT NOT NULL,
+			tag        TEXT NOT NULL,
+			PRIMARY KEY (message_id, tag),
+			FOREIGN KEY (message_id) REFERENCES messages(message_id) ON DELETE CASCADE
+		)`); err != nil {
+			return fmt.Errorf("migration 53→54: create message_tags: %w", err)
+		}
+		if _, err := tx.Exec(`CREATE INDEX IF NOT EXISTS idx_message_tags_tag ON message_tags(tag, message_id)`); err != nil {
+			return fmt.Errorf("migration 53→54: create idx_message_tags_tag: %w", err)
+		}
+	}
+
+	// v55: agent_aliases. New feature, nothing to backfill.
+	if startVersion < 55 && endVersion >= 55 {
+		if _, err := tx.Exec(`CREATE TABLE IF NOT EXISTS agent_aliases (
+			alias      TEXT PRIMARY KEY,
+			agent_id   TEXT NOT NULL,
+			created_at TEXT NOT NULL
+		)`); err != nil {
+			return fmt.Errorf("migration 54→55: create agent_aliases: %w", err)
+		}
+		if _, err := tx.Exec(`CREATE INDEX IF NOT EXISTS idx_agent_aliases_agent ON agent_aliases(agent_id)`); err != nil {
+			return fmt.Errorf("migration 54→55: create idx_agent_aliases_agent: %w", err)
+		}
+	}
+
+	// v56: message_pins. New feature, nothing to backfill.
+	if startVersion < 56 && endVersion >= 56 {
+		if _, err := tx.Exec(`CREATE TABLE IF NOT EXISTS message_pins (
+			message_id TEXT PRIMARY KEY,
+			pinned_by  TEXT NOT NULL,
+			pinned_at  TEXT NOT NULL,
+			FOREIGN KEY (message_id) REFERENCES messages(message_id) ON DELETE CASCADE
+		)`); err != nil {
+			return fmt.Errorf("migration 55→56: create message_pins: %w", err)
+		}
+	}
+
+	// v57: messages.expires_at. Existing messages never expire, so NULL is
+	// the right value for every pre-existing row.
+	if startVersion < 57 && endVersion >= 57 {
+		hasMessages, hasErr := tableExists(tx, "messages")
+		if hasErr != nil {
+			return fmt.Errorf("migration 56→57: check messages table: %w", hasErr)
+		}
+		if hasMessages {
+			cols, colErr := columnSet(tx, "messages")
+			if colErr != nil {
+				return fmt.Errorf("migration 56→57: read messages columns: %w", colErr)
+			}
+			if !cols["expires_at"] {
+				if _, err := tx.Exec(`ALTER TABLE messages ADD COLUMN expires_at TEXT`); err != nil {
+					return fmt.Errorf("migration 56→57: add messages.expires_at: %w", err)
+				}
+			}
+			if _, err := tx.Exec(`CREATE INDEX IF NOT EXISTS idx_messages_expires ON messages(expires_at) WHERE expires_at IS NOT NULL`); err != nil {
+				return fmt.Errorf("migration 56→57: create idx_messages_expires: %w", err)
+			}
+		}
+	}
+
+	// v58: agent_capabilities. New feature, nothing to backfill.
+	if startVersion < 58 && endVersion >= 58 {
+		if _, err := tx.Exec(`CREATE TABLE IF NOT EXISTS agent_capabilities (
+			agent_id   TEXT NOT NULL,
+			capability TEXT NOT NULL,
+			created_at TEXT NOT NULL,
+			PRIMARY KEY (agent_id, capability)
+		)`); err != nil {
+			return fmt.Errorf("migration 57→58: create agent_capabilities: %w", err)
+		}
+		if _, err := tx.Exec(`CREATE INDEX IF NOT EXISTS idx_agent_capabilities_capability ON agent_capabilities(capability)`); err != nil {
+			return fmt.Errorf("migration 57→58: create idx_agent_capabilities_capability: %w", err)
+		}
+	}
+
+	// v59: agent_mutes. New feature, nothing to backfill.
+	if startVersion < 59 && endVersion >= 59 {
+		if _, err := tx.Exec(`CREATE TABLE IF NOT EXISTS agent_mutes (
+			agent_id       TEXT PRIMARY KEY,
+			muted_until    TEXT,
+			allow_mentions INTEGER NOT NULL DEFAULT 0,
+			muted_at       TEXT NOT NULL
+		)`); err != nil {
+			return fmt.Errorf("migration 58→59: create agent_mutes: %w", err)
+		}
+	}
+
+	// v60: message_assignments. New feature, nothing to backfill.
+	if startVersion < 60 && endVersion >= 60 {
+		if _, err := tx.Exec(`CREATE TABLE IF NOT EXISTS message_assignments (
+			assignment_id TEXT PRIMARY KEY,
+			message_id    TEXT NOT NULL,
+			assignee      TEXT NOT NULL,
+			assigned_by   TEXT NOT NULL,
+			status        TEXT NOT NULL DEFAULT 'open',
+			assigned_at   TEXT NOT NULL,
+			closed_at     TEXT,
+			FOREIGN KEY (message_id) REFERENCES messages(message_id) ON DELETE CASCADE
+		)`); err != nil {
+			return fmt.Errorf("migration 59→60: create message_assignments: %w", err)
+		}
+		if _, err := tx.Exec(`CREATE INDEX IF NOT EXISTS idx_message_assignments_message ON message_assignments(message_id)`); err != nil {
+			return fmt.Errorf("migration 59→60: create idx_message_assignments_message: %w", err)
+		}
+		if _, err := tx.Exec(`CREATE INDEX IF NOT EXISTS idx_message_assignments_assignee ON message_assignments(assignee, status)`); err != nil {
+			return fmt.Errorf("migration 59→60: create idx_message_assignments_assignee: %w", err)
+		}
+	}
+
+	// v61: pending_receipts. Receipts dropped before this version are gone
+	// from the projection; nothing to backfill.
+	if startVersion < 61 && endVersion >= 61 {
+		if _, err := tx.Exec(`CREATE TABLE IF NOT EXISTS pending_receipts (
+			message_id   TEXT NOT NULL,
+			agent_id     TEXT NOT NULL,
+			receipt_type TEXT NOT NULL,
+			timestamp    TEXT NOT NULL,
+			PRIMARY KEY (message_id, agent_id, receipt_type)
+		)`); err != nil {
+			return fmt.Errorf("migration 60→61: create pending_receipts: %w", err)
+		}
+	}
+
+	// v62: messages.bumped_at. No message has been bumped yet, so NULL is
+	// the right value for every pre-existing row.
+	if startVersion < 62 && endVersion >= 62 {
+		hasMessages, hasErr := tableExists(tx, "messages")
+		if hasErr != nil {
+			return fmt.Errorf("migration 61→62: check messages table: %w", hasErr)
+		}
+		if hasMessages {
+			cols, colErr := columnSet(tx, "messages")
+			if colErr != nil {
+				return fmt.Errorf("migration 61→62: read messages columns: %w", colErr)
+			}
+			if !cols["bumped_at"] {
+				if _, err := tx.Exec(`ALTER TABLE messages ADD COLUMN bumped_at TEXT`); err != nil {
+					return fmt.Errorf("migration 61→62: add messages.bumped_at: %w", err)
+				}
+			}
+		}
+	}
+
+	// v63: send_idempotency. Starts empty; keys only matter for retries of
+	// sends made after the upgrade.
+	if startVersion < 63 && endVersion >= 63 {
+		if _, err := tx.Exec(`CREATE TABLE IF NOT EXISTS send_idempotency (
+			agent_id        TEXT NOT NULL,
+			idempotency_key TEXT NOT NULL,
+			request_hash    TEXT NOT NULL,
+			response        TEXT NOT NULL DEFAULT '',
+			created_at      TEXT NOT NULL,
+			PRIMARY KEY (agent_id, idempotency_key)
+		)`); err != nil {
+			return fmt.Errorf("migration 62→63: create send_idempotency: %w", err)
+		}
+	}
+
+	// v64: messages.origin_daemon + messages.origin_sequence. Rows whose
+	// message.create event is still in the events table get its sequence
+	// and origin back; messages whose event was compacted or purged keep
+	// (0, '') and sort before everything else in sequence order.
+	if startVersion < 64 && endVersion >= 64 {
+		hasMessages, hasErr := tableExists(tx, "messages")
+		if hasErr != nil {
+			return fmt.Errorf("migration 63→64: check messages table: %w", hasErr)
+		}
+		if hasMessages {
+			cols, colErr := columnSet(tx, "messages")
+			if colErr != nil {
+				return fmt.Errorf("migration 63→64: read messages columns: %w", colErr)
+			}
+			if !cols["origin_daemon"] {
+				if _, err := tx.Exec(`ALTER TABLE messages ADD COLUMN origin_daemon TEXT NOT NULL DEFAULT ''`); err != nil {
+					return fmt.Errorf("migration 63→64: add messages.origin_daemon: %w", err)
+				}
+			}
+			if !cols["origin_sequence"] {
+				if _, err := tx.Exec(`ALTER TABLE messages ADD COLUMN origin_sequence INTEGER NOT NULL DEFAULT 0`); err != nil {
+					return fmt.Errorf("migration 63→64: add messages.origin_sequence: %w", err)
+				}
+			}
+			if _, err := tx.Exec(`CREATE INDEX IF NOT EXISTS idx_messages_origin_seq ON messages(origin_sequence, origin_daemon)`); err != nil {
+				return fmt.Errorf("migration 63→64: create idx_messages_origin_seq: %w", err)
+			}
+
+			hasEvents, evErr := tableExists(tx, "events")
+			if evErr != nil {
+				return fmt.Errorf("migration 63→64: check events table: %w", evErr)
+			}
+			if hasEvents {
+				if _, err := tx.Exec(`UPDATE messages SET
+					origin_sequence = COALESCE(e.seq, 0),
+					origin_daemon   = COALESCE(e.origin, '')
+					FROM (
+						-- Relayed history can carry one message under several
+						-- event_ids; the bare columns come from the MIN row, so
+						-- the earliest local copy wins.
+						SELECT json_extract(event_json, '$.message_id') AS message_id,
+						       json_extract(event_json, '$.sequence') AS seq,
+						       json_extract(event_json, '$.origin_daemon') AS origin,
+						       MIN(sequence)
+						FROM events
+						WHERE type = 'message.create'
+						GROUP BY json_extract(event_json, '$.message_id')
+					) AS e
+					WHERE e.message_id = messages.message_id
+					  AND messages.origin_sequence = 0`); err != nil {
+					return fmt.Errorf("migration 63→64: backfill message sequences: %w", err)
+				}
+			}
+		}
+	}
+
+	// v65: subscriptions.scope_prefix. Existing subscriptions were all
+	// exact-match, which the 0 default preserves.
+	if startVersion < 65 && endVersion >= 65 {
+		hasSubs, hasErr := tableExists(tx, "subscriptions")
+		if hasErr != nil {
+			return fmt.Errorf("migration 64→65: check subscriptions table: %w", hasErr)
+		}
+		if hasSubs {
+			cols, colErr := columnSet(tx, "subscriptions")
+			if colErr != nil {
+				return fmt.Errorf("migration 64→65: read subscriptions columns: %w", colErr)
+			}
+			if !cols["scope_prefix"] {
+				if _, err := tx.Exec(`ALTER TABLE subscriptions ADD COLUMN scope_prefix INTEGER NOT NULL DEFAULT 0`); err != nil {
+					return fmt.Errorf("migration 64→65: add subscriptions.scope_prefix: %w", err)
+				}
+			}
+		}
+	}
+
+	// v66: messages.deliver_at + scheduled_messages. Every existing message
+	// was delivered when sent, so NULL is right for all of them and the
+	// queue starts empty.
+	if startVersion < 66 && endVersion >= 66 {
+		hasMessages, hasErr := tableExists(tx, "messages")
+		if hasErr != nil {
+			return fmt.Errorf("migration 65→66: check messages table: %w", hasErr)
+		}
+		if hasMessages {
+			cols, colErr := columnSet(tx, "messages")
+			if colErr != nil {
+				return fmt.Errorf("migration 65→66: read messages columns: %w", colErr)
+			}
+			if !cols["deliver_at"] {
+				if _, err := tx.Exec(`ALTER TABLE messages ADD COLUMN deliver_at TEXT`); err != nil {
+					return fmt.Errorf("migration 65→66: add messages.deliver_at: %w", err)
+				}
+			}
+			if _, err := tx.Exec(`CREATE INDEX IF NOT EXISTS idx_messages_deliver ON messages(deliver_at) WHERE deliver_at IS NOT NULL`); err != nil {
+				return fmt.Errorf("migration 65→66: create idx_messages_deliver: %w", err)
+			}
+		}
+		if _, err := tx.Exec(`CREATE TABLE IF NOT EXISTS scheduled_messages (
+			message_id TEXT PRIMARY KEY,
+			deliver_at TEXT NOT NULL,
+			event_json TEXT NOT NULL
+		)`); err != nil {
+			return fmt.Errorf("migration 65→66: create scheduled_messages: %w", err)
+		}
+		if _, err := tx.Exec(`CREATE INDEX IF NOT EXISTS idx_scheduled_messages_deliver ON scheduled_messages(deliver_at)`); err != nil {
+			return fmt.Errorf("migration 65→66: create idx_scheduled_messages_deliver: %w", err)
+		}
+	}
+
 	// Update schema version
 	_, err = tx.Exec("UPDATE schema_version SET version = ?", endVersion)
 	if err != nil {
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -1064,9 +1065,9 @@ func TestWorkContexts_ForeignKeyCascade(t *testing.T) {
 	}
 }
 
-func TestSchema_V51_CurrentVersion(t *testing.T) {
-	if schema.CurrentVersion != 51 {
-		t.Errorf("CurrentVersion = %d, want 51 (v40 read-state marker + v41–v51 dead-end DDL forward-port from thrum-agents per thrum-399av)", schema.CurrentVersion)
+func TestSchema_V66_CurrentVersion(t *testing.T) {
+	if schema.CurrentVersion != 66 {
+		t.Errorf("CurrentVersion = %d, want 66 (v40 read-state marker + v41–v51 dead-end DDL forward-port from thrum-agents per thrum-399av + v52 message_reactions + v53 messages_fts + v54 message_tags + v55 agent_aliases + v56 message_pins + v57 messages.expires_at + v58 agent_capabilities + v59 agent_mutes + v60 message_assignments + v61 pending_receipts + v62 messages.bumped_at + v63 send_idempotency + v64 messages.origin_sequence + v65 subscriptions.scope_prefix + v66 messages.deliver_at)", schema.CurrentVersion)
 	}
 	// The read-state crossing constant stays at the v40 marker version — the
 	// state.NewState gate compares the pre-migration version against it, and the
@@ -1077,6 +1078,48 @@ func TestSchema_V51_CurrentVersion(t *testing.T) {
 	}
 }
 
+// TestMessageChildTables fails when a table gains a message_id column
+// without being added to MessageChildTables (or to the exemptions below),
+// so hard deletes can't silently start leaving orphaned rows behind.
+func TestMessageChildTables(t *testing.T) {
+	db, err := schema.OpenDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("OpenDB() failed: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+	if err := schema.InitDB(db); err != nil {
+		t.Fatalf("InitDB() failed: %v", err)
+	}
+
+	// Tables keyed by message_id that are not per-message children.
+	notChildren := map[string]bool{
+		"messages":           true, // the parent itself
+		"permission_nudges":  true, // external permission prompt IDs
+		"email_msg_seen":     true, // email bridge dedup, keyed by email Message-ID
+		"pending_receipts":   true, // receipts for messages that have not arrived yet
+		"scheduled_messages": true, // daemon-local queue; the scheduler drops missing messages
+	}
+
+	rows, err := db.Query(`SELECT m.name FROM sqlite_master m, pragma_table_info(m.name) p
+		WHERE m.type = 'table' AND p.name = 'message_id'`)
+	if err != nil {
+		t.Fatalf("list tables: %v", err)
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			t.Fatalf("scan: %v", err)
+		}
+		if !notChildren[name] && !slices.Contains(schema.MessageChildTables, name) {
+			t.Errorf("table %s has a message_id column but is not in MessageChildTables", name)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("rows: %v", err)
+	}
+}
+
 // TestSchema_V36_AgentAPIErrorRemediation pins the thrum-sdzk v36 table on
 // BOTH paths (canonical-ref §3.11 Guard-1): fresh install (createTables) and
 // upgrade (the v36 migration block). The shared DDL const makes drift
@@ -2503,3 +2546,436 @@ func TestSchema_V38_to_V39_AddsMonitorSchedule(t *testing.T) {
 		t.Errorf("re-run Migrate at v39 must be a no-op: %v", err)
 	}
 }
+
+// TestMigration_V53BackfillsMessagesFTS verifies the v53 migration indexes
+// messages that predate messages_fts, so message.search covers history.
+func TestMigration_V53BackfillsMessagesFTS(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "v40_to_v53.db")
+	db, err := schema.OpenDB(dbPath)
+	if err != nil {
+		t.Fatalf("OpenDB: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	bootstrapV40(t, db) // seeds m_pre with body "hello"
+
+	if err := schema.Migrate(db); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+
+	var id string
+	if err := db.QueryRow(`SELECT message_id FROM messages_fts WHERE messages_fts MATCH 'hello'`).Scan(&id); err != nil {
+		t.Fatalf("query messages_fts: %v", err)
+	}
+	if id != "m_pre" {
+		t.Errorf("messages_fts hit = %q, want m_pre", id)
+	}
+}
+
+// TestMigration_V54CreatesMessageTags verifies the v54 migration adds the
+// message_tags table and its tag lookup index.
+func TestMigration_V54CreatesMessageTags(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "v40_to_v54.db")
+	db, err := schema.OpenDB(dbPath)
+	if err != nil {
+		t.Fatalf("OpenDB: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	bootstrapV40(t, db)
+
+	if err := schema.Migrate(db); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+
+	if _, err := db.Exec(`INSERT INTO message_tags (message_id, tag) VALUES ('m_pre', 'decision')`); err != nil {
+		t.Fatalf("insert message_tags: %v", err)
+	}
+	var n int
+	if err := db.QueryRow(
+		`SELECT COUNT(*) FROM sqlite_master WHERE type = 'index' AND name = 'idx_message_tags_tag'`,
+	).Scan(&n); err != nil {
+		t.Fatalf("index check: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("idx_message_tags_tag missing after migration")
+	}
+}
+
+// TestMigration_V55CreatesAgentAliases verifies the v55 migration adds the
+// agent_aliases table with alias as its primary key.
+func TestMigration_V55CreatesAgentAliases(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "v40_to_v55.db")
+	db, err := schema.OpenDB(dbPath)
+	if err != nil {
+		t.Fatalf("OpenDB: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	bootstrapV40(t, db)
+
+	if err := schema.Migrate(db); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+
+	insert := `INSERT INTO agent_aliases (alias, agent_id, created_at) VALUES ('rev', ?, '2026-01-01T00:00:00Z')`
+	if _, err := db.Exec(insert, "reviewer_01"); err != nil {
+		t.Fatalf("insert agent_aliases: %v", err)
+	}
+	if _, err := db.Exec(insert, "planner_01"); err == nil {
+		t.Error("duplicate alias accepted; alias must be unique")
+	}
+}
+
+// TestMigration_V56CreatesMessagePins verifies the v56 migration adds the
+// message_pins table keyed by message_id.
+func TestMigration_V56CreatesMessagePins(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "v40_to_v56.db")
+	db, err := schema.OpenDB(dbPath)
+	if err != nil {
+		t.Fatalf("OpenDB: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	bootstrapV40(t, db)
+
+	if err := schema.Migrate(db); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+
+	insert := `INSERT INTO message_pins (message_id, pinned_by, pinned_at) VALUES ('m_pre', ?, '2026-01-01T00:00:00Z')`
+	if _, err := db.Exec(insert, "reviewer_01"); err != nil {
+		t.Fatalf("insert message_pins: %v", err)
+	}
+	if _, err := db.Exec(insert, "planner_01"); err == nil {
+		t.Error("duplicate pin accepted; a message is pinned at most once")
+	}
+}
+
+// TestMigration_V57AddsMessageExpiry verifies the v57 migration adds a
+// nullable messages.expires_at column, leaving existing messages unexpiring.
+func TestMigration_V57AddsMessageExpiry(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "v40_to_v57.db")
+	db, err := schema.OpenDB(dbPath)
+	if err != nil {
+		t.Fatalf("OpenDB: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	bootstrapV40(t, db)
+
+	if err := schema.Migrate(db); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+
+	if _, err := db.Exec(`INSERT INTO messages (message_id, agent_id, session_id, created_at, body_format, body_content, expires_at)
+		VALUES ('m_ttl', 'a1', 's1', '2026-01-01T00:00:00Z', 'plain', 'building now', '2026-01-01T01:00:00Z')`); err != nil {
+		t.Fatalf("insert message with expires_at: %v", err)
+	}
+	var expiresAt sql.NullString
+	if err := db.QueryRow(`SELECT expires_at FROM messages WHERE message_id = 'm_ttl'`).Scan(&expiresAt); err != nil {
+		t.Fatalf("read expires_at: %v", err)
+	}
+	if expiresAt.String != "2026-01-01T01:00:00Z" {
+		t.Errorf("expires_at = %q, want 2026-01-01T01:00:00Z", expiresAt.String)
+	}
+}
+
+// TestMigration_V58CreatesAgentCapabilities verifies the v58 migration
+// creates agent_capabilities keyed by (agent_id, capability).
+func TestMigration_V58CreatesAgentCapabilities(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "v40_to_v58.db")
+	db, err := schema.OpenDB(dbPath)
+	if err != nil {
+		t.Fatalf("OpenDB: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	bootstrapV40(t, db)
+
+	if err := schema.Migrate(db); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+
+	insert := `INSERT INTO agent_capabilities (agent_id, capability, created_at) VALUES ('a1', ?, '2026-01-01T00:00:00Z')`
+	if _, err := db.Exec(insert, "go"); err != nil {
+		t.Fatalf("insert agent_capabilities: %v", err)
+	}
+	if _, err := db.Exec(insert, "security"); err != nil {
+		t.Fatalf("insert second capability: %v", err)
+	}
+	if _, err := db.Exec(insert, "go"); err == nil {
+		t.Error("duplicate (agent_id, capability) accepted")
+	}
+}
+
+// TestMigration_V59CreatesAgentMutes verifies the v59 migration creates
+// agent_mutes with one row per agent and a nullable muted_until.
+func TestMigration_V59CreatesAgentMutes(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "v40_to_v59.db")
+	db, err := schema.OpenDB(dbPath)
+	if err != nil {
+		t.Fatalf("OpenDB: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	bootstrapV40(t, db)
+
+	if err := schema.Migrate(db); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+
+	if _, err := db.Exec(`INSERT INTO agent_mutes (agent_id, muted_at) VALUES ('a1', '2026-01-01T00:00:00Z')`); err != nil {
+		t.Fatalf("insert indefinite mute: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO agent_mutes (agent_id, muted_until, allow_mentions, muted_at) VALUES ('a1', '2026-01-01T00:30:00Z', 1, '2026-01-01T00:00:00Z')`); err == nil {
+		t.Error("second mute row for the same agent accepted")
+	}
+}
+
+// TestMigration_V60CreatesMessageAssignments verifies the v60 migration
+// creates message_assignments with room for several rows per message.
+func TestMigration_V60CreatesMessageAssignments(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "v40_to_v60.db")
+	db, err := schema.OpenDB(dbPath)
+	if err != nil {
+		t.Fatalf("OpenDB: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	bootstrapV40(t, db)
+
+	if err := schema.Migrate(db); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+
+	if _, err := db.Exec(`INSERT INTO messages (message_id, agent_id, session_id, created_at, body_format, body_content)
+		VALUES ('m1', 'a2', 's1', '2026-01-01T00:00:00Z', 'markdown', 'do the thing')`); err != nil {
+		t.Fatalf("insert message: %v", err)
+	}
+	for _, id := range []string{"as1", "as2"} {
+		if _, err := db.Exec(`INSERT INTO message_assignments (assignment_id, message_id, assignee, assigned_by, assigned_at) VALUES (?, 'm1', 'a1', 'a2', '2026-01-01T00:00:00Z')`, id); err != nil {
+			t.Fatalf("insert assignment %s: %v", id, err)
+		}
+	}
+	var status string
+	if err := db.QueryRow(`SELECT status FROM message_assignments WHERE assignment_id = 'as1'`).Scan(&status); err != nil {
+		t.Fatalf("query status: %v", err)
+	}
+	if status != "open" {
+		t.Errorf("default status = %q, want open", status)
+	}
+}
+
+// TestMigration_V61CreatesPendingReceipts verifies the v61 migration creates
+// pending_receipts keyed by (message, agent, receipt type), with no foreign
+// key on the not-yet-projected message.
+func TestMigration_V61CreatesPendingReceipts(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "v40_to_v61.db")
+	db, err := schema.OpenDB(dbPath)
+	if err != nil {
+		t.Fatalf("OpenDB: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	bootstrapV40(t, db)
+
+	if err := schema.Migrate(db); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+
+	insert := `INSERT INTO pending_receipts (message_id, agent_id, receipt_type, timestamp) VALUES ('m_missing', 'a1', ?, '2026-01-01T00:00:00Z')`
+	for _, typ := range []string{"seen", "read"} {
+		if _, err := db.Exec(insert, typ); err != nil {
+			t.Fatalf("insert %s receipt: %v", typ, err)
+		}
+	}
+	if _, err := db.Exec(insert, "read"); err == nil {
+		t.Error("duplicate (message, agent, type) insert succeeded, want primary key conflict")
+	}
+}
+
+// TestMigration_V62AddsMessageBumpedAt verifies the v62 migration adds a
+// nullable messages.bumped_at column, leaving existing messages unbumped.
+func TestMigration_V62AddsMessageBumpedAt(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "v40_to_v62.db")
+	db, err := schema.OpenDB(dbPath)
+	if err != nil {
+		t.Fatalf("OpenDB: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	bootstrapV40(t, db)
+
+	if err := schema.Migrate(db); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+
+	if _, err := db.Exec(`INSERT INTO messages (message_id, agent_id, session_id, created_at, body_format, body_content)
+		VALUES ('m_plain', 'a1', 's1', '2026-01-01T00:00:00Z', 'plain', 'review please')`); err != nil {
+		t.Fatalf("insert message: %v", err)
+	}
+	var bumpedAt sql.NullString
+	if err := db.QueryRow(`SELECT bumped_at FROM messages WHERE message_id = 'm_plain'`).Scan(&bumpedAt); err != nil {
+		t.Fatalf("read bumped_at: %v", err)
+	}
+	if bumpedAt.Valid {
+		t.Errorf("bumped_at = %q, want NULL for a message never bumped", bumpedAt.String)
+	}
+}
+
+// TestMigration_V63CreatesSendIdempotency verifies the v63 migration creates
+// send_idempotency keyed by (agent, key), so the same key from two agents
+// does not collide.
+func TestMigration_V63CreatesSendIdempotency(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "v40_to_v63.db")
+	db, err := schema.OpenDB(dbPath)
+	if err != nil {
+		t.Fatalf("OpenDB: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	bootstrapV40(t, db)
+
+	if err := schema.Migrate(db); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+
+	insert := `INSERT INTO send_idempotency (agent_id, idempotency_key, request_hash, created_at) VALUES (?, 'k1', 'h', '2026-01-01T00:00:00Z')`
+	for _, agent := range []string{"a1", "a2"} {
+		if _, err := db.Exec(insert, agent); err != nil {
+			t.Fatalf("insert key for %s: %v", agent, err)
+		}
+	}
+	if _, err := db.Exec(insert, "a1"); err == nil {
+		t.Error("duplicate (agent, key) insert succeeded, want primary key conflict")
+	}
+}
+
+// TestMigration_V64BackfillsMessageSequence verifies the v64 migration adds
+// messages.origin_daemon/origin_sequence and fills them from the message's
+// message.create event, leaving messages whose event is gone with an empty
+// origin and sequence 0.
+func TestMigration_V64BackfillsMessageSequence(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "v40_to_v64.db")
+	db, err := schema.OpenDB(dbPath)
+	if err != nil {
+		t.Fatalf("OpenDB: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	bootstrapV40(t, db)
+	stmts := []string{
+		`CREATE TABLE events (event_id TEXT PRIMARY KEY, sequence INTEGER UNIQUE NOT NULL, type TEXT NOT NULL,
+			timestamp TEXT NOT NULL, origin_daemon TEXT NOT NULL, event_json TEXT NOT NULL)`,
+		`INSERT INTO messages (message_id, agent_id, session_id, created_at, body_format, body_content)
+			VALUES ('m_synced', 'a1', 's1', '2026-01-01T00:00:00Z', 'plain', 'from a peer'),
+			       ('m_compacted', 'a1', 's1', '2026-01-01T00:00:01Z', 'plain', 'event gone')`,
+		`INSERT INTO events (event_id, sequence, type, timestamp, origin_daemon, event_json)
+			VALUES ('evt_1', 7, 'message.create', '2026-01-01T00:00:00Z', 'd_peer',
+			        '{"type":"message.create","message_id":"m_synced","origin_daemon":"d_peer","sequence":42}')`,
+	}
+	for _, stmt := range stmts {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("seed v40 rows: %v", err)
+		}
+	}
+
+	if err := schema.Migrate(db); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+
+	for _, tc := range []struct {
+		id     string
+		origin string
+		seq    int64
+	}{
+		// The peer's own sequence, not the local events-table row number.
+		{"m_synced", "d_peer", 42},
+		{"m_compacted", "", 0},
+	} {
+		var origin string
+		var seq int64
+		if err := db.QueryRow(`SELECT origin_daemon, origin_sequence FROM messages WHERE message_id = ?`, tc.id).Scan(&origin, &seq); err != nil {
+			t.Fatalf("read %s: %v", tc.id, err)
+		}
+		if origin != tc.origin || seq != tc.seq {
+			t.Errorf("%s = (%q, %d), want (%q, %d)", tc.id, origin, seq, tc.origin, tc.seq)
+		}
+	}
+}
+
+// TestMigration_V65AddsSubscriptionScopePrefix verifies the v65 migration
+// adds subscriptions.scope_prefix and leaves existing subscriptions
+// exact-match.
+func TestMigration_V65AddsSubscriptionScopePrefix(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "v40_to_v65.db")
+	db, err := schema.OpenDB(dbPath)
+	if err != nil {
+		t.Fatalf("OpenDB: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	bootstrapV40(t, db)
+	stmts := []string{
+		`CREATE TABLE subscriptions (id INTEGER PRIMARY KEY AUTOINCREMENT, session_id TEXT NOT NULL,
+			scope_type TEXT, scope_value TEXT, mention_role TEXT, created_at TEXT NOT NULL,
+			UNIQUE(session_id, scope_type, scope_value, mention_role))`,
+		`INSERT INTO subscriptions (session_id, scope_type, scope_value, created_at)
+			VALUES ('ses_1', 'module', 'auth', '2026-01-01T00:00:00Z')`,
+	}
+	for _, stmt := range stmts {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("seed v40 rows: %v", err)
+		}
+	}
+
+	if err := schema.Migrate(db); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+
+	var prefix int
+	if err := db.QueryRow(`SELECT scope_prefix FROM subscriptions WHERE session_id = 'ses_1'`).Scan(&prefix); err != nil {
+		t.Fatalf("read scope_prefix: %v", err)
+	}
+	if prefix != 0 {
+		t.Errorf("scope_prefix = %d, want 0 for a pre-v65 subscription", prefix)
+	}
+}
+
+// TestMigration_V66AddsMessageDeliverAt verifies the v66 migration adds
+// messages.deliver_at, leaving existing messages delivered, and creates the
+// empty scheduled_messages queue.
+func TestMigration_V66AddsMessageDeliverAt(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "v40_to_v66.db")
+	db, err := schema.OpenDB(dbPath)
+	if err != nil {
+		t.Fatalf("OpenDB: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	bootstrapV40(t, db)
+	if _, err := db.Exec(`INSERT INTO messages (message_id, agent_id, session_id, created_at, body_format, body_content)
+		VALUES ('m_old', 'a1', 's1', '2026-01-01T00:00:00Z', 'plain', 'sent before v66')`); err != nil {
+		t.Fatalf("seed v40 rows: %v", err)
+	}
+
+	if err := schema.Migrate(db); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+
+	var deliverAt sql.NullString
+	if err := db.QueryRow(`SELECT deliver_at FROM messages WHERE message_id = 'm_old'`).Scan(&deliverAt); err != nil {
+		t.Fatalf("read deliver_at: %v", err)
+	}
+	if deliverAt.Valid {
+		t.Errorf("deliver_at = %q, want NULL for a pre-v66 message", deliverAt.String)
+	}
+	var queued int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM scheduled_messages`).Scan(&queued); err != nil {
+		t.Fatalf("count scheduled_messages: %v", err)
+	}
+	if queued != 0 {
+		t.Errorf("scheduled_messages has %d rows, want 0", queued)
+	}
+}
@@ -137,8 +137,8 @@ func TestForwardPortV51_FreshInit(t *testing.T) {
 	if err != nil {
 		t.Fatalf("GetSchemaVersion: %v", err)
 	}
-	if v != 51 {
-		t.Errorf("fresh DB version = %d, want 51", v)
+	if v != schema.CurrentVersion {
+		t.Errorf("fresh DB version = %d, want %d", v, schema.CurrentVersion)
 	}
 	assertV51Surface(t, db)
 }
@@ -215,8 +215,8 @@ func TestForwardPortV51_MigrateFromV40(t *testing.T) {
 	if err != nil {
 		t.Fatalf("GetSchemaVersion: %v", err)
 	}
-	if v != 51 {
-		t.Fatalf("post-migration version = %d, want 51", v)
+	if v != schema.CurrentVersion {
+		t.Fatalf("post-migration version = %d, want %d", v, schema.CurrentVersion)
 	}
 
 	// All new columns/tables present, index swap landed.
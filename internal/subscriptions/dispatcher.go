@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/leonletto/thrum/internal/daemon/safedb"
 	"github.com/leonletto/thrum/internal/identity"
@@ -54,16 +56,20 @@ type SubscriptionMatch struct {
 }
 
 // DispatchForMessage finds all subscriptions that match a message and pushes notifications.
+// Sessions of a muted agent are skipped unless the mute lets msg through.
 // Returns a list of sessions that were notified.
 func (d *Dispatcher) DispatchForMessage(ctx context.Context, msg *MessageInfo) ([]SubscriptionMatch, error) {
-	// Query all active subscriptions with agent info for mention matching
-	query := `SELECT s.id, s.session_id, s.scope_type, s.scope_value, s.mention_role,
-	                 a.agent_id, a.role
+	// Query all active subscriptions with agent info for mention matching,
+	// plus the agent's mute when one is still in effect.
+	query := `SELECT s.id, s.session_id, s.scope_type, s.scope_value, s.scope_prefix, s.mention_role,
+	                 a.agent_id, a.role, mu.agent_id, mu.allow_mentions
 	          FROM subscriptions s
 	          LEFT JOIN sessions sess ON s.session_id = sess.session_id
-	          LEFT JOIN agents a ON sess.agent_id = a.agent_id`
+	          LEFT JOIN agents a ON sess.agent_id = a.agent_id
+	          LEFT JOIN agent_mutes mu ON mu.agent_id = a.agent_id
+	               AND (mu.muted_until IS NULL OR mu.muted_until > ?)`
 
-	rows, err := d.db.QueryContext(ctx, query)
+	rows, err := d.db.QueryContext(ctx, query, time.Now().UTC().Format(time.RFC3339))
 	if err != nil {
 		return nil, fmt.Errorf("query subscriptions: %w", err)
 	}
@@ -75,15 +81,24 @@ func (d *Dispatcher) DispatchForMessage(ctx context.Context, msg *MessageInfo) (
 		var id int
 		var sessionID string
 		var scopeType, scopeValue, mentionRole sql.NullString
-		var agentID, agentRole sql.NullString
+		var scopePrefix bool
+		var agentID, agentRole, mutedAgent sql.NullString
+		var allowMentions sql.NullBool
 
-		err := rows.Scan(&id, &sessionID, &scopeType, &scopeValue, &mentionRole, &agentID, &agentRole)
+		err := rows.Scan(&id, &sessionID, &scopeType, &scopeValue, &scopePrefix, &mentionRole, &agentID, &agentRole, &mutedAgent, &allowMentions)
 		if err != nil {
 			return nil, fmt.Errorf("scan subscription: %w", err)
 		}
 
+		if mutedAgent.Valid {
+			mute := &Mute{AgentID: mutedAgent.String, AllowMentions: allowMentions.Bool}
+			if mute.Suppresses(msg) {
+				continue
+			}
+		}
+
 		// Check if this subscription matches the message
-		matchType := matchSubscription(msg, scopeType, scopeValue, mentionRole, agentID, agentRole)
+		matchType := matchSubscription(msg, scopeType, scopeValue, scopePrefix, mentionRole, agentID, agentRole)
 		if matchType != "" {
 			match := SubscriptionMatch{
 				SubscriptionID: id,
@@ -159,7 +174,8 @@ func (d *Dispatcher) buildNotification(msg *MessageInfo, match SubscriptionMatch
 // matchSubscription checks if a message matches a subscription.
 // Returns the match type ("scope", "mention", "all") or empty string if no match.
 // Supports both role-based mentions (@reviewer) and name-based mentions (@furiosa).
-func matchSubscription(msg *MessageInfo, scopeType, scopeValue, mentionRole, agentID, agentRole sql.NullString) string {
+// A scopePrefix subscription also matches scope values below its own.
+func matchSubscription(msg *MessageInfo, scopeType, scopeValue sql.NullString, scopePrefix bool, mentionRole, agentID, agentRole sql.NullString) string {
 	// All subscription (all fields NULL) - always matches
 	if !scopeType.Valid && !scopeValue.Valid && !mentionRole.Valid {
 		return "all"
@@ -168,7 +184,10 @@ func matchSubscription(msg *MessageInfo, scopeType, scopeValue, mentionRole, age
 	// Scope subscription - check if message has matching scope
 	if scopeType.Valid && scopeValue.Valid {
 		for _, scope := range msg.Scopes {
-			if scope.Type == scopeType.String && scope.Value == scopeValue.String {
+			if scope.Type != scopeType.String {
+				continue
+			}
+			if scope.Value == scopeValue.String || (scopePrefix && underScope(scope.Value, scopeValue.String)) {
 				return "scope"
 			}
 		}
@@ -198,6 +217,13 @@ func matchSubscription(msg *MessageInfo, scopeType, scopeValue, mentionRole, age
 	return ""
 }
 
+// underScope reports whether value lies below prefix in the scope path
+// hierarchy. Matching stops at a "/" segment boundary, so auth covers
+// auth/login but not authz.
+func underScope(value, prefix string) bool {
+	return strings.HasPrefix(value, prefix+"/")
+}
+
 // ThreadUpdateInfo represents the information for a thread update notification.
 type ThreadUpdateInfo struct {
 	ThreadID     string
@@ -217,14 +243,6 @@ func (d *Dispatcher) DispatchThreadUpdated(ctx context.Context, info *ThreadUpda
 		return nil
 	}
 
-	// Query all active subscriptions
-	query := `SELECT DISTINCT session_id FROM subscriptions`
-	rows, err := d.db.QueryContext(ctx, query)
-	if err != nil {
-		return fmt.Errorf("query subscriptions: %w", err)
-	}
-	defer func() { _ = rows.Close() }()
-
 	notification := map[string]any{
 		"method": "notification.thread.updated",
 		"params": map[string]any{
@@ -238,7 +256,18 @@ func (d *Dispatcher) DispatchThreadUpdated(ctx context.Context, info *ThreadUpda
 		},
 	}
 
-	// Send to all subscribed sessions
+	return d.notifySubscribed(ctx, notification)
+}
+
+// notifySubscribed sends notification to every session with at least one
+// subscription, best-effort.
+func (d *Dispatcher) notifySubscribed(ctx context.Context, notification any) error {
+	rows, err := d.db.QueryContext(ctx, `SELECT DISTINCT session_id FROM subscriptions`)
+	if err != nil {
+		return fmt.Errorf("query subscriptions: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
 	for rows.Next() {
 		var sessionID string
 		if err := rows.Scan(&sessionID); err != nil {
@@ -255,3 +284,42 @@ func (d *Dispatcher) DispatchThreadUpdated(ctx context.Context, info *ThreadUpda
 
 	return nil
 }
+
+// ContextUpdateInfo identifies a session whose work context changed.
+type ContextUpdateInfo struct {
+	AgentID   string
+	SessionID string
+	Timestamp string
+}
+
+// allNotifier is implemented by client notifiers that can reach every
+// connected client, not just sessions with a subscription row.
+type allNotifier interface {
+	BroadcastAll(notification any)
+}
+
+// DispatchContextUpdated sends a notification.context.updated after a
+// session's work context changes (heartbeat, intent, task). Watchers such as
+// `thrum agent context --watch` connect without subscribing, so it goes to
+// every connected client when the notifier supports that, and to subscribed
+// sessions otherwise. This is a real-time notification (not persisted to JSONL).
+func (d *Dispatcher) DispatchContextUpdated(ctx context.Context, info *ContextUpdateInfo) error {
+	if d.clients == nil {
+		return nil
+	}
+
+	notification := map[string]any{
+		"method": "notification.context.updated",
+		"params": map[string]any{
+			"agent_id":   info.AgentID,
+			"session_id": info.SessionID,
+			"timestamp":  info.Timestamp,
+		},
+	}
+
+	if all, ok := d.clients.(allNotifier); ok {
+		all.BroadcastAll(notification)
+		return nil
+	}
+	return d.notifySubscribed(ctx, notification)
+}
@@ -3,6 +3,7 @@ package subscriptions_test
 import (
 	"context"
 	"path/filepath"
+	"slices"
 	"sync"
 	"testing"
 
@@ -336,6 +337,73 @@ func TestDispatchForMessage_MultipleScopes(t *testing.T) {
 	}
 }
 
+func TestDispatchForMessage_ScopePrefix(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "test.db")
+
+	db, err := schema.OpenDB(dbPath)
+	if err != nil {
+		t.Fatalf("OpenDB() failed: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	if err := schema.InitDB(db); err != nil {
+		t.Fatalf("InitDB() failed: %v", err)
+	}
+
+	sdb := safedb.New(db)
+	svc := subscriptions.NewService(sdb)
+	dispatcher := subscriptions.NewDispatcher(sdb)
+
+	// ses_prefix gets module:auth and everything below it; ses_exact only
+	// module:auth itself.
+	if _, err := svc.SubscribeScopePrefix(context.Background(), "ses_prefix", types.Scope{Type: "module", Value: "auth/"}, nil); err != nil {
+		t.Fatalf("SubscribeScopePrefix() failed: %v", err)
+	}
+	if _, err := svc.Subscribe(context.Background(), "ses_exact", &types.Scope{Type: "module", Value: "auth"}, nil, false); err != nil {
+		t.Fatalf("Subscribe() failed: %v", err)
+	}
+
+	for _, tc := range []struct {
+		value string
+		want  []string
+	}{
+		{"auth", []string{"ses_prefix", "ses_exact"}},
+		{"auth/login", []string{"ses_prefix"}},
+		{"auth/login/oauth", []string{"ses_prefix"}},
+		{"authz", nil}, // not a path segment below auth
+	} {
+		matches, err := dispatcher.DispatchForMessage(context.Background(), &subscriptions.MessageInfo{
+			MessageID: "msg_" + tc.value,
+			Scopes:    []types.Scope{{Type: "module", Value: tc.value}},
+		})
+		if err != nil {
+			t.Fatalf("DispatchForMessage(%s) failed: %v", tc.value, err)
+		}
+		var got []string
+		for _, m := range matches {
+			got = append(got, m.SessionID)
+		}
+		if len(got) != len(tc.want) {
+			t.Errorf("module:%s matched %v, want %v", tc.value, got, tc.want)
+			continue
+		}
+		for _, want := range tc.want {
+			if !slices.Contains(got, want) {
+				t.Errorf("module:%s matched %v, want %v", tc.value, got, tc.want)
+			}
+		}
+	}
+
+	// A prefix subscription is distinct from the exact one on the same scope.
+	if _, err := svc.Subscribe(context.Background(), "ses_prefix", &types.Scope{Type: "module", Value: "auth"}, nil, false); err != nil {
+		t.Errorf("exact Subscribe() beside a prefix one failed: %v", err)
+	}
+	if _, err := svc.SubscribeScopePrefix(context.Background(), "ses_prefix", types.Scope{Type: "module", Value: "auth"}, nil); err == nil {
+		t.Error("duplicate SubscribeScopePrefix() succeeded, want already exists")
+	}
+}
+
 func TestDispatchForMessage_NoSubscriptions(t *testing.T) {
 	tmpDir := t.TempDir()
 	dbPath := filepath.Join(tmpDir, "test.db")
@@ -594,3 +662,144 @@ func TestDispatcher_NotificationWithoutClientNotifier(t *testing.T) {
 		t.Errorf("Expected 1 match, got %d", len(matches))
 	}
 }
+
+func TestDispatchForMessage_MutedAgent(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "test.db")
+
+	db, err := schema.OpenDB(dbPath)
+	if err != nil {
+		t.Fatalf("OpenDB() failed: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	if err := schema.InitDB(db); err != nil {
+		t.Fatalf("InitDB() failed: %v", err)
+	}
+
+	for _, stmt := range []string{
+		`INSERT INTO agents (agent_id, kind, role, module, registered_at) VALUES ('alice', 'named', 'implementer', 'test', '2026-01-01T00:00:00Z')`,
+		`INSERT INTO sessions (session_id, agent_id, started_at, last_seen_at) VALUES ('ses_alice', 'alice', '2026-01-01T00:00:00Z', '2026-01-01T00:00:00Z')`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+
+	sdb := safedb.New(db)
+	svc := subscriptions.NewService(sdb)
+	dispatcher := subscriptions.NewDispatcher(sdb)
+	notifier := newMockNotifier()
+	dispatcher.SetClientNotifier(notifier)
+
+	if _, err := svc.Subscribe(context.Background(), "ses_alice", nil, nil, true); err != nil {
+		t.Fatalf("Subscribe() failed: %v", err)
+	}
+
+	setMute := func(until string, allowMentions bool) {
+		t.Helper()
+		var untilArg any
+		if until != "" {
+			untilArg = until
+		}
+		if _, err := db.Exec(`INSERT OR REPLACE INTO agent_mutes (agent_id, muted_until, allow_mentions, muted_at) VALUES ('alice', ?, ?, '2026-01-01T00:00:00Z')`, untilArg, allowMentions); err != nil {
+			t.Fatalf("set mute: %v", err)
+		}
+	}
+	dispatch := func(refs ...types.Ref) int {
+		t.Helper()
+		matches, err := dispatcher.DispatchForMessage(context.Background(), &subscriptions.MessageInfo{MessageID: "msg_001", Refs: refs})
+		if err != nil {
+			t.Fatalf("DispatchForMessage() failed: %v", err)
+		}
+		return len(matches)
+	}
+	direct := types.Ref{Type: "mention", Value: "alice"}
+	role := types.Ref{Type: "mention", Value: "implementer"}
+
+	setMute("", false)
+	if n := dispatch(direct); n != 0 {
+		t.Errorf("muted agent got %d pushes, want 0", n)
+	}
+
+	setMute("", true)
+	if n := dispatch(direct); n != 1 {
+		t.Errorf("direct mention with --allow-mentions got %d pushes, want 1", n)
+	}
+	if n := dispatch(role); n != 0 {
+		t.Errorf("role mention with --allow-mentions got %d pushes, want 0", n)
+	}
+
+	// An expired mute no longer applies.
+	setMute("2000-01-01T00:00:00Z", false)
+	if n := dispatch(); n != 1 {
+		t.Errorf("expired mute got %d pushes, want 1", n)
+	}
+	if got := len(notifier.GetNotifications("ses_alice")); got != 2 {
+		t.Errorf("notifications sent = %d, want 2", got)
+	}
+}
+
+// broadcastNotifier is a mockNotifier that can also reach every client.
+type broadcastNotifier struct {
+	*mockNotifier
+	broadcasts []any
+}
+
+func (b *broadcastNotifier) BroadcastAll(notification any) {
+	b.broadcasts = append(b.broadcasts, notification)
+}
+
+func TestDispatchContextUpdated(t *testing.T) {
+	tmpDir := t.TempDir()
+	db, err := schema.OpenDB(filepath.Join(tmpDir, "test.db"))
+	if err != nil {
+		t.Fatalf("OpenDB() failed: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+	if err := schema.InitDB(db); err != nil {
+		t.Fatalf("InitDB() failed: %v", err)
+	}
+
+	sdb := safedb.New(db)
+	if _, err := subscriptions.NewService(sdb).Subscribe(context.Background(), "ses_sub", nil, nil, true); err != nil {
+		t.Fatalf("Subscribe() failed: %v", err)
+	}
+	info := &subscriptions.ContextUpdateInfo{AgentID: "furiosa", SessionID: "ses_001", Timestamp: "2026-01-01T12:00:00Z"}
+
+	t.Run("broadcasts_when_supported", func(t *testing.T) {
+		notifier := &broadcastNotifier{mockNotifier: newMockNotifier()}
+		dispatcher := subscriptions.NewDispatcher(sdb)
+		dispatcher.SetClientNotifier(notifier)
+
+		if err := dispatcher.DispatchContextUpdated(context.Background(), info); err != nil {
+			t.Fatalf("DispatchContextUpdated() failed: %v", err)
+		}
+		if len(notifier.broadcasts) != 1 {
+			t.Fatalf("Expected 1 broadcast, got %d", len(notifier.broadcasts))
+		}
+		notif := notifier.broadcasts[0].(map[string]any)
+		if notif["method"] != "notification.context.updated" {
+			t.Errorf("Expected method='notification.context.updated', got %v", notif["method"])
+		}
+		if params := notif["params"].(map[string]any); params["agent_id"] != "furiosa" || params["session_id"] != "ses_001" {
+			t.Errorf("Unexpected params: %v", params)
+		}
+		if len(notifier.GetNotifications("ses_sub")) != 0 {
+			t.Error("Expected no per-session notification when broadcasting")
+		}
+	})
+
+	t.Run("falls_back_to_subscribed_sessions", func(t *testing.T) {
+		notifier := newMockNotifier()
+		dispatcher := subscriptions.NewDispatcher(sdb)
+		dispatcher.SetClientNotifier(notifier)
+
+		if err := dispatcher.DispatchContextUpdated(context.Background(), info); err != nil {
+			t.Fatalf("DispatchContextUpdated() failed: %v", err)
+		}
+		if len(notifier.GetNotifications("ses_sub")) != 1 {
+			t.Errorf("Expected 1 notification for ses_sub, got %d", len(notifier.GetNotifications("ses_sub")))
+		}
+	})
+}
@@ -0,0 +1,53 @@
+package subscriptions
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/leonletto/thrum/internal/daemon/safedb"
+)
+
+// Mute is a notification mute in effect for an agent (agent mute). Messages
+// still reach the agent's inbox; only pushes are held back.
+type Mute struct {
+	AgentID       string
+	Until         string // RFC3339 UTC; empty means until unmuted
+	AllowMentions bool   // direct @mentions of the agent still get through
+}
+
+// ActiveMute returns agentID's mute if it is in effect at now, or nil when
+// the agent is not muted or its mute has expired.
+func ActiveMute(ctx context.Context, db *safedb.DB, agentID string, now time.Time) (*Mute, error) {
+	var until sql.NullString
+	var allowMentions bool
+	err := db.QueryRowContext(ctx,
+		`SELECT muted_until, allow_mentions FROM agent_mutes
+		 WHERE agent_id = ? AND (muted_until IS NULL OR muted_until > ?)`,
+		agentID, now.UTC().Format(time.RFC3339),
+	).Scan(&until, &allowMentions)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query agent mute: %w", err)
+	}
+	return &Mute{AgentID: agentID, Until: until.String, AllowMentions: allowMentions}, nil
+}
+
+// Suppresses reports whether the mute holds back a push for msg. Everything
+// is held back unless mentions are allowed and msg mentions the agent by
+// name; role, group, and @everyone mentions don't count as direct.
+func (m *Mute) Suppresses(msg *MessageInfo) bool {
+	if !m.AllowMentions {
+		return true
+	}
+	for _, ref := range msg.Refs {
+		if ref.Type == "mention" && ref.Value == m.AgentID {
+			return false
+		}
+	}
+	return true
+}
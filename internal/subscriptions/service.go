@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/leonletto/thrum/internal/daemon/safedb"
@@ -26,6 +27,7 @@ type Subscription struct {
 	SessionID   string
 	ScopeType   *string // nil = wildcard
 	ScopeValue  *string
+	ScopePrefix bool // Scope also matches values below ScopeValue (module:auth → module:auth/login)
 	MentionRole *string
 	CreatedAt   string
 }
@@ -37,6 +39,22 @@ func (s *Service) Subscribe(ctx context.Context, sessionID string, scope *types.
 	if scope == nil && mentionRole == nil && !all {
 		return nil, fmt.Errorf("at least one of scope, mention_role, or all must be specified")
 	}
+	return s.subscribe(ctx, sessionID, scope, mentionRole, all, false)
+}
+
+// SubscribeScopePrefix creates a subscription that matches scope and every
+// scope below it in the path hierarchy: module:auth matches module:auth and
+// module:auth/login, but not module:authz. mentionRole is optional, as
+// with Subscribe.
+func (s *Service) SubscribeScopePrefix(ctx context.Context, sessionID string, scope types.Scope, mentionRole *string) (*Subscription, error) {
+	scope.Value = strings.TrimSuffix(scope.Value, "/")
+	if scope.Type == "" || scope.Value == "" {
+		return nil, fmt.Errorf("scope prefix needs a type and a value")
+	}
+	return s.subscribe(ctx, sessionID, &scope, mentionRole, false, true)
+}
+
+func (s *Service) subscribe(ctx context.Context, sessionID string, scope *types.Scope, mentionRole *string, all, scopePrefix bool) (*Subscription, error) {
 
 	// Prepare scope fields
 	var scopeType, scopeValue *string
@@ -55,7 +73,7 @@ func (s *Service) Subscribe(ctx context.Context, sessionID string, scope *types.
 	now := time.Now().UTC().Format(time.RFC3339Nano)
 
 	// Check for existing subscription (SQLite UNIQUE constraint doesn't handle NULLs correctly)
-	exists, err := s.subscriptionExists(ctx, sessionID, scopeType, scopeValue, mentionRole)
+	exists, err := s.subscriptionExists(ctx, sessionID, scopeType, scopeValue, mentionRole, scopePrefix)
 	if err != nil {
 		return nil, fmt.Errorf("check subscription exists: %w", err)
 	}
@@ -64,10 +82,10 @@ func (s *Service) Subscribe(ctx context.Context, sessionID string, scope *types.
 	}
 
 	// Insert subscription
-	query := `INSERT INTO subscriptions (session_id, scope_type, scope_value, mention_role, created_at)
-	          VALUES (?, ?, ?, ?, ?)`
+	query := `INSERT INTO subscriptions (session_id, scope_type, scope_value, mention_role, created_at, scope_prefix)
+	          VALUES (?, ?, ?, ?, ?, ?)`
 
-	result, err := s.db.ExecContext(ctx, query, sessionID, scopeType, scopeValue, mentionRole, now)
+	result, err := s.db.ExecContext(ctx, query, sessionID, scopeType, scopeValue, mentionRole, now, scopePrefix)
 	if err != nil {
 		return nil, fmt.Errorf("insert subscription: %w", err)
 	}
@@ -82,6 +100,7 @@ func (s *Service) Subscribe(ctx context.Context, sessionID string, scope *types.
 		SessionID:   sessionID,
 		ScopeType:   scopeType,
 		ScopeValue:  scopeValue,
+		ScopePrefix: scopePrefix,
 		MentionRole: mentionRole,
 		CreatedAt:   now,
 	}, nil
@@ -122,7 +141,7 @@ func (s *Service) ClearBySession(ctx context.Context, sessionID string) (int, er
 
 // List returns all subscriptions for the given session.
 func (s *Service) List(ctx context.Context, sessionID string) ([]Subscription, error) {
-	query := `SELECT id, session_id, scope_type, scope_value, mention_role, created_at
+	query := `SELECT id, session_id, scope_type, scope_value, mention_role, created_at, scope_prefix
 	          FROM subscriptions
 	          WHERE session_id = ?
 	          ORDER BY created_at DESC`
@@ -138,7 +157,7 @@ func (s *Service) List(ctx context.Context, sessionID string) ([]Subscription, e
 		var sub Subscription
 		var scopeType, scopeValue, mentionRole sql.NullString
 
-		err := rows.Scan(&sub.ID, &sub.SessionID, &scopeType, &scopeValue, &mentionRole, &sub.CreatedAt)
+		err := rows.Scan(&sub.ID, &sub.SessionID, &scopeType, &scopeValue, &mentionRole, &sub.CreatedAt, &sub.ScopePrefix)
 		if err != nil {
 			return nil, fmt.Errorf("scan subscription: %w", err)
 		}
@@ -165,7 +184,8 @@ func (s *Service) List(ctx context.Context, sessionID string) ([]Subscription, e
 
 // subscriptionExists checks if a subscription with the exact same parameters already exists.
 // This is needed because SQLite's UNIQUE constraint doesn't treat NULL values as equal.
-func (s *Service) subscriptionExists(ctx context.Context, sessionID string, scopeType, scopeValue, mentionRole *string) (bool, error) {
+// An exact and a prefix subscription on the same scope are different subscriptions.
+func (s *Service) subscriptionExists(ctx context.Context, sessionID string, scopeType, scopeValue, mentionRole *string, scopePrefix bool) (bool, error) {
 	var query string
 	var args []any
 
@@ -188,8 +208,9 @@ func (s *Service) subscriptionExists(ctx context.Context, sessionID string, scop
 			  AND scope_type = ?
 			  AND scope_value = ?
 			  AND mention_role IS NULL
+			  AND scope_prefix = ?
 		)`
-		args = []any{sessionID, *scopeType, *scopeValue}
+		args = []any{sessionID, *scopeType, *scopeValue, scopePrefix}
 	} else if scopeType == nil && scopeValue == nil && mentionRole != nil {
 		// Mention-only subscription
 		query = `SELECT EXISTS(
@@ -208,8 +229,9 @@ func (s *Service) subscriptionExists(ctx context.Context, sessionID string, scop
 			  AND scope_type = ?
 			  AND scope_value = ?
 			  AND mention_role = ?
+			  AND scope_prefix = ?
 		)`
-		args = []any{sessionID, *scopeType, *scopeValue, *mentionRole}
+		args = []any{sessionID, *scopeType, *scopeValue, *mentionRole, scopePrefix}
 	} else {
 		// Invalid combination (e.g., only scope_type without scope_value)
 		return false, fmt.Errorf("invalid subscription parameter combination")
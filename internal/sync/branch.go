@@ -281,6 +281,7 @@ func (b *BranchManager) CreateSyncWorktree(ctx context.Context, syncDir string)
 //     messages/, messages.jsonl)
 //   - v0.10.6 wire-stream paths introduced by thrum-s6os (state/,
 //     messages-v2/, receipts/)
+//   - attachments/, the files added with send --attach
 func (b *BranchManager) configureSparseCheckout(ctx context.Context, syncDir string) error {
 	// Initialize sparse checkout (non-cone mode for pattern matching)
 	if _, err := safecmd.Git(ctx, syncDir, "sparse-checkout", "init", "--no-cone"); err != nil {
@@ -296,6 +297,7 @@ func (b *BranchManager) configureSparseCheckout(ctx context.Context, syncDir str
 		"/state/",
 		"/messages-v2/",
 		"/receipts/",
+		"/attachments/",
 	); err != nil {
 		return fmt.Errorf("sparse-checkout set: %w", err)
 	}
@@ -352,6 +354,11 @@ func (b *BranchManager) isHealthyWorktree(ctx context.Context, syncDir string) b
 	if !strings.Contains(sparse, "state/") || !strings.Contains(sparse, "messages-v2/") || !strings.Contains(sparse, "receipts/") {
 		return false
 	}
+	// Same for attachments/: outside the sparse set, git add skips the
+	// files send --attach writes and checkout never restores peers' ones.
+	if !strings.Contains(sparse, "attachments/") {
+		return false
+	}
 
 	return true
 }
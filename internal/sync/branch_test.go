@@ -563,9 +563,9 @@ func TestBranchManager_CreateSyncWorktree_SparseCheckout(t *testing.T) {
 	// thrum-s6os E10: the v0.10.6 wire-stream skeleton must be in the
 	// sparse pattern set, otherwise the new state-not-events flow won't
 	// see writes to state/, messages-v2/, or receipts/.
-	for _, pat := range []string{"state/", "messages-v2/", "receipts/"} {
+	for _, pat := range []string{"state/", "messages-v2/", "receipts/", "attachments/"} {
 		if !strings.Contains(content, pat) {
-			t.Errorf("sparse-checkout missing v0.10.6 pattern %q, got:\n%s", pat, content)
+			t.Errorf("sparse-checkout missing pattern %q, got:\n%s", pat, content)
 		}
 	}
 }
@@ -9,6 +9,8 @@
 //   - CompactReceiptStateFile: dedup receipts/<agentID>.jsonl by
 //     (message_id, agent_id) when file exceeds sizeThresholdBytes.
 //   - CompactAll: orchestrates all three, idempotent, safe to call repeatedly.
+//   - GC: CompactAll plus dropping events superseded by deletes, for
+//     `thrum daemon gc` (gc.go).
 //
 // Anti-patterns enforced in this package:
 //   - All SQL routed through safedb (no raw db.Exec/db.Query).
@@ -199,23 +201,15 @@ func (c *Compactor) CompactAll(ctx context.Context, db *safedb.DB) error {
 	// box). The daemon-startup pass runs uncontended, so it never skips. Skip is
 	// preferred over a blocking mutex precisely because trailing compactions add
 	// no value: the next trigger compacts current state anyway.
-	c.gateMu.Lock()
-	if c.inFlight {
-		c.gateMu.Unlock()
+	if !c.acquire() {
 		slog.Debug("compaction.skipped_inflight")
 		return nil
 	}
-	c.inFlight = true
-	c.gateMu.Unlock()
 	// Panic-safe clear (mirrors internal/daemon pullGate): CompactAll does
 	// SQLite + file I/O, so a panic must NOT leave inFlight latched — that would
 	// wedge compaction for the daemon's lifetime (every subsequent call skipped
 	// forever). defer clears it on both normal return and panic unwind.
-	defer func() {
-		c.gateMu.Lock()
-		c.inFlight = false
-		c.gateMu.Unlock()
-	}()
+	defer c.release()
 
 	// thrum-bpq5 substrate: per-phase compactor timing.
 	// Gated by THRUM_PROFILE; zero cost when off.
@@ -248,6 +242,25 @@ func (c *Compactor) CompactAll(ctx context.Context, db *safedb.DB) error {
 	return nil
 }
 
+// acquire claims the single-flight gate. It returns false when another
+// compaction (CompactAll or GC) already holds it.
+func (c *Compactor) acquire() bool {
+	c.gateMu.Lock()
+	defer c.gateMu.Unlock()
+	if c.inFlight {
+		return false
+	}
+	c.inFlight = true
+	return true
+}
+
+// release clears the single-flight gate taken by acquire.
+func (c *Compactor) release() {
+	c.gateMu.Lock()
+	c.inFlight = false
+	c.gateMu.Unlock()
+}
+
 // compactDir enumerates *.jsonl files in dir and calls compact(ctx, stem) for
 // each, where stem is the base filename without the .jsonl extension.
 // Missing directories are silently skipped (idempotent).
@@ -0,0 +1,204 @@
+package compact
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/leonletto/thrum/internal/daemon/safedb"
+	"github.com/leonletto/thrum/internal/jsonl"
+)
+
+// ErrInFlight is returned by GC when another compaction holds the
+// single-flight gate.
+var ErrInFlight = errors.New("compaction already running")
+
+// GCStats reports what GC removed.
+type GCStats struct {
+	EventsDropped  int // rows removed from the SQLite events table
+	JournalDropped int // lines removed from .thrum/events.jsonl
+}
+
+// GC is the heavy pass behind `thrum daemon gc`. On top of what CompactAll
+// does it drops events superseded by later deletes (see superseded) from the
+// events table and the journal, and dedups every
+// messages-v2/receipts file regardless of sizeThresholdBytes.
+//
+// GC shares CompactAll's single-flight gate but returns ErrInFlight rather
+// than skipping silently, so the caller can report it. The caller is
+// responsible for checking that peers have pulled the events being dropped.
+func (c *Compactor) GC(ctx context.Context, db *safedb.DB) (GCStats, error) {
+	var stats GCStats
+	if !c.acquire() {
+		return stats, ErrInFlight
+	}
+	defer c.release()
+
+	var err error
+	if stats.EventsDropped, err = dropSupersededEvents(ctx, db); err != nil {
+		return stats, fmt.Errorf("GC events table: %w", err)
+	}
+	journalPath := filepath.Join(c.thrumDir, "events.jsonl")
+	if stats.JournalDropped, err = dropSupersededJournal(journalPath); err != nil {
+		return stats, fmt.Errorf("GC events journal: %w", err)
+	}
+	if stats.EventsDropped > 0 || stats.JournalDropped > 0 {
+		slog.Info("compaction.gc_superseded",
+			"events_dropped", stats.EventsDropped,
+			"journal_dropped", stats.JournalDropped)
+	}
+
+	if _, err := c.CompactEventsJournal(ctx, db); err != nil {
+		return stats, fmt.Errorf("GC events journal: %w", err)
+	}
+	full := New(c.thrumDir, c.syncDir, c.retentionDays, 0)
+	if err := full.compactDir(ctx, filepath.Join(c.syncDir, "messages-v2"), full.CompactMessageStateFile); err != nil {
+		return stats, fmt.Errorf("GC messages-v2: %w", err)
+	}
+	if err := full.compactDir(ctx, filepath.Join(c.syncDir, "receipts"), full.CompactReceiptStateFile); err != nil {
+		return stats, fmt.Errorf("GC receipts: %w", err)
+	}
+	return stats, nil
+}
+
+// eventRef is the part of an event superseded needs.
+type eventRef struct {
+	EventID   string `json:"event_id"`
+	Type      string `json:"type"`
+	MessageID string `json:"message_id"`
+}
+
+// deletedRedundantTypes are the message events a later message.delete makes
+// redundant: the delete unpins the message, unread counts skip tombstones,
+// and a bump only resurfaces live messages. Every other event still shapes
+// the tombstone row (create and edit its body and message history, move its
+// thread, react, assign and complete its side tables), so a rebuild from the
+// log must replay them for the delete to have a row to apply to.
+var deletedRedundantTypes = map[string]bool{
+	"message.pin":     true,
+	"message.receipt": true,
+	"message.bump":    true,
+}
+
+// superseded returns the IDs of events made redundant by later ones: the
+// deletedRedundantTypes events of every deleted message. Edits are all kept,
+// since each one is a version in message history.
+func superseded(events []eventRef) map[string]bool {
+	deleted := make(map[string]bool)
+	for _, ev := range events {
+		if ev.Type == "message.delete" {
+			deleted[ev.MessageID] = true
+		}
+	}
+
+	drop := make(map[string]bool)
+	for _, ev := range events {
+		if ev.EventID == "" || ev.MessageID == "" {
+			continue
+		}
+		if deleted[ev.MessageID] && deletedRedundantTypes[ev.Type] {
+			drop[ev.EventID] = true
+		}
+	}
+	return drop
+}
+
+// dropSupersededEvents deletes superseded rows from the events table. The
+// row holding MAX(sequence) always stays: the daemon seeds its sequence
+// counter from it, and reusing a sequence would hide new events from peers.
+func dropSupersededEvents(ctx context.Context, db *safedb.DB) (int, error) {
+	var maxSeq int64
+	if err := db.QueryRowContext(ctx,
+		`SELECT COALESCE(MAX(sequence), 0) FROM events`,
+	).Scan(&maxSeq); err != nil {
+		return 0, fmt.Errorf("load max sequence: %w", err)
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT event_id, sequence, event_json FROM events
+		WHERE type LIKE 'message.%'
+		ORDER BY sequence`)
+	if err != nil {
+		return 0, fmt.Errorf("query events: %w", err)
+	}
+	var events []eventRef
+	keep := ""
+	for rows.Next() {
+		var id, raw string
+		var seq int64
+		if err := rows.Scan(&id, &seq, &raw); err != nil {
+			_ = rows.Close()
+			return 0, fmt.Errorf("scan event: %w", err)
+		}
+		var ev eventRef
+		if json.Unmarshal([]byte(raw), &ev) != nil {
+			continue
+		}
+		ev.EventID = id
+		events = append(events, ev)
+		if seq == maxSeq {
+			keep = id
+		}
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("iterate events: %w", err)
+	}
+
+	drop := superseded(events)
+	delete(drop, keep)
+	if len(drop) == 0 {
+		return 0, nil
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	for id := range drop {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM events WHERE event_id = ?`, id); err != nil {
+			return 0, fmt.Errorf("delete event %s: %w", id, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit: %w", err)
+	}
+	return len(drop), nil
+}
+
+// dropSupersededJournal rewrites events.jsonl without superseded events.
+// A missing journal is not an error.
+func dropSupersededJournal(path string) (int, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return 0, nil
+	}
+	reader, err := jsonl.NewReader(path)
+	if err != nil {
+		return 0, err
+	}
+	lines, err := reader.ReadAll()
+	if err != nil {
+		return 0, err
+	}
+	events := make([]eventRef, 0, len(lines))
+	for _, line := range lines {
+		var ev eventRef
+		if json.Unmarshal(line, &ev) == nil {
+			events = append(events, ev)
+		}
+	}
+	drop := superseded(events)
+	if len(drop) == 0 {
+		return 0, nil
+	}
+
+	return jsonl.RemoveWhere(path, func(obj map[string]json.RawMessage) bool {
+		var id string
+		return json.Unmarshal(obj["event_id"], &id) == nil && drop[id]
+	})
+}
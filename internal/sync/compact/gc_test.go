@@ -0,0 +1,279 @@
+package compact_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/leonletto/thrum/internal/daemon/safedb"
+	"github.com/leonletto/thrum/internal/projection"
+	"github.com/leonletto/thrum/internal/schema"
+	"github.com/leonletto/thrum/internal/sync/compact"
+	"github.com/leonletto/thrum/internal/types"
+)
+
+// gcEvent is one event seeded into both the events table and the journal.
+type gcEvent struct {
+	id, typ, messageID string
+	seq                int64
+}
+
+// seedGC writes events to a sequence-aware events table and to
+// thrumDir/events.jsonl, mirroring what state.WriteEvent produces.
+func seedGC(t *testing.T, thrumDir string, events []gcEvent) *safedb.DB {
+	t.Helper()
+	payloads := make([]any, len(events))
+	for i, ev := range events {
+		payloads[i] = map[string]any{
+			"event_id":   ev.id,
+			"type":       ev.typ,
+			"message_id": ev.messageID,
+			"sequence":   ev.seq,
+			"timestamp":  "2099-01-01T00:00:00Z",
+		}
+	}
+	return seedGCPayloads(t, thrumDir, payloads)
+}
+
+// seedGCPayloads is seedGC for full event payloads. A payload without a
+// sequence is stored with its position as the sequence.
+func seedGCPayloads(t *testing.T, thrumDir string, payloads []any) *safedb.DB {
+	t.Helper()
+	raw, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	t.Cleanup(func() { _ = raw.Close() })
+	if _, err := raw.Exec(`CREATE TABLE events (
+		event_id   TEXT PRIMARY KEY,
+		sequence   INTEGER NOT NULL,
+		type       TEXT NOT NULL,
+		timestamp  TEXT NOT NULL,
+		event_json TEXT NOT NULL
+	)`); err != nil {
+		t.Fatalf("create events table: %v", err)
+	}
+
+	var journal strings.Builder
+	for i, p := range payloads {
+		line, _ := json.Marshal(p)
+		var ev struct {
+			EventID  string `json:"event_id"`
+			Type     string `json:"type"`
+			Sequence int64  `json:"sequence"`
+		}
+		if err := json.Unmarshal(line, &ev); err != nil {
+			t.Fatalf("unmarshal payload: %v", err)
+		}
+		if ev.Sequence == 0 {
+			ev.Sequence = int64(i + 1)
+		}
+		if _, err := raw.Exec(`INSERT INTO events (event_id, sequence, type, timestamp, event_json) VALUES (?, ?, ?, ?, ?)`,
+			ev.EventID, ev.Sequence, ev.Type, "2099-01-01T00:00:00Z", string(line)); err != nil {
+			t.Fatalf("insert event: %v", err)
+		}
+		journal.Write(line)
+		journal.WriteByte('\n')
+	}
+	if err := os.WriteFile(filepath.Join(thrumDir, "events.jsonl"), []byte(journal.String()), 0600); err != nil {
+		t.Fatalf("write journal: %v", err)
+	}
+	return safedb.New(raw)
+}
+
+func remainingEventIDs(t *testing.T, db *safedb.DB) []string {
+	t.Helper()
+	rows, err := db.QueryContext(context.Background(), `SELECT event_id FROM events ORDER BY sequence`)
+	if err != nil {
+		t.Fatalf("query events: %v", err)
+	}
+	defer func() { _ = rows.Close() }()
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			t.Fatalf("scan: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func journalEventIDs(t *testing.T, path string) []string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read journal: %v", err)
+	}
+	var ids []string
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var ev struct {
+			EventID string `json:"event_id"`
+		}
+		if err := json.Unmarshal([]byte(line), &ev); err != nil {
+			t.Fatalf("unmarshal %q: %v", line, err)
+		}
+		ids = append(ids, ev.EventID)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func TestCompactor_GC_DropsSupersededEvents(t *testing.T) {
+	thrumDir := t.TempDir()
+	db := seedGC(t, thrumDir, []gcEvent{
+		{"e01", "message.create", "msg_gone", 1},
+		{"e02", "message.edit", "msg_gone", 2},
+		{"e03", "message.receipt", "msg_gone", 3},
+		{"e04", "message.pin", "msg_gone", 4},
+		{"e05", "message.create", "msg_kept", 5},
+		{"e06", "message.edit", "msg_kept", 6},
+		{"e07", "message.edit", "msg_kept", 7},
+		{"e08", "message.receipt", "msg_kept", 8},
+		{"e09", "message.delete", "msg_gone", 9},
+		{"e10", "agent.register", "", 10},
+	})
+
+	stats, err := compact.New(thrumDir, t.TempDir(), 0, 10).GC(context.Background(), db)
+	if err != nil {
+		t.Fatalf("GC: %v", err)
+	}
+
+	// Deleted message: its receipt and pin go; create, edit and delete stay
+	// so a rebuild still has the tombstone. Kept message: untouched.
+	want := []string{"e01", "e02", "e05", "e06", "e07", "e08", "e09", "e10"}
+	if got := remainingEventIDs(t, db); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("events table = %v, want %v", got, want)
+	}
+	if got := journalEventIDs(t, filepath.Join(thrumDir, "events.jsonl")); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("journal = %v, want %v", got, want)
+	}
+	if stats.EventsDropped != 2 || stats.JournalDropped != 2 {
+		t.Errorf("stats = %+v, want 2 dropped from each", stats)
+	}
+}
+
+func TestCompactor_GC_KeepsMaxSequenceRow(t *testing.T) {
+	thrumDir := t.TempDir()
+	// The newest event is a superseded receipt. Dropping it from the table
+	// would let the daemon reuse its sequence number on restart.
+	db := seedGC(t, thrumDir, []gcEvent{
+		{"e1", "message.create", "msg_a", 1},
+		{"e2", "message.delete", "msg_a", 2},
+		{"e3", "message.receipt", "msg_a", 3},
+	})
+
+	if _, err := compact.New(thrumDir, t.TempDir(), 0, 10).GC(context.Background(), db); err != nil {
+		t.Fatalf("GC: %v", err)
+	}
+
+	want := []string{"e1", "e2", "e3"}
+	if got := remainingEventIDs(t, db); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("events table = %v, want %v", got, want)
+	}
+	// The journal does not seed the sequence counter, so it loses e3 too.
+	if got := journalEventIDs(t, filepath.Join(thrumDir, "events.jsonl")); strings.Join(got, ",") != "e1,e2" {
+		t.Errorf("journal = %v, want [e1 e2]", got)
+	}
+}
+
+func TestCompactor_GC_DedupsStateFilesBelowThreshold(t *testing.T) {
+	thrumDir, syncDir := t.TempDir(), t.TempDir()
+	db := seedGC(t, thrumDir, nil)
+	path := filepath.Join(syncDir, "messages-v2", "alice.jsonl")
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		t.Fatal(err)
+	}
+	writeJSONLFile(t, path, []map[string]string{
+		{"message_id": "msg_1", "body": "v1"},
+		{"message_id": "msg_1", "body": "v2"},
+	})
+
+	// 10 MB threshold: CompactAll would skip this tiny file, GC must not.
+	if _, err := compact.New(thrumDir, syncDir, 0, 10).GC(context.Background(), db); err != nil {
+		t.Fatalf("GC: %v", err)
+	}
+	if n := countJSONLLines(t, path); n != 1 {
+		t.Errorf("messages-v2 lines = %d, want 1", n)
+	}
+}
+
+// TestCompactor_GC_RebuildKeepsTombstoneAndHistory replays the journal GC
+// leaves behind into a fresh database, as a rebuild or a new peer would:
+// the deleted message must still be there as a tombstone, and the edited
+// one must keep every version.
+func TestCompactor_GC_RebuildKeepsTombstoneAndHistory(t *testing.T) {
+	thrumDir := t.TempDir()
+	const ts = "2026-01-01T00:00:00Z"
+	create := func(id, msgID string) types.MessageCreateEvent {
+		return types.MessageCreateEvent{Type: "message.create", Timestamp: ts, EventID: id, Version: 1,
+			MessageID: msgID, AgentID: "alice", SessionID: "ses_1", Body: types.MessageBody{Format: "markdown", Content: "v1"}}
+	}
+	edit := func(id, msgID, content string) types.MessageEditEvent {
+		return types.MessageEditEvent{Type: "message.edit", Timestamp: ts, EventID: id, Version: 1,
+			MessageID: msgID, Body: types.MessageBody{Format: "markdown", Content: content}}
+	}
+	db := seedGCPayloads(t, thrumDir, []any{
+		create("e1", "msg_gone"),
+		edit("e2", "msg_gone", "v2"),
+		types.MessageBumpEvent{Type: "message.bump", Timestamp: ts, EventID: "e3", Version: 1, MessageID: "msg_gone", AgentID: "alice"},
+		types.MessageDeleteEvent{Type: "message.delete", Timestamp: ts, EventID: "e4", Version: 1, MessageID: "msg_gone", Reason: "dup"},
+		create("e5", "msg_kept"),
+		edit("e6", "msg_kept", "v2"),
+		edit("e7", "msg_kept", "v3"),
+		map[string]any{"type": "agent.register", "event_id": "e8", "timestamp": ts},
+	})
+
+	if _, err := compact.New(thrumDir, t.TempDir(), 0, 10).GC(context.Background(), db); err != nil {
+		t.Fatalf("GC: %v", err)
+	}
+	if got := journalEventIDs(t, filepath.Join(thrumDir, "events.jsonl")); strings.Join(got, ",") != "e1,e2,e4,e5,e6,e7,e8" {
+		t.Fatalf("journal = %v, want only the bump dropped", got)
+	}
+
+	rebuilt, err := schema.OpenDB(filepath.Join(t.TempDir(), "rebuilt.db"))
+	if err != nil {
+		t.Fatalf("OpenDB: %v", err)
+	}
+	defer func() { _ = rebuilt.Close() }()
+	if err := schema.InitDB(rebuilt); err != nil {
+		t.Fatalf("InitDB: %v", err)
+	}
+	p := projection.NewProjector(safedb.New(rebuilt))
+	data, err := os.ReadFile(filepath.Join(thrumDir, "events.jsonl"))
+	if err != nil {
+		t.Fatalf("read journal: %v", err)
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var ev struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal([]byte(line), &ev); err != nil || !strings.HasPrefix(ev.Type, "message.") {
+			continue
+		}
+		if err := p.Apply(context.Background(), json.RawMessage(line)); err != nil {
+			t.Fatalf("apply %s: %v", line, err)
+		}
+	}
+
+	var deleted int
+	var reason sql.NullString
+	if err := rebuilt.QueryRow(`SELECT deleted, delete_reason FROM messages WHERE message_id = 'msg_gone'`).Scan(&deleted, &reason); err != nil {
+		t.Fatalf("tombstone after rebuild: %v", err)
+	}
+	if deleted != 1 || reason.String != "dup" {
+		t.Errorf("tombstone deleted=%d reason=%q, want 1 %q", deleted, reason.String, "dup")
+	}
+	var edits int
+	if err := rebuilt.QueryRow(`SELECT COUNT(*) FROM message_edits WHERE message_id = 'msg_kept'`).Scan(&edits); err != nil {
+		t.Fatalf("count edits: %v", err)
+	}
+	if edits != 2 {
+		t.Errorf("message_edits for msg_kept = %d, want 2 (history kept)", edits)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"log"
 	"log/slog"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -34,19 +35,53 @@ type SyncLoop struct {
 	repoPath  string
 	syncDir   string // Path to sync worktree (.git/thrum-sync/a-sync)
 	thrumDir  string // Path to .thrum/ directory (used for lock path)
-	localOnly bool   // when true, skip all remote git operations
-	// localOnlyReason records WHY remote sync is held off (e.g. the exposure
-	// gate), for honest status reporting. Set once before Start() via
-	// SetLocalOnlyReason; never mutated after, so it is read under the same lock.
-	localOnlyReason string
-	stopCh          chan struct{}
-	stoppedCh       chan struct{}
-	notifyCh        chan []string // Channel to notify of new event IDs
-	manualSyncCh    chan struct{} // Channel to trigger manual sync
-	mu              sync.Mutex
-	running         bool
-	lastSyncAt      time.Time
-	lastError       error
+	// config is the desired loop configuration (local-only mode and push
+	// retry policy). Reconfigure replaces it at runtime; configDirty tells
+	// the loop goroutine to hand it to the syncer before the next cycle, so
+	// the syncer's fields are only ever touched between cycles. Guarded by mu.
+	config       LoopConfig
+	configDirty  bool
+	stopCh       chan struct{}
+	stoppedCh    chan struct{}
+	notifyCh     chan []string // Channel to notify of new event IDs
+	manualSyncCh chan struct{} // Channel to trigger manual sync
+	// pendingDirection is the direction of the queued manual sync. Two
+	// different directions queued before the loop drains collapse into
+	// DirectionBoth. Guarded by mu.
+	pendingDirection Direction
+	manualPending    bool
+	// pendingOverride marks the queued manual sync as one that runs even
+	// while paused (sync force --force-even-if-paused). Guarded by mu.
+	pendingOverride bool
+	// paused holds off every sync cycle without stopping the loop (sync
+	// pause); pausedUntil is the auto-resume deadline (zero = until
+	// Resume) and resumeTimer fires it. In memory only: a daemon restart
+	// resumes syncing. Guarded by mu.
+	paused      bool
+	pausedAt    time.Time
+	pausedUntil time.Time
+	resumeTimer *time.Timer
+	mu          sync.Mutex
+	running     bool
+	lastSyncAt  time.Time
+	lastError   error
+	// consecutiveFailures counts failed cycles since the last successful
+	// one, for sync.status alerting. Guarded by mu.
+	consecutiveFailures int
+	// cycles, successes and errors count sync attempts, completed cycles
+	// and recorded failures since daemon start, for the metrics endpoint.
+	// Guarded by mu.
+	cycles    uint64
+	successes uint64
+	errors    uint64
+	// history is a ring buffer of the most recent sync attempts for
+	// sync.log; historyNext is the slot the next attempt overwrites and
+	// historyLen how many slots are filled. In memory only: it spans forced
+	// and write-triggered syncs but starts empty on daemon start. Guarded
+	// by mu.
+	history     [SyncLogSize]SyncAttempt
+	historyNext int
+	historyLen  int
 	// walkerCounts provides per-walk row counts for the sync.commit telemetry
 	// event. Set via SetCommitCountsProvider from bootstrap; nil is safe (emits
 	// zeros for the count fields). The provider returns (stateFiles, msgRows, rcptRows).
@@ -90,13 +125,17 @@ func (l *SyncLoop) SetCommitCountsProvider(fn func() (stateFiles, msgRows, rcptR
 // (thrum-s6os). The periodic ticker has been removed; sync runs on
 // structural writes and once at startup for catch-up.
 func NewSyncLoop(syncer *Syncer, projector *projection.Projector, repoPath string, syncDir string, thrumDir string, localOnly bool) *SyncLoop {
+	cfg := LoopConfig{LocalOnly: localOnly}
+	if syncer != nil {
+		cfg.PushRetries, cfg.PushRetryDelay = syncer.pushRetries, syncer.pushRetryDelay
+	}
 	return &SyncLoop{
 		syncer:       syncer,
 		projector:    projector,
 		repoPath:     repoPath,
 		syncDir:      syncDir,
 		thrumDir:     thrumDir,
-		localOnly:    localOnly,
+		config:       cfg,
 		stopCh:       make(chan struct{}),
 		stoppedCh:    make(chan struct{}),
 		notifyCh:     make(chan []string, 100), // Buffered for async notifications
@@ -152,8 +191,42 @@ func (l *SyncLoop) Stop() error {
 	return nil
 }
 
+// Direction restricts which half of a sync cycle runs.
+type Direction string
+
+const (
+	// DirectionBoth fetches + merges remote events, then commits and pushes
+	// local changes. The default for every automatic sync.
+	DirectionBoth Direction = "both"
+	// DirectionPushOnly commits and pushes local changes without fetching
+	// or merging the remote first (one-way recovery). A rejected push still
+	// falls back to CommitAndPush's fetch-merge-retry.
+	DirectionPushOnly Direction = "push"
+	// DirectionPullOnly fetches and merges remote events into the local
+	// projection without committing or pushing local state.
+	DirectionPullOnly Direction = "pull"
+)
+
 // TriggerSync manually triggers a sync cycle (non-blocking).
 func (l *SyncLoop) TriggerSync() {
+	l.TriggerSyncDirection(DirectionBoth)
+}
+
+// TriggerSyncDirection manually triggers a sync cycle restricted to dir
+// (non-blocking). If a manual sync with a different direction is already
+// queued, the queued cycle is widened to DirectionBoth so neither request
+// is lost.
+func (l *SyncLoop) TriggerSyncDirection(dir Direction) {
+	l.mu.Lock()
+	switch {
+	case !l.manualPending:
+		l.pendingDirection = dir
+		l.manualPending = true
+	case l.pendingDirection != dir:
+		l.pendingDirection = DirectionBoth
+	}
+	l.mu.Unlock()
+
 	select {
 	case l.manualSyncCh <- struct{}{}:
 	default:
@@ -161,6 +234,65 @@ func (l *SyncLoop) TriggerSync() {
 	}
 }
 
+// ForceSyncDirection is TriggerSyncDirection for a cycle that runs even
+// while the loop is paused. The pause itself stays in place.
+func (l *SyncLoop) ForceSyncDirection(dir Direction) {
+	l.mu.Lock()
+	l.pendingOverride = true
+	l.mu.Unlock()
+	l.TriggerSyncDirection(dir)
+}
+
+// Pause stops the loop from fetching, committing, or pushing until Resume,
+// or until d has passed when d > 0. The loop stays alive and local writes
+// keep landing in the sync worktree; they go out with the first cycle after
+// resuming. Pausing an already-paused loop replaces its deadline.
+func (l *SyncLoop) Pause(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.resumeTimer != nil {
+		l.resumeTimer.Stop()
+		l.resumeTimer = nil
+	}
+	if !l.paused {
+		l.pausedAt = time.Now()
+	}
+	l.paused = true
+	l.pausedUntil = time.Time{}
+	if d > 0 {
+		l.pausedUntil = time.Now().Add(d)
+		l.resumeTimer = time.AfterFunc(d, func() { l.Resume() })
+	}
+}
+
+// Resume lifts a Pause and triggers a catch-up sync. Reports whether the
+// loop was paused.
+func (l *SyncLoop) Resume() bool {
+	l.mu.Lock()
+	wasPaused := l.paused
+	l.paused = false
+	l.pausedAt = time.Time{}
+	l.pausedUntil = time.Time{}
+	if l.resumeTimer != nil {
+		l.resumeTimer.Stop()
+		l.resumeTimer = nil
+	}
+	l.mu.Unlock()
+
+	if wasPaused {
+		l.TriggerSync()
+	}
+	return wasPaused
+}
+
+// IsPaused reports whether the loop is paused.
+func (l *SyncLoop) IsPaused() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.paused
+}
+
 // NotifyChannel returns a channel that receives new event IDs after each sync.
 // This is used by the subscription system (Epic 6) to notify subscribers.
 func (l *SyncLoop) NotifyChannel() <-chan []string {
@@ -169,12 +301,58 @@ func (l *SyncLoop) NotifyChannel() <-chan []string {
 
 // IsLocalOnly returns whether the sync loop is in local-only mode.
 func (l *SyncLoop) IsLocalOnly() bool {
-	return l.localOnly
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.config.LocalOnly
 }
 
 // SetLocalOnlyReason records WHY remote sync is disabled (e.g. exposure gate),
-// for status reporting. Call before Start(); not safe to call concurrently.
-func (l *SyncLoop) SetLocalOnlyReason(reason string) { l.localOnlyReason = reason }
+// for status reporting.
+func (l *SyncLoop) SetLocalOnlyReason(reason string) {
+	l.mu.Lock()
+	l.config.LocalOnlyReason = reason
+	l.mu.Unlock()
+}
+
+// LoopConfig is the part of the sync loop's configuration that can change
+// while the daemon runs (daemon reload).
+type LoopConfig struct {
+	LocalOnly       bool          // skip all remote git operations
+	LocalOnlyReason string        // why remote sync is held off, for status
+	PushRetries     int           // see Syncer.SetPushRetry
+	PushRetryDelay  time.Duration // see Syncer.SetPushRetry
+}
+
+// Config returns the loop's current configuration.
+func (l *SyncLoop) Config() LoopConfig {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.config
+}
+
+// Reconfigure replaces the loop's configuration. Status reflects it
+// immediately; the syncer picks it up at the start of the next cycle, so a
+// sync already in flight finishes under the old settings.
+func (l *SyncLoop) Reconfigure(cfg LoopConfig) {
+	l.mu.Lock()
+	l.config = cfg
+	l.configDirty = true
+	l.mu.Unlock()
+}
+
+// applyConfig hands a pending Reconfigure to the syncer. Runs on the loop
+// goroutine between cycles.
+func (l *SyncLoop) applyConfig() {
+	l.mu.Lock()
+	cfg, dirty := l.config, l.configDirty
+	l.configDirty = false
+	l.mu.Unlock()
+	if !dirty {
+		return
+	}
+	l.syncer.setLocalOnly(cfg.LocalOnly)
+	l.syncer.SetPushRetry(cfg.PushRetries, cfg.PushRetryDelay)
+}
 
 // GetStatus returns the current sync status.
 func (l *SyncLoop) GetStatus() SyncStatus {
@@ -182,10 +360,14 @@ func (l *SyncLoop) GetStatus() SyncStatus {
 	defer l.mu.Unlock()
 
 	status := SyncStatus{
-		Running:         l.running,
-		LocalOnly:       l.localOnly,
-		LocalOnlyReason: l.localOnlyReason,
-		LastSyncAt:      l.lastSyncAt,
+		Running:             l.running,
+		LocalOnly:           l.config.LocalOnly,
+		LocalOnlyReason:     l.config.LocalOnlyReason,
+		LastSyncAt:          l.lastSyncAt,
+		Paused:              l.paused,
+		ConsecutiveFailures: l.consecutiveFailures,
+		PausedAt:            l.pausedAt,
+		PausedUntil:         l.pausedUntil,
 	}
 
 	if l.lastError != nil {
@@ -195,13 +377,87 @@ func (l *SyncLoop) GetStatus() SyncStatus {
 	return status
 }
 
+// PendingPushCount returns the number of local a-sync commits not yet on
+// origin/a-sync, via git rev-list in the sync worktree. When origin/a-sync
+// does not exist (no remote, or never pushed) every a-sync commit counts.
+func (l *SyncLoop) PendingPushCount(ctx context.Context) (int, error) {
+	rangeSpec := SyncBranchName
+	remoteRef := "refs/remotes/origin/" + SyncBranchName
+	if _, err := safecmd.Git(ctx, l.syncDir, "rev-parse", "--verify", "--quiet", remoteRef); err == nil {
+		rangeSpec = remoteRef + ".." + SyncBranchName
+	}
+	out, err := safecmd.Git(ctx, l.syncDir, "rev-list", "--count", rangeSpec)
+	if err != nil {
+		return 0, fmt.Errorf("count unpushed commits: %w", err)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(string(out)))
+	if err != nil {
+		return 0, fmt.Errorf("parse rev-list count %q: %w", strings.TrimSpace(string(out)), err)
+	}
+	return n, nil
+}
+
+// Counters returns the number of sync cycles attempted, cycles that
+// completed, and errors recorded since the loop was created.
+func (l *SyncLoop) Counters() (cycles, successes, errors uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.cycles, l.successes, l.errors
+}
+
+// SyncLogSize is the number of sync attempts SyncLoop keeps for SyncLog.
+const SyncLogSize = 50
+
+// SyncAttempt records one sync cycle.
+type SyncAttempt struct {
+	StartedAt  time.Time `json:"started_at"`
+	DurationMS int64     `json:"duration_ms"`
+	Direction  Direction `json:"direction"`
+	Fetched    int       `json:"fetched"` // New remote events merged
+	Pushed     int       `json:"pushed"`  // Lines added by the commit pushed to the remote
+	// Retries lists rejected pushes that were retried, whether or not the
+	// attempt finally succeeded.
+	Retries []PushRetry `json:"retries,omitempty"`
+	Error   string      `json:"error,omitempty"`
+}
+
+// SyncLog returns the recorded sync attempts, newest first. At most
+// SyncLogSize attempts are kept, and the log is lost on daemon restart.
+func (l *SyncLoop) SyncLog() []SyncAttempt {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	attempts := make([]SyncAttempt, l.historyLen)
+	for i := range attempts {
+		attempts[i] = l.history[(l.historyNext-1-i+SyncLogSize)%SyncLogSize]
+	}
+	return attempts
+}
+
+// recordAttempt appends a finished attempt to the history ring buffer.
+func (l *SyncLoop) recordAttempt(attempt *SyncAttempt) {
+	attempt.DurationMS = time.Since(attempt.StartedAt).Milliseconds()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.history[l.historyNext] = *attempt
+	l.historyNext = (l.historyNext + 1) % SyncLogSize
+	if l.historyLen < SyncLogSize {
+		l.historyLen++
+	}
+}
+
 // SyncStatus contains the current status of the sync loop.
 type SyncStatus struct {
-	Running         bool      `json:"running"`
-	LocalOnly       bool      `json:"local_only"`
-	LocalOnlyReason string    `json:"local_only_reason,omitempty"`
-	LastSyncAt      time.Time `json:"last_sync_at"`
-	LastError       string    `json:"last_error,omitempty"`
+	Running             bool      `json:"running"`
+	LocalOnly           bool      `json:"local_only"`
+	LocalOnlyReason     string    `json:"local_only_reason,omitempty"`
+	LastSyncAt          time.Time `json:"last_sync_at"`
+	LastError           string    `json:"last_error,omitempty"`
+	Paused              bool      `json:"paused,omitempty"`
+	PausedAt            time.Time `json:"paused_at,omitzero"`
+	PausedUntil         time.Time `json:"paused_until,omitzero"` // zero = until resumed
+	ConsecutiveFailures int       `json:"consecutive_failures"`  // failed cycles since the last success
 }
 
 // run is the main loop that runs in a goroutine.
@@ -211,7 +467,9 @@ func (l *SyncLoop) run(ctx context.Context) {
 	// Do an initial sync to catch up on any peer events written while the
 	// daemon was offline. Subsequent syncs are triggered by SyncOnWrite
 	// (structural events) or TriggerSync (manual/RPC).
-	l.doSync(ctx)
+	if !l.IsPaused() {
+		l.doSync(ctx)
+	}
 
 	for {
 		select {
@@ -220,34 +478,84 @@ func (l *SyncLoop) run(ctx context.Context) {
 		case <-l.stopCh:
 			return
 		case <-l.manualSyncCh:
-			l.doSync(ctx)
+			l.mu.Lock()
+			dir, override := l.pendingDirection, l.pendingOverride
+			l.manualPending = false
+			l.pendingOverride = false
+			paused := l.paused
+			l.mu.Unlock()
+			if paused && !override {
+				continue
+			}
+			l.doSyncDirection(ctx, dir)
 		}
 	}
 }
 
-// doSync performs a single sync cycle.
+// doSync performs a single full (pull + push) sync cycle.
 func (l *SyncLoop) doSync(ctx context.Context) {
+	l.doSyncDirection(ctx, DirectionBoth)
+}
+
+// doSyncDirection performs a single sync cycle. DirectionPushOnly skips
+// steps 1-4 (fetch, merge, projection, notify); DirectionPullOnly skips
+// steps 5-6 (commit, push, telemetry).
+func (l *SyncLoop) doSyncDirection(ctx context.Context, dir Direction) {
+	l.applyConfig()
+
+	l.mu.Lock()
+	l.cycles++
+	l.mu.Unlock()
+
+	attempt := &SyncAttempt{StartedAt: time.Now(), Direction: dir}
+	defer l.recordAttempt(attempt)
+
 	// Acquire lock
 	lockPath := filepath.Join(paths.VarDir(l.thrumDir), "sync.lock")
 	lock, err := acquireLock(lockPath)
 	if err != nil {
-		l.setError(fmt.Errorf("acquire lock: %w", err))
+		l.failAttempt(attempt, fmt.Errorf("acquire lock: %w", err))
 		return
 	}
 	defer func() { _ = releaseLock(lock) }()
 
+	if dir != DirectionPushOnly {
+		if !l.pullRemote(ctx, attempt) {
+			return
+		}
+	}
+
+	if dir != DirectionPullOnly {
+		if !l.pushLocal(ctx, attempt) {
+			return
+		}
+	}
+
+	// Success - update status
+	l.mu.Lock()
+	l.lastSyncAt = time.Now()
+	l.lastError = nil
+	l.consecutiveFailures = 0
+	l.successes++
+	l.mu.Unlock()
+}
+
+// pullRemote fetches and merges remote events, applies them to the projection,
+// and notifies subscribers. Reports false (after recording the error) when
+// the cycle must stop.
+func (l *SyncLoop) pullRemote(ctx context.Context, attempt *SyncAttempt) bool {
 	// 1. Fetch remote
 	if err := l.syncer.merger.Fetch(ctx); err != nil {
-		l.setError(fmt.Errorf("fetch: %w", err))
-		return
+		l.failAttempt(attempt, fmt.Errorf("fetch: %w", err))
+		return false
 	}
 
 	// 2. Merge all files (events.jsonl + messages/*.jsonl)
 	mergeResult, err := l.syncer.merger.MergeAll(ctx)
 	if err != nil {
-		if !l.localOnly {
-			l.setError(fmt.Errorf("merge: %w", err))
-			return
+		if !l.syncer.localOnly {
+			l.failAttempt(attempt, fmt.Errorf("merge: %w", err))
+			return false
 		}
 		// In local-only mode, merge errors are expected (no remote to merge
 		// from). Continue to CommitAndPush so local changes are committed.
@@ -256,9 +564,10 @@ func (l *SyncLoop) doSync(ctx context.Context) {
 
 	// 3. Update SQLite projection with new events
 	if mergeResult != nil && mergeResult.NewEvents > 0 {
+		attempt.Fetched = mergeResult.NewEvents
 		if err := l.updateProjection(ctx, mergeResult.NewParsedEvents); err != nil {
-			l.setError(fmt.Errorf("update projection: %w", err))
-			return
+			l.failAttempt(attempt, fmt.Errorf("update projection: %w", err))
+			return false
 		}
 
 		// 4. Notify subscribers of new events (Epic 6)
@@ -271,7 +580,12 @@ func (l *SyncLoop) doSync(ctx context.Context) {
 			}
 		}
 	}
+	return true
+}
 
+// pushLocal commits and pushes local changes, emitting sync.commit telemetry
+// when a commit lands. Reports false (after recording the error) on failure.
+func (l *SyncLoop) pushLocal(ctx context.Context, attempt *SyncAttempt) bool {
 	// 5. Commit and push if local changes.
 	// Capture HEAD before CommitAndPush so the post-call comparison can
 	// tell whether a new commit actually landed. Spec §10 requires
@@ -283,9 +597,11 @@ func (l *SyncLoop) doSync(ctx context.Context) {
 	if shaBytes, shaErr := safecmd.Git(ctx, l.syncDir, "rev-parse", "HEAD"); shaErr == nil {
 		preSHA = strings.TrimSpace(string(shaBytes))
 	}
-	if err := l.syncer.CommitAndPush(ctx); err != nil {
-		l.setError(fmt.Errorf("commit and push: %w", err))
-		return
+	retries, err := l.syncer.CommitAndPush(ctx)
+	attempt.Retries = retries
+	if err != nil {
+		l.failAttempt(attempt, fmt.Errorf("commit and push: %w", err))
+		return false
 	}
 
 	// 6. Emit sync.commit telemetry only when a new commit actually
@@ -306,13 +622,30 @@ func (l *SyncLoop) doSync(ctx context.Context) {
 			"state_files", stateFiles,
 			"message_rows", msgRows,
 			"receipt_rows", rcptRows)
+
+		if !l.syncer.localOnly && preSHA != "" {
+			attempt.Pushed = addedLines(ctx, l.syncDir, preSHA, postSHA)
+		}
 	}
+	return true
+}
 
-	// Success - update status
-	l.mu.Lock()
-	l.lastSyncAt = time.Now()
-	l.lastError = nil
-	l.mu.Unlock()
+// addedLines sums the lines added between two commits in dir. Each synced
+// JSONL line is one event or message, so this is the number of records a
+// push carried. Errors count as zero; the log is diagnostic only.
+func addedLines(ctx context.Context, dir, from, to string) int {
+	out, err := safecmd.Git(ctx, dir, "diff", "--numstat", from, to)
+	if err != nil {
+		return 0
+	}
+	total := 0
+	for line := range strings.SplitSeq(strings.TrimSpace(string(out)), "\n") {
+		added, _, _ := strings.Cut(line, "\t")
+		if n, err := strconv.Atoi(added); err == nil {
+			total += n
+		}
+	}
+	return total
 }
 
 // updateProjection applies the parsed events to SQLite. When an
@@ -356,10 +689,18 @@ func extractEventIDFromRaw(data json.RawMessage) (string, error) {
 	return extractEventID(data, base.Type)
 }
 
+// failAttempt records err on the attempt and as the loop's last error.
+func (l *SyncLoop) failAttempt(attempt *SyncAttempt, err error) {
+	attempt.Error = err.Error()
+	l.setError(err)
+}
+
 // setError updates the last error status.
 func (l *SyncLoop) setError(err error) {
 	l.mu.Lock()
 	l.lastError = err
+	l.errors++
+	l.consecutiveFailures++
 	l.mu.Unlock()
 	log.Printf("sync: error: %v", err)
 }
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"os/exec"
 	"path/filepath"
 	gosync "sync"
 	"testing"
@@ -287,6 +288,14 @@ func initTestSchema(db *sql.DB) error {
 		FOREIGN KEY (agent_id) REFERENCES agents(agent_id)
 	);
 
+	CREATE TABLE IF NOT EXISTS session_scopes (
+		session_id TEXT NOT NULL,
+		scope_type TEXT NOT NULL,
+		scope_value TEXT NOT NULL,
+		added_at TEXT NOT NULL,
+		PRIMARY KEY (session_id, scope_type, scope_value)
+	);
+
 	CREATE TABLE IF NOT EXISTS threads (
 		thread_id TEXT PRIMARY KEY,
 		title TEXT NOT NULL,
@@ -310,6 +319,11 @@ func initTestSchema(db *sql.DB) error {
 		authored_by              TEXT,
 		disclosed                INTEGER DEFAULT 0,
 		pending_route_resolution INTEGER NOT NULL DEFAULT 0,
+		priority                 TEXT NOT NULL DEFAULT '',
+		expires_at               TEXT,
+		origin_daemon            TEXT NOT NULL DEFAULT '',
+		origin_sequence          INTEGER NOT NULL DEFAULT 0,
+		deliver_at               TEXT,
 		FOREIGN KEY (thread_id) REFERENCES threads(thread_id),
 		FOREIGN KEY (agent_id) REFERENCES agents(agent_id),
 		FOREIGN KEY (session_id) REFERENCES sessions(session_id)
@@ -338,6 +352,39 @@ func initTestSchema(db *sql.DB) error {
 		PRIMARY KEY (message_id, recipient_agent_id),
 		FOREIGN KEY (message_id) REFERENCES messages(message_id) ON DELETE CASCADE
 	);
+
+	CREATE TABLE IF NOT EXISTS pending_receipts (
+		message_id   TEXT NOT NULL,
+		agent_id     TEXT NOT NULL,
+		receipt_type TEXT NOT NULL,
+		timestamp    TEXT NOT NULL,
+		PRIMARY KEY (message_id, agent_id, receipt_type)
+	);
+
+	CREATE VIRTUAL TABLE IF NOT EXISTS messages_fts USING fts5(
+		message_id UNINDEXED,
+		body_content
+	);
+
+	CREATE TABLE IF NOT EXISTS message_tags (
+		message_id TEXT NOT NULL,
+		tag        TEXT NOT NULL,
+		PRIMARY KEY (message_id, tag),
+		FOREIGN KEY (message_id) REFERENCES messages(message_id) ON DELETE CASCADE
+	);
+
+	CREATE TABLE IF NOT EXISTS message_pins (
+		message_id TEXT PRIMARY KEY,
+		pinned_by  TEXT NOT NULL,
+		pinned_at  TEXT NOT NULL,
+		FOREIGN KEY (message_id) REFERENCES messages(message_id) ON DELETE CASCADE
+	);
+
+	CREATE TABLE IF NOT EXISTS agent_aliases (
+		alias      TEXT PRIMARY KEY,
+		agent_id   TEXT NOT NULL,
+		created_at TEXT NOT NULL
+	);
 	`
 
 	_, err := db.Exec(schema)
@@ -368,6 +415,43 @@ func TestSyncLoop_IsLocalOnly(t *testing.T) {
 	})
 }
 
+func TestSyncLoop_Reconfigure(t *testing.T) {
+	tmpDir := setupTestRepoWithCommit(t)
+	setupThrumFiles(t, tmpDir)
+	syncDir := filepath.Join(tmpDir, ".git", "thrum-sync", "a-sync")
+
+	syncer := NewSyncer(tmpDir, syncDir, true)
+	projector := setupTestProjector(t, tmpDir)
+	loop := NewSyncLoop(syncer, projector, tmpDir, syncDir, filepath.Join(tmpDir, ".thrum"), true)
+
+	cfg := loop.Config()
+	if !cfg.LocalOnly || cfg.PushRetries != DefaultPushRetries {
+		t.Fatalf("initial Config() = %+v", cfg)
+	}
+
+	cfg.LocalOnly = false
+	cfg.PushRetries = 1
+	cfg.PushRetryDelay = 10 * time.Millisecond
+	loop.Reconfigure(cfg)
+
+	// Status reflects the new config immediately...
+	if loop.IsLocalOnly() || loop.GetStatus().LocalOnly {
+		t.Error("expected local-only off right after Reconfigure")
+	}
+	// ...but the syncer only picks it up between cycles.
+	if !syncer.localOnly {
+		t.Error("syncer changed before the next cycle")
+	}
+
+	loop.applyConfig()
+	if syncer.localOnly || syncer.branchManager.localOnly || syncer.merger.localOnly {
+		t.Error("expected syncer, branch manager and merger to leave local-only mode")
+	}
+	if syncer.pushRetries != 1 || syncer.pushRetryDelay != 10*time.Millisecond {
+		t.Errorf("push retry = %d/%v, want 1/10ms", syncer.pushRetries, syncer.pushRetryDelay)
+	}
+}
+
 func TestSyncLoop_LocalOnly_StatusReportsMode(t *testing.T) {
 	tmpDir := setupMergeTestRepo(t)
 	syncDir := filepath.Join(tmpDir, ".git", "thrum-sync", "a-sync")
@@ -554,6 +638,50 @@ func TestLoop_SetError(t *testing.T) {
 	if status.LastError != "test error" {
 		t.Errorf("Expected error to be recorded, got: %s", status.LastError)
 	}
+	loop.setError(testErr)
+	if got := loop.GetStatus().ConsecutiveFailures; got != 2 {
+		t.Errorf("ConsecutiveFailures = %d, want 2", got)
+	}
+}
+
+func TestSyncLoop_ConsecutiveFailuresResetOnSuccess(t *testing.T) {
+	tmpDir := setupMergeTestRepo(t)
+	syncDir := filepath.Join(tmpDir, ".git", "thrum-sync", "a-sync")
+	loop := NewSyncLoop(NewSyncer(tmpDir, syncDir, true), setupTestProjector(t, tmpDir), tmpDir, syncDir, filepath.Join(tmpDir, ".thrum"), true)
+
+	loop.setError(fmt.Errorf("first"))
+	loop.setError(fmt.Errorf("second"))
+	loop.doSync(context.Background())
+
+	status := loop.GetStatus()
+	if status.LastError != "" || status.ConsecutiveFailures != 0 {
+		t.Errorf("after a successful cycle: last error %q, failures %d; want both cleared",
+			status.LastError, status.ConsecutiveFailures)
+	}
+	if cycles, successes, errs := loop.Counters(); cycles != 1 || successes != 1 || errs != 2 {
+		t.Errorf("Counters() = %d cycles, %d successes, %d errors; want 1, 1, 2", cycles, successes, errs)
+	}
+}
+
+func TestSyncLoop_PendingPushCount(t *testing.T) {
+	repoPath, _ := setupRepoWithRemote(t)
+	syncDir := filepath.Join(repoPath, ".git", "thrum-sync", "a-sync")
+	loop := NewSyncLoop(NewSyncer(repoPath, syncDir, false), nil, repoPath, syncDir, filepath.Join(repoPath, ".thrum"), false)
+	ctx := context.Background()
+
+	if n, err := loop.PendingPushCount(ctx); err != nil || n != 0 {
+		t.Fatalf("PendingPushCount after push = %d, %v; want 0", n, err)
+	}
+	for i := range 2 {
+		cmd := exec.Command("git", "commit", "--allow-empty", "-m", fmt.Sprintf("local %d", i))
+		cmd.Dir = syncDir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("commit: %v: %s", err, out)
+		}
+	}
+	if n, err := loop.PendingPushCount(ctx); err != nil || n != 2 {
+		t.Errorf("PendingPushCount = %d, %v; want 2", n, err)
+	}
 }
 
 func TestExtractEventIDFromRaw(t *testing.T) {
@@ -805,3 +933,163 @@ func TestSyncLoop_SetLocalOnlyReason_SurfacesInStatus(t *testing.T) {
 		t.Fatalf("LocalOnlyReason = %q, want %q", st.LocalOnlyReason, reason)
 	}
 }
+
+func TestSyncLoop_TriggerSyncDirection_CollapsesToBoth(t *testing.T) {
+	l := NewSyncLoop(nil, nil, t.TempDir(), t.TempDir(), t.TempDir(), true)
+
+	l.TriggerSyncDirection(DirectionPullOnly)
+	if l.pendingDirection != DirectionPullOnly {
+		t.Fatalf("pendingDirection = %q, want %q", l.pendingDirection, DirectionPullOnly)
+	}
+	l.TriggerSyncDirection(DirectionPullOnly)
+	if l.pendingDirection != DirectionPullOnly {
+		t.Fatalf("repeat trigger changed direction to %q", l.pendingDirection)
+	}
+	l.TriggerSyncDirection(DirectionPushOnly)
+	if l.pendingDirection != DirectionBoth {
+		t.Errorf("mixed triggers: pendingDirection = %q, want %q", l.pendingDirection, DirectionBoth)
+	}
+	if len(l.manualSyncCh) != 1 {
+		t.Errorf("manualSyncCh len = %d, want 1", len(l.manualSyncCh))
+	}
+}
+
+func TestSyncLoop_SyncLog_RingBuffer(t *testing.T) {
+	l := NewSyncLoop(nil, nil, t.TempDir(), t.TempDir(), t.TempDir(), true)
+
+	if got := l.SyncLog(); len(got) != 0 {
+		t.Fatalf("new loop log = %v, want empty", got)
+	}
+
+	for i := range SyncLogSize + 5 {
+		attempt := &SyncAttempt{StartedAt: time.Now(), Direction: DirectionBoth, Fetched: i}
+		if i%2 == 1 {
+			l.failAttempt(attempt, fmt.Errorf("attempt %d failed", i))
+		}
+		l.recordAttempt(attempt)
+	}
+
+	log := l.SyncLog()
+	if len(log) != SyncLogSize {
+		t.Fatalf("log length = %d, want %d", len(log), SyncLogSize)
+	}
+	// Newest first; the five oldest were overwritten.
+	if log[0].Fetched != SyncLogSize+4 || log[len(log)-1].Fetched != 5 {
+		t.Errorf("log spans fetched %d..%d, want %d..5", log[0].Fetched, log[len(log)-1].Fetched, SyncLogSize+4)
+	}
+	if log[0].Error != "" || log[1].Error != fmt.Sprintf("attempt %d failed", SyncLogSize+3) {
+		t.Errorf("errors = %q, %q; want only the odd attempt failed", log[0].Error, log[1].Error)
+	}
+}
+
+func TestSyncLoop_SyncLog_RecordsCycles(t *testing.T) {
+	tmpDir := setupMergeTestRepo(t)
+	syncDir := filepath.Join(tmpDir, ".git", "thrum-sync", "a-sync")
+
+	syncer := NewSyncer(tmpDir, syncDir, true)
+	projector := setupTestProjector(t, tmpDir)
+	loop := NewSyncLoop(syncer, projector, tmpDir, syncDir, filepath.Join(tmpDir, ".thrum"), true)
+
+	ctx := context.Background()
+	if err := loop.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer func() { _ = loop.Stop() }()
+
+	// The startup sync and a forced push-only sync both land in the log.
+	waitForLog := func(n int) []SyncAttempt {
+		t.Helper()
+		deadline := time.After(2 * time.Second)
+		for {
+			if log := loop.SyncLog(); len(log) >= n {
+				return log
+			}
+			select {
+			case <-deadline:
+				t.Fatalf("sync log did not reach %d attempts", n)
+			default:
+				time.Sleep(20 * time.Millisecond)
+			}
+		}
+	}
+	waitForLog(1)
+	loop.TriggerSyncDirection(DirectionPushOnly)
+	log := waitForLog(2)
+
+	if log[0].Direction != DirectionPushOnly || log[1].Direction != DirectionBoth {
+		t.Errorf("directions = %q, %q; want push, both", log[0].Direction, log[1].Direction)
+	}
+	if log[0].StartedAt.Before(log[1].StartedAt) {
+		t.Error("log is not newest first")
+	}
+	for _, a := range log {
+		if a.Error != "" {
+			t.Errorf("attempt error: %s", a.Error)
+		}
+	}
+}
+
+func TestSyncLoop_PauseResume(t *testing.T) {
+	tmpDir := setupMergeTestRepo(t)
+	syncDir := filepath.Join(tmpDir, ".git", "thrum-sync", "a-sync")
+
+	syncer := NewSyncer(tmpDir, syncDir, true)
+	projector := setupTestProjector(t, tmpDir)
+	loop := NewSyncLoop(syncer, projector, tmpDir, syncDir, filepath.Join(tmpDir, ".thrum"), true)
+
+	ctx := context.Background()
+	if err := loop.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer func() { _ = loop.Stop() }()
+
+	waitForLog := func(n int) {
+		t.Helper()
+		deadline := time.After(2 * time.Second)
+		for len(loop.SyncLog()) < n {
+			select {
+			case <-deadline:
+				t.Fatalf("sync log did not reach %d attempts", n)
+			default:
+				time.Sleep(20 * time.Millisecond)
+			}
+		}
+	}
+	waitForLog(1) // startup sync
+
+	loop.Pause(0)
+	if st := loop.GetStatus(); !st.Paused || st.PausedAt.IsZero() || !st.PausedUntil.IsZero() {
+		t.Fatalf("status after Pause(0) = %+v", st)
+	}
+	loop.TriggerSync()
+	time.Sleep(100 * time.Millisecond)
+	if n := len(loop.SyncLog()); n != 1 {
+		t.Fatalf("sync ran while paused: %d attempts", n)
+	}
+
+	// A forced cycle runs through the pause and leaves it in place.
+	loop.ForceSyncDirection(DirectionPushOnly)
+	waitForLog(2)
+	if !loop.IsPaused() {
+		t.Error("forced sync lifted the pause")
+	}
+
+	// Resume catches up right away.
+	if !loop.Resume() {
+		t.Error("Resume reported the loop was not paused")
+	}
+	waitForLog(3)
+	if loop.Resume() {
+		t.Error("second Resume reported the loop was paused")
+	}
+
+	// Auto-resume after the duration.
+	loop.Pause(50 * time.Millisecond)
+	if st := loop.GetStatus(); st.PausedUntil.IsZero() {
+		t.Error("timed pause has no paused_until")
+	}
+	waitForLog(4)
+	if loop.IsPaused() {
+		t.Error("loop still paused after the duration")
+	}
+}
@@ -247,9 +247,18 @@ func (m *Merger) MergeAll(ctx context.Context) (*MergeResult, error) {
 		return nil, fmt.Errorf("merge receipts: %w", mergeErr)
 	}
 
-	// 6. Local-only files are kept as-is (will be pushed)
+	// 6. attachments/: copy in peer attachments this worktree lacks. They
+	// are immutable (one directory per message ID), so there is nothing to
+	// merge, but the reset below rebuilds the index from origin and the
+	// next stageChanges would otherwise record any file missing here as
+	// deleted.
+	if mergeErr := m.mergeAttachments(ctx); mergeErr != nil {
+		return nil, fmt.Errorf("merge attachments: %w", mergeErr)
+	}
+
+	// 7. Local-only files are kept as-is (will be pushed)
 
-	// 7. thrum-ychn: reset the local a-sync branch pointer to origin/a-sync
+	// 8. thrum-ychn: reset the local a-sync branch pointer to origin/a-sync
 	// so the next commit is fast-forward-able on push. MergeAll has already
 	// written deduped content into the working tree; --mixed preserves the
 	// working tree while moving HEAD (→ origin tip) and clearing the index
@@ -285,6 +294,36 @@ func (m *Merger) MergeAll(ctx context.Context) (*MergeResult, error) {
 	return totalStats, nil
 }
 
+// mergeAttachments checks out every attachments/ file present on
+// origin/a-sync but missing from the worktree. A missing remote branch
+// (local-only mode, first sync) is not an error: there is nothing to copy.
+func (m *Merger) mergeAttachments(ctx context.Context) error {
+	if m.localOnly {
+		return nil
+	}
+	output, err := safecmd.Git(ctx, m.syncDir, "ls-tree", "-r", "-z", "--name-only", "origin/"+SyncBranchName, "--", "attachments/")
+	if err != nil {
+		return nil //nolint:nilerr // remote branch doesn't exist yet
+	}
+	var missing []string
+	for _, line := range strings.Split(string(output), "\x00") {
+		if line == "" {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(m.syncDir, filepath.FromSlash(line))); os.IsNotExist(err) {
+			missing = append(missing, line)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	args := append([]string{"checkout", "origin/" + SyncBranchName, "--"}, missing...)
+	if _, err := safecmd.GitLong(ctx, m.syncDir, args...); err != nil {
+		return fmt.Errorf("check out %d attachment(s): %w", len(missing), err)
+	}
+	return nil
+}
+
 // extractRemoteFiles batch-extracts remote files from the sync branch
 // using git archive + tar. Returns the temp directory path containing the
 // extracted files. The caller must clean up the temp directory.
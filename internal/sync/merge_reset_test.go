@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/leonletto/thrum/internal/daemon/safecmd"
 )
@@ -194,7 +195,7 @@ func TestSyncer_CommitAndPush_SucceedsAfterConcurrentRemoteAdvance(t *testing.T)
 	if _, err := s.merger.MergeAll(ctx); err != nil {
 		t.Fatalf("merge: %v", err)
 	}
-	if err := s.CommitAndPush(ctx); err != nil {
+	if _, err := s.CommitAndPush(ctx); err != nil {
 		t.Fatalf("CommitAndPush should succeed after remote advance; got %v", err)
 	}
 
@@ -217,6 +218,69 @@ func TestSyncer_CommitAndPush_SucceedsAfterConcurrentRemoteAdvance(t *testing.T)
 	}
 }
 
+// A push that races a peer (no fetch first) is rejected, then retried after
+// backoff + fetch + merge; the retry is reported for the sync log. With
+// retries disabled the rejection surfaces immediately.
+func TestSyncer_CommitAndPush_RetriesRejectedPush(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		retries     int
+		wantRetries int
+		wantErr     string
+	}{
+		{name: "retried", retries: 2, wantRetries: 1},
+		{name: "retries disabled", retries: 0, wantRetries: 0, wantErr: "push rejected after 0 retries"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			repoPath, bareDir := setupRepoWithRemote(t)
+			syncDir := filepath.Join(repoPath, ".git", "thrum-sync", "a-sync")
+
+			pushFromSecondClone(t, bareDir, `{"type":"message.create","timestamp":"2026-02-03T00:00:03Z","message_id":"msg_peer_003","event_id":"evt_peer_003"}`)
+			eventsPath := filepath.Join(syncDir, "events.jsonl")
+			local, _ := os.ReadFile(eventsPath) //nolint:gosec
+			local = append(local, `{"type":"message.create","timestamp":"2026-02-03T00:00:04Z","message_id":"msg_local_002","event_id":"evt_local_002"}`+"\n"...)
+			if err := os.WriteFile(eventsPath, local, 0600); err != nil {
+				t.Fatalf("write local event: %v", err)
+			}
+
+			s := NewSyncer(repoPath, syncDir, false)
+			s.SetPushRetry(tt.retries, time.Millisecond)
+			retries, err := s.CommitAndPush(context.Background())
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("CommitAndPush: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("CommitAndPush err = %v, want containing %q", err, tt.wantErr)
+			}
+			if len(retries) != tt.wantRetries {
+				t.Fatalf("retries = %+v, want %d", retries, tt.wantRetries)
+			}
+			if tt.wantRetries > 0 && (retries[0].Attempt != 1 || !strings.Contains(retries[0].Error, "rejected")) {
+				t.Errorf("retry = %+v, want attempt 1 with the rejection", retries[0])
+			}
+			if tt.wantRetries == 0 {
+				return
+			}
+
+			// The retried push landed the local event next to the peer's.
+			peerCheck := t.TempDir()
+			cmd := exec.Command("git", "clone", "--branch", "a-sync", bareDir, peerCheck) //nolint:gosec
+			if err := cmd.Run(); err != nil {
+				t.Fatalf("clone bare for check: %v", err)
+			}
+			remote, err := os.ReadFile(filepath.Join(peerCheck, "events.jsonl")) //nolint:gosec
+			if err != nil {
+				t.Fatalf("read remote events: %v", err)
+			}
+			for _, id := range []string{"msg_peer_003", "msg_local_002"} {
+				if !strings.Contains(string(remote), id) {
+					t.Errorf("remote missing %s after retry; events:\n%s", id, remote)
+				}
+			}
+		})
+	}
+}
+
 // Coverage for the messages/ merge path through the reset: a peer pushes
 // both an events.jsonl entry AND a messages/*.jsonl file; local has a
 // different messages file. After MergeAll, both message files should be
@@ -347,3 +411,59 @@ func TestMerger_MergeAll_FirstSyncNoRemoteBranch(t *testing.T) {
 			expectedTip, postTip)
 	}
 }
+
+// Attachments pushed by a peer land in the local worktree, so the next
+// stageChanges (after the --mixed reset rebuilt the index from origin)
+// does not record them as deleted.
+func TestMerger_MergeAll_ChecksOutPeerAttachments(t *testing.T) {
+	repoPath, bareDir := setupRepoWithRemote(t)
+	syncDir := filepath.Join(repoPath, ".git", "thrum-sync", "a-sync")
+
+	peerDir := t.TempDir()
+	run := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.email=peer@test", "-c", "user.name=peer"}, args...)...) //nolint:gosec // G204 test uses controlled args
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	run(peerDir, "clone", "--branch", "a-sync", bareDir, ".")
+	attachDir := filepath.Join(peerDir, "attachments", "msg_peer_att")
+	if err := os.MkdirAll(attachDir, 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(attachDir, "build log.txt"), []byte("FAIL\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	run(peerDir, "add", ".")
+	run(peerDir, "commit", "-m", "peer attachment")
+	run(peerDir, "push", "origin", "a-sync")
+
+	s := NewSyncer(repoPath, syncDir, false)
+	ctx := context.Background()
+	if err := s.merger.Fetch(ctx); err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	if _, err := s.merger.MergeAll(ctx); err != nil {
+		t.Fatalf("mergeAll: %v", err)
+	}
+	got, err := os.ReadFile(filepath.Join(syncDir, "attachments", "msg_peer_att", "build log.txt")) //nolint:gosec // test path
+	if err != nil {
+		t.Fatalf("peer attachment not checked out: %v", err)
+	}
+	if string(got) != "FAIL\n" {
+		t.Errorf("attachment content = %q, want %q", got, "FAIL\n")
+	}
+
+	if _, err := s.CommitAndPush(ctx); err != nil {
+		t.Fatalf("CommitAndPush: %v", err)
+	}
+	out, err := safecmd.Git(ctx, syncDir, "ls-tree", "-r", "--name-only", "origin/a-sync", "--", "attachments/")
+	if err != nil {
+		t.Fatalf("ls-tree: %v", err)
+	}
+	if !strings.Contains(string(out), "msg_peer_att") {
+		t.Errorf("peer attachment dropped from origin/a-sync after push; tree:\n%s", out)
+	}
+}
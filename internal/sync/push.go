@@ -3,6 +3,7 @@ package sync
 import (
 	"context"
 	"fmt"
+	"math/rand/v2"
 	"net/url"
 	"strings"
 	"time"
@@ -10,6 +11,12 @@ import (
 	"github.com/leonletto/thrum/internal/daemon/safecmd"
 )
 
+// Defaults for retrying a rejected push; see SetPushRetry.
+const (
+	DefaultPushRetries    = 3
+	DefaultPushRetryDelay = 500 * time.Millisecond
+)
+
 // Syncer coordinates sync operations (branch, merge, push).
 type Syncer struct {
 	repoPath      string
@@ -17,20 +24,51 @@ type Syncer struct {
 	localOnly     bool   // when true, skip all git push/fetch operations
 	branchManager *BranchManager
 	merger        *Merger
+	// pushRetries is how many times a rejected push is retried after
+	// fetch+merge; pushRetryDelay is the backoff before the first retry,
+	// doubled for each one after.
+	pushRetries    int
+	pushRetryDelay time.Duration
+}
+
+// PushRetry records one rejected push that CommitAndPush retried.
+type PushRetry struct {
+	Attempt int    `json:"attempt"`  // 1-based push attempt that was rejected
+	DelayMS int64  `json:"delay_ms"` // backoff waited before the next attempt
+	Error   string `json:"error"`
 }
 
 // NewSyncer creates a new Syncer for the given repository path.
 // When localOnly is true, all remote git operations (push/fetch) are skipped.
 func NewSyncer(repoPath string, syncDir string, localOnly bool) *Syncer {
 	return &Syncer{
-		repoPath:      repoPath,
-		syncDir:       syncDir,
-		localOnly:     localOnly,
-		branchManager: NewBranchManager(repoPath, localOnly),
-		merger:        NewMerger(repoPath, syncDir, localOnly),
+		repoPath:       repoPath,
+		syncDir:        syncDir,
+		localOnly:      localOnly,
+		branchManager:  NewBranchManager(repoPath, localOnly),
+		merger:         NewMerger(repoPath, syncDir, localOnly),
+		pushRetries:    DefaultPushRetries,
+		pushRetryDelay: DefaultPushRetryDelay,
 	}
 }
 
+// setLocalOnly switches remote git operations off (true) or on for the
+// syncer and its branch manager and merger. Not safe to call during a sync;
+// SyncLoop calls it between cycles.
+func (s *Syncer) setLocalOnly(localOnly bool) {
+	s.localOnly = localOnly
+	s.branchManager.localOnly = localOnly
+	s.merger.localOnly = localOnly
+}
+
+// SetPushRetry overrides how many times a rejected push is retried and the
+// base backoff delay. Call before the sync loop starts; once it runs, use
+// SyncLoop.Reconfigure. retries of 0 disables retrying.
+func (s *Syncer) SetPushRetry(retries int, baseDelay time.Duration) {
+	s.pushRetries = max(retries, 0)
+	s.pushRetryDelay = max(baseDelay, 0)
+}
+
 // CommitAndPush commits and pushes changes to the remote a-sync branch.
 // Steps:
 // 1. Stage all files in sync worktree (events.jsonl + messages/*.jsonl)
@@ -38,67 +76,88 @@ func NewSyncer(repoPath string, syncDir string, localOnly bool) *Syncer {
 // 3. Push to origin a-sync
 // 4. Handle push rejection (remote ahead)
 //
-// Push rejection handling:
-// - If push rejected, fetch + merge + retry
-// - Max 3 retries before failing.
-func (s *Syncer) CommitAndPush(ctx context.Context) error {
-	const maxRetries = 3
+// Push rejection handling: another daemon pushing to the same remote at
+// nearly the same moment makes ours non-fast-forward. A rejected push is
+// retried up to pushRetries times, each after an exponential backoff with
+// jitter (so two racing daemons drift apart) and a fetch + merge that
+// rebases the local commit onto the new remote tip. Other push errors are
+// returned immediately. The returned retries describe every rejection that
+// was retried, for the sync log; they are returned alongside an error too.
+func (s *Syncer) CommitAndPush(ctx context.Context) ([]PushRetry, error) {
+	var retries []PushRetry
 
-	for attempt := 1; attempt <= maxRetries; attempt++ {
+	for attempt := 1; ; attempt++ {
 		// Check if there are changes to commit
 		hasChanges, err := s.hasChanges(ctx)
 		if err != nil {
-			return fmt.Errorf("checking for changes: %w", err)
+			return retries, fmt.Errorf("checking for changes: %w", err)
 		}
 
 		if !hasChanges {
 			// No changes to push
-			return nil
+			return retries, nil
 		}
 
 		// Stage all JSONL files (events.jsonl + messages/*.jsonl)
 		if err := s.stageChanges(ctx); err != nil {
-			return fmt.Errorf("staging changes: %w", err)
+			return retries, fmt.Errorf("staging changes: %w", err)
 		}
 
 		// Commit with timestamp
 		timestamp := time.Now().UTC().Format(time.RFC3339)
 		commitMsg := fmt.Sprintf("sync: %s", timestamp)
 		if err := s.commitChanges(ctx, commitMsg); err != nil {
-			return fmt.Errorf("committing changes: %w", err)
+			return retries, fmt.Errorf("committing changes: %w", err)
 		}
 
 		// Push to origin a-sync
 		err = s.push(ctx)
 		if err == nil {
 			// Push succeeded
-			return nil
+			return retries, nil
 		}
 
 		// Check if it's a push rejection (remote ahead)
 		if !isPushRejected(err) {
 			// Some other error, not a rejection
-			return fmt.Errorf("pushing: %w", err)
+			return retries, fmt.Errorf("pushing: %w", err)
 		}
 
 		// Push rejected - remote is ahead
-		if attempt == maxRetries {
-			return fmt.Errorf("push rejected after %d retries: remote ahead", maxRetries)
+		if attempt > s.pushRetries {
+			return retries, fmt.Errorf("push rejected after %d retries: remote ahead: %w", s.pushRetries, err)
+		}
+
+		delay := s.retryDelay(attempt)
+		retries = append(retries, PushRetry{Attempt: attempt, DelayMS: delay.Milliseconds(), Error: err.Error()})
+		select {
+		case <-ctx.Done():
+			return retries, fmt.Errorf("waiting to retry push: %w", ctx.Err())
+		case <-time.After(delay):
 		}
 
 		// Fetch and merge, then retry
 		if err := s.merger.Fetch(ctx); err != nil {
-			return fmt.Errorf("fetch after rejection (attempt %d): %w", attempt, err)
+			return retries, fmt.Errorf("fetch after rejection (attempt %d): %w", attempt, err)
 		}
 
 		if _, err := s.merger.MergeAll(ctx); err != nil {
-			return fmt.Errorf("merge after rejection (attempt %d): %w", attempt, err)
+			return retries, fmt.Errorf("merge after rejection (attempt %d): %w", attempt, err)
 		}
 
 		// Loop will retry the commit and push
 	}
+}
 
-	return fmt.Errorf("push failed after %d retries", maxRetries)
+// retryDelay returns the backoff before retrying after the given rejected
+// attempt: pushRetryDelay doubled per prior retry, then jittered to a
+// random value between half and all of that.
+func (s *Syncer) retryDelay(attempt int) time.Duration {
+	d := s.pushRetryDelay << (attempt - 1)
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + rand.N(d/2+1)
 }
 
 // hasChanges checks if there are uncommitted changes in the sync worktree.
@@ -210,6 +269,10 @@ type PushError struct {
 }
 
 func (e *PushError) Error() string {
+	// safecmd errors already carry git's output; print it once.
+	if e.Output == "" || (e.Err != nil && strings.Contains(e.Err.Error(), e.Output)) {
+		return fmt.Sprintf("push failed: %v", e.Err)
+	}
 	return fmt.Sprintf("push failed: %v (output: %s)", e.Err, e.Output)
 }
 
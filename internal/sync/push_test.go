@@ -2,6 +2,7 @@ package sync
 
 import (
 	"context"
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -326,7 +327,7 @@ func TestSyncer_CommitAndPush_NoChanges(t *testing.T) {
 	_ = cmd.Run()
 
 	// CommitAndPush should succeed with no changes
-	if err := s.CommitAndPush(context.Background()); err != nil {
+	if _, err := s.CommitAndPush(context.Background()); err != nil {
 		t.Errorf("CommitAndPush failed with no changes: %v", err)
 	}
 }
@@ -355,7 +356,7 @@ func TestSyncer_CommitAndPush_WithChanges(t *testing.T) {
 	_ = f.Close()
 
 	// CommitAndPush should succeed
-	if err := s.CommitAndPush(context.Background()); err != nil {
+	if _, err := s.CommitAndPush(context.Background()); err != nil {
 		t.Errorf("CommitAndPush failed: %v", err)
 	}
 
@@ -409,6 +410,15 @@ func TestPushError_Error(t *testing.T) {
 	if !strings.Contains(errStr, "test output") {
 		t.Errorf("error string should contain output, got: %s", errStr)
 	}
+
+	// Output the wrapped error already carries is not repeated.
+	err = &PushError{
+		Err:    errors.New("git push: exit status 1 (output: ! [rejected] a-sync)"),
+		Output: "! [rejected] a-sync",
+	}
+	if n := strings.Count(err.Error(), "[rejected]"); n != 1 {
+		t.Errorf("output appears %d times, want once: %s", n, err.Error())
+	}
 }
 
 func TestPushError_Unwrap(t *testing.T) {
@@ -511,7 +521,7 @@ func TestSyncer_CommitAndPush_LocalOnly_CommitsButDoesNotPush(t *testing.T) {
 	s := NewSyncer(repoPath, syncDir, true)
 
 	// CommitAndPush should succeed — commits locally, skips push
-	if err := s.CommitAndPush(context.Background()); err != nil {
+	if _, err := s.CommitAndPush(context.Background()); err != nil {
 		t.Fatalf("CommitAndPush failed in local-only mode: %v", err)
 	}
 
@@ -578,6 +578,12 @@ func populateSQLite(outputDir string, agents []agentInfo, sessions []sessionInfo
 	}
 	defer func() { _ = msgStmt.Close() }()
 
+	ftsStmt, err := tx.Prepare("INSERT INTO messages_fts (message_id, body_content) VALUES (?, ?)")
+	if err != nil {
+		return fmt.Errorf("prepare message index insert: %w", err)
+	}
+	defer func() { _ = ftsStmt.Close() }()
+
 	scopeStmt, err := tx.Prepare("INSERT INTO message_scopes (message_id, scope_type, scope_value) VALUES (?, ?, ?)")
 	if err != nil {
 		return fmt.Errorf("prepare scope insert: %w", err)
@@ -589,6 +595,9 @@ func populateSQLite(outputDir string, agents []agentInfo, sessions []sessionInfo
 		if _, err := msgStmt.Exec(m.MessageID, m.AgentID, m.SessionID, ts, m.Format, m.Content); err != nil {
 			return fmt.Errorf("insert message %s: %w", m.MessageID, err)
 		}
+		if _, err := ftsStmt.Exec(m.MessageID, m.Content); err != nil {
+			return fmt.Errorf("index message %s: %w", m.MessageID, err)
+		}
 		for _, s := range m.Scopes {
 			if _, err := scopeStmt.Exec(m.MessageID, s.Type, s.Value); err != nil {
 				return fmt.Errorf("insert scope for %s: %w", m.MessageID, err)
@@ -14,6 +14,8 @@ const (
 	TransportWebSocket
 	// TransportTailscale represents a Tailscale tsnet connection (sync only).
 	TransportTailscale
+	// TransportHTTP represents a plain HTTP request through the RPC gateway.
+	TransportHTTP
 )
 
 // String returns the string representation of a transport type.
@@ -25,6 +27,8 @@ func (t Transport) String() string {
 		return "websocket"
 	case TransportTailscale:
 		return "tailscale"
+	case TransportHTTP:
+		return "http"
 	default:
 		return "unknown"
 	}
@@ -46,3 +50,19 @@ func GetTransport(ctx context.Context) Transport {
 	}
 	return TransportUnknown
 }
+
+// peerTokenKey is the context key for the authenticated peer token.
+type peerTokenKey struct{}
+
+// WithPeerToken returns a new context carrying the token a peer daemon
+// authenticated with, so sync handlers can tell which peer is calling.
+func WithPeerToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, peerTokenKey{}, token)
+}
+
+// PeerToken returns the peer token from the context, or "" if the caller
+// did not authenticate as a peer.
+func PeerToken(ctx context.Context) string {
+	token, _ := ctx.Value(peerTokenKey{}).(string)
+	return token
+}
@@ -16,6 +16,7 @@ type MessageCreateEvent struct {
 	EventID      string      `json:"event_id"`
 	Version      int         `json:"v"`
 	OriginDaemon string      `json:"origin_daemon,omitempty"`
+	Sequence     int64       `json:"sequence,omitempty"` // Assigned by the origin daemon's WriteEvent
 	MessageID    string      `json:"message_id"`
 	ThreadID     string      `json:"thread_id,omitempty"`
 	AgentID      string      `json:"agent_id"`
@@ -26,6 +27,10 @@ type MessageCreateEvent struct {
 	Recipients   []string    `json:"recipients,omitempty"`  // Snapshot of resolved recipient agent IDs
 	AuthoredBy   string      `json:"authored_by,omitempty"` // Actual author if impersonating
 	Disclosed    bool        `json:"disclosed,omitempty"`   // Show [via user:X] in UI
+	Tags         []string    `json:"tags,omitempty"`        // Free-form labels (normalized by message.send)
+	Priority     string      `json:"priority,omitempty"`    // "low" or "high"; empty means normal
+	ExpiresAt    string      `json:"expires_at,omitempty"`  // RFC 3339, second precision; set by send --ttl
+	DeliverAt    string      `json:"deliver_at,omitempty"`  // RFC 3339, second precision; set by send --schedule
 }
 
 // MessageBody represents the body of a message.
@@ -69,6 +74,91 @@ type MessageDeleteEvent struct {
 	Reason       string `json:"reason,omitempty"`
 }
 
+// MessageReactEvent represents a message.react event: an agent adding or
+// removing an emoji reaction on a message. The toggle is resolved by the
+// writer, so Removed is explicit and replay is idempotent.
+type MessageReactEvent struct {
+	Type         string `json:"type"`
+	Timestamp    string `json:"timestamp"`
+	EventID      string `json:"event_id"`
+	Version      int    `json:"v"`
+	OriginDaemon string `json:"origin_daemon,omitempty"`
+	MessageID    string `json:"message_id"`
+	AgentID      string `json:"agent_id"`
+	Emoji        string `json:"emoji"`
+	Removed      bool   `json:"removed,omitempty"`
+}
+
+// MessagePinEvent represents a message.pin event: a message being pinned or
+// unpinned for the whole repo. Like reactions, the writer resolves the
+// state change, so Unpinned is explicit and replay is idempotent.
+type MessagePinEvent struct {
+	Type         string `json:"type"`
+	Timestamp    string `json:"timestamp"`
+	EventID      string `json:"event_id"`
+	Version      int    `json:"v"`
+	OriginDaemon string `json:"origin_daemon,omitempty"`
+	MessageID    string `json:"message_id"`
+	AgentID      string `json:"agent_id"` // who pinned or unpinned
+	Unpinned     bool   `json:"unpinned,omitempty"`
+}
+
+// MessageMoveEvent represents a message.move event: a message reassigned to
+// another existing thread by its author, or a message outside any thread
+// placed in the new thread its first reply starts (AgentID is then the
+// replier). Only thread_id changes; refs such as reply_to are kept.
+type MessageMoveEvent struct {
+	Type         string `json:"type"`
+	Timestamp    string `json:"timestamp"`
+	EventID      string `json:"event_id"`
+	Version      int    `json:"v"`
+	OriginDaemon string `json:"origin_daemon,omitempty"`
+	MessageID    string `json:"message_id"`
+	ThreadID     string `json:"thread_id"` // destination thread
+	AgentID      string `json:"agent_id"`  // who moved it
+}
+
+// MessageBumpEvent represents a message.bump event: the author resurfacing a
+// message. Projection records the time in messages.bumped_at; the daemon that
+// took the bump re-notifies the message's subscribers.
+type MessageBumpEvent struct {
+	Type         string `json:"type"`
+	Timestamp    string `json:"timestamp"`
+	EventID      string `json:"event_id"`
+	Version      int    `json:"v"`
+	OriginDaemon string `json:"origin_daemon,omitempty"`
+	MessageID    string `json:"message_id"`
+	AgentID      string `json:"agent_id"` // who bumped it (always the author)
+}
+
+// MessageAssignEvent represents a message.assign event: a message turned
+// into a task for an agent. Any open assignment of the same message is
+// closed as reassigned, so history is kept rather than overwritten.
+type MessageAssignEvent struct {
+	Type         string `json:"type"`
+	Timestamp    string `json:"timestamp"`
+	EventID      string `json:"event_id"`
+	Version      int    `json:"v"`
+	OriginDaemon string `json:"origin_daemon,omitempty"`
+	MessageID    string `json:"message_id"`
+	AssignmentID string `json:"assignment_id"`
+	Assignee     string `json:"assignee"`
+	AgentID      string `json:"agent_id"` // who assigned it
+}
+
+// MessageCompleteEvent represents a message.complete event: the assignee
+// marking an open assignment done.
+type MessageCompleteEvent struct {
+	Type         string `json:"type"`
+	Timestamp    string `json:"timestamp"`
+	EventID      string `json:"event_id"`
+	Version      int    `json:"v"`
+	OriginDaemon string `json:"origin_daemon,omitempty"`
+	MessageID    string `json:"message_id"`
+	AssignmentID string `json:"assignment_id"`
+	AgentID      string `json:"agent_id"` // who completed it
+}
+
 // MessageReceiptEvent represents durable recipient receipt state for a message.
 type MessageReceiptEvent struct {
 	Type         string `json:"type"`
@@ -177,6 +267,61 @@ type AgentCleanupEvent struct {
 	Method       string `json:"method,omitempty"` // "manual", "automated", "ui"
 }
 
+// AgentAliasEvent represents an agent.alias event: a nickname assigned to or
+// removed from an agent. Collision checks happen in the writer, so replay is
+// a plain upsert or delete.
+type AgentAliasEvent struct {
+	Type         string `json:"type"` // "agent.alias"
+	Timestamp    string `json:"timestamp"`
+	EventID      string `json:"event_id"`
+	Version      int    `json:"v"`
+	OriginDaemon string `json:"origin_daemon,omitempty"`
+	AgentID      string `json:"agent_id"`
+	Alias        string `json:"alias"`
+	Removed      bool   `json:"removed,omitempty"`
+}
+
+// AgentCapabilitiesEvent represents an agent.capabilities event: the full
+// set of capability tags an agent advertises (register --capability, agent
+// set-capabilities). Replay replaces the agent's set; an empty list clears it.
+type AgentCapabilitiesEvent struct {
+	Type         string   `json:"type"` // "agent.capabilities"
+	Timestamp    string   `json:"timestamp"`
+	EventID      string   `json:"event_id"`
+	Version      int      `json:"v"`
+	OriginDaemon string   `json:"origin_daemon,omitempty"`
+	AgentID      string   `json:"agent_id"`
+	Capabilities []string `json:"capabilities"`
+}
+
+// AgentMuteEvent represents an agent.mute event: the agent stops getting
+// subscription pushes and wait wake-ups until Until passes (empty means
+// until unmuted). Removed marks an unmute.
+type AgentMuteEvent struct {
+	Type          string `json:"type"` // "agent.mute"
+	Timestamp     string `json:"timestamp"`
+	EventID       string `json:"event_id"`
+	Version       int    `json:"v"`
+	OriginDaemon  string `json:"origin_daemon,omitempty"`
+	AgentID       string `json:"agent_id"`
+	Until         string `json:"until,omitempty"`          // RFC3339 UTC
+	AllowMentions bool   `json:"allow_mentions,omitempty"` // direct @mentions still get through
+	Removed       bool   `json:"removed,omitempty"`
+}
+
+// AgentRenameEvent represents an agent.rename event. Replay moves every row
+// keyed by OldAgentID (sessions, messages, deliveries, reads, reactions,
+// aliases, and agent group memberships) to AgentID.
+type AgentRenameEvent struct {
+	Type         string `json:"type"` // "agent.rename"
+	Timestamp    string `json:"timestamp"`
+	EventID      string `json:"event_id"`
+	Version      int    `json:"v"`
+	OriginDaemon string `json:"origin_daemon,omitempty"`
+	AgentID      string `json:"agent_id"` // New name
+	OldAgentID   string `json:"old_agent_id"`
+}
+
 // PurgeExecutedEvent represents a purge.executed event.
 // When synced to peers, triggers the same purge on the remote node.
 type PurgeExecutedEvent struct {
@@ -222,62 +222,59 @@ func (c *Connection) processSingleRequest(ctx context.Context, req jsonRPCReques
 		}
 	}
 
-	// Get handler
-	handler, ok := c.server.getHandler(req.Method)
+	result, rpcErr := c.server.call(ctx, transport.TransportWebSocket, req.Method, req.Params)
+	if rpcErr != nil {
+		return jsonRPCResponse{JSONRPC: "2.0", ID: req.ID, Error: rpcErr}
+	}
+
+	// Success response
+	return jsonRPCResponse{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result:  result,
+	}
+}
+
+// call runs the handler for method and marshals its result. It is shared by
+// the WebSocket connection and the HTTP gateway so both apply the same
+// lookup, params defaulting, and error mapping.
+func (s *Server) call(ctx context.Context, t transport.Transport, method string, params json.RawMessage) (json.RawMessage, *jsonRPCError) {
+	handler, ok := s.getHandler(method)
 	if !ok {
-		return jsonRPCResponse{
-			JSONRPC: "2.0",
-			ID:      req.ID,
-			Error: &jsonRPCError{
-				Code:    -32601, // Method not found
-				Message: "Method not found",
-				Data:    fmt.Sprintf("method '%s' is not registered", req.Method),
-			},
+		return nil, &jsonRPCError{
+			Code:    -32601, // Method not found
+			Message: "Method not found",
+			Data:    fmt.Sprintf("method '%s' is not registered", method),
 		}
 	}
+	if s.callObserver != nil {
+		s.callObserver(method)
+	}
 
 	// Default nil params to empty JSON object so handlers can always unmarshal.
 	// This happens when the client omits the "params" field (e.g. JSON.stringify
 	// drops undefined values), which leaves req.Params as nil after parsing.
-	params := req.Params
-	if params == nil {
+	if len(params) == 0 {
 		params = json.RawMessage("{}")
 	}
 
-	// Call handler with WebSocket transport context
-	ctxWithTransport := transport.WithTransport(ctx, transport.TransportWebSocket)
-	result, err := handler(ctxWithTransport, params)
+	result, err := handler(transport.WithTransport(ctx, t), params)
 	if err != nil {
-		return jsonRPCResponse{
-			JSONRPC: "2.0",
-			ID:      req.ID,
-			Error: &jsonRPCError{
-				Code:    -32000, // Server error
-				Message: err.Error(),
-			},
+		return nil, &jsonRPCError{
+			Code:    -32000, // Server error
+			Message: err.Error(),
 		}
 	}
 
-	// Marshal result
 	resultJSON, err := json.Marshal(result)
 	if err != nil {
-		return jsonRPCResponse{
-			JSONRPC: "2.0",
-			ID:      req.ID,
-			Error: &jsonRPCError{
-				Code:    -32603, // Internal error
-				Message: "Internal error",
-				Data:    err.Error(),
-			},
+		return nil, &jsonRPCError{
+			Code:    -32603, // Internal error
+			Message: "Internal error",
+			Data:    err.Error(),
 		}
 	}
-
-	// Success response
-	return jsonRPCResponse{
-		JSONRPC: "2.0",
-		ID:      req.ID,
-		Result:  resultJSON,
-	}
+	return resultJSON, nil
 }
 
 // sendResponse sends a JSON-RPC response to the client.
@@ -0,0 +1,112 @@
+package websocket
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/leonletto/thrum/internal/transport"
+)
+
+// gatewayMaxBodyBytes caps a gateway request body. Generous enough for a
+// message.send at the default 1 MB body cap plus JSON overhead.
+const gatewayMaxBodyBytes = 4 << 20
+
+// gatewayBlockedPrefixes lists the method families the gateway hides unless
+// explicitly allowed: they drive peer sync and pairing, which no local HTTP
+// tool should be poking at.
+var gatewayBlockedPrefixes = []string{"sync.", "peer.", "pair."}
+
+// GatewayAllow returns the method filter for WithHTTPGateway. Every
+// registered method is exposed except sync.*, peer.* and pair.*; methods in
+// extra are exposed even if they fall in one of those families.
+func GatewayAllow(extra []string) func(method string) bool {
+	allowed := make(map[string]bool, len(extra))
+	for _, m := range extra {
+		allowed[m] = true
+	}
+	return func(method string) bool {
+		if allowed[method] {
+			return true
+		}
+		for _, p := range gatewayBlockedPrefixes {
+			if strings.HasPrefix(method, p) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// handleGateway serves POST /rpc/{method}. The request body is the params
+// object (empty means {}); the response body is the handler's result. Errors
+// come back as {"error": {"code", "message", "data"}} with the JSON-RPC code
+// so callers can reuse WebSocket error handling. Like /metrics, only
+// loopback clients are answered: the mux is also served on LAN/tailnet
+// listeners, and the gateway carries no token of its own. Because a browser
+// on the same machine is also a loopback client, requests must carry
+// Content-Type: application/json (which a cross-site form cannot send
+// without a CORS preflight) and any Origin must pass the same allowlist as
+// the /ws upgrade.
+func (s *Server) handleGateway(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		writeGatewayError(w, http.StatusMethodNotAllowed, &jsonRPCError{Code: -32600, Message: "Invalid request", Data: "use POST"})
+		return
+	}
+	if !isLoopbackAddr(r.RemoteAddr) {
+		writeGatewayError(w, http.StatusForbidden, &jsonRPCError{Code: -32600, Message: "Invalid request", Data: "gateway only accepts loopback clients"})
+		return
+	}
+	if !checkOrigin(s.allowedOrigins, r.Header.Get("Origin")) {
+		writeGatewayError(w, http.StatusForbidden, &jsonRPCError{Code: -32600, Message: "Invalid request", Data: "origin not allowed"})
+		return
+	}
+	if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != "application/json" {
+		writeGatewayError(w, http.StatusUnsupportedMediaType, &jsonRPCError{Code: -32600, Message: "Invalid request", Data: "Content-Type must be application/json"})
+		return
+	}
+
+	method := strings.TrimPrefix(r.URL.Path, "/rpc/")
+	if method == "" || strings.Contains(method, "/") {
+		writeGatewayError(w, http.StatusNotFound, &jsonRPCError{Code: -32601, Message: "Method not found", Data: "expected POST /rpc/{method}"})
+		return
+	}
+	if !s.gatewayAllow(method) {
+		writeGatewayError(w, http.StatusForbidden, &jsonRPCError{Code: -32601, Message: "Method not found", Data: "method '" + method + "' is not exposed over HTTP"})
+		return
+	}
+
+	var params json.RawMessage
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, gatewayMaxBodyBytes)).Decode(&params); err != nil && !errors.Is(err, io.EOF) {
+		writeGatewayError(w, http.StatusBadRequest, &jsonRPCError{Code: -32700, Message: "Parse error", Data: err.Error()})
+		return
+	}
+
+	result, rpcErr := s.call(r.Context(), transport.TransportHTTP, method, params)
+	if rpcErr != nil {
+		status := http.StatusBadRequest
+		switch rpcErr.Code {
+		case -32601:
+			status = http.StatusNotFound
+		case -32603:
+			status = http.StatusInternalServerError
+		}
+		writeGatewayError(w, status, rpcErr)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(result)
+}
+
+func writeGatewayError(w http.ResponseWriter, status int, rpcErr *jsonRPCError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(struct {
+		Error *jsonRPCError `json:"error"`
+	}{rpcErr})
+}
@@ -0,0 +1,174 @@
+package websocket_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/leonletto/thrum/internal/transport"
+	ws "github.com/leonletto/thrum/internal/websocket"
+)
+
+func newGatewayServer(t *testing.T, extra []string) *ws.Server {
+	t.Helper()
+	registry := ws.NewSimpleRegistry()
+	registry.Register("message.list", func(ctx context.Context, params json.RawMessage) (any, error) {
+		var req struct {
+			PageSize int `json:"page_size"`
+		}
+		if err := json.Unmarshal(params, &req); err != nil {
+			return nil, err
+		}
+		if req.PageSize < 0 {
+			return nil, errors.New("page_size must be non-negative")
+		}
+		return map[string]any{
+			"page_size": req.PageSize,
+			"transport": transport.GetTransport(ctx).String(),
+		}, nil
+	})
+	registry.Register("sync.force", func(ctx context.Context, params json.RawMessage) (any, error) {
+		return map[string]string{"status": "ok"}, nil
+	})
+	return ws.NewServer("localhost:0", registry, nil, ws.WithHTTPGateway(ws.GatewayAllow(extra)))
+}
+
+func gatewayRequest(t *testing.T, s *ws.Server, method, path, body, remote string) (*httptest.ResponseRecorder, map[string]any) {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.RemoteAddr = remote
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	s.HTTPHandler().ServeHTTP(rec, req)
+	var out map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+		t.Fatalf("decode response %q: %v", rec.Body.String(), err)
+	}
+	return rec, out
+}
+
+func TestGateway(t *testing.T) {
+	s := newGatewayServer(t, nil)
+	const loopback = "127.0.0.1:5555"
+
+	cases := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		remote   string
+		wantCode int
+	}{
+		{"call with params", http.MethodPost, "/rpc/message.list", `{"page_size":5}`, loopback, http.StatusOK},
+		{"empty body defaults params", http.MethodPost, "/rpc/message.list", "", loopback, http.StatusOK},
+		{"handler validation error", http.MethodPost, "/rpc/message.list", `{"page_size":-1}`, loopback, http.StatusBadRequest},
+		{"malformed body", http.MethodPost, "/rpc/message.list", `{`, loopback, http.StatusBadRequest},
+		{"unknown method", http.MethodPost, "/rpc/nope.nope", "", loopback, http.StatusNotFound},
+		{"sync blocked by default", http.MethodPost, "/rpc/sync.force", "", loopback, http.StatusForbidden},
+		{"GET rejected", http.MethodGet, "/rpc/message.list", "", loopback, http.StatusMethodNotAllowed},
+		{"non-loopback rejected", http.MethodPost, "/rpc/message.list", "", "192.168.1.20:5555", http.StatusForbidden},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec, out := gatewayRequest(t, s, tc.method, tc.path, tc.body, tc.remote)
+			if rec.Code != tc.wantCode {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tc.wantCode, rec.Body.String())
+			}
+			if tc.wantCode == http.StatusOK {
+				if out["transport"] != "http" {
+					t.Errorf("transport = %v, want http", out["transport"])
+				}
+			} else if out["error"] == nil {
+				t.Errorf("expected error envelope, got %v", out)
+			}
+		})
+	}
+}
+
+// TestGateway_CrossSiteRejected pins the CSRF guards: a page in a local
+// browser is a loopback client too, so foreign Origins and non-JSON bodies
+// (what a cross-site form or no-preflight fetch can send) are refused.
+func TestGateway_CrossSiteRejected(t *testing.T) {
+	s := newGatewayServer(t, nil)
+	localOrigin := "http://localhost:" + strconv.Itoa(s.Port())
+
+	cases := []struct {
+		name        string
+		origin      string
+		contentType string
+		wantCode    int
+	}{
+		{"no origin", "", "application/json", http.StatusOK},
+		{"local origin", localOrigin, "application/json", http.StatusOK},
+		{"json with charset", "", "application/json; charset=utf-8", http.StatusOK},
+		{"foreign origin", "https://evil.example", "application/json", http.StatusForbidden},
+		{"missing content type", "", "", http.StatusUnsupportedMediaType},
+		{"form content type", "", "application/x-www-form-urlencoded", http.StatusUnsupportedMediaType},
+		{"text content type", "", "text/plain", http.StatusUnsupportedMediaType},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/rpc/message.list", strings.NewReader(`{"page_size":5}`))
+			req.RemoteAddr = "127.0.0.1:5555"
+			if tc.origin != "" {
+				req.Header.Set("Origin", tc.origin)
+			}
+			if tc.contentType != "" {
+				req.Header.Set("Content-Type", tc.contentType)
+			}
+			rec := httptest.NewRecorder()
+			s.HTTPHandler().ServeHTTP(rec, req)
+			if rec.Code != tc.wantCode {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tc.wantCode, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestGateway_ExtraMethodsExposed(t *testing.T) {
+	s := newGatewayServer(t, []string{"sync.force"})
+	rec, _ := gatewayRequest(t, s, http.MethodPost, "/rpc/sync.force", "", "[::1]:5555")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (body %s)", rec.Code, rec.Body.String())
+	}
+}
+
+func TestGateway_CallObserver(t *testing.T) {
+	registry := ws.NewSimpleRegistry()
+	registry.Register("message.list", func(ctx context.Context, params json.RawMessage) (any, error) {
+		return map[string]string{}, nil
+	})
+	var observed []string
+	s := ws.NewServer("localhost:0", registry, nil,
+		ws.WithHTTPGateway(ws.GatewayAllow(nil)),
+		ws.WithCallObserver(func(method string) { observed = append(observed, method) }))
+
+	gatewayRequest(t, s, http.MethodPost, "/rpc/message.list", "", "127.0.0.1:5555")
+	gatewayRequest(t, s, http.MethodPost, "/rpc/nope.nope", "", "127.0.0.1:5555")
+	if len(observed) != 1 || observed[0] != "message.list" {
+		t.Errorf("observed = %v, want only the registered message.list call", observed)
+	}
+}
+
+func TestGateway_DisabledByDefault(t *testing.T) {
+	registry := ws.NewSimpleRegistry()
+	registry.Register("message.list", func(ctx context.Context, params json.RawMessage) (any, error) {
+		return map[string]string{}, nil
+	})
+	s := ws.NewServer("localhost:0", registry, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/rpc/message.list", nil)
+	req.RemoteAddr = "127.0.0.1:5555"
+	rec := httptest.NewRecorder()
+	s.HTTPHandler().ServeHTTP(rec, req)
+	// Without the option, /rpc/ falls through to the WebSocket handler,
+	// which rejects a non-upgrade request.
+	if rec.Code == http.StatusOK {
+		t.Fatal("gateway should not be mounted without WithHTTPGateway")
+	}
+}
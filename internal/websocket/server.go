@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/leonletto/thrum/internal/transport"
 )
 
 // DisconnectFunc is called when a WebSocket client disconnects.
@@ -44,17 +45,43 @@ func WithPairingValidator(fn func(string) bool) ServerOption {
 	return func(s *Server) { s.pairingValidator = fn }
 }
 
+// WithMetricsHandler mounts h at GET /metrics on the server's HTTP mux. Used
+// for the optional Prometheus endpoint (daemon.metrics_enabled); when not set
+// the route does not exist.
+func WithMetricsHandler(h http.Handler) ServerOption {
+	return func(s *Server) { s.metricsHandler = h }
+}
+
+// WithHTTPGateway mounts the plain HTTP+JSON gateway at POST /rpc/{method}
+// (daemon.http_gateway_enabled). allow decides which registered methods the
+// gateway exposes; see GatewayAllow. When not set the route does not exist.
+func WithHTTPGateway(allow func(method string) bool) ServerOption {
+	return func(s *Server) { s.gatewayAllow = allow }
+}
+
+// WithCallObserver calls fn with the method name of every request that
+// reaches a registered handler, over WebSocket or the HTTP gateway. Used to
+// count RPC calls for the metrics endpoint; fn must be cheap and safe for
+// concurrent use.
+func WithCallObserver(fn func(method string)) ServerOption {
+	return func(s *Server) { s.callObserver = fn }
+}
+
 // Server represents the WebSocket RPC server.
 type Server struct {
 	addr             string
 	httpServer       *http.Server
 	upgrader         websocket.Upgrader
+	allowedOrigins   []string
 	registry         HandlerRegistry
 	clients          *ClientRegistry
 	onDisconnect     DisconnectFunc
 	tokenValidator   func(string) bool
 	pairingValidator func(string) bool
 	peerAcceptFn     func(token string)
+	metricsHandler   http.Handler
+	gatewayAllow     func(method string) bool
+	callObserver     func(method string)
 	mu               sync.RWMutex
 	shutdown         bool
 	wg               sync.WaitGroup
@@ -104,12 +131,12 @@ func NewServer(addr string, registry HandlerRegistry, uiFS fs.FS, opts ...Server
 		startTime: time.Now(),
 	}
 
-	// Build the origin allowlist from the daemon port so the upgrader can
-	// validate browser-style Origin headers at handshake time.
-	allowedOrigins := allowedOriginsForPort(s.Port())
+	// Build the origin allowlist from the daemon port so the upgrader (and
+	// the HTTP gateway) can validate browser-style Origin headers.
+	s.allowedOrigins = allowedOriginsForPort(s.Port())
 	s.upgrader = websocket.Upgrader{
 		CheckOrigin: func(r *http.Request) bool {
-			return checkOrigin(allowedOrigins, r.Header.Get("Origin"))
+			return checkOrigin(s.allowedOrigins, r.Header.Get("Origin"))
 		},
 		ReadBufferSize:  1024,
 		WriteBufferSize: 1024,
@@ -123,6 +150,14 @@ func NewServer(addr string, registry HandlerRegistry, uiFS fs.FS, opts ...Server
 	// Set up HTTP server with route handlers
 	mux := http.NewServeMux()
 
+	if s.metricsHandler != nil {
+		mux.Handle("/metrics", s.metricsHandler)
+	}
+
+	if s.gatewayAllow != nil {
+		mux.HandleFunc("/rpc/", s.handleGateway)
+	}
+
 	if uiFS != nil {
 		// UI mode: WebSocket at /ws, static assets and SPA at /
 		mux.HandleFunc("/ws", s.handleWebSocket)
@@ -328,6 +363,7 @@ func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 	// Connections with ?pairing_code= are for the pair.request flow and do not
 	// have a token yet — that is the whole point of pairing.
 	pairingCode := r.URL.Query().Get("pairing_code")
+	connCtx := context.Background()
 	if pairingCode != "" {
 		if s.pairingValidator == nil || !s.pairingValidator(pairingCode) {
 			http.Error(w, "Invalid pairing code", http.StatusUnauthorized)
@@ -344,6 +380,8 @@ func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 				http.Error(w, "Unauthorized", http.StatusUnauthorized)
 				return
 			}
+			// Sync handlers read the token to tell which peer is calling.
+			connCtx = transport.WithPeerToken(connCtx, token)
 			// Non-loopback peer connected with a valid token — notify accept handler.
 			if s.peerAcceptFn != nil && !isLoopbackAddr(r.RemoteAddr) {
 				s.peerAcceptFn(token)
@@ -375,7 +413,7 @@ func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Handle the WebSocket connection
-	go s.handleConnection(context.Background(), conn)
+	go s.handleConnection(connCtx, conn)
 }
 
 // handleConnection manages a single WebSocket connection.
@@ -357,5 +357,167 @@ describe('ThrumWebSocket', () => {
       expect(states).toContain(ConnectionState.CONNECTED);
       expect(states.length).toBeGreaterThanOrEqual(2); // DISCONNECTED -> CONNECTING -> CONNECTED
     }, 500);
+
+    it('should not reconnect after an explicit disconnect', async () => {
+      const connectPromise = client.connect();
+      currentMockWs!.simulateOpen();
+      await connectPromise;
+
+      client.disconnect();
+      await new Promise((resolve) => setTimeout(resolve, 150));
+
+      expect(client.state).toBe(ConnectionState.DISCONNECTED);
+    }, 500);
+
+    it('should call the configured state callback', async () => {
+      const states: ConnectionState[] = [];
+      const configured = new ThrumWebSocket({
+        url: 'ws://localhost:9842',
+        onStateChange: (state) => states.push(state),
+      });
+
+      const connectPromise = configured.connect();
+      currentMockWs!.simulateOpen();
+      await connectPromise;
+      configured.disconnect();
+
+      expect(states).toEqual([
+        ConnectionState.CONNECTING,
+        ConnectionState.CONNECTED,
+        ConnectionState.DISCONNECTED,
+      ]);
+    }, 500);
+
+    it('should re-subscribe with the new ID after reconnecting', async () => {
+      const connectPromise = client.connect();
+      currentMockWs!.simulateOpen();
+      await connectPromise;
+
+      const params = { filter_type: 'scope', scope: { type: 'module', value: 'ui' } };
+      const subscribePromise = client.call('subscribe', params);
+      const first = JSON.parse((currentMockWs as any).lastSent);
+      currentMockWs!.simulateMessage(
+        JSON.stringify({ jsonrpc: '2.0', result: { subscription_id: 'sub_1' }, id: first.id })
+      );
+      await subscribePromise;
+
+      // Daemon restarts: the socket drops and a new one opens
+      currentMockWs!.close();
+      await new Promise((resolve) => setTimeout(resolve, 150));
+      currentMockWs!.simulateOpen();
+
+      const resent = JSON.parse((currentMockWs as any).lastSent);
+      expect(resent.method).toBe('subscribe');
+      expect(resent.params).toEqual(params);
+      currentMockWs!.simulateMessage(
+        JSON.stringify({ jsonrpc: '2.0', result: { subscription_id: 'sub_2' }, id: resent.id })
+      );
+      await new Promise((resolve) => setTimeout(resolve, 0));
+
+      // The caller still holds sub_1; the server only knows sub_2
+      const unsubscribePromise = client.call('unsubscribe', { subscription_id: 'sub_1' });
+      const unsub = JSON.parse((currentMockWs as any).lastSent);
+      expect(unsub.params).toEqual({ subscription_id: 'sub_2' });
+      currentMockWs!.simulateMessage(
+        JSON.stringify({ jsonrpc: '2.0', result: { subscription_id: 'sub_2' }, id: unsub.id })
+      );
+      await unsubscribePromise;
+    }, 1000);
+
+    it('should drop replayed message notifications', async () => {
+      const connectPromise = client.connect();
+      currentMockWs!.simulateOpen();
+      await connectPromise;
+
+      const handler = vi.fn();
+      client.on('notification.message', handler);
+
+      const notify = (timestamp: string) =>
+        currentMockWs!.simulateMessage(
+          JSON.stringify({
+            jsonrpc: '2.0',
+            method: 'notification.message',
+            params: { message_id: 'msg_1', timestamp },
+          })
+        );
+
+      notify('2026-01-01T00:00:00Z');
+      notify('2026-01-01T00:00:00Z'); // replayed after reconnect
+      notify('2026-01-01T00:05:00Z'); // edited
+
+      expect(handler).toHaveBeenCalledTimes(2);
+    });
+
+    it('should deliver one message to each matching subscription', async () => {
+      const connectPromise = client.connect();
+      currentMockWs!.simulateOpen();
+      await connectPromise;
+
+      const handler = vi.fn();
+      client.on('notification.message', handler);
+
+      const notify = (subscriptionId: number) =>
+        currentMockWs!.simulateMessage(
+          JSON.stringify({
+            jsonrpc: '2.0',
+            method: 'notification.message',
+            params: {
+              message_id: 'msg_1',
+              timestamp: '2026-01-01T00:00:00Z',
+              matched_subscription: { subscription_id: subscriptionId, match_type: 'scope' },
+            },
+          })
+        );
+
+      notify(1);
+      notify(2);
+      notify(1); // replayed
+
+      expect(handler).toHaveBeenCalledTimes(2);
+    });
+
+    it('should drop replays to a re-created subscription', async () => {
+      const connectPromise = client.connect();
+      currentMockWs!.simulateOpen();
+      await connectPromise;
+
+      const subscribePromise = client.call('subscribe', { filter_type: 'all' });
+      const first = JSON.parse((currentMockWs as any).lastSent);
+      currentMockWs!.simulateMessage(
+        JSON.stringify({ jsonrpc: '2.0', result: { subscription_id: 'sub_1' }, id: first.id })
+      );
+      await subscribePromise;
+
+      const handler = vi.fn();
+      client.on('notification.message', handler);
+
+      const notify = (subscriptionId: string) =>
+        currentMockWs!.simulateMessage(
+          JSON.stringify({
+            jsonrpc: '2.0',
+            method: 'notification.message',
+            params: {
+              message_id: 'msg_1',
+              timestamp: '2026-01-01T00:00:00Z',
+              matched_subscription: { subscription_id: subscriptionId, match_type: 'all' },
+            },
+          })
+        );
+
+      notify('sub_1');
+
+      currentMockWs!.close();
+      await new Promise((resolve) => setTimeout(resolve, 150));
+      currentMockWs!.simulateOpen();
+      const resent = JSON.parse((currentMockWs as any).lastSent);
+      currentMockWs!.simulateMessage(
+        JSON.stringify({ jsonrpc: '2.0', result: { subscription_id: 'sub_2' }, id: resent.id })
+      );
+      await new Promise((resolve) => setTimeout(resolve, 0));
+
+      notify('sub_2'); // replayed to the new subscription
+
+      expect(handler).toHaveBeenCalledTimes(1);
+    }, 1000);
   });
 });
@@ -21,13 +21,22 @@ type PendingRequest = {
 
 type EventHandler<T = unknown> = (data: T) => void;
 
+type TrackedSubscription = {
+  params: Record<string, unknown> | undefined;
+  currentId: string;
+};
+
+// How many recent notification keys to remember for de-duplication
+const SEEN_NOTIFICATION_LIMIT = 500;
+
 /**
  * WebSocket client for Thrum daemon communication
  *
  * Features:
  * - JSON-RPC 2.0 request/response handling
  * - Automatic reconnection with exponential backoff
- * - Event streaming and subscriptions
+ * - Event streaming and subscriptions, re-established after a reconnect
+ * - De-duplication of message notifications replayed after a reconnect
  * - Request timeout handling
  * - Connection state management
  */
@@ -40,6 +49,12 @@ export class ThrumWebSocket {
   private pendingRequests = new Map<number | string, PendingRequest>();
   private eventHandlers = new Map<string, Set<EventHandler>>();
   private stateChangeHandlers = new Set<(state: ConnectionState) => void>();
+  private intentionalClose = false;
+  private hasConnected = false;
+  // Keyed by the subscription ID the caller first received; the server
+  // issues a new ID every time we re-subscribe.
+  private subscriptions = new Map<string, TrackedSubscription>();
+  private seenNotifications = new Set<string>();
 
   private readonly maxReconnectAttempts: number;
   private readonly reconnectDelayMs: number;
@@ -53,6 +68,9 @@ export class ThrumWebSocket {
     this.reconnectDelayMs = config.reconnectDelayMs ?? 1000;
     this.maxReconnectDelayMs = config.maxReconnectDelayMs ?? 30000;
     this.requestTimeoutMs = config.requestTimeoutMs ?? 30000;
+    if (config.onStateChange) {
+      this.stateChangeHandlers.add(config.onStateChange);
+    }
   }
 
   /**
@@ -85,6 +103,8 @@ export class ThrumWebSocket {
       });
     }
 
+    this.intentionalClose = false;
+
     return new Promise((resolve, reject) => {
       this.setState(ConnectionState.CONNECTING);
 
@@ -98,8 +118,13 @@ export class ThrumWebSocket {
 
       const onOpen = () => {
         cleanup();
+        const isReconnect = this.hasConnected;
+        this.hasConnected = true;
         this.reconnectAttempts = 0;
         this.setState(ConnectionState.CONNECTED);
+        if (isReconnect) {
+          void this.resubscribe();
+        }
         resolve();
       };
 
@@ -126,6 +151,7 @@ export class ThrumWebSocket {
    * Disconnect from the WebSocket server
    */
   disconnect(): void {
+    this.intentionalClose = true;
     if (this.reconnectTimeoutId) {
       clearTimeout(this.reconnectTimeoutId);
       this.reconnectTimeoutId = null;
@@ -143,6 +169,12 @@ export class ThrumWebSocket {
       this.pendingRequests.delete(id);
     }
 
+    // Subscriptions are session-scoped on the daemon, so an explicit
+    // disconnect ends them; only dropped connections are re-subscribed.
+    this.subscriptions.clear();
+    this.seenNotifications.clear();
+    this.hasConnected = false;
+
     this.setState(ConnectionState.DISCONNECTED);
   }
 
@@ -150,8 +182,34 @@ export class ThrumWebSocket {
    * Make a JSON-RPC call
    */
   async call<T = unknown>(method: string, params?: Record<string, unknown>): Promise<T> {
+    if (method === 'subscribe') {
+      const result = await this.send<T>(method, params);
+      const subscriptionId = (result as { subscription_id?: unknown } | null)?.subscription_id;
+      if (typeof subscriptionId === 'string') {
+        this.subscriptions.set(subscriptionId, { params, currentId: subscriptionId });
+      }
+      return result;
+    }
+
+    const unsubscribeId = method === 'unsubscribe' ? params?.subscription_id : undefined;
+    if (typeof unsubscribeId === 'string') {
+      // Translate the caller's ID to the one the server knows after a reconnect
+      const tracked = this.subscriptions.get(unsubscribeId);
+      if (tracked) {
+        this.subscriptions.delete(unsubscribeId);
+        return this.send<T>(method, { ...params, subscription_id: tracked.currentId });
+      }
+    }
+
+    return this.send<T>(method, params);
+  }
+
+  /**
+   * Send a JSON-RPC request and wait for its response
+   */
+  private send<T>(method: string, params?: Record<string, unknown>): Promise<T> {
     if (!this.isConnected) {
-      throw new WebSocketConnectionError('Not connected');
+      return Promise.reject(new WebSocketConnectionError('Not connected'));
     }
 
     const id = this.nextRequestId++;
@@ -286,6 +344,10 @@ export class ThrumWebSocket {
    * Handle JSON-RPC notification (event)
    */
   private handleNotification(notification: JsonRpcNotification): void {
+    if (this.isDuplicate(notification)) {
+      return;
+    }
+
     const handlers = this.eventHandlers.get(notification.method);
     if (handlers) {
       for (const handler of handlers) {
@@ -302,14 +364,89 @@ export class ThrumWebSocket {
    * Handle WebSocket close
    */
   private handleClose(): void {
+    if (this.intentionalClose || this.reconnectTimeoutId) {
+      // Closed on purpose, or the error handler already scheduled a retry
+      return;
+    }
     this.setState(ConnectionState.DISCONNECTED);
     this.scheduleReconnect();
   }
 
+  /**
+   * Report whether a message notification was already delivered. The daemon
+   * may replay recent messages to a re-established subscription; edits are
+   * re-sent with a new timestamp and so are not treated as duplicates. The
+   * key includes the matched subscription, so one message delivered to two
+   * subscriptions reaches both.
+   */
+  private isDuplicate(notification: JsonRpcNotification): boolean {
+    const messageId = notification.params?.message_id;
+    if (typeof messageId !== 'string') {
+      return false;
+    }
+
+    const subscription = this.originalSubscriptionId(notification.params?.matched_subscription);
+    const key = `${notification.method}:${subscription}:${messageId}:${notification.params?.timestamp ?? ''}`;
+    if (this.seenNotifications.has(key)) {
+      return true;
+    }
+
+    this.seenNotifications.add(key);
+    if (this.seenNotifications.size > SEEN_NOTIFICATION_LIMIT) {
+      // Sets iterate in insertion order, so this drops the oldest key
+      const oldest = this.seenNotifications.values().next().value;
+      if (oldest !== undefined) {
+        this.seenNotifications.delete(oldest);
+      }
+    }
+    return false;
+  }
+
+  /**
+   * Map the subscription a notification matched back to the ID the caller
+   * first received, so a replay to a re-created subscription shares its key.
+   */
+  private originalSubscriptionId(matched: unknown): string {
+    const id = (matched as { subscription_id?: unknown } | undefined)?.subscription_id;
+    if (id === undefined || id === null) {
+      return '';
+    }
+    const current = String(id);
+    for (const [originalId, tracked] of this.subscriptions) {
+      if (tracked.currentId === current) {
+        return originalId;
+      }
+    }
+    return current;
+  }
+
+  /**
+   * Re-create every tracked subscription on a fresh connection. The server
+   * assigns new subscription IDs, which are mapped back to the IDs callers
+   * already hold so a later unsubscribe still works.
+   */
+  private async resubscribe(): Promise<void> {
+    for (const [originalId, tracked] of this.subscriptions) {
+      try {
+        const result = await this.send<{ subscription_id?: string }>('subscribe', tracked.params);
+        if (result?.subscription_id) {
+          tracked.currentId = result.subscription_id;
+        }
+      } catch (error) {
+        console.error(`Failed to restore subscription ${originalId}:`, error);
+      }
+    }
+  }
+
   /**
    * Schedule reconnection attempt
    */
   private scheduleReconnect(): void {
+    if (this.intentionalClose || this.reconnectTimeoutId) {
+      // An error followed by a close would otherwise schedule two attempts
+      return;
+    }
+
     if (this.reconnectAttempts >= this.maxReconnectAttempts) {
       this.setState(ConnectionState.FAILED);
       return;
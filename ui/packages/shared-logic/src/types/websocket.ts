@@ -80,6 +80,8 @@ export interface WebSocketConfig {
   reconnectDelayMs?: number;
   maxReconnectDelayMs?: number;
   requestTimeoutMs?: number;
+  /** Called on every connection state change, including reconnect attempts */
+  onStateChange?: (state: ConnectionState) => void;
 }
 
 /**